@@ -17,6 +17,10 @@ type ArrivalSpec struct {
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=253
 	Holder string `json:"holder"`
+
+	// Generation is the round of a cyclic barrier this arrival counts towards
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
 }
 
 // ArrivalStatus defines the observed state of Arrival
@@ -6,6 +6,7 @@ import (
 
 // BarrierSpec defines the desired state of Barrier
 // +kubebuilder:validation:XValidation:rule="!has(self.quorum) || self.quorum <= self.expected",message="quorum must not exceed expected"
+// +kubebuilder:validation:XValidation:rule="!has(self.openOnTimeoutIfQuorum) || !self.openOnTimeoutIfQuorum || (has(self.quorum) && has(self.timeout))",message="openOnTimeoutIfQuorum requires quorum and timeout"
 // +kubebuilder:validation:XValidation:rule="!has(self.timeout) || self.timeout.matches(r'^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$')",message="timeout must be a valid duration (e.g., 30s, 5m, 1h)"
 type BarrierSpec struct {
 	// Expected is the number of arrivals required to open the barrier
@@ -21,6 +22,24 @@ type BarrierSpec struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=1
 	Quorum *int32 `json:"quorum,omitempty"`
+
+	// OpenOnTimeoutIfQuorum waits for all expected arrivals until the timeout
+	// and then opens the barrier, instead of failing it, if at least quorum
+	// have arrived. Requires quorum and timeout.
+	// +optional
+	OpenOnTimeoutIfQuorum bool `json:"openOnTimeoutIfQuorum,omitempty"`
+
+	// Cyclic resets the barrier for the next round as soon as it opens,
+	// incrementing status.generation instead of staying Open
+	// +optional
+	Cyclic bool `json:"cyclic,omitempty"`
+
+	// RequireDistinctHolders counts each holder once however many Arrivals it
+	// has, so a single misbehaving client cannot open the barrier alone. Set
+	// it to false to count every Arrival instead. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	RequireDistinctHolders *bool `json:"requireDistinctHolders,omitempty"`
 }
 
 // BarrierStatus defines the observed state of Barrier
@@ -38,6 +57,20 @@ type BarrierStatus struct {
 	// +optional
 	OpenedAt *metav1.Time `json:"openedAt,omitempty"`
 
+	// ResetAt is when the barrier was last reset for another round. Arrivals
+	// created before it are ignored and the timeout is measured from it.
+	// +optional
+	ResetAt *metav1.Time `json:"resetAt,omitempty"`
+
+	// Generation counts the rounds a cyclic barrier has completed. Arrivals
+	// tagged with this generation count towards the current round.
+	// +optional
+	Generation int64 `json:"generation,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultBarrierExpected is the arrival count of a Barrier created without one
+const DefaultBarrierExpected int32 = 1
+
+// SetupWebhookWithManager registers the Barrier admission webhooks with mgr
+func (r *Barrier) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&barrierDefaulter{}).
+		WithValidator(&barrierValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-sync-konductor-io-v1-barrier,mutating=true,failurePolicy=fail,sideEffects=None,groups=sync.konductor.io,resources=barriers,verbs=create;update,versions=v1,name=mbarrier.konductor.io,admissionReviewVersions=v1
+
+// barrierDefaulter fills in the Barrier spec fields that were left unset
+type barrierDefaulter struct{}
+
+var _ admission.CustomDefaulter = &barrierDefaulter{}
+
+func (d *barrierDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	barrier, ok := obj.(*Barrier)
+	if !ok {
+		return fmt.Errorf("expected a Barrier but got %T", obj)
+	}
+	barrier.setDefaults()
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-sync-konductor-io-v1-barrier,mutating=false,failurePolicy=fail,sideEffects=None,groups=sync.konductor.io,resources=barriers,verbs=create;update,versions=v1,name=vbarrier.konductor.io,admissionReviewVersions=v1
+
+// barrierValidator rejects Barriers whose spec cannot be reconciled
+type barrierValidator struct{}
+
+var _ admission.CustomValidator = &barrierValidator{}
+
+func (v *barrierValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	barrier, ok := obj.(*Barrier)
+	if !ok {
+		return nil, fmt.Errorf("expected a Barrier but got %T", obj)
+	}
+	return nil, barrier.validate()
+}
+
+func (v *barrierValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *barrierValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *Barrier) validate() error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	if r.Spec.Expected < 1 {
+		errs = append(errs, field.Invalid(spec.Child("expected"), r.Spec.Expected, "must be at least 1"))
+	}
+	if r.Spec.Quorum != nil {
+		quorum := *r.Spec.Quorum
+		switch {
+		case quorum < 1:
+			errs = append(errs, field.Invalid(spec.Child("quorum"), quorum, "must be at least 1"))
+		case quorum > r.Spec.Expected:
+			errs = append(errs, field.Invalid(spec.Child("quorum"), quorum,
+				fmt.Sprintf("must not exceed expected (%d)", r.Spec.Expected)))
+		}
+	}
+	if r.Spec.OpenOnTimeoutIfQuorum && (r.Spec.Quorum == nil || r.Spec.Timeout == nil) {
+		errs = append(errs, field.Invalid(spec.Child("openOnTimeoutIfQuorum"), true, "requires quorum and timeout"))
+	}
+	errs = append(errs, validateDuration(spec.Child("timeout"), r.Spec.Timeout)...)
+
+	return invalidError("Barrier", r.Name, errs)
+}
+
+// setDefaults defaults an unset arrival count to DefaultBarrierExpected
+func (r *Barrier) setDefaults() {
+	if r.Spec.Expected == 0 {
+		r.Spec.Expected = DefaultBarrierExpected
+	}
+}
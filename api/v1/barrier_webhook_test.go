@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBarrierValidator(t *testing.T) {
+	quorum := func(q int32) *int32 { return &q }
+
+	tests := []struct {
+		name    string
+		spec    BarrierSpec
+		wantErr string
+	}{
+		{
+			name: "valid",
+			spec: BarrierSpec{Expected: 3, Quorum: quorum(2), Timeout: &metav1.Duration{Duration: time.Minute}},
+		},
+		{
+			name: "quorum equal to expected",
+			spec: BarrierSpec{Expected: 3, Quorum: quorum(3)},
+		},
+		{
+			name:    "zero expected",
+			spec:    BarrierSpec{Expected: 0},
+			wantErr: "spec.expected: Invalid value: 0: must be at least 1",
+		},
+		{
+			name:    "quorum greater than expected",
+			spec:    BarrierSpec{Expected: 2, Quorum: quorum(3)},
+			wantErr: "spec.quorum: Invalid value: 3: must not exceed expected (2)",
+		},
+		{
+			name:    "zero quorum",
+			spec:    BarrierSpec{Expected: 2, Quorum: quorum(0)},
+			wantErr: "spec.quorum: Invalid value: 0: must be at least 1",
+		},
+		{
+			name: "open on timeout with quorum and timeout",
+			spec: BarrierSpec{Expected: 3, Quorum: quorum(2), Timeout: &metav1.Duration{Duration: time.Minute}, OpenOnTimeoutIfQuorum: true},
+		},
+		{
+			name:    "open on timeout without quorum",
+			spec:    BarrierSpec{Expected: 3, Timeout: &metav1.Duration{Duration: time.Minute}, OpenOnTimeoutIfQuorum: true},
+			wantErr: "spec.openOnTimeoutIfQuorum: Invalid value: true: requires quorum and timeout",
+		},
+		{
+			name:    "open on timeout without timeout",
+			spec:    BarrierSpec{Expected: 3, Quorum: quorum(2), OpenOnTimeoutIfQuorum: true},
+			wantErr: "spec.openOnTimeoutIfQuorum: Invalid value: true: requires quorum and timeout",
+		},
+		{
+			name:    "negative timeout",
+			spec:    BarrierSpec{Expected: 1, Timeout: &metav1.Duration{Duration: -time.Minute}},
+			wantErr: `spec.timeout: Invalid value: "-1m0s": must not be negative`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barrier := &Barrier{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			validator := &barrierValidator{}
+
+			_, createErr := validator.ValidateCreate(context.Background(), barrier)
+			_, updateErr := validator.ValidateUpdate(context.Background(), &Barrier{}, barrier)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+			for _, err := range []error{createErr, updateErr} {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBarrierDefaulter(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     BarrierSpec
+		expected int32
+	}{
+		{name: "unset expected", spec: BarrierSpec{}, expected: DefaultBarrierExpected},
+		{name: "explicit expected", spec: BarrierSpec{Expected: 4}, expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barrier := &Barrier{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			require.NoError(t, (&barrierDefaulter{}).Default(context.Background(), barrier))
+			assert.Equal(t, tt.expected, barrier.Spec.Expected)
+
+			_, err := (&barrierValidator{}).ValidateCreate(context.Background(), barrier)
+			assert.NoError(t, err)
+		})
+	}
+}
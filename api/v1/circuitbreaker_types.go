@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defaults for CircuitBreaker fields left unset
+const (
+	DefaultCircuitBreakerFailureThreshold int32 = 5
+	DefaultCircuitBreakerSuccessThreshold int32 = 1
+	DefaultCircuitBreakerResetTimeout           = 30 * time.Second
+)
+
+// CircuitBreakerSpec defines the desired state of CircuitBreaker
+type CircuitBreakerSpec struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// breaker. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// SuccessThreshold is the number of successful trial requests that closes
+	// a half-open breaker again. It is also how many trial requests are let
+	// through while half-open. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+
+	// ResetTimeout is how long the breaker stays open before letting trial
+	// requests through. Defaults to 30s.
+	// +optional
+	ResetTimeout *metav1.Duration `json:"resetTimeout,omitempty"`
+
+	// TTL is the optional time-to-live for cleanup
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// CircuitBreakerStatus defines the observed state of CircuitBreaker
+type CircuitBreakerStatus struct {
+	// State is whether requests are let through: all of them while Closed,
+	// none while Open and a limited number of trials while HalfOpen
+	// +kubebuilder:validation:Enum=Closed;Open;HalfOpen
+	State CircuitBreakerState `json:"state"`
+
+	// Failures is the number of consecutive failures recorded since the last
+	// success
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Failures int32 `json:"failures,omitempty"`
+
+	// Successes is the number of successful trial requests while half-open
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Successes int32 `json:"successes,omitempty"`
+
+	// TrialRequests is the number of trial requests let through while
+	// half-open
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TrialRequests int32 `json:"trialRequests,omitempty"`
+
+	// LastFailureTime is when the last failure was recorded
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// StateChangedAt is when the breaker last changed state
+	// +optional
+	StateChangedAt *metav1.Time `json:"stateChangedAt,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CircuitBreakerState represents the state of a CircuitBreaker
+type CircuitBreakerState string
+
+const (
+	CircuitBreakerStateClosed   CircuitBreakerState = "Closed"
+	CircuitBreakerStateOpen     CircuitBreakerState = "Open"
+	CircuitBreakerStateHalfOpen CircuitBreakerState = "HalfOpen"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+//+kubebuilder:printcolumn:name="Failures",type=integer,JSONPath=`.status.failures`
+//+kubebuilder:printcolumn:name="Threshold",type=integer,JSONPath=`.spec.failureThreshold`
+//+kubebuilder:printcolumn:name="Changed",type=date,JSONPath=`.status.stateChangedAt`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// CircuitBreaker is the Schema for the circuitbreakers API
+type CircuitBreaker struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CircuitBreakerSpec   `json:"spec,omitempty"`
+	Status CircuitBreakerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CircuitBreakerList contains a list of CircuitBreaker
+type CircuitBreakerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CircuitBreaker `json:"items"`
+}
@@ -0,0 +1,70 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EventSpec defines the desired state of Event
+type EventSpec struct {
+	// TTL is the optional time-to-live for cleanup
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// EventStatus defines the observed state of Event
+type EventStatus struct {
+	// Signaled indicates if the event is currently set
+	Signaled bool `json:"signaled"`
+
+	// SignaledBy is who last set the event
+	// +optional
+	SignaledBy string `json:"signaledBy,omitempty"`
+
+	// SignaledAt is when the event was last set
+	// +optional
+	SignaledAt *metav1.Time `json:"signaledAt,omitempty"`
+
+	// Phase represents the current state
+	Phase EventPhase `json:"phase"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// EventPhase represents the phase of an Event
+type EventPhase string
+
+const (
+	EventPhaseCleared EventPhase = "Cleared"
+	EventPhaseSet     EventPhase = "Set"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=kevent
+//+kubebuilder:printcolumn:name="Signaled",type=boolean,JSONPath=`.status.signaled`
+//+kubebuilder:printcolumn:name="SignaledBy",type=string,JSONPath=`.status.signaledBy`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="SignaledAt",type=date,JSONPath=`.status.signaledAt`
+
+// Event is the Schema for the events API
+type Event struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EventSpec   `json:"spec,omitempty"`
+	Status EventStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EventList contains a list of Event
+type EventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Event `json:"items"`
+}
@@ -6,12 +6,13 @@ import (
 
 // GateCondition defines a condition that must be met
 type GateCondition struct {
-	// Type of condition (Job, Semaphore, Barrier, Lease)
+	// Type of condition (Job, Semaphore, Barrier, Lease, Mutex, RWMutex, ConfigMap, Secret, Pods)
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=Job;Semaphore;Barrier;Lease;Gate;Mutex;Once;WaitGroup
+	// +kubebuilder:validation:Enum=Job;Semaphore;Barrier;Lease;Gate;Mutex;RWMutex;Once;WaitGroup;ConfigMap;Secret;Pods
 	Type string `json:"type"`
 
-	// Name of the resource to check
+	// Name of the resource to check. Pods conditions match pods by Selector
+	// instead, and the name only identifies the condition in the status.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
@@ -26,15 +27,42 @@ type GateCondition struct {
 	// For Lease: Acquired or Available
 	// For Gate: Open or Closed
 	// For Mutex: Locked or Unlocked
+	// For RWMutex: Unlocked, ReadLocked or WriteLocked
 	// For Once: Done or Pending
 	// For WaitGroup: Zero or NonZero
 	// +optional
-	// +kubebuilder:validation:Enum=Complete;Failed;Active;Open;Closed;Acquired;Available;Locked;Unlocked;Done;Pending;Zero;NonZero
+	// +kubebuilder:validation:Enum=Complete;Failed;Active;Open;Closed;Acquired;Available;Locked;Unlocked;ReadLocked;WriteLocked;Done;Pending;Zero;NonZero
 	State string `json:"state,omitempty"`
 
-	// Value for numeric conditions (e.g., semaphore permits)
+	// Value for numeric conditions (e.g., semaphore permits, ready pods)
 	// +optional
 	Value *int32 `json:"value,omitempty"`
+
+	// Selector picks the pods a Pods condition counts. The condition is met
+	// once at least Value of them are Ready.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Key is the data key that must be present for ConfigMap and Secret conditions
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// StringValue, if set, is the exact value the ConfigMap or Secret key must hold
+	// +optional
+	StringValue string `json:"stringValue,omitempty"`
+
+	// Negate inverts the condition, so it is met while the resource is not in
+	// the required state. A resource that cannot be found never meets it.
+	// +optional
+	Negate bool `json:"negate,omitempty"`
+
+	// Timeout fails the gate if this condition is still unmet this long after
+	// the controller started waiting on it. With Or logic the gate fails once
+	// every condition has timed out.
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=duration
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // GateSpec defines the desired state of Gate
@@ -43,6 +71,13 @@ type GateSpec struct {
 	// +kubebuilder:validation:MinItems=1
 	Conditions []GateCondition `json:"conditions"`
 
+	// Logic is how the conditions combine: And opens the gate once all of them
+	// are met, Or once any one is
+	// +kubebuilder:validation:Enum=And;Or
+	// +kubebuilder:default=And
+	// +optional
+	Logic GateLogic `json:"logic,omitempty"`
+
 	// Timeout for waiting for conditions
 	// +optional
 	// +kubebuilder:validation:Type=string
@@ -50,6 +85,14 @@ type GateSpec struct {
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
+// GateLogic is how a Gate combines its conditions
+type GateLogic string
+
+const (
+	GateLogicAnd GateLogic = "And"
+	GateLogicOr  GateLogic = "Or"
+)
+
 // GateStatus defines the observed state of Gate
 type GateStatus struct {
 	// Phase represents the current state of the gate
@@ -62,6 +105,20 @@ type GateStatus struct {
 	// +optional
 	OpenedAt *metav1.Time `json:"openedAt,omitempty"`
 
+	// LastConditionChangeAt is when a condition of a waiting gate last
+	// changed. The next check interval grows with the time since then.
+	// +optional
+	LastConditionChangeAt *metav1.Time `json:"lastConditionChangeAt,omitempty"`
+
+	// NextCheckInterval is how long the controller waits before re-evaluating
+	// a waiting gate. It doubles while no condition changes and resets when one does.
+	// +optional
+	NextCheckInterval *metav1.Duration `json:"nextCheckInterval,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -79,6 +136,11 @@ type GateConditionStatus struct {
 
 	// Message provides details about the condition status
 	Message string `json:"message,omitempty"`
+
+	// StartedAt is when the controller first evaluated the condition, which
+	// its timeout counts from
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
 }
 
 // GatePhase represents the phase of a Gate
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the Gate admission webhooks with mgr
+func (r *Gate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&gateValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-sync-konductor-io-v1-gate,mutating=false,failurePolicy=fail,sideEffects=None,groups=sync.konductor.io,resources=gates,verbs=create;update,versions=v1,name=vgate.konductor.io,admissionReviewVersions=v1
+
+// gateValidator rejects Gates whose conditions can never be met
+type gateValidator struct{}
+
+var _ admission.CustomValidator = &gateValidator{}
+
+func (v *gateValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	gate, ok := obj.(*Gate)
+	if !ok {
+		return nil, fmt.Errorf("expected a Gate but got %T", obj)
+	}
+	return nil, gate.validate()
+}
+
+func (v *gateValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *gateValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *Gate) validate() error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	if len(r.Spec.Conditions) == 0 {
+		errs = append(errs, field.Required(spec.Child("conditions"), "must have at least one condition"))
+	}
+	for i, condition := range r.Spec.Conditions {
+		path := spec.Child("conditions").Index(i)
+
+		if condition.Name == "" {
+			errs = append(errs, field.Required(path.Child("name"), "must name the resource to check"))
+		}
+
+		errs = append(errs, validateDuration(path.Child("timeout"), condition.Timeout)...)
+
+		switch condition.Type {
+		case "Semaphore":
+			if condition.Value == nil {
+				errs = append(errs, field.Required(path.Child("value"), "Semaphore conditions require the number of available permits"))
+			}
+		case "ConfigMap", "Secret":
+			if condition.Key == "" {
+				errs = append(errs, field.Required(path.Child("key"), fmt.Sprintf("%s conditions require a key", condition.Type)))
+			}
+		case "Pods":
+			if condition.Selector == nil {
+				errs = append(errs, field.Required(path.Child("selector"), "Pods conditions require a selector"))
+			} else if _, err := metav1.LabelSelectorAsSelector(condition.Selector); err != nil {
+				errs = append(errs, field.Invalid(path.Child("selector"), metav1.FormatLabelSelector(condition.Selector), err.Error()))
+			}
+			if condition.Value == nil {
+				errs = append(errs, field.Required(path.Child("value"), "Pods conditions require the number of ready pods"))
+			}
+		}
+	}
+	errs = append(errs, validateDuration(spec.Child("timeout"), r.Spec.Timeout)...)
+
+	return invalidError("Gate", r.Name, errs)
+}
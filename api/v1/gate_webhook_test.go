@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGateValidator(t *testing.T) {
+	permits := int32(2)
+
+	tests := []struct {
+		name    string
+		spec    GateSpec
+		wantErr string
+	}{
+		{
+			name: "valid",
+			spec: GateSpec{
+				Conditions: []GateCondition{
+					{Type: "Job", Name: "setup", State: "Complete", Timeout: &metav1.Duration{Duration: 30 * time.Minute}},
+					{Type: "Semaphore", Name: "api-quota", Value: &permits},
+					{Type: "ConfigMap", Name: "settings", Key: "ready"},
+					{Type: "Pods", Name: "api", Value: &permits, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+				},
+				Timeout: &metav1.Duration{Duration: time.Hour},
+			},
+		},
+		{
+			name:    "no conditions",
+			spec:    GateSpec{},
+			wantErr: "spec.conditions: Required value: must have at least one condition",
+		},
+		{
+			name:    "condition without a name",
+			spec:    GateSpec{Conditions: []GateCondition{{Type: "Job", State: "Complete"}}},
+			wantErr: "spec.conditions[0].name: Required value: must name the resource to check",
+		},
+		{
+			name:    "semaphore condition without a value",
+			spec:    GateSpec{Conditions: []GateCondition{{Type: "Semaphore", Name: "api-quota"}}},
+			wantErr: "spec.conditions[0].value: Required value: Semaphore conditions require the number of available permits",
+		},
+		{
+			name: "secret condition without a key",
+			spec: GateSpec{Conditions: []GateCondition{
+				{Type: "Job", Name: "setup", State: "Complete"},
+				{Type: "Secret", Name: "credentials"},
+			}},
+			wantErr: "spec.conditions[1].key: Required value: Secret conditions require a key",
+		},
+		{
+			name:    "pods condition without a selector",
+			spec:    GateSpec{Conditions: []GateCondition{{Type: "Pods", Name: "api", Value: &permits}}},
+			wantErr: "spec.conditions[0].selector: Required value: Pods conditions require a selector",
+		},
+		{
+			name: "pods condition without a value",
+			spec: GateSpec{Conditions: []GateCondition{
+				{Type: "Pods", Name: "api", Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+			}},
+			wantErr: "spec.conditions[0].value: Required value: Pods conditions require the number of ready pods",
+		},
+		{
+			name: "pods condition with an invalid selector",
+			spec: GateSpec{Conditions: []GateCondition{{Type: "Pods", Name: "api", Value: &permits, Selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}},
+			}}}},
+			wantErr: "spec.conditions[0].selector: Invalid value",
+		},
+		{
+			name: "negative timeout",
+			spec: GateSpec{
+				Conditions: []GateCondition{{Type: "Job", Name: "setup", State: "Complete"}},
+				Timeout:    &metav1.Duration{Duration: -time.Hour},
+			},
+			wantErr: `spec.timeout: Invalid value: "-1h0m0s": must not be negative`,
+		},
+		{
+			name: "negative condition timeout",
+			spec: GateSpec{Conditions: []GateCondition{
+				{Type: "Job", Name: "setup", State: "Complete", Timeout: &metav1.Duration{Duration: -time.Minute}},
+			}},
+			wantErr: `spec.conditions[0].timeout: Invalid value: "-1m0s": must not be negative`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := &Gate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			validator := &gateValidator{}
+
+			_, createErr := validator.ValidateCreate(context.Background(), gate)
+			_, updateErr := validator.ValidateUpdate(context.Background(), &Gate{}, gate)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+			for _, err := range []error{createErr, updateErr} {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -31,4 +31,7 @@ func init() {
 	SchemeBuilder.Register(&RWMutex{}, &RWMutexList{})
 	SchemeBuilder.Register(&Once{}, &OnceList{})
 	SchemeBuilder.Register(&WaitGroup{}, &WaitGroupList{})
+	SchemeBuilder.Register(&Event{}, &EventList{})
+	SchemeBuilder.Register(&RateLimiter{}, &RateLimiterList{})
+	SchemeBuilder.Register(&CircuitBreaker{}, &CircuitBreakerList{})
 }
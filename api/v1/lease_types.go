@@ -6,6 +6,7 @@ import (
 
 // LeaseSpec defines the desired state of Lease
 // +kubebuilder:validation:XValidation:rule="!has(self.ttl) || self.ttl.matches('^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$')",message="ttl must be a valid duration (e.g., 30s, 5m, 1h)"
+// +kubebuilder:validation:XValidation:rule="!has(self.maxHoldTime) || self.maxHoldTime.matches('^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$')",message="maxHoldTime must be a valid duration (e.g., 30s, 5m, 1h)"
 type LeaseSpec struct {
 	// TTL is the time-to-live for the lease
 	// +optional
@@ -14,6 +15,12 @@ type LeaseSpec struct {
 	// Priority for lease acquisition (higher wins)
 	// +optional
 	Priority *int32 `json:"priority,omitempty"`
+
+	// MaxHoldTime caps how long a holder may keep the lease from when it was
+	// acquired, however often it is renewed. Once exceeded the lease is revoked
+	// and granted to the next request.
+	// +optional
+	MaxHoldTime *metav1.Duration `json:"maxHoldTime,omitempty"`
 }
 
 // LeaseStatus defines the observed state of Lease
@@ -37,6 +44,15 @@ type LeaseStatus struct {
 	// +optional
 	RenewCount int32 `json:"renewCount"`
 
+	// FenceToken increases every time the lease is granted to a new holder
+	// so downstream systems can reject writes from stale holders
+	// +optional
+	FenceToken int64 `json:"fenceToken,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
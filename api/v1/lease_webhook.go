@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultLeaseTTL is the TTL of a Lease created without one
+const DefaultLeaseTTL = 10 * time.Minute
+
+// SetupWebhookWithManager registers the Lease admission webhooks with mgr
+func (r *Lease) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&leaseDefaulter{}).
+		WithValidator(&leaseValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-sync-konductor-io-v1-lease,mutating=true,failurePolicy=fail,sideEffects=None,groups=sync.konductor.io,resources=leases,verbs=create;update,versions=v1,name=mlease.konductor.io,admissionReviewVersions=v1
+
+// leaseDefaulter fills in the Lease spec fields that were left unset
+type leaseDefaulter struct{}
+
+var _ admission.CustomDefaulter = &leaseDefaulter{}
+
+func (d *leaseDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	lease, ok := obj.(*Lease)
+	if !ok {
+		return fmt.Errorf("expected a Lease but got %T", obj)
+	}
+	lease.setDefaults()
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-sync-konductor-io-v1-lease,mutating=false,failurePolicy=fail,sideEffects=None,groups=sync.konductor.io,resources=leases,verbs=create;update,versions=v1,name=vlease.konductor.io,admissionReviewVersions=v1
+
+// leaseValidator rejects Leases whose spec cannot be reconciled
+type leaseValidator struct{}
+
+var _ admission.CustomValidator = &leaseValidator{}
+
+func (v *leaseValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	lease, ok := obj.(*Lease)
+	if !ok {
+		return nil, fmt.Errorf("expected a Lease but got %T", obj)
+	}
+	return nil, lease.validate()
+}
+
+func (v *leaseValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *leaseValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *Lease) validate() error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	errs = append(errs, validateDuration(spec.Child("ttl"), r.Spec.TTL)...)
+	errs = append(errs, validateDuration(spec.Child("maxHoldTime"), r.Spec.MaxHoldTime)...)
+
+	return invalidError("Lease", r.Name, errs)
+}
+
+// setDefaults defaults an unset TTL to DefaultLeaseTTL
+func (r *Lease) setDefaults() {
+	if r.Spec.TTL == nil {
+		r.Spec.TTL = &metav1.Duration{Duration: DefaultLeaseTTL}
+	}
+}
@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLeaseValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    LeaseSpec
+		wantErr string
+	}{
+		{
+			name: "valid",
+			spec: LeaseSpec{TTL: &metav1.Duration{Duration: 10 * time.Minute}},
+		},
+		{
+			name: "no ttl",
+			spec: LeaseSpec{},
+		},
+		{
+			name:    "negative ttl",
+			spec:    LeaseSpec{TTL: &metav1.Duration{Duration: -30 * time.Second}},
+			wantErr: `spec.ttl: Invalid value: "-30s": must not be negative`,
+		},
+		{
+			name:    "negative max hold time",
+			spec:    LeaseSpec{MaxHoldTime: &metav1.Duration{Duration: -time.Hour}},
+			wantErr: `spec.maxHoldTime: Invalid value: "-1h0m0s": must not be negative`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lease := &Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-lease", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			validator := &leaseValidator{}
+
+			_, createErr := validator.ValidateCreate(context.Background(), lease)
+			_, updateErr := validator.ValidateUpdate(context.Background(), &Lease{}, lease)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+			for _, err := range []error{createErr, updateErr} {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLeaseDefaulter(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     LeaseSpec
+		expected time.Duration
+	}{
+		{name: "unset ttl", spec: LeaseSpec{}, expected: DefaultLeaseTTL},
+		{name: "explicit ttl", spec: LeaseSpec{TTL: &metav1.Duration{Duration: time.Minute}}, expected: time.Minute},
+		{name: "explicit zero ttl", spec: LeaseSpec{TTL: &metav1.Duration{}}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lease := &Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-lease", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			require.NoError(t, (&leaseDefaulter{}).Default(context.Background(), lease))
+			require.NotNil(t, lease.Spec.TTL)
+			assert.Equal(t, tt.expected, lease.Spec.TTL.Duration)
+		})
+	}
+}
+
+func TestLeaseDefaulter_WrongType(t *testing.T) {
+	err := (&leaseDefaulter{}).Default(context.Background(), &Semaphore{})
+	assert.ErrorContains(t, err, "expected a Lease")
+}
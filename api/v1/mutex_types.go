@@ -9,6 +9,11 @@ type MutexSpec struct {
 	// TTL is the optional time-to-live for automatic unlock
 	// +optional
 	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Reentrant lets the current holder lock the mutex again without
+	// waiting. Each Lock must then be matched by an Unlock.
+	// +optional
+	Reentrant bool `json:"reentrant,omitempty"`
 }
 
 // MutexStatus defines the observed state of Mutex
@@ -25,10 +30,25 @@ type MutexStatus struct {
 	// +optional
 	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 
+	// FenceToken increases every time the mutex is granted to a new holder
+	// so downstream systems can reject writes from stale holders
+	// +optional
+	FenceToken int64 `json:"fenceToken,omitempty"`
+
+	// HoldCount is how many times the current holder has locked a reentrant
+	// mutex without unlocking it
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	HoldCount int32 `json:"holdCount,omitempty"`
+
 	// Phase represents the current state of the mutex
 	// +kubebuilder:validation:Enum=Unlocked;Locked
 	Phase MutexPhase `json:"phase"`
 
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -9,6 +9,12 @@ type OnceSpec struct {
 	// TTL is the optional time-to-live for cleanup
 	// +optional
 	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// ResetInterval makes the once executable again this long after it was
+	// last executed, for tasks that run once per period. It is never reset
+	// while the execution is still running.
+	// +optional
+	ResetInterval *metav1.Duration `json:"resetInterval,omitempty"`
 }
 
 // OnceStatus defines the observed state of Once
@@ -24,9 +30,23 @@ type OnceStatus struct {
 	// +optional
 	ExecutedAt *metav1.Time `json:"executedAt,omitempty"`
 
+	// CompletedAt is when the executor finished the action successfully
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Result is an optional payload stored by the executor for callers
+	// that did not execute the action
+	// +optional
+	// +kubebuilder:validation:MaxLength=32768
+	Result string `json:"result,omitempty"`
+
 	// Phase represents the current state
 	Phase OncePhase `json:"phase"`
 
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -20,6 +20,17 @@ type PermitSpec struct {
 	// TTL is the time-to-live for this permit
 	// +optional
 	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Priority orders pending permits when the semaphore queues requests.
+	// Higher values are granted first.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// Weight is how many of the semaphore's permits this permit counts for
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
 }
 
 // PermitStatus defines the observed state of Permit
@@ -43,6 +54,7 @@ type PermitStatus struct {
 type PermitPhase string
 
 const (
+	PermitPhasePending PermitPhase = "Pending"
 	PermitPhaseGranted PermitPhase = "Granted"
 	PermitPhaseDenied  PermitPhase = "Denied"
 	PermitPhaseExpired PermitPhase = "Expired"
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RateLimiterSpec defines the desired state of RateLimiter
+type RateLimiterSpec struct {
+	// Rate is the number of tokens added to the bucket every period
+	// +kubebuilder:validation:Minimum=1
+	Rate int32 `json:"rate"`
+
+	// Period is the interval over which Rate tokens are added, defaulting to
+	// one second
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// Burst is the most tokens the bucket holds, and so the most that can be
+	// taken at once. Defaults to Rate.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+
+	// TTL is the optional time-to-live for cleanup
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// RateLimiterStatus defines the observed state of RateLimiter
+type RateLimiterStatus struct {
+	// AvailableTokens is the number of tokens that can be taken right now
+	// +kubebuilder:validation:Minimum=0
+	AvailableTokens int32 `json:"availableTokens"`
+
+	// LastRefillTime is when tokens were last added to the bucket. It keeps
+	// microsecond precision so refills shorter than a second are not lost.
+	// +optional
+	LastRefillTime *metav1.MicroTime `json:"lastRefillTime,omitempty"`
+
+	// Phase represents the current state of the rate limiter
+	Phase RateLimiterPhase `json:"phase"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RateLimiterPhase represents the phase of a RateLimiter
+type RateLimiterPhase string
+
+const (
+	RateLimiterPhaseAvailable RateLimiterPhase = "Available"
+	RateLimiterPhaseExhausted RateLimiterPhase = "Exhausted"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Rate",type=integer,JSONPath=`.spec.rate`
+//+kubebuilder:printcolumn:name="Period",type=string,JSONPath=`.spec.period`
+//+kubebuilder:printcolumn:name="Burst",type=integer,JSONPath=`.spec.burst`
+//+kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableTokens`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RateLimiter is the Schema for the ratelimiters API
+type RateLimiter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RateLimiterSpec   `json:"spec,omitempty"`
+	Status RateLimiterStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RateLimiterList contains a list of RateLimiter
+type RateLimiterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RateLimiter `json:"items"`
+}
@@ -1,9 +1,16 @@
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// RWMutexWritePendingTimeout is how long a pending writer registration lasts
+// without being renewed. Waiting writers renew it well within this, so the
+// controller only clears the registration of a writer that has gone away.
+const RWMutexWritePendingTimeout = 30 * time.Second
+
 // RWMutexSpec defines the desired state of RWMutex
 type RWMutexSpec struct {
 	// TTL is the optional time-to-live for automatic unlock
@@ -23,6 +30,16 @@ type RWMutexStatus struct {
 	// +optional
 	ReadHolders []string `json:"readHolders,omitempty"`
 
+	// WritePending is the writer waiting for read locks to drain; new read locks
+	// are not granted while it is set
+	// +optional
+	WritePending string `json:"writePending,omitempty"`
+
+	// WritePendingSince is when the pending writer last renewed its
+	// registration
+	// +optional
+	WritePendingSince *metav1.Time `json:"writePendingSince,omitempty"`
+
 	// LockedAt is when the lock was acquired
 	// +optional
 	LockedAt *metav1.Time `json:"lockedAt,omitempty"`
@@ -34,6 +51,10 @@ type RWMutexStatus struct {
 	// Phase represents the current state of the rwmutex
 	Phase RWMutexPhase `json:"phase"`
 
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
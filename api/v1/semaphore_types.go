@@ -13,12 +13,25 @@ type SemaphoreSpec struct {
 	// TTL is the default time-to-live for permits
 	// +optional
 	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Fair grants permits in the order they were requested, or by priority
+	// when permits set one. Requests beyond the permit count stay Pending
+	// until earlier holders release.
+	// +optional
+	Fair bool `json:"fair,omitempty"`
+
+	// Drain stops new permits being granted, for maintenance, while current
+	// holders keep theirs until they release. Requests made while draining
+	// stay Pending until drain is unset.
+	// +optional
+	Drain bool `json:"drain,omitempty"`
 }
 
 // SemaphoreStatus defines the observed state of Semaphore
 // +kubebuilder:validation:XValidation:rule="self.inUse >= 0 && self.available >= 0",message="inUse and available must be non-negative"
 type SemaphoreStatus struct {
-	// InUse is the current number of permits in use
+	// InUse is the current number of permits in use, the total weight of the
+	// granted permits
 	// +kubebuilder:validation:Minimum=0
 	InUse int32 `json:"inUse"`
 
@@ -26,9 +39,33 @@ type SemaphoreStatus struct {
 	// +kubebuilder:validation:Minimum=0
 	Available int32 `json:"available"`
 
+	// Pending is the number of permits queued waiting for a grant
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Pending int32 `json:"pending,omitempty"`
+
+	// LongestWait is how long the oldest pending permit has been queued, as
+	// of the last status update
+	// +optional
+	LongestWait *metav1.Duration `json:"longestWait,omitempty"`
+
+	// MaxInUse is the most permits ever in use at once, a high-water mark for
+	// sizing the semaphore
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxInUse int32 `json:"maxInUse,omitempty"`
+
+	// LastFullAt is when every permit was last found in use
+	// +optional
+	LastFullAt *metav1.Time `json:"lastFullAt,omitempty"`
+
 	// Phase represents the current state of the semaphore
 	Phase SemaphorePhase `json:"phase"`
 
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
@@ -40,6 +77,7 @@ const (
 	SemaphorePhaseReady       SemaphorePhase = "Ready"
 	SemaphorePhaseFull        SemaphorePhase = "Full"
 	SemaphorePhaseUnavailable SemaphorePhase = "Unavailable"
+	SemaphorePhaseDraining    SemaphorePhase = "Draining"
 )
 
 //+kubebuilder:object:root=true
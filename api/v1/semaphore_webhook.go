@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DefaultSemaphorePermits is the permit count of a Semaphore created without one
+const DefaultSemaphorePermits int32 = 1
+
+// SetupWebhookWithManager registers the Semaphore admission webhooks with mgr
+func (r *Semaphore) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&semaphoreDefaulter{}).
+		WithValidator(&semaphoreValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-sync-konductor-io-v1-semaphore,mutating=true,failurePolicy=fail,sideEffects=None,groups=sync.konductor.io,resources=semaphores,verbs=create;update,versions=v1,name=msemaphore.konductor.io,admissionReviewVersions=v1
+
+// semaphoreDefaulter fills in the Semaphore spec fields that were left unset
+type semaphoreDefaulter struct{}
+
+var _ admission.CustomDefaulter = &semaphoreDefaulter{}
+
+func (d *semaphoreDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	semaphore, ok := obj.(*Semaphore)
+	if !ok {
+		return fmt.Errorf("expected a Semaphore but got %T", obj)
+	}
+	semaphore.setDefaults()
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-sync-konductor-io-v1-semaphore,mutating=false,failurePolicy=fail,sideEffects=None,groups=sync.konductor.io,resources=semaphores,verbs=create;update,versions=v1,name=vsemaphore.konductor.io,admissionReviewVersions=v1
+
+// semaphoreValidator rejects Semaphores whose spec cannot be reconciled
+type semaphoreValidator struct{}
+
+var _ admission.CustomValidator = &semaphoreValidator{}
+
+func (v *semaphoreValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	semaphore, ok := obj.(*Semaphore)
+	if !ok {
+		return nil, fmt.Errorf("expected a Semaphore but got %T", obj)
+	}
+	return nil, semaphore.validate()
+}
+
+func (v *semaphoreValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.ValidateCreate(ctx, newObj)
+}
+
+func (v *semaphoreValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *Semaphore) validate() error {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	if r.Spec.Permits < 1 {
+		errs = append(errs, field.Invalid(spec.Child("permits"), r.Spec.Permits, "must be at least 1"))
+	}
+	errs = append(errs, validateDuration(spec.Child("ttl"), r.Spec.TTL)...)
+
+	return invalidError("Semaphore", r.Name, errs)
+}
+
+// setDefaults defaults an unset permit count to DefaultSemaphorePermits
+func (r *Semaphore) setDefaults() {
+	if r.Spec.Permits == 0 {
+		r.Spec.Permits = DefaultSemaphorePermits
+	}
+}
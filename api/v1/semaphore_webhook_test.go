@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSemaphoreValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    SemaphoreSpec
+		wantErr string
+	}{
+		{
+			name: "valid",
+			spec: SemaphoreSpec{Permits: 3, TTL: &metav1.Duration{Duration: time.Minute}},
+		},
+		{
+			name:    "zero permits",
+			spec:    SemaphoreSpec{Permits: 0},
+			wantErr: "spec.permits: Invalid value: 0: must be at least 1",
+		},
+		{
+			name:    "negative permits",
+			spec:    SemaphoreSpec{Permits: -2},
+			wantErr: "spec.permits: Invalid value: -2: must be at least 1",
+		},
+		{
+			name:    "negative ttl",
+			spec:    SemaphoreSpec{Permits: 1, TTL: &metav1.Duration{Duration: -time.Second}},
+			wantErr: `spec.ttl: Invalid value: "-1s": must not be negative`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			semaphore := &Semaphore{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			validator := &semaphoreValidator{}
+
+			_, createErr := validator.ValidateCreate(context.Background(), semaphore)
+			_, updateErr := validator.ValidateUpdate(context.Background(), &Semaphore{}, semaphore)
+
+			if tt.wantErr == "" {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+			for _, err := range []error{createErr, updateErr} {
+				require.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSemaphoreValidator_WrongType(t *testing.T) {
+	_, err := (&semaphoreValidator{}).ValidateCreate(context.Background(), &Barrier{})
+	assert.ErrorContains(t, err, "expected a Semaphore")
+}
+
+func TestSemaphoreDefaulter(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     SemaphoreSpec
+		expected int32
+	}{
+		{name: "unset permits", spec: SemaphoreSpec{}, expected: DefaultSemaphorePermits},
+		{name: "explicit permits", spec: SemaphoreSpec{Permits: 5}, expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			semaphore := &Semaphore{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+
+			require.NoError(t, (&semaphoreDefaulter{}).Default(context.Background(), semaphore))
+			assert.Equal(t, tt.expected, semaphore.Spec.Permits)
+
+			_, err := (&semaphoreValidator{}).ValidateCreate(context.Background(), semaphore)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -22,6 +22,10 @@ type WaitGroupStatus struct {
 	// +kubebuilder:validation:Enum=Waiting;Done
 	Phase WaitGroupPhase `json:"phase"`
 
+	// ObservedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
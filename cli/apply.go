@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// applyKinds are the kinds apply accepts. Permits, arrivals and lease
+// requests are created by holders through the primitives, not declared.
+var applyKinds = map[string]bool{
+	"Semaphore":      true,
+	"Barrier":        true,
+	"Lease":          true,
+	"Gate":           true,
+	"Mutex":          true,
+	"RWMutex":        true,
+	"Once":           true,
+	"WaitGroup":      true,
+	"Event":          true,
+	"RateLimiter":    true,
+	"CircuitBreaker": true,
+}
+
+// applyResult is the structured form of what apply did with one object
+type applyResult struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Result    string `json:"result"`
+	Error     string `json:"error,omitempty"`
+}
+
+const (
+	applyCreated    = "created"
+	applyConfigured = "configured"
+	applyFailed     = "failed"
+)
+
+func newApplyCmd() *cobra.Command {
+	var filename string
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <file>",
+		Short: "Create or update primitives from a file",
+		Long:  "Create or update the primitives declared in a multi-document YAML or JSON file, reporting the result for each one. Every document is decoded before anything is applied, so a malformed file changes nothing. Pass -f - to read from stdin.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := cmd.InOrStdin()
+			if filename != "-" {
+				f, err := os.Open(filename)
+				if err != nil {
+					return fmt.Errorf("failed to open %s: %w", filename, err)
+				}
+				defer f.Close()
+				in = f
+			}
+
+			objects, err := decodePrimitives(in)
+			if err != nil {
+				return err
+			}
+			if len(objects) == 0 {
+				return fmt.Errorf("no objects found in %s", filename)
+			}
+
+			results := applyPrimitives(cmd.Context(), kubeClient(), objects)
+
+			failed := 0
+			for _, r := range results {
+				if r.Result == applyFailed {
+					failed++
+				}
+			}
+
+			if isStructuredOutput() {
+				if err := printStructuredList(cmd.OutOrStdout(), results); err != nil {
+					return err
+				}
+			} else {
+				for _, r := range results {
+					fields := []zap.Field{
+						zap.String("kind", r.Kind),
+						zap.String("name", r.Name),
+						zap.String("namespace", r.Namespace),
+					}
+					if r.Result == applyFailed {
+						logger.Error("Failed to apply", append(fields, zap.String("error", r.Error))...)
+						continue
+					}
+					logger.Info(dryRunMsg("Applied"), append(fields, zap.String("result", r.Result))...)
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("failed to apply %d of %d objects", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "File declaring the primitives to apply, or - for stdin")
+	_ = cmd.MarkFlagRequired("filename")
+
+	return cmd
+}
+
+// decodePrimitives decodes every document in r into a typed syncv1 object,
+// defaulting the namespace of those that set none. Empty documents are
+// skipped and any other kind is rejected.
+func decodePrimitives(r io.Reader) ([]client.Object, error) {
+	scheme := runtime.NewScheme()
+	if err := syncv1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	deserializer := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var objects []client.Object
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for doc := 1; ; doc++ {
+		data, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read document %d: %w", doc, err)
+		}
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", doc, err)
+		}
+		if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+			continue
+		}
+
+		decoded, gvk, err := deserializer.Decode(data, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document %d: %w", doc, err)
+		}
+		if !applyKinds[gvk.Kind] {
+			return nil, fmt.Errorf("document %d: kind %s cannot be applied", doc, gvk.Kind)
+		}
+
+		obj := decoded.(client.Object)
+		if obj.GetName() == "" {
+			return nil, fmt.Errorf("document %d: %s has no name", doc, gvk.Kind)
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// applyPrimitives creates each object, or updates it if it already exists,
+// carrying on past failures so every object gets a result
+func applyPrimitives(ctx context.Context, c client.Client, objects []client.Object) []applyResult {
+	results := make([]applyResult, 0, len(objects))
+	for _, obj := range objects {
+		result := applyResult{
+			Kind:      obj.GetObjectKind().GroupVersionKind().Kind,
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		}
+
+		outcome, err := applyPrimitive(ctx, c, obj)
+		if err != nil {
+			result.Result = applyFailed
+			result.Error = err.Error()
+		} else {
+			result.Result = outcome
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// applyPrimitive creates obj, or replaces the spec, labels and annotations of
+// the object of the same name if there is one. Status is left to the
+// controller, and finalizers and owner references the file does not set are
+// kept.
+func applyPrimitive(ctx context.Context, c client.Client, obj client.Object) (string, error) {
+	existing := obj.DeepCopyObject().(client.Object)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		if err := c.Create(ctx, obj); err != nil {
+			return "", err
+		}
+		return applyCreated, nil
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if len(obj.GetFinalizers()) == 0 {
+		obj.SetFinalizers(existing.GetFinalizers())
+	}
+	if len(obj.GetOwnerReferences()) == 0 {
+		obj.SetOwnerReferences(existing.GetOwnerReferences())
+	}
+	if err := c.Update(ctx, obj); err != nil {
+		return "", err
+	}
+	return applyConfigured, nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+const applyTestManifest = `apiVersion: sync.konductor.io/v1
+kind: Semaphore
+metadata:
+  name: db-pool
+spec:
+  permits: 5
+---
+apiVersion: sync.konductor.io/v1
+kind: Barrier
+metadata:
+  name: stage-sync
+spec:
+  expected: 3
+---
+apiVersion: sync.konductor.io/v1
+kind: Lease
+metadata:
+  name: leader
+spec:
+  ttl: 30s
+---
+apiVersion: sync.konductor.io/v1
+kind: Gate
+metadata:
+  name: deploy-gate
+spec:
+  conditions:
+  - type: Job
+    name: migrate
+    state: Complete
+---
+# An empty document is skipped
+---
+apiVersion: sync.konductor.io/v1
+kind: Mutex
+metadata:
+  name: migrations
+  namespace: other
+spec: {}
+---
+apiVersion: sync.konductor.io/v1
+kind: RWMutex
+metadata:
+  name: config
+spec: {}
+---
+apiVersion: sync.konductor.io/v1
+kind: Once
+metadata:
+  name: init
+spec: {}
+`
+
+func setupApplyTest(t *testing.T, objects ...runtime.Object) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		Build()
+	namespace = "default"
+}
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "primitives.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestApplyCmd_CreatesAllObjects(t *testing.T) {
+	setupApplyTest(t)
+
+	cmd := newApplyCmd()
+	cmd.SetArgs([]string{"-f", writeManifest(t, applyTestManifest)})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Equal(t, 7, strings.Count(output, "created"))
+
+	ctx := context.Background()
+	key := func(name string) types.NamespacedName {
+		return types.NamespacedName{Name: name, Namespace: "default"}
+	}
+
+	var sem syncv1.Semaphore
+	require.NoError(t, k8sClient.Get(ctx, key("db-pool"), &sem))
+	assert.Equal(t, int32(5), sem.Spec.Permits)
+
+	var bar syncv1.Barrier
+	require.NoError(t, k8sClient.Get(ctx, key("stage-sync"), &bar))
+	assert.Equal(t, int32(3), bar.Spec.Expected)
+
+	objects := map[string]client.Object{
+		"leader":      &syncv1.Lease{},
+		"deploy-gate": &syncv1.Gate{},
+		"config":      &syncv1.RWMutex{},
+		"init":        &syncv1.Once{},
+	}
+	for name, obj := range objects {
+		assert.NoError(t, k8sClient.Get(ctx, key(name), obj), name)
+	}
+
+	var mutex syncv1.Mutex
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "migrations", Namespace: "other"}, &mutex),
+		"a namespace set in the file wins over the default")
+}
+
+func TestApplyCmd_UpdatesExistingObjects(t *testing.T) {
+	setupApplyTest(t, &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "db-pool",
+			Namespace:  "default",
+			Finalizers: []string{"sync.konductor.io/cleanup"},
+		},
+		Spec: syncv1.SemaphoreSpec{Permits: 1},
+	})
+
+	cmd := newApplyCmd()
+	cmd.SetArgs([]string{"-f", writeManifest(t, applyTestManifest)})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(output, "configured"))
+	assert.Equal(t, 6, strings.Count(output, "created"))
+
+	var sem syncv1.Semaphore
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "db-pool", Namespace: "default"}, &sem))
+	assert.Equal(t, int32(5), sem.Spec.Permits)
+	assert.Equal(t, []string{"sync.konductor.io/cleanup"}, sem.Finalizers)
+}
+
+func TestApplyCmd_FromStdin(t *testing.T) {
+	setupApplyTest(t)
+
+	cmd := newApplyCmd()
+	cmd.SetArgs([]string{"-f", "-"})
+	cmd.SetIn(strings.NewReader(applyTestManifest))
+	_, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+
+	var once syncv1.Once
+	assert.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "init", Namespace: "default"}, &once))
+}
+
+func TestApplyCmd_StructuredOutput(t *testing.T) {
+	setupApplyTest(t)
+	originalFormat := outputFormat
+	defer func() { outputFormat = originalFormat }()
+	outputFormat = "json"
+
+	cmd := newApplyCmd()
+	cmd.SetArgs([]string{"-f", writeManifest(t, applyTestManifest)})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, `"kind": "Semaphore"`)
+	assert.Contains(t, output, `"result": "created"`)
+}
+
+func TestApplyCmd_RejectsFileBeforeApplying(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		errMsg   string
+	}{
+		{
+			name:     "kind that is not a primitive",
+			manifest: applyTestManifest + "---\napiVersion: sync.konductor.io/v1\nkind: Permit\nmetadata:\n  name: p\n",
+			errMsg:   "kind Permit cannot be applied",
+		},
+		{
+			name:     "unknown kind",
+			manifest: applyTestManifest + "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: c\n",
+			errMsg:   "failed to decode document 9",
+		},
+		{
+			name:     "missing name",
+			manifest: "apiVersion: sync.konductor.io/v1\nkind: Mutex\nmetadata: {}\n",
+			errMsg:   "has no name",
+		},
+		{
+			name:     "malformed yaml",
+			manifest: applyTestManifest + "---\nkind: [\n",
+			errMsg:   "failed to parse document 9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupApplyTest(t)
+
+			cmd := newApplyCmd()
+			cmd.SetArgs([]string{"-f", writeManifest(t, tt.manifest)})
+			_, err := executeCommandWithOutput(t, cmd)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+
+			var sem syncv1.Semaphore
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "db-pool", Namespace: "default"}, &sem)
+			assert.True(t, apierrors.IsNotFound(err), "nothing is applied from a rejected file")
+		})
+	}
+}
+
+func TestApplyCmd_ReportsFailuresAndCarriesOn(t *testing.T) {
+	setupApplyTest(t)
+
+	// An object of a type the client's scheme does not know fails on its own
+	objects := []client.Object{
+		&syncv1.Mutex{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default"}},
+		&unregistered{ObjectMeta: metav1.ObjectMeta{Name: "bad", Namespace: "default"}},
+		&syncv1.Mutex{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default"}},
+	}
+
+	results := applyPrimitives(context.Background(), k8sClient, objects)
+	require.Len(t, results, 3)
+	assert.Equal(t, applyCreated, results[0].Result)
+	assert.Equal(t, applyFailed, results[1].Result)
+	assert.NotEmpty(t, results[1].Error)
+	assert.Equal(t, applyCreated, results[2].Result)
+}
+
+// unregistered is a kind missing from the test scheme
+type unregistered struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+}
+
+func (u *unregistered) DeepCopyObject() runtime.Object {
+	c := *u
+	return &c
+}
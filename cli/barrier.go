@@ -1,10 +1,17 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -24,16 +31,23 @@ func newBarrierCmd() *cobra.Command {
 	cmd.AddCommand(newBarrierWaitCmd())
 	cmd.AddCommand(newBarrierArriveCmd())
 	cmd.AddCommand(newBarrierListCmd())
+	cmd.AddCommand(newBarrierResetCmd())
+
+	registerNameCompletion(cmd, &syncv1.BarrierList{})
 
 	return cmd
 }
 
 func createBarrierClient() *konductor.Client {
-	return konductor.NewFromClient(k8sClient, namespace)
+	return konductor.NewFromClient(kubeClient(), namespace)
 }
 
 func newBarrierWaitCmd() *cobra.Command {
-	var timeout time.Duration
+	var (
+		timeout    time.Duration
+		events     bool
+		minArrived int32
+	)
 
 	cmd := &cobra.Command{
 		Use:   "wait <barrier-name>",
@@ -43,8 +57,35 @@ func newBarrierWaitCmd() *cobra.Command {
 			barrierName := args[0]
 			ctx := cmd.Context()
 
+			countOnly := cmd.Flags().Changed("min-arrived")
+			if events && countOnly {
+				return fmt.Errorf("--events cannot be combined with --min-arrived")
+			}
+			if events {
+				return waitBarrierEvents(ctx, cmd.OutOrStdout(), barrierName, timeout)
+			}
+
 			client := createBarrierClient()
 
+			if countOnly {
+				if err := barrier.WaitForCount(client, ctx, barrierName, minArrived, timeout); err != nil {
+					return err
+				}
+
+				barrierObj, err := barrier.Get(client, ctx, barrierName)
+				if err != nil {
+					logger.Warn("Failed to get barrier status", zap.Error(err))
+				} else if barrierObj != nil {
+					logger.Info("Barrier reached arrival count",
+						zap.String("barrier", barrierName),
+						zap.Int32("arrived", barrierObj.Status.Arrived),
+						zap.Int32("min-arrived", minArrived),
+						zap.Int32("expected", barrierObj.Spec.Expected),
+					)
+				}
+				return nil
+			}
+
 			// Build options
 			var opts []konductor.Option
 			if timeout > 0 {
@@ -73,13 +114,169 @@ func newBarrierWaitCmd() *cobra.Command {
 	}
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for waiting (e.g., 30s, 5m)")
+	cmd.Flags().BoolVar(&events, "events", false, "Print a JSON line for every status change until the barrier opens or fails")
+	cmd.Flags().Int32Var(&minArrived, "min-arrived", 0, "Return once at least this many have arrived, whether or not the barrier is open")
 
 	return cmd
 }
 
+// defaultBarrierEventsTimeout matches how long barrier.Wait waits when no
+// timeout is given
+const defaultBarrierEventsTimeout = 30 * time.Second
+
+// waitBarrierEvents writes a JSON line to out for every status change of the
+// named barrier until it opens or fails, or timeout passes. The watch is
+// re-established whenever the API server closes it.
+func waitBarrierEvents(ctx context.Context, out io.Writer, name string, timeout time.Duration) error {
+	watcher, ok := k8sClient.(ctrlclient.WithWatch)
+	if !ok {
+		return fmt.Errorf("kubernetes client does not support watch")
+	}
+
+	if timeout <= 0 {
+		timeout = defaultBarrierEventsTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stream := &barrierEventStream{encoder: json.NewEncoder(out), name: name}
+	for {
+		w, err := watcher.Watch(waitCtx, &syncv1.BarrierList{},
+			ctrlclient.InNamespace(namespace),
+			ctrlclient.MatchingFields{"metadata.name": name})
+		if err != nil {
+			if waitCtx.Err() != nil {
+				return barrierEventsError(ctx, name)
+			}
+			return fmt.Errorf("failed to watch barrier %s: %w", name, err)
+		}
+
+		done, err := stream.follow(waitCtx, w)
+		w.Stop()
+		if done || err != nil {
+			return err
+		}
+		if waitCtx.Err() != nil {
+			return barrierEventsError(ctx, name)
+		}
+	}
+}
+
+// barrierEventsError reports cancellation of ctx as-is and the end of the
+// timeout as ErrTimeout
+func barrierEventsError(ctx context.Context, name string) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("context cancelled while waiting for barrier %s: %w", name, ctx.Err())
+	}
+	return fmt.Errorf("%w waiting for barrier %s", konductor.ErrTimeout, name)
+}
+
+// barrierEventStream prints the transitions of one barrier, remembering the
+// last one across re-established watches
+type barrierEventStream struct {
+	encoder *json.Encoder
+	name    string
+	last    *barrierSummary
+
+	// generation is the round of a cyclic barrier in progress when it was
+	// first seen, which the wait is for
+	generation *int64
+}
+
+// follow reads the barrier's current state once w is started, so no
+// transition between the two can be missed, then prints every change from w.
+// It reports true once the barrier has opened, failed or been deleted, and
+// false when w closes or ctx is done.
+func (s *barrierEventStream) follow(ctx context.Context, w watch.Interface) (bool, error) {
+	var current syncv1.Barrier
+	err := k8sClient.Get(ctx, ctrlclient.ObjectKey{Name: s.name, Namespace: namespace}, &current)
+	switch {
+	case err == nil:
+		if done, err := s.observe(watch.Added, &current); done || err != nil {
+			return done, err
+		}
+	case !apierrors.IsNotFound(err) && ctx.Err() == nil:
+		return false, fmt.Errorf("failed to get barrier %s: %w", s.name, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case event, open := <-w.ResultChan():
+			if !open {
+				return false, nil
+			}
+			if event.Type != watch.Added && event.Type != watch.Modified && event.Type != watch.Deleted {
+				continue
+			}
+			b, ok := event.Object.(*syncv1.Barrier)
+			if !ok || b.Name != s.name {
+				continue
+			}
+			if done, err := s.observe(event.Type, b); done || err != nil {
+				return done, err
+			}
+		}
+	}
+}
+
+// observe prints b if its status changed and reports whether the wait is over
+func (s *barrierEventStream) observe(eventType watch.EventType, b *syncv1.Barrier) (bool, error) {
+	summary, _, _ := summarizeBarrier(b)
+	current := summary.(barrierSummary)
+	if eventType == watch.Deleted || s.last == nil || current != *s.last {
+		s.last = &current
+		if err := s.encoder.Encode(watchEvent{Type: eventType, Status: current}); err != nil {
+			return true, err
+		}
+	}
+
+	if eventType == watch.Deleted {
+		return true, fmt.Errorf("barrier %s was deleted", s.name)
+	}
+
+	// As in barrier.Wait, a cyclic barrier never stays Open, so the wait is
+	// over once the round in progress when it started has opened
+	opened := b.Status.Phase == syncv1.BarrierPhaseOpen
+	if b.Spec.Cyclic {
+		if s.generation == nil {
+			started := b.Status.Generation
+			s.generation = &started
+		}
+		opened = b.Status.Generation > *s.generation
+	}
+
+	switch {
+	case opened:
+		return true, nil
+	case b.Status.Phase == syncv1.BarrierPhaseFailed:
+		return true, fmt.Errorf("barrier %s failed", s.name)
+	}
+	return false, nil
+}
+
+// arriveMany signals arrival for each of holders, logging each holder that
+// could not arrive
+func arriveMany(ctx context.Context, client *konductor.Client, name string, holders []string) error {
+	failed, err := barrier.ArriveMany(client, ctx, name, holders)
+	for _, holder := range holders {
+		if holderErr, ok := failed[holder]; ok {
+			logger.Error("Failed to signal arrival", zap.String("barrier", name), zap.String("holder", holder), zap.Error(holderErr))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Signaled arrivals at barrier", zap.String("barrier", name), zap.Strings("holders", holders))
+	return nil
+}
+
 func newBarrierArriveCmd() *cobra.Command {
 	var (
 		holder            string
+		holders           []string
 		waitForUpdate     bool
 		updateWaitTimeout time.Duration
 	)
@@ -94,14 +291,34 @@ func newBarrierArriveCmd() *cobra.Command {
 
 			client := createBarrierClient()
 
+			if len(holders) > 0 {
+				if len(args) > 1 || holder != "" {
+					return errors.New("--holders cannot be combined with --holder or a holder argument")
+				}
+				if skipForDryRun("signal arrivals at barrier", zap.String("barrier", barrierName), zap.Strings("holders", holders)) {
+					return nil
+				}
+				return arriveMany(ctx, client, barrierName, holders)
+			}
+
+			if len(args) > 1 {
+				holder = args[1]
+			}
+			holder, err := validateHolder(holder)
+			if err != nil {
+				return err
+			}
+
 			// Build options
 			var opts []konductor.Option
-			if len(args) > 1 {
-				opts = append(opts, konductor.WithHolder(args[1]))
-			} else if holder != "" {
+			if holder != "" {
 				opts = append(opts, konductor.WithHolder(holder))
 			}
 
+			if skipForDryRun("signal arrival at barrier", zap.String("barrier", barrierName)) {
+				return nil
+			}
+
 			// Arrive at barrier using SDK
 			if err := barrier.Arrive(client, ctx, barrierName, opts...); err != nil {
 				return err
@@ -144,7 +361,8 @@ func newBarrierArriveCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&holder, "holder", "", "Arrival holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Arrival holder identifier (defaults to the detected holder)")
+	cmd.Flags().StringSliceVar(&holders, "holders", nil, "Comma-separated holders to signal arrival for at once")
 	cmd.Flags().BoolVar(&waitForUpdate, "wait-for-update", false, "Wait for controller to process the change")
 	cmd.Flags().DurationVar(&updateWaitTimeout, "update-timeout", 5*time.Second, "Timeout for waiting for controller update")
 
@@ -152,6 +370,8 @@ func newBarrierArriveCmd() *cobra.Command {
 }
 
 func newBarrierListCmd() *cobra.Command {
+	var filters listFilters
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all barriers",
@@ -160,12 +380,21 @@ func newBarrierListCmd() *cobra.Command {
 
 			client := createBarrierClient()
 
+			opts, err := filters.options()
+			if err != nil {
+				return err
+			}
+
 			// List barriers using SDK
-			barriers, err := barrier.List(client, ctx)
+			barriers, err := barrier.List(client, ctx, opts...)
 			if err != nil {
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), barriers)
+			}
+
 			if len(barriers) == 0 {
 				logger.Info("No barriers found")
 				return nil
@@ -179,6 +408,7 @@ func newBarrierListCmd() *cobra.Command {
 
 				logger.Info("Barrier",
 					zap.String("name", b.Name),
+					zap.String("namespace", b.Namespace),
 					zap.Int32("expected", b.Spec.Expected),
 					zap.Int32("arrived", b.Status.Arrived),
 					zap.String("phase", string(b.Status.Phase)),
@@ -190,6 +420,8 @@ func newBarrierListCmd() *cobra.Command {
 		},
 	}
 
+	addListFlags(cmd, &filters)
+
 	return cmd
 }
 
@@ -222,7 +454,7 @@ func newBarrierCreateCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Created barrier",
+			logger.Info(dryRunMsg("Created barrier"),
 				zap.String("barrier", barrierName),
 				zap.Int32("expected", expected),
 			)
@@ -238,6 +470,9 @@ func newBarrierCreateCmd() *cobra.Command {
 }
 
 func newBarrierDeleteCmd() *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "delete <barrier-name>",
 		Short: "Delete a barrier",
@@ -252,7 +487,42 @@ func newBarrierDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Deleted barrier", zap.String("barrier", barrierName))
+			if err := waitForDeletion(ctx, client, &syncv1.Barrier{}, barrierName, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted barrier"), zap.String("barrier", barrierName))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the barrier and its arrivals are gone")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultDeleteWaitTimeout, "How long --wait blocks before giving up")
+
+	return cmd
+}
+
+func newBarrierResetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset <barrier-name>",
+		Short: "Reset a barrier for another round",
+		Long:  "Delete all arrivals at a barrier and return it to the Waiting phase so it can be reused",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			barrierName := args[0]
+			ctx := cmd.Context()
+
+			client := createBarrierClient()
+
+			if skipForDryRun("reset barrier", zap.String("barrier", barrierName)) {
+				return nil
+			}
+
+			if err := barrier.Reset(client, ctx, barrierName); err != nil {
+				return err
+			}
+
+			logger.Info("Reset barrier", zap.String("barrier", barrierName))
 			return nil
 		},
 	}
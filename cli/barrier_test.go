@@ -1,19 +1,29 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
 func setupTestClient(t *testing.T, objects ...runtime.Object) {
@@ -78,6 +88,52 @@ func TestBarrierWaitCmd_Failed(t *testing.T) {
 	assert.Contains(t, err.Error(), "barrier test-barrier failed")
 }
 
+func TestBarrierWaitCmd_MinArrived(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "default",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 3,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase:   syncv1.BarrierPhaseWaiting,
+			Arrived: 1,
+		},
+	}
+
+	setupTestClient(t, barrier)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		var current syncv1.Barrier
+		if err := k8sClient.Get(context.Background(), types.NamespacedName{
+			Name: "test-barrier", Namespace: "default",
+		}, &current); err != nil {
+			return
+		}
+		current.Status.Arrived = 2
+		_ = k8sClient.Update(context.Background(), &current)
+	}()
+
+	cmd := newBarrierWaitCmd()
+	cmd.SetArgs([]string{"test-barrier", "--min-arrived", "2", "--timeout", "10s"})
+	require.NoError(t, cmd.Execute())
+
+	cmd = newBarrierWaitCmd()
+	cmd.SetArgs([]string{"test-barrier", "--min-arrived", "3", "--timeout", "300ms"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+
+	cmd = newBarrierWaitCmd()
+	cmd.SetArgs([]string{"test-barrier", "--min-arrived", "2", "--events"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--events cannot be combined with --min-arrived")
+}
+
 func TestBarrierArriveCmd(t *testing.T) {
 	barrier := &syncv1.Barrier{
 		ObjectMeta: metav1.ObjectMeta{
@@ -102,6 +158,80 @@ func TestBarrierArriveCmd(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestBarrierArriveCmd_HolderFile(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "default",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 3,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	setupTestClient(t, barrier)
+	useHolderFile(t, "sidecar-holder")
+
+	cmd := newBarrierArriveCmd()
+	cmd.SetArgs([]string{"test-barrier"})
+	require.NoError(t, cmd.Execute())
+
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, k8sClient.List(context.Background(), &arrivals))
+	require.Len(t, arrivals.Items, 1)
+	assert.Equal(t, "sidecar-holder", arrivals.Items[0].Spec.Holder)
+}
+
+func TestBarrierArriveCmd_Holders(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "default",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 3,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	setupTestClient(t, barrier)
+
+	cmd := newBarrierArriveCmd()
+	cmd.SetArgs([]string{"test-barrier", "--holders", "worker-1,worker-2"})
+	require.NoError(t, cmd.Execute())
+
+	// Arriving again for a holder that is already there is a no-op
+	cmd = newBarrierArriveCmd()
+	cmd.SetArgs([]string{"test-barrier", "--holders", "worker-2,worker-3"})
+	require.NoError(t, cmd.Execute())
+
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, k8sClient.List(context.Background(), &arrivals))
+	holders := make([]string, 0, len(arrivals.Items))
+	for _, arrival := range arrivals.Items {
+		holders = append(holders, arrival.Spec.Holder)
+	}
+	assert.ElementsMatch(t, []string{"worker-1", "worker-2", "worker-3"}, holders)
+}
+
+func TestBarrierArriveCmd_HoldersWithHolder(t *testing.T) {
+	setupTestClient(t)
+
+	cmd := newBarrierArriveCmd()
+	cmd.SetArgs([]string{"test-barrier", "worker-1", "--holders", "worker-2,worker-3"})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be combined")
+}
+
 func TestBarrierListCmd(t *testing.T) {
 	barriers := []runtime.Object{
 		&syncv1.Barrier{
@@ -196,3 +326,171 @@ func TestBarrierDeleteCmd(t *testing.T) {
 	err := cmd.Execute()
 	require.NoError(t, err)
 }
+
+func TestBarrierResetCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "default",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 1,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase:    syncv1.BarrierPhaseOpen,
+			Arrived:  1,
+			Arrivals: []string{"holder-1"},
+		},
+	}
+	arrival := &syncv1.Arrival{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier-holder-1",
+			Namespace: "default",
+			Labels:    map[string]string{"barrier": "test-barrier"},
+		},
+		Spec: syncv1.ArrivalSpec{
+			Barrier: "test-barrier",
+			Holder:  "holder-1",
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(barrier, arrival).
+		WithStatusSubresource(&syncv1.Barrier{}).
+		Build()
+	namespace = "default"
+	logger, _ = zap.NewDevelopment()
+
+	cmd := newBarrierResetCmd()
+	cmd.SetArgs([]string{"test-barrier"})
+	require.NoError(t, cmd.Execute())
+
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, k8sClient.List(context.Background(), &arrivals))
+	assert.Empty(t, arrivals.Items)
+
+	var updated syncv1.Barrier
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "test-barrier", Namespace: "default"}, &updated))
+	assert.Equal(t, syncv1.BarrierPhaseWaiting, updated.Status.Phase)
+	assert.Equal(t, int32(0), updated.Status.Arrived)
+}
+
+func newEventsBarrier(arrived int32, phase syncv1.BarrierPhase) *syncv1.Barrier {
+	return &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "default",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 3,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase:   phase,
+			Arrived: arrived,
+		},
+	}
+}
+
+// setupBarrierEventsClient returns a client holding current whose watches
+// replay events and then stay open
+func setupBarrierEventsClient(t *testing.T, current *syncv1.Barrier, events ...watch.Event) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(current).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+				w := watch.NewFakeWithChanSize(len(events), false)
+				for _, event := range events {
+					w.Action(event.Type, event.Object)
+				}
+				return w, nil
+			},
+		}).
+		Build()
+	namespace = "default"
+	logger, _ = zap.NewDevelopment()
+}
+
+type barrierWatchEvent struct {
+	Type   string         `json:"type"`
+	Status barrierSummary `json:"status"`
+}
+
+func executeBarrierEvents(t *testing.T, args ...string) ([]barrierWatchEvent, error) {
+	t.Helper()
+	cmd := newBarrierWaitCmd()
+	cmd.SetArgs(append([]string{"test-barrier", "--events"}, args...))
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	var buf strings.Builder
+	cmd.SetOut(&buf)
+	err := cmd.Execute()
+
+	var events []barrierWatchEvent
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var event barrierWatchEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event), "each event is a single JSON line")
+		events = append(events, event)
+	}
+	return events, err
+}
+
+func TestBarrierWaitCmd_EventsUntilOpen(t *testing.T) {
+	setupBarrierEventsClient(t, newEventsBarrier(1, syncv1.BarrierPhaseWaiting),
+		// The watch replays the state that was just read
+		watch.Event{Type: watch.Added, Object: newEventsBarrier(1, syncv1.BarrierPhaseWaiting)},
+		watch.Event{Type: watch.Modified, Object: newEventsBarrier(2, syncv1.BarrierPhaseWaiting)},
+		watch.Event{Type: watch.Modified, Object: newEventsBarrier(3, syncv1.BarrierPhaseOpen)},
+		// Nothing after the barrier opens is printed
+		watch.Event{Type: watch.Deleted, Object: newEventsBarrier(3, syncv1.BarrierPhaseOpen)},
+	)
+
+	events, err := executeBarrierEvents(t)
+	require.NoError(t, err)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, barrierWatchEvent{Type: "ADDED", Status: barrierSummary{
+		Name: "test-barrier", Arrived: 1, Expected: 3, Phase: "Waiting",
+	}}, events[0])
+	assert.Equal(t, "MODIFIED", events[1].Type)
+	assert.Equal(t, int32(2), events[1].Status.Arrived)
+	assert.Equal(t, "MODIFIED", events[2].Type)
+	assert.Equal(t, int32(3), events[2].Status.Arrived)
+	assert.Equal(t, "Open", events[2].Status.Phase)
+}
+
+func TestBarrierWaitCmd_EventsUntilFailed(t *testing.T) {
+	setupBarrierEventsClient(t, newEventsBarrier(1, syncv1.BarrierPhaseWaiting),
+		watch.Event{Type: watch.Modified, Object: newEventsBarrier(1, syncv1.BarrierPhaseFailed)},
+	)
+
+	events, err := executeBarrierEvents(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "barrier test-barrier failed")
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "Waiting", events[0].Status.Phase)
+	assert.Equal(t, "Failed", events[1].Status.Phase)
+}
+
+func TestBarrierWaitCmd_EventsTimeout(t *testing.T) {
+	setupBarrierEventsClient(t, newEventsBarrier(1, syncv1.BarrierPhaseWaiting))
+
+	events, err := executeBarrierEvents(t, "--timeout", "200ms")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+
+	require.Len(t, events, 1)
+	assert.Equal(t, int32(1), events[0].Status.Arrived)
+}
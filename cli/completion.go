@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate shell completion scripts",
+		Long: `Generate a completion script for koncli. Loading it completes commands,
+flags and the names of primitives that already exist in the cluster.
+
+  # bash
+  source <(koncli completion bash)
+
+  # zsh
+  koncli completion zsh > "${fpath[1]}/_koncli"
+
+  # fish
+  koncli completion fish | source
+
+  # powershell
+  koncli completion powershell | Out-String | Invoke-Expression`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		// Generating a script needs no cluster, so skip the client setup
+		// done for every other command
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletionV2(out, true)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(out)
+			case "fish":
+				return cmd.Root().GenFishCompletion(out, true)
+			case "powershell":
+				return cmd.Root().GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("unsupported shell %q", args[0])
+		},
+	}
+
+	return cmd
+}
+
+// registerNameCompletion completes the <name> argument of cmd's subcommands
+// with the names of the existing objects that list holds. create is left out,
+// since it takes a name that does not exist yet.
+func registerNameCompletion(cmd *cobra.Command, list client.ObjectList) {
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == "create" || !strings.Contains(sub.Use, "<") {
+			continue
+		}
+		sub.ValidArgsFunction = completeNames(list)
+	}
+}
+
+// completeNames returns a completion function listing the names of the
+// objects that list holds in the current namespace. Only the first argument
+// is completed.
+func completeNames(list client.ObjectList) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		if err := initCompletionClient(cmd); err != nil {
+			cobra.CompDebugln(fmt.Sprintf("failed to set up client: %v", err), true)
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		objects := list.DeepCopyObject().(client.ObjectList)
+		if err := k8sClient.List(cmd.Context(), objects, client.InNamespace(namespace)); err != nil {
+			cobra.CompDebugln(fmt.Sprintf("failed to list names: %v", err), true)
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var names []string
+		err := meta.EachListItem(objects, func(obj runtime.Object) error {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(accessor.GetName(), toComplete) {
+				names = append(names, accessor.GetName())
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// initCompletionClient sets up the client for a completion request, for which
+// cobra does not run the root command's PersistentPreRunE. Anything logged
+// would end up among the completions, so the logger is silenced.
+func initCompletionClient(cmd *cobra.Command) error {
+	if k8sClient != nil {
+		return nil
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return initKubeClient(cmd)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func newCompletionTestRoot() (*cobra.Command, *bytes.Buffer) {
+	root := &cobra.Command{Use: "koncli"}
+	root.AddCommand(newCompletionCmd())
+	root.AddCommand(newMutexCmd())
+	root.AddCommand(newSemaphoreCmd())
+	root.AddCommand(newStatusCmd())
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &buf
+}
+
+func setupCompletionClient(t *testing.T) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(
+			&syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "db-pool", Namespace: "default"}},
+			&syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "api-quota", Namespace: "default"}},
+			&syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "db-other-ns", Namespace: "other"}},
+			&syncv1.Mutex{ObjectMeta: metav1.ObjectMeta{Name: "migrations", Namespace: "default"}},
+		).
+		Build()
+	namespace = "default"
+}
+
+func TestCompletionCmd(t *testing.T) {
+	tests := []struct {
+		shell    string
+		expected string
+	}{
+		{shell: "bash", expected: "__start_koncli"},
+		{shell: "zsh", expected: "#compdef koncli"},
+		{shell: "fish", expected: "complete -c koncli"},
+		{shell: "powershell", expected: "Register-ArgumentCompleter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			root, buf := newCompletionTestRoot()
+			root.SetArgs([]string{"completion", tt.shell})
+
+			require.NoError(t, root.Execute())
+			assert.Contains(t, buf.String(), tt.expected)
+		})
+	}
+}
+
+func TestCompletionCmd_UnsupportedShell(t *testing.T) {
+	root, _ := newCompletionTestRoot()
+	root.SetArgs([]string{"completion", "tcsh"})
+
+	assert.Error(t, root.Execute())
+}
+
+func TestCompleteNames(t *testing.T) {
+	setupCompletionClient(t)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	complete := completeNames(&syncv1.SemaphoreList{})
+
+	names, directive := complete(cmd, nil, "")
+	assert.ElementsMatch(t, []string{"db-pool", "api-quota"}, names)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	names, _ = complete(cmd, nil, "db")
+	assert.Equal(t, []string{"db-pool"}, names)
+
+	names, _ = complete(cmd, []string{"db-pool"}, "")
+	assert.Empty(t, names, "only the name argument is completed")
+}
+
+func TestRegisterNameCompletion(t *testing.T) {
+	cmd := newMutexCmd()
+
+	for _, sub := range cmd.Commands() {
+		switch sub.Name() {
+		case "create", "list":
+			assert.Nil(t, sub.ValidArgsFunction, "%s does not take an existing name", sub.Name())
+		default:
+			assert.NotNil(t, sub.ValidArgsFunction, "%s should complete mutex names", sub.Name())
+		}
+	}
+}
+
+func TestCompletion_ListsNamesFromCluster(t *testing.T) {
+	setupCompletionClient(t)
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{"semaphore", "acquire", ""}, expected: []string{"api-quota", "db-pool"}},
+		{args: []string{"mutex", "lock", ""}, expected: []string{"migrations"}},
+		{args: []string{"status", "semaphore", "api"}, expected: []string{"api-quota"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args[:2], " "), func(t *testing.T) {
+			root, buf := newCompletionTestRoot()
+			root.SetArgs(append([]string{cobra.ShellCompNoDescRequestCmd}, tt.args...))
+			root.SetContext(context.Background())
+
+			require.NoError(t, root.Execute())
+
+			// Completions are followed by a ":<directive>" line
+			var names []string
+			for _, line := range strings.Split(buf.String(), "\n") {
+				if strings.HasPrefix(line, ":") {
+					break
+				}
+				names = append(names, line)
+			}
+			assert.ElementsMatch(t, tt.expected, names)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+// defaultDeleteWaitTimeout bounds how long a delete command's --wait blocks
+const defaultDeleteWaitTimeout = time.Minute
+
+// waitForDeletion blocks until the object of obj's kind named name, and the
+// objects it owns, are gone, when a delete command was run with --wait. A
+// delete sent with --dry-run leaves the object in place, so there is nothing
+// to wait for then.
+func waitForDeletion(ctx context.Context, c *konductor.Client, obj client.Object, name string, wait bool, timeout time.Duration) error {
+	if !wait || dryRun {
+		return nil
+	}
+	obj.SetName(name)
+	return c.WaitForDeletion(ctx, obj, timeout)
+}
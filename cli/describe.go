@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// describeKinds are the primitive kinds describe accepts. A kind with related
+// objects finds them by the label they carry with the primitive's name.
+var describeKinds = []struct {
+	name    string
+	object  client.Object
+	related client.ObjectList
+	label   string
+	title   string
+}{
+	{"semaphore", &syncv1.Semaphore{}, &syncv1.PermitList{}, "semaphore", "Permits"},
+	{"barrier", &syncv1.Barrier{}, &syncv1.ArrivalList{}, "barrier", "Arrivals"},
+	{"lease", &syncv1.Lease{}, &syncv1.LeaseRequestList{}, "lease", "LeaseRequests"},
+	{"gate", &syncv1.Gate{}, nil, "", ""},
+	{"mutex", &syncv1.Mutex{}, nil, "", ""},
+	{"rwmutex", &syncv1.RWMutex{}, nil, "", ""},
+	{"once", &syncv1.Once{}, nil, "", ""},
+	{"waitgroup", &syncv1.WaitGroup{}, nil, "", ""},
+	{"event", &syncv1.Event{}, nil, "", ""},
+	{"ratelimiter", &syncv1.RateLimiter{}, nil, "", ""},
+	{"circuitbreaker", &syncv1.CircuitBreaker{}, nil, "", ""},
+}
+
+// primitiveDescription is the structured form of `describe`. Related holds
+// the Permits of a semaphore, the Arrivals of a barrier or the LeaseRequests
+// of a lease, and is empty for the other kinds.
+type primitiveDescription struct {
+	Object  map[string]interface{}   `json:"object"`
+	Related []map[string]interface{} `json:"related"`
+
+	// relatedTitle heads the related objects in text output, and is empty
+	// for kinds that have none
+	relatedTitle string
+}
+
+func newDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe <kind> <name>",
+		Short: "Show a primitive in full, with its related objects",
+		Long: fmt.Sprintf("Print the spec and status of a primitive together with the objects that belong to it: "+
+			"the Permits of a semaphore, the Arrivals of a barrier and the LeaseRequests of a lease. "+
+			"<kind> is one of: %s.", strings.Join(describeKindNames(), ", ")),
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeDescribeArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			desc, err := describePrimitive(cmd.Context(), k8sClient, args[0], args[1])
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput() {
+				return printStructured(cmd.OutOrStdout(), desc)
+			}
+			return printDescription(cmd.OutOrStdout(), desc)
+		},
+	}
+
+	return cmd
+}
+
+// describeKindNames returns the kinds describe accepts, in help order
+func describeKindNames() []string {
+	names := make([]string, 0, len(describeKinds))
+	for _, k := range describeKinds {
+		names = append(names, k.name)
+	}
+	return names
+}
+
+// completeDescribeArgs completes the kind, then the names of that kind
+func completeDescribeArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		var kinds []string
+		for _, name := range describeKindNames() {
+			if strings.HasPrefix(name, toComplete) {
+				kinds = append(kinds, name)
+			}
+		}
+		return kinds, cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		for _, t := range exportTypes {
+			if t.name == strings.ToLower(args[0]) {
+				return completeNames(t.list)(cmd, nil, toComplete)
+			}
+		}
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// describePrimitive fetches the named primitive of the given kind in the
+// current namespace, along with its related objects
+func describePrimitive(ctx context.Context, c client.Client, kind, name string) (*primitiveDescription, error) {
+	kind = strings.ToLower(kind)
+	for _, k := range describeKinds {
+		if k.name != kind {
+			continue
+		}
+
+		obj := k.object.DeepCopyObject().(client.Object)
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj); err != nil {
+			return nil, fmt.Errorf("failed to get %s %s: %w", kind, name, err)
+		}
+
+		u, err := toUnstructured(c.Scheme(), obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe %s %s: %w", kind, name, err)
+		}
+		desc := &primitiveDescription{Object: u, Related: []map[string]interface{}{}, relatedTitle: k.title}
+
+		if k.related == nil {
+			return desc, nil
+		}
+
+		related := k.related.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, related, client.InNamespace(namespace),
+			client.MatchingLabels{k.label: name}); err != nil {
+			return nil, fmt.Errorf("failed to list objects related to %s %s: %w", kind, name, err)
+		}
+		err = meta.EachListItem(related, func(item runtime.Object) error {
+			u, err := toUnstructured(c.Scheme(), item)
+			if err != nil {
+				return err
+			}
+			desc.Related = append(desc.Related, u)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe objects related to %s %s: %w", kind, name, err)
+		}
+		sort.SliceStable(desc.Related, func(i, j int) bool {
+			return creationTimestamp(desc.Related[i]).Before(creationTimestamp(desc.Related[j]))
+		})
+		return desc, nil
+	}
+
+	return nil, fmt.Errorf("unknown primitive kind %q (valid: %s)", kind, strings.Join(describeKindNames(), ", "))
+}
+
+// printDescription writes desc to w in the style of kubectl describe: the
+// metadata, the spec and status as indented YAML, then a table of the related
+// objects
+func printDescription(w io.Writer, desc *primitiveDescription) error {
+	obj := desc.Object
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Name:\t%s\n", unstructuredString(obj, "metadata", "name"))
+	fmt.Fprintf(tw, "Namespace:\t%s\n", unstructuredString(obj, "metadata", "namespace"))
+	fmt.Fprintf(tw, "Kind:\t%s\n", unstructuredString(obj, "kind"))
+	fmt.Fprintf(tw, "Labels:\t%s\n", formatLabels(obj))
+	if created := creationTimestamp(obj); !created.IsZero() {
+		fmt.Fprintf(tw, "Created:\t%s (%s ago)\n", created.Format(time.RFC3339), duration.HumanDuration(time.Since(created)))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, section := range []string{"spec", "status"} {
+		if err := printSection(w, strings.ToUpper(section[:1])+section[1:], obj[section]); err != nil {
+			return err
+		}
+	}
+
+	if desc.relatedTitle == "" {
+		return nil
+	}
+	return printRelated(w, desc.relatedTitle, desc.Related)
+}
+
+// printSection writes value under title as YAML indented by two spaces
+func printSection(w io.Writer, title string, value interface{}) error {
+	if value == nil {
+		_, err := fmt.Fprintf(w, "%s: <none>\n", title)
+		return err
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s as yaml: %w", strings.ToLower(title), err)
+	}
+	if _, err := fmt.Fprintf(w, "%s:\n", title); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printRelated writes the related objects as a table of name, holder, phase
+// and age, which Permits, Arrivals and LeaseRequests all have
+func printRelated(w io.Writer, title string, related []map[string]interface{}) error {
+	if len(related) == 0 {
+		_, err := fmt.Fprintf(w, "%s: <none>\n", title)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "%s (%d):\n", title, len(related)); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  NAME\tHOLDER\tPHASE\tAGE")
+	for _, obj := range related {
+		age := "<unknown>"
+		if created := creationTimestamp(obj); !created.IsZero() {
+			age = duration.HumanDuration(time.Since(created))
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n",
+			unstructuredString(obj, "metadata", "name"),
+			unstructuredString(obj, "spec", "holder"),
+			unstructuredString(obj, "status", "phase"),
+			age)
+	}
+	return tw.Flush()
+}
+
+// unstructuredString returns the string at fields in obj, or an empty string
+func unstructuredString(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	return value
+}
+
+// formatLabels returns obj's labels as sorted key=value pairs
+func formatLabels(obj map[string]interface{}) string {
+	labels, _, _ := unstructured.NestedStringMap(obj, "metadata", "labels")
+	if len(labels) == 0 {
+		return "<none>"
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// creationTimestamp returns when obj was created, or the zero time
+func creationTimestamp(obj map[string]interface{}) time.Time {
+	created, err := time.Parse(time.RFC3339, unstructuredString(obj, "metadata", "creationTimestamp"))
+	if err != nil {
+		return time.Time{}
+	}
+	return created
+}
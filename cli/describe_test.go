@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func describeTestObjects() []runtime.Object {
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	owned := func(name, label, owner string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            map[string]string{label: owner},
+			CreationTimestamp: created,
+		}
+	}
+
+	return []runtime.Object{
+		&syncv1.Semaphore{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-pool", Namespace: "default", Labels: map[string]string{"team": "data"}},
+			Spec:       syncv1.SemaphoreSpec{Permits: 2},
+			Status:     syncv1.SemaphoreStatus{InUse: 1, Available: 1, Pending: 1, Phase: syncv1.SemaphorePhaseReady},
+		},
+		&syncv1.Permit{
+			ObjectMeta: owned("db-pool-worker-1", "semaphore", "db-pool"),
+			Spec:       syncv1.PermitSpec{Semaphore: "db-pool", Holder: "worker-1"},
+			Status:     syncv1.PermitStatus{Phase: syncv1.PermitPhaseGranted},
+		},
+		&syncv1.Permit{
+			ObjectMeta: owned("db-pool-worker-2", "semaphore", "db-pool"),
+			Spec:       syncv1.PermitSpec{Semaphore: "db-pool", Holder: "worker-2"},
+			Status:     syncv1.PermitStatus{Phase: syncv1.PermitPhasePending},
+		},
+		&syncv1.Permit{
+			ObjectMeta: owned("other-pool-worker-3", "semaphore", "other-pool"),
+			Spec:       syncv1.PermitSpec{Semaphore: "other-pool", Holder: "worker-3"},
+		},
+		&syncv1.Barrier{
+			ObjectMeta: metav1.ObjectMeta{Name: "stage-sync", Namespace: "default"},
+			Spec:       syncv1.BarrierSpec{Expected: 3},
+			Status:     syncv1.BarrierStatus{Arrived: 1, Arrivals: []string{"worker-1"}, Phase: syncv1.BarrierPhaseWaiting},
+		},
+		&syncv1.Arrival{
+			ObjectMeta: owned("stage-sync-worker-1", "barrier", "stage-sync"),
+			Spec:       syncv1.ArrivalSpec{Barrier: "stage-sync", Holder: "worker-1"},
+			Status:     syncv1.ArrivalStatus{Phase: syncv1.ArrivalPhaseRecorded},
+		},
+		&syncv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "leader", Namespace: "default"},
+			Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: time.Minute}},
+			Status:     syncv1.LeaseStatus{Holder: "worker-1", Phase: syncv1.LeasePhaseHeld},
+		},
+		&syncv1.LeaseRequest{
+			ObjectMeta: owned("leader-worker-2", "lease", "leader"),
+			Spec:       syncv1.LeaseRequestSpec{Lease: "leader", Holder: "worker-2"},
+			Status:     syncv1.LeaseRequestStatus{Phase: syncv1.LeaseRequestPhasePending},
+		},
+		&syncv1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"},
+			Status:     syncv1.GateStatus{Phase: syncv1.GatePhaseWaiting},
+		},
+	}
+}
+
+// relatedNames returns the names of the related objects in desc, checking
+// they are all of the given kind
+func relatedNames(t *testing.T, desc *primitiveDescription, kind string) []string {
+	t.Helper()
+	var names []string
+	for _, obj := range desc.Related {
+		assert.Equal(t, kind, obj["kind"])
+		names = append(names, unstructuredString(obj, "metadata", "name"))
+	}
+	return names
+}
+
+func TestDescribePrimitive_RelatedObjects(t *testing.T) {
+	newExportTestClient(t, describeTestObjects()...)
+
+	tests := []struct {
+		kind        string
+		name        string
+		wantKind    string
+		relatedKind string
+		wantRelated []string
+	}{
+		{"semaphore", "db-pool", "Semaphore", "Permit", []string{"db-pool-worker-1", "db-pool-worker-2"}},
+		{"barrier", "stage-sync", "Barrier", "Arrival", []string{"stage-sync-worker-1"}},
+		{"lease", "leader", "Lease", "LeaseRequest", []string{"leader-worker-2"}},
+		{"gate", "deploy", "Gate", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			desc, err := describePrimitive(context.Background(), k8sClient, tt.kind, tt.name)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantKind, desc.Object["kind"])
+			assert.Equal(t, "sync.konductor.io/v1", desc.Object["apiVersion"])
+			assert.Equal(t, tt.name, unstructuredString(desc.Object, "metadata", "name"))
+			assert.Contains(t, desc.Object, "spec")
+			assert.Contains(t, desc.Object, "status")
+			assert.Equal(t, tt.wantRelated, relatedNames(t, desc, tt.relatedKind))
+		})
+	}
+}
+
+func TestDescribePrimitive_Errors(t *testing.T) {
+	newExportTestClient(t, describeTestObjects()...)
+
+	_, err := describePrimitive(context.Background(), k8sClient, "queue", "db-pool")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown primitive kind "queue"`)
+
+	_, err = describePrimitive(context.Background(), k8sClient, "semaphore", "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get semaphore missing")
+}
+
+func TestDescribeCmd_Text(t *testing.T) {
+	newExportTestClient(t, describeTestObjects()...)
+
+	var buf bytes.Buffer
+	cmd := newDescribeCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"Semaphore", "db-pool"})
+	require.NoError(t, cmd.Execute())
+
+	output := buf.String()
+	assert.Contains(t, output, "Name:       db-pool")
+	assert.Contains(t, output, "Kind:       Semaphore")
+	assert.Contains(t, output, "Labels:     team=data")
+	assert.Contains(t, output, "Spec:\n  permits: 2\n")
+	assert.Contains(t, output, "  pending: 1\n")
+	assert.Contains(t, output, "Permits (2):")
+	assert.Regexp(t, `db-pool-worker-1\s+worker-1\s+Granted\s+60m`, output)
+	assert.Regexp(t, `db-pool-worker-2\s+worker-2\s+Pending\s+60m`, output)
+	assert.NotContains(t, output, "other-pool")
+}
+
+func TestDescribeCmd_TextWithoutRelated(t *testing.T) {
+	newExportTestClient(t, describeTestObjects()...)
+
+	var buf bytes.Buffer
+	cmd := newDescribeCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"gate", "deploy"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, buf.String(), "Kind:       Gate")
+	assert.NotContains(t, buf.String(), "Permits")
+
+	buf.Reset()
+	newExportTestClient(t, &syncv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default"}})
+	cmd = newDescribeCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"lease", "idle"})
+	require.NoError(t, cmd.Execute())
+
+	assert.Contains(t, buf.String(), "LeaseRequests: <none>")
+}
+
+func TestDescribeCmd_JSON(t *testing.T) {
+	newExportTestClient(t, describeTestObjects()...)
+	originalFormat := outputFormat
+	outputFormat = "json"
+	defer func() { outputFormat = originalFormat }()
+
+	var buf bytes.Buffer
+	cmd := newDescribeCmd()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"barrier", "stage-sync"})
+	require.NoError(t, cmd.Execute())
+
+	var desc primitiveDescription
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &desc), buf.String())
+	assert.Equal(t, "Barrier", desc.Object["kind"])
+	assert.Equal(t, []string{"stage-sync-worker-1"}, relatedNames(t, &desc, "Arrival"))
+}
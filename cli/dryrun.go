@@ -0,0 +1,36 @@
+package main
+
+import (
+	"go.uber.org/zap"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kubeClient returns the client commands reach the cluster through. With
+// --dry-run, creates and deletes are sent with DryRunAll so the API server
+// validates them without persisting anything.
+func kubeClient() client.Client {
+	if dryRun && k8sClient != nil {
+		return client.NewDryRunClient(k8sClient)
+	}
+	return k8sClient
+}
+
+// skipForDryRun reports whether --dry-run is set, logging the change a
+// status-mutating command would have made in place of making it. These
+// commands wait on the controller or on other holders, so sending their
+// writes as dry runs would leave them waiting for a change that never lands.
+func skipForDryRun(change string, fields ...zap.Field) bool {
+	if !dryRun {
+		return false
+	}
+	logger.Info("Dry run: would "+change, fields...)
+	return true
+}
+
+// dryRunMsg marks the log message of a create or delete sent with --dry-run
+func dryRunMsg(msg string) string {
+	if dryRun {
+		return msg + " (dry run)"
+	}
+	return msg
+}
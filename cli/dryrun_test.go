@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func setupDryRunTest(t *testing.T, objects ...client.Object) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&syncv1.Mutex{}, &syncv1.Barrier{}).
+		Build()
+	namespace = "default"
+
+	dryRun = true
+	t.Cleanup(func() { dryRun = false })
+}
+
+func TestDryRun_CreateDoesNotCreate(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  func() *cobra.Command
+		args []string
+		obj  client.Object
+	}{
+		{name: "semaphore", cmd: newSemaphoreCreateCmd, args: []string{"dry", "--permits", "2"}, obj: &syncv1.Semaphore{}},
+		{name: "barrier", cmd: newBarrierCreateCmd, args: []string{"dry", "--expected", "2"}, obj: &syncv1.Barrier{}},
+		{name: "lease", cmd: newLeaseCreateCmd, args: []string{"dry"}, obj: &syncv1.Lease{}},
+		{name: "gate", cmd: newGateCreateCmd, args: []string{"dry"}, obj: &syncv1.Gate{}},
+		{name: "mutex", cmd: newMutexCreateCmd, args: []string{"dry"}, obj: &syncv1.Mutex{}},
+		{name: "rwmutex", cmd: newRWMutexCreateCmd, args: []string{"dry"}, obj: &syncv1.RWMutex{}},
+		{name: "once", cmd: newOnceCreateCmd, args: []string{"dry"}, obj: &syncv1.Once{}},
+		{name: "waitgroup", cmd: newWaitGroupCreateCmd, args: []string{"dry"}, obj: &syncv1.WaitGroup{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupDryRunTest(t)
+
+			cmd := tt.cmd()
+			cmd.SetArgs(tt.args)
+			output, err := executeCommandWithOutput(t, cmd)
+			require.NoError(t, err)
+			assert.Contains(t, output, "(dry run)")
+
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Name: "dry", Namespace: "default"}, tt.obj)
+			assert.True(t, apierrors.IsNotFound(err), "dry run must not create the %s", tt.name)
+		})
+	}
+}
+
+func TestDryRun_DeleteDoesNotDelete(t *testing.T) {
+	mutex := &syncv1.Mutex{ObjectMeta: metav1.ObjectMeta{Name: "keep", Namespace: "default"}}
+	setupDryRunTest(t, mutex)
+
+	cmd := newMutexDeleteCmd()
+	cmd.SetArgs([]string{"keep"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Deleted mutex (dry run)")
+
+	var existing syncv1.Mutex
+	assert.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "keep", Namespace: "default"}, &existing))
+}
+
+func TestDryRun_LockAndUnlockLeaveMutexAlone(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mutex", Namespace: "default"},
+		Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseUnlocked},
+	}
+	setupDryRunTest(t, mutex)
+
+	lock := newMutexLockCmd()
+	lock.SetArgs([]string{"test-mutex", "--holder", "ci"})
+	output, err := executeCommandWithOutput(t, lock)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Dry run: would lock mutex")
+
+	var updated syncv1.Mutex
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "test-mutex", Namespace: "default"}, &updated))
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, updated.Status.Phase)
+	assert.Empty(t, updated.Status.Holder)
+
+	unlock := newMutexUnlockCmd()
+	unlock.SetArgs([]string{"test-mutex", "--holder", "ci"})
+	output, err = executeCommandWithOutput(t, unlock)
+	require.NoError(t, err, "a dry run must not fail just because the holder does not hold the mutex")
+	assert.Contains(t, output, "Dry run: would unlock mutex")
+}
+
+func TestDryRun_ArriveLeavesBarrierAlone(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "default"},
+		Spec:       syncv1.BarrierSpec{Expected: 2},
+		Status:     syncv1.BarrierStatus{Phase: syncv1.BarrierPhaseWaiting},
+	}
+	setupDryRunTest(t, barrier)
+
+	cmd := newBarrierArriveCmd()
+	cmd.SetArgs([]string{"test-barrier", "ci"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Dry run: would signal arrival at barrier")
+
+	var updated syncv1.Barrier
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "test-barrier", Namespace: "default"}, &updated))
+	assert.Equal(t, int32(0), updated.Status.Arrived)
+
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, k8sClient.List(context.Background(), &arrivals))
+	assert.Empty(t, arrivals.Items)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// exportTypes are the primitive types export can dump, in output order
+var exportTypes = []struct {
+	name string
+	list client.ObjectList
+}{
+	{"semaphore", &syncv1.SemaphoreList{}},
+	{"barrier", &syncv1.BarrierList{}},
+	{"lease", &syncv1.LeaseList{}},
+	{"gate", &syncv1.GateList{}},
+	{"mutex", &syncv1.MutexList{}},
+	{"rwmutex", &syncv1.RWMutexList{}},
+	{"once", &syncv1.OnceList{}},
+	{"waitgroup", &syncv1.WaitGroupList{}},
+	{"event", &syncv1.EventList{}},
+	{"ratelimiter", &syncv1.RateLimiterList{}},
+	{"circuitbreaker", &syncv1.CircuitBreakerList{}},
+}
+
+// exportStrippedMetadata are the metadata fields set by the API server or
+// the controllers rather than declared, so they are left out of an export.
+// The namespace goes too, so an export can be applied to another one.
+var exportStrippedMetadata = []string{
+	"namespace",
+	"uid",
+	"resourceVersion",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"managedFields",
+	"selfLink",
+	"finalizers",
+	"ownerReferences",
+}
+
+func newExportCmd() *cobra.Command {
+	var types []string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Dump primitives as re-appliable YAML",
+		Long:  "Print the primitives in the namespace as a multi-document YAML file that `koncli apply -f` accepts, for backups or promoting them to another environment. Status and the metadata kept by the API server are left out, and so is the namespace. With -o json the objects are printed as a JSON array instead.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			objects, err := exportPrimitives(cmd.Context(), k8sClient, types)
+			if err != nil {
+				return err
+			}
+
+			if strings.ToLower(outputFormat) == outputJSON {
+				return printStructuredList(cmd.OutOrStdout(), objects)
+			}
+			return printExportYAML(cmd.OutOrStdout(), objects)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&types, "type", []string{"all"}, "Primitive types to export, e.g. semaphore,lease, or all")
+
+	return cmd
+}
+
+// exportPrimitives lists the primitives of the given types in the current
+// namespace and returns them stripped down to what apply needs
+func exportPrimitives(ctx context.Context, c client.Client, types []string) ([]map[string]interface{}, error) {
+	selected, err := selectExportTypes(types)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]interface{}
+	for _, t := range exportTypes {
+		if !selected[t.name] {
+			continue
+		}
+
+		list := t.list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list %s objects: %w", t.name, err)
+		}
+
+		err := meta.EachListItem(list, func(item runtime.Object) error {
+			obj, err := cleanForExport(c.Scheme(), item)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, obj)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s objects: %w", t.name, err)
+		}
+	}
+
+	return objects, nil
+}
+
+// cleanForExport converts obj to a plain object carrying its apiVersion and
+// kind, with status and the server-kept metadata removed
+func cleanForExport(scheme *runtime.Scheme, obj runtime.Object) (map[string]interface{}, error) {
+	u, err := toUnstructured(scheme, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(u, "status")
+	if metadata, ok := u["metadata"].(map[string]interface{}); ok {
+		for _, field := range exportStrippedMetadata {
+			delete(metadata, field)
+		}
+	}
+	return u, nil
+}
+
+// toUnstructured converts obj to a plain object carrying its apiVersion and
+// kind, which typed objects read from the API server leave empty
+func toUnstructured(scheme *runtime.Scheme, obj runtime.Object) (map[string]interface{}, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u["apiVersion"] = gvk.GroupVersion().String()
+	u["kind"] = gvk.Kind
+	return u, nil
+}
+
+// printExportYAML writes objects to w as a multi-document YAML stream
+func printExportYAML(w io.Writer, objects []map[string]interface{}) error {
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal output as yaml: %w", err)
+		}
+		if i > 0 {
+			if _, err := fmt.Fprintln(w, "---"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectExportTypes returns the set of types to export, where "all" selects
+// every type
+func selectExportTypes(types []string) (map[string]bool, error) {
+	valid := make([]string, 0, len(exportTypes))
+	for _, t := range exportTypes {
+		valid = append(valid, t.name)
+	}
+
+	selected := map[string]bool{}
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "all" {
+			for _, name := range valid {
+				selected[name] = true
+			}
+			continue
+		}
+		if !slices.Contains(valid, t) {
+			return nil, fmt.Errorf("unknown primitive type %q (valid: all, %s)", t, strings.Join(valid, ", "))
+		}
+		selected[t] = true
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no primitive types to export")
+	}
+	return selected, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 	"github.com/LogicIQ/konductor/sdk/go/gate"
 )
@@ -25,6 +26,8 @@ func newGateCmd() *cobra.Command {
 	cmd.AddCommand(newGateWaitCmd())
 	cmd.AddCommand(newGateListCmd())
 
+	registerNameCompletion(cmd, &syncv1.GateList{})
+
 	return cmd
 }
 
@@ -32,7 +35,7 @@ func createGateClient() (*konductor.Client, error) {
 	if k8sClient == nil {
 		return nil, fmt.Errorf("kubernetes client not initialized")
 	}
-	return konductor.NewFromClient(k8sClient, namespace), nil
+	return konductor.NewFromClient(kubeClient(), namespace), nil
 }
 
 func newGateWaitCmd() *cobra.Command {
@@ -73,6 +76,8 @@ func newGateWaitCmd() *cobra.Command {
 }
 
 func newGateListCmd() *cobra.Command {
+	var filters listFilters
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all gates",
@@ -84,12 +89,21 @@ func newGateListCmd() *cobra.Command {
 				return err
 			}
 
+			opts, err := filters.options()
+			if err != nil {
+				return err
+			}
+
 			// List gates using SDK
-			gates, err := gate.List(client, ctx)
+			gates, err := gate.List(client, ctx, opts...)
 			if err != nil {
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), gates)
+			}
+
 			if len(gates) == 0 {
 				logger.Info("No gates found")
 				return nil
@@ -111,6 +125,7 @@ func newGateListCmd() *cobra.Command {
 
 				logger.Info("Gate",
 					zap.String("name", g.Name),
+					zap.String("namespace", g.Namespace),
 					zap.Int("conditions_met", metCount),
 					zap.Int("conditions_total", conditionCount),
 					zap.String("phase", string(g.Status.Phase)),
@@ -132,6 +147,8 @@ func newGateListCmd() *cobra.Command {
 		},
 	}
 
+	addListFlags(cmd, &filters)
+
 	return cmd
 }
 
@@ -153,7 +170,7 @@ func newGateCreateCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Created gate", zap.String("gate", gateName))
+			logger.Info(dryRunMsg("Created gate"), zap.String("gate", gateName))
 			return nil
 		},
 	}
@@ -162,6 +179,9 @@ func newGateCreateCmd() *cobra.Command {
 }
 
 func newGateDeleteCmd() *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "delete <gate-name>",
 		Short: "Delete a gate",
@@ -179,11 +199,18 @@ func newGateDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Deleted gate", zap.String("gate", gateName))
+			if err := waitForDeletion(ctx, client, &syncv1.Gate{}, gateName, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted gate"), zap.String("gate", gateName))
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the gate is gone")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultDeleteWaitTimeout, "How long --wait blocks before giving up")
+
 	return cmd
 }
 
@@ -201,6 +228,10 @@ func newGateOpenCmd() *cobra.Command {
 				return err
 			}
 
+			if skipForDryRun("open gate", zap.String("gate", gateName)) {
+				return nil
+			}
+
 			if err := gate.Open(client, ctx, gateName); err != nil {
 				return err
 			}
@@ -227,6 +258,10 @@ func newGateCloseCmd() *cobra.Command {
 				return err
 			}
 
+			if skipForDryRun("close gate", zap.String("gate", gateName)) {
+				return nil
+			}
+
 			if err := gate.Close(client, ctx, gateName); err != nil {
 				return err
 			}
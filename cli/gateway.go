@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/LogicIQ/konductor/sdk/go/barrier"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/gate"
+	"github.com/LogicIQ/konductor/sdk/go/lease"
+	"github.com/LogicIQ/konductor/sdk/go/mutex"
+	"github.com/LogicIQ/konductor/sdk/go/semaphore"
+)
+
+const (
+	// gatewayShutdownTimeout bounds how long in-flight requests may run after
+	// the gateway is interrupted
+	gatewayShutdownTimeout = 10 * time.Second
+	// gatewayReadHeaderTimeout guards against clients that never finish
+	// sending request headers
+	gatewayReadHeaderTimeout = 10 * time.Second
+)
+
+func newGatewayCmd() *cobra.Command {
+	var (
+		bindAddress string
+		namespaces  []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gateway",
+		Short: "Serve coordination primitives over HTTP",
+		Long:  "Run an HTTP REST gateway so services without a Kubernetes client can acquire, release and inspect coordination primitives",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return runGateway(ctx, bindAddress, newGatewayHandler(konductor.NewFromClient(k8sClient, namespace), namespaces))
+		},
+	}
+
+	cmd.Flags().StringVar(&bindAddress, "bind-address", "127.0.0.1:8080", "Address the gateway listens on. It does not authenticate callers, so only bind beyond localhost on a trusted network")
+	cmd.Flags().StringSliceVar(&namespaces, "namespaces", nil, "Namespaces requests may name with ?namespace=, others are refused with 403 (default: the CLI's namespace only)")
+
+	return cmd
+}
+
+// runGateway serves handler on addr until ctx is cancelled, then waits for
+// in-flight requests to finish
+func runGateway(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: gatewayReadHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	logger.Info("Serving gateway", zap.String("address", addr), zap.String("namespace", namespace))
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("gateway failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), gatewayShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down gateway: %w", err)
+	}
+	logger.Info("Gateway stopped")
+	return nil
+}
+
+// gatewayRequest is the body accepted by the acquire, release and arrive
+// endpoints. Durations use Go syntax, e.g. "30s" or "5m".
+type gatewayRequest struct {
+	Holder   string `json:"holder"`
+	TTL      string `json:"ttl,omitempty"`
+	Timeout  string `json:"timeout,omitempty"`
+	Priority int32  `json:"priority,omitempty"`
+}
+
+// gatewayAcquired is returned once a primitive has been acquired
+type gatewayAcquired struct {
+	Name       string `json:"name"`
+	Holder     string `json:"holder"`
+	FenceToken int64  `json:"fenceToken,omitempty"`
+}
+
+type gatewayError struct {
+	Error string `json:"error"`
+}
+
+type gateway struct {
+	client     *konductor.Client
+	namespaces map[string]bool
+}
+
+// newGatewayHandler routes the REST API onto the SDK calls koncli makes. Each
+// request may name a namespace with ?namespace=, otherwise client's is used.
+// Namespaces other than those listed are refused, and an empty list allows
+// only client's namespace, since callers are not authenticated and would
+// otherwise act wherever koncli's credentials reach.
+func newGatewayHandler(client *konductor.Client, namespaces []string) http.Handler {
+	g := &gateway{client: client, namespaces: map[string]bool{}}
+	for _, ns := range namespaces {
+		g.namespaces[ns] = true
+	}
+	if len(g.namespaces) == 0 {
+		g.namespaces[client.Namespace()] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/semaphores/{name}", g.getSemaphore)
+	mux.HandleFunc("POST /v1/semaphores/{name}/acquire", g.acquireSemaphore)
+	mux.HandleFunc("POST /v1/semaphores/{name}/release", g.releaseSemaphore)
+
+	mux.HandleFunc("GET /v1/leases/{name}", g.getLease)
+	mux.HandleFunc("POST /v1/leases/{name}/acquire", g.acquireLease)
+	mux.HandleFunc("POST /v1/leases/{name}/release", g.releaseLease)
+
+	mux.HandleFunc("GET /v1/mutexes/{name}", g.getMutex)
+	mux.HandleFunc("POST /v1/mutexes/{name}/acquire", g.acquireMutex)
+	mux.HandleFunc("POST /v1/mutexes/{name}/release", g.releaseMutex)
+
+	mux.HandleFunc("GET /v1/barriers/{name}", g.getBarrier)
+	mux.HandleFunc("POST /v1/barriers/{name}/arrive", g.arriveBarrier)
+
+	mux.HandleFunc("GET /v1/gates/{name}", g.getGate)
+
+	root := http.NewServeMux()
+	root.HandleFunc("GET /healthz", versionHealthCheck)
+	root.Handle("/v1/", g.servedNamespaces(mux))
+	return root
+}
+
+// servedNamespaces refuses requests for a namespace the gateway was not
+// allowed to serve, including the CLI's own when it is not listed, before
+// they reach next
+func (g *gateway) servedNamespaces(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ns := r.URL.Query().Get("namespace")
+		if ns == "" {
+			ns = g.client.Namespace()
+		}
+		if !g.namespaces[ns] {
+			writeJSON(w, http.StatusForbidden, gatewayError{Error: fmt.Sprintf("namespace %s is not served by this gateway", ns)})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// versionHealthCheck reports the gateway as healthy, with the build version in
+// the same X-Konductor-Version header `koncli operator` reads
+func versionHealthCheck(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Konductor-Version", version)
+	writeJSON(w, http.StatusOK, struct {
+		Status  string `json:"status"`
+		Version string `json:"version"`
+		Commit  string `json:"commit"`
+	}{Status: "ok", Version: version, Commit: commit})
+}
+
+func (g *gateway) clientFor(r *http.Request) *konductor.Client {
+	if ns := r.URL.Query().Get("namespace"); ns != "" {
+		return g.client.WithNamespace(ns)
+	}
+	return g.client
+}
+
+// decodeRequest reads the body of an acquire, release or arrive request and
+// turns it into SDK options. The holder is always required, since the gateway
+// cannot derive one from the caller's environment.
+func decodeRequest(r *http.Request) (gatewayRequest, []konductor.Option, error) {
+	var req gatewayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, nil, fmt.Errorf("invalid request body: %w", err)
+	}
+	if req.Holder == "" {
+		return req, nil, errors.New("holder is required")
+	}
+
+	opts := []konductor.Option{konductor.WithHolder(req.Holder)}
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			return req, nil, fmt.Errorf("invalid ttl: %w", err)
+		}
+		opts = append(opts, konductor.WithTTL(ttl))
+	}
+	if req.Timeout != "" {
+		timeout, err := time.ParseDuration(req.Timeout)
+		if err != nil {
+			return req, nil, fmt.Errorf("invalid timeout: %w", err)
+		}
+		opts = append(opts, konductor.WithTimeout(timeout))
+	}
+	if req.Priority > 0 {
+		opts = append(opts, konductor.WithPriority(req.Priority))
+	}
+	return req, opts, nil
+}
+
+func (g *gateway) getSemaphore(w http.ResponseWriter, r *http.Request) {
+	sem, err := semaphore.Get(g.clientFor(r), r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summary, _, _ := summarizeSemaphore(sem)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (g *gateway) acquireSemaphore(w http.ResponseWriter, r *http.Request) {
+	_, opts, err := decodeRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, gatewayError{Error: err.Error()})
+		return
+	}
+
+	permit, err := semaphore.Acquire(g.clientFor(r), r.Context(), r.PathValue("name"), opts...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, gatewayAcquired{Name: permit.Name(), Holder: permit.Holder()})
+}
+
+func (g *gateway) releaseSemaphore(w http.ResponseWriter, r *http.Request) {
+	req, _, err := decodeRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, gatewayError{Error: err.Error()})
+		return
+	}
+
+	if err := releaseSemaphorePermit(r.Context(), g.clientFor(r), r.PathValue("name"), req.Holder); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *gateway) getLease(w http.ResponseWriter, r *http.Request) {
+	l, err := lease.Get(g.clientFor(r), r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summary, _, _ := summarizeLease(l)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (g *gateway) acquireLease(w http.ResponseWriter, r *http.Request) {
+	_, opts, err := decodeRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, gatewayError{Error: err.Error()})
+		return
+	}
+
+	l, err := lease.Acquire(g.clientFor(r), r.Context(), r.PathValue("name"), opts...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, gatewayAcquired{Name: l.Name(), Holder: l.Holder(), FenceToken: l.FenceToken()})
+}
+
+func (g *gateway) releaseLease(w http.ResponseWriter, r *http.Request) {
+	req, _, err := decodeRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, gatewayError{Error: err.Error()})
+		return
+	}
+
+	if err := g.clientFor(r).ReleaseLease(r.Context(), r.PathValue("name"), req.Holder); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *gateway) getMutex(w http.ResponseWriter, r *http.Request) {
+	m, err := mutex.Get(g.clientFor(r), r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summary, _, _ := summarizeMutex(m)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (g *gateway) acquireMutex(w http.ResponseWriter, r *http.Request) {
+	_, opts, err := decodeRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, gatewayError{Error: err.Error()})
+		return
+	}
+
+	m, err := mutex.Lock(g.clientFor(r), r.Context(), r.PathValue("name"), opts...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, gatewayAcquired{Name: m.Name(), Holder: m.Holder(), FenceToken: m.FenceToken()})
+}
+
+func (g *gateway) releaseMutex(w http.ResponseWriter, r *http.Request) {
+	req, _, err := decodeRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, gatewayError{Error: err.Error()})
+		return
+	}
+
+	if err := mutex.Unlock(g.clientFor(r), r.Context(), r.PathValue("name"), req.Holder); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *gateway) getBarrier(w http.ResponseWriter, r *http.Request) {
+	b, err := barrier.Get(g.clientFor(r), r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summary, _, _ := summarizeBarrier(b)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (g *gateway) arriveBarrier(w http.ResponseWriter, r *http.Request) {
+	_, opts, err := decodeRequest(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, gatewayError{Error: err.Error()})
+		return
+	}
+
+	if err := barrier.Arrive(g.clientFor(r), r.Context(), r.PathValue("name"), opts...); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (g *gateway) getGate(w http.ResponseWriter, r *http.Request) {
+	gt, err := gate.Get(g.clientFor(r), r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	summary, _, _ := summarizeGate(gt)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// gatewayStatus maps an SDK error onto the HTTP status callers can act on
+func gatewayStatus(err error) int {
+	switch {
+	case errors.Is(err, konductor.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusRequestTimeout
+	case errors.Is(err, konductor.ErrDenied):
+		return http.StatusForbidden
+	case errors.Is(err, konductor.ErrNotHolder), errors.Is(err, konductor.ErrExpired),
+		errors.Is(err, konductor.ErrLocked), errors.Is(err, konductor.ErrNoPermits):
+		return http.StatusConflict
+	case errors.Is(err, konductor.ErrDraining):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, errNoPermit), apierrors.IsNotFound(err):
+		return http.StatusNotFound
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, gatewayStatus(err), gatewayError{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Debug("Failed to write gateway response", zap.Error(err))
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+// setupGatewayServer serves the gateway over httptest, backed by a fake client
+// holding objects
+func setupGatewayServer(t *testing.T, funcs interceptor.Funcs, objects ...runtime.Object) (*httptest.Server, client.Client) {
+	return setupGatewayServerFor(t, nil, funcs, objects...)
+}
+
+// setupGatewayServerFor is setupGatewayServer allowing requests to name the
+// given namespaces
+func setupGatewayServerFor(t *testing.T, namespaces []string, funcs interceptor.Funcs, objects ...runtime.Object) (*httptest.Server, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithInterceptorFuncs(funcs).
+		Build()
+	logger = initTestLogger(t)
+
+	srv := httptest.NewServer(newGatewayHandler(konductor.NewFromClient(fakeClient, "default"), namespaces))
+	t.Cleanup(srv.Close)
+	return srv, fakeClient
+}
+
+func newGatewaySemaphore(available int32) *syncv1.Semaphore {
+	return &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 1},
+		Status: syncv1.SemaphoreStatus{
+			Available: available,
+			InUse:     1 - available,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+}
+
+func post(t *testing.T, srv *httptest.Server, path, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestGatewayCmd_BindsLocalhostByDefault(t *testing.T) {
+	flag := newGatewayCmd().Flags().Lookup("bind-address")
+	require.NotNil(t, flag)
+	assert.Equal(t, "127.0.0.1:8080", flag.DefValue)
+}
+
+func TestGateway_RefusesNamespacesNotServed(t *testing.T) {
+	staging := newGatewaySemaphore(1)
+	staging.Namespace = "staging"
+	srv, _ := setupGatewayServer(t, interceptor.Funcs{}, newGatewaySemaphore(1), staging)
+
+	resp, err := http.Get(srv.URL + "/v1/semaphores/test-sem")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(srv.URL + "/v1/semaphores/test-sem?namespace=default")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	refused := post(t, srv, "/v1/semaphores/test-sem/acquire?namespace=staging", `{"holder": "worker-1"}`)
+	assert.Equal(t, http.StatusForbidden, refused.StatusCode)
+	var body gatewayError
+	require.NoError(t, json.NewDecoder(refused.Body).Decode(&body))
+	assert.Contains(t, body.Error, "namespace staging is not served")
+
+	// Listed namespaces are served, and the CLI's is then no longer implied
+	srv, _ = setupGatewayServerFor(t, []string{"staging"}, interceptor.Funcs{}, newGatewaySemaphore(1), staging)
+	resp, err = http.Get(srv.URL + "/v1/semaphores/test-sem?namespace=staging")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for _, path := range []string{"/v1/semaphores/test-sem?namespace=default", "/v1/semaphores/test-sem"} {
+		resp, err = http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, path)
+	}
+
+	// Health checks name no namespace and are always served
+	resp, err = http.Get(srv.URL + "/healthz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestGateway_Healthz(t *testing.T) {
+	srv, _ := setupGatewayServer(t, interceptor.Funcs{})
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, version, resp.Header.Get("X-Konductor-Version"))
+}
+
+func TestGateway_AcquireSemaphore(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		expected int
+	}{
+		{
+			name:     "acquired",
+			path:     "/v1/semaphores/test-sem/acquire",
+			body:     `{"holder": "worker-1"}`,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "timed out waiting for a permit",
+			path:     "/v1/semaphores/full-sem/acquire",
+			body:     `{"holder": "worker-1", "timeout": "200ms"}`,
+			expected: http.StatusRequestTimeout,
+		},
+		{
+			name:     "missing holder",
+			path:     "/v1/semaphores/test-sem/acquire",
+			body:     `{}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid timeout",
+			path:     "/v1/semaphores/test-sem/acquire",
+			body:     `{"holder": "worker-1", "timeout": "soon"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "missing semaphore",
+			path:     "/v1/semaphores/missing/acquire",
+			body:     `{"holder": "worker-1"}`,
+			expected: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full := newGatewaySemaphore(0)
+			full.Name = "full-sem"
+			srv, _ := setupGatewayServer(t, grantPermits, newGatewaySemaphore(1), full)
+
+			resp := post(t, srv, tt.path, tt.body)
+			assert.Equal(t, tt.expected, resp.StatusCode)
+		})
+	}
+}
+
+func TestGateway_SemaphoreRelease(t *testing.T) {
+	srv, fakeClient := setupGatewayServer(t, grantPermits, newGatewaySemaphore(1))
+
+	resp := post(t, srv, "/v1/semaphores/test-sem/acquire", `{"holder": "worker-1"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var acquired gatewayAcquired
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&acquired))
+	assert.Equal(t, "worker-1", acquired.Holder)
+
+	resp = post(t, srv, "/v1/semaphores/test-sem/release", `{"holder": "worker-2"}`)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = post(t, srv, "/v1/semaphores/test-sem/release", `{"holder": "worker-1"}`)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	var permits syncv1.PermitList
+	require.NoError(t, fakeClient.List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestGateway_AcquireLeaseDenied(t *testing.T) {
+	l := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-lease", Namespace: "default"},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: time.Minute}},
+	}
+	// Stand in for the controller by denying every lease request as it is created
+	deny := interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if req, ok := obj.(*syncv1.LeaseRequest); ok {
+				req.Status.Phase = syncv1.LeaseRequestPhaseDenied
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}
+	srv, _ := setupGatewayServer(t, deny, l)
+
+	resp := post(t, srv, "/v1/leases/test-lease/acquire", `{"holder": "worker-1", "timeout": "5s"}`)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	var body gatewayError
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Contains(t, body.Error, "denied")
+}
+
+func TestGateway_MutexReleaseByOtherHolderConflicts(t *testing.T) {
+	m := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mutex", Namespace: "default"},
+		Status: syncv1.MutexStatus{
+			Phase:  syncv1.MutexPhaseLocked,
+			Holder: "worker-1",
+		},
+	}
+	srv, _ := setupGatewayServer(t, interceptor.Funcs{}, m)
+
+	resp := post(t, srv, "/v1/mutexes/test-mutex/release", `{"holder": "worker-2"}`)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestGateway_GetPrimitives(t *testing.T) {
+	b := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "default"},
+		Spec:       syncv1.BarrierSpec{Expected: 3},
+		Status:     syncv1.BarrierStatus{Arrived: 2, Phase: syncv1.BarrierPhaseWaiting},
+	}
+	g := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+		Spec: syncv1.GateSpec{
+			Conditions: []syncv1.GateCondition{{Type: "Job", Name: "setup", State: "Complete"}},
+		},
+		Status: syncv1.GateStatus{
+			Phase:             syncv1.GatePhaseOpen,
+			ConditionStatuses: []syncv1.GateConditionStatus{{Type: "Job", Name: "setup", Met: true}},
+		},
+	}
+	srv, _ := setupGatewayServer(t, interceptor.Funcs{}, b, g)
+
+	resp, err := http.Get(srv.URL + "/v1/barriers/test-barrier")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var barrierBody barrierSummary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&barrierBody))
+	assert.Equal(t, int32(2), barrierBody.Arrived)
+	assert.Equal(t, int32(3), barrierBody.Expected)
+
+	resp, err = http.Get(srv.URL + "/v1/gates/test-gate")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var gateBody gateSummary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&gateBody))
+	assert.Equal(t, "Open", gateBody.Phase)
+	assert.Equal(t, 1, gateBody.ConditionsMet)
+
+	resp, err = http.Get(srv.URL + "/v1/gates/missing")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
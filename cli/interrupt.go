@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+// interruptContext returns a context that is cancelled when the command
+// receives SIGINT or SIGTERM, so blocking commands can clean up before exiting
+func interruptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// releaseIfInterrupted releases what a blocking command acquired if ctx was
+// cancelled by the time it got it, and returns an error so the command exits
+// non-zero. ctx is already done by then, so release runs with a detached
+// context. It returns nil if ctx is still live.
+func releaseIfInterrupted(ctx context.Context, what, name string, release func(context.Context) error) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+
+	cleanupCtx, cancel := konductor.CleanupContext(ctx)
+	defer cancel()
+	if err := release(cleanupCtx); err != nil {
+		return fmt.Errorf("interrupted after acquiring %s %s and failed to release it: %w (cause: %v)", what, name, err, ctx.Err())
+	}
+
+	logger.Info("Interrupted, released "+what, zap.String(what, name))
+	return fmt.Errorf("interrupted after acquiring %s %s, released it: %w", what, name, ctx.Err())
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/semaphore"
+)
+
+func TestReleaseIfInterrupted_NotInterrupted(t *testing.T) {
+	logger = initTestLogger(t)
+
+	called := false
+	err := releaseIfInterrupted(context.Background(), "semaphore", "test-sem", func(context.Context) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, called, "nothing is released while the command is still running")
+}
+
+func TestReleaseIfInterrupted_DeletesPermit(t *testing.T) {
+	logger = initTestLogger(t)
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	sem := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 1},
+		Status:     syncv1.SemaphoreStatus{Available: 1, Phase: syncv1.SemaphorePhaseReady},
+	}
+	k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(sem).WithInterceptorFuncs(grantPermits).Build()
+	namespace = "default"
+
+	permit, err := semaphore.TryAcquire(createSemaphoreClient(), context.Background(), "test-sem", konductor.WithHolder("test-holder"))
+	require.NoError(t, err)
+
+	// Simulate SIGINT arriving while the permit is held
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = releaseIfInterrupted(ctx, "semaphore", "test-sem", permit.Release)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Contains(t, err.Error(), "released it")
+
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestReleaseIfInterrupted_ReleaseFails(t *testing.T) {
+	logger = initTestLogger(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := releaseIfInterrupted(ctx, "lease", "test-lease", func(releaseCtx context.Context) error {
+		// The release gets a live context even though ctx is done
+		require.NoError(t, releaseCtx.Err())
+		return errors.New("forbidden")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "interrupted after acquiring lease test-lease and failed to release it: forbidden")
+	assert.Contains(t, err.Error(), "context canceled")
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math/rand/v2"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 	"github.com/LogicIQ/konductor/sdk/go/lease"
 )
@@ -22,30 +27,68 @@ func newLeaseCmd() *cobra.Command {
 	cmd.AddCommand(newLeaseCreateCmd())
 	cmd.AddCommand(newLeaseDeleteCmd())
 	cmd.AddCommand(newLeaseAcquireCmd())
+	cmd.AddCommand(newLeaseRenewCmd())
 	cmd.AddCommand(newLeaseReleaseCmd())
+	cmd.AddCommand(newLeaseTransferCmd())
 	cmd.AddCommand(newLeaseListCmd())
+	cmd.AddCommand(newLeaseOwnerCmd())
+
+	registerNameCompletion(cmd, &syncv1.LeaseList{})
 
 	return cmd
 }
 
 func createLeaseClient() *konductor.Client {
-	return konductor.NewFromClient(k8sClient, namespace)
+	return konductor.NewFromClient(kubeClient(), namespace)
 }
 
+// validateHolder returns holder, falling back to --holder-file and then to
+// detectHolder when it is empty
 func validateHolder(holder string) (string, error) {
+	holder, err := holderOrFile(holder)
+	if err != nil {
+		return "", err
+	}
 	if holder == "" {
-		if holder = os.Getenv("HOSTNAME"); holder == "" {
-			return "", errors.New("holder must be specified or HOSTNAME must be set")
-		}
+		return detectHolder()
+	}
+	return holder, nil
+}
+
+// holderOrFile returns holder, or the holder read from --holder-file when
+// holder is empty. It returns an empty holder when neither is set.
+func holderOrFile(holder string) (string, error) {
+	if holder != "" || holderFile == "" {
+		return holder, nil
+	}
+	data, err := os.ReadFile(holderFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read holder file: %w", err)
+	}
+	if holder = strings.TrimSpace(string(data)); holder == "" {
+		return "", fmt.Errorf("holder file %s is empty", holderFile)
 	}
 	return holder, nil
 }
 
+const (
+	// leaseWaitDefaultTimeout bounds lease acquire when neither --timeout nor
+	// --max-attempts is given
+	leaseWaitDefaultTimeout = 30 * time.Second
+	// leaseWaitMax caps the window given to each lease acquire attempt
+	leaseWaitMax = time.Minute
+	// leaseWaitJitter moves each window by up to this fraction either way, so
+	// holders that started together do not retry in step
+	leaseWaitJitter = 0.1
+)
+
 func newLeaseAcquireCmd() *cobra.Command {
 	var (
-		timeout  time.Duration
-		priority int32
-		holder   string
+		timeout      time.Duration
+		priority     int32
+		holder       string
+		pollInterval time.Duration
+		maxAttempts  int
 	)
 
 	cmd := &cobra.Command{
@@ -54,7 +97,8 @@ func newLeaseAcquireCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			leaseName := args[0]
-			ctx := cmd.Context()
+			ctx, stop := interruptContext(cmd.Context())
+			defer stop()
 
 			var err error
 			holder, err = validateHolder(holder)
@@ -71,24 +115,144 @@ func newLeaseAcquireCmd() *cobra.Command {
 			if priority > 0 {
 				opts = append(opts, konductor.WithPriority(priority))
 			}
-			if timeout > 0 {
-				opts = append(opts, konductor.WithTimeout(timeout))
+			if pollInterval <= 0 {
+				return errors.New("--poll-interval must be positive")
+			}
+			if maxAttempts < 0 {
+				return errors.New("--max-attempts must not be negative")
+			}
+			if timeout == 0 && maxAttempts == 0 {
+				timeout = leaseWaitDefaultTimeout
 			}
 
-			// Acquire lease using SDK
-			leaseObj, err := lease.Acquire(client, ctx, leaseName, opts...)
+			if skipForDryRun("acquire lease", zap.String("lease", leaseName), zap.String("holder", holder)) {
+				return nil
+			}
+
+			leaseObj, err := acquireLeaseWithRetry(ctx, client, leaseName, timeout, pollInterval, maxAttempts, opts)
 			if err != nil {
 				return err
 			}
+			if err := releaseIfInterrupted(ctx, "lease", leaseName, leaseObj.Release); err != nil {
+				return err
+			}
 
 			logger.Info("Acquired lease", zap.String("lease", leaseName), zap.String("holder", leaseObj.Holder()))
 			return nil
 		},
 	}
 
-	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for waiting (e.g., 30s, 5m)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for waiting (e.g., 30s, 5m); defaults to 30s unless --max-attempts is set")
 	cmd.Flags().Int32Var(&priority, "priority", 0, "Priority for lease acquisition (higher wins)")
-	cmd.Flags().StringVar(&holder, "holder", "", "Lease holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Lease holder identifier (defaults to the detected holder)")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 5*time.Second, "How long the first attempt waits for a grant; later attempts wait twice as long, up to 1m")
+	cmd.Flags().IntVar(&maxAttempts, "max-attempts", 0, "Give up after this many attempts (0 for no limit)")
+
+	return cmd
+}
+
+// acquireLeaseWithRetry requests the lease until it is granted, giving each
+// attempt a window that starts at pollInterval and doubles with jitter up to
+// leaseWaitMax. Each attempt that is not granted in time withdraws its request
+// before the next one is made. It gives up when timeout elapses, after
+// maxAttempts attempts if that is positive, or when ctx is cancelled. A denied
+// request is not retried.
+func acquireLeaseWithRetry(ctx context.Context, client *konductor.Client, name string, timeout, pollInterval time.Duration, maxAttempts int, opts []konductor.Option) (*lease.Lease, error) {
+	var deadline time.Time
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		deadline, _ = ctx.Deadline()
+	}
+
+	start := time.Now()
+	window := pollInterval
+	for attempt := 1; ; attempt++ {
+		attemptWindow := time.Duration(float64(window) * (1 + leaseWaitJitter*(2*rand.Float64()-1)))
+		attemptOpts := append(append([]konductor.Option{}, opts...), konductor.WithTimeout(attemptWindow))
+		leaseObj, err := lease.Acquire(client, ctx, name, attemptOpts...)
+		if err == nil {
+			return leaseObj, nil
+		}
+
+		switch {
+		case timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, fmt.Errorf("timed out after %s waiting for lease %s: %w", timeout, name, konductor.ErrTimeout)
+		case ctx.Err() != nil:
+			return nil, fmt.Errorf("stopped waiting for lease %s: %w", name, ctx.Err())
+		case !errors.Is(err, konductor.ErrTimeout):
+			return nil, err
+		case maxAttempts > 0 && attempt >= maxAttempts:
+			return nil, fmt.Errorf("lease %s not granted after %d attempts: %w", name, attempt, konductor.ErrTimeout)
+		}
+
+		fields := []zap.Field{
+			zap.String("lease", name),
+			zap.Int("attempt", attempt),
+			zap.Duration("elapsed", time.Since(start).Round(time.Second)),
+		}
+		if !deadline.IsZero() {
+			fields = append(fields, zap.Duration("remaining", time.Until(deadline).Round(time.Second)))
+		}
+		if maxAttempts > 0 {
+			fields = append(fields, zap.Int("attemptsLeft", maxAttempts-attempt))
+		}
+		logger.Info("Waiting for lease", fields...)
+
+		window *= 2
+		if window > leaseWaitMax {
+			window = leaseWaitMax
+		}
+	}
+}
+
+func newLeaseRenewCmd() *cobra.Command {
+	var holder string
+
+	cmd := &cobra.Command{
+		Use:   "renew <lease-name>",
+		Short: "Renew a held lease",
+		Long:  "Push the expiry of a lease forward by its TTL. Fails if the lease is held by someone else or has already expired.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			leaseName := args[0]
+			ctx := cmd.Context()
+
+			var err error
+			holder, err = validateHolder(holder)
+			if err != nil {
+				return err
+			}
+
+			client := createLeaseClient()
+
+			if skipForDryRun("renew lease", zap.String("lease", leaseName), zap.String("holder", holder)) {
+				return nil
+			}
+
+			// Renew lease using SDK
+			renewed, err := lease.Renew(client, ctx, leaseName, konductor.WithHolder(holder))
+			if err != nil {
+				return err
+			}
+
+			expires := "N/A"
+			if renewed.Status.ExpiresAt != nil {
+				expires = renewed.Status.ExpiresAt.Format(time.RFC3339)
+			}
+
+			logger.Info("Renewed lease",
+				zap.String("lease", leaseName),
+				zap.String("holder", holder),
+				zap.String("expires", expires),
+				zap.Int32("renewals", renewed.Status.RenewCount),
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&holder, "holder", "", "Lease holder identifier (defaults to the detected holder)")
 
 	return cmd
 }
@@ -112,6 +276,10 @@ func newLeaseReleaseCmd() *cobra.Command {
 
 			client := createLeaseClient()
 
+			if skipForDryRun("release lease", zap.String("lease", leaseName), zap.String("holder", holder)) {
+				return nil
+			}
+
 			// Release lease using SDK
 			if err := client.ReleaseLease(ctx, leaseName, holder); err != nil {
 				return err
@@ -122,12 +290,69 @@ func newLeaseReleaseCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&holder, "holder", "", "Lease holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Lease holder identifier (defaults to the detected holder)")
+
+	return cmd
+}
+
+func newLeaseTransferCmd() *cobra.Command {
+	var from, to string
+
+	cmd := &cobra.Command{
+		Use:   "transfer <lease-name>",
+		Short: "Hand a held lease to another holder",
+		Long:  "Transfer a lease from its current holder to a successor without releasing it, so no other waiting holder can take it in between. Fails if --from does not hold the lease or it has already expired.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			leaseName := args[0]
+			ctx := cmd.Context()
+
+			if to == "" {
+				return errors.New("--to must be specified")
+			}
+
+			var err error
+			from, err = validateHolder(from)
+			if err != nil {
+				return err
+			}
+
+			client := createLeaseClient()
+
+			if skipForDryRun("transfer lease", zap.String("lease", leaseName), zap.String("from", from), zap.String("to", to)) {
+				return nil
+			}
+
+			transferred, err := lease.Transfer(client, ctx, leaseName, from, to)
+			if err != nil {
+				return err
+			}
+
+			expires := "N/A"
+			if transferred.Status.ExpiresAt != nil {
+				expires = transferred.Status.ExpiresAt.Format(time.RFC3339)
+			}
+
+			logger.Info("Transferred lease",
+				zap.String("lease", leaseName),
+				zap.String("from", from),
+				zap.String("to", to),
+				zap.String("expires", expires),
+				zap.Int64("fence_token", transferred.Status.FenceToken),
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Current lease holder (defaults to the detected holder)")
+	cmd.Flags().StringVar(&to, "to", "", "Holder to hand the lease to")
 
 	return cmd
 }
 
 func newLeaseListCmd() *cobra.Command {
+	var filters listFilters
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all leases",
@@ -136,12 +361,21 @@ func newLeaseListCmd() *cobra.Command {
 
 			client := createLeaseClient()
 
+			opts, err := filters.options()
+			if err != nil {
+				return err
+			}
+
 			// List leases using SDK
-			leases, err := lease.List(client, ctx)
+			leases, err := lease.List(client, ctx, opts...)
 			if err != nil {
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), leases)
+			}
+
 			if len(leases) == 0 {
 				logger.Info("No leases found")
 				return nil
@@ -160,6 +394,7 @@ func newLeaseListCmd() *cobra.Command {
 
 				logger.Info("Lease",
 					zap.String("name", l.Name),
+					zap.String("namespace", l.Namespace),
 					zap.String("holder", holder),
 					zap.String("phase", string(l.Status.Phase)),
 					zap.String("acquired", acquired),
@@ -171,6 +406,52 @@ func newLeaseListCmd() *cobra.Command {
 		},
 	}
 
+	addListFlags(cmd, &filters)
+
+	return cmd
+}
+
+func newLeaseOwnerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owner <lease-name>",
+		Short: "Show who holds a lease",
+		Long:  "Show the current holder of a lease and since when they have held it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			leaseName := args[0]
+			ctx := cmd.Context()
+
+			client := createLeaseClient()
+
+			holder, since, err := lease.CurrentHolder(client, ctx, leaseName)
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput() {
+				return printStructured(cmd.OutOrStdout(), ownerStatus{Name: leaseName, Holder: holder, Since: formatSince(since)})
+			}
+
+			if holder == "" {
+				logger.Info("Lease is available", zap.String("lease", leaseName))
+				return nil
+			}
+
+			fields := []zap.Field{
+				zap.String("lease", leaseName),
+				zap.String("holder", holder),
+			}
+			if !since.IsZero() {
+				fields = append(fields,
+					zap.String("since", formatSince(since)),
+					zap.Duration("held", time.Since(since).Truncate(time.Second)),
+				)
+			}
+			logger.Info("Lease owner", fields...)
+			return nil
+		},
+	}
+
 	return cmd
 }
 
@@ -195,7 +476,7 @@ func newLeaseCreateCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Created lease", zap.String("lease", leaseName))
+			logger.Info(dryRunMsg("Created lease"), zap.String("lease", leaseName))
 			return nil
 		},
 	}
@@ -206,6 +487,9 @@ func newLeaseCreateCmd() *cobra.Command {
 }
 
 func newLeaseDeleteCmd() *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "delete <lease-name>",
 		Short: "Delete a lease",
@@ -220,10 +504,17 @@ func newLeaseDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Deleted lease", zap.String("lease", leaseName))
+			if err := waitForDeletion(ctx, client, &syncv1.Lease{}, leaseName, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted lease"), zap.String("lease", leaseName))
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the lease and its requests are gone")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultDeleteWaitTimeout, "How long --wait blocks before giving up")
+
 	return cmd
 }
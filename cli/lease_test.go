@@ -2,7 +2,10 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,9 +13,12 @@ import (
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
 func TestLeaseAcquireCmd(t *testing.T) {
@@ -100,6 +106,167 @@ func TestLeaseAcquireCmd_NotAvailable(t *testing.T) {
 	assert.Contains(t, err.Error(), "lease 'test-lease' is not available")
 }
 
+func TestLeaseAcquireCmd_InterruptDeletesLeaseRequest(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-lease", Namespace: "default"},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: time.Hour}},
+		Status:     syncv1.LeaseStatus{Phase: syncv1.LeasePhaseHeld, Holder: "other-holder"},
+	}
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease).
+		WithStatusSubresource(&syncv1.LeaseRequest{}).
+		Build()
+	namespace = "default"
+
+	// No controller grants the request, so the command is still waiting when
+	// it is interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+
+	cmd := newLeaseAcquireCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder"})
+	cmd.SetContext(ctx)
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, k8sClient.List(context.Background(), &requests))
+	assert.Empty(t, requests.Items)
+}
+
+// setupGrantAfterClient points k8sClient at a fake holding an available
+// lease, whose lease requests are granted from the grantOn-th one created
+// onwards, as a controller busy with other holders would. A zero grantOn never
+// grants.
+func setupGrantAfterClient(t *testing.T, grantOn int) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-lease", Namespace: "default"},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: time.Hour}},
+		Status:     syncv1.LeaseStatus{Phase: syncv1.LeasePhaseAvailable},
+	}
+
+	created := 0
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if request, ok := obj.(*syncv1.LeaseRequest); ok {
+					created++
+					if grantOn > 0 && created >= grantOn {
+						request.Status.Phase = syncv1.LeaseRequestPhaseGranted
+					}
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	namespace = "default"
+}
+
+// leaseRequests returns the lease requests left in k8sClient
+func leaseRequests(t *testing.T) []syncv1.LeaseRequest {
+	t.Helper()
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, k8sClient.List(context.Background(), &requests))
+	return requests.Items
+}
+
+func TestLeaseAcquireCmd_RetriesUntilGranted(t *testing.T) {
+	setupGrantAfterClient(t, 3)
+
+	cmd := newLeaseAcquireCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder", "--poll-interval", "50ms", "--timeout", "30s"})
+
+	output, err := executeCommandWithOutputAndLogs(t, cmd)
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Waiting for lease")
+	assert.Contains(t, output, `"attempt": 1`)
+	assert.Contains(t, output, `"attempt": 2`)
+	assert.NotContains(t, output, `"attempt": 3`)
+	assert.Contains(t, output, `"remaining"`)
+	assert.Contains(t, output, "Acquired lease")
+
+	// The two attempts that were not granted withdrew their requests
+	requests := leaseRequests(t)
+	require.Len(t, requests, 1)
+	assert.Equal(t, syncv1.LeaseRequestPhaseGranted, requests[0].Status.Phase)
+}
+
+func TestLeaseAcquireCmd_MaxAttempts(t *testing.T) {
+	setupGrantAfterClient(t, 0)
+
+	cmd := newLeaseAcquireCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder", "--poll-interval", "20ms", "--max-attempts", "3"})
+
+	start := time.Now()
+	output, err := executeCommandWithOutputAndLogs(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Contains(t, err.Error(), "lease test-lease not granted after 3 attempts")
+	// Without --timeout the attempts alone bound the wait
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	assert.Contains(t, output, `"attemptsLeft": 1`)
+	assert.NotContains(t, output, `"remaining"`)
+	assert.Empty(t, leaseRequests(t))
+}
+
+func TestLeaseAcquireCmd_BackoffDoublesWindow(t *testing.T) {
+	setupGrantAfterClient(t, 0)
+
+	cmd := newLeaseAcquireCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder", "--poll-interval", "100ms", "--max-attempts", "3"})
+
+	// Windows of about 100ms, 200ms and 400ms, each moved by up to 10%
+	start := time.Now()
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 630*time.Millisecond)
+	assert.Less(t, elapsed, 3*time.Second)
+}
+
+func TestLeaseAcquireCmd_TimeoutStopsRetrying(t *testing.T) {
+	setupGrantAfterClient(t, 0)
+
+	cmd := newLeaseAcquireCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder", "--poll-interval", "50ms", "--timeout", "300ms"})
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Contains(t, err.Error(), "timed out after 300ms waiting for lease test-lease")
+	assert.Empty(t, leaseRequests(t))
+}
+
+func TestLeaseAcquireCmd_InvalidRetryFlags(t *testing.T) {
+	setupGrantAfterClient(t, 1)
+
+	for args, want := range map[string]string{
+		"--poll-interval=0":   "--poll-interval must be positive",
+		"--max-attempts=-1":   "--max-attempts must not be negative",
+		"--poll-interval=-1s": "--poll-interval must be positive",
+	} {
+		cmd := newLeaseAcquireCmd()
+		cmd.SetArgs([]string{"test-lease", "--holder", "test-holder", args})
+		_, err := executeCommandWithOutput(t, cmd)
+		assert.EqualError(t, err, want, args)
+	}
+	assert.Empty(t, leaseRequests(t))
+}
+
 func TestLeaseReleaseCmd(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, syncv1.AddToScheme(scheme))
@@ -133,6 +300,83 @@ func TestLeaseReleaseCmd(t *testing.T) {
 	_ = buf.String()
 }
 
+func setupRenewClient(t *testing.T, holder string, expiresAt time.Time) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	expires := metav1.NewTime(expiresAt)
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "default",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase:      syncv1.LeasePhaseHeld,
+			Holder:     holder,
+			ExpiresAt:  &expires,
+			RenewCount: 1,
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease).
+		WithStatusSubresource(&syncv1.Lease{}).
+		Build()
+	namespace = "default"
+}
+
+func TestLeaseRenewCmd(t *testing.T) {
+	setupRenewClient(t, "test-holder", time.Now().Add(time.Minute))
+
+	cmd := newLeaseRenewCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder"})
+
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Renewed lease")
+
+	var result syncv1.Lease
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-lease", Namespace: "default"}, &result))
+	assert.Equal(t, int32(2), result.Status.RenewCount)
+	require.NotNil(t, result.Status.ExpiresAt)
+	assert.True(t, result.Status.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+}
+
+func TestLeaseRenewCmd_NotHolder(t *testing.T) {
+	setupRenewClient(t, "other-holder", time.Now().Add(time.Minute))
+
+	cmd := newLeaseRenewCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder"})
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotHolder))
+	assert.Contains(t, err.Error(), "not held by test-holder")
+
+	var result syncv1.Lease
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-lease", Namespace: "default"}, &result))
+	assert.Equal(t, int32(1), result.Status.RenewCount)
+}
+
+func TestLeaseRenewCmd_Expired(t *testing.T) {
+	setupRenewClient(t, "test-holder", time.Now().Add(-time.Minute))
+
+	cmd := newLeaseRenewCmd()
+	cmd.SetArgs([]string{"test-lease", "--holder", "test-holder"})
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrExpired))
+
+	var result syncv1.Lease
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-lease", Namespace: "default"}, &result))
+	assert.Equal(t, int32(1), result.Status.RenewCount)
+}
+
 func TestLeaseListCmd(t *testing.T) {
 	logger = initTestLogger(t)
 	scheme := runtime.NewScheme()
@@ -279,3 +523,130 @@ func TestLeaseDeleteCmd(t *testing.T) {
 
 	_ = buf.String()
 }
+
+func TestLeaseTransferCmd(t *testing.T) {
+	setupRenewClient(t, "test-holder", time.Now().Add(time.Minute))
+
+	cmd := newLeaseTransferCmd()
+	cmd.SetArgs([]string{"test-lease", "--from", "test-holder", "--to", "successor"})
+
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Transferred lease")
+
+	var result syncv1.Lease
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-lease", Namespace: "default"}, &result))
+	assert.Equal(t, "successor", result.Status.Holder)
+	assert.Equal(t, int32(0), result.Status.RenewCount)
+	require.NotNil(t, result.Status.AcquiredAt)
+	require.NotNil(t, result.Status.ExpiresAt)
+	assert.True(t, result.Status.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+}
+
+func TestLeaseTransferCmd_NotHolder(t *testing.T) {
+	setupRenewClient(t, "other-holder", time.Now().Add(time.Minute))
+
+	cmd := newLeaseTransferCmd()
+	cmd.SetArgs([]string{"test-lease", "--from", "test-holder", "--to", "successor"})
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotHolder))
+
+	var result syncv1.Lease
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-lease", Namespace: "default"}, &result))
+	assert.Equal(t, "other-holder", result.Status.Holder)
+}
+
+func TestLeaseTransferCmd_MissingTo(t *testing.T) {
+	setupRenewClient(t, "test-holder", time.Now().Add(time.Minute))
+
+	cmd := newLeaseTransferCmd()
+	cmd.SetArgs([]string{"test-lease", "--from", "test-holder"})
+
+	_, err := executeCommandWithOutput(t, cmd)
+	assert.ErrorContains(t, err, "--to must be specified")
+}
+
+func TestLeaseOwnerCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	acquiredAt := metav1.NewTime(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	leases := []runtime.Object{
+		&syncv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "held", Namespace: "default"},
+			Status: syncv1.LeaseStatus{
+				Phase:      syncv1.LeasePhaseHeld,
+				Holder:     "holder-1",
+				AcquiredAt: &acquiredAt,
+			},
+		},
+		&syncv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "available", Namespace: "default"},
+			Status:     syncv1.LeaseStatus{Phase: syncv1.LeasePhaseAvailable},
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(leases...).
+		Build()
+	namespace = "default"
+
+	originalFormat := outputFormat
+	defer func() { outputFormat = originalFormat }()
+	outputFormat = "json"
+
+	cmd := newLeaseOwnerCmd()
+	cmd.SetArgs([]string{"held"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	require.NoError(t, cmd.Execute())
+	assert.JSONEq(t, `{"name": "held", "holder": "holder-1", "since": "2024-01-15T10:30:00Z"}`, buf.String())
+
+	cmd = newLeaseOwnerCmd()
+	cmd.SetArgs([]string{"available"})
+	buf.Reset()
+	cmd.SetOut(&buf)
+	require.NoError(t, cmd.Execute())
+	assert.JSONEq(t, `{"name": "available"}`, buf.String())
+}
+
+// useHolderFile points --holder-file at a temporary file holding content
+func useHolderFile(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "holder")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	holderFile = path
+	t.Cleanup(func() { holderFile = "" })
+}
+
+func TestValidateHolder_HolderFile(t *testing.T) {
+	t.Setenv("POD_NAME", "")
+	t.Setenv("HOSTNAME", "test-host")
+
+	useHolderFile(t, "sidecar-holder\n")
+
+	holder, err := validateHolder("")
+	require.NoError(t, err)
+	assert.Equal(t, "sidecar-holder", holder, "the holder file takes precedence over HOSTNAME")
+
+	holder, err = validateHolder("flag-holder")
+	require.NoError(t, err)
+	assert.Equal(t, "flag-holder", holder, "--holder takes precedence over the holder file")
+}
+
+func TestValidateHolder_HolderFileErrors(t *testing.T) {
+	t.Setenv("HOSTNAME", "test-host")
+
+	useHolderFile(t, "  \n")
+	_, err := validateHolder("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is empty")
+
+	holderFile = filepath.Join(t.TempDir(), "missing")
+	_, err = validateHolder("")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read holder file")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+// listFilters holds the flags shared by list commands
+type listFilters struct {
+	selector      string
+	allNamespaces bool
+}
+
+// addListFlags registers the --selector/-l and --all-namespaces/-A flags
+// shared by list commands
+func addListFlags(cmd *cobra.Command, filters *listFilters) {
+	cmd.Flags().StringVarP(&filters.selector, "selector", "l", "", "Label selector to filter on, e.g. team=data,env=prod")
+	cmd.Flags().BoolVarP(&filters.allNamespaces, "all-namespaces", "A", false, "List across all namespaces")
+}
+
+// options turns the list flags into the SDK options for a List call
+func (f listFilters) options() ([]konductor.Option, error) {
+	var opts []konductor.Option
+	if f.allNamespaces {
+		opts = append(opts, konductor.WithAllNamespaces())
+	}
+	if f.selector == "" {
+		return opts, nil
+	}
+
+	selected, err := labels.ConvertSelectorToLabelsMap(f.selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", f.selector, err)
+	}
+	return append(opts, konductor.WithLabelSelector(selected)), nil
+}
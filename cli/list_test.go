@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func TestListCommands_Selector(t *testing.T) {
+	scheme := setupOutputTestScheme(t)
+
+	labeled := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"team": "data"}}
+	}
+	unlabeled := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default"}
+	}
+	objects := []runtime.Object{
+		&syncv1.Semaphore{ObjectMeta: labeled("data-sem")}, &syncv1.Semaphore{ObjectMeta: unlabeled("other-sem")},
+		&syncv1.Barrier{ObjectMeta: labeled("data-barrier")}, &syncv1.Barrier{ObjectMeta: unlabeled("other-barrier")},
+		&syncv1.Lease{ObjectMeta: labeled("data-lease")}, &syncv1.Lease{ObjectMeta: unlabeled("other-lease")},
+		&syncv1.Gate{ObjectMeta: labeled("data-gate")}, &syncv1.Gate{ObjectMeta: unlabeled("other-gate")},
+		&syncv1.Mutex{ObjectMeta: labeled("data-mutex")}, &syncv1.Mutex{ObjectMeta: unlabeled("other-mutex")},
+		&syncv1.RWMutex{ObjectMeta: labeled("data-rwmutex")}, &syncv1.RWMutex{ObjectMeta: unlabeled("other-rwmutex")},
+		&syncv1.Once{ObjectMeta: labeled("data-once")}, &syncv1.Once{ObjectMeta: unlabeled("other-once")},
+		&syncv1.WaitGroup{ObjectMeta: labeled("data-wg")}, &syncv1.WaitGroup{ObjectMeta: unlabeled("other-wg")},
+	}
+
+	tests := []struct {
+		name     string
+		newCmd   func() *cobra.Command
+		expected string
+	}{
+		{"semaphore", newSemaphoreListCmd, "data-sem"},
+		{"barrier", newBarrierListCmd, "data-barrier"},
+		{"lease", newLeaseListCmd, "data-lease"},
+		{"gate", newGateListCmd, "data-gate"},
+		{"mutex", newMutexListCmd, "data-mutex"},
+		{"rwmutex", newRWMutexListCmd, "data-rwmutex"},
+		{"once", newOnceListCmd, "data-once"},
+		{"waitgroup", newWaitGroupListCmd, "data-wg"},
+	}
+
+	originalFormat := outputFormat
+	defer func() { outputFormat = originalFormat }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(objects...).
+				Build()
+			namespace = "default"
+			outputFormat = "json"
+
+			var buf bytes.Buffer
+			cmd := tt.newCmd()
+			cmd.SetOut(&buf)
+			cmd.SetArgs([]string{"-l", "team=data"})
+			require.NoError(t, cmd.Execute())
+
+			var items []struct {
+				Metadata metav1.ObjectMeta `json:"metadata"`
+			}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &items), buf.String())
+			require.Len(t, items, 1)
+			assert.Equal(t, tt.expected, items[0].Metadata.Name)
+		})
+	}
+}
+
+func TestListCommands_AllNamespaces(t *testing.T) {
+	scheme := setupOutputTestScheme(t)
+
+	originalFormat := outputFormat
+	defer func() { outputFormat = originalFormat }()
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(
+			&syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "sem-a", Namespace: "default"}},
+			&syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "sem-b", Namespace: "team-b"}},
+		).
+		Build()
+	namespace = "default"
+	outputFormat = "json"
+
+	run := func(args ...string) []metav1.ObjectMeta {
+		var buf bytes.Buffer
+		cmd := newSemaphoreListCmd()
+		cmd.SetOut(&buf)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+
+		var items []struct {
+			Metadata metav1.ObjectMeta `json:"metadata"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &items), buf.String())
+		var metas []metav1.ObjectMeta
+		for _, item := range items {
+			metas = append(metas, item.Metadata)
+		}
+		return metas
+	}
+
+	assert.Len(t, run(), 1)
+
+	all := run("-A")
+	require.Len(t, all, 2)
+	namespaces := []string{all[0].Namespace, all[1].Namespace}
+	assert.ElementsMatch(t, []string{"default", "team-b"}, namespaces)
+}
+
+func TestListCommands_TextIncludesNamespace(t *testing.T) {
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(setupOutputTestScheme(t)).
+		WithRuntimeObjects(&syncv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "leader", Namespace: "team-b"}}).
+		Build()
+	namespace = "default"
+	originalFormat := outputFormat
+	outputFormat = "text"
+	defer func() { outputFormat = originalFormat }()
+
+	cmd := newLeaseListCmd()
+	cmd.SetArgs([]string{"--all-namespaces"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "leader")
+	assert.Contains(t, output, `"namespace": "team-b"`)
+}
+
+func TestListFilters_Options(t *testing.T) {
+	opts, err := listFilters{}.options()
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	opts, err = listFilters{selector: "team=data,env=prod", allNamespaces: true}.options()
+	require.NoError(t, err)
+	assert.Len(t, opts, 2)
+
+	_, err = listFilters{selector: "team"}.options()
+	assert.Error(t, err)
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -13,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
 var (
@@ -25,6 +30,9 @@ var (
 	namespace    string
 	logLevel     string
 	outputFormat string
+	dryRun       bool
+	holderFile   string
+	instanceID   string
 	k8sClient    client.Client
 	logger       *zap.Logger
 )
@@ -41,6 +49,10 @@ func execute() error {
 		Short: "Konductor CLI for coordination primitives",
 		Long:  "A CLI tool to interact with Konductor synchronization primitives (semaphores, barriers, leases, gates)\n\nNamespace Detection:\n  - Auto-detects namespace when running in a pod\n  - Falls back to kubeconfig context or 'default'",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			outputFormat = viper.GetString("output")
+			if err := validateOutputFormat(); err != nil {
+				return err
+			}
 			if err := initLogger(); err != nil {
 				return err
 			}
@@ -51,13 +63,18 @@ func execute() error {
 	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file")
 	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default", "Kubernetes namespace (auto-detected if running in pod)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Preview changes without applying them")
+	rootCmd.PersistentFlags().StringVar(&holderFile, "holder-file", "", "File to read the holder identifier from when --holder is not set")
+	rootCmd.PersistentFlags().StringVar(&instanceID, "instance-id", "", "Holder identifier to use outside a pod when neither --holder nor --holder-file is set")
 
 	// Bind flags to viper - errors only occur if flag doesn't exist, which can't happen here
 	_ = viper.BindPFlag("kubeconfig", rootCmd.PersistentFlags().Lookup("kubeconfig"))
 	_ = viper.BindPFlag("namespace", rootCmd.PersistentFlags().Lookup("namespace"))
 	_ = viper.BindPFlag("log-level", rootCmd.PersistentFlags().Lookup("log-level"))
 	_ = viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	_ = viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
+	_ = viper.BindPFlag("instance-id", rootCmd.PersistentFlags().Lookup("instance-id"))
 
 	// Set up viper
 	viper.SetConfigName("koncli")
@@ -80,7 +97,14 @@ func execute() error {
 	rootCmd.AddCommand(newRWMutexCmd())
 	rootCmd.AddCommand(newOnceCmd())
 	rootCmd.AddCommand(newWaitGroupCmd())
+	rootCmd.AddCommand(newMaintenanceCmd())
 	rootCmd.AddCommand(newStatusCmd())
+	rootCmd.AddCommand(newDescribeCmd())
+	rootCmd.AddCommand(newWatchCmd())
+	rootCmd.AddCommand(newGatewayCmd())
+	rootCmd.AddCommand(newApplyCmd())
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newCompletionCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		if logger != nil {
@@ -140,6 +164,8 @@ func initKubeClient(cmd *cobra.Command) error {
 	namespace = viper.GetString("namespace")
 	logLevel = viper.GetString("log-level")
 	outputFormat = viper.GetString("output")
+	dryRun = viper.GetBool("dry-run")
+	instanceID = viper.GetString("instance-id")
 
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -151,7 +177,7 @@ func initKubeClient(cmd *cobra.Command) error {
 		return err
 	}
 
-	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	k8sClient, err = client.NewWithWatch(cfg, client.Options{Scheme: scheme})
 	if err != nil {
 		return err
 	}
@@ -214,3 +240,70 @@ func getNamespaceFromKubeconfig() string {
 	}
 	return ""
 }
+
+// detectHolder picks the holder for commands not given --holder or
+// --holder-file. It prefers the pod identity from the downward API, then
+// --instance-id, then HOSTNAME, and last an identifier generated once per
+// session so a lock and its unlock from the same shell still match.
+func detectHolder() (string, error) {
+	if os.Getenv("POD_NAME") != "" {
+		holder := konductor.HolderFromEnv()
+		logger.Debug("Auto-detected holder from pod name", zap.String("holder", holder))
+		return holder, nil
+	}
+
+	if instanceID != "" {
+		return instanceID, nil
+	}
+
+	if hostname := os.Getenv("HOSTNAME"); hostname != "" {
+		logger.Debug("Auto-detected holder from hostname", zap.String("holder", hostname))
+		return hostname, nil
+	}
+
+	holder, err := sessionHolder()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate a holder, set --holder or --instance-id: %w", err)
+	}
+	logger.Debug("Using generated holder for this session", zap.String("holder", holder))
+	return holder, nil
+}
+
+// sessionHolderPath is the temp file that keeps the generated holder of the
+// shell session koncli runs in, keyed by user and parent process
+func sessionHolderPath() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("koncli-holder-%d-%d", os.Getuid(), os.Getppid()))
+}
+
+// sessionHolder returns the holder generated for this session, generating
+// and saving one on first use
+func sessionHolder() (string, error) {
+	path := sessionHolderPath()
+	if data, err := os.ReadFile(path); err == nil {
+		if holder := strings.TrimSpace(string(data)); holder != "" {
+			return holder, nil
+		}
+	}
+
+	holder := fmt.Sprintf("koncli-%s", uuid.NewString())
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		// Another invocation from the same session got there first
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		if holder = strings.TrimSpace(string(data)); holder == "" {
+			return "", fmt.Errorf("session holder file %s is empty", path)
+		}
+		return holder, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := file.WriteString(holder + "\n"); err != nil {
+		return "", err
+	}
+	return holder, nil
+}
@@ -3,9 +3,12 @@ package main
 import (
 	"bytes"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -323,6 +326,107 @@ func TestDetectNamespacePriority(t *testing.T) {
 	}
 }
 
+// useInstanceID sets --instance-id for the length of the test
+func useInstanceID(t *testing.T, id string) {
+	t.Helper()
+	instanceID = id
+	t.Cleanup(func() { instanceID = "" })
+}
+
+func TestDetectHolder(t *testing.T) {
+	logger = initTestLogger(t)
+
+	tests := []struct {
+		name       string
+		podName    string
+		podUID     string
+		instanceID string
+		hostname   string
+		expected   string
+	}{
+		{
+			name:       "pod name wins over everything else",
+			podName:    "job-abc12",
+			instanceID: "laptop-alice",
+			hostname:   "node-1",
+			expected:   "job-abc12",
+		},
+		{
+			name:     "pod name with uid",
+			podName:  "job-abc12",
+			podUID:   "9b8c7d6e-1111-2222-3333-444455556666",
+			hostname: "node-1",
+			expected: "job-abc12-9b8c7d6e-1111-2222-3333-444455556666",
+		},
+		{
+			name:       "instance id wins over hostname",
+			instanceID: "laptop-alice",
+			hostname:   "shared-box",
+			expected:   "laptop-alice",
+		},
+		{
+			name:     "hostname",
+			hostname: "shared-box",
+			expected: "shared-box",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POD_NAME", tt.podName)
+			t.Setenv("POD_UID", tt.podUID)
+			t.Setenv("HOSTNAME", tt.hostname)
+			useInstanceID(t, tt.instanceID)
+
+			holder, err := detectHolder()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, holder)
+		})
+	}
+}
+
+func TestDetectHolder_GeneratedForSession(t *testing.T) {
+	logger = initTestLogger(t)
+	t.Setenv("POD_NAME", "")
+	t.Setenv("HOSTNAME", "")
+	t.Setenv("TMPDIR", t.TempDir())
+
+	first, err := detectHolder()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "koncli-"), first)
+
+	// Later invocations from the same session reuse it
+	second, err := detectHolder()
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	data, err := os.ReadFile(sessionHolderPath())
+	require.NoError(t, err)
+	assert.Equal(t, first, strings.TrimSpace(string(data)))
+
+	// A new session gets a new one
+	t.Setenv("TMPDIR", t.TempDir())
+	third, err := detectHolder()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, third)
+}
+
+func TestValidateHolder_InstanceID(t *testing.T) {
+	logger = initTestLogger(t)
+	t.Setenv("POD_NAME", "")
+	t.Setenv("HOSTNAME", "shared-box")
+	useInstanceID(t, "laptop-alice")
+
+	holder, err := validateHolder("")
+	require.NoError(t, err)
+	assert.Equal(t, "laptop-alice", holder)
+
+	useHolderFile(t, "sidecar-holder")
+	holder, err = validateHolder("")
+	require.NoError(t, err)
+	assert.Equal(t, "sidecar-holder", holder, "the holder file takes precedence over --instance-id")
+}
+
 // TestMain runs before all tests
 func TestMain(m *testing.M) {
 	// Run tests
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+func newMaintenanceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Recovery tools for operators",
+		Long:  "Repair the state of primitives, for example after the controller was down",
+	}
+
+	cmd.AddCommand(newMaintenanceGCCmd())
+
+	return cmd
+}
+
+// gcResult is what maintenance gc cleared, for structured output
+type gcResult struct {
+	Namespace  string `json:"namespace"`
+	Mutexes    int    `json:"mutexes"`
+	RWMutexes  int    `json:"rwmutexes"`
+	Leases     int    `json:"leases"`
+	Permits    int    `json:"permits"`
+	Semaphores int    `json:"semaphores"`
+}
+
+func newMaintenanceGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Clear expired locks, leases and permits",
+		Long: "Release every mutex, rwmutex and lease in the namespace whose expiry has passed, delete expired semaphore permits " +
+			"and recompute the counts of their semaphores. The controller does this itself while it runs; gc catches up on what " +
+			"it missed while it was down. With --dry-run the changes are sent as dry runs, so nothing is stored.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := konductor.NewFromClient(kubeClient(), namespace)
+
+			counts, err := client.CollectExpired(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput() {
+				return printStructured(cmd.OutOrStdout(), gcResult{
+					Namespace:  namespace,
+					Mutexes:    counts.Mutexes,
+					RWMutexes:  counts.RWMutexes,
+					Leases:     counts.Leases,
+					Permits:    counts.Permits,
+					Semaphores: counts.Semaphores,
+				})
+			}
+
+			if counts.Total() == 0 {
+				logger.Info("Nothing expired to collect", zap.String("namespace", namespace))
+				return nil
+			}
+			logger.Info(dryRunMsg("Collected expired holds"),
+				zap.String("namespace", namespace),
+				zap.Int("mutexes", counts.Mutexes),
+				zap.Int("rwmutexes", counts.RWMutexes),
+				zap.Int("leases", counts.Leases),
+				zap.Int("permits", counts.Permits),
+				zap.Int("semaphores", counts.Semaphores))
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func newMaintenanceTestClient(t *testing.T, objects ...runtime.Object) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.Mutex{}, &syncv1.Lease{}, &syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+	namespace = "default"
+}
+
+func TestMaintenanceGCCmd_JSON(t *testing.T) {
+	initTestLogger(t)
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	permit := func(name string) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"semaphore": "db-pool"}},
+			Spec:       syncv1.PermitSpec{Semaphore: "db-pool", Holder: name},
+			Status:     syncv1.PermitStatus{Phase: syncv1.PermitPhaseGranted, ExpiresAt: &past},
+		}
+	}
+	newMaintenanceTestClient(t,
+		&syncv1.Mutex{
+			ObjectMeta: metav1.ObjectMeta{Name: "migrations", Namespace: "default"},
+			Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseLocked, Holder: "pod-1", ExpiresAt: &past},
+		},
+		&syncv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "leader", Namespace: "default"},
+			Status:     syncv1.LeaseStatus{Phase: syncv1.LeasePhaseHeld, Holder: "pod-2", ExpiresAt: &past},
+		},
+		&syncv1.Semaphore{
+			ObjectMeta: metav1.ObjectMeta{Name: "db-pool", Namespace: "default"},
+			Spec:       syncv1.SemaphoreSpec{Permits: 2},
+			Status:     syncv1.SemaphoreStatus{InUse: 2, Available: 0, Phase: syncv1.SemaphorePhaseFull},
+		},
+		permit("pod-3"), permit("pod-4"),
+	)
+	originalFormat := outputFormat
+	defer func() { outputFormat = originalFormat }()
+	outputFormat = "json"
+
+	output, err := executeCommandWithOutput(t, newMaintenanceGCCmd())
+	require.NoError(t, err)
+
+	var result gcResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, gcResult{Namespace: "default", Mutexes: 1, Leases: 1, Permits: 2, Semaphores: 1}, result)
+
+	ctx := context.Background()
+	key := func(name string) types.NamespacedName { return types.NamespacedName{Name: name, Namespace: "default"} }
+
+	var mutex syncv1.Mutex
+	require.NoError(t, k8sClient.Get(ctx, key("migrations"), &mutex))
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, mutex.Status.Phase)
+	assert.Empty(t, mutex.Status.Holder)
+
+	var lease syncv1.Lease
+	require.NoError(t, k8sClient.Get(ctx, key("leader"), &lease))
+	assert.Equal(t, syncv1.LeasePhaseAvailable, lease.Status.Phase)
+
+	var p syncv1.Permit
+	assert.True(t, errors.IsNotFound(k8sClient.Get(ctx, key("pod-3"), &p)))
+
+	var sem syncv1.Semaphore
+	require.NoError(t, k8sClient.Get(ctx, key("db-pool"), &sem))
+	assert.Equal(t, int32(0), sem.Status.InUse)
+	assert.Equal(t, int32(2), sem.Status.Available)
+	assert.Equal(t, syncv1.SemaphorePhaseReady, sem.Status.Phase)
+}
+
+func TestMaintenanceGCCmd_NothingExpired(t *testing.T) {
+	initTestLogger(t)
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+	newMaintenanceTestClient(t, &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrations", Namespace: "default"},
+		Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseLocked, Holder: "pod-1", ExpiresAt: &future},
+	})
+
+	_, err := executeCommandWithOutput(t, newMaintenanceGCCmd())
+	require.NoError(t, err)
+
+	var mutex syncv1.Mutex
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "migrations", Namespace: "default"}, &mutex))
+	assert.Equal(t, "pod-1", mutex.Status.Holder)
+}
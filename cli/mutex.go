@@ -1,11 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 	"github.com/LogicIQ/konductor/sdk/go/mutex"
 )
@@ -22,12 +24,16 @@ func newMutexCmd() *cobra.Command {
 	cmd.AddCommand(newMutexLockCmd())
 	cmd.AddCommand(newMutexUnlockCmd())
 	cmd.AddCommand(newMutexListCmd())
+	cmd.AddCommand(newMutexOwnerCmd())
+	cmd.AddCommand(newMutexBreakCmd())
+
+	registerNameCompletion(cmd, &syncv1.MutexList{})
 
 	return cmd
 }
 
 func createMutexClient() *konductor.Client {
-	return konductor.NewFromClient(k8sClient, namespace)
+	return konductor.NewFromClient(kubeClient(), namespace)
 }
 
 func newMutexLockCmd() *cobra.Command {
@@ -42,7 +48,8 @@ func newMutexLockCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			mutexName := args[0]
-			ctx := cmd.Context()
+			ctx, stop := interruptContext(cmd.Context())
+			defer stop()
 
 			var err error
 			holder, err = validateHolder(holder)
@@ -59,10 +66,17 @@ func newMutexLockCmd() *cobra.Command {
 				opts = append(opts, konductor.WithTimeout(timeout))
 			}
 
+			if skipForDryRun("lock mutex", zap.String("mutex", mutexName), zap.String("holder", holder)) {
+				return nil
+			}
+
 			mutexObj, err := mutex.Lock(client, ctx, mutexName, opts...)
 			if err != nil {
 				return err
 			}
+			if err := releaseIfInterrupted(ctx, "mutex", mutexName, mutexObj.Unlock); err != nil {
+				return err
+			}
 
 			logger.Info("Locked mutex", zap.String("mutex", mutexName), zap.String("holder", mutexObj.Holder()))
 			return nil
@@ -70,7 +84,7 @@ func newMutexLockCmd() *cobra.Command {
 	}
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for waiting (e.g., 30s, 5m)")
-	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to the detected holder)")
 
 	return cmd
 }
@@ -94,6 +108,10 @@ func newMutexUnlockCmd() *cobra.Command {
 
 			client := createMutexClient()
 
+			if skipForDryRun("unlock mutex", zap.String("mutex", mutexName), zap.String("holder", holder)) {
+				return nil
+			}
+
 			if err := mutex.Unlock(client, ctx, mutexName, holder); err != nil {
 				return err
 			}
@@ -103,12 +121,65 @@ func newMutexUnlockCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to the detected holder)")
+
+	return cmd
+}
+
+func newMutexBreakCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "break <mutex-name>",
+		Short: "Forcibly unlock a mutex",
+		Long: "Unlock a mutex whoever holds it, to free a lock whose holder is gone before its TTL runs out. " +
+			"The fence token is bumped so the old holder's token is stale. Requires --force.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mutexName := args[0]
+			ctx := cmd.Context()
+
+			if err := requireForce("mutex", mutexName, force); err != nil {
+				return err
+			}
+
+			client := createMutexClient()
+
+			if skipForDryRun("break mutex", zap.String("mutex", mutexName)) {
+				return nil
+			}
+
+			holder, err := mutex.Break(client, ctx, mutexName)
+			if err != nil {
+				return err
+			}
+
+			if holder == "" {
+				logger.Info("Mutex is unlocked", zap.String("mutex", mutexName))
+				return nil
+			}
+			logger.Warn("Broke mutex lock", zap.String("mutex", mutexName), zap.String("holder", holder))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Confirm taking the lock from its holder")
 
 	return cmd
 }
 
+// requireForce refuses to break a lock unless --force was given, since its
+// holder may still be running and relying on it
+func requireForce(kind, name string, force bool) error {
+	if force {
+		return nil
+	}
+	return fmt.Errorf("breaking %s %s takes the lock from its holder, which may still be running; pass --force to confirm", kind, name)
+}
+
 func newMutexListCmd() *cobra.Command {
+	var filters listFilters
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all mutexes",
@@ -117,11 +188,20 @@ func newMutexListCmd() *cobra.Command {
 
 			client := createMutexClient()
 
-			mutexes, err := mutex.List(client, ctx)
+			opts, err := filters.options()
 			if err != nil {
 				return err
 			}
 
+			mutexes, err := mutex.List(client, ctx, opts...)
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), mutexes)
+			}
+
 			if len(mutexes) == 0 {
 				logger.Info("No mutexes found")
 				return nil
@@ -140,6 +220,7 @@ func newMutexListCmd() *cobra.Command {
 
 				logger.Info("Mutex",
 					zap.String("name", m.Name),
+					zap.String("namespace", m.Namespace),
 					zap.String("holder", holder),
 					zap.String("phase", string(m.Status.Phase)),
 					zap.String("locked", locked),
@@ -150,11 +231,60 @@ func newMutexListCmd() *cobra.Command {
 		},
 	}
 
+	addListFlags(cmd, &filters)
+
+	return cmd
+}
+
+func newMutexOwnerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owner <mutex-name>",
+		Short: "Show who holds a mutex",
+		Long:  "Show the current holder of a mutex and since when they have held it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mutexName := args[0]
+			ctx := cmd.Context()
+
+			client := createMutexClient()
+
+			holder, since, err := mutex.Owner(client, ctx, mutexName)
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput() {
+				return printStructured(cmd.OutOrStdout(), ownerStatus{Name: mutexName, Holder: holder, Since: formatSince(since)})
+			}
+
+			if holder == "" {
+				logger.Info("Mutex is unlocked", zap.String("mutex", mutexName))
+				return nil
+			}
+
+			fields := []zap.Field{
+				zap.String("mutex", mutexName),
+				zap.String("holder", holder),
+			}
+			if !since.IsZero() {
+				fields = append(fields,
+					zap.String("since", formatSince(since)),
+					zap.Duration("held", time.Since(since).Truncate(time.Second)),
+				)
+			}
+			logger.Info("Mutex owner", fields...)
+			return nil
+		},
+	}
+
 	return cmd
 }
 
 func newMutexCreateCmd() *cobra.Command {
-	var ttl time.Duration
+	var (
+		ttl       time.Duration
+		reentrant bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "create <mutex-name>",
@@ -170,22 +300,29 @@ func newMutexCreateCmd() *cobra.Command {
 			if ttl > 0 {
 				opts = append(opts, konductor.WithTTL(ttl))
 			}
+			if reentrant {
+				opts = append(opts, konductor.WithReentrant())
+			}
 
 			if err := mutex.Create(client, ctx, mutexName, opts...); err != nil {
 				return err
 			}
 
-			logger.Info("Created mutex", zap.String("mutex", mutexName))
+			logger.Info(dryRunMsg("Created mutex"), zap.String("mutex", mutexName))
 			return nil
 		},
 	}
 
 	cmd.Flags().DurationVar(&ttl, "ttl", 0, "Optional TTL for automatic unlock")
+	cmd.Flags().BoolVar(&reentrant, "reentrant", false, "Let the holder lock the mutex again without waiting")
 
 	return cmd
 }
 
 func newMutexDeleteCmd() *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "delete <mutex-name>",
 		Short: "Delete a mutex",
@@ -200,10 +337,17 @@ func newMutexDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Deleted mutex", zap.String("mutex", mutexName))
+			if err := waitForDeletion(ctx, client, &syncv1.Mutex{}, mutexName, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted mutex"), zap.String("mutex", mutexName))
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the mutex is gone")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultDeleteWaitTimeout, "How long --wait blocks before giving up")
+
 	return cmd
 }
@@ -2,12 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -77,6 +80,26 @@ func TestMutexCreateCmd(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMutexCreateCmd_Reentrant(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+	namespace = "default"
+
+	cmd := newMutexCreateCmd()
+	cmd.SetArgs([]string{"test-mutex", "--reentrant"})
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+
+	var created syncv1.Mutex
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-mutex", Namespace: "default"}, &created))
+	assert.True(t, created.Spec.Reentrant)
+}
+
 func TestMutexCreateCmd_WithTTL(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, syncv1.AddToScheme(scheme))
@@ -154,6 +177,41 @@ func TestMutexLockCmd(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMutexLockCmd_HolderFile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "default",
+		},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseUnlocked,
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		Build()
+	namespace = "default"
+	useHolderFile(t, "sidecar-holder\n")
+
+	cmd := newMutexLockCmd()
+	cmd.SetArgs([]string{"test-mutex"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	require.NoError(t, cmd.Execute())
+
+	var updated syncv1.Mutex
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(mutex), &updated))
+	assert.Equal(t, "sidecar-holder", updated.Status.Holder)
+}
+
 func TestMutexUnlockCmd(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, syncv1.AddToScheme(scheme))
@@ -293,3 +351,103 @@ func TestMutexLockCmd_AlreadyLocked(t *testing.T) {
 	err := cmd.Execute()
 	require.Error(t, err)
 }
+
+func TestMutexOwnerCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lockedAt := metav1.NewTime(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	mutexes := []runtime.Object{
+		&syncv1.Mutex{
+			ObjectMeta: metav1.ObjectMeta{Name: "locked", Namespace: "default"},
+			Status: syncv1.MutexStatus{
+				Phase:    syncv1.MutexPhaseLocked,
+				Holder:   "holder-1",
+				LockedAt: &lockedAt,
+			},
+		},
+		&syncv1.Mutex{
+			ObjectMeta: metav1.ObjectMeta{Name: "unlocked", Namespace: "default"},
+			Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseUnlocked},
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutexes...).
+		Build()
+	namespace = "default"
+
+	cmd := newMutexOwnerCmd()
+	cmd.SetArgs([]string{"locked"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "holder-1")
+	assert.Contains(t, output, "2024-01-15T10:30:00Z")
+
+	cmd = newMutexOwnerCmd()
+	cmd.SetArgs([]string{"unlocked"})
+	output, err = executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Mutex is unlocked")
+}
+
+func TestMutexBreakCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lockedAt := metav1.Now()
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "default",
+		},
+		Status: syncv1.MutexStatus{
+			Phase:      syncv1.MutexPhaseLocked,
+			Holder:     "crashed-holder",
+			LockedAt:   &lockedAt,
+			FenceToken: 7,
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		Build()
+	namespace = "default"
+
+	// Without --force nothing is touched
+	cmd := newMutexBreakCmd()
+	cmd.SetArgs([]string{"test-mutex"})
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pass --force to confirm")
+
+	var current syncv1.Mutex
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-mutex", Namespace: "default"}, &current))
+	assert.Equal(t, "crashed-holder", current.Status.Holder)
+
+	cmd = newMutexBreakCmd()
+	cmd.SetArgs([]string{"test-mutex", "--force"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "WARN")
+	assert.Contains(t, output, "Broke mutex lock")
+	assert.Contains(t, output, "crashed-holder")
+
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-mutex", Namespace: "default"}, &current))
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, current.Status.Phase)
+	assert.Empty(t, current.Status.Holder)
+	assert.Equal(t, int64(8), current.Status.FenceToken)
+
+	// The lock can be taken again straight away
+	cmd = newMutexLockCmd()
+	cmd.SetArgs([]string{"test-mutex", "--holder", "new-holder", "--timeout", "2s"})
+	_, err = executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-mutex", Namespace: "default"}, &current))
+	assert.Equal(t, "new-holder", current.Status.Holder)
+	assert.Equal(t, int64(9), current.Status.FenceToken)
+}
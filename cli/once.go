@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 	"github.com/LogicIQ/konductor/sdk/go/once"
 )
@@ -22,8 +23,12 @@ func newOnceCmd() *cobra.Command {
 	cmd.AddCommand(newOnceCreateCmd())
 	cmd.AddCommand(newOnceDeleteCmd())
 	cmd.AddCommand(newOnceCheckCmd())
+	cmd.AddCommand(newOnceStatusCmd())
+	cmd.AddCommand(newOnceDoCmd())
 	cmd.AddCommand(newOnceListCmd())
 
+	registerNameCompletion(cmd, &syncv1.OnceList{})
+
 	return cmd
 }
 
@@ -31,7 +36,7 @@ func createOnceClient() (*konductor.Client, error) {
 	if k8sClient == nil {
 		return nil, fmt.Errorf("kubernetes client not initialized")
 	}
-	return konductor.NewFromClient(k8sClient, namespace), nil
+	return konductor.NewFromClient(kubeClient(), namespace), nil
 }
 
 func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
@@ -78,9 +83,119 @@ func newOnceCheckCmd() *cobra.Command {
 	return cmd
 }
 
-func newOnceListCmd() *cobra.Command {
+func newOnceStatusCmd() *cobra.Command {
 	var timeout time.Duration
 
+	cmd := &cobra.Command{
+		Use:   "status <once-name>",
+		Short: "Show once status",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx, cancel := withTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			client, err := createOnceClient()
+			if err != nil {
+				return err
+			}
+
+			o, err := once.Get(client, ctx, name)
+			if err != nil {
+				return err
+			}
+
+			executor := o.Status.Executor
+			if executor == "" {
+				executor = "N/A"
+			}
+
+			executedAt := "N/A"
+			if o.Status.ExecutedAt != nil {
+				executedAt = o.Status.ExecutedAt.Format(time.RFC3339)
+			}
+
+			logger.Info("Once status",
+				zap.String("name", o.Name),
+				zap.Bool("executed", o.Status.Executed),
+				zap.String("executor", executor),
+				zap.String("phase", string(o.Status.Phase)),
+				zap.String("executedAt", executedAt),
+			)
+			if o.Status.Result != "" {
+				logger.Info("Once result", zap.String("name", o.Name), zap.String("result", o.Status.Result))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for operation")
+
+	return cmd
+}
+
+func newOnceDoCmd() *cobra.Command {
+	var (
+		executor string
+		result   string
+		timeout  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "do <once-name>",
+		Short: "Mark a once as executed",
+		Long:  "Atomically mark a once as executed and report whether this invocation was the first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx, cancel := withTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			client, err := createOnceClient()
+			if err != nil {
+				return err
+			}
+
+			var opts []konductor.Option
+			if executor != "" {
+				opts = append(opts, konductor.WithHolder(executor))
+			}
+
+			if skipForDryRun("mark once as executed", zap.String("once", name)) {
+				return nil
+			}
+
+			// The CLI has no user function to run, so it only records the
+			// execution and the result given on the command line
+			first, err := once.DoWithResult(client, ctx, name, func() (string, error) { return result, nil }, opts...)
+			if err != nil {
+				return err
+			}
+
+			if first {
+				logger.Info("Once executed by this invocation", zap.String("once", name), zap.Bool("first", true))
+			} else {
+				logger.Info("Once was already executed", zap.String("once", name), zap.Bool("first", false))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&executor, "executor", "", "Executor identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&result, "result", "", "Result to store for callers that did not execute the once")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for operation")
+
+	return cmd
+}
+
+func newOnceListCmd() *cobra.Command {
+	var (
+		timeout time.Duration
+		filters listFilters
+	)
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all onces",
@@ -93,11 +208,20 @@ func newOnceListCmd() *cobra.Command {
 				return err
 			}
 
-			onces, err := once.List(client, ctx)
+			opts, err := filters.options()
+			if err != nil {
+				return err
+			}
+
+			onces, err := once.List(client, ctx, opts...)
 			if err != nil {
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), onces)
+			}
+
 			if len(onces) == 0 {
 				logger.Info("No onces found")
 				return nil
@@ -116,6 +240,7 @@ func newOnceListCmd() *cobra.Command {
 
 				logger.Info("Once",
 					zap.String("name", o.Name),
+					zap.String("namespace", o.Namespace),
 					zap.Bool("executed", o.Status.Executed),
 					zap.String("executor", executor),
 					zap.String("phase", string(o.Status.Phase)),
@@ -128,6 +253,7 @@ func newOnceListCmd() *cobra.Command {
 	}
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for operation")
+	addListFlags(cmd, &filters)
 
 	return cmd
 }
@@ -160,7 +286,7 @@ func newOnceCreateCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Created once", zap.String("once", name))
+			logger.Info(dryRunMsg("Created once"), zap.String("once", name))
 			return nil
 		},
 	}
@@ -173,6 +299,7 @@ func newOnceCreateCmd() *cobra.Command {
 
 func newOnceDeleteCmd() *cobra.Command {
 	var timeout time.Duration
+	var wait bool
 
 	cmd := &cobra.Command{
 		Use:   "delete <once-name>",
@@ -192,12 +319,17 @@ func newOnceDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Deleted once", zap.String("once", name))
+			if err := waitForDeletion(ctx, client, &syncv1.Once{}, name, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted once"), zap.String("once", name))
 			return nil
 		},
 	}
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for operation")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the once is gone")
 
 	return cmd
 }
@@ -2,11 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -19,6 +22,7 @@ func setupOnceTest(t *testing.T, objects ...runtime.Object) func() {
 	k8sClient = fake.NewClientBuilder().
 		WithScheme(scheme).
 		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.Once{}).
 		Build()
 	namespace = "default"
 
@@ -148,3 +152,131 @@ func TestOnceListCmd_Empty(t *testing.T) {
 	err := cmd.Execute()
 	require.NoError(t, err)
 }
+
+func TestOnceStatusCmd(t *testing.T) {
+	executedAt := metav1.Now()
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "default",
+		},
+		Status: syncv1.OnceStatus{
+			Executed:   true,
+			Executor:   "pod-1",
+			ExecutedAt: &executedAt,
+			Phase:      syncv1.OncePhaseExecuted,
+		},
+	}
+
+	defer setupOnceTest(t, once)()
+
+	cmd := newOnceStatusCmd()
+	cmd.SetArgs([]string{"test-once"})
+
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "pod-1")
+	assert.Contains(t, output, "Executed")
+}
+
+func TestOnceStatusCmd_NotFound(t *testing.T) {
+	defer setupOnceTest(t)()
+
+	cmd := newOnceStatusCmd()
+	cmd.SetArgs([]string{"missing"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
+
+func TestOnceDoCmd(t *testing.T) {
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "default",
+		},
+		Status: syncv1.OnceStatus{
+			Phase: syncv1.OncePhasePending,
+		},
+	}
+
+	defer setupOnceTest(t, once)()
+
+	cmd := newOnceDoCmd()
+	cmd.SetArgs([]string{"test-once", "--executor", "worker-1"})
+
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Once executed by this invocation")
+
+	var updated syncv1.Once
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{
+		Name: "test-once", Namespace: "default",
+	}, &updated))
+	assert.True(t, updated.Status.Executed)
+	assert.Equal(t, "worker-1", updated.Status.Executor)
+	assert.Equal(t, syncv1.OncePhaseExecuted, updated.Status.Phase)
+
+	cmd = newOnceDoCmd()
+	cmd.SetArgs([]string{"test-once", "--executor", "worker-2"})
+
+	output, err = executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Once was already executed")
+
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{
+		Name: "test-once", Namespace: "default",
+	}, &updated))
+	assert.Equal(t, "worker-1", updated.Status.Executor)
+}
+
+func TestOnceDoCmd_Result(t *testing.T) {
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "default",
+		},
+		Status: syncv1.OnceStatus{
+			Phase: syncv1.OncePhasePending,
+		},
+	}
+
+	defer setupOnceTest(t, once)()
+
+	cmd := newOnceDoCmd()
+	cmd.SetArgs([]string{"test-once", "--executor", "worker-1", "--result", "snapshot-2024-01-15"})
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+
+	var updated syncv1.Once
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{
+		Name: "test-once", Namespace: "default",
+	}, &updated))
+	assert.Equal(t, "snapshot-2024-01-15", updated.Status.Result)
+
+	cmd = newOnceStatusCmd()
+	cmd.SetArgs([]string{"test-once"})
+
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "snapshot-2024-01-15")
+}
+
+func TestOnceDoCmd_NotFound(t *testing.T) {
+	defer setupOnceTest(t)()
+
+	cmd := newOnceDoCmd()
+	cmd.SetArgs([]string{"missing"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
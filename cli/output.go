@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputTable = "table"
+	outputText  = "text"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// validateOutputFormat rejects unknown values of the --output flag
+func validateOutputFormat() error {
+	switch strings.ToLower(outputFormat) {
+	case "", outputTable, outputText, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q (must be one of: %s, %s, %s)", outputFormat, outputTable, outputJSON, outputYAML)
+	}
+}
+
+// isStructuredOutput reports whether list and status commands should print a
+// machine-readable document instead of log lines
+func isStructuredOutput() bool {
+	switch strings.ToLower(outputFormat) {
+	case outputJSON, outputYAML:
+		return true
+	default:
+		return false
+	}
+}
+
+// printStructured writes v to w as JSON or YAML according to the output format
+func printStructured(w io.Writer, v interface{}) error {
+	if strings.ToLower(outputFormat) == outputYAML {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal output as yaml: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output as json: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// printStructuredList writes items as a JSON or YAML array, emitting an empty
+// array rather than null when there are no items
+func printStructuredList[T any](w io.Writer, items []T) error {
+	if items == nil {
+		items = []T{}
+	}
+	return printStructured(w, items)
+}
+
+// ownerStatus is the structured form of the `owner` commands
+type ownerStatus struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder,omitempty"`
+	Since  string `json:"since,omitempty"`
+}
+
+// formatSince formats when a holder took a primitive, or returns an empty
+// string when it is not held
+func formatSince(since time.Time) string {
+	if since.IsZero() {
+		return ""
+	}
+	return since.Format(time.RFC3339)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 	"github.com/LogicIQ/konductor/sdk/go/rwmutex"
 )
@@ -23,13 +24,18 @@ func newRWMutexCmd() *cobra.Command {
 	cmd.AddCommand(newRWMutexLockCmd())
 	cmd.AddCommand(newRWMutexUnlockCmd())
 	cmd.AddCommand(newRWMutexListCmd())
+	cmd.AddCommand(newRWMutexOwnersCmd())
+	cmd.AddCommand(newRWMutexBreakCmd())
+
+	registerNameCompletion(cmd, &syncv1.RWMutexList{})
 
 	return cmd
 }
 
-func rwmutexLockHelper(cmd *cobra.Command, args []string, holder string, timeout time.Duration, lockFn func(*konductor.Client, interface{}, string, ...konductor.Option) (*rwmutex.RWMutex, error), logMsg string) error {
+func rwmutexLockHelper(cmd *cobra.Command, args []string, holder string, timeout time.Duration, lockFn func(*konductor.Client, interface{}, string, ...konductor.Option) (*rwmutex.RWMutex, error), lockKind string) error {
 	name := args[0]
-	ctx := cmd.Context()
+	ctx, stop := interruptContext(cmd.Context())
+	defer stop()
 
 	var err error
 	holder, err = validateHolder(holder)
@@ -37,7 +43,7 @@ func rwmutexLockHelper(cmd *cobra.Command, args []string, holder string, timeout
 		return err
 	}
 
-	client := konductor.NewFromClient(k8sClient, namespace)
+	client := konductor.NewFromClient(kubeClient(), namespace)
 
 	opts := []konductor.Option{
 		konductor.WithHolder(holder),
@@ -46,12 +52,19 @@ func rwmutexLockHelper(cmd *cobra.Command, args []string, holder string, timeout
 		opts = append(opts, konductor.WithTimeout(timeout))
 	}
 
+	if skipForDryRun("acquire "+lockKind+" lock", zap.String("rwmutex", name), zap.String("holder", holder)) {
+		return nil
+	}
+
 	rwm, err := lockFn(client, ctx, name, opts...)
 	if err != nil {
 		return err
 	}
+	if err := releaseIfInterrupted(ctx, "rwmutex", name, rwm.Unlock); err != nil {
+		return err
+	}
 
-	logger.Info(logMsg, zap.String("rwmutex", name), zap.String("holder", rwm.Holder()))
+	logger.Info("Acquired "+lockKind+" lock", zap.String("rwmutex", name), zap.String("holder", rwm.Holder()))
 	return nil
 }
 
@@ -73,12 +86,12 @@ func newRWMutexRLockCmd() *cobra.Command {
 					Err() error
 					Value(interface{}) interface{}
 				}), name, opts...)
-			}, "Acquired read lock")
+			}, "read")
 		},
 	}
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for waiting (e.g., 30s, 5m)")
-	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to the detected holder)")
 
 	return cmd
 }
@@ -101,12 +114,12 @@ func newRWMutexLockCmd() *cobra.Command {
 					Err() error
 					Value(interface{}) interface{}
 				}), name, opts...)
-			}, "Acquired write lock")
+			}, "write")
 		},
 	}
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for waiting (e.g., 30s, 5m)")
-	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to the detected holder)")
 
 	return cmd
 }
@@ -128,7 +141,11 @@ func newRWMutexUnlockCmd() *cobra.Command {
 				return err
 			}
 
-			client := konductor.NewFromClient(k8sClient, namespace)
+			client := konductor.NewFromClient(kubeClient(), namespace)
+
+			if skipForDryRun("release lock", zap.String("rwmutex", name), zap.String("holder", holder)) {
+				return nil
+			}
 
 			if err := rwmutex.Unlock(client, ctx, name, holder); err != nil {
 				return err
@@ -139,25 +156,36 @@ func newRWMutexUnlockCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Lock holder identifier (defaults to the detected holder)")
 
 	return cmd
 }
 
 func newRWMutexListCmd() *cobra.Command {
+	var filters listFilters
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all rwmutexes",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 
-			client := konductor.NewFromClient(k8sClient, namespace)
+			client := konductor.NewFromClient(kubeClient(), namespace)
 
-			rwmutexes, err := rwmutex.List(client, ctx)
+			opts, err := filters.options()
 			if err != nil {
 				return err
 			}
 
+			rwmutexes, err := rwmutex.List(client, ctx, opts...)
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), rwmutexes)
+			}
+
 			if len(rwmutexes) == 0 {
 				logger.Info("No rwmutexes found")
 				return nil
@@ -176,6 +204,7 @@ func newRWMutexListCmd() *cobra.Command {
 
 				logger.Info("RWMutex",
 					zap.String("name", m.Name),
+					zap.String("namespace", m.Namespace),
 					zap.String("writeHolder", writeHolder),
 					zap.Int("readers", len(m.Status.ReadHolders)),
 					zap.String("phase", string(m.Status.Phase)),
@@ -187,6 +216,130 @@ func newRWMutexListCmd() *cobra.Command {
 		},
 	}
 
+	addListFlags(cmd, &filters)
+
+	return cmd
+}
+
+// rwmutexOwners is the structured form of `rwmutex owners`
+type rwmutexOwners struct {
+	Name    string   `json:"name"`
+	Writer  string   `json:"writer,omitempty"`
+	Readers []string `json:"readers"`
+	Pending string   `json:"pending,omitempty"`
+	Since   string   `json:"since,omitempty"`
+}
+
+func newRWMutexOwnersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owners <rwmutex-name>",
+		Short: "Show who holds a rwmutex",
+		Long:  "Show the write holder, read holders and pending writer of a rwmutex and since when it has been locked",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx := cmd.Context()
+
+			client := konductor.NewFromClient(kubeClient(), namespace)
+
+			holders, err := rwmutex.Owners(client, ctx, name)
+			if err != nil {
+				return err
+			}
+
+			if isStructuredOutput() {
+				readers := holders.Readers
+				if readers == nil {
+					readers = []string{}
+				}
+				return printStructured(cmd.OutOrStdout(), rwmutexOwners{
+					Name:    name,
+					Writer:  holders.Writer,
+					Readers: readers,
+					Pending: holders.Pending,
+					Since:   formatSince(holders.Since),
+				})
+			}
+
+			if holders.Writer == "" && len(holders.Readers) == 0 {
+				logger.Info("RWMutex is unlocked", zap.String("rwmutex", name))
+				return nil
+			}
+
+			fields := []zap.Field{zap.String("rwmutex", name)}
+			if holders.Writer != "" {
+				fields = append(fields, zap.String("writer", holders.Writer))
+			}
+			if len(holders.Readers) > 0 {
+				fields = append(fields, zap.Strings("readers", holders.Readers))
+			}
+			if holders.Pending != "" {
+				fields = append(fields, zap.String("pending", holders.Pending))
+			}
+			if !holders.Since.IsZero() {
+				fields = append(fields,
+					zap.String("since", formatSince(holders.Since)),
+					zap.Duration("held", time.Since(holders.Since).Truncate(time.Second)),
+				)
+			}
+			logger.Info("RWMutex owners", fields...)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newRWMutexBreakCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "break <rwmutex-name>",
+		Short: "Forcibly release every lock on a rwmutex",
+		Long: "Release the write lock and every read lock on a rwmutex, and withdraw any pending writer, " +
+			"whoever holds them, to free a lock whose holders are gone before its TTL runs out. Requires --force.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			ctx := cmd.Context()
+
+			if err := requireForce("rwmutex", name, force); err != nil {
+				return err
+			}
+
+			client := konductor.NewFromClient(kubeClient(), namespace)
+
+			if skipForDryRun("break rwmutex", zap.String("rwmutex", name)) {
+				return nil
+			}
+
+			holders, err := rwmutex.Break(client, ctx, name)
+			if err != nil {
+				return err
+			}
+
+			if holders.Writer == "" && len(holders.Readers) == 0 && holders.Pending == "" {
+				logger.Info("RWMutex is unlocked", zap.String("rwmutex", name))
+				return nil
+			}
+
+			fields := []zap.Field{zap.String("rwmutex", name)}
+			if holders.Writer != "" {
+				fields = append(fields, zap.String("writer", holders.Writer))
+			}
+			if len(holders.Readers) > 0 {
+				fields = append(fields, zap.Strings("readers", holders.Readers))
+			}
+			if holders.Pending != "" {
+				fields = append(fields, zap.String("pending", holders.Pending))
+			}
+			logger.Warn("Broke rwmutex locks", fields...)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Confirm taking the locks from their holders")
+
 	return cmd
 }
 
@@ -201,7 +354,7 @@ func newRWMutexCreateCmd() *cobra.Command {
 			name := args[0]
 			ctx := cmd.Context()
 
-			client := konductor.NewFromClient(k8sClient, namespace)
+			client := konductor.NewFromClient(kubeClient(), namespace)
 
 			var opts []konductor.Option
 			if ttl > 0 {
@@ -211,7 +364,7 @@ func newRWMutexCreateCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Created rwmutex", zap.String("rwmutex", name))
+			logger.Info(dryRunMsg("Created rwmutex"), zap.String("rwmutex", name))
 			return nil
 		},
 	}
@@ -222,6 +375,9 @@ func newRWMutexCreateCmd() *cobra.Command {
 }
 
 func newRWMutexDeleteCmd() *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "delete <rwmutex-name>",
 		Short: "Delete a rwmutex",
@@ -230,16 +386,23 @@ func newRWMutexDeleteCmd() *cobra.Command {
 			name := args[0]
 			ctx := cmd.Context()
 
-			client := konductor.NewFromClient(k8sClient, namespace)
+			client := konductor.NewFromClient(kubeClient(), namespace)
 
 			if err := rwmutex.Delete(client, ctx, name); err != nil {
 				return err
 			}
 
-			logger.Info("Deleted rwmutex", zap.String("rwmutex", name))
+			if err := waitForDeletion(ctx, client, &syncv1.RWMutex{}, name, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted rwmutex"), zap.String("rwmutex", name))
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the rwmutex is gone")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultDeleteWaitTimeout, "How long --wait blocks before giving up")
+
 	return cmd
 }
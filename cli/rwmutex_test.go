@@ -2,11 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -354,3 +358,118 @@ func TestRWMutexLockCmd_AlreadyWriteLocked(t *testing.T) {
 	err := cmd.Execute()
 	require.Error(t, err)
 }
+
+func TestRWMutexOwnersCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lockedAt := metav1.NewTime(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	rwmutexes := []runtime.Object{
+		&syncv1.RWMutex{
+			ObjectMeta: metav1.ObjectMeta{Name: "read-locked", Namespace: "default"},
+			Status: syncv1.RWMutexStatus{
+				Phase:        syncv1.RWMutexPhaseReadLocked,
+				ReadHolders:  []string{"reader-1", "reader-2"},
+				WritePending: "writer-1",
+				LockedAt:     &lockedAt,
+			},
+		},
+		&syncv1.RWMutex{
+			ObjectMeta: metav1.ObjectMeta{Name: "unlocked", Namespace: "default"},
+			Status:     syncv1.RWMutexStatus{Phase: syncv1.RWMutexPhaseUnlocked},
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rwmutexes...).
+		Build()
+	namespace = "default"
+
+	cmd := newRWMutexOwnersCmd()
+	cmd.SetArgs([]string{"read-locked"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "reader-1")
+	assert.Contains(t, output, "reader-2")
+	assert.Contains(t, output, "writer-1")
+	assert.Contains(t, output, "2024-01-15T10:30:00Z")
+
+	originalFormat := outputFormat
+	defer func() { outputFormat = originalFormat }()
+	outputFormat = "json"
+
+	cmd = newRWMutexOwnersCmd()
+	cmd.SetArgs([]string{"unlocked"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	require.NoError(t, cmd.Execute())
+	assert.JSONEq(t, `{"name": "unlocked", "readers": []}`, buf.String())
+}
+
+func TestRWMutexBreakCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lockedAt := metav1.Now()
+	rwmutex := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-rwmutex",
+			Namespace: "default",
+		},
+		Status: syncv1.RWMutexStatus{
+			Phase:        syncv1.RWMutexPhaseReadLocked,
+			ReadHolders:  []string{"reader-1", "reader-2"},
+			WritePending: "writer-1",
+			LockedAt:     &lockedAt,
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rwmutex).
+		WithStatusSubresource(&syncv1.RWMutex{}).
+		Build()
+	namespace = "default"
+
+	cmd := newRWMutexBreakCmd()
+	cmd.SetArgs([]string{"test-rwmutex"})
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pass --force to confirm")
+
+	cmd = newRWMutexBreakCmd()
+	cmd.SetArgs([]string{"test-rwmutex", "--force"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Broke rwmutex locks")
+	assert.Contains(t, output, "reader-2")
+	assert.Contains(t, output, "writer-1")
+
+	var current syncv1.RWMutex
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-rwmutex", Namespace: "default"}, &current))
+	assert.Equal(t, syncv1.RWMutexPhaseUnlocked, current.Status.Phase)
+	assert.Empty(t, current.Status.ReadHolders)
+	assert.Empty(t, current.Status.WritePending)
+
+	// A writer that was blocked behind the readers gets the lock now
+	cmd = newRWMutexLockCmd()
+	cmd.SetArgs([]string{"test-rwmutex", "--holder", "writer-2", "--timeout", "2s"})
+	_, err = executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-rwmutex", Namespace: "default"}, &current))
+	assert.Equal(t, "writer-2", current.Status.WriteHolder)
+
+	// Breaking an unlocked rwmutex after releasing is a no-op
+	cmd = newRWMutexUnlockCmd()
+	cmd.SetArgs([]string{"test-rwmutex", "--holder", "writer-2"})
+	_, err = executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+
+	cmd = newRWMutexBreakCmd()
+	cmd.SetArgs([]string{"test-rwmutex", "--force"})
+	output, err = executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "RWMutex is unlocked")
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"errors"
-	"os"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -25,20 +27,34 @@ func newSemaphoreCmd() *cobra.Command {
 	cmd.AddCommand(newSemaphoreAcquireCmd())
 	cmd.AddCommand(newSemaphoreReleaseCmd())
 	cmd.AddCommand(newSemaphoreListCmd())
+	cmd.AddCommand(newSemaphoreDrainCmd())
+	cmd.AddCommand(newSemaphoreSetPermitsCmd())
+
+	registerNameCompletion(cmd, &syncv1.SemaphoreList{})
 
 	return cmd
 }
 
 func createSemaphoreClient() *konductor.Client {
-	return konductor.NewFromClient(k8sClient, namespace)
+	return konductor.NewFromClient(kubeClient(), namespace)
 }
 
+const (
+	// semaphoreWaitInitial is the first window given to each acquire attempt with --wait
+	semaphoreWaitInitial = time.Second
+	// semaphoreWaitMax caps the window between "waiting" messages
+	semaphoreWaitMax = 30 * time.Second
+)
+
 func newSemaphoreAcquireCmd() *cobra.Command {
 	var (
 		timeout      time.Duration
 		ttl          time.Duration
 		holder       string
 		waitDuration time.Duration
+		priority     int32
+		count        int32
+		wait         bool
 	)
 
 	cmd := &cobra.Command{
@@ -47,10 +63,20 @@ func newSemaphoreAcquireCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			semaphoreName := args[0]
-			ctx := cmd.Context()
+			ctx, stop := interruptContext(cmd.Context())
+			defer stop()
+
+			if count < 1 {
+				return errors.New("--count must be at least 1")
+			}
 
 			client := createSemaphoreClient()
 
+			holder, err := validateHolder(holder)
+			if err != nil {
+				return err
+			}
+
 			// Build options
 			var opts []konductor.Option
 			if holder != "" {
@@ -59,12 +85,24 @@ func newSemaphoreAcquireCmd() *cobra.Command {
 			if ttl > 0 {
 				opts = append(opts, konductor.WithTTL(ttl))
 			}
-			if timeout > 0 {
-				opts = append(opts, konductor.WithTimeout(timeout))
+			if priority > 0 {
+				opts = append(opts, konductor.WithPriority(priority))
+			}
+
+			if skipForDryRun("acquire permits for semaphore", zap.String("semaphore", semaphoreName), zap.Int32("count", count)) {
+				return nil
 			}
 
-			// Acquire semaphore using SDK
-			permit, err := semaphore.Acquire(client, ctx, semaphoreName, opts...)
+			var permit heldPermit
+			if wait {
+				permit, err = acquireSemaphoreWithRetry(ctx, client, semaphoreName, count, timeout, opts)
+			} else {
+				if timeout > 0 {
+					opts = append(opts, konductor.WithTimeout(timeout))
+				}
+				// Acquire semaphore using SDK
+				permit, err = acquirePermits(ctx, client, semaphoreName, count, opts)
+			}
 			if err != nil {
 				return err
 			}
@@ -74,10 +112,16 @@ func newSemaphoreAcquireCmd() *cobra.Command {
 				select {
 				case <-time.After(waitDuration):
 				case <-ctx.Done():
-					return ctx.Err()
 				}
 			}
+			if err := releaseIfInterrupted(ctx, "semaphore", semaphoreName, permit.Release); err != nil {
+				return err
+			}
 
+			if count > 1 {
+				logger.Info("Acquired permits for semaphore", zap.String("semaphore", semaphoreName), zap.String("holder", permit.Holder()), zap.Int32("count", count))
+				return nil
+			}
 			logger.Info("Acquired permit for semaphore", zap.String("semaphore", semaphoreName), zap.String("holder", permit.Holder()))
 			return nil
 		},
@@ -85,12 +129,81 @@ func newSemaphoreAcquireCmd() *cobra.Command {
 
 	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for waiting (e.g., 30s, 5m)")
 	cmd.Flags().DurationVar(&ttl, "ttl", 10*time.Minute, "Time-to-live for the permit")
-	cmd.Flags().StringVar(&holder, "holder", "", "Permit holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Permit holder identifier (defaults to the detected holder)")
 	cmd.Flags().DurationVar(&waitDuration, "wait-duration", 0, "Duration to wait for controller to process (e.g., 3s)")
+	cmd.Flags().Int32Var(&priority, "priority", 0, "Priority for permit acquisition (higher wins)")
+	cmd.Flags().Int32Var(&count, "count", 1, "Number of permits to acquire together, all or none")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until a permit is granted, --timeout elapses or the command is interrupted")
 
 	return cmd
 }
 
+// heldPermit is what acquire reports about the permits it was granted, and
+// releases them if the command is interrupted
+type heldPermit interface {
+	Holder() string
+	Release(ctx context.Context) error
+}
+
+// acquirePermits acquires count permits on the named semaphore, all or none
+// when count is more than one
+func acquirePermits(ctx context.Context, client *konductor.Client, name string, count int32, opts []konductor.Option) (heldPermit, error) {
+	if count > 1 {
+		permits, err := semaphore.AcquireN(client, ctx, name, count, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return permits, nil
+	}
+
+	permit, err := semaphore.Acquire(client, ctx, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return permit, nil
+}
+
+// acquireSemaphoreWithRetry keeps trying to acquire count permits until they
+// are granted, giving each attempt a longer window up to semaphoreWaitMax and
+// logging between attempts. A zero timeout waits until ctx is cancelled.
+func acquireSemaphoreWithRetry(ctx context.Context, client *konductor.Client, name string, count int32, timeout time.Duration, opts []konductor.Option) (heldPermit, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	window := semaphoreWaitInitial
+	for attempt := 1; ; attempt++ {
+		attemptOpts := append(append([]konductor.Option{}, opts...), konductor.WithTimeout(window))
+		permit, err := acquirePermits(ctx, client, name, count, attemptOpts)
+		if err == nil {
+			return permit, nil
+		}
+
+		switch {
+		case timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, fmt.Errorf("timed out after %s waiting for a permit on semaphore %s: %w", timeout, name, konductor.ErrAcquireTimeout)
+		case ctx.Err() != nil:
+			return nil, fmt.Errorf("stopped waiting for a permit on semaphore %s: %w", name, ctx.Err())
+		case !errors.Is(err, konductor.ErrAcquireTimeout):
+			return nil, err
+		}
+
+		logger.Info("Waiting for semaphore permit",
+			zap.String("semaphore", name),
+			zap.Int("attempt", attempt),
+			zap.Duration("elapsed", time.Since(start).Round(time.Second)),
+		)
+
+		window *= 2
+		if window > semaphoreWaitMax {
+			window = semaphoreWaitMax
+		}
+	}
+}
+
 func newSemaphoreReleaseCmd() *cobra.Command {
 	var holder string
 
@@ -102,35 +215,16 @@ func newSemaphoreReleaseCmd() *cobra.Command {
 			semaphoreName := args[0]
 			ctx := cmd.Context()
 
-			if holder == "" {
-				if holder = os.Getenv("HOSTNAME"); holder == "" {
-					return errors.New("holder must be specified or HOSTNAME must be set")
-				}
-			}
-
-			client := createSemaphoreClient()
-
-			// Find and release permit by holder
-			// Note: Currently fetches all permits due to SDK limitations.
-			// TODO: Request SDK enhancement for direct permit lookup by holder.
-			permits, err := client.ListPermits(ctx, semaphoreName)
+			holder, err := validateHolder(holder)
 			if err != nil {
 				return err
 			}
 
-			var permitToDelete *syncv1.Permit
-			for i := range permits {
-				if permits[i].Spec.Holder == holder {
-					permitToDelete = &permits[i]
-					break
-				}
-			}
-
-			if permitToDelete == nil {
-				return errors.New("no permit found for holder: " + holder)
+			if skipForDryRun("release permit for semaphore", zap.String("semaphore", semaphoreName), zap.String("holder", holder)) {
+				return nil
 			}
 
-			if err := client.K8sClient().Delete(ctx, permitToDelete); err != nil {
+			if err := releaseSemaphorePermit(ctx, createSemaphoreClient(), semaphoreName, holder); err != nil {
 				return err
 			}
 
@@ -139,12 +233,36 @@ func newSemaphoreReleaseCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&holder, "holder", "", "Permit holder identifier (defaults to hostname)")
+	cmd.Flags().StringVar(&holder, "holder", "", "Permit holder identifier (defaults to the detected holder)")
 
 	return cmd
 }
 
+// errNoPermit is returned when releasing a permit the holder does not have
+var errNoPermit = errors.New("no permit found for holder")
+
+// releaseSemaphorePermit deletes the permit holder has on the named semaphore
+func releaseSemaphorePermit(ctx context.Context, client *konductor.Client, name, holder string) error {
+	// Find and release permit by holder
+	// Note: Currently fetches all permits due to SDK limitations.
+	// TODO: Request SDK enhancement for direct permit lookup by holder.
+	permits, err := client.ListPermits(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for i := range permits {
+		if permits[i].Spec.Holder == holder {
+			return client.K8sClient().Delete(ctx, &permits[i])
+		}
+	}
+
+	return fmt.Errorf("%w: %s", errNoPermit, holder)
+}
+
 func newSemaphoreListCmd() *cobra.Command {
+	var filters listFilters
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all semaphores",
@@ -153,12 +271,21 @@ func newSemaphoreListCmd() *cobra.Command {
 
 			client := createSemaphoreClient()
 
+			opts, err := filters.options()
+			if err != nil {
+				return err
+			}
+
 			// List semaphores using SDK
-			semaphores, err := semaphore.List(client, ctx)
+			semaphores, err := semaphore.List(client, ctx, opts...)
 			if err != nil {
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), semaphores)
+			}
+
 			if len(semaphores) == 0 {
 				logger.Info("No semaphores found")
 				return nil
@@ -167,6 +294,7 @@ func newSemaphoreListCmd() *cobra.Command {
 			for _, sem := range semaphores {
 				logger.Info("Semaphore",
 					zap.String("name", sem.Name),
+					zap.String("namespace", sem.Namespace),
 					zap.Int32("permits", sem.Spec.Permits),
 					zap.Int32("in-use", sem.Status.InUse),
 					zap.Int32("available", sem.Status.Available),
@@ -178,6 +306,8 @@ func newSemaphoreListCmd() *cobra.Command {
 		},
 	}
 
+	addListFlags(cmd, &filters)
+
 	return cmd
 }
 
@@ -209,7 +339,7 @@ func newSemaphoreCreateCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Created semaphore", zap.String("semaphore", semaphoreName), zap.Int32("permits", permits))
+			logger.Info(dryRunMsg("Created semaphore"), zap.String("semaphore", semaphoreName), zap.Int32("permits", permits))
 			return nil
 		},
 	}
@@ -221,6 +351,9 @@ func newSemaphoreCreateCmd() *cobra.Command {
 }
 
 func newSemaphoreDeleteCmd() *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "delete <semaphore-name>",
 		Short: "Delete a semaphore",
@@ -235,7 +368,84 @@ func newSemaphoreDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Deleted semaphore", zap.String("semaphore", semaphoreName))
+			if err := waitForDeletion(ctx, client, &syncv1.Semaphore{}, semaphoreName, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted semaphore"), zap.String("semaphore", semaphoreName))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the semaphore and its permits are gone")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultDeleteWaitTimeout, "How long --wait blocks before giving up")
+
+	return cmd
+}
+
+func newSemaphoreDrainCmd() *cobra.Command {
+	var on, off bool
+
+	cmd := &cobra.Command{
+		Use:   "drain <semaphore-name>",
+		Short: "Turn drain mode on or off for a semaphore",
+		Long:  "While a semaphore is draining, current holders keep their permits but no new ones are granted, so it empties for maintenance. Requests made meanwhile wait until drain is turned off.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			semaphoreName := args[0]
+			ctx := cmd.Context()
+
+			if on == off {
+				return errors.New("exactly one of --on or --off must be specified")
+			}
+
+			client := createSemaphoreClient()
+
+			if skipForDryRun("set drain mode for semaphore", zap.String("semaphore", semaphoreName), zap.Bool("drain", on)) {
+				return nil
+			}
+
+			if err := semaphore.SetDrain(client, ctx, semaphoreName, on); err != nil {
+				return err
+			}
+
+			if on {
+				logger.Info("Draining semaphore", zap.String("semaphore", semaphoreName))
+			} else {
+				logger.Info("Stopped draining semaphore", zap.String("semaphore", semaphoreName))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&on, "on", false, "Stop granting new permits")
+	cmd.Flags().BoolVar(&off, "off", false, "Resume granting permits")
+
+	return cmd
+}
+
+func newSemaphoreSetPermitsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-permits <semaphore-name> <permits>",
+		Short: "Change how many permits a semaphore has",
+		Long:  "Resize a semaphore. Shrinking it below the permits in use revokes none of them; no new permits are granted until enough are released.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			semaphoreName := args[0]
+			ctx := cmd.Context()
+
+			permits, err := strconv.ParseInt(args[1], 10, 32)
+			if err != nil || permits <= 0 {
+				return fmt.Errorf("invalid permit count %q: must be a whole number greater than zero", args[1])
+			}
+
+			client := createSemaphoreClient()
+
+			if err := semaphore.SetPermits(client, ctx, semaphoreName, int32(permits)); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Updated semaphore"), zap.String("semaphore", semaphoreName), zap.Int64("permits", permits))
 			return nil
 		},
 	}
@@ -2,18 +2,36 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
+// grantPermits stands in for the controller, granting every permit as soon
+// as it is created
+var grantPermits = interceptor.Funcs{
+	Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+		if permit, ok := obj.(*syncv1.Permit); ok {
+			permit.Status.Phase = syncv1.PermitPhaseGranted
+		}
+		return c.Create(ctx, obj, opts...)
+	},
+}
+
 func TestSemaphoreAcquireCmd(t *testing.T) {
 	t.Skip("Skipping test that requires controller to grant permit")
 	scheme := runtime.NewScheme()
@@ -82,6 +100,172 @@ func TestSemaphoreAcquireCmd_NoPermits(t *testing.T) {
 	assert.Contains(t, err.Error(), "no permits available")
 }
 
+// setupFullSemaphore returns a fake client with a semaphore whose only permit
+// is held by other-holder
+func setupFullSemaphore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 1,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     1,
+			Available: 0,
+			Phase:     syncv1.SemaphorePhaseFull,
+		},
+	}
+
+	held := &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem-other-holder",
+			Namespace: "default",
+			Labels:    map[string]string{"semaphore": "test-sem"},
+		},
+		Spec: syncv1.PermitSpec{
+			Semaphore: "test-sem",
+			Holder:    "other-holder",
+		},
+		Status: syncv1.PermitStatus{
+			Phase: syncv1.PermitPhaseGranted,
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, held).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+	namespace = "default"
+}
+
+// freePermitAfter stands in for the controller: after delay it releases the
+// held permit, then grants the next permit requested by holder
+func freePermitAfter(ctx context.Context, delay time.Duration, holder string) {
+	time.Sleep(delay)
+
+	key := client.ObjectKey{Name: "test-sem", Namespace: "default"}
+	_ = k8sClient.Delete(ctx, &syncv1.Permit{ObjectMeta: metav1.ObjectMeta{Name: "test-sem-other-holder", Namespace: "default"}})
+
+	var sem syncv1.Semaphore
+	if err := k8sClient.Get(ctx, key, &sem); err != nil {
+		return
+	}
+	sem.Status.InUse = 0
+	sem.Status.Available = 1
+	sem.Status.Phase = syncv1.SemaphorePhaseReady
+	_ = k8sClient.Status().Update(ctx, &sem)
+
+	for ctx.Err() == nil {
+		var permits syncv1.PermitList
+		if err := k8sClient.List(ctx, &permits); err == nil {
+			for i := range permits.Items {
+				permit := &permits.Items[i]
+				if permit.Spec.Holder == holder && permit.Status.Phase != syncv1.PermitPhaseGranted {
+					permit.Status.Phase = syncv1.PermitPhaseGranted
+					_ = k8sClient.Status().Update(ctx, permit)
+					return
+				}
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSemaphoreAcquireCmd_WaitUntilFreed(t *testing.T) {
+	setupFullSemaphore(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go freePermitAfter(ctx, 1500*time.Millisecond, "test-holder")
+
+	cmd := newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--holder", "test-holder", "--wait", "--timeout", "20s"})
+
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Waiting for semaphore permit")
+	assert.Contains(t, output, "Acquired permit for semaphore")
+
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "test-holder", permits.Items[0].Spec.Holder)
+	assert.Equal(t, syncv1.PermitPhaseGranted, permits.Items[0].Status.Phase)
+}
+
+func TestSemaphoreAcquireCmd_WaitTimeout(t *testing.T) {
+	setupFullSemaphore(t)
+
+	cmd := newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--holder", "test-holder", "--wait", "--timeout", "1500ms"})
+
+	start := time.Now()
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrAcquireTimeout))
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// No permit is left behind for the timed out request
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "other-holder", permits.Items[0].Spec.Holder)
+}
+
+func TestSemaphoreAcquireCmd_WaitCancelled(t *testing.T) {
+	setupFullSemaphore(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+
+	cmd := newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--holder", "test-holder", "--wait"})
+	cmd.SetContext(ctx)
+
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestSemaphoreAcquireCmd_InterruptReleasesPermit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 1},
+		Status:     syncv1.SemaphoreStatus{Available: 1, Phase: syncv1.SemaphorePhaseReady},
+	}
+	k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(semaphore).WithInterceptorFuncs(grantPermits).Build()
+	namespace = "default"
+
+	// The interrupt arrives while the command holds the permit
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+
+	cmd := newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--holder", "test-holder", "--wait-duration", "30s"})
+	cmd.SetContext(ctx)
+
+	start := time.Now()
+	output, err := executeCommandWithOutputAndLogs(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Contains(t, output, "Interrupted, released semaphore")
+	assert.NotContains(t, output, "Acquired permit for semaphore")
+
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
 func TestSemaphoreReleaseCmd(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, syncv1.AddToScheme(scheme))
@@ -193,6 +377,7 @@ func TestSemaphoreCmd_DefaultHolder(t *testing.T) {
 	k8sClient = fake.NewClientBuilder().
 		WithScheme(scheme).
 		WithRuntimeObjects(semaphore).
+		WithInterceptorFuncs(grantPermits).
 		Build()
 	namespace = "default"
 
@@ -257,3 +442,222 @@ func TestSemaphoreDeleteCmd(t *testing.T) {
 
 	_ = buf.String()
 }
+
+func TestSemaphoreDeleteCmd_Wait(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default", UID: "sem-uid"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 2},
+	}
+	permit := &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem-worker",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "sync.konductor.io/v1",
+				Kind:       "Semaphore",
+				Name:       semaphore.Name,
+				UID:        semaphore.UID,
+			}},
+		},
+		Spec: syncv1.PermitSpec{Semaphore: semaphore.Name, Holder: "worker"},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, permit).
+		Build()
+	namespace = "default"
+
+	// The fake client has no garbage collector, so the permit outlives the
+	// semaphore until it is deleted here
+	cmd := newSemaphoreDeleteCmd()
+	cmd.SetArgs([]string{"test-sem", "--wait", "--timeout", "300ms"})
+	_, err := executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Contains(t, err.Error(), "1 dependents remain")
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = k8sClient.Delete(context.Background(), permit.DeepCopy())
+	}()
+
+	require.NoError(t, k8sClient.Create(context.Background(), &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default", UID: "sem-uid"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 2},
+	}))
+	cmd = newSemaphoreDeleteCmd()
+	cmd.SetArgs([]string{"test-sem", "--wait", "--timeout", "5s"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Deleted semaphore")
+
+	err = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(permit), &syncv1.Permit{})
+	assert.True(t, apierrors.IsNotFound(err), "returns only once the permit is gone")
+}
+
+func TestSemaphoreDrainCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	sem := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 2},
+		Status:     syncv1.SemaphoreStatus{InUse: 1, Available: 1, Phase: syncv1.SemaphorePhaseReady},
+	}
+	held := &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem-existing",
+			Namespace: "default",
+			Labels:    map[string]string{"semaphore": "test-sem"},
+		},
+		Spec:   syncv1.PermitSpec{Semaphore: "test-sem", Holder: "existing"},
+		Status: syncv1.PermitStatus{Phase: syncv1.PermitPhaseGranted},
+	}
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(sem, held).
+		Build()
+	namespace = "default"
+
+	cmd := newSemaphoreDrainCmd()
+	cmd.SetArgs([]string{"test-sem", "--on"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Draining semaphore")
+
+	var result syncv1.Semaphore
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-sem", Namespace: "default"}, &result))
+	assert.True(t, result.Spec.Drain)
+
+	// New acquisitions are refused while the existing permit is kept
+	acquire := newSemaphoreAcquireCmd()
+	acquire.SetArgs([]string{"test-sem", "--holder", "newcomer"})
+	_, err = executeCommandWithOutput(t, acquire)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrDraining))
+
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "existing", permits.Items[0].Spec.Holder)
+
+	cmd = newSemaphoreDrainCmd()
+	cmd.SetArgs([]string{"test-sem", "--off"})
+	output, err = executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Stopped draining semaphore")
+
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-sem", Namespace: "default"}, &result))
+	assert.False(t, result.Spec.Drain)
+}
+
+func TestSemaphoreDrainCmd_RequiresOneMode(t *testing.T) {
+	for _, args := range [][]string{{"test-sem"}, {"test-sem", "--on", "--off"}} {
+		cmd := newSemaphoreDrainCmd()
+		cmd.SetArgs(args)
+		_, err := executeCommandWithOutput(t, cmd)
+		assert.ErrorContains(t, err, "exactly one of --on or --off")
+	}
+}
+
+func TestSemaphoreSetPermitsCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	sem := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 2},
+	}
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(sem).
+		Build()
+	namespace = "default"
+
+	cmd := newSemaphoreSetPermitsCmd()
+	cmd.SetArgs([]string{"test-sem", "5"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Updated semaphore")
+
+	var result syncv1.Semaphore
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-sem", Namespace: "default"}, &result))
+	assert.Equal(t, int32(5), result.Spec.Permits)
+
+	for _, permits := range []string{"0", "two", "3000000000"} {
+		cmd := newSemaphoreSetPermitsCmd()
+		cmd.SetArgs([]string{"test-sem", permits})
+		_, err := executeCommandWithOutput(t, cmd)
+		assert.ErrorContains(t, err, "invalid permit count")
+	}
+}
+
+func TestSemaphoreAcquireCmd_Count(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	sem := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 3},
+		Status:     syncv1.SemaphoreStatus{Available: 3, Phase: syncv1.SemaphorePhaseReady},
+	}
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(sem).
+		WithInterceptorFuncs(grantPermits).
+		Build()
+	namespace = "default"
+
+	cmd := newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--holder", "test-holder", "--count", "2"})
+	output, err := executeCommandWithOutput(t, cmd)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Acquired permits for semaphore")
+
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &permits))
+	require.Len(t, permits.Items, 2)
+	for _, p := range permits.Items {
+		assert.Equal(t, "test-holder", p.Spec.Holder)
+	}
+}
+
+func TestSemaphoreAcquireCmd_CountTimeout(t *testing.T) {
+	setupFullSemaphore(t)
+
+	// The semaphore only has one permit, so --count 2 is refused outright
+	cmd := newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--holder", "test-holder", "--count", "2", "--timeout", "300ms"})
+	_, err := executeCommandWithOutput(t, cmd)
+	assert.ErrorContains(t, err, "only has 1")
+
+	var sem syncv1.Semaphore
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "test-sem", Namespace: "default"}, &sem))
+	sem.Spec.Permits = 3
+	require.NoError(t, k8sClient.Update(context.Background(), &sem))
+	sem.Status.Available = 1
+	require.NoError(t, k8sClient.Status().Update(context.Background(), &sem))
+
+	// One permit is free, but two are asked for
+	cmd = newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--holder", "test-holder", "--count", "2", "--timeout", "300ms"})
+	_, err = executeCommandWithOutput(t, cmd)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrAcquireTimeout))
+
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "other-holder", permits.Items[0].Spec.Holder)
+}
+
+func TestSemaphoreAcquireCmd_InvalidCount(t *testing.T) {
+	cmd := newSemaphoreAcquireCmd()
+	cmd.SetArgs([]string{"test-sem", "--count", "0"})
+	_, err := executeCommandWithOutput(t, cmd)
+	assert.ErrorContains(t, err, "--count must be at least 1")
+}
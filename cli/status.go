@@ -1,22 +1,33 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	"github.com/LogicIQ/konductor/sdk/go/barrier"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/event"
 	"github.com/LogicIQ/konductor/sdk/go/gate"
 	"github.com/LogicIQ/konductor/sdk/go/lease"
+	"github.com/LogicIQ/konductor/sdk/go/mutex"
+	"github.com/LogicIQ/konductor/sdk/go/once"
+	"github.com/LogicIQ/konductor/sdk/go/rwmutex"
 	"github.com/LogicIQ/konductor/sdk/go/semaphore"
+	"github.com/LogicIQ/konductor/sdk/go/waitgroup"
 )
 
 func newStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show status of coordination primitives",
-		Long:  "Display detailed status information for semaphores, barriers, leases, and gates",
+		Long:  "Display detailed status information for coordination primitives",
 	}
 
 	cmd.AddCommand(newStatusSemaphoreCmd())
@@ -32,11 +43,103 @@ func createStatusClient() *konductor.Client {
 	return konductor.NewFromClient(k8sClient, namespace)
 }
 
+// semaphoreStatus is the structured form of `status semaphore`
+type semaphoreStatus struct {
+	Semaphore *syncv1.Semaphore `json:"semaphore"`
+	Permits   []syncv1.Permit   `json:"permits"`
+}
+
+// leaseStatus is the structured form of `status lease`
+type leaseStatus struct {
+	Lease           *syncv1.Lease         `json:"lease"`
+	PendingRequests []syncv1.LeaseRequest `json:"pendingRequests"`
+}
+
+// statusTypes are the primitive types `status all` reports on, in the order
+// they are printed
+var statusTypes = []string{"semaphore", "barrier", "lease", "gate", "mutex", "rwmutex", "once", "waitgroup", "event"}
+
+// StatusSummary is the machine-readable form of `status all`. Counts holds the
+// number of objects of each reported type; types filtered out with --type are
+// left out entirely. Errors holds, by type, why listing a type failed, and
+// such a type has no count.
+type StatusSummary struct {
+	Namespace  string             `json:"namespace"`
+	Counts     map[string]int     `json:"counts"`
+	Errors     map[string]string  `json:"errors,omitempty"`
+	Semaphores []semaphoreSummary `json:"semaphores,omitzero"`
+	Barriers   []barrierSummary   `json:"barriers,omitzero"`
+	Leases     []leaseSummary     `json:"leases,omitzero"`
+	Gates      []gateSummary      `json:"gates,omitzero"`
+	Mutexes    []mutexSummary     `json:"mutexes,omitzero"`
+	RWMutexes  []rwmutexSummary   `json:"rwmutexes,omitzero"`
+	Onces      []onceSummary      `json:"onces,omitzero"`
+	WaitGroups []waitGroupSummary `json:"waitgroups,omitzero"`
+	Events     []eventSummary     `json:"events,omitzero"`
+}
+
+type semaphoreSummary struct {
+	Name  string `json:"name"`
+	InUse int32  `json:"inUse"`
+	Total int32  `json:"total"`
+	Phase string `json:"phase"`
+}
+
+type barrierSummary struct {
+	Name       string `json:"name"`
+	Arrived    int32  `json:"arrived"`
+	Expected   int32  `json:"expected"`
+	Phase      string `json:"phase"`
+	Generation int64  `json:"generation,omitempty"`
+}
+
+type leaseSummary struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder,omitempty"`
+	Phase  string `json:"phase"`
+}
+
+type gateSummary struct {
+	Name            string `json:"name"`
+	ConditionsMet   int    `json:"conditionsMet"`
+	ConditionsTotal int    `json:"conditionsTotal"`
+	Phase           string `json:"phase"`
+}
+
+type onceSummary struct {
+	Name     string `json:"name"`
+	Executor string `json:"executor,omitempty"`
+	Phase    string `json:"phase"`
+}
+
+type waitGroupSummary struct {
+	Name    string `json:"name"`
+	Counter int32  `json:"counter"`
+	Phase   string `json:"phase"`
+}
+
+type eventSummary struct {
+	Name       string `json:"name"`
+	SignaledBy string `json:"signaledBy,omitempty"`
+	Phase      string `json:"phase"`
+}
+
+func countMetConditions(g *syncv1.Gate) int {
+	metCount := 0
+	for _, status := range g.Status.ConditionStatuses {
+		if status.Met {
+			metCount++
+		}
+	}
+	return metCount
+}
+
 func newStatusSemaphoreCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "semaphore <name>",
-		Short: "Show semaphore status",
-		Args:  cobra.ExactArgs(1),
+		Use:               "semaphore <name>",
+		Short:             "Show semaphore status",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNames(&syncv1.SemaphoreList{}),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			ctx := cmd.Context()
@@ -48,12 +151,36 @@ func newStatusSemaphoreCmd() *cobra.Command {
 				return err
 			}
 
+			if isStructuredOutput() {
+				permits, err := client.ListPermits(ctx, name)
+				if err != nil {
+					return err
+				}
+				if permits == nil {
+					permits = []syncv1.Permit{}
+				}
+				return printStructured(cmd.OutOrStdout(), semaphoreStatus{Semaphore: sem, Permits: permits})
+			}
+
+			lastFullAt := "N/A"
+			if sem.Status.LastFullAt != nil {
+				lastFullAt = sem.Status.LastFullAt.Format(time.RFC3339)
+			}
+			longestWait := "N/A"
+			if sem.Status.LongestWait != nil {
+				longestWait = sem.Status.LongestWait.Duration.String()
+			}
+
 			logger.Info("Semaphore status",
 				zap.String("name", sem.Name),
 				zap.String("namespace", sem.Namespace),
 				zap.Int32("permits_total", sem.Spec.Permits),
 				zap.Int32("permits_in_use", sem.Status.InUse),
 				zap.Int32("permits_available", sem.Status.Available),
+				zap.Int32("pending", sem.Status.Pending),
+				zap.String("longest_wait", longestWait),
+				zap.Int32("max_in_use", sem.Status.MaxInUse),
+				zap.String("last_full_at", lastFullAt),
 				zap.String("phase", string(sem.Status.Phase)),
 			)
 
@@ -83,9 +210,10 @@ func newStatusSemaphoreCmd() *cobra.Command {
 
 func newStatusBarrierCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "barrier <name>",
-		Short: "Show barrier status",
-		Args:  cobra.ExactArgs(1),
+		Use:               "barrier <name>",
+		Short:             "Show barrier status",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNames(&syncv1.BarrierList{}),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			ctx := cmd.Context()
@@ -97,6 +225,10 @@ func newStatusBarrierCmd() *cobra.Command {
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructured(cmd.OutOrStdout(), bar)
+			}
+
 			fields := []zap.Field{
 				zap.String("name", bar.Name),
 				zap.String("namespace", bar.Namespace),
@@ -130,9 +262,10 @@ func newStatusBarrierCmd() *cobra.Command {
 
 func newStatusLeaseCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "lease <name>",
-		Short: "Show lease status",
-		Args:  cobra.ExactArgs(1),
+		Use:               "lease <name>",
+		Short:             "Show lease status",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNames(&syncv1.LeaseList{}),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			ctx := cmd.Context()
@@ -144,6 +277,20 @@ func newStatusLeaseCmd() *cobra.Command {
 				return err
 			}
 
+			if isStructuredOutput() {
+				requests, err := client.ListLeaseRequests(ctx, name)
+				if err != nil {
+					return err
+				}
+				pending := []syncv1.LeaseRequest{}
+				for _, req := range requests {
+					if req.Status.Phase == syncv1.LeaseRequestPhasePending {
+						pending = append(pending, req)
+					}
+				}
+				return printStructured(cmd.OutOrStdout(), leaseStatus{Lease: l, PendingRequests: pending})
+			}
+
 			fields := []zap.Field{
 				zap.String("name", l.Name),
 				zap.String("namespace", l.Namespace),
@@ -192,9 +339,10 @@ func newStatusLeaseCmd() *cobra.Command {
 
 func newStatusGateCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "gate <name>",
-		Short: "Show gate status",
-		Args:  cobra.ExactArgs(1),
+		Use:               "gate <name>",
+		Short:             "Show gate status",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNames(&syncv1.GateList{}),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 			ctx := cmd.Context()
@@ -206,6 +354,10 @@ func newStatusGateCmd() *cobra.Command {
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructured(cmd.OutOrStdout(), g)
+			}
+
 			fields := []zap.Field{
 				zap.String("name", g.Name),
 				zap.String("namespace", g.Namespace),
@@ -253,91 +405,336 @@ func newStatusGateCmd() *cobra.Command {
 }
 
 func newStatusAllCmd() *cobra.Command {
+	var types []string
+
 	cmd := &cobra.Command{
 		Use:   "all",
 		Short: "Show status of all coordination primitives",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			client := createStatusClient()
-
-			logger.Info("Konductor Status Overview")
-
-			// List semaphores using SDK
-			semaphores, err := semaphore.List(client, ctx)
+			summary, err := buildStatusSummary(cmd.Context(), createStatusClient(), types)
 			if err != nil {
-				logger.Warn("Failed to list semaphores", zap.Error(err))
-			} else {
-				logger.Info("Semaphores", zap.Int("count", len(semaphores)))
-				for _, sem := range semaphores {
-					logger.Info("Semaphore",
-						zap.String("name", sem.Name),
-						zap.Int32("in_use", sem.Status.InUse),
-						zap.Int32("total", sem.Spec.Permits),
-						zap.String("phase", string(sem.Status.Phase)),
-					)
-				}
+				return err
 			}
 
-			// List barriers using SDK
-			barriers, err := barrier.List(client, ctx)
-			if err != nil {
-				logger.Warn("Failed to list barriers", zap.Error(err))
-			} else {
-				logger.Info("Barriers", zap.Int("count", len(barriers)))
-				for _, b := range barriers {
-					logger.Info("Barrier",
-						zap.String("name", b.Name),
-						zap.Int32("arrived", b.Status.Arrived),
-						zap.Int32("expected", b.Spec.Expected),
-						zap.String("phase", string(b.Status.Phase)),
-					)
+			if isStructuredOutput() {
+				if err := printStructured(cmd.OutOrStdout(), summary); err != nil {
+					return err
 				}
+			} else {
+				printStatusSummary(summary)
 			}
 
-			// List leases using SDK
-			leases, err := lease.List(client, ctx)
-			if err != nil {
-				logger.Warn("Failed to list leases", zap.Error(err))
-			} else {
-				logger.Info("Leases", zap.Int("count", len(leases)))
-				for _, l := range leases {
-					holder := "Available"
-					if l.Status.Holder != "" {
-						holder = l.Status.Holder
-					}
-					logger.Info("Lease",
-						zap.String("name", l.Name),
-						zap.String("holder", holder),
-						zap.String("phase", string(l.Status.Phase)),
-					)
-				}
+			// The types that listed are shown, but the command still fails
+			return statusListError(summary)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&types, "type", nil, "Only report these primitive types, e.g. semaphore,lease (default all)")
+
+	return cmd
+}
+
+// buildStatusSummary lists every primitive of the given types, or of all
+// types when none are given, and summarizes them. A type that fails to list
+// is recorded in the summary's Errors, so one missing CRD or RBAC rule does
+// not hide the types that did list.
+func buildStatusSummary(ctx context.Context, client *konductor.Client, types []string) (*StatusSummary, error) {
+	selected, err := selectStatusTypes(types)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &StatusSummary{
+		Namespace: client.Namespace(),
+		Counts:    map[string]int{},
+		Errors:    map[string]string{},
+	}
+
+	// listed records why listing type t failed, reporting whether it succeeded
+	listed := func(t string, err error) bool {
+		if err != nil {
+			summary.Errors[t] = err.Error()
+			return false
+		}
+		return true
+	}
+
+	if selected["semaphore"] {
+		if semaphores, err := semaphore.List(client, ctx); listed("semaphore", err) {
+			summary.Semaphores = []semaphoreSummary{}
+			for _, sem := range semaphores {
+				summary.Semaphores = append(summary.Semaphores, semaphoreSummary{
+					Name:  sem.Name,
+					InUse: sem.Status.InUse,
+					Total: sem.Spec.Permits,
+					Phase: string(sem.Status.Phase),
+				})
 			}
+			summary.Counts["semaphore"] = len(semaphores)
+		}
+	}
 
-			// List gates using SDK
-			gates, err := gate.List(client, ctx)
-			if err != nil {
-				logger.Warn("Failed to list gates", zap.Error(err))
-			} else {
-				logger.Info("Gates", zap.Int("count", len(gates)))
-				for _, g := range gates {
-					metCount := 0
-					for _, status := range g.Status.ConditionStatuses {
-						if status.Met {
-							metCount++
-						}
-					}
-					logger.Info("Gate",
-						zap.String("name", g.Name),
-						zap.Int("conditions_met", metCount),
-						zap.Int("conditions_total", len(g.Spec.Conditions)),
-						zap.String("phase", string(g.Status.Phase)),
-					)
-				}
+	if selected["barrier"] {
+		if barriers, err := barrier.List(client, ctx); listed("barrier", err) {
+			summary.Barriers = []barrierSummary{}
+			for _, b := range barriers {
+				summary.Barriers = append(summary.Barriers, barrierSummary{
+					Name:       b.Name,
+					Arrived:    b.Status.Arrived,
+					Expected:   b.Spec.Expected,
+					Phase:      string(b.Status.Phase),
+					Generation: b.Status.Generation,
+				})
 			}
+			summary.Counts["barrier"] = len(barriers)
+		}
+	}
 
-			return nil
-		},
+	if selected["lease"] {
+		if leases, err := lease.List(client, ctx); listed("lease", err) {
+			summary.Leases = []leaseSummary{}
+			for _, l := range leases {
+				summary.Leases = append(summary.Leases, leaseSummary{
+					Name:   l.Name,
+					Holder: l.Status.Holder,
+					Phase:  string(l.Status.Phase),
+				})
+			}
+			summary.Counts["lease"] = len(leases)
+		}
 	}
 
-	return cmd
+	if selected["gate"] {
+		if gates, err := gate.List(client, ctx); listed("gate", err) {
+			summary.Gates = []gateSummary{}
+			for i := range gates {
+				summary.Gates = append(summary.Gates, gateSummary{
+					Name:            gates[i].Name,
+					ConditionsMet:   countMetConditions(&gates[i]),
+					ConditionsTotal: len(gates[i].Spec.Conditions),
+					Phase:           string(gates[i].Status.Phase),
+				})
+			}
+			summary.Counts["gate"] = len(gates)
+		}
+	}
+
+	if selected["mutex"] {
+		if mutexes, err := mutex.List(client, ctx); listed("mutex", err) {
+			summary.Mutexes = []mutexSummary{}
+			for _, m := range mutexes {
+				summary.Mutexes = append(summary.Mutexes, mutexSummary{
+					Name:   m.Name,
+					Holder: m.Status.Holder,
+					Phase:  string(m.Status.Phase),
+				})
+			}
+			summary.Counts["mutex"] = len(mutexes)
+		}
+	}
+
+	if selected["rwmutex"] {
+		if rwmutexes, err := rwmutex.List(client, ctx); listed("rwmutex", err) {
+			summary.RWMutexes = []rwmutexSummary{}
+			for _, rw := range rwmutexes {
+				summary.RWMutexes = append(summary.RWMutexes, rwmutexSummary{
+					Name:        rw.Name,
+					WriteHolder: rw.Status.WriteHolder,
+					ReadHolders: rw.Status.ReadHolders,
+					Phase:       string(rw.Status.Phase),
+				})
+			}
+			summary.Counts["rwmutex"] = len(rwmutexes)
+		}
+	}
+
+	if selected["once"] {
+		if onces, err := once.List(client, ctx); listed("once", err) {
+			summary.Onces = []onceSummary{}
+			for _, o := range onces {
+				summary.Onces = append(summary.Onces, onceSummary{
+					Name:     o.Name,
+					Executor: o.Status.Executor,
+					Phase:    string(o.Status.Phase),
+				})
+			}
+			summary.Counts["once"] = len(onces)
+		}
+	}
+
+	if selected["waitgroup"] {
+		if waitGroups, err := waitgroup.List(client, ctx); listed("waitgroup", err) {
+			summary.WaitGroups = []waitGroupSummary{}
+			for _, wg := range waitGroups {
+				summary.WaitGroups = append(summary.WaitGroups, waitGroupSummary{
+					Name:    wg.Name,
+					Counter: wg.Status.Counter,
+					Phase:   string(wg.Status.Phase),
+				})
+			}
+			summary.Counts["waitgroup"] = len(waitGroups)
+		}
+	}
+
+	if selected["event"] {
+		if events, err := event.List(client, ctx); listed("event", err) {
+			summary.Events = []eventSummary{}
+			for _, e := range events {
+				summary.Events = append(summary.Events, eventSummary{
+					Name:       e.Name,
+					SignaledBy: e.Status.SignaledBy,
+					Phase:      string(e.Status.Phase),
+				})
+			}
+			summary.Counts["event"] = len(events)
+		}
+	}
+
+	return summary, nil
+}
+
+// statusListError returns an error naming every type that failed to list, or
+// nil if all of them listed
+func statusListError(summary *StatusSummary) error {
+	var failed []string
+	for _, t := range statusTypes {
+		if _, ok := summary.Errors[t]; ok {
+			failed = append(failed, t)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to list %s", strings.Join(failed, ", "))
+}
+
+// selectStatusTypes returns the set of types to report, rejecting any that
+// `status all` does not know about
+func selectStatusTypes(types []string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	if len(types) == 0 {
+		for _, t := range statusTypes {
+			selected[t] = true
+		}
+		return selected, nil
+	}
+
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !slices.Contains(statusTypes, t) {
+			return nil, fmt.Errorf("unknown primitive type %q (valid: %s)", t, strings.Join(statusTypes, ", "))
+		}
+		selected[t] = true
+	}
+	return selected, nil
+}
+
+// printStatusSummary logs a count line for every reported type followed by a
+// line per object, and an error line for every type that failed to list
+func printStatusSummary(summary *StatusSummary) {
+	logger.Info("Konductor Status Overview", zap.String("namespace", summary.Namespace))
+
+	for _, t := range statusTypes {
+		if listErr, failed := summary.Errors[t]; failed {
+			logger.Error("Failed to list primitives", zap.String("type", t), zap.String("error", listErr))
+			continue
+		}
+
+		count, ok := summary.Counts[t]
+		if !ok {
+			continue
+		}
+
+		switch t {
+		case "semaphore":
+			logger.Info("Semaphores", zap.Int("count", count))
+			for _, sem := range summary.Semaphores {
+				logger.Info("Semaphore",
+					zap.String("name", sem.Name),
+					zap.Int32("in_use", sem.InUse),
+					zap.Int32("total", sem.Total),
+					zap.String("phase", sem.Phase),
+				)
+			}
+		case "barrier":
+			logger.Info("Barriers", zap.Int("count", count))
+			for _, b := range summary.Barriers {
+				logger.Info("Barrier",
+					zap.String("name", b.Name),
+					zap.Int32("arrived", b.Arrived),
+					zap.Int32("expected", b.Expected),
+					zap.String("phase", b.Phase),
+				)
+			}
+		case "lease":
+			logger.Info("Leases", zap.Int("count", count))
+			for _, l := range summary.Leases {
+				holder := "Available"
+				if l.Holder != "" {
+					holder = l.Holder
+				}
+				logger.Info("Lease",
+					zap.String("name", l.Name),
+					zap.String("holder", holder),
+					zap.String("phase", l.Phase),
+				)
+			}
+		case "gate":
+			logger.Info("Gates", zap.Int("count", count))
+			for _, g := range summary.Gates {
+				logger.Info("Gate",
+					zap.String("name", g.Name),
+					zap.Int("conditions_met", g.ConditionsMet),
+					zap.Int("conditions_total", g.ConditionsTotal),
+					zap.String("phase", g.Phase),
+				)
+			}
+		case "mutex":
+			logger.Info("Mutexes", zap.Int("count", count))
+			for _, m := range summary.Mutexes {
+				logger.Info("Mutex",
+					zap.String("name", m.Name),
+					zap.String("holder", m.Holder),
+					zap.String("phase", m.Phase),
+				)
+			}
+		case "rwmutex":
+			logger.Info("RWMutexes", zap.Int("count", count))
+			for _, rw := range summary.RWMutexes {
+				logger.Info("RWMutex",
+					zap.String("name", rw.Name),
+					zap.String("write_holder", rw.WriteHolder),
+					zap.Strings("read_holders", rw.ReadHolders),
+					zap.String("phase", rw.Phase),
+				)
+			}
+		case "once":
+			logger.Info("Onces", zap.Int("count", count))
+			for _, o := range summary.Onces {
+				logger.Info("Once",
+					zap.String("name", o.Name),
+					zap.String("executor", o.Executor),
+					zap.String("phase", o.Phase),
+				)
+			}
+		case "waitgroup":
+			logger.Info("WaitGroups", zap.Int("count", count))
+			for _, wg := range summary.WaitGroups {
+				logger.Info("WaitGroup",
+					zap.String("name", wg.Name),
+					zap.Int32("counter", wg.Counter),
+					zap.String("phase", wg.Phase),
+				)
+			}
+		case "event":
+			logger.Info("Events", zap.Int("count", count))
+			for _, e := range summary.Events {
+				logger.Info("Event",
+					zap.String("name", e.Name),
+					zap.String("signaled_by", e.SignaledBy),
+					zap.String("phase", e.Phase),
+				)
+			}
+		}
+	}
 }
@@ -2,18 +2,26 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
 func _TestStatusCommands(t *testing.T) {
@@ -35,9 +43,13 @@ func _TestStatusCommands(t *testing.T) {
 				Permits: 5,
 			},
 			Status: syncv1.SemaphoreStatus{
-				InUse:     2,
-				Available: 3,
-				Phase:     syncv1.SemaphorePhaseReady,
+				InUse:       2,
+				Available:   3,
+				Pending:     1,
+				LongestWait: &metav1.Duration{Duration: 30 * time.Second},
+				MaxInUse:    5,
+				LastFullAt:  &now,
+				Phase:       syncv1.SemaphorePhaseReady,
 			},
 		},
 		&syncv1.Barrier{
@@ -149,6 +161,10 @@ func _TestStatusCommands(t *testing.T) {
 					"test-semaphore",
 					"permits_total",
 					"permits_in_use",
+					"pending",
+					"longest_wait",
+					"max_in_use",
+					"last_full_at",
 					"Ready",
 				}
 				for _, expected := range expectedStrings {
@@ -320,3 +336,204 @@ func TestStatusAllEmpty(t *testing.T) {
 		}
 	}
 }
+
+func newMixedPrimitivesClient(t *testing.T) *konductor.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&syncv1.Semaphore{
+				ObjectMeta: metav1.ObjectMeta{Name: "sem-a", Namespace: "default"},
+				Spec:       syncv1.SemaphoreSpec{Permits: 3},
+				Status:     syncv1.SemaphoreStatus{InUse: 2, Phase: syncv1.SemaphorePhaseReady},
+			},
+			&syncv1.Semaphore{
+				ObjectMeta: metav1.ObjectMeta{Name: "sem-b", Namespace: "default"},
+				Spec:       syncv1.SemaphoreSpec{Permits: 1},
+				Status:     syncv1.SemaphoreStatus{InUse: 1, Phase: syncv1.SemaphorePhaseFull},
+			},
+			&syncv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Name: "leader", Namespace: "default"},
+				Status:     syncv1.LeaseStatus{Holder: "pod-1", Phase: syncv1.LeasePhaseHeld},
+			},
+			&syncv1.Mutex{
+				ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+				Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseUnlocked},
+			},
+			&syncv1.WaitGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "default"},
+				Status:     syncv1.WaitGroupStatus{Counter: 4, Phase: syncv1.WaitGroupPhaseWaiting},
+			},
+			&syncv1.Semaphore{
+				ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "other"},
+			},
+		).
+		Build()
+
+	return konductor.NewFromClient(fakeClient, "default")
+}
+
+func TestBuildStatusSummary(t *testing.T) {
+	summary, err := buildStatusSummary(context.Background(), newMixedPrimitivesClient(t), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "default", summary.Namespace)
+	assert.Equal(t, map[string]int{
+		"semaphore": 2,
+		"barrier":   0,
+		"lease":     1,
+		"gate":      0,
+		"mutex":     1,
+		"rwmutex":   0,
+		"once":      0,
+		"waitgroup": 1,
+		"event":     0,
+	}, summary.Counts)
+
+	assert.ElementsMatch(t, []semaphoreSummary{
+		{Name: "sem-a", InUse: 2, Total: 3, Phase: "Ready"},
+		{Name: "sem-b", InUse: 1, Total: 1, Phase: "Full"},
+	}, summary.Semaphores)
+	assert.Equal(t, []leaseSummary{{Name: "leader", Holder: "pod-1", Phase: "Held"}}, summary.Leases)
+	assert.Equal(t, []mutexSummary{{Name: "migrate", Phase: "Unlocked"}}, summary.Mutexes)
+	assert.Equal(t, []waitGroupSummary{{Name: "workers", Counter: 4, Phase: "Waiting"}}, summary.WaitGroups)
+	assert.NotNil(t, summary.Barriers)
+	assert.Empty(t, summary.Barriers)
+}
+
+func TestBuildStatusSummary_FilterByType(t *testing.T) {
+	summary, err := buildStatusSummary(context.Background(), newMixedPrimitivesClient(t), []string{"semaphore", "Lease"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"semaphore": 2, "lease": 1}, summary.Counts)
+	assert.Len(t, summary.Semaphores, 2)
+	assert.Len(t, summary.Leases, 1)
+	assert.Nil(t, summary.Mutexes)
+	assert.Nil(t, summary.WaitGroups)
+
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "mutexes")
+}
+
+func TestBuildStatusSummary_UnknownType(t *testing.T) {
+	_, err := buildStatusSummary(context.Background(), newMixedPrimitivesClient(t), []string{"semaphore", "queue"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown primitive type "queue"`)
+}
+
+// failLeaseList wraps c so that listing leases is forbidden, as it is when
+// RBAC does not grant it
+func failLeaseList(c client.Client) client.Client {
+	return interceptor.NewClient(c.(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*syncv1.LeaseList); ok {
+				return apierrors.NewForbidden(schema.GroupResource{Group: "sync.konductor.io", Resource: "leases"}, "", nil)
+			}
+			return c.List(ctx, list, opts...)
+		},
+	})
+}
+
+func TestBuildStatusSummary_ListError(t *testing.T) {
+	mixed := newMixedPrimitivesClient(t)
+	client := konductor.NewFromClient(failLeaseList(mixed.K8sClient()), "default")
+
+	summary, err := buildStatusSummary(context.Background(), client, nil)
+	require.NoError(t, err)
+
+	require.Contains(t, summary.Errors, "lease")
+	assert.Contains(t, summary.Errors["lease"], "forbidden")
+	assert.Len(t, summary.Errors, 1)
+	assert.NotContains(t, summary.Counts, "lease", "a type that failed to list must not look empty")
+	assert.Nil(t, summary.Leases)
+
+	// The other types still list
+	assert.Equal(t, 2, summary.Counts["semaphore"])
+	assert.Equal(t, 1, summary.Counts["mutex"])
+
+	assert.EqualError(t, statusListError(summary), "failed to list lease")
+}
+
+func TestStatusAll_ListErrorFailsCommand(t *testing.T) {
+	originalClient := k8sClient
+	originalFormat := outputFormat
+	originalLogger := logger
+	defer func() {
+		k8sClient = originalClient
+		outputFormat = originalFormat
+		logger = originalLogger
+	}()
+
+	k8sClient = failLeaseList(newMixedPrimitivesClient(t).K8sClient())
+	namespace = "default"
+
+	t.Run("text", func(t *testing.T) {
+		outputFormat = "text"
+		var logBuf bytes.Buffer
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.TimeKey = ""
+		logger = zap.New(zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig), zapcore.AddSync(&logBuf), zapcore.DebugLevel))
+
+		cmd := newStatusAllCmd()
+		cmd.SetArgs(nil)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list lease")
+
+		output := logBuf.String()
+		assert.Contains(t, output, "Failed to list primitives")
+		assert.Contains(t, output, `"type": "lease"`)
+		assert.Contains(t, output, "forbidden")
+		assert.Contains(t, output, "Semaphores")
+		assert.NotContains(t, output, "Leases")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		outputFormat = "json"
+
+		cmd := newStatusAllCmd()
+		cmd.SetArgs(nil)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		require.Error(t, cmd.Execute())
+
+		var summary StatusSummary
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &summary), buf.String())
+		assert.Contains(t, summary.Errors["lease"], "forbidden")
+		assert.Equal(t, 2, summary.Counts["semaphore"])
+	})
+}
+
+func TestStatusAll_TypeFlag(t *testing.T) {
+	originalClient := k8sClient
+	originalFormat := outputFormat
+	defer func() {
+		k8sClient = originalClient
+		outputFormat = originalFormat
+	}()
+
+	k8sClient = newMixedPrimitivesClient(t).K8sClient()
+	namespace = "default"
+	outputFormat = "json"
+
+	rootCmd := &cobra.Command{Use: "koncli"}
+	rootCmd.AddCommand(newStatusCmd())
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetArgs([]string{"status", "all", "--type", "waitgroup"})
+	require.NoError(t, rootCmd.Execute())
+
+	var summary StatusSummary
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &summary), buf.String())
+	assert.Equal(t, map[string]int{"waitgroup": 1}, summary.Counts)
+	assert.Equal(t, []waitGroupSummary{{Name: "workers", Counter: 4, Phase: "Waiting"}}, summary.WaitGroups)
+	assert.Nil(t, summary.Semaphores)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 	"github.com/LogicIQ/konductor/sdk/go/waitgroup"
 )
@@ -24,11 +25,13 @@ func newWaitGroupCmd() *cobra.Command {
 	cmd.AddCommand(newWaitGroupWaitCmd())
 	cmd.AddCommand(newWaitGroupListCmd())
 
+	registerNameCompletion(cmd, &syncv1.WaitGroupList{})
+
 	return cmd
 }
 
 func createWaitGroupClient() *konductor.Client {
-	return konductor.NewFromClient(k8sClient, namespace)
+	return konductor.NewFromClient(kubeClient(), namespace)
 }
 
 func newWaitGroupAddCmd() *cobra.Command {
@@ -43,6 +46,10 @@ func newWaitGroupAddCmd() *cobra.Command {
 			ctx := cmd.Context()
 
 			client := createWaitGroupClient()
+			if skipForDryRun("add to waitgroup", zap.String("waitgroup", name), zap.Int32("delta", delta)) {
+				return nil
+			}
+
 			if err := waitgroup.Add(client, ctx, name, delta); err != nil {
 				logger.Error("Failed to add to waitgroup", zap.Error(err))
 				return err
@@ -68,6 +75,10 @@ func newWaitGroupDoneCmd() *cobra.Command {
 			ctx := cmd.Context()
 
 			client := createWaitGroupClient()
+			if skipForDryRun("call done on waitgroup", zap.String("waitgroup", name)) {
+				return nil
+			}
+
 			if err := waitgroup.Done(client, ctx, name); err != nil {
 				logger.Error("Failed to call done on waitgroup", zap.Error(err))
 				return err
@@ -114,6 +125,8 @@ func newWaitGroupWaitCmd() *cobra.Command {
 }
 
 func newWaitGroupListCmd() *cobra.Command {
+	var filters listFilters
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all waitgroups",
@@ -121,12 +134,22 @@ func newWaitGroupListCmd() *cobra.Command {
 			ctx := cmd.Context()
 
 			client := createWaitGroupClient()
-			wgs, err := waitgroup.List(client, ctx)
+
+			opts, err := filters.options()
+			if err != nil {
+				return err
+			}
+
+			wgs, err := waitgroup.List(client, ctx, opts...)
 			if err != nil {
 				logger.Error("Failed to list waitgroups", zap.Error(err))
 				return err
 			}
 
+			if isStructuredOutput() {
+				return printStructuredList(cmd.OutOrStdout(), wgs)
+			}
+
 			if len(wgs) == 0 {
 				logger.Info("No waitgroups found")
 				return nil
@@ -135,6 +158,7 @@ func newWaitGroupListCmd() *cobra.Command {
 			for _, wg := range wgs {
 				logger.Info("WaitGroup",
 					zap.String("name", wg.Name),
+					zap.String("namespace", wg.Namespace),
 					zap.Int32("counter", wg.Status.Counter),
 					zap.String("phase", string(wg.Status.Phase)),
 				)
@@ -144,11 +168,16 @@ func newWaitGroupListCmd() *cobra.Command {
 		},
 	}
 
+	addListFlags(cmd, &filters)
+
 	return cmd
 }
 
 func newWaitGroupCreateCmd() *cobra.Command {
-	var ttl time.Duration
+	var (
+		ttl   time.Duration
+		count int32
+	)
 
 	cmd := &cobra.Command{
 		Use:   "create <waitgroup-name>",
@@ -158,7 +187,7 @@ func newWaitGroupCreateCmd() *cobra.Command {
 			name := args[0]
 			ctx := cmd.Context()
 
-			var opts []konductor.Option
+			opts := []konductor.Option{konductor.WithCount(count)}
 			if ttl > 0 {
 				opts = append(opts, konductor.WithTTL(ttl))
 			}
@@ -168,17 +197,21 @@ func newWaitGroupCreateCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Created waitgroup", zap.String("waitgroup", name))
+			logger.Info(dryRunMsg("Created waitgroup"), zap.String("waitgroup", name), zap.Int32("count", count))
 			return nil
 		},
 	}
 
 	cmd.Flags().DurationVar(&ttl, "ttl", 0, "Optional TTL for cleanup")
+	cmd.Flags().Int32Var(&count, "count", 0, "Initial counter value")
 
 	return cmd
 }
 
 func newWaitGroupDeleteCmd() *cobra.Command {
+	var wait bool
+	var timeout time.Duration
+
 	cmd := &cobra.Command{
 		Use:   "delete <waitgroup-name>",
 		Short: "Delete a waitgroup",
@@ -193,10 +226,17 @@ func newWaitGroupDeleteCmd() *cobra.Command {
 				return err
 			}
 
-			logger.Info("Deleted waitgroup", zap.String("waitgroup", name))
+			if err := waitForDeletion(ctx, client, &syncv1.WaitGroup{}, name, wait, timeout); err != nil {
+				return err
+			}
+
+			logger.Info(dryRunMsg("Deleted waitgroup"), zap.String("waitgroup", name))
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&wait, "wait", false, "Block until the waitgroup is gone")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultDeleteWaitTimeout, "How long --wait blocks before giving up")
+
 	return cmd
 }
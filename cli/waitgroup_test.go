@@ -2,11 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -167,3 +170,96 @@ func TestWaitGroupDoneCmd(t *testing.T) {
 	err := cmd.Execute()
 	require.NoError(t, err)
 }
+
+func TestWaitGroupCreateCmd_WithCount(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&syncv1.WaitGroup{}).
+		Build()
+	namespace = "default"
+	logger = initTestLogger(t)
+
+	cmd := newWaitGroupCreateCmd()
+	cmd.SetArgs([]string{"test-wg", "--count", "3"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	err := cmd.Execute()
+	require.NoError(t, err)
+
+	var wg syncv1.WaitGroup
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{
+		Name: "test-wg", Namespace: "default",
+	}, &wg))
+	assert.Equal(t, int32(3), wg.Status.Counter)
+	assert.Equal(t, syncv1.WaitGroupPhaseWaiting, wg.Status.Phase)
+}
+
+func TestWaitGroupWaitCmd(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-wg",
+			Namespace: "default",
+		},
+		Status: syncv1.WaitGroupStatus{
+			Counter: 0,
+			Phase:   syncv1.WaitGroupPhaseDone,
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(wg).
+		Build()
+	namespace = "default"
+	logger = initTestLogger(t)
+
+	cmd := newWaitGroupWaitCmd()
+	cmd.SetArgs([]string{"test-wg", "--timeout", "5s"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	err := cmd.Execute()
+	require.NoError(t, err)
+}
+
+func TestWaitGroupWaitCmd_Timeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-wg",
+			Namespace: "default",
+		},
+		Status: syncv1.WaitGroupStatus{
+			Counter: 2,
+			Phase:   syncv1.WaitGroupPhaseWaiting,
+		},
+	}
+
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(wg).
+		Build()
+	namespace = "default"
+	logger = initTestLogger(t)
+
+	cmd := newWaitGroupWaitCmd()
+	cmd.SetArgs([]string{"test-wg", "--timeout", "200ms"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stream status changes of coordination primitives",
+		Long:  "Watch a coordination primitive and print a line on every status transition until interrupted",
+	}
+
+	cmd.AddCommand(newWatchPrimitiveCmd("semaphore", &syncv1.SemaphoreList{}, summarizeSemaphore))
+	cmd.AddCommand(newWatchPrimitiveCmd("barrier", &syncv1.BarrierList{}, summarizeBarrier))
+	cmd.AddCommand(newWatchPrimitiveCmd("lease", &syncv1.LeaseList{}, summarizeLease))
+	cmd.AddCommand(newWatchPrimitiveCmd("gate", &syncv1.GateList{}, summarizeGate))
+	cmd.AddCommand(newWatchPrimitiveCmd("mutex", &syncv1.MutexList{}, summarizeMutex))
+	cmd.AddCommand(newWatchPrimitiveCmd("rwmutex", &syncv1.RWMutexList{}, summarizeRWMutex))
+
+	return cmd
+}
+
+// watchEvent is the structured form of a line printed by `watch`
+type watchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Status interface{}     `json:"status"`
+}
+
+type mutexSummary struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder,omitempty"`
+	Phase  string `json:"phase"`
+}
+
+type rwmutexSummary struct {
+	Name        string   `json:"name"`
+	WriteHolder string   `json:"writeHolder,omitempty"`
+	ReadHolders []string `json:"readHolders,omitempty"`
+	Phase       string   `json:"phase"`
+}
+
+// summarizer reduces a watched object to the fields that make up a status
+// transition, along with the log fields used for table output
+type summarizer func(obj client.Object) (interface{}, []zap.Field, bool)
+
+func newWatchPrimitiveCmd(kind string, list client.ObjectList, summarize summarizer) *cobra.Command {
+	return &cobra.Command{
+		Use:               kind + " <name>",
+		Short:             fmt.Sprintf("Watch %s status changes", kind),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNames(list),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return watchPrimitive(ctx, cmd, kind, args[0], list, summarize)
+		},
+	}
+}
+
+// watchPrimitive prints the status of the named object every time it changes
+// until ctx is cancelled. The watch is re-established whenever the API server
+// closes it.
+func watchPrimitive(ctx context.Context, cmd *cobra.Command, kind, name string, list client.ObjectList, summarize summarizer) error {
+	watcher, ok := k8sClient.(client.WithWatch)
+	if !ok {
+		return fmt.Errorf("kubernetes client does not support watch")
+	}
+
+	var last interface{}
+	for ctx.Err() == nil {
+		w, err := watcher.Watch(ctx, list.DeepCopyObject().(client.ObjectList),
+			client.InNamespace(namespace),
+			client.MatchingFields{"metadata.name": name})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return fmt.Errorf("failed to watch %s %s: %w", kind, name, err)
+		}
+
+		last, err = drainWatch(ctx, cmd, w, kind, name, last, summarize)
+		w.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// drainWatch prints transitions from w until the watch closes or ctx is
+// cancelled, returning the last printed summary
+func drainWatch(ctx context.Context, cmd *cobra.Command, w watch.Interface, kind, name string, last interface{}, summarize summarizer) (interface{}, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return last, nil
+		case event, open := <-w.ResultChan():
+			if !open {
+				return last, nil
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+			case watch.Deleted:
+				// Always report deletion, and print the next Added in full
+				last = nil
+			default:
+				continue
+			}
+
+			obj, ok := event.Object.(client.Object)
+			if !ok || obj.GetName() != name {
+				continue
+			}
+
+			summary, fields, ok := summarize(obj)
+			if !ok || (event.Type != watch.Deleted && reflect.DeepEqual(summary, last)) {
+				continue
+			}
+			if event.Type != watch.Deleted {
+				last = summary
+			}
+
+			if err := printWatchEvent(cmd, kind, event.Type, summary, fields); err != nil {
+				return last, err
+			}
+		}
+	}
+}
+
+func printWatchEvent(cmd *cobra.Command, kind string, eventType watch.EventType, summary interface{}, fields []zap.Field) error {
+	if isStructuredOutput() {
+		if strings.ToLower(outputFormat) == outputYAML {
+			if _, err := fmt.Fprintln(cmd.OutOrStdout(), "---"); err != nil {
+				return err
+			}
+		}
+		return printStructured(cmd.OutOrStdout(), watchEvent{Type: eventType, Status: summary})
+	}
+
+	fields = append([]zap.Field{zap.String("event", string(eventType))}, fields...)
+	logger.Info(strings.ToUpper(kind[:1])+kind[1:]+" status", fields...)
+	return nil
+}
+
+func summarizeSemaphore(obj client.Object) (interface{}, []zap.Field, bool) {
+	sem, ok := obj.(*syncv1.Semaphore)
+	if !ok {
+		return nil, nil, false
+	}
+	summary := semaphoreSummary{
+		Name:  sem.Name,
+		InUse: sem.Status.InUse,
+		Total: sem.Spec.Permits,
+		Phase: string(sem.Status.Phase),
+	}
+	return summary, []zap.Field{
+		zap.String("name", summary.Name),
+		zap.Int32("in_use", summary.InUse),
+		zap.Int32("total", summary.Total),
+		zap.String("phase", summary.Phase),
+	}, true
+}
+
+func summarizeBarrier(obj client.Object) (interface{}, []zap.Field, bool) {
+	b, ok := obj.(*syncv1.Barrier)
+	if !ok {
+		return nil, nil, false
+	}
+	summary := barrierSummary{
+		Name:       b.Name,
+		Arrived:    b.Status.Arrived,
+		Expected:   b.Spec.Expected,
+		Phase:      string(b.Status.Phase),
+		Generation: b.Status.Generation,
+	}
+	fields := []zap.Field{
+		zap.String("name", summary.Name),
+		zap.Int32("arrived", summary.Arrived),
+		zap.Int32("expected", summary.Expected),
+		zap.String("phase", summary.Phase),
+	}
+	if b.Spec.Cyclic {
+		fields = append(fields, zap.Int64("generation", summary.Generation))
+	}
+	return summary, fields, true
+}
+
+func summarizeLease(obj client.Object) (interface{}, []zap.Field, bool) {
+	l, ok := obj.(*syncv1.Lease)
+	if !ok {
+		return nil, nil, false
+	}
+	summary := leaseSummary{
+		Name:   l.Name,
+		Holder: l.Status.Holder,
+		Phase:  string(l.Status.Phase),
+	}
+	return summary, []zap.Field{
+		zap.String("name", summary.Name),
+		zap.String("holder", summary.Holder),
+		zap.String("phase", summary.Phase),
+	}, true
+}
+
+func summarizeGate(obj client.Object) (interface{}, []zap.Field, bool) {
+	g, ok := obj.(*syncv1.Gate)
+	if !ok {
+		return nil, nil, false
+	}
+	summary := gateSummary{
+		Name:            g.Name,
+		ConditionsMet:   countMetConditions(g),
+		ConditionsTotal: len(g.Spec.Conditions),
+		Phase:           string(g.Status.Phase),
+	}
+	return summary, []zap.Field{
+		zap.String("name", summary.Name),
+		zap.Int("conditions_met", summary.ConditionsMet),
+		zap.Int("conditions_total", summary.ConditionsTotal),
+		zap.String("phase", summary.Phase),
+	}, true
+}
+
+func summarizeMutex(obj client.Object) (interface{}, []zap.Field, bool) {
+	m, ok := obj.(*syncv1.Mutex)
+	if !ok {
+		return nil, nil, false
+	}
+	summary := mutexSummary{
+		Name:   m.Name,
+		Holder: m.Status.Holder,
+		Phase:  string(m.Status.Phase),
+	}
+	return summary, []zap.Field{
+		zap.String("name", summary.Name),
+		zap.String("holder", summary.Holder),
+		zap.String("phase", summary.Phase),
+	}, true
+}
+
+func summarizeRWMutex(obj client.Object) (interface{}, []zap.Field, bool) {
+	rw, ok := obj.(*syncv1.RWMutex)
+	if !ok {
+		return nil, nil, false
+	}
+	summary := rwmutexSummary{
+		Name:        rw.Name,
+		WriteHolder: rw.Status.WriteHolder,
+		ReadHolders: rw.Status.ReadHolders,
+		Phase:       string(rw.Status.Phase),
+	}
+	return summary, []zap.Field{
+		zap.String("name", summary.Name),
+		zap.String("write_holder", summary.WriteHolder),
+		zap.Strings("read_holders", summary.ReadHolders),
+		zap.String("phase", summary.Phase),
+	}, true
+}
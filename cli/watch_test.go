@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// setupWatchClient returns a client whose first watch replays events and then
+// closes. Re-establishing the watch cancels ctx, so the command returns once
+// every event has been handled.
+func setupWatchClient(t *testing.T, cancel context.CancelFunc, events ...watch.Event) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	calls := 0
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+				calls++
+				if calls > 1 {
+					cancel()
+					return watch.NewEmptyWatch(), nil
+				}
+				w := watch.NewFakeWithChanSize(len(events), false)
+				for _, event := range events {
+					w.Action(event.Type, event.Object)
+				}
+				w.Stop()
+				return w, nil
+			},
+		}).
+		Build()
+}
+
+func newWatchSemaphore(inUse int32, phase syncv1.SemaphorePhase) *syncv1.Semaphore {
+	return &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 2,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     inUse,
+			Available: 2 - inUse,
+			Phase:     phase,
+		},
+	}
+}
+
+func executeWatchCommand(t *testing.T, ctx context.Context, kind string) (string, error) {
+	t.Helper()
+	cmd := newWatchCmd()
+	cmd.SetArgs([]string{kind, "test-sem"})
+	cmd.SetContext(ctx)
+	return executeCommandWithOutput(t, cmd)
+}
+
+func TestWatchCmd_PrintsTransitions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	k8sClient = setupWatchClient(t, cancel,
+		watch.Event{Type: watch.Added, Object: newWatchSemaphore(0, syncv1.SemaphorePhaseReady)},
+		watch.Event{Type: watch.Modified, Object: newWatchSemaphore(1, syncv1.SemaphorePhaseReady)},
+		// A resync with no status change must not print again
+		watch.Event{Type: watch.Modified, Object: newWatchSemaphore(1, syncv1.SemaphorePhaseReady)},
+		watch.Event{Type: watch.Modified, Object: newWatchSemaphore(2, syncv1.SemaphorePhaseFull)},
+		watch.Event{Type: watch.Deleted, Object: newWatchSemaphore(2, syncv1.SemaphorePhaseFull)},
+	)
+	namespace = "default"
+	outputFormat = "text"
+
+	output, err := executeWatchCommand(t, ctx, "semaphore")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, strings.Count(output, "Semaphore status"))
+	assert.Contains(t, output, `"event": "ADDED"`)
+	assert.Contains(t, output, `"phase": "Full"`)
+	assert.Contains(t, output, `"event": "DELETED"`)
+}
+
+func TestWatchCmd_JSON(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	k8sClient = setupWatchClient(t, cancel,
+		watch.Event{Type: watch.Added, Object: newWatchSemaphore(0, syncv1.SemaphorePhaseReady)},
+		watch.Event{Type: watch.Modified, Object: newWatchSemaphore(2, syncv1.SemaphorePhaseFull)},
+	)
+	namespace = "default"
+	outputFormat = "json"
+	defer func() { outputFormat = "text" }()
+
+	cmd := newWatchCmd()
+	cmd.SetArgs([]string{"semaphore", "test-sem"})
+	cmd.SetContext(ctx)
+	var buf strings.Builder
+	cmd.SetOut(&buf)
+	require.NoError(t, cmd.Execute())
+
+	type semaphoreWatchEvent struct {
+		Type   string           `json:"type"`
+		Status semaphoreSummary `json:"status"`
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(buf.String()))
+	var events []semaphoreWatchEvent
+	for decoder.More() {
+		var event semaphoreWatchEvent
+		require.NoError(t, decoder.Decode(&event))
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "ADDED", events[0].Type)
+	assert.Equal(t, int32(0), events[0].Status.InUse)
+	assert.Equal(t, "MODIFIED", events[1].Type)
+	assert.Equal(t, "Full", events[1].Status.Phase)
+}
+
+func TestWatchCmd_IgnoresOtherObjects(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	other := newWatchSemaphore(1, syncv1.SemaphorePhaseReady)
+	other.Name = "other-sem"
+
+	k8sClient = setupWatchClient(t, cancel,
+		watch.Event{Type: watch.Added, Object: other},
+		watch.Event{Type: watch.Added, Object: newWatchSemaphore(0, syncv1.SemaphorePhaseReady)},
+	)
+	namespace = "default"
+	outputFormat = "text"
+
+	output, err := executeWatchCommand(t, ctx, "semaphore")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, strings.Count(output, "Semaphore status"))
+	assert.NotContains(t, output, "other-sem")
+}
+
+func TestWatchCmd_Subcommands(t *testing.T) {
+	cmd := newWatchCmd()
+
+	var names []string
+	for _, sub := range cmd.Commands() {
+		names = append(names, sub.Name())
+	}
+
+	assert.ElementsMatch(t, []string{"semaphore", "barrier", "lease", "gate", "mutex", "rwmutex"}, names)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -17,11 +18,15 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	"github.com/LogicIQ/konductor/controllers"
+	"github.com/LogicIQ/konductor/server"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -55,6 +60,115 @@ func initLogger(level string) (*zap.Logger, error) {
 	return config.Build()
 }
 
+// managerFlags holds the flags that shape the manager itself
+type managerFlags struct {
+	metricsAddr             string
+	probeAddr               string
+	webhookPort             int
+	webhookCertDir          string
+	enableLeaderElection    bool
+	leaderElectionID        string
+	leaderElectionNamespace string
+	watchNamespace          string
+}
+
+func (f *managerFlags) bind(fs *flag.FlagSet) {
+	fs.StringVar(&f.metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	fs.StringVar(&f.probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	fs.IntVar(&f.webhookPort, "webhook-port", 9443, "The port the admission webhook server binds to.")
+	fs.StringVar(&f.webhookCertDir, "webhook-cert-dir", "",
+		"Directory holding tls.crt and tls.key for the webhook server. Defaults to <temp-dir>/k8s-webhook-server/serving-certs.")
+	fs.BoolVar(&f.enableLeaderElection, "leader-elect", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	fs.StringVar(&f.leaderElectionID, "leader-election-id", "konductor.io",
+		"Name of the lease used for leader election. Instances watching different namespaces need different IDs.")
+	fs.StringVar(&f.leaderElectionNamespace, "leader-election-namespace", "",
+		"Namespace of the leader election lease. Defaults to the namespace the manager runs in.")
+	fs.StringVar(&f.watchNamespace, "watch-namespace", os.Getenv("WATCH_NAMESPACE"),
+		"Comma-separated namespaces to reconcile primitives in. Defaults to $WATCH_NAMESPACE, and to all namespaces if that is empty.")
+}
+
+// watchNamespaces returns the namespaces set with --watch-namespace, or nil
+// to watch all of them
+func (f *managerFlags) watchNamespaces() []string {
+	var namespaces []string
+	for _, ns := range strings.Split(f.watchNamespace, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// grpcFlags holds the flags that enable and secure the coordination gRPC API
+type grpcFlags struct {
+	addr       string
+	certDir    string
+	insecure   bool
+	namespaces string
+}
+
+func (f *grpcFlags) bind(fs *flag.FlagSet) {
+	fs.StringVar(&f.addr, "grpc-bind-address", "0",
+		"The address the coordination gRPC API binds to. Set to 0 to disable it.")
+	fs.StringVar(&f.certDir, "grpc-cert-dir", "",
+		"Directory holding tls.crt and tls.key for the gRPC API, and the ca.crt that must have signed every caller's client certificate.")
+	fs.BoolVar(&f.insecure, "grpc-insecure", false,
+		"Serve the gRPC API in plaintext without authenticating callers. Only for networks where every caller is trusted.")
+	fs.StringVar(&f.namespaces, "grpc-namespaces", server.DefaultNamespace,
+		"Comma-separated namespaces gRPC callers may act on primitives in.")
+}
+
+// serverOptions builds the gRPC API's options from its flags. The API needs
+// either a certificate directory or an explicit --grpc-insecure.
+func (f *grpcFlags) serverOptions() (server.Options, error) {
+	var opts server.Options
+	for _, ns := range strings.Split(f.namespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			opts.Namespaces = append(opts.Namespaces, ns)
+		}
+	}
+
+	switch {
+	case f.certDir != "":
+		tlsConfig, err := server.LoadTLSConfig(f.certDir)
+		if err != nil {
+			return server.Options{}, err
+		}
+		opts.TLS = tlsConfig
+	case !f.insecure:
+		return server.Options{}, fmt.Errorf("the gRPC API needs --grpc-cert-dir, or --grpc-insecure to serve it without authentication")
+	}
+	return opts, nil
+}
+
+// managerOptions builds the manager's options from its flags
+func managerOptions(f *managerFlags) ctrl.Options {
+	opts := ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: f.metricsAddr,
+		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    f.webhookPort,
+			CertDir: f.webhookCertDir,
+		}),
+		HealthProbeBindAddress:  f.probeAddr,
+		LeaderElection:          f.enableLeaderElection,
+		LeaderElectionID:        f.leaderElectionID,
+		LeaderElectionNamespace: f.leaderElectionNamespace,
+	}
+
+	if namespaces := f.watchNamespaces(); len(namespaces) > 0 {
+		opts.Cache.DefaultNamespaces = make(map[string]cache.Config, len(namespaces))
+		for _, ns := range namespaces {
+			opts.Cache.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+	return opts
+}
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(syncv1.AddToScheme(scheme))
@@ -62,16 +176,27 @@ func init() {
 }
 
 func main() {
-	var metricsAddr string
-	var enableLeaderElection bool
-	var probeAddr string
+	var mgrFlags managerFlags
 	var logLevel string
-	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	var gateMaxRequeue time.Duration
+	var leaseRequeueMax time.Duration
+	var arrivalRetention time.Duration
+	var reconcileStaleAfter time.Duration
+	var grpcFlags grpcFlags
+	var enableWebhooks bool
+	mgrFlags.bind(flag.CommandLine)
+	grpcFlags.bind(flag.CommandLine)
 	flag.StringVar(&logLevel, "log-level", "info", "Log level (debug, info, warn, error)")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&gateMaxRequeue, "gate-max-requeue", controllers.DefaultGateMaxRequeue,
+		"Maximum interval between checks of a waiting Gate.")
+	flag.DurationVar(&leaseRequeueMax, "lease-requeue-max", controllers.DefaultLeaseMaxRequeue,
+		"Maximum interval between checks of a Lease, however far off its expiry.")
+	flag.DurationVar(&arrivalRetention, "arrival-retention", controllers.DefaultArrivalRetention,
+		"How long Arrivals are kept after their Barrier has opened or failed.")
+	flag.DurationVar(&reconcileStaleAfter, "reconcile-stale-after", controllers.DefaultReconcileStaleAfter,
+		"How long a controller may keep failing to reconcile before the manager reports not ready.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the admission webhooks that default and validate primitive specs. Requires a serving certificate.")
 	flag.Parse()
 
 	// Initialize zap logger
@@ -92,34 +217,34 @@ func main() {
 	logger.Info("Starting konductor operator",
 		zap.String("version", version),
 		zap.String("log-level", logLevel),
-		zap.Bool("leader-election", enableLeaderElection))
+		zap.Bool("leader-election", mgrFlags.enableLeaderElection),
+		zap.String("leader-election-id", mgrFlags.leaderElectionID),
+		zap.Strings("watch-namespaces", mgrFlags.watchNamespaces()))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
-		},
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "konductor.io",
-	})
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions(&mgrFlags))
 	if err != nil {
 		logger.Error("Unable to start manager", zap.Error(err))
 		os.Exit(1)
 	}
 
+	// Built before the controllers below shadow the package name
+	readiness := controllers.ReadinessCheck(mgr.GetCache(), reconcileStaleAfter)
+
 	controllers := []struct {
 		reconciler reconciler
 		name       string
 	}{
-		{&controllers.SemaphoreReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "Semaphore"},
-		{&controllers.BarrierReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "Barrier"},
-		{&controllers.LeaseReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "Lease"},
-		{&controllers.GateReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "Gate"},
-		{&controllers.MutexReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "Mutex"},
-		{&controllers.RWMutexReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "RWMutex"},
-		{&controllers.OnceReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "Once"},
-		{&controllers.WaitGroupReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}, "WaitGroup"},
+		{&controllers.SemaphoreReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("semaphore-controller")}, "Semaphore"},
+		{&controllers.BarrierReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("barrier-controller"), ArrivalRetention: arrivalRetention}, "Barrier"},
+		{&controllers.LeaseReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("lease-controller"), MaxRequeue: leaseRequeueMax}, "Lease"},
+		{&controllers.GateReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("gate-controller"), MaxRequeue: gateMaxRequeue}, "Gate"},
+		{&controllers.MutexReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("mutex-controller")}, "Mutex"},
+		{&controllers.RWMutexReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("rwmutex-controller")}, "RWMutex"},
+		{&controllers.OnceReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("once-controller")}, "Once"},
+		{&controllers.WaitGroupReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("waitgroup-controller")}, "WaitGroup"},
+		{&controllers.EventReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("event-controller")}, "Event"},
+		{&controllers.RateLimiterReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("ratelimiter-controller")}, "RateLimiter"},
+		{&controllers.CircuitBreakerReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("circuitbreaker-controller")}, "CircuitBreaker"},
 	}
 
 	for _, c := range controllers {
@@ -129,13 +254,54 @@ func main() {
 		}
 	}
 
+	if enableWebhooks {
+		webhooks := []struct {
+			webhook webhookSetup
+			name    string
+		}{
+			{&syncv1.Semaphore{}, "Semaphore"},
+			{&syncv1.Barrier{}, "Barrier"},
+			{&syncv1.Lease{}, "Lease"},
+			{&syncv1.Gate{}, "Gate"},
+		}
+
+		for _, w := range webhooks {
+			if err := w.webhook.SetupWebhookWithManager(mgr); err != nil {
+				logger.Error("Unable to create webhook", zap.Error(err), zap.String("webhook", w.name))
+				os.Exit(1)
+			}
+		}
+		logger.Info("Enabled admission webhooks", zap.Int("port", mgrFlags.webhookPort))
+	}
+
 	//+kubebuilder:scaffold:builder
 
+	if grpcFlags.addr != "0" {
+		grpcOpts, err := grpcFlags.serverOptions()
+		if err != nil {
+			logger.Error("Unable to configure gRPC API", zap.Error(err))
+			os.Exit(1)
+		}
+		// The API reads and writes through the API server instead of the
+		// manager's cache, so waits can use watches and see their own writes
+		grpcClient, err := client.NewWithWatch(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+		if err != nil {
+			logger.Error("Unable to create gRPC API client", zap.Error(err))
+			os.Exit(1)
+		}
+		if err := mgr.Add(server.New(grpcFlags.addr, grpcClient, server.DefaultNamespace, grpcOpts)); err != nil {
+			logger.Error("Unable to set up gRPC API", zap.Error(err))
+			os.Exit(1)
+		}
+		logger.Info("Enabled coordination gRPC API", zap.String("address", grpcFlags.addr),
+			zap.Bool("tls", grpcOpts.TLS != nil), zap.Strings("namespaces", grpcOpts.Namespaces))
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		logger.Error("Unable to set up health check", zap.Error(err))
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	if err := mgr.AddReadyzCheck("readyz", readiness); err != nil {
 		logger.Error("Unable to set up ready check", zap.Error(err))
 		os.Exit(1)
 	}
@@ -151,6 +317,10 @@ type reconciler interface {
 	SetupWithManager(mgr ctrl.Manager) error
 }
 
+type webhookSetup interface {
+	SetupWebhookWithManager(mgr ctrl.Manager) error
+}
+
 func setupController(mgr ctrl.Manager, r reconciler, name string, logger *zap.Logger) error {
 	if err := r.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("failed to setup %s controller: %w", name, err)
@@ -1,10 +1,99 @@
 package main
 
 import (
+	"flag"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 )
 
 func TestMain(t *testing.T) {
 	// Placeholder test to satisfy go test
 	t.Skip("Main function tests require full integration setup")
 }
+
+// parseManagerFlags parses args as the manager's command line would be
+func parseManagerFlags(t *testing.T, args ...string) *managerFlags {
+	t.Helper()
+	var f managerFlags
+	fs := flag.NewFlagSet("konductor", flag.ContinueOnError)
+	f.bind(fs)
+	require.NoError(t, fs.Parse(args))
+	return &f
+}
+
+func TestManagerOptions(t *testing.T) {
+	t.Setenv("WATCH_NAMESPACE", "")
+
+	t.Run("defaults", func(t *testing.T) {
+		opts := managerOptions(parseManagerFlags(t))
+
+		assert.False(t, opts.LeaderElection)
+		assert.Equal(t, "konductor.io", opts.LeaderElectionID)
+		assert.Empty(t, opts.LeaderElectionNamespace)
+		assert.Nil(t, opts.Cache.DefaultNamespaces, "watches all namespaces")
+		assert.Equal(t, ":8080", opts.Metrics.BindAddress)
+		assert.Equal(t, ":8081", opts.HealthProbeBindAddress)
+	})
+
+	t.Run("scoped instance", func(t *testing.T) {
+		opts := managerOptions(parseManagerFlags(t,
+			"--leader-elect",
+			"--leader-election-id", "konductor-team-a",
+			"--leader-election-namespace", "team-a",
+			"--watch-namespace", "team-a, team-a-jobs",
+			"--metrics-bind-address", ":9090",
+		))
+
+		assert.True(t, opts.LeaderElection)
+		assert.Equal(t, "konductor-team-a", opts.LeaderElectionID)
+		assert.Equal(t, "team-a", opts.LeaderElectionNamespace)
+		assert.Equal(t, map[string]cache.Config{"team-a": {}, "team-a-jobs": {}}, opts.Cache.DefaultNamespaces)
+		assert.Equal(t, ":9090", opts.Metrics.BindAddress)
+		assert.Same(t, scheme, opts.Scheme)
+	})
+
+	t.Run("watch namespace from environment", func(t *testing.T) {
+		t.Setenv("WATCH_NAMESPACE", "team-b")
+		opts := managerOptions(parseManagerFlags(t))
+		assert.Equal(t, map[string]cache.Config{"team-b": {}}, opts.Cache.DefaultNamespaces)
+
+		opts = managerOptions(parseManagerFlags(t, "--watch-namespace", "team-c"))
+		assert.Equal(t, map[string]cache.Config{"team-c": {}}, opts.Cache.DefaultNamespaces, "the flag wins")
+	})
+}
+
+func TestGRPCServerOptions(t *testing.T) {
+	parse := func(args ...string) *grpcFlags {
+		var f grpcFlags
+		fs := flag.NewFlagSet("konductor", flag.ContinueOnError)
+		f.bind(fs)
+		require.NoError(t, fs.Parse(args))
+		return &f
+	}
+
+	t.Run("refuses plaintext unless asked", func(t *testing.T) {
+		_, err := parse("--grpc-bind-address", ":9090").serverOptions()
+		assert.ErrorContains(t, err, "--grpc-cert-dir")
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		opts, err := parse("--grpc-insecure").serverOptions()
+		require.NoError(t, err)
+		assert.Nil(t, opts.TLS)
+		assert.Equal(t, []string{"default"}, opts.Namespaces)
+	})
+
+	t.Run("allow-listed namespaces", func(t *testing.T) {
+		opts, err := parse("--grpc-insecure", "--grpc-namespaces", "team-a, team-a-jobs").serverOptions()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a", "team-a-jobs"}, opts.Namespaces)
+	})
+
+	t.Run("missing certificates", func(t *testing.T) {
+		_, err := parse("--grpc-cert-dir", t.TempDir()).serverOptions()
+		assert.ErrorContains(t, err, "failed to load serving certificate")
+	})
+}
@@ -2,11 +2,13 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -14,33 +16,43 @@ import (
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
 
+// DefaultArrivalRetention is how long Arrivals of an opened or failed barrier
+// are kept when ArrivalRetention is unset
+const DefaultArrivalRetention = time.Hour
+
 // BarrierReconciler reconciles a Barrier object
 type BarrierReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// ArrivalRetention is how long Arrivals are kept after their barrier has
+	// opened or failed. Defaults to DefaultArrivalRetention.
+	ArrivalRetention time.Duration
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=barriers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=barriers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=barriers/finalizers,verbs=update
-//+kubebuilder:rbac:groups=sync.konductor.io,resources=arrivals,verbs=get;list;watch
-
-func (r *BarrierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=arrivals,verbs=get;list;watch;delete
 
-	log.Info("Reconciling Barrier", "name", req.Name, "namespace", req.Namespace)
+func (r *BarrierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("Barrier", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "Barrier", req)
 
 	var barrier syncv1.Barrier
 	if err := r.Get(ctx, req.NamespacedName, &barrier); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Barrier not found, likely deleted", "name", req.Name)
+			barrierArrivals.DeleteLabelValues(req.Namespace, req.Name)
+			barrierExpected.DeleteLabelValues(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "unable to fetch Barrier")
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Found Barrier", "name", barrier.Name, "expected", barrier.Spec.Expected, "currentArrived", barrier.Status.Arrived)
+	log.V(1).Info("Found Barrier", "expected", barrier.Spec.Expected, "currentArrived", barrier.Status.Arrived)
 
 	arrivals := &syncv1.ArrivalList{}
 	if err := r.List(ctx, arrivals, client.InNamespace(req.Namespace),
@@ -49,48 +61,107 @@ func (r *BarrierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Found arrivals", "count", len(arrivals.Items), "barrier", barrier.Name)
+	log.V(1).Info("Found arrivals", "count", len(arrivals.Items))
 
-	oldArrived := barrier.Status.Arrived
-	barrier.Status.Arrived = int32(len(arrivals.Items))
-	barrier.Status.Arrivals = make([]string, len(arrivals.Items))
-	for i, arrival := range arrivals.Items {
-		barrier.Status.Arrivals[i] = arrival.Spec.Holder
+	// Once the retention window has passed the barrier's status is final, so
+	// its Arrivals are collected without recounting them
+	collectAt, finished := r.arrivalCollectionTime(&barrier)
+	if finished && !collectAt.After(time.Now()) {
+		for i := range arrivals.Items {
+			arrival := &arrivals.Items[i]
+			if err := r.Delete(ctx, arrival); client.IgnoreNotFound(err) != nil {
+				log.Error(err, "failed to delete arrival", "arrival", arrival.Name)
+				return ctrl.Result{}, err
+			}
+			log.Info("Deleted arrival past retention", "arrival", arrival.Name, "barrier", barrier.Name)
+		}
+		return ctrl.Result{}, nil
 	}
 
+	oldArrived := barrier.Status.Arrived
+	barrier.Status.Arrivals = arrivedHolders(arrivals.Items, &barrier)
+	barrier.Status.Arrived = int32(len(barrier.Status.Arrivals))
+
+	// In best-effort mode the quorum only opens the barrier once the timeout
+	// has passed; until then every expected arrival is waited for
 	requiredArrivals := barrier.Spec.Expected
-	if barrier.Spec.Quorum != nil {
+	bestEffort := barrier.Spec.OpenOnTimeoutIfQuorum && barrier.Spec.Quorum != nil
+	if barrier.Spec.Quorum != nil && !bestEffort {
 		requiredArrivals = *barrier.Spec.Quorum
 	}
 
 	var newPhase syncv1.BarrierPhase
-	if barrier.Spec.Timeout != nil && barrier.CreationTimestamp.Add(barrier.Spec.Timeout.Duration).Before(time.Now()) {
-		if barrier.Status.Arrived < requiredArrivals {
+	if barrier.Spec.Timeout != nil && roundStart(&barrier).Add(barrier.Spec.Timeout.Duration).Before(time.Now()) {
+		if bestEffort {
+			requiredArrivals = *barrier.Spec.Quorum
+		}
+		switch {
+		case barrier.Status.Arrived < requiredArrivals:
 			newPhase = syncv1.BarrierPhaseFailed
-		} else {
+		case bestEffort && barrier.Status.Phase != syncv1.BarrierPhaseFailed:
+			newPhase = syncv1.BarrierPhaseOpen
+			markOpened(&barrier)
+		default:
 			newPhase = barrier.Status.Phase
 		}
 	} else if barrier.Status.Arrived >= requiredArrivals {
 		newPhase = syncv1.BarrierPhaseOpen
-		if barrier.Status.OpenedAt == nil {
-			now := metav1.Now()
-			barrier.Status.OpenedAt = &now
-		}
+		markOpened(&barrier)
 	} else {
 		newPhase = syncv1.BarrierPhaseWaiting
 	}
 
-	if barrier.Status.Phase != newPhase || oldArrived != barrier.Status.Arrived {
+	// A cyclic barrier does not stay open: the round that just opened is
+	// closed off and the next one starts straight away
+	openedGeneration, openedWith := barrier.Status.Generation, barrier.Status.Arrived
+	advanced := barrier.Spec.Cyclic && newPhase == syncv1.BarrierPhaseOpen
+	if advanced {
+		nextGeneration(&barrier)
+		newPhase = syncv1.BarrierPhaseWaiting
+	}
+
+	oldPhase := barrier.Status.Phase
+	generationChanged := observeGeneration(&barrier.Status.ObservedGeneration, &barrier)
+	if barrier.Status.Phase != newPhase || oldArrived != barrier.Status.Arrived || generationChanged || advanced {
 		barrier.Status.Phase = newPhase
 		if err := r.Status().Update(ctx, &barrier); err != nil {
 			log.Error(err, "unable to update Barrier status")
 			return ctrl.Result{}, err
 		}
-		log.Info("Successfully updated Barrier status", "name", barrier.Name, "arrived", barrier.Status.Arrived, "phase", barrier.Status.Phase)
+
+		if advanced {
+			recordNormal(r.Recorder, &barrier, ReasonBarrierOpened, "Barrier generation %d opened with %d of %d arrivals", openedGeneration, openedWith, barrier.Spec.Expected)
+		}
+		if oldPhase != newPhase {
+			switch newPhase {
+			case syncv1.BarrierPhaseOpen:
+				recordNormal(r.Recorder, &barrier, ReasonBarrierOpened, "Barrier opened with %d of %d arrivals", barrier.Status.Arrived, barrier.Spec.Expected)
+			case syncv1.BarrierPhaseFailed:
+				recordWarning(r.Recorder, &barrier, ReasonBarrierFailed, "Barrier timed out with %d of %d required arrivals", barrier.Status.Arrived, requiredArrivals)
+			}
+		}
+	}
+
+	reason := fmt.Sprintf("%d of %d arrived", barrier.Status.Arrived, barrier.Spec.Expected)
+	switch {
+	case advanced:
+		reason = fmt.Sprintf("generation %d opened with %d of %d arrivals", openedGeneration, openedWith, barrier.Spec.Expected)
+	case newPhase == syncv1.BarrierPhaseFailed:
+		reason = fmt.Sprintf("timed out with %d of %d required arrivals", barrier.Status.Arrived, requiredArrivals)
+	}
+	logPhase(log, oldPhase, newPhase, reason)
+
+	if barrier.Spec.Cyclic {
+		if err := r.deleteCompletedArrivals(ctx, &barrier, arrivals.Items); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
+	barrierArrivals.WithLabelValues(barrier.Namespace, barrier.Name).Set(float64(barrier.Status.Arrived))
+	barrierExpected.WithLabelValues(barrier.Namespace, barrier.Name).Set(float64(barrier.Spec.Expected))
+
 	if barrier.Spec.Timeout != nil && barrier.Status.Phase == syncv1.BarrierPhaseWaiting {
-		timeoutAt := barrier.CreationTimestamp.Add(barrier.Spec.Timeout.Duration)
+		timeoutAt := roundStart(&barrier).Add(barrier.Spec.Timeout.Duration)
 		requeueAfter := time.Until(timeoutAt)
 		if requeueAfter > time.Minute {
 			requeueAfter = time.Minute
@@ -100,12 +171,116 @@ func (r *BarrierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
+	if collectAt, finished := r.arrivalCollectionTime(&barrier); finished && len(arrivals.Items) > 0 {
+		return ctrl.Result{RequeueAfter: time.Until(collectAt)}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// markOpened records when the barrier opened, keeping the first time it did
+func markOpened(barrier *syncv1.Barrier) {
+	if barrier.Status.OpenedAt == nil {
+		now := metav1.Now()
+		barrier.Status.OpenedAt = &now
+	}
+}
+
+// nextGeneration starts the next round of a cyclic barrier once the current
+// one has opened
+func nextGeneration(barrier *syncv1.Barrier) {
+	now := metav1.Now()
+	barrier.Status.Generation++
+	barrier.Status.Arrived = 0
+	barrier.Status.Arrivals = nil
+	barrier.Status.OpenedAt = &now
+	barrier.Status.ResetAt = &now
+}
+
+// deleteCompletedArrivals deletes the Arrivals of a cyclic barrier that were
+// tagged with a generation that has already opened
+func (r *BarrierReconciler) deleteCompletedArrivals(ctx context.Context, barrier *syncv1.Barrier, arrivals []syncv1.Arrival) error {
+	log := log.FromContext(ctx)
+
+	for i := range arrivals {
+		arrival := &arrivals[i]
+		if arrival.Spec.Generation >= barrier.Status.Generation {
+			continue
+		}
+		if err := r.Delete(ctx, arrival); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "failed to delete arrival", "arrival", arrival.Name)
+			return err
+		}
+		log.Info("Deleted arrival from completed generation", "arrival", arrival.Name, "generation", arrival.Spec.Generation)
+	}
+	return nil
+}
+
+// arrivedHolders returns the holder of each Arrival in the order they were
+// listed. Unless the barrier turns off RequireDistinctHolders, a holder
+// arriving twice is only counted once. Arrivals from another round are
+// skipped: for a cyclic barrier those tagged with another generation,
+// otherwise those left over from before the last reset.
+func arrivedHolders(arrivals []syncv1.Arrival, barrier *syncv1.Barrier) []string {
+	distinct := barrier.Spec.RequireDistinctHolders == nil || *barrier.Spec.RequireDistinctHolders
+	resetAt := barrier.Status.ResetAt
+	seen := make(map[string]bool, len(arrivals))
+	holders := make([]string, 0, len(arrivals))
+	for _, arrival := range arrivals {
+		if barrier.Spec.Cyclic {
+			if arrival.Spec.Generation != barrier.Status.Generation {
+				continue
+			}
+		} else if resetAt != nil && arrival.CreationTimestamp.Before(resetAt) {
+			continue
+		}
+		if distinct && seen[arrival.Spec.Holder] {
+			continue
+		}
+		seen[arrival.Spec.Holder] = true
+		holders = append(holders, arrival.Spec.Holder)
+	}
+	return holders
+}
+
+// roundStart returns when the barrier's current round began, which is its
+// creation or its last reset
+func roundStart(barrier *syncv1.Barrier) time.Time {
+	if barrier.Status.ResetAt != nil {
+		return barrier.Status.ResetAt.Time
+	}
+	return barrier.CreationTimestamp.Time
+}
+
+// arrivalCollectionTime returns when the Arrivals of an opened or failed
+// barrier may be deleted. It reports false while the barrier is still waiting.
+func (r *BarrierReconciler) arrivalCollectionTime(barrier *syncv1.Barrier) (time.Time, bool) {
+	retention := r.ArrivalRetention
+	if retention <= 0 {
+		retention = DefaultArrivalRetention
+	}
+
+	switch barrier.Status.Phase {
+	case syncv1.BarrierPhaseOpen:
+		if barrier.Status.OpenedAt == nil {
+			return time.Time{}, false
+		}
+		return barrier.Status.OpenedAt.Add(retention), true
+	case syncv1.BarrierPhaseFailed:
+		if barrier.Spec.Timeout == nil {
+			return time.Time{}, false
+		}
+		return roundStart(barrier).Add(barrier.Spec.Timeout.Duration).Add(retention), true
+	}
+	return time.Time{}, false
+}
+
 func (r *BarrierReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("barrier-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.Barrier{}).
 		Owns(&syncv1.Arrival{}).
-		Complete(r)
+		Complete(trackHealth("barrier", r))
 }
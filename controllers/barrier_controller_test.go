@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -24,6 +26,24 @@ func TestBarrierReconciler_Reconcile(t *testing.T) {
 	utilruntime.Must(syncv1.AddToScheme(scheme))
 
 	quorum := int32(2)
+	distinct, anyHolder := true, false
+
+	// duplicateArrivals has holder-1 arrive twice under different names and
+	// holder-2 once
+	duplicateArrivals := []syncv1.Arrival{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "arrival-1", Namespace: "default", Labels: map[string]string{"barrier": "test-barrier"}},
+			Spec:       syncv1.ArrivalSpec{Barrier: "test-barrier", Holder: "holder-1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "arrival-1-again", Namespace: "default", Labels: map[string]string{"barrier": "test-barrier"}},
+			Spec:       syncv1.ArrivalSpec{Barrier: "test-barrier", Holder: "holder-1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "arrival-2", Namespace: "default", Labels: map[string]string{"barrier": "test-barrier"}},
+			Spec:       syncv1.ArrivalSpec{Barrier: "test-barrier", Holder: "holder-2"},
+		},
+	}
 
 	tests := []struct {
 		name          string
@@ -151,6 +171,125 @@ func TestBarrierReconciler_Reconcile(t *testing.T) {
 			expectedPhase: syncv1.BarrierPhaseOpen,
 			expectedCount: 2,
 		},
+		{
+			name: "repeated arrivals from one holder count once",
+			barrier: &syncv1.Barrier{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-barrier",
+					Namespace: "default",
+				},
+				Spec: syncv1.BarrierSpec{
+					Expected: 2,
+				},
+			},
+			arrivals: []syncv1.Arrival{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "arrival-1",
+						Namespace: "default",
+						Labels:    map[string]string{"barrier": "test-barrier"},
+					},
+					Spec: syncv1.ArrivalSpec{
+						Barrier: "test-barrier",
+						Holder:  "holder-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "arrival-1-retry",
+						Namespace: "default",
+						Labels:    map[string]string{"barrier": "test-barrier"},
+					},
+					Spec: syncv1.ArrivalSpec{
+						Barrier: "test-barrier",
+						Holder:  "holder-1",
+					},
+				},
+			},
+			expectedPhase: syncv1.BarrierPhaseWaiting,
+			expectedCount: 1,
+		},
+		{
+			name: "barrier with repeated arrivals opens at distinct holder threshold",
+			barrier: &syncv1.Barrier{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-barrier",
+					Namespace: "default",
+				},
+				Spec: syncv1.BarrierSpec{
+					Expected: 2,
+				},
+			},
+			arrivals: []syncv1.Arrival{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "arrival-1",
+						Namespace: "default",
+						Labels:    map[string]string{"barrier": "test-barrier"},
+					},
+					Spec: syncv1.ArrivalSpec{
+						Barrier: "test-barrier",
+						Holder:  "holder-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "arrival-1-retry",
+						Namespace: "default",
+						Labels:    map[string]string{"barrier": "test-barrier"},
+					},
+					Spec: syncv1.ArrivalSpec{
+						Barrier: "test-barrier",
+						Holder:  "holder-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "arrival-2",
+						Namespace: "default",
+						Labels:    map[string]string{"barrier": "test-barrier"},
+					},
+					Spec: syncv1.ArrivalSpec{
+						Barrier: "test-barrier",
+						Holder:  "holder-2",
+					},
+				},
+			},
+			expectedPhase: syncv1.BarrierPhaseOpen,
+			expectedCount: 2,
+		},
+		{
+			name: "distinct holders required explicitly",
+			barrier: &syncv1.Barrier{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-barrier",
+					Namespace: "default",
+				},
+				Spec: syncv1.BarrierSpec{
+					Expected:               3,
+					RequireDistinctHolders: &distinct,
+				},
+			},
+			arrivals:      duplicateArrivals,
+			expectedPhase: syncv1.BarrierPhaseWaiting,
+			expectedCount: 2,
+		},
+		{
+			name: "every arrival counts when distinct holders are not required",
+			barrier: &syncv1.Barrier{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-barrier",
+					Namespace: "default",
+				},
+				Spec: syncv1.BarrierSpec{
+					Expected:               3,
+					RequireDistinctHolders: &anyHolder,
+				},
+			},
+			arrivals:      duplicateArrivals,
+			expectedPhase: syncv1.BarrierPhaseOpen,
+			expectedCount: 3,
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,9 +305,11 @@ func TestBarrierReconciler_Reconcile(t *testing.T) {
 				WithStatusSubresource(&syncv1.Barrier{}).
 				Build()
 
+			recorder := record.NewFakeRecorder(10)
 			reconciler := &BarrierReconciler{
-				Client: client,
-				Scheme: scheme,
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: recorder,
 			}
 
 			req := ctrl.Request{
@@ -191,6 +332,10 @@ func TestBarrierReconciler_Reconcile(t *testing.T) {
 
 			if tt.expectedPhase == syncv1.BarrierPhaseOpen {
 				assert.NotNil(t, updated.Status.OpenedAt)
+				assertEvents(t, recorder, fmt.Sprintf("Normal BarrierOpened Barrier opened with %d of %d arrivals",
+					tt.expectedCount, tt.barrier.Spec.Expected))
+			} else {
+				assertEvents(t, recorder)
 			}
 
 			if tt.barrier.Spec.Timeout != nil && tt.expectedPhase == syncv1.BarrierPhaseWaiting {
@@ -223,9 +368,11 @@ func TestBarrierReconciler_Timeout(t *testing.T) {
 		WithStatusSubresource(&syncv1.Barrier{}).
 		Build()
 
+	recorder := record.NewFakeRecorder(10)
 	reconciler := &BarrierReconciler{
-		Client: client,
-		Scheme: scheme,
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
 	}
 
 	req := ctrl.Request{
@@ -243,4 +390,430 @@ func TestBarrierReconciler_Timeout(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, syncv1.BarrierPhaseFailed, updated.Status.Phase)
+	assertEvents(t, recorder, "Warning BarrierFailed Barrier timed out with 0 of 3 required arrivals")
+}
+
+func TestBarrierReconciler_OpenOnTimeoutIfQuorum(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	tests := []struct {
+		name          string
+		age           time.Duration
+		arrived       int
+		phase         syncv1.BarrierPhase
+		expectedPhase syncv1.BarrierPhase
+		expectedEvent []string
+	}{
+		{
+			name:          "opens at timeout with quorum arrivals",
+			age:           2 * time.Hour,
+			arrived:       2,
+			expectedPhase: syncv1.BarrierPhaseOpen,
+			expectedEvent: []string{"Normal BarrierOpened Barrier opened with 2 of 4 arrivals"},
+		},
+		{
+			name:          "opens at timeout with arrivals above quorum",
+			age:           2 * time.Hour,
+			arrived:       3,
+			expectedPhase: syncv1.BarrierPhaseOpen,
+			expectedEvent: []string{"Normal BarrierOpened Barrier opened with 3 of 4 arrivals"},
+		},
+		{
+			name:          "fails at timeout below quorum",
+			age:           2 * time.Hour,
+			arrived:       1,
+			expectedPhase: syncv1.BarrierPhaseFailed,
+			expectedEvent: []string{"Warning BarrierFailed Barrier timed out with 1 of 2 required arrivals"},
+		},
+		{
+			name:          "keeps waiting for expected arrivals before timeout",
+			age:           time.Minute,
+			arrived:       3,
+			expectedPhase: syncv1.BarrierPhaseWaiting,
+		},
+		{
+			name:          "stays failed when quorum arrives after failing",
+			age:           90 * time.Minute,
+			arrived:       2,
+			phase:         syncv1.BarrierPhaseFailed,
+			expectedPhase: syncv1.BarrierPhaseFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quorum := int32(2)
+			barrier := &syncv1.Barrier{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-barrier",
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-tt.age)),
+				},
+				Spec: syncv1.BarrierSpec{
+					Expected:              4,
+					Quorum:                &quorum,
+					Timeout:               &metav1.Duration{Duration: time.Hour},
+					OpenOnTimeoutIfQuorum: true,
+				},
+				Status: syncv1.BarrierStatus{Phase: tt.phase},
+			}
+
+			objs := []runtime.Object{barrier}
+			for i := 0; i < tt.arrived; i++ {
+				objs = append(objs, &syncv1.Arrival{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("arrival-%d", i),
+						Namespace: "default",
+						Labels:    map[string]string{"barrier": "test-barrier"},
+					},
+					Spec: syncv1.ArrivalSpec{
+						Barrier: "test-barrier",
+						Holder:  fmt.Sprintf("holder-%d", i),
+					},
+				})
+			}
+
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(objs...).
+				WithStatusSubresource(&syncv1.Barrier{}).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &BarrierReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+
+			req := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      barrier.Name,
+					Namespace: barrier.Namespace,
+				},
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated syncv1.Barrier
+			require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			assert.Equal(t, int32(tt.arrived), updated.Status.Arrived)
+			assert.Equal(t, tt.expectedPhase == syncv1.BarrierPhaseOpen, updated.Status.OpenedAt != nil)
+			assertEvents(t, recorder, tt.expectedEvent...)
+		})
+	}
+}
+
+func TestBarrierReconciler_ArrivalRetention(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	tests := []struct {
+		name            string
+		phase           syncv1.BarrierPhase
+		finishedAgo     time.Duration
+		expectCollected bool
+	}{
+		{
+			name:            "opened barrier keeps arrivals within retention",
+			phase:           syncv1.BarrierPhaseOpen,
+			finishedAgo:     10 * time.Minute,
+			expectCollected: false,
+		},
+		{
+			name:            "opened barrier collects arrivals after retention",
+			phase:           syncv1.BarrierPhaseOpen,
+			finishedAgo:     2 * time.Hour,
+			expectCollected: true,
+		},
+		{
+			name:            "failed barrier collects arrivals after retention",
+			phase:           syncv1.BarrierPhaseFailed,
+			finishedAgo:     2 * time.Hour,
+			expectCollected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finishedAt := time.Now().Add(-tt.finishedAgo)
+			openedAt := metav1.NewTime(finishedAt)
+
+			barrier := &syncv1.Barrier{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-barrier",
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(finishedAt.Add(-time.Minute)),
+				},
+				Spec: syncv1.BarrierSpec{
+					Expected: 2,
+					Timeout:  &metav1.Duration{Duration: time.Minute},
+				},
+				Status: syncv1.BarrierStatus{
+					Phase:    tt.phase,
+					Arrived:  1,
+					Arrivals: []string{"holder-1"},
+					OpenedAt: &openedAt,
+				},
+			}
+			if tt.phase == syncv1.BarrierPhaseOpen {
+				barrier.Spec.Expected = 1
+			}
+
+			arrival := &syncv1.Arrival{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "arrival-1",
+					Namespace: "default",
+					Labels:    map[string]string{"barrier": "test-barrier"},
+				},
+				Spec: syncv1.ArrivalSpec{
+					Barrier: "test-barrier",
+					Holder:  "holder-1",
+				},
+			}
+
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(barrier, arrival).
+				WithStatusSubresource(&syncv1.Barrier{}).
+				Build()
+
+			reconciler := &BarrierReconciler{
+				Client:           client,
+				Scheme:           scheme,
+				ArrivalRetention: time.Hour,
+			}
+
+			req := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      barrier.Name,
+					Namespace: barrier.Namespace,
+				},
+			}
+
+			result, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var arrivals syncv1.ArrivalList
+			require.NoError(t, client.List(context.Background(), &arrivals))
+			if tt.expectCollected {
+				assert.Empty(t, arrivals.Items)
+				assert.Equal(t, ctrl.Result{}, result)
+			} else {
+				assert.Len(t, arrivals.Items, 1)
+				assert.InDelta(t, 50*time.Minute, result.RequeueAfter, float64(time.Second))
+			}
+
+			// Collecting arrivals must not reopen or reset a finished barrier
+			_, err = reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated syncv1.Barrier
+			require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+			assert.Equal(t, tt.phase, updated.Status.Phase)
+			assert.Equal(t, int32(1), updated.Status.Arrived)
+		})
+	}
+}
+
+func TestBarrierReconciler_Reset(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	createdAt := time.Now().Add(-time.Hour)
+	resetAt := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	// The barrier was created long enough ago that its timeout would have
+	// passed, but it was just reset for another round
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-barrier",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(createdAt),
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+			Timeout:  &metav1.Duration{Duration: 5 * time.Minute},
+		},
+		Status: syncv1.BarrierStatus{
+			Phase:   syncv1.BarrierPhaseWaiting,
+			ResetAt: &resetAt,
+		},
+	}
+
+	newArrival := func(name, holder string, created time.Time) *syncv1.Arrival {
+		return &syncv1.Arrival{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"barrier": "test-barrier"},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: syncv1.ArrivalSpec{
+				Barrier: "test-barrier",
+				Holder:  holder,
+			},
+		}
+	}
+
+	// Arrivals from the previous round that have not been deleted yet
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(barrier,
+			newArrival("stale-1", "holder-1", createdAt),
+			newArrival("stale-2", "holder-2", createdAt)).
+		WithStatusSubresource(&syncv1.Barrier{}).
+		Build()
+
+	reconciler := &BarrierReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      barrier.Name,
+			Namespace: barrier.Namespace,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Barrier
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.BarrierPhaseWaiting, updated.Status.Phase)
+	assert.Equal(t, int32(0), updated.Status.Arrived)
+	assert.Nil(t, updated.Status.OpenedAt)
+
+	// Refilling the barrier in the new round opens it again
+	for _, holder := range []string{"holder-1", "holder-2"} {
+		require.NoError(t, client.Create(context.Background(), newArrival("new-"+holder, holder, time.Now())))
+	}
+
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.BarrierPhaseOpen, updated.Status.Phase)
+	assert.Equal(t, int32(2), updated.Status.Arrived)
+	assert.ElementsMatch(t, []string{"holder-1", "holder-2"}, updated.Status.Arrivals)
+	assert.NotNil(t, updated.Status.OpenedAt)
+}
+
+func TestBarrierReconciler_CyclicGenerations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-barrier",
+			Namespace:         "default",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+			Cyclic:   true,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(barrier).
+		WithStatusSubresource(&syncv1.Barrier{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &BarrierReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      barrier.Name,
+			Namespace: barrier.Namespace,
+		},
+	}
+
+	arrive := func(holder string, generation int64) {
+		require.NoError(t, client.Create(context.Background(), &syncv1.Arrival{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-barrier-%s-%d", holder, generation),
+				Namespace: "default",
+				Labels:    map[string]string{"barrier": "test-barrier"},
+			},
+			Spec: syncv1.ArrivalSpec{
+				Barrier:    "test-barrier",
+				Holder:     holder,
+				Generation: generation,
+			},
+		}))
+	}
+
+	reconcile := func() syncv1.Barrier {
+		_, err := reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		var updated syncv1.Barrier
+		require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+		return updated
+	}
+
+	remainingArrivals := func() []string {
+		var arrivals syncv1.ArrivalList
+		require.NoError(t, client.List(context.Background(), &arrivals))
+		names := make([]string, 0, len(arrivals.Items))
+		for _, arrival := range arrivals.Items {
+			names = append(names, arrival.Name)
+		}
+		return names
+	}
+
+	// Generation 0 opens once both holders arrive, and the barrier moves
+	// straight on to generation 1
+	arrive("holder-1", 0)
+	updated := reconcile()
+	assert.Equal(t, int32(1), updated.Status.Arrived)
+	assert.Equal(t, int64(0), updated.Status.Generation)
+
+	arrive("holder-2", 0)
+	updated = reconcile()
+	assert.Equal(t, syncv1.BarrierPhaseWaiting, updated.Status.Phase)
+	assert.Equal(t, int64(1), updated.Status.Generation)
+	assert.Equal(t, int32(0), updated.Status.Arrived)
+	assert.Empty(t, updated.Status.Arrivals)
+	assert.NotNil(t, updated.Status.OpenedAt)
+	assertEvents(t, recorder, "Normal BarrierOpened Barrier generation 0 opened with 2 of 2 arrivals")
+	assert.Empty(t, remainingArrivals())
+
+	// A late arrival tagged with the completed generation is not counted
+	// towards generation 1 and is collected
+	arrive("holder-3", 0)
+	arrive("holder-1", 1)
+	updated = reconcile()
+	assert.Equal(t, int64(1), updated.Status.Generation)
+	assert.Equal(t, int32(1), updated.Status.Arrived)
+	assert.Equal(t, []string{"holder-1"}, updated.Status.Arrivals)
+	assert.Equal(t, []string{"test-barrier-holder-1-1"}, remainingArrivals())
+
+	// Generation 1 opens the same way
+	arrive("holder-2", 1)
+	updated = reconcile()
+	assert.Equal(t, syncv1.BarrierPhaseWaiting, updated.Status.Phase)
+	assert.Equal(t, int64(2), updated.Status.Generation)
+	assert.Equal(t, int32(0), updated.Status.Arrived)
+	assertEvents(t, recorder, "Normal BarrierOpened Barrier generation 1 opened with 2 of 2 arrivals")
+	assert.Empty(t, remainingArrivals())
 }
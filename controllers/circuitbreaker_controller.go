@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// CircuitBreakerReconciler reconciles a CircuitBreaker object
+type CircuitBreakerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=circuitbreakers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=circuitbreakers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=circuitbreakers/finalizers,verbs=update
+
+func (r *CircuitBreakerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("CircuitBreaker", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "CircuitBreaker", req)
+
+	var cb syncv1.CircuitBreaker
+	if err := r.Get(ctx, req.NamespacedName, &cb); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch CircuitBreaker")
+		return ctrl.Result{}, err
+	}
+
+	// Check TTL expiration
+	if cb.Spec.TTL != nil {
+		expirationTime := cb.CreationTimestamp.Add(cb.Spec.TTL.Duration)
+		if time.Now().After(expirationTime) {
+			if err := r.Delete(ctx, &cb); err != nil {
+				log.Error(err, "unable to delete expired CircuitBreaker")
+				return ctrl.Result{RequeueAfter: time.Second}, err
+			}
+			log.Info("Deleted expired CircuitBreaker", "name", cb.Name)
+			recordNormal(r.Recorder, &cb, ReasonCircuitBreakerExpired, "Deleted after TTL of %s", cb.Spec.TTL.Duration)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	previous := cb.Status.DeepCopy()
+	next := transitionCircuitBreaker(&cb, time.Now())
+	stateChanged := cb.Status.State != previous.State
+
+	generationChanged := observeGeneration(&cb.Status.ObservedGeneration, &cb)
+	if stateChanged || !cb.Status.StateChangedAt.Equal(previous.StateChangedAt) || generationChanged {
+		if err := r.Status().Update(ctx, &cb); err != nil {
+			if errors.IsConflict(err) {
+				// A caller recorded a result in the meantime; start from its update
+				return ctrl.Result{Requeue: true}, nil
+			}
+			log.Error(err, "unable to update CircuitBreaker status")
+			return ctrl.Result{RequeueAfter: time.Second}, err
+		}
+	}
+
+	reason := fmt.Sprintf("%d failures, %d successes", cb.Status.Failures, cb.Status.Successes)
+	if stateChanged {
+		switch cb.Status.State {
+		case syncv1.CircuitBreakerStateClosed:
+			reason = "initialized"
+		case syncv1.CircuitBreakerStateHalfOpen:
+			reason = fmt.Sprintf("reset timeout of %s elapsed", circuitBreakerResetTimeout(&cb))
+			recordNormal(r.Recorder, &cb, ReasonCircuitBreakerHalfOpen,
+				"Letting %d trial requests through after %s open", circuitBreakerSuccessThreshold(&cb), circuitBreakerResetTimeout(&cb))
+		case syncv1.CircuitBreakerStateOpen:
+			reason = "trial requests went unresolved"
+			recordWarning(r.Recorder, &cb, ReasonCircuitBreakerOpened,
+				"Reopened after trial requests went unresolved for %s", circuitBreakerResetTimeout(&cb))
+		}
+	}
+
+	logPhase(log, previous.State, cb.Status.State, reason)
+
+	requeueAfter := next
+	if cb.Spec.TTL != nil {
+		untilExpiry := time.Until(cb.CreationTimestamp.Add(cb.Spec.TTL.Duration))
+		if requeueAfter == 0 || untilExpiry < requeueAfter {
+			requeueAfter = untilExpiry
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// transitionCircuitBreaker applies the state changes that are due on time
+// alone and returns how long until the next one, or zero when none is
+// pending. Callers record failures and successes themselves; the controller
+// only lets an open breaker go half-open once its reset timeout has elapsed,
+// and reopens a half-open breaker whose trial requests were all handed out
+// but never resolved within the same timeout, so a caller that died mid-trial
+// cannot wedge it.
+func transitionCircuitBreaker(cb *syncv1.CircuitBreaker, now time.Time) time.Duration {
+	if cb.Status.State == "" {
+		setCircuitBreakerState(cb, syncv1.CircuitBreakerStateClosed, now)
+		return 0
+	}
+	if cb.Status.StateChangedAt == nil {
+		changedAt := metav1.NewTime(now)
+		cb.Status.StateChangedAt = &changedAt
+	}
+
+	deadline := cb.Status.StateChangedAt.Add(circuitBreakerResetTimeout(cb))
+
+	switch cb.Status.State {
+	case syncv1.CircuitBreakerStateOpen:
+		if now.Before(deadline) {
+			return deadline.Sub(now)
+		}
+		setCircuitBreakerState(cb, syncv1.CircuitBreakerStateHalfOpen, now)
+	case syncv1.CircuitBreakerStateHalfOpen:
+		if cb.Status.TrialRequests < circuitBreakerSuccessThreshold(cb) {
+			// Trial slots are still free; the next caller resolves the state
+			return 0
+		}
+		if now.Before(deadline) {
+			return deadline.Sub(now)
+		}
+		setCircuitBreakerState(cb, syncv1.CircuitBreakerStateOpen, now)
+		return circuitBreakerResetTimeout(cb)
+	}
+	return 0
+}
+
+// setCircuitBreakerState moves cb to state, resetting the trial counters
+func setCircuitBreakerState(cb *syncv1.CircuitBreaker, state syncv1.CircuitBreakerState, now time.Time) {
+	changedAt := metav1.NewTime(now)
+	cb.Status.State = state
+	cb.Status.StateChangedAt = &changedAt
+	cb.Status.TrialRequests = 0
+	cb.Status.Successes = 0
+}
+
+// circuitBreakerSuccessThreshold returns the number of successful trials that
+// closes the breaker, which defaults to DefaultCircuitBreakerSuccessThreshold
+func circuitBreakerSuccessThreshold(cb *syncv1.CircuitBreaker) int32 {
+	if cb.Spec.SuccessThreshold > 0 {
+		return cb.Spec.SuccessThreshold
+	}
+	return syncv1.DefaultCircuitBreakerSuccessThreshold
+}
+
+// circuitBreakerResetTimeout returns how long the breaker stays open, which
+// defaults to DefaultCircuitBreakerResetTimeout
+func circuitBreakerResetTimeout(cb *syncv1.CircuitBreaker) time.Duration {
+	if cb.Spec.ResetTimeout != nil && cb.Spec.ResetTimeout.Duration > 0 {
+		return cb.Spec.ResetTimeout.Duration
+	}
+	return syncv1.DefaultCircuitBreakerResetTimeout
+}
+
+func (r *CircuitBreakerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("circuitbreaker-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&syncv1.CircuitBreaker{}).
+		Complete(trackHealth("circuitbreaker", r))
+}
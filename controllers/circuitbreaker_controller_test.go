@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func newTestCircuitBreaker(state syncv1.CircuitBreakerState, changedAt *time.Time) *syncv1.CircuitBreaker {
+	cb := &syncv1.CircuitBreaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-circuitbreaker",
+			Namespace: "default",
+		},
+		Spec: syncv1.CircuitBreakerSpec{
+			FailureThreshold: 3,
+			SuccessThreshold: 2,
+			ResetTimeout:     &metav1.Duration{Duration: 30 * time.Second},
+		},
+		Status: syncv1.CircuitBreakerStatus{
+			State: state,
+		},
+	}
+	if changedAt != nil {
+		at := metav1.NewTime(*changedAt)
+		cb.Status.StateChangedAt = &at
+	}
+	return cb
+}
+
+func TestTransitionCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+	withTrials := func(cb *syncv1.CircuitBreaker, trials, successes int32) *syncv1.CircuitBreaker {
+		cb.Status.TrialRequests = trials
+		cb.Status.Successes = successes
+		return cb
+	}
+
+	tests := []struct {
+		name          string
+		circuit       *syncv1.CircuitBreaker
+		wantState     syncv1.CircuitBreakerState
+		wantChangedAt time.Time
+		wantTrials    int32
+		wantNext      time.Duration
+	}{
+		{
+			name:          "new breaker starts closed",
+			circuit:       newTestCircuitBreaker("", nil),
+			wantState:     syncv1.CircuitBreakerStateClosed,
+			wantChangedAt: now,
+		},
+		{
+			name:          "closed stays closed",
+			circuit:       newTestCircuitBreaker(syncv1.CircuitBreakerStateClosed, at(time.Hour)),
+			wantState:     syncv1.CircuitBreakerStateClosed,
+			wantChangedAt: now.Add(-time.Hour),
+		},
+		{
+			name:          "open waits out the reset timeout",
+			circuit:       newTestCircuitBreaker(syncv1.CircuitBreakerStateOpen, at(10*time.Second)),
+			wantState:     syncv1.CircuitBreakerStateOpen,
+			wantChangedAt: now.Add(-10 * time.Second),
+			wantNext:      20 * time.Second,
+		},
+		{
+			name:          "open goes half-open after the reset timeout",
+			circuit:       newTestCircuitBreaker(syncv1.CircuitBreakerStateOpen, at(30*time.Second)),
+			wantState:     syncv1.CircuitBreakerStateHalfOpen,
+			wantChangedAt: now,
+		},
+		{
+			name:          "half-open with free trial slots waits for callers",
+			circuit:       withTrials(newTestCircuitBreaker(syncv1.CircuitBreakerStateHalfOpen, at(time.Hour)), 1, 0),
+			wantState:     syncv1.CircuitBreakerStateHalfOpen,
+			wantChangedAt: now.Add(-time.Hour),
+			wantTrials:    1,
+		},
+		{
+			name:          "half-open with trials in flight waits for their results",
+			circuit:       withTrials(newTestCircuitBreaker(syncv1.CircuitBreakerStateHalfOpen, at(5*time.Second)), 2, 1),
+			wantState:     syncv1.CircuitBreakerStateHalfOpen,
+			wantChangedAt: now.Add(-5 * time.Second),
+			wantTrials:    2,
+			wantNext:      25 * time.Second,
+		},
+		{
+			name:          "half-open reopens when trials go unresolved",
+			circuit:       withTrials(newTestCircuitBreaker(syncv1.CircuitBreakerStateHalfOpen, at(time.Minute)), 2, 1),
+			wantState:     syncv1.CircuitBreakerStateOpen,
+			wantChangedAt: now,
+			wantNext:      30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := transitionCircuitBreaker(tt.circuit, now)
+
+			assert.Equal(t, tt.wantState, tt.circuit.Status.State)
+			require.NotNil(t, tt.circuit.Status.StateChangedAt)
+			assert.True(t, tt.wantChangedAt.Equal(tt.circuit.Status.StateChangedAt.Time),
+				"state changed at %v, want %v", tt.circuit.Status.StateChangedAt.Time, tt.wantChangedAt)
+			assert.Equal(t, tt.wantTrials, tt.circuit.Status.TrialRequests)
+			assert.Equal(t, tt.wantNext, next)
+		})
+	}
+}
+
+func TestCircuitBreakerDefaults(t *testing.T) {
+	cb := &syncv1.CircuitBreaker{}
+	assert.Equal(t, syncv1.DefaultCircuitBreakerSuccessThreshold, circuitBreakerSuccessThreshold(cb))
+	assert.Equal(t, syncv1.DefaultCircuitBreakerResetTimeout, circuitBreakerResetTimeout(cb))
+}
+
+func TestCircuitBreakerReconciler_HalfOpensAfterResetTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	openedAt := time.Now().Add(-time.Minute)
+	cb := newTestCircuitBreaker(syncv1.CircuitBreakerStateOpen, &openedAt)
+	cb.Status.Failures = 3
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(cb).
+		WithStatusSubresource(&syncv1.CircuitBreaker{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &CircuitBreakerReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: cb.Name, Namespace: cb.Namespace},
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	var updated syncv1.CircuitBreaker
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.CircuitBreakerStateHalfOpen, updated.Status.State)
+	assert.Equal(t, int32(0), updated.Status.TrialRequests)
+	assert.Equal(t, int32(3), updated.Status.Failures)
+	assertEvents(t, recorder, "Normal CircuitBreakerHalfOpen Letting 2 trial requests through after 30s open")
+}
+
+func TestCircuitBreakerReconciler_OpenRequeuesForResetTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	openedAt := time.Now().Add(-10 * time.Second)
+	cb := newTestCircuitBreaker(syncv1.CircuitBreakerStateOpen, &openedAt)
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(cb).
+		WithStatusSubresource(&syncv1.CircuitBreaker{}).
+		Build()
+
+	reconciler := &CircuitBreakerReconciler{Client: client, Scheme: scheme}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: cb.Name, Namespace: cb.Namespace},
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, 15*time.Second)
+	assert.LessOrEqual(t, result.RequeueAfter, 20*time.Second)
+
+	var updated syncv1.CircuitBreaker
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.CircuitBreakerStateOpen, updated.Status.State)
+}
+
+func TestCircuitBreakerReconciler_InitialisesNewBreaker(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	cb := newTestCircuitBreaker("", nil)
+	cb.Generation = 1
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(cb).
+		WithStatusSubresource(&syncv1.CircuitBreaker{}).
+		Build()
+
+	reconciler := &CircuitBreakerReconciler{Client: client, Scheme: scheme}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: cb.Name, Namespace: cb.Namespace},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.CircuitBreaker
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.CircuitBreakerStateClosed, updated.Status.State)
+	assert.NotNil(t, updated.Status.StateChangedAt)
+	assert.Equal(t, updated.Generation, updated.Status.ObservedGeneration)
+}
+
+func TestCircuitBreakerReconciler_TTLExpired(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	cb := newTestCircuitBreaker(syncv1.CircuitBreakerStateClosed, nil)
+	cb.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	cb.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(cb).
+		WithStatusSubresource(&syncv1.CircuitBreaker{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &CircuitBreakerReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: cb.Name, Namespace: cb.Namespace},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.CircuitBreaker
+	err = client.Get(context.Background(), req.NamespacedName, &updated)
+	assert.True(t, apierrors.IsNotFound(err))
+	assertEvents(t, recorder, "Normal CircuitBreakerExpired Deleted after TTL of 1h0m0s")
+}
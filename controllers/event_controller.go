@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// EventReconciler reconciles an Event object
+type EventReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=events,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=events/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=events/finalizers,verbs=update
+
+func (r *EventReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("Event", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "Event", req)
+
+	var event syncv1.Event
+	if err := r.Get(ctx, req.NamespacedName, &event); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch Event")
+		return ctrl.Result{}, err
+	}
+
+	// Check TTL expiration
+	if event.Spec.TTL != nil {
+		expirationTime := event.CreationTimestamp.Add(event.Spec.TTL.Duration)
+		if time.Now().After(expirationTime) {
+			if err := r.Delete(ctx, &event); err != nil {
+				log.Error(err, "unable to delete expired Event")
+				return ctrl.Result{RequeueAfter: time.Second}, err
+			}
+			log.Info("Deleted expired Event", "name", event.Name)
+			recordNormal(r.Recorder, &event, ReasonEventExpired, "Deleted after TTL of %s", event.Spec.TTL.Duration)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Keep the phase in line with the signaled flag, which clients flip
+	// directly through Set and Clear
+	phase := syncv1.EventPhaseCleared
+	reason := "not signaled"
+	if event.Status.Signaled {
+		phase = syncv1.EventPhaseSet
+		reason = "signaled"
+	}
+
+	oldPhase := event.Status.Phase
+	generationChanged := observeGeneration(&event.Status.ObservedGeneration, &event)
+	if event.Status.Phase != phase || generationChanged {
+		event.Status.Phase = phase
+		if err := r.Status().Update(ctx, &event); err != nil {
+			log.Error(err, "unable to update Event phase")
+			return ctrl.Result{RequeueAfter: time.Second}, err
+		}
+	}
+	logPhase(log, oldPhase, phase, reason)
+
+	if event.Spec.TTL != nil {
+		return ctrl.Result{RequeueAfter: time.Until(event.CreationTimestamp.Add(event.Spec.TTL.Duration))}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *EventReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("event-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&syncv1.Event{}).
+		Complete(trackHealth("event", r))
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func TestEventReconciler_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	tests := []struct {
+		name          string
+		event         *syncv1.Event
+		expectedPhase syncv1.EventPhase
+	}{
+		{
+			name: "new event",
+			event: &syncv1.Event{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-event",
+					Namespace: "default",
+				},
+			},
+			expectedPhase: syncv1.EventPhaseCleared,
+		},
+		{
+			name: "signaled event",
+			event: &syncv1.Event{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-event",
+					Namespace: "default",
+				},
+				Status: syncv1.EventStatus{
+					Phase:    syncv1.EventPhaseCleared,
+					Signaled: true,
+				},
+			},
+			expectedPhase: syncv1.EventPhaseSet,
+		},
+		{
+			name: "cleared event",
+			event: &syncv1.Event{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-event",
+					Namespace: "default",
+				},
+				Status: syncv1.EventStatus{
+					Phase:    syncv1.EventPhaseSet,
+					Signaled: false,
+				},
+			},
+			expectedPhase: syncv1.EventPhaseCleared,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(tt.event).
+				WithStatusSubresource(&syncv1.Event{}).
+				Build()
+
+			reconciler := &EventReconciler{
+				Client: client,
+				Scheme: scheme,
+			}
+
+			req := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      tt.event.Name,
+					Namespace: tt.event.Namespace,
+				},
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated syncv1.Event
+			err = client.Get(context.Background(), req.NamespacedName, &updated)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+		})
+	}
+}
+
+func TestEventReconciler_TTLExpired(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	event := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-event",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+		},
+		Spec: syncv1.EventSpec{
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(event).
+		WithStatusSubresource(&syncv1.Event{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &EventReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      event.Name,
+			Namespace: event.Namespace,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Event
+	err = client.Get(context.Background(), req.NamespacedName, &updated)
+	assert.True(t, apierrors.IsNotFound(err))
+	assertEvents(t, recorder, "Normal EventExpired Deleted after TTL of 1h0m0s")
+}
@@ -2,12 +2,17 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -15,159 +20,186 @@ import (
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
 
+const (
+	// gateMinRequeue is the first requeue interval for a waiting gate
+	gateMinRequeue = time.Second
+
+	// DefaultGateMaxRequeue caps the requeue interval when MaxRequeue is unset
+	DefaultGateMaxRequeue = 30 * time.Second
+)
+
 // GateReconciler reconciles a Gate object
 type GateReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MaxRequeue caps the backoff between checks of a waiting gate.
+	// Defaults to DefaultGateMaxRequeue.
+	MaxRequeue time.Duration
+
+	// APIReader reads the ConfigMaps, Secrets and pods of gate conditions
+	// straight from the API server, so the manager does not cache every one
+	// in the cluster. Defaults to the manager's API reader, or to Client
+	// outside a manager.
+	APIReader client.Reader
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=gates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=gates/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=gates/finalizers,verbs=update
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 
-func (r *GateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
-
-	log.Info("Reconciling Gate", "name", req.Name, "namespace", req.Namespace)
+func (r *GateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("Gate", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "Gate", req)
 
 	var gate syncv1.Gate
 	if err := r.Get(ctx, req.NamespacedName, &gate); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Gate not found, likely deleted", "name", req.Name)
+			gateConditionsMet.DeleteLabelValues(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "unable to fetch Gate")
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Found Gate", "name", gate.Name, "conditions", len(gate.Spec.Conditions), "currentPhase", gate.Status.Phase)
+	log.V(1).Info("Found Gate", "conditions", len(gate.Spec.Conditions), "currentPhase", gate.Status.Phase)
 
-	allMet := true
+	original := gate.Status.DeepCopy()
 	conditionStatuses := make([]syncv1.GateConditionStatus, len(gate.Spec.Conditions))
+	metCount := 0
+	now := metav1.Now()
 
-	for i, condition := range gate.Spec.Conditions {
-		status := syncv1.GateConditionStatus{
-			Type: condition.Type,
-			Name: condition.Name,
-			Met:  false,
-		}
+	// timedOut lists the conditions whose own timeout has passed unmet, and
+	// deadline is the earliest timeout still to come
+	var timedOut []string
+	var deadline time.Time
 
+	for i, condition := range gate.Spec.Conditions {
 		namespace := condition.Namespace
 		if namespace == "" {
 			namespace = gate.Namespace
 		}
 
-		switch condition.Type {
-		case "Job":
-			var job batchv1.Job
-			if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &job); err != nil {
-				if errors.IsNotFound(err) {
-					log.V(1).Info("Job not found for gate condition", "job", condition.Name, "namespace", namespace)
-					status.Message = "Job not found"
-				} else {
-					log.Error(err, "Failed to get Job for gate condition", "job", condition.Name, "namespace", namespace)
-					status.Message = "Failed to get Job"
-				}
-				allMet = false
-			} else {
-				if condition.State == "Complete" && job.Status.Succeeded > 0 {
-					status.Met = true
-					status.Message = "Job completed successfully"
-				} else {
-					status.Message = "Job not in required state"
-					allMet = false
-				}
-			}
-
-		case "Semaphore":
-			var semaphore syncv1.Semaphore
-			if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &semaphore); err != nil {
-				status.Message = "Semaphore not found"
-				allMet = false
-			} else {
-				if condition.Value != nil && semaphore.Status.Available >= *condition.Value {
-					status.Met = true
-					status.Message = "Semaphore has required permits"
-				} else {
-					status.Message = "Semaphore does not have required permits"
-					allMet = false
-				}
-			}
-
-		case "Barrier":
-			var barrier syncv1.Barrier
-			if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &barrier); err != nil {
-				status.Message = "Barrier not found"
-				allMet = false
-			} else {
-				if condition.State == "Open" && barrier.Status.Phase == syncv1.BarrierPhaseOpen {
-					status.Met = true
-					status.Message = "Barrier is open"
-				} else {
-					status.Message = "Barrier is not open"
-					allMet = false
-				}
-			}
-
-		case "Lease":
-			var lease syncv1.Lease
-			if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &lease); err != nil {
-				status.Message = "Lease not found"
-				allMet = false
+		status, observed := r.evaluateCondition(ctx, condition, namespace)
+		// Negation inverts the state of a resource that was read. A resource
+		// that could not be read leaves the condition unmet either way.
+		if condition.Negate && observed {
+			status.Met = !status.Met
+			status.Message += " (negated)"
+		}
+		if status.Met {
+			metCount++
+		}
+
+		status.StartedAt = conditionStartedAt(original.ConditionStatuses, i, status, now)
+		if condition.Timeout != nil && !status.Met {
+			conditionDeadline := status.StartedAt.Add(condition.Timeout.Duration)
+			if now.Time.Before(conditionDeadline) {
+				deadline = earliest(deadline, conditionDeadline)
 			} else {
-				if condition.State == "Available" && lease.Status.Phase == syncv1.LeasePhaseAvailable {
-					status.Met = true
-					status.Message = "Lease is available"
-				} else {
-					status.Message = "Lease is not available"
-					allMet = false
-				}
+				status.Message = fmt.Sprintf("Timed out after %s: %s", condition.Timeout.Duration, status.Message)
+				timedOut = append(timedOut, fmt.Sprintf("%s %s after %s", condition.Type, condition.Name, condition.Timeout.Duration))
 			}
-
-		default:
-			status.Message = "Unknown condition type"
-			allMet = false
 		}
 
 		conditionStatuses[i] = status
 	}
 
+	// And, the default, needs every condition; Or needs any one of them
+	open := metCount == len(conditionStatuses)
+	// A timed-out condition fails an And gate, which can no longer open
+	// without it. An Or gate only fails once all of them have timed out.
+	conditionFailed := len(timedOut) > 0
+	if gate.Spec.Logic == syncv1.GateLogicOr {
+		open = metCount > 0
+		conditionFailed = len(timedOut) == len(conditionStatuses)
+	}
+
+	gateTimedOut := false
+	if gate.Spec.Timeout != nil {
+		gateDeadline := gate.CreationTimestamp.Add(gate.Spec.Timeout.Duration)
+		gateTimedOut = gateDeadline.Before(now.Time)
+		deadline = earliest(deadline, gateDeadline)
+	}
+
+	oldPhase := gate.Status.Phase
+	changed := conditionsChanged(gate.Status.ConditionStatuses, conditionStatuses)
 	gate.Status.ConditionStatuses = conditionStatuses
 
-	if allMet {
+	if open {
 		gate.Status.Phase = syncv1.GatePhaseOpen
 		if gate.Status.OpenedAt == nil {
-			now := metav1.Now()
 			gate.Status.OpenedAt = &now
 		}
+	} else if conditionFailed || gateTimedOut {
+		gate.Status.Phase = syncv1.GatePhaseFailed
 	} else {
-		if gate.Spec.Timeout != nil && gate.CreationTimestamp.Add(gate.Spec.Timeout.Duration).Before(time.Now()) {
-			gate.Status.Phase = syncv1.GatePhaseFailed
-		} else {
-			gate.Status.Phase = syncv1.GatePhaseWaiting
+		gate.Status.Phase = syncv1.GatePhaseWaiting
+	}
+
+	// The interval follows from the time since a condition last changed, not
+	// from how often the gate was reconciled, so the reconciles its own status
+	// writes trigger do not speed up the backoff
+	if gate.Status.Phase == syncv1.GatePhaseWaiting {
+		if changed || gate.Status.LastConditionChangeAt == nil {
+			gate.Status.LastConditionChangeAt = &now
+		}
+		gate.Status.NextCheckInterval = &metav1.Duration{
+			Duration: r.nextCheckInterval(now.Sub(gate.Status.LastConditionChangeAt.Time)),
 		}
+	} else {
+		gate.Status.LastConditionChangeAt = nil
+		gate.Status.NextCheckInterval = nil
 	}
 
-	if err := r.Status().Update(ctx, &gate); err != nil {
-		log.Error(err, "unable to update Gate status")
-		return ctrl.Result{}, err
+	observeGeneration(&gate.Status.ObservedGeneration, &gate)
+	if !equality.Semantic.DeepEqual(original, &gate.Status) {
+		if err := r.Status().Update(ctx, &gate); err != nil {
+			log.Error(err, "unable to update Gate status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	reason := fmt.Sprintf("%d of %d conditions met", metCount, len(conditionStatuses))
+	if len(timedOut) > 0 {
+		reason += ", timed out: " + strings.Join(timedOut, ", ")
 	}
+	logPhase(log, oldPhase, gate.Status.Phase, reason)
 
-	log.Info("Successfully updated Gate status", "name", gate.Name, "phase", gate.Status.Phase, "allMet", allMet)
+	if oldPhase != gate.Status.Phase {
+		switch gate.Status.Phase {
+		case syncv1.GatePhaseOpen:
+			if gate.Spec.Logic == syncv1.GateLogicOr {
+				recordNormal(r.Recorder, &gate, ReasonGateOpened, "%d of %d conditions met", metCount, len(conditionStatuses))
+			} else {
+				recordNormal(r.Recorder, &gate, ReasonGateOpened, "All %d conditions met", len(conditionStatuses))
+			}
+		case syncv1.GatePhaseFailed:
+			if conditionFailed {
+				recordWarning(r.Recorder, &gate, ReasonGateFailed, "Condition timed out: %s", strings.Join(timedOut, ", "))
+			} else {
+				recordWarning(r.Recorder, &gate, ReasonGateFailed, "Gate timed out after %s with conditions unmet", gate.Spec.Timeout.Duration)
+			}
+		}
+	}
+
+	gateConditionsMet.WithLabelValues(gate.Namespace, gate.Name).Set(float64(metCount))
 
 	if gate.Status.Phase == syncv1.GatePhaseWaiting {
-		requeueAfter := 10 * time.Second
-		if gate.Spec.Timeout != nil {
-			remaining := time.Until(gate.CreationTimestamp.Add(gate.Spec.Timeout.Duration))
-			if remaining > 0 {
-				// Use 10% of remaining time, bounded between 1s and 30s
-				requeueAfter = remaining / 10
-				if requeueAfter < time.Second {
-					requeueAfter = time.Second
-				} else if requeueAfter > 30*time.Second {
-					requeueAfter = 30 * time.Second
-				}
+		requeueAfter := gate.Status.NextCheckInterval.Duration
+		if !deadline.IsZero() {
+			// Don't sleep past the next deadline, so the gate fails on time
+			remaining := time.Until(deadline)
+			if remaining < gateMinRequeue {
+				remaining = gateMinRequeue
+			}
+			if remaining < requeueAfter {
+				requeueAfter = remaining
 			}
 		}
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
@@ -176,8 +208,281 @@ func (r *GateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	return ctrl.Result{}, nil
 }
 
+// evaluateCondition checks a single gate condition against the resource it
+// names. observed is false if the resource could not be read, so the status
+// reflects its absence rather than its state.
+func (r *GateReconciler) evaluateCondition(ctx context.Context, condition syncv1.GateCondition, namespace string) (status syncv1.GateConditionStatus, observed bool) {
+	log := log.FromContext(ctx)
+
+	status = syncv1.GateConditionStatus{
+		Type: condition.Type,
+		Name: condition.Name,
+		Met:  false,
+	}
+	observed = true
+
+	switch condition.Type {
+	case "Job":
+		var job batchv1.Job
+		if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &job); err != nil {
+			if errors.IsNotFound(err) {
+				log.V(1).Info("Job not found for gate condition", "job", condition.Name, "namespace", namespace)
+				status.Message = "Job not found"
+			} else {
+				log.Error(err, "Failed to get Job for gate condition", "job", condition.Name, "namespace", namespace)
+				status.Message = "Failed to get Job"
+			}
+			observed = false
+		} else {
+			if condition.State == "Complete" && job.Status.Succeeded > 0 {
+				status.Met = true
+				status.Message = "Job completed successfully"
+			} else {
+				status.Message = "Job not in required state"
+			}
+		}
+
+	case "Semaphore":
+		var semaphore syncv1.Semaphore
+		if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &semaphore); err != nil {
+			status.Message = "Semaphore not found"
+			observed = false
+		} else {
+			if condition.Value != nil && semaphore.Status.Available >= *condition.Value {
+				status.Met = true
+				status.Message = "Semaphore has required permits"
+			} else {
+				status.Message = "Semaphore does not have required permits"
+			}
+		}
+
+	case "Barrier":
+		var barrier syncv1.Barrier
+		if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &barrier); err != nil {
+			status.Message = "Barrier not found"
+			observed = false
+		} else {
+			if condition.State == "Open" && barrier.Status.Phase == syncv1.BarrierPhaseOpen {
+				status.Met = true
+				status.Message = "Barrier is open"
+			} else {
+				status.Message = "Barrier is not open"
+			}
+		}
+
+	case "Lease":
+		var lease syncv1.Lease
+		if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &lease); err != nil {
+			status.Message = "Lease not found"
+			observed = false
+		} else {
+			if condition.State == "Available" && lease.Status.Phase == syncv1.LeasePhaseAvailable {
+				status.Met = true
+				status.Message = "Lease is available"
+			} else {
+				status.Message = "Lease is not available"
+			}
+		}
+
+	case "Mutex":
+		var mutex syncv1.Mutex
+		if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &mutex); err != nil {
+			status.Message = "Mutex not found"
+			observed = false
+		} else {
+			// A mutex nobody has locked yet may not have a phase
+			phase := mutex.Status.Phase
+			if phase == "" {
+				phase = syncv1.MutexPhaseUnlocked
+			}
+			if condition.State == string(phase) {
+				status.Met = true
+				status.Message = fmt.Sprintf("Mutex is %s", phase)
+			} else {
+				status.Message = fmt.Sprintf("Mutex is %s, waiting for %s", phase, condition.State)
+			}
+		}
+
+	case "RWMutex":
+		var rwmutex syncv1.RWMutex
+		if err := r.Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &rwmutex); err != nil {
+			status.Message = "RWMutex not found"
+			observed = false
+		} else {
+			phase := rwmutex.Status.Phase
+			if phase == "" {
+				phase = syncv1.RWMutexPhaseUnlocked
+			}
+			if condition.State == string(phase) {
+				status.Met = true
+				status.Message = fmt.Sprintf("RWMutex is %s", phase)
+			} else {
+				status.Message = fmt.Sprintf("RWMutex is %s, waiting for %s", phase, condition.State)
+			}
+		}
+
+	case "ConfigMap":
+		var configMap corev1.ConfigMap
+		if err := r.apiReader().Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &configMap); err != nil {
+			status.Message = "ConfigMap not found"
+			observed = false
+		} else {
+			value, found := configMap.Data[condition.Key]
+			if binary, ok := configMap.BinaryData[condition.Key]; !found && ok {
+				value, found = string(binary), true
+			}
+			status.Met, status.Message = dataKeyStatus("ConfigMap", condition, value, found)
+		}
+
+	case "Secret":
+		var secret corev1.Secret
+		if err := r.apiReader().Get(ctx, client.ObjectKey{Name: condition.Name, Namespace: namespace}, &secret); err != nil {
+			status.Message = "Secret not found"
+			observed = false
+		} else {
+			value, found := secret.Data[condition.Key]
+			status.Met, status.Message = dataKeyStatus("Secret", condition, string(value), found)
+		}
+
+	case "Pods":
+		if condition.Selector == nil || condition.Value == nil {
+			status.Message = "Pods condition requires a selector and a value"
+			observed = false
+			break
+		}
+		selector, err := metav1.LabelSelectorAsSelector(condition.Selector)
+		if err != nil {
+			status.Message = fmt.Sprintf("Invalid pod selector: %v", err)
+			observed = false
+			break
+		}
+		var pods corev1.PodList
+		if err := r.apiReader().List(ctx, &pods, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			log.Error(err, "Failed to list Pods for gate condition", "selector", selector.String(), "namespace", namespace)
+			status.Message = "Failed to list Pods"
+			observed = false
+			break
+		}
+		// Terminating pods are on their way out and are not counted,
+		// even while they still report Ready
+		matched, ready := 0, int32(0)
+		for i := range pods.Items {
+			if pods.Items[i].DeletionTimestamp != nil {
+				continue
+			}
+			matched++
+			if podReady(&pods.Items[i]) {
+				ready++
+			}
+		}
+		status.Met = ready >= *condition.Value
+		status.Message = fmt.Sprintf("%d of %d matching pods ready, %d required", ready, matched, *condition.Value)
+
+	default:
+		status.Message = "Unknown condition type"
+		observed = false
+	}
+
+	return status, observed
+}
+
+// nextCheckInterval returns gateMinRequeue doubled once for every check that
+// would have found the conditions unchanged in the time since they last
+// changed, up to MaxRequeue. Checks then fall 1s, 3s, 7s, ... after the change.
+func (r *GateReconciler) nextCheckInterval(sinceChange time.Duration) time.Duration {
+	maxRequeue := r.MaxRequeue
+	if maxRequeue <= 0 {
+		maxRequeue = DefaultGateMaxRequeue
+	}
+
+	interval := gateMinRequeue
+	for interval < maxRequeue && interval*2 <= sinceChange+gateMinRequeue {
+		interval *= 2
+	}
+	if interval > maxRequeue {
+		interval = maxRequeue
+	}
+	return interval
+}
+
+// conditionsChanged reports whether any condition differs from the last
+// recorded evaluation.
+func conditionsChanged(previous, current []syncv1.GateConditionStatus) bool {
+	if len(previous) != len(current) {
+		return true
+	}
+	for i := range current {
+		if !equality.Semantic.DeepEqual(previous[i], current[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionStartedAt carries over when the controller started waiting on the
+// condition at index i, if the last evaluation there was of the same
+// condition, and otherwise starts the clock now.
+func conditionStartedAt(previous []syncv1.GateConditionStatus, i int, current syncv1.GateConditionStatus, now metav1.Time) *metav1.Time {
+	if i < len(previous) && previous[i].Type == current.Type && previous[i].Name == current.Name && previous[i].StartedAt != nil {
+		return previous[i].StartedAt
+	}
+	return &now
+}
+
+// earliest returns the earlier of two deadlines, treating the zero time as
+// no deadline
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// dataKeyStatus evaluates a ConfigMap or Secret condition against the value found
+// under its key. Messages never include the value itself, since it may be secret.
+func dataKeyStatus(kind string, condition syncv1.GateCondition, value string, found bool) (bool, string) {
+	switch {
+	case condition.Key == "":
+		return false, fmt.Sprintf("%s condition requires a key", kind)
+	case !found:
+		return false, fmt.Sprintf("%s key %s not found", kind, condition.Key)
+	case condition.StringValue != "" && value != condition.StringValue:
+		return false, fmt.Sprintf("%s key %s does not have the required value", kind, condition.Key)
+	case condition.StringValue != "":
+		return true, fmt.Sprintf("%s key %s has the required value", kind, condition.Key)
+	default:
+		return true, fmt.Sprintf("%s key %s is present", kind, condition.Key)
+	}
+}
+
+// podReady reports whether pod has the Ready condition, the way a Deployment
+// counts its ready replicas
+// apiReader returns the reader for the objects gate conditions look up
+// without caching them
+func (r *GateReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (r *GateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("gate-controller")
+	}
+	if r.APIReader == nil {
+		r.APIReader = mgr.GetAPIReader()
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.Gate{}).
-		Complete(r)
+		Complete(trackHealth("gate", r))
 }
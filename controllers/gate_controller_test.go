@@ -2,15 +2,18 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -25,13 +28,15 @@ func TestGateReconciler_Reconcile(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, syncv1.AddToScheme(scheme))
 	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
 
 	tests := []struct {
-		name          string
-		gate          *syncv1.Gate
-		objects       []runtime.Object
-		expectedPhase syncv1.GatePhase
-		expectedMet   int
+		name            string
+		gate            *syncv1.Gate
+		objects         []runtime.Object
+		expectedPhase   syncv1.GatePhase
+		expectedMet     int
+		expectedMessage string
 	}{
 		{
 			name: "gate with no conditions should open",
@@ -296,6 +301,491 @@ func TestGateReconciler_Reconcile(t *testing.T) {
 			expectedPhase: syncv1.GatePhaseOpen,
 			expectedMet:   1,
 		},
+		{
+			name: "gate waiting for mutex to unlock",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "Mutex",
+							Name:  "test-mutex",
+							State: "Unlocked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.Mutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-mutex",
+						Namespace: "default",
+					},
+					Status: syncv1.MutexStatus{
+						Phase:  syncv1.MutexPhaseLocked,
+						Holder: "worker-1",
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "Mutex is Locked, waiting for Unlocked",
+		},
+		{
+			name: "gate should open when mutex is unlocked",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "Mutex",
+							Name:  "test-mutex",
+							State: "Unlocked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.Mutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-mutex",
+						Namespace: "default",
+					},
+					Status: syncv1.MutexStatus{
+						Phase: syncv1.MutexPhaseUnlocked,
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "Mutex is Unlocked",
+		},
+		{
+			name: "gate should open when mutex without status is unlocked",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "Mutex",
+							Name:  "test-mutex",
+							State: "Unlocked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.Mutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-mutex",
+						Namespace: "default",
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "Mutex is Unlocked",
+		},
+		{
+			name: "gate should open when mutex is locked",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "Mutex",
+							Name:  "test-mutex",
+							State: "Locked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.Mutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-mutex",
+						Namespace: "default",
+					},
+					Status: syncv1.MutexStatus{
+						Phase:  syncv1.MutexPhaseLocked,
+						Holder: "worker-1",
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "Mutex is Locked",
+		},
+		{
+			name: "gate waiting for rwmutex to unlock",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "RWMutex",
+							Name:  "test-rwmutex",
+							State: "Unlocked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.RWMutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-rwmutex",
+						Namespace: "default",
+					},
+					Status: syncv1.RWMutexStatus{
+						Phase:       syncv1.RWMutexPhaseReadLocked,
+						ReadHolders: []string{"reader-1"},
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "RWMutex is ReadLocked, waiting for Unlocked",
+		},
+		{
+			name: "gate should open when rwmutex is unlocked",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "RWMutex",
+							Name:  "test-rwmutex",
+							State: "Unlocked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.RWMutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-rwmutex",
+						Namespace: "default",
+					},
+					Status: syncv1.RWMutexStatus{
+						Phase: syncv1.RWMutexPhaseUnlocked,
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "RWMutex is Unlocked",
+		},
+		{
+			name: "gate waiting for rwmutex write lock",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "RWMutex",
+							Name:  "test-rwmutex",
+							State: "WriteLocked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.RWMutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-rwmutex",
+						Namespace: "default",
+					},
+					Status: syncv1.RWMutexStatus{
+						Phase:       syncv1.RWMutexPhaseReadLocked,
+						ReadHolders: []string{"reader-1"},
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "RWMutex is ReadLocked, waiting for WriteLocked",
+		},
+		{
+			name: "gate should open when rwmutex is write locked",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "RWMutex",
+							Name:  "test-rwmutex",
+							State: "WriteLocked",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&syncv1.RWMutex{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-rwmutex",
+						Namespace: "default",
+					},
+					Status: syncv1.RWMutexStatus{
+						Phase:       syncv1.RWMutexPhaseWriteLocked,
+						WriteHolder: "writer-1",
+					},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "RWMutex is WriteLocked",
+		},
+		{
+			name: "gate waiting for missing mutex",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:  "Mutex",
+							Name:  "missing-mutex",
+							State: "Unlocked",
+						},
+					},
+				},
+			},
+			objects:         []runtime.Object{},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "Mutex not found",
+		},
+		{
+			name: "gate waiting for configmap key",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type: "ConfigMap",
+							Name: "pipeline-output",
+							Key:  "checksum",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pipeline-output",
+						Namespace: "default",
+					},
+					Data: map[string]string{"other": "x"},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "ConfigMap key checksum not found",
+		},
+		{
+			name: "gate should open when configmap key is present",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type: "ConfigMap",
+							Name: "pipeline-output",
+							Key:  "checksum",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pipeline-output",
+						Namespace: "default",
+					},
+					Data: map[string]string{"checksum": "abc123"},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "ConfigMap key checksum is present",
+		},
+		{
+			name: "gate waiting for configmap key value",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:        "ConfigMap",
+							Name:        "pipeline-output",
+							Key:         "status",
+							StringValue: "ready",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pipeline-output",
+						Namespace: "default",
+					},
+					Data: map[string]string{"status": "running"},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "ConfigMap key status does not have the required value",
+		},
+		{
+			name: "gate should open when configmap key has value",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:        "ConfigMap",
+							Name:        "pipeline-output",
+							Key:         "status",
+							StringValue: "ready",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pipeline-output",
+						Namespace: "default",
+					},
+					Data: map[string]string{"status": "ready"},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "ConfigMap key status has the required value",
+		},
+		{
+			name: "gate waiting for missing configmap",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type: "ConfigMap",
+							Name: "pipeline-output",
+							Key:  "checksum",
+						},
+					},
+				},
+			},
+			objects:         []runtime.Object{},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "ConfigMap not found",
+		},
+		{
+			name: "gate waiting for secret key",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type: "Secret",
+							Name: "pipeline-token",
+							Key:  "token",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pipeline-token",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{"other": []byte("x")},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMet:     0,
+			expectedMessage: "Secret key token not found",
+		},
+		{
+			name: "gate should open when secret key has value",
+			gate: &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gate",
+					Namespace: "default",
+				},
+				Spec: syncv1.GateSpec{
+					Conditions: []syncv1.GateCondition{
+						{
+							Type:        "Secret",
+							Name:        "pipeline-token",
+							Key:         "token",
+							StringValue: "s3cr3t",
+						},
+					},
+				},
+			},
+			objects: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pipeline-token",
+						Namespace: "default",
+					},
+					Data: map[string][]byte{"token": []byte("s3cr3t")},
+				},
+			},
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMet:     1,
+			expectedMessage: "Secret key token has the required value",
+		},
 	}
 
 	for _, tt := range tests {
@@ -309,9 +799,11 @@ func TestGateReconciler_Reconcile(t *testing.T) {
 				WithStatusSubresource(&syncv1.Gate{}).
 				Build()
 
+			recorder := record.NewFakeRecorder(10)
 			reconciler := &GateReconciler{
-				Client: client,
-				Scheme: scheme,
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: recorder,
 			}
 
 			req := ctrl.Request{
@@ -319,35 +811,216 @@ func TestGateReconciler_Reconcile(t *testing.T) {
 					Name:      tt.gate.Name,
 					Namespace: tt.gate.Namespace,
 				},
-			}
-
-			result, err := reconciler.Reconcile(context.Background(), req)
-			require.NoError(t, err)
-
-			var updated syncv1.Gate
-			err = client.Get(context.Background(), req.NamespacedName, &updated)
-			require.NoError(t, err)
+			}
+
+			result, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated syncv1.Gate
+			err = client.Get(context.Background(), req.NamespacedName, &updated)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			assert.Len(t, updated.Status.ConditionStatuses, len(tt.gate.Spec.Conditions))
+
+			metCount := 0
+			for _, status := range updated.Status.ConditionStatuses {
+				if status.Met {
+					metCount++
+				}
+			}
+			assert.Equal(t, tt.expectedMet, metCount)
+
+			if tt.expectedMessage != "" {
+				assert.Equal(t, tt.expectedMessage, updated.Status.ConditionStatuses[0].Message)
+			}
+
+			if tt.expectedPhase == syncv1.GatePhaseOpen {
+				assert.NotNil(t, updated.Status.OpenedAt)
+				assertEvents(t, recorder, fmt.Sprintf("Normal GateOpened All %d conditions met", len(tt.gate.Spec.Conditions)))
+			} else {
+				assertEvents(t, recorder)
+			}
+
+			if tt.expectedPhase == syncv1.GatePhaseWaiting {
+				// First evaluation of a waiting gate starts the backoff
+				assert.Equal(t, time.Second, result.RequeueAfter)
+				require.NotNil(t, updated.Status.NextCheckInterval)
+				assert.Equal(t, time.Second, updated.Status.NextCheckInterval.Duration)
+			} else {
+				assert.Nil(t, updated.Status.NextCheckInterval)
+			}
+		})
+	}
+}
+
+func TestGateReconciler_RequeueBackoff(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gate",
+			Namespace: "default",
+		},
+		Spec: syncv1.GateSpec{
+			Conditions: []syncv1.GateCondition{
+				{
+					Type:  "Barrier",
+					Name:  "test-barrier",
+					State: "Open",
+				},
+			},
+		},
+	}
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "default",
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(gate).
+		WithStatusSubresource(&syncv1.Gate{}).
+		Build()
+
+	reconciler := &GateReconciler{
+		Client:     client,
+		Scheme:     scheme,
+		MaxRequeue: 10 * time.Second,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      gate.Name,
+			Namespace: gate.Namespace,
+		},
+	}
+
+	reconcile := func() time.Duration {
+		result, err := reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		var updated syncv1.Gate
+		require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+		require.NotNil(t, updated.Status.NextCheckInterval)
+		assert.Equal(t, result.RequeueAfter, updated.Status.NextCheckInterval.Duration)
+		return result.RequeueAfter
+	}
+	// changedAgo moves the last condition change back, as if the gate had
+	// been waiting that long
+	changedAgo := func(ago time.Duration) {
+		var updated syncv1.Gate
+		require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+		updated.Status.LastConditionChangeAt = &metav1.Time{Time: time.Now().Add(-ago)}
+		require.NoError(t, client.Status().Update(context.Background(), &updated))
+	}
+
+	assert.Equal(t, time.Second, reconcile())
+
+	// Interval doubles while nothing changes, capped at MaxRequeue
+	for _, tt := range []struct {
+		ago      time.Duration
+		expected time.Duration
+	}{
+		{time.Second, 2 * time.Second},
+		{3 * time.Second, 4 * time.Second},
+		{7 * time.Second, 8 * time.Second},
+		{15 * time.Second, 10 * time.Second},
+		{time.Hour, 10 * time.Second},
+	} {
+		changedAgo(tt.ago)
+		assert.Equal(t, tt.expected, reconcile())
+	}
+
+	// The barrier appearing changes the condition, so the backoff starts over
+	require.NoError(t, client.Create(context.Background(), barrier))
+	assert.Equal(t, time.Second, reconcile())
+	changedAgo(time.Second)
+	assert.Equal(t, 2*time.Second, reconcile())
+}
+
+func TestGateReconciler_StatusWriteDoesNotAdvanceBackoff(t *testing.T) {
+	gate := waitingGate(syncv1.GateLogicAnd,
+		syncv1.GateCondition{Type: "Job", Name: "migrate", State: "Complete"},
+	)
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(gate).
+		WithStatusSubresource(&syncv1.Gate{}).
+		Build()
+	reconciler := &GateReconciler{Client: client, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: gate.Name, Namespace: gate.Namespace}}
+
+	// Each status write triggers another reconcile straight away, which must
+	// not count as a check that found nothing changed
+	for range 5 {
+		result, err := reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, result.RequeueAfter)
+	}
+}
+
+func TestGateReconciler_RequeueBoundedByTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	waitingSince := metav1.NewTime(time.Now().Add(-time.Hour))
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-gate",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: syncv1.GateSpec{
+			Timeout: &metav1.Duration{Duration: time.Hour + 5*time.Second},
+			Conditions: []syncv1.GateCondition{
+				{
+					Type:  "Job",
+					Name:  "nonexistent-job",
+					State: "Complete",
+				},
+			},
+		},
+		Status: syncv1.GateStatus{
+			Phase: syncv1.GatePhaseWaiting,
+			ConditionStatuses: []syncv1.GateConditionStatus{
+				{Type: "Job", Name: "nonexistent-job", Message: "Job not found", StartedAt: &waitingSince},
+			},
+			LastConditionChangeAt: &waitingSince,
+		},
+	}
 
-			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
-			assert.Len(t, updated.Status.ConditionStatuses, len(tt.gate.Spec.Conditions))
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(gate).
+		WithStatusSubresource(&syncv1.Gate{}).
+		Build()
 
-			metCount := 0
-			for _, status := range updated.Status.ConditionStatuses {
-				if status.Met {
-					metCount++
-				}
-			}
-			assert.Equal(t, tt.expectedMet, metCount)
+	reconciler := &GateReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
 
-			if tt.expectedPhase == syncv1.GatePhaseOpen {
-				assert.NotNil(t, updated.Status.OpenedAt)
-			}
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      gate.Name,
+			Namespace: gate.Namespace,
+		},
+	})
+	require.NoError(t, err)
 
-			if tt.expectedPhase == syncv1.GatePhaseWaiting {
-				assert.Equal(t, 10*time.Second, result.RequeueAfter)
-			}
-		})
-	}
+	// The gate has waited long enough for the interval to reach the cap, but
+	// it times out sooner
+	assert.LessOrEqual(t, result.RequeueAfter, 5*time.Second)
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
 }
 
 func TestGateReconciler_Timeout(t *testing.T) {
@@ -378,9 +1051,11 @@ func TestGateReconciler_Timeout(t *testing.T) {
 		WithStatusSubresource(&syncv1.Gate{}).
 		Build()
 
+	recorder := record.NewFakeRecorder(10)
 	reconciler := &GateReconciler{
-		Client: client,
-		Scheme: scheme,
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
 	}
 
 	req := ctrl.Request{
@@ -398,4 +1073,538 @@ func TestGateReconciler_Timeout(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, syncv1.GatePhaseFailed, updated.Status.Phase)
+	assertEvents(t, recorder, "Warning GateFailed Gate timed out after 1h0m0s with conditions unmet")
+}
+
+// testMutex returns a mutex in the default namespace in the given phase
+func testMutex(name string, phase syncv1.MutexPhase) *syncv1.Mutex {
+	return &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     syncv1.MutexStatus{Phase: phase},
+	}
+}
+
+func TestGateReconciler_LogicAndNegate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+
+	unlocked := syncv1.GateCondition{Type: "Mutex", Name: "mutex-a", State: "Unlocked"}
+	notLocked := syncv1.GateCondition{Type: "Mutex", Name: "mutex-a", State: "Locked", Negate: true}
+	jobComplete := syncv1.GateCondition{Type: "Job", Name: "test-job", State: "Complete"}
+
+	tests := []struct {
+		name           string
+		logic          syncv1.GateLogic
+		conditions     []syncv1.GateCondition
+		objects        []runtime.Object
+		expectedPhase  syncv1.GatePhase
+		expectedMet    []bool
+		expectedEvents []string
+	}{
+		{
+			name:  "or opens when any condition is met",
+			logic: syncv1.GateLogicOr,
+			conditions: []syncv1.GateCondition{
+				unlocked,
+				{Type: "Mutex", Name: "mutex-b", State: "Unlocked"},
+			},
+			objects: []runtime.Object{
+				testMutex("mutex-a", syncv1.MutexPhaseUnlocked),
+				testMutex("mutex-b", syncv1.MutexPhaseLocked),
+			},
+			expectedPhase:  syncv1.GatePhaseOpen,
+			expectedMet:    []bool{true, false},
+			expectedEvents: []string{"Normal GateOpened 1 of 2 conditions met"},
+		},
+		{
+			name:  "or waits while no condition is met",
+			logic: syncv1.GateLogicOr,
+			conditions: []syncv1.GateCondition{
+				unlocked,
+				jobComplete,
+			},
+			objects:       []runtime.Object{testMutex("mutex-a", syncv1.MutexPhaseLocked)},
+			expectedPhase: syncv1.GatePhaseWaiting,
+			expectedMet:   []bool{false, false},
+		},
+		{
+			name:  "and waits while any condition is unmet",
+			logic: syncv1.GateLogicAnd,
+			conditions: []syncv1.GateCondition{
+				unlocked,
+				{Type: "Mutex", Name: "mutex-b", State: "Unlocked"},
+			},
+			objects: []runtime.Object{
+				testMutex("mutex-a", syncv1.MutexPhaseUnlocked),
+				testMutex("mutex-b", syncv1.MutexPhaseLocked),
+			},
+			expectedPhase: syncv1.GatePhaseWaiting,
+			expectedMet:   []bool{true, false},
+		},
+		{
+			name:           "negated condition is met when the state differs",
+			conditions:     []syncv1.GateCondition{notLocked},
+			objects:        []runtime.Object{testMutex("mutex-a", syncv1.MutexPhaseUnlocked)},
+			expectedPhase:  syncv1.GatePhaseOpen,
+			expectedMet:    []bool{true},
+			expectedEvents: []string{"Normal GateOpened All 1 conditions met"},
+		},
+		{
+			name:          "negated condition is unmet when the state matches",
+			conditions:    []syncv1.GateCondition{notLocked},
+			objects:       []runtime.Object{testMutex("mutex-a", syncv1.MutexPhaseLocked)},
+			expectedPhase: syncv1.GatePhaseWaiting,
+			expectedMet:   []bool{false},
+		},
+		{
+			name:          "negated condition on a missing resource is unmet",
+			conditions:    []syncv1.GateCondition{notLocked},
+			expectedPhase: syncv1.GatePhaseWaiting,
+			expectedMet:   []bool{false},
+		},
+		{
+			name:       "and combines plain and negated conditions",
+			conditions: []syncv1.GateCondition{notLocked, jobComplete},
+			objects: []runtime.Object{
+				testMutex("mutex-a", syncv1.MutexPhaseUnlocked),
+				&batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "default"},
+					Status:     batchv1.JobStatus{Succeeded: 1},
+				},
+			},
+			expectedPhase:  syncv1.GatePhaseOpen,
+			expectedMet:    []bool{true, true},
+			expectedEvents: []string{"Normal GateOpened All 2 conditions met"},
+		},
+		{
+			name:       "or opens on a negated condition alone",
+			logic:      syncv1.GateLogicOr,
+			conditions: []syncv1.GateCondition{jobComplete, notLocked},
+			objects: []runtime.Object{
+				testMutex("mutex-a", syncv1.MutexPhaseUnlocked),
+				&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "default"}},
+			},
+			expectedPhase:  syncv1.GatePhaseOpen,
+			expectedMet:    []bool{false, true},
+			expectedEvents: []string{"Normal GateOpened 1 of 2 conditions met"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+				Spec:       syncv1.GateSpec{Logic: tt.logic, Conditions: tt.conditions},
+			}
+
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(append([]runtime.Object{gate}, tt.objects...)...).
+				WithStatusSubresource(&syncv1.Gate{}).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &GateReconciler{
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: recorder,
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: gate.Name, Namespace: gate.Namespace}}
+			_, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated syncv1.Gate
+			require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			met := make([]bool, len(updated.Status.ConditionStatuses))
+			for i, status := range updated.Status.ConditionStatuses {
+				met[i] = status.Met
+			}
+			assert.Equal(t, tt.expectedMet, met)
+			assertEvents(t, recorder, tt.expectedEvents...)
+		})
+	}
+}
+
+func TestGateReconciler_NegatedConditionMessage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+		Spec: syncv1.GateSpec{
+			Conditions: []syncv1.GateCondition{
+				{Type: "Mutex", Name: "mutex-a", State: "Locked", Negate: true},
+				{Type: "Mutex", Name: "missing", State: "Locked", Negate: true},
+			},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(gate, testMutex("mutex-a", syncv1.MutexPhaseUnlocked)).
+		WithStatusSubresource(&syncv1.Gate{}).
+		Build()
+
+	reconciler := &GateReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: gate.Name, Namespace: gate.Namespace}}
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Gate
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	require.Len(t, updated.Status.ConditionStatuses, 2)
+	assert.Equal(t, "Mutex is Unlocked, waiting for Locked (negated)", updated.Status.ConditionStatuses[0].Message)
+	assert.Equal(t, "Mutex not found", updated.Status.ConditionStatuses[1].Message)
+}
+
+// reconcileGate runs one reconcile of gate against objects and returns the
+// result, the stored gate and the events recorded
+func reconcileGate(t *testing.T, gate *syncv1.Gate, objects ...runtime.Object) (ctrl.Result, *syncv1.Gate, *record.FakeRecorder) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(append([]runtime.Object{gate}, objects...)...).
+		WithStatusSubresource(&syncv1.Gate{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &GateReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: gate.Name, Namespace: gate.Namespace}}
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Gate
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	return result, &updated, recorder
+}
+
+// waitingGate returns a gate whose conditions the controller started waiting
+// on an hour ago
+func waitingGate(logic syncv1.GateLogic, conditions ...syncv1.GateCondition) *syncv1.Gate {
+	startedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-gate",
+			Namespace:         "default",
+			CreationTimestamp: startedAt,
+		},
+		Spec:   syncv1.GateSpec{Logic: logic, Conditions: conditions},
+		Status: syncv1.GateStatus{Phase: syncv1.GatePhaseWaiting},
+	}
+	for _, condition := range conditions {
+		gate.Status.ConditionStatuses = append(gate.Status.ConditionStatuses, syncv1.GateConditionStatus{
+			Type:      condition.Type,
+			Name:      condition.Name,
+			StartedAt: &startedAt,
+		})
+	}
+	return gate
+}
+
+func TestGateReconciler_ConditionTimeout(t *testing.T) {
+	gate := waitingGate(syncv1.GateLogicAnd,
+		syncv1.GateCondition{Type: "Job", Name: "migrate", State: "Complete", Timeout: &metav1.Duration{Duration: 30 * time.Minute}},
+		syncv1.GateCondition{Type: "Mutex", Name: "mutex-a", State: "Unlocked", Timeout: &metav1.Duration{Duration: 2 * time.Hour}},
+		syncv1.GateCondition{Type: "Mutex", Name: "mutex-b", State: "Unlocked"},
+	)
+
+	result, updated, recorder := reconcileGate(t, gate,
+		testMutex("mutex-a", syncv1.MutexPhaseLocked),
+		testMutex("mutex-b", syncv1.MutexPhaseLocked),
+	)
+
+	assert.Equal(t, syncv1.GatePhaseFailed, updated.Status.Phase)
+	assert.Zero(t, result.RequeueAfter)
+	require.Len(t, updated.Status.ConditionStatuses, 3)
+	assert.Equal(t, "Timed out after 30m0s: Job not found", updated.Status.ConditionStatuses[0].Message)
+	assert.Equal(t, "Mutex is Locked, waiting for Unlocked", updated.Status.ConditionStatuses[1].Message)
+	assert.Equal(t, "Mutex is Locked, waiting for Unlocked", updated.Status.ConditionStatuses[2].Message)
+	assertEvents(t, recorder, "Warning GateFailed Condition timed out: Job migrate after 30m0s")
+}
+
+func TestGateReconciler_ConditionTimeoutPending(t *testing.T) {
+	gate := waitingGate(syncv1.GateLogicAnd,
+		syncv1.GateCondition{Type: "Job", Name: "migrate", State: "Complete", Timeout: &metav1.Duration{Duration: time.Hour + 5*time.Second}},
+	)
+	gate.Status.ConditionStatuses[0].Message = "Job not found"
+	gate.Status.LastConditionChangeAt = gate.Status.ConditionStatuses[0].StartedAt
+
+	result, updated, recorder := reconcileGate(t, gate)
+
+	assert.Equal(t, syncv1.GatePhaseWaiting, updated.Status.Phase)
+	assert.Equal(t, "Job not found", updated.Status.ConditionStatuses[0].Message)
+	assertEvents(t, recorder)
+
+	// The condition times out before the next check would otherwise be due
+	assert.LessOrEqual(t, result.RequeueAfter, 5*time.Second)
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+}
+
+func TestGateReconciler_ConditionTimeoutMetConditionDoesNotFail(t *testing.T) {
+	gate := waitingGate(syncv1.GateLogicAnd,
+		syncv1.GateCondition{Type: "Mutex", Name: "mutex-a", State: "Unlocked", Timeout: &metav1.Duration{Duration: time.Minute}},
+		syncv1.GateCondition{Type: "Mutex", Name: "mutex-b", State: "Unlocked"},
+	)
+
+	_, updated, _ := reconcileGate(t, gate,
+		testMutex("mutex-a", syncv1.MutexPhaseUnlocked),
+		testMutex("mutex-b", syncv1.MutexPhaseLocked),
+	)
+
+	assert.Equal(t, syncv1.GatePhaseWaiting, updated.Status.Phase)
+	assert.Equal(t, "Mutex is Unlocked", updated.Status.ConditionStatuses[0].Message)
+}
+
+func TestGateReconciler_ConditionTimeoutOr(t *testing.T) {
+	timeout := &metav1.Duration{Duration: 30 * time.Minute}
+
+	// One timed-out condition leaves an Or gate waiting on the other
+	gate := waitingGate(syncv1.GateLogicOr,
+		syncv1.GateCondition{Type: "Job", Name: "sync-eu", State: "Complete", Timeout: timeout},
+		syncv1.GateCondition{Type: "Job", Name: "sync-us", State: "Complete"},
+	)
+	_, updated, recorder := reconcileGate(t, gate)
+	assert.Equal(t, syncv1.GatePhaseWaiting, updated.Status.Phase)
+	assert.Equal(t, "Timed out after 30m0s: Job not found", updated.Status.ConditionStatuses[0].Message)
+	assertEvents(t, recorder)
+
+	// Once every condition has timed out it fails
+	gate = waitingGate(syncv1.GateLogicOr,
+		syncv1.GateCondition{Type: "Job", Name: "sync-eu", State: "Complete", Timeout: timeout},
+		syncv1.GateCondition{Type: "Job", Name: "sync-us", State: "Complete", Timeout: timeout},
+	)
+	_, updated, recorder = reconcileGate(t, gate)
+	assert.Equal(t, syncv1.GatePhaseFailed, updated.Status.Phase)
+	assertEvents(t, recorder, "Warning GateFailed Condition timed out: Job sync-eu after 30m0s, Job sync-us after 30m0s")
+}
+
+func TestGateReconciler_ConditionStartedAt(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+		Spec: syncv1.GateSpec{
+			Conditions: []syncv1.GateCondition{
+				{Type: "Job", Name: "migrate", State: "Complete", Timeout: &metav1.Duration{Duration: time.Minute}},
+			},
+		},
+	}
+
+	// The first evaluation starts the clock
+	before := time.Now().Add(-time.Second)
+	_, updated, _ := reconcileGate(t, gate)
+	require.NotNil(t, updated.Status.ConditionStatuses[0].StartedAt)
+	assert.True(t, updated.Status.ConditionStatuses[0].StartedAt.After(before))
+	assert.Equal(t, syncv1.GatePhaseWaiting, updated.Status.Phase)
+
+	// Later evaluations keep it
+	startedAt := metav1.NewTime(time.Now().Add(-30 * time.Second).Truncate(time.Second))
+	updated.Status.ConditionStatuses[0].StartedAt = &startedAt
+	_, again, _ := reconcileGate(t, updated)
+	assert.True(t, startedAt.Equal(again.Status.ConditionStatuses[0].StartedAt))
+
+	// A different condition at the same position starts afresh
+	again.Spec.Conditions[0].Name = "seed"
+	_, replaced, _ := reconcileGate(t, again)
+	assert.True(t, replaced.Status.ConditionStatuses[0].StartedAt.After(before))
+	assert.False(t, startedAt.Equal(replaced.Status.ConditionStatuses[0].StartedAt))
+}
+
+// testPod returns a pod in namespace with labels, Ready if ready is set and
+// without a Ready condition otherwise
+func testPod(name, namespace string, labels map[string]string, ready *corev1.ConditionStatus) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+	if ready != nil {
+		pod.Status.Conditions = []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+			{Type: corev1.PodReady, Status: *ready},
+		}
+	}
+	return pod
+}
+
+func TestGateReconciler_PodsCondition(t *testing.T) {
+	ready, notReady := corev1.ConditionTrue, corev1.ConditionFalse
+	api := map[string]string{"app": "api"}
+	pods := []runtime.Object{
+		testPod("api-1", "default", api, &ready),
+		testPod("api-2", "default", api, &ready),
+		testPod("api-3", "default", api, &notReady),
+		testPod("api-4", "default", api, nil),
+		testPod("worker-1", "default", map[string]string{"app": "worker"}, &ready),
+		testPod("api-1", "staging", api, &ready),
+	}
+	gate := func(value int32, selector *metav1.LabelSelector, negate bool) *syncv1.Gate {
+		return &syncv1.Gate{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+			Spec: syncv1.GateSpec{Conditions: []syncv1.GateCondition{
+				{Type: "Pods", Name: "api", Selector: selector, Value: &value, Negate: negate},
+			}},
+		}
+	}
+	apiSelector := &metav1.LabelSelector{MatchLabels: api}
+
+	tests := []struct {
+		name            string
+		gate            *syncv1.Gate
+		expectedPhase   syncv1.GatePhase
+		expectedMessage string
+	}{
+		{
+			name:            "enough ready pods",
+			gate:            gate(2, apiSelector, false),
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMessage: "2 of 4 matching pods ready, 2 required",
+		},
+		{
+			name:            "too few ready pods",
+			gate:            gate(3, apiSelector, false),
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMessage: "2 of 4 matching pods ready, 3 required",
+		},
+		{
+			name: "selector with expressions",
+			gate: gate(3, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"api", "worker"}},
+			}}, false),
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMessage: "3 of 5 matching pods ready, 3 required",
+		},
+		{
+			name:            "no matching pods",
+			gate:            gate(1, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}, false),
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMessage: "0 of 0 matching pods ready, 1 required",
+		},
+		{
+			name:            "negated while too few are ready",
+			gate:            gate(3, apiSelector, true),
+			expectedPhase:   syncv1.GatePhaseOpen,
+			expectedMessage: "2 of 4 matching pods ready, 3 required (negated)",
+		},
+		{
+			name:            "without a selector",
+			gate:            gate(1, nil, true),
+			expectedPhase:   syncv1.GatePhaseWaiting,
+			expectedMessage: "Pods condition requires a selector and a value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, updated, _ := reconcileGate(t, tt.gate, pods...)
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			require.Len(t, updated.Status.ConditionStatuses, 1)
+			status := updated.Status.ConditionStatuses[0]
+			assert.Equal(t, "Pods", status.Type)
+			assert.Equal(t, "api", status.Name)
+			assert.Equal(t, tt.expectedPhase == syncv1.GatePhaseOpen, status.Met)
+			assert.Equal(t, tt.expectedMessage, status.Message)
+		})
+	}
+}
+
+func TestGateReconciler_PodsConditionSkipsTerminatingPods(t *testing.T) {
+	ready := corev1.ConditionTrue
+	api := map[string]string{"app": "api"}
+	terminating := testPod("api-3", "default", api, &ready)
+	deletedAt := metav1.Now()
+	terminating.DeletionTimestamp = &deletedAt
+	terminating.Finalizers = []string{"example.com/drain"}
+
+	value := int32(3)
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+		Spec: syncv1.GateSpec{Conditions: []syncv1.GateCondition{
+			{Type: "Pods", Name: "api", Selector: &metav1.LabelSelector{MatchLabels: api}, Value: &value},
+		}},
+	}
+
+	_, updated, _ := reconcileGate(t, gate,
+		testPod("api-1", "default", api, &ready),
+		testPod("api-2", "default", api, &ready),
+		terminating)
+	assert.Equal(t, syncv1.GatePhaseWaiting, updated.Status.Phase)
+	require.Len(t, updated.Status.ConditionStatuses, 1)
+	assert.Equal(t, "2 of 2 matching pods ready, 3 required", updated.Status.ConditionStatuses[0].Message)
+}
+
+func TestGateReconciler_ConditionsReadThroughAPIReader(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	ready := corev1.ConditionTrue
+	api := map[string]string{"app": "api"}
+	value := int32(1)
+
+	tests := []struct {
+		name            string
+		condition       syncv1.GateCondition
+		object          runtime.Object
+		expectedMessage string
+	}{
+		{
+			name:            "pods",
+			condition:       syncv1.GateCondition{Type: "Pods", Name: "api", Selector: &metav1.LabelSelector{MatchLabels: api}, Value: &value},
+			object:          testPod("api-1", "default", api, &ready),
+			expectedMessage: "1 of 1 matching pods ready, 1 required",
+		},
+		{
+			name:      "configmap",
+			condition: syncv1.GateCondition{Type: "ConfigMap", Name: "pipeline-output", Key: "checksum"},
+			object: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pipeline-output", Namespace: "default"},
+				Data:       map[string]string{"checksum": "abc123"},
+			},
+			expectedMessage: "ConfigMap key checksum is present",
+		},
+		{
+			name:      "secret",
+			condition: syncv1.GateCondition{Type: "Secret", Name: "db-credentials", Key: "password"},
+			object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"password": []byte("s3cret")},
+			},
+			expectedMessage: "Secret key password is present",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := &syncv1.Gate{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "default"},
+				Spec:       syncv1.GateSpec{Conditions: []syncv1.GateCondition{tt.condition}},
+			}
+
+			// The object is only visible to the API reader, as it would be
+			// to a manager that does not cache it
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(gate).
+				WithStatusSubresource(&syncv1.Gate{}).
+				Build()
+			apiReader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(tt.object).
+				Build()
+
+			reconciler := &GateReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10), APIReader: apiReader}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: gate.Name, Namespace: gate.Namespace}}
+			_, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated syncv1.Gate
+			require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+			assert.Equal(t, syncv1.GatePhaseOpen, updated.Status.Phase)
+			assert.Equal(t, tt.expectedMessage, updated.Status.ConditionStatuses[0].Message)
+		})
+	}
 }
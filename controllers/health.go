@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultReconcileStaleAfter is how long a controller may go without a
+	// successful reconcile, while it has work, before readiness fails
+	DefaultReconcileStaleAfter = 5 * time.Minute
+
+	// cacheSyncTimeout bounds how long a readiness check waits on the caches
+	cacheSyncTimeout = time.Second
+)
+
+// reconcileHealth records how every controller's reconciles are going, for
+// ReadinessCheck
+var reconcileHealth = newHealthTracker()
+
+// controllerHealth is one controller's reconcile record, in Unix nanoseconds
+type controllerHealth struct {
+	// lastSuccess is when a reconcile last succeeded, or 0 if none has yet
+	lastSuccess atomic.Int64
+	// pendingSince is when the oldest reconcile since lastSuccess started, or
+	// 0 if every reconcile since then has succeeded
+	pendingSince atomic.Int64
+}
+
+// healthTracker holds the reconcile record of each controller by name
+type healthTracker struct {
+	mu          sync.Mutex
+	controllers map[string]*controllerHealth
+	now         func() time.Time
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{controllers: map[string]*controllerHealth{}, now: time.Now}
+}
+
+// controller returns the record for name, creating it on first use
+func (t *healthTracker) controller(name string) *controllerHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h, ok := t.controllers[name]
+	if !ok {
+		h = &controllerHealth{}
+		t.controllers[name] = h
+	}
+	return h
+}
+
+// track wraps r so every reconcile it runs is recorded under name
+func (t *healthTracker) track(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &trackedReconciler{Reconciler: r, health: t.controller(name), now: t.now}
+}
+
+// check fails if any controller has been reconciling for longer than
+// staleAfter without success, because its reconciles keep failing or one is
+// stuck. A controller with nothing to reconcile is not stale.
+func (t *healthTracker) check(staleAfter time.Duration) error {
+	t.mu.Lock()
+	names := make([]string, 0, len(t.controllers))
+	for name := range t.controllers {
+		names = append(names, name)
+	}
+	t.mu.Unlock()
+	sort.Strings(names)
+
+	now := t.now()
+	var stale []string
+	for _, name := range names {
+		h := t.controller(name)
+		since := h.pendingSince.Load()
+		if since == 0 || now.Sub(time.Unix(0, since)) <= staleAfter {
+			continue
+		}
+
+		last := "never succeeded"
+		if success := h.lastSuccess.Load(); success != 0 {
+			last = fmt.Sprintf("last succeeded %s ago", now.Sub(time.Unix(0, success)).Round(time.Second))
+		}
+		stale = append(stale, fmt.Sprintf("%s (reconciling without success for %s, %s)",
+			name, now.Sub(time.Unix(0, since)).Round(time.Second), last))
+	}
+
+	if len(stale) > 0 {
+		return fmt.Errorf("controllers not reconciling: %s", strings.Join(stale, "; "))
+	}
+	return nil
+}
+
+// trackedReconciler records the outcome of each reconcile of the wrapped
+// Reconciler
+type trackedReconciler struct {
+	reconcile.Reconciler
+	health *controllerHealth
+	now    func() time.Time
+}
+
+func (r *trackedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.health.pendingSince.CompareAndSwap(0, r.now().UnixNano())
+
+	result, err := r.Reconciler.Reconcile(ctx, req)
+	if err == nil {
+		r.health.lastSuccess.Store(r.now().UnixNano())
+		r.health.pendingSince.Store(0)
+	}
+	return result, err
+}
+
+// trackHealth wraps the named controller's reconciler so ReadinessCheck can
+// see how it is doing
+func trackHealth(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcileHealth.track(name, r)
+}
+
+// ReadinessCheck reports the manager ready once its informer caches have
+// synced, and not ready while any controller has gone without a successful
+// reconcile for longer than staleAfter.
+func ReadinessCheck(c cache.Cache, staleAfter time.Duration) healthz.Checker {
+	return readinessCheck(c, reconcileHealth, staleAfter)
+}
+
+func readinessCheck(c cache.Cache, tracker *healthTracker, staleAfter time.Duration) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("informer caches are not synced")
+		}
+		return tracker.check(staleAfter)
+	}
+}
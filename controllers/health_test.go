@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// testHealthTracker returns a tracker whose clock only moves when the test
+// moves it
+func testHealthTracker() (*healthTracker, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newHealthTracker()
+	tracker.now = func() time.Time { return now }
+	return tracker, &now
+}
+
+// outcomeReconciler returns a reconciler that fails while *fail is set
+func outcomeReconciler(fail *bool) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		if *fail {
+			return ctrl.Result{}, errors.New("conflict")
+		}
+		return ctrl.Result{}, nil
+	})
+}
+
+func TestHealthTracker_StaleController(t *testing.T) {
+	tracker, now := testHealthTracker()
+	fail := true
+	semaphore := tracker.track("semaphore", outcomeReconciler(&fail))
+	tracker.track("gate", outcomeReconciler(new(bool)))
+
+	_, err := semaphore.Reconcile(context.Background(), ctrl.Request{})
+	require.Error(t, err)
+	assert.NoError(t, tracker.check(5*time.Minute), "a fresh failure is within the threshold")
+
+	// Failing again later does not restart the clock
+	*now = now.Add(4 * time.Minute)
+	_, _ = semaphore.Reconcile(context.Background(), ctrl.Request{})
+	*now = now.Add(2 * time.Minute)
+
+	err = tracker.check(5 * time.Minute)
+	require.Error(t, err)
+	assert.EqualError(t, err, "controllers not reconciling: semaphore (reconciling without success for 6m0s, never succeeded)")
+
+	// A success makes it healthy again
+	fail = false
+	_, err = semaphore.Reconcile(context.Background(), ctrl.Request{})
+	require.NoError(t, err)
+	assert.NoError(t, tracker.check(5*time.Minute))
+}
+
+func TestHealthTracker_ReportsLastSuccess(t *testing.T) {
+	tracker, now := testHealthTracker()
+	fail := false
+	lease := tracker.track("lease", outcomeReconciler(&fail))
+	mutex := tracker.track("mutex", outcomeReconciler(&fail))
+
+	_, _ = lease.Reconcile(context.Background(), ctrl.Request{})
+	*now = now.Add(time.Minute)
+	fail = true
+	_, _ = lease.Reconcile(context.Background(), ctrl.Request{})
+	_, _ = mutex.Reconcile(context.Background(), ctrl.Request{})
+	*now = now.Add(10 * time.Minute)
+
+	assert.EqualError(t, tracker.check(5*time.Minute), "controllers not reconciling: "+
+		"lease (reconciling without success for 10m0s, last succeeded 11m0s ago); "+
+		"mutex (reconciling without success for 10m0s, never succeeded)")
+}
+
+func TestHealthTracker_IdleControllerIsNotStale(t *testing.T) {
+	tracker, now := testHealthTracker()
+	tracker.track("barrier", outcomeReconciler(new(bool)))
+
+	*now = now.Add(time.Hour)
+	assert.NoError(t, tracker.check(5*time.Minute))
+}
+
+// syncedCache is a cache that only answers whether it has synced
+type syncedCache struct {
+	cache.Cache
+	synced bool
+}
+
+func (c *syncedCache) WaitForCacheSync(ctx context.Context) bool {
+	return c.synced
+}
+
+func TestReadinessCheck(t *testing.T) {
+	tracker, now := testHealthTracker()
+	fail := true
+	waitgroup := tracker.track("waitgroup", outcomeReconciler(&fail))
+	req := httptest.NewRequest("GET", "/readyz", nil)
+
+	unsynced := readinessCheck(&syncedCache{}, tracker, time.Minute)
+	assert.EqualError(t, unsynced(req), "informer caches are not synced")
+
+	check := readinessCheck(&syncedCache{synced: true}, tracker, time.Minute)
+	assert.NoError(t, check(req))
+
+	_, _ = waitgroup.Reconcile(context.Background(), ctrl.Request{})
+	*now = now.Add(2 * time.Minute)
+	err := check(req)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waitgroup (reconciling without success for 2m0s")
+}
@@ -2,22 +2,41 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
 
+const (
+	// DefaultLeaseMinRequeue is the shortest requeue when MinRequeue is unset
+	DefaultLeaseMinRequeue = time.Second
+
+	// DefaultLeaseMaxRequeue caps the requeue interval when MaxRequeue is unset
+	DefaultLeaseMaxRequeue = time.Minute
+)
+
 // LeaseReconciler reconciles a Lease object
 type LeaseReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MinRequeue is the shortest wait before a held lease is checked for
+	// expiry again. Defaults to DefaultLeaseMinRequeue.
+	MinRequeue time.Duration
+
+	// MaxRequeue caps the wait between checks of a lease, however far off its
+	// expiry. Defaults to DefaultLeaseMaxRequeue.
+	MaxRequeue time.Duration
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
@@ -26,32 +45,49 @@ type LeaseReconciler struct {
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=leaserequests,verbs=get;list;watch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=leaserequests/status,verbs=get;update;patch
 
-func (r *LeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
-
-	log.Info("Reconciling Lease", "name", req.Name, "namespace", req.Namespace)
+func (r *LeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("Lease", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "Lease", req)
 
 	var lease syncv1.Lease
 	if err := r.Get(ctx, req.NamespacedName, &lease); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Lease not found, likely deleted", "name", req.Name)
+			leaseHoldDuration.DeleteLabelValues(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "unable to fetch Lease")
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Found Lease", "name", lease.Name, "currentHolder", lease.Status.Holder, "currentPhase", lease.Status.Phase)
+	log.V(1).Info("Found Lease", "currentHolder", lease.Status.Holder, "currentPhase", lease.Status.Phase)
 
+	original := lease.Status.DeepCopy()
 	now := time.Now()
+	expiredHolder := ""
+	granted := false
 
 	if lease.Status.ExpiresAt != nil && lease.Status.ExpiresAt.Time.Before(now) {
+		expiredHolder = lease.Status.Holder
+		if lease.Status.AcquiredAt != nil {
+			held := lease.Status.ExpiresAt.Sub(lease.Status.AcquiredAt.Time)
+			leaseHoldDuration.WithLabelValues(lease.Namespace, lease.Name).Observe(held.Seconds())
+		}
 		lease.Status.Phase = syncv1.LeasePhaseExpired
 		lease.Status.Holder = ""
 		lease.Status.AcquiredAt = nil
 		lease.Status.ExpiresAt = nil
 	}
 
+	revokedHolder := ""
+	if lease.Status.Holder != "" && exceededMaxHold(&lease, now) {
+		revokedHolder = lease.Status.Holder
+		leaseHoldDuration.WithLabelValues(lease.Namespace, lease.Name).Observe(now.Sub(lease.Status.AcquiredAt.Time).Seconds())
+		lease.Status.Holder = ""
+		lease.Status.AcquiredAt = nil
+		lease.Status.ExpiresAt = nil
+	}
+
 	if lease.Status.Holder == "" {
 		lease.Status.Phase = syncv1.LeasePhaseAvailable
 	}
@@ -63,7 +99,23 @@ func (r *LeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Found lease requests", "count", len(requests.Items), "lease", lease.Name)
+	log.V(1).Info("Found lease requests", "count", len(requests.Items))
+
+	// The revoked holder's request is denied so the lease is not simply
+	// granted back to it
+	if revokedHolder != "" {
+		for i := range requests.Items {
+			leaseReq := &requests.Items[i]
+			if leaseReq.Spec.Holder != revokedHolder || leaseReq.Status.Phase == syncv1.LeaseRequestPhaseDenied {
+				continue
+			}
+			leaseReq.Status.Phase = syncv1.LeaseRequestPhaseDenied
+			if err := r.Status().Update(ctx, leaseReq); err != nil {
+				log.Error(err, "unable to update lease request status", "request", leaseReq.Name)
+				return ctrl.Result{RequeueAfter: time.Second * 5}, err
+			}
+		}
+	}
 
 	if lease.Status.Phase == syncv1.LeasePhaseAvailable && len(requests.Items) > 0 {
 		var bestRequest *syncv1.LeaseRequest
@@ -71,6 +123,9 @@ func (r *LeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 
 		for i := range requests.Items {
 			leaseReq := &requests.Items[i]
+			if leaseReq.Status.Phase == syncv1.LeaseRequestPhaseDenied {
+				continue
+			}
 			priority := int32(0)
 			if leaseReq.Spec.Priority != nil {
 				priority = *leaseReq.Spec.Priority
@@ -91,35 +146,112 @@ func (r *LeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 				lease.Status.ExpiresAt = &expiresAt
 			}
 			lease.Status.RenewCount = 0
+			// Persisted in status so the token keeps increasing across controller restarts
+			lease.Status.FenceToken++
 
 			bestRequest.Status.Phase = syncv1.LeaseRequestPhaseGranted
 			if err := r.Status().Update(ctx, bestRequest); err != nil {
 				log.Error(err, "unable to update lease request status", "request", bestRequest.Name)
 				return ctrl.Result{RequeueAfter: time.Second * 5}, err
 			}
+			granted = true
 		}
 	}
 
-	if err := r.Status().Update(ctx, &lease); err != nil {
-		if errors.IsConflict(err) {
-			log.V(1).Info("Lease update conflict, will retry", "name", lease.Name)
-			return ctrl.Result{Requeue: true}, nil
+	observeGeneration(&lease.Status.ObservedGeneration, &lease)
+	if !equality.Semantic.DeepEqual(original, &lease.Status) {
+		if err := r.Status().Update(ctx, &lease); err != nil {
+			if errors.IsConflict(err) {
+				log.V(1).Info("Lease update conflict, will retry", "name", lease.Name)
+				return ctrl.Result{Requeue: true}, nil
+			}
+			log.Error(err, "unable to update Lease status")
+			return ctrl.Result{}, err
 		}
-		log.Error(err, "unable to update Lease status")
-		return ctrl.Result{}, err
 	}
 
-	log.Info("Successfully updated Lease status", "name", lease.Name, "holder", lease.Status.Holder, "phase", lease.Status.Phase)
+	reason := "no holder"
+	switch {
+	case granted:
+		reason = fmt.Sprintf("granted to %s", lease.Status.Holder)
+	case expiredHolder != "":
+		reason = fmt.Sprintf("lease held by %s expired", expiredHolder)
+	case revokedHolder != "":
+		reason = fmt.Sprintf("lease held by %s exceeded its maximum hold time", revokedHolder)
+	case lease.Status.Holder != "":
+		reason = fmt.Sprintf("held by %s", lease.Status.Holder)
+	}
+	logPhase(log, original.Phase, lease.Status.Phase, reason)
 
-	if lease.Status.ExpiresAt != nil {
-		return ctrl.Result{RequeueAfter: time.Until(lease.Status.ExpiresAt.Time)}, nil
+	if expiredHolder != "" {
+		recordWarning(r.Recorder, &lease, ReasonLeaseExpired, "Lease held by %s expired", expiredHolder)
+	}
+	if revokedHolder != "" {
+		recordWarning(r.Recorder, &lease, ReasonLeaseMaxHoldExceeded, "Lease held by %s revoked after exceeding its maximum hold time of %s",
+			revokedHolder, lease.Spec.MaxHoldTime.Duration)
+	}
+	if granted {
+		recordNormal(r.Recorder, &lease, ReasonLeaseGranted, "Lease granted to %s", lease.Status.Holder)
+	}
+
+	return ctrl.Result{RequeueAfter: r.requeueAfter(&lease, time.Now())}, nil
+}
+
+// exceededMaxHold reports whether the lease has been held for longer than
+// its MaxHoldTime
+func exceededMaxHold(lease *syncv1.Lease, now time.Time) bool {
+	deadline, ok := maxHoldDeadline(lease)
+	return ok && now.After(deadline)
+}
+
+// maxHoldDeadline returns when the current holder's MaxHoldTime runs out, if
+// the lease has one
+func maxHoldDeadline(lease *syncv1.Lease) (time.Time, bool) {
+	if lease.Spec.MaxHoldTime == nil || lease.Spec.MaxHoldTime.Duration <= 0 || lease.Status.AcquiredAt == nil {
+		return time.Time{}, false
+	}
+	return lease.Status.AcquiredAt.Add(lease.Spec.MaxHoldTime.Duration), true
+}
+
+// requeueAfter returns the time until the lease expires or reaches its
+// maximum hold time, whichever comes first, clamped between MinRequeue and
+// MaxRequeue so either is processed as soon as it is due. A lease with
+// neither is checked again after MaxRequeue.
+func (r *LeaseReconciler) requeueAfter(lease *syncv1.Lease, now time.Time) time.Duration {
+	minRequeue := r.MinRequeue
+	if minRequeue <= 0 {
+		minRequeue = DefaultLeaseMinRequeue
+	}
+	maxRequeue := r.MaxRequeue
+	if maxRequeue <= 0 {
+		maxRequeue = DefaultLeaseMaxRequeue
 	}
 
-	return ctrl.Result{RequeueAfter: time.Minute}, nil
+	var due time.Time
+	if lease.Status.ExpiresAt != nil {
+		due = lease.Status.ExpiresAt.Time
+	}
+	if deadline, ok := maxHoldDeadline(lease); ok && (due.IsZero() || deadline.Before(due)) {
+		due = deadline
+	}
+	if due.IsZero() {
+		return maxRequeue
+	}
+	interval := due.Sub(now)
+	if interval < minRequeue {
+		interval = minRequeue
+	}
+	if interval > maxRequeue {
+		interval = maxRequeue
+	}
+	return interval
 }
 
 func (r *LeaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("lease-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.Lease{}).
-		Complete(r)
+		Complete(trackHealth("lease", r))
 }
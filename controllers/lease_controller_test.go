@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -180,9 +181,11 @@ func TestLeaseReconciler_Expiration(t *testing.T) {
 		WithStatusSubresource(&syncv1.Lease{}).
 		Build()
 
+	recorder := record.NewFakeRecorder(10)
 	reconciler := &LeaseReconciler{
-		Client: client,
-		Scheme: scheme,
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
 	}
 
 	req := ctrl.Request{
@@ -205,4 +208,457 @@ func TestLeaseReconciler_Expiration(t *testing.T) {
 	assert.Equal(t, syncv1.LeasePhaseAvailable, updated.Status.Phase)
 	assert.Equal(t, "", updated.Status.Holder)
 	assert.Nil(t, updated.Status.ExpiresAt)
+	assertEvents(t, recorder, "Warning LeaseExpired Lease held by holder-1 expired")
+}
+
+func TestLeaseReconciler_RequeuesAtExpiry(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "default",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase:     syncv1.LeasePhaseHeld,
+			Holder:    "holder-1",
+			ExpiresAt: &metav1.Time{Time: time.Now().Add(2 * time.Second)},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease).
+		WithStatusSubresource(&syncv1.Lease{}).
+		Build()
+
+	reconciler := &LeaseReconciler{Client: client, Scheme: scheme}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: lease.Name, Namespace: lease.Namespace},
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	// Expiry is due in 2s, well before the default one minute cap
+	assert.Greater(t, result.RequeueAfter, time.Second)
+	assert.LessOrEqual(t, result.RequeueAfter, 2*time.Second)
+}
+
+func TestLeaseRequeueAfter(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	expiringIn := func(d time.Duration) *syncv1.Lease {
+		return &syncv1.Lease{Status: syncv1.LeaseStatus{ExpiresAt: &metav1.Time{Time: now.Add(d)}}}
+	}
+
+	tests := []struct {
+		name       string
+		reconciler *LeaseReconciler
+		lease      *syncv1.Lease
+		want       time.Duration
+	}{
+		{
+			name:       "time until expiry",
+			reconciler: &LeaseReconciler{},
+			lease:      expiringIn(2 * time.Second),
+			want:       2 * time.Second,
+		},
+		{
+			name:       "no expiry waits the maximum",
+			reconciler: &LeaseReconciler{},
+			lease:      &syncv1.Lease{},
+			want:       DefaultLeaseMaxRequeue,
+		},
+		{
+			name:       "distant expiry is capped",
+			reconciler: &LeaseReconciler{MaxRequeue: 10 * time.Second},
+			lease:      expiringIn(time.Hour),
+			want:       10 * time.Second,
+		},
+		{
+			name:       "imminent expiry waits the minimum",
+			reconciler: &LeaseReconciler{},
+			lease:      expiringIn(100 * time.Millisecond),
+			want:       DefaultLeaseMinRequeue,
+		},
+		{
+			name:       "max hold time due before expiry",
+			reconciler: &LeaseReconciler{},
+			lease: &syncv1.Lease{
+				Spec: syncv1.LeaseSpec{MaxHoldTime: &metav1.Duration{Duration: time.Minute}},
+				Status: syncv1.LeaseStatus{
+					AcquiredAt: &metav1.Time{Time: now.Add(-50 * time.Second)},
+					ExpiresAt:  &metav1.Time{Time: now.Add(30 * time.Second)},
+				},
+			},
+			want: 10 * time.Second,
+		},
+		{
+			name:       "past expiry waits the configured minimum",
+			reconciler: &LeaseReconciler{MinRequeue: 200 * time.Millisecond},
+			lease:      expiringIn(-time.Second),
+			want:       200 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.reconciler.requeueAfter(tt.lease, now))
+		})
+	}
+}
+
+func TestLeaseReconciler_FenceTokenIncreasesAcrossHolders(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	// Start from a token left by an earlier controller instance
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "default",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase:      syncv1.LeasePhaseAvailable,
+			FenceToken: 5,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease).
+		WithStatusSubresource(&syncv1.Lease{}, &syncv1.LeaseRequest{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LeaseReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      lease.Name,
+			Namespace: lease.Namespace,
+		},
+	}
+
+	lastToken := lease.Status.FenceToken
+	previous := ""
+	for _, holder := range []string{"holder-1", "holder-2", "holder-3"} {
+		request := &syncv1.LeaseRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-lease-" + holder,
+				Namespace: "default",
+				Labels:    map[string]string{"lease": "test-lease"},
+			},
+			Spec: syncv1.LeaseRequestSpec{
+				Lease:  "test-lease",
+				Holder: holder,
+			},
+		}
+		require.NoError(t, client.Create(context.Background(), request))
+
+		_, err := reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		var updated syncv1.Lease
+		require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+		assert.Equal(t, holder, updated.Status.Holder)
+		assert.Greater(t, updated.Status.FenceToken, lastToken, "fence token must strictly increase for %s", holder)
+		lastToken = updated.Status.FenceToken
+
+		// Each grant after the first also expires the previous holder
+		expected := []string{"Normal LeaseGranted Lease granted to " + holder}
+		if previous != "" {
+			expected = append([]string{"Warning LeaseExpired Lease held by " + previous + " expired"}, expected...)
+		}
+		assertEvents(t, recorder, expected...)
+		previous = holder
+
+		// Release the lease and let it expire so the next holder can be granted
+		require.NoError(t, client.Delete(context.Background(), request))
+		expired := metav1.NewTime(time.Now().Add(-time.Minute))
+		updated.Status.ExpiresAt = &expired
+		require.NoError(t, client.Status().Update(context.Background(), &updated))
+	}
+
+	assert.Equal(t, int64(8), lastToken)
+}
+
+func TestLeaseReconciler_FenceTokenStableWhileHeld(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	expiresAt := metav1.NewTime(time.Now().Add(time.Hour))
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "default",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase:      syncv1.LeasePhaseHeld,
+			Holder:     "holder-1",
+			ExpiresAt:  &expiresAt,
+			FenceToken: 3,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease).
+		WithStatusSubresource(&syncv1.Lease{}).
+		Build()
+
+	reconciler := &LeaseReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      lease.Name,
+			Namespace: lease.Namespace,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Lease
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, int64(3), updated.Status.FenceToken)
+}
+
+func TestLeaseReconciler_RespectsTransferredHolder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	// The state left by handing the lease from holder-1 to holder-2: the new
+	// holder's request is granted and another holder is still waiting
+	acquiredAt := metav1.Now()
+	expiresAt := metav1.NewTime(time.Now().Add(time.Hour))
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "default",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase:      syncv1.LeasePhaseHeld,
+			Holder:     "holder-2",
+			AcquiredAt: &acquiredAt,
+			ExpiresAt:  &expiresAt,
+			FenceToken: 2,
+		},
+	}
+	priority := int32(10)
+	requests := []runtime.Object{
+		&syncv1.LeaseRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-lease-holder-2",
+				Namespace: "default",
+				Labels:    map[string]string{"lease": "test-lease"},
+			},
+			Spec:   syncv1.LeaseRequestSpec{Lease: "test-lease", Holder: "holder-2"},
+			Status: syncv1.LeaseRequestStatus{Phase: syncv1.LeaseRequestPhaseGranted},
+		},
+		&syncv1.LeaseRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-lease-holder-3",
+				Namespace: "default",
+				Labels:    map[string]string{"lease": "test-lease"},
+			},
+			Spec:   syncv1.LeaseRequestSpec{Lease: "test-lease", Holder: "holder-3", Priority: &priority},
+			Status: syncv1.LeaseRequestStatus{Phase: syncv1.LeaseRequestPhasePending},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(append(requests, lease)...).
+		WithStatusSubresource(&syncv1.Lease{}, &syncv1.LeaseRequest{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LeaseReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      lease.Name,
+			Namespace: lease.Namespace,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Lease
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, "holder-2", updated.Status.Holder)
+	assert.Equal(t, syncv1.LeasePhaseHeld, updated.Status.Phase)
+	assert.Equal(t, int64(2), updated.Status.FenceToken)
+
+	var waiting syncv1.LeaseRequest
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: "test-lease-holder-3", Namespace: "default"}, &waiting))
+	assert.Equal(t, syncv1.LeaseRequestPhasePending, waiting.Status.Phase)
+	assertEvents(t, recorder)
+}
+
+// leaseHeldSince returns a lease held by holder-1 since acquiredAt and
+// renewed to expire in an hour, with holder-1's granted request and any
+// other requests given
+func leaseHeldSince(acquiredAt time.Time, maxHold time.Duration, others ...*syncv1.LeaseRequest) []runtime.Object {
+	expiresAt := metav1.NewTime(time.Now().Add(time.Hour))
+	objects := []runtime.Object{
+		&syncv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-lease",
+				Namespace: "default",
+			},
+			Spec: syncv1.LeaseSpec{
+				TTL:         &metav1.Duration{Duration: time.Hour},
+				MaxHoldTime: &metav1.Duration{Duration: maxHold},
+			},
+			Status: syncv1.LeaseStatus{
+				Phase:      syncv1.LeasePhaseHeld,
+				Holder:     "holder-1",
+				AcquiredAt: &metav1.Time{Time: acquiredAt},
+				ExpiresAt:  &expiresAt,
+				RenewCount: 12,
+				FenceToken: 1,
+			},
+		},
+		&syncv1.LeaseRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-lease-holder-1",
+				Namespace: "default",
+				Labels:    map[string]string{"lease": "test-lease"},
+			},
+			Spec:   syncv1.LeaseRequestSpec{Lease: "test-lease", Holder: "holder-1"},
+			Status: syncv1.LeaseRequestStatus{Phase: syncv1.LeaseRequestPhaseGranted},
+		},
+	}
+	for _, other := range others {
+		objects = append(objects, other)
+	}
+	return objects
+}
+
+func TestLeaseReconciler_RevokesAfterMaxHoldTime(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(leaseHeldSince(time.Now().Add(-2*time.Hour), time.Hour)...).
+		WithStatusSubresource(&syncv1.Lease{}, &syncv1.LeaseRequest{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LeaseReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-lease", Namespace: "default"}}
+
+	// Revoked even though renewals keep its expiry an hour away, and not
+	// granted back to the same holder on the next pass
+	for i := 0; i < 2; i++ {
+		_, err := reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+	}
+
+	var updated syncv1.Lease
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.LeasePhaseAvailable, updated.Status.Phase)
+	assert.Empty(t, updated.Status.Holder)
+	assert.Nil(t, updated.Status.AcquiredAt)
+	assert.Nil(t, updated.Status.ExpiresAt)
+
+	var request syncv1.LeaseRequest
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: "test-lease-holder-1", Namespace: "default"}, &request))
+	assert.Equal(t, syncv1.LeaseRequestPhaseDenied, request.Status.Phase)
+
+	assertEvents(t, recorder, "Warning LeaseMaxHoldExceeded Lease held by holder-1 revoked after exceeding its maximum hold time of 1h0m0s")
+}
+
+func TestLeaseReconciler_MaxHoldTimeGrantsNextRequest(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	waiting := &syncv1.LeaseRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease-holder-2",
+			Namespace: "default",
+			Labels:    map[string]string{"lease": "test-lease"},
+		},
+		Spec:   syncv1.LeaseRequestSpec{Lease: "test-lease", Holder: "holder-2"},
+		Status: syncv1.LeaseRequestStatus{Phase: syncv1.LeaseRequestPhasePending},
+	}
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(leaseHeldSince(time.Now().Add(-2*time.Hour), time.Hour, waiting)...).
+		WithStatusSubresource(&syncv1.Lease{}, &syncv1.LeaseRequest{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LeaseReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-lease", Namespace: "default"}}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Lease
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.LeasePhaseHeld, updated.Status.Phase)
+	assert.Equal(t, "holder-2", updated.Status.Holder)
+	assert.Equal(t, int64(2), updated.Status.FenceToken)
+	assert.Equal(t, int32(0), updated.Status.RenewCount)
+
+	assertEvents(t, recorder,
+		"Warning LeaseMaxHoldExceeded Lease held by holder-1 revoked after exceeding its maximum hold time of 1h0m0s",
+		"Normal LeaseGranted Lease granted to holder-2")
+}
+
+func TestLeaseReconciler_KeepsHolderWithinMaxHoldTime(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(leaseHeldSince(time.Now().Add(-30*time.Minute), time.Hour)...).
+		WithStatusSubresource(&syncv1.Lease{}, &syncv1.LeaseRequest{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LeaseReconciler{Client: client, Scheme: scheme, Recorder: recorder, MaxRequeue: 2 * time.Hour}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-lease", Namespace: "default"}}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Lease
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.LeasePhaseHeld, updated.Status.Phase)
+	assert.Equal(t, "holder-1", updated.Status.Holder)
+	assertEvents(t, recorder)
+
+	// Checked again when the maximum hold time runs out, ahead of the expiry
+	assert.InDelta(t, 30*time.Minute, result.RequeueAfter, float64(time.Minute))
 }
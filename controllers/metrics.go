@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	semaphorePermitsInUse = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "konductor_semaphore_permits_in_use",
+			Help: "Number of semaphore permits currently in use",
+		},
+		[]string{"namespace", "semaphore"},
+	)
+
+	semaphorePermitsAvailable = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "konductor_semaphore_permits_available",
+			Help: "Number of semaphore permits currently available",
+		},
+		[]string{"namespace", "semaphore"},
+	)
+
+	barrierArrivals = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "konductor_barrier_arrivals",
+			Help: "Number of arrivals recorded at a barrier",
+		},
+		[]string{"namespace", "barrier"},
+	)
+
+	barrierExpected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "konductor_barrier_expected",
+			Help: "Number of arrivals expected at a barrier",
+		},
+		[]string{"namespace", "barrier"},
+	)
+
+	leaseHoldDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "konductor_lease_hold_duration_seconds",
+			Help:    "How long a lease was held before it expired",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 16),
+		},
+		[]string{"namespace", "lease"},
+	)
+
+	mutexContention = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "konductor_mutex_contention_total",
+			Help: "Number of conflicting concurrent updates observed on a mutex",
+		},
+		[]string{"namespace", "mutex"},
+	)
+
+	gateConditionsMet = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "konductor_gate_conditions_met",
+			Help: "Number of gate conditions currently met",
+		},
+		[]string{"namespace", "gate"},
+	)
+
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "konductor_reconcile_duration_seconds",
+			Help:    "How long a controller took to reconcile a primitive",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+		},
+		[]string{"controller"},
+	)
+
+	reconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "konductor_reconcile_errors_total",
+			Help: "Number of reconciles that returned an error",
+		},
+		[]string{"controller"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		semaphorePermitsInUse,
+		semaphorePermitsAvailable,
+		barrierArrivals,
+		barrierExpected,
+		leaseHoldDuration,
+		mutexContention,
+		gateConditionsMet,
+		reconcileDuration,
+		reconcileErrors,
+	)
+}
+
+// observeReconcile records a reconcile by controller that started at start,
+// counting it as an error if *err is set. Reconcile defers it with a pointer
+// to its error result, so every return is recorded.
+func observeReconcile(controller string, start time.Time, err *error) {
+	reconcileDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		reconcileErrors.WithLabelValues(controller).Inc()
+	}
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// assertMetric checks that the registry exposes the metric family with a series
+// carrying exactly the given labels.
+func assertMetric(t *testing.T, name string, labels map[string]string) {
+	t.Helper()
+
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			got := make(map[string]string)
+			for _, pair := range metric.GetLabel() {
+				got[pair.GetName()] = pair.GetValue()
+			}
+			if assert.ObjectsAreEqual(labels, got) {
+				return
+			}
+		}
+		t.Fatalf("metric %s has no series with labels %v", name, labels)
+	}
+	t.Fatalf("metric family %s not found", name)
+}
+
+func reconcileRequest(name string) ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "metrics"}}
+}
+
+func TestMetrics_Semaphore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "sem", Namespace: "metrics"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 3},
+		Status:     syncv1.SemaphoreStatus{Phase: syncv1.SemaphorePhaseReady},
+	}
+	permit := &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sem-holder",
+			Namespace: "metrics",
+			Labels:    map[string]string{"semaphore": "sem"},
+		},
+		Spec: syncv1.PermitSpec{Semaphore: "sem", Holder: "holder"},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, permit).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	reconciler := &SemaphoreReconciler{Client: k8sClient, Scheme: scheme}
+	_, err := reconciler.Reconcile(context.Background(), reconcileRequest("sem"))
+	require.NoError(t, err)
+
+	labels := map[string]string{"namespace": "metrics", "semaphore": "sem"}
+	assertMetric(t, "konductor_semaphore_permits_in_use", labels)
+	assertMetric(t, "konductor_semaphore_permits_available", labels)
+}
+
+func TestMetrics_Barrier(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "metrics"},
+		Spec:       syncv1.BarrierSpec{Expected: 2},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(barrier).
+		WithStatusSubresource(&syncv1.Barrier{}).
+		Build()
+
+	reconciler := &BarrierReconciler{Client: k8sClient, Scheme: scheme}
+	_, err := reconciler.Reconcile(context.Background(), reconcileRequest("bar"))
+	require.NoError(t, err)
+
+	labels := map[string]string{"namespace": "metrics", "barrier": "bar"}
+	assertMetric(t, "konductor_barrier_arrivals", labels)
+	assertMetric(t, "konductor_barrier_expected", labels)
+}
+
+func TestMetrics_LeaseHoldDuration(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	acquiredAt := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	expiresAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "lease", Namespace: "metrics"},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: time.Minute}},
+		Status: syncv1.LeaseStatus{
+			Holder:     "holder",
+			Phase:      syncv1.LeasePhaseHeld,
+			AcquiredAt: &acquiredAt,
+			ExpiresAt:  &expiresAt,
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease).
+		WithStatusSubresource(&syncv1.Lease{}).
+		Build()
+
+	reconciler := &LeaseReconciler{Client: k8sClient, Scheme: scheme}
+	_, err := reconciler.Reconcile(context.Background(), reconcileRequest("lease"))
+	require.NoError(t, err)
+
+	assertMetric(t, "konductor_lease_hold_duration_seconds", map[string]string{"namespace": "metrics", "lease": "lease"})
+}
+
+func TestMetrics_MutexContention(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	expiresAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "mutex", Namespace: "metrics"},
+		Status: syncv1.MutexStatus{
+			Holder:    "holder",
+			Phase:     syncv1.MutexPhaseLocked,
+			ExpiresAt: &expiresAt,
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				return errors.NewConflict(schema.GroupResource{Group: "sync.konductor.io", Resource: "mutexes"}, obj.GetName(), nil)
+			},
+		}).
+		Build()
+
+	reconciler := &MutexReconciler{Client: k8sClient, Scheme: scheme}
+	result, err := reconciler.Reconcile(context.Background(), reconcileRequest("mutex"))
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+
+	assertMetric(t, "konductor_mutex_contention_total", map[string]string{"namespace": "metrics", "mutex": "mutex"})
+}
+
+func TestMetrics_GateConditionsMet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "gate", Namespace: "metrics"},
+		Spec: syncv1.GateSpec{
+			Conditions: []syncv1.GateCondition{
+				{Type: "Barrier", Name: "missing", State: "Open"},
+			},
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(gate).
+		WithStatusSubresource(&syncv1.Gate{}).
+		Build()
+
+	reconciler := &GateReconciler{Client: k8sClient, Scheme: scheme}
+	_, err := reconciler.Reconcile(context.Background(), reconcileRequest("gate"))
+	require.NoError(t, err)
+
+	assertMetric(t, "konductor_gate_conditions_met", map[string]string{"namespace": "metrics", "gate": "gate"})
+}
+
+func TestMetrics_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	once := &syncv1.Once{ObjectMeta: metav1.ObjectMeta{Name: "once", Namespace: "metrics"}}
+	failGet := false
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(once).
+		WithStatusSubresource(&syncv1.Once{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if failGet {
+					return errors.NewServiceUnavailable("api server unavailable")
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	reconciler := &OnceReconciler{Client: k8sClient, Scheme: scheme}
+
+	observed := func() uint64 {
+		histogram, ok := reconcileDuration.WithLabelValues("Once").(prometheus.Histogram)
+		require.True(t, ok)
+		var metric dto.Metric
+		require.NoError(t, histogram.Write(&metric))
+		return metric.GetHistogram().GetSampleCount()
+	}
+	startObserved := observed()
+	startErrors := testutil.ToFloat64(reconcileErrors.WithLabelValues("Once"))
+
+	_, err := reconciler.Reconcile(context.Background(), reconcileRequest("once"))
+	require.NoError(t, err)
+	assert.Equal(t, startObserved+1, observed())
+	assert.Equal(t, startErrors, testutil.ToFloat64(reconcileErrors.WithLabelValues("Once")), "a successful reconcile is not an error")
+
+	failGet = true
+	_, err = reconciler.Reconcile(context.Background(), reconcileRequest("once"))
+	require.Error(t, err)
+	assert.Equal(t, startObserved+2, observed(), "a failed reconcile is timed too")
+	assert.Equal(t, startErrors+1, testutil.ToFloat64(reconcileErrors.WithLabelValues("Once")))
+}
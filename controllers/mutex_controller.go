@@ -2,13 +2,14 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
@@ -16,34 +17,41 @@ import (
 // MutexReconciler reconciles a Mutex object
 type MutexReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=mutexes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=mutexes/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=mutexes/finalizers,verbs=update
 
-func (r *MutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
+func (r *MutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("Mutex", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "Mutex", req)
 
 	var mutex syncv1.Mutex
 	if err := r.Get(ctx, req.NamespacedName, &mutex); err != nil {
 		if errors.IsNotFound(err) {
+			mutexContention.DeleteLabelValues(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
 	now := time.Now()
+	oldPhase := mutex.Status.Phase
 	updated := false
+	expiredHolder := ""
 
 	// Check TTL expiration
-	if mutex.Status.ExpiresAt != nil && mutex.Status.ExpiresAt.Time.Before(now) {
+	if hasExpired(mutex.Status.ExpiresAt, now) {
 		log.Info("Mutex expired due to TTL", "holder", mutex.Status.Holder, "expiresAt", mutex.Status.ExpiresAt)
+		expiredHolder = mutex.Status.Holder
 		mutex.Status.Phase = syncv1.MutexPhaseUnlocked
 		mutex.Status.Holder = ""
 		mutex.Status.LockedAt = nil
 		mutex.Status.ExpiresAt = nil
+		mutex.Status.HoldCount = 0
 		updated = true
 	}
 
@@ -52,26 +60,42 @@ func (r *MutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		updated = true
 	}
 
+	if observeGeneration(&mutex.Status.ObservedGeneration, &mutex) {
+		updated = true
+	}
+
 	if updated {
 		if err := r.Status().Update(ctx, &mutex); err != nil {
 			if errors.IsConflict(err) {
+				mutexContention.WithLabelValues(mutex.Namespace, mutex.Name).Inc()
 				return ctrl.Result{Requeue: true}, nil
 			}
 			log.Error(err, "unable to update Mutex status")
 			return ctrl.Result{}, err
 		}
+		if expiredHolder != "" {
+			recordWarning(r.Recorder, &mutex, ReasonMutexTTLExpired, "Lock held by %s expired", expiredHolder)
+		}
 	}
 
-	// Requeue if TTL is set
-	if mutex.Status.ExpiresAt != nil && mutex.Status.ExpiresAt.Time.After(now) {
-		return ctrl.Result{RequeueAfter: time.Until(mutex.Status.ExpiresAt.Time)}, nil
+	reason := "no holder"
+	switch {
+	case expiredHolder != "":
+		reason = fmt.Sprintf("lock held by %s expired", expiredHolder)
+	case mutex.Status.Holder != "":
+		reason = fmt.Sprintf("held by %s", mutex.Status.Holder)
 	}
+	logPhase(log, oldPhase, mutex.Status.Phase, reason)
 
-	return ctrl.Result{}, nil
+	// An unexpired TTL is cleared as soon as it runs out
+	return requeueAtExpiry(mutex.Status.ExpiresAt, now), nil
 }
 
 func (r *MutexReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("mutex-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.Mutex{}).
-		Complete(r)
+		Complete(trackHealth("mutex", r))
 }
@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -104,6 +105,7 @@ func TestMutexReconciler_Expiration(t *testing.T) {
 		Status: syncv1.MutexStatus{
 			Phase:     syncv1.MutexPhaseLocked,
 			Holder:    "holder-1",
+			HoldCount: 2,
 			ExpiresAt: &metav1.Time{Time: time.Now().Add(-time.Hour)},
 		},
 	}
@@ -114,9 +116,11 @@ func TestMutexReconciler_Expiration(t *testing.T) {
 		WithStatusSubresource(&syncv1.Mutex{}).
 		Build()
 
+	recorder := record.NewFakeRecorder(10)
 	reconciler := &MutexReconciler{
-		Client: client,
-		Scheme: scheme,
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
 	}
 
 	req := ctrl.Request{
@@ -136,6 +140,8 @@ func TestMutexReconciler_Expiration(t *testing.T) {
 	assert.Equal(t, syncv1.MutexPhaseUnlocked, updated.Status.Phase)
 	assert.Equal(t, "", updated.Status.Holder)
 	assert.Nil(t, updated.Status.ExpiresAt)
+	assert.Equal(t, int32(0), updated.Status.HoldCount)
+	assertEvents(t, recorder, "Warning MutexTTLExpired Lock held by holder-1 expired")
 }
 
 func TestMutexReconciler_RequeueWithTTL(t *testing.T) {
@@ -179,3 +185,105 @@ func TestMutexReconciler_RequeueWithTTL(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, result.RequeueAfter > 0)
 }
+
+func TestMutexReconciler_ExpirationKeepsFenceToken(t *testing.T) {
+	scheme := setupMutexScheme(t)
+
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "default",
+		},
+		Spec: syncv1.MutexSpec{
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: syncv1.MutexStatus{
+			Phase:      syncv1.MutexPhaseLocked,
+			Holder:     "holder-1",
+			ExpiresAt:  &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			FenceToken: 7,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		Build()
+
+	reconciler := &MutexReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      mutex.Name,
+			Namespace: mutex.Namespace,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Mutex
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+
+	// Clearing an expired holder must never reset the token, or a later holder
+	// could be issued a token an earlier one already used
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, updated.Status.Phase)
+	assert.Equal(t, int64(7), updated.Status.FenceToken)
+}
+
+func TestMutexReconciler_UnlocksWhenRequeuedAtExpiry(t *testing.T) {
+	scheme := setupMutexScheme(t)
+
+	// Stored timestamps have whole-second precision, so expire on a second
+	// boundary between one and two seconds away
+	expiresAt := metav1.NewTime(time.Now().Truncate(time.Second).Add(2 * time.Second))
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "default",
+		},
+		Spec: syncv1.MutexSpec{
+			TTL: &metav1.Duration{Duration: 2 * time.Second},
+		},
+		Status: syncv1.MutexStatus{
+			Phase:     syncv1.MutexPhaseLocked,
+			Holder:    "holder-1",
+			ExpiresAt: &expiresAt,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &MutexReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: mutex.Name, Namespace: mutex.Namespace}}
+
+	untilExpiry := time.Until(expiresAt.Time)
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, time.Until(expiresAt.Time), "requeued just past the expiry")
+	assert.LessOrEqual(t, result.RequeueAfter, untilExpiry+expiryRequeueMargin)
+
+	var current syncv1.Mutex
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &current))
+	assert.Equal(t, "holder-1", current.Status.Holder)
+
+	time.Sleep(result.RequeueAfter)
+	result, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &current))
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, current.Status.Phase)
+	assert.Empty(t, current.Status.Holder)
+	assert.Nil(t, current.Status.ExpiresAt)
+	assertEvents(t, recorder, "Warning MutexTTLExpired Lock held by holder-1 expired")
+}
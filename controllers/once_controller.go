@@ -2,13 +2,14 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
@@ -16,15 +17,17 @@ import (
 // OnceReconciler reconciles a Once object
 type OnceReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=onces,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=onces/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=onces/finalizers,verbs=update
 
-func (r *OnceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
+func (r *OnceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("Once", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "Once", req)
 
 	var once syncv1.Once
 	if err := r.Get(ctx, req.NamespacedName, &once); err != nil {
@@ -39,11 +42,12 @@ func (r *OnceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	if once.Status.Phase == "" {
 		once.Status.Phase = syncv1.OncePhasePending
 		once.Status.Executed = false
+		observeGeneration(&once.Status.ObservedGeneration, &once)
 		if err := r.Status().Update(ctx, &once); err != nil {
 			log.Error(err, "unable to initialize Once status")
 			return ctrl.Result{RequeueAfter: time.Second}, err
 		}
-		log.Info("Initialized Once status", "name", once.Name)
+		logPhase(log, "", once.Status.Phase, "initialized")
 		return ctrl.Result{}, nil
 	}
 
@@ -56,31 +60,91 @@ func (r *OnceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 				return ctrl.Result{RequeueAfter: time.Second}, err
 			}
 			log.Info("Deleted expired Once", "name", once.Name)
+			recordNormal(r.Recorder, &once, ReasonOnceExpired, "Deleted after TTL of %s", once.Spec.TTL.Duration)
 			return ctrl.Result{}, nil
 		}
 	}
 
+	generationChanged := observeGeneration(&once.Status.ObservedGeneration, &once)
+
 	// If already executed, ensure phase is correct
 	if once.Status.Executed {
-		if once.Status.Phase != syncv1.OncePhaseExecuted {
+		oldPhase := once.Status.Phase
+		reset, resetAfter := onceResetDue(&once, time.Now())
+		if reset {
+			executor := once.Status.Executor
+			once.Status.Executed = false
+			once.Status.Executor = ""
+			once.Status.ExecutedAt = nil
+			once.Status.CompletedAt = nil
+			once.Status.Result = ""
+			once.Status.Phase = syncv1.OncePhasePending
+			if err := r.Status().Update(ctx, &once); err != nil {
+				log.Error(err, "unable to reset Once")
+				return ctrl.Result{RequeueAfter: time.Second}, err
+			}
+			logPhase(log, oldPhase, once.Status.Phase,
+				fmt.Sprintf("reset %s after execution by %s", once.Spec.ResetInterval.Duration, executor))
+			recordNormal(r.Recorder, &once, ReasonOnceReset, "Reset %s after execution by %s", once.Spec.ResetInterval.Duration, executor)
+			return ctrl.Result{}, nil
+		}
+
+		if once.Status.Phase != syncv1.OncePhaseExecuted || generationChanged {
 			once.Status.Phase = syncv1.OncePhaseExecuted
 			if err := r.Status().Update(ctx, &once); err != nil {
 				log.Error(err, "unable to update Once phase")
 				return ctrl.Result{RequeueAfter: time.Second}, err
 			}
-			log.Info("Updated Once phase to Executed", "name", once.Name)
+			if oldPhase != syncv1.OncePhaseExecuted {
+				recordNormal(r.Recorder, &once, ReasonOnceExecuted, "Executed by %s", once.Status.Executor)
+			}
+		}
+		logPhase(log, oldPhase, once.Status.Phase, fmt.Sprintf("executed by %s", once.Status.Executor))
+		return ctrl.Result{RequeueAfter: resetAfter}, nil
+	}
+
+	if generationChanged {
+		if err := r.Status().Update(ctx, &once); err != nil {
+			log.Error(err, "unable to update Once status")
+			return ctrl.Result{RequeueAfter: time.Second}, err
 		}
-		return ctrl.Result{}, nil
 	}
 
 	// Once is pending and not executed - no action needed
 	// External processes will mark it as executed when they complete
+	logPhase(log, once.Status.Phase, once.Status.Phase, "not executed yet")
 	return ctrl.Result{}, nil
 
 }
 
+// onceResetDue reports whether an executed once with a ResetInterval can be
+// reset at now, or else how long until it can. While the executor is still
+// running, which is until it records CompletedAt, it is never due; recording
+// completion triggers another reconcile.
+func onceResetDue(once *syncv1.Once, now time.Time) (bool, time.Duration) {
+	if once.Spec.ResetInterval == nil || once.Spec.ResetInterval.Duration <= 0 {
+		return false, 0
+	}
+	if once.Status.CompletedAt == nil {
+		return false, 0
+	}
+
+	last := once.Status.CompletedAt.Time
+	if once.Status.ExecutedAt != nil {
+		last = once.Status.ExecutedAt.Time
+	}
+	resetAt := last.Add(once.Spec.ResetInterval.Duration)
+	if now.Before(resetAt) {
+		return false, resetAt.Sub(now)
+	}
+	return true, 0
+}
+
 func (r *OnceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("once-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.Once{}).
-		Complete(r)
+		Complete(trackHealth("once", r))
 }
@@ -3,12 +3,14 @@ package controllers
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -20,9 +22,10 @@ func TestOnceReconciler_Reconcile(t *testing.T) {
 	require.NoError(t, syncv1.AddToScheme(scheme))
 
 	tests := []struct {
-		name          string
-		once          *syncv1.Once
-		expectedPhase syncv1.OncePhase
+		name           string
+		once           *syncv1.Once
+		expectedPhase  syncv1.OncePhase
+		expectedEvents []string
 	}{
 		{
 			name: "pending once",
@@ -49,7 +52,8 @@ func TestOnceReconciler_Reconcile(t *testing.T) {
 					Executor: "pod-1",
 				},
 			},
-			expectedPhase: syncv1.OncePhaseExecuted,
+			expectedPhase:  syncv1.OncePhaseExecuted,
+			expectedEvents: []string{"Normal OnceExecuted Executed by pod-1"},
 		},
 	}
 
@@ -61,9 +65,11 @@ func TestOnceReconciler_Reconcile(t *testing.T) {
 				WithStatusSubresource(&syncv1.Once{}).
 				Build()
 
+			recorder := record.NewFakeRecorder(10)
 			reconciler := &OnceReconciler{
-				Client: client,
-				Scheme: scheme,
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: recorder,
 			}
 
 			req := ctrl.Request{
@@ -81,6 +87,146 @@ func TestOnceReconciler_Reconcile(t *testing.T) {
 			require.NoError(t, err)
 
 			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			assertEvents(t, recorder, tt.expectedEvents...)
+		})
+	}
+}
+
+func executedOnce(executedAt time.Time, completed bool) *syncv1.Once {
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "default",
+		},
+		Spec: syncv1.OnceSpec{
+			ResetInterval: &metav1.Duration{Duration: time.Hour},
+		},
+		Status: syncv1.OnceStatus{
+			Phase:      syncv1.OncePhaseExecuted,
+			Executed:   true,
+			Executor:   "pod-1",
+			ExecutedAt: &metav1.Time{Time: executedAt},
+			Result:     "report-42",
+		},
+	}
+	if completed {
+		once.Status.CompletedAt = &metav1.Time{Time: executedAt.Add(time.Minute)}
+	}
+	return once
+}
+
+func TestOnceResetDue(t *testing.T) {
+	executedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		once      *syncv1.Once
+		now       time.Time
+		wantReset bool
+		wantAfter time.Duration
+	}{
+		{
+			name:      "before the interval",
+			once:      executedOnce(executedAt, true),
+			now:       executedAt.Add(20 * time.Minute),
+			wantAfter: 40 * time.Minute,
+		},
+		{
+			name:      "interval elapsed",
+			once:      executedOnce(executedAt, true),
+			now:       executedAt.Add(time.Hour),
+			wantReset: true,
+		},
+		{
+			name: "still running",
+			once: executedOnce(executedAt, false),
+			now:  executedAt.Add(2 * time.Hour),
+		},
+		{
+			name: "no reset interval",
+			once: func() *syncv1.Once {
+				once := executedOnce(executedAt, true)
+				once.Spec.ResetInterval = nil
+				return once
+			}(),
+			now: executedAt.Add(2 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset, after := onceResetDue(tt.once, tt.now)
+			assert.Equal(t, tt.wantReset, reset)
+			assert.Equal(t, tt.wantAfter, after)
+		})
+	}
+}
+
+func TestOnceReconciler_ResetInterval(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	tests := []struct {
+		name           string
+		once           *syncv1.Once
+		expectExecuted bool
+		expectedPhase  syncv1.OncePhase
+		// expectedRequeue is when the once is checked again, if it is not reset
+		expectedRequeue time.Duration
+		expectedEvents  []string
+	}{
+		{
+			name:           "interval elapsed resets the once",
+			once:           executedOnce(time.Now().Add(-2*time.Hour), true),
+			expectExecuted: false,
+			expectedPhase:  syncv1.OncePhasePending,
+			expectedEvents: []string{"Normal OnceReset Reset 1h0m0s after execution by pod-1"},
+		},
+		{
+			name:            "interval not elapsed",
+			once:            executedOnce(time.Now().Add(-30*time.Minute), true),
+			expectExecuted:  true,
+			expectedPhase:   syncv1.OncePhaseExecuted,
+			expectedRequeue: 30 * time.Minute,
+		},
+		{
+			name:           "execution still running",
+			once:           executedOnce(time.Now().Add(-2*time.Hour), false),
+			expectExecuted: true,
+			expectedPhase:  syncv1.OncePhaseExecuted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithRuntimeObjects(tt.once).
+				WithStatusSubresource(&syncv1.Once{}).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &OnceReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+
+			req := ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: tt.once.Name, Namespace: tt.once.Namespace},
+			}
+
+			result, err := reconciler.Reconcile(context.Background(), req)
+			require.NoError(t, err)
+
+			var updated syncv1.Once
+			require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+			assert.Equal(t, tt.expectExecuted, updated.Status.Executed)
+			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			if !tt.expectExecuted {
+				assert.Empty(t, updated.Status.Executor)
+				assert.Nil(t, updated.Status.ExecutedAt)
+				assert.Nil(t, updated.Status.CompletedAt)
+				assert.Empty(t, updated.Status.Result)
+			}
+			assert.InDelta(t, tt.expectedRequeue.Seconds(), result.RequeueAfter.Seconds(), 5)
+			assertEvents(t, recorder, tt.expectedEvents...)
 		})
 	}
 }
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// defaultRateLimiterPeriod is the refill period of rate limiters that do not
+// set one
+const defaultRateLimiterPeriod = time.Second
+
+// RateLimiterReconciler reconciles a RateLimiter object
+type RateLimiterReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=ratelimiters,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=ratelimiters/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=ratelimiters/finalizers,verbs=update
+
+func (r *RateLimiterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("RateLimiter", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "RateLimiter", req)
+
+	var rl syncv1.RateLimiter
+	if err := r.Get(ctx, req.NamespacedName, &rl); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch RateLimiter")
+		return ctrl.Result{}, err
+	}
+
+	// Check TTL expiration
+	if rl.Spec.TTL != nil {
+		expirationTime := rl.CreationTimestamp.Add(rl.Spec.TTL.Duration)
+		if time.Now().After(expirationTime) {
+			if err := r.Delete(ctx, &rl); err != nil {
+				log.Error(err, "unable to delete expired RateLimiter")
+				return ctrl.Result{RequeueAfter: time.Second}, err
+			}
+			log.Info("Deleted expired RateLimiter", "name", rl.Name)
+			recordNormal(r.Recorder, &rl, ReasonRateLimiterExpired, "Deleted after TTL of %s", rl.Spec.TTL.Duration)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	previous := rl.Status.DeepCopy()
+	nextToken := refillTokens(&rl, time.Now())
+
+	generationChanged := observeGeneration(&rl.Status.ObservedGeneration, &rl)
+	if rl.Status.AvailableTokens != previous.AvailableTokens || rl.Status.Phase != previous.Phase ||
+		!rl.Status.LastRefillTime.Equal(previous.LastRefillTime) || generationChanged {
+		if err := r.Status().Update(ctx, &rl); err != nil {
+			if errors.IsConflict(err) {
+				// A caller took tokens in the meantime; refill from its update
+				return ctrl.Result{Requeue: true}, nil
+			}
+			log.Error(err, "unable to update RateLimiter status")
+			return ctrl.Result{RequeueAfter: time.Second}, err
+		}
+	}
+
+	logPhase(log, previous.Phase, rl.Status.Phase,
+		fmt.Sprintf("%d of %d tokens available", rl.Status.AvailableTokens, rateLimiterBurst(&rl)))
+
+	// Come back when the next token is due. A full bucket earns nothing, so it
+	// waits for the update of the next caller to take a token.
+	requeueAfter := nextToken
+	if rl.Spec.TTL != nil {
+		untilExpiry := time.Until(rl.CreationTimestamp.Add(rl.Spec.TTL.Duration))
+		if requeueAfter == 0 || untilExpiry < requeueAfter {
+			requeueAfter = untilExpiry
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// refillTokens adds the tokens earned since the last refill to rl's bucket,
+// capped at its burst, and returns how long until the next token is due, or
+// zero when the bucket is full. LastRefillTime only advances by the tokens
+// actually added so the remainder of a partial interval carries over.
+func refillTokens(rl *syncv1.RateLimiter, now time.Time) time.Duration {
+	burst := rateLimiterBurst(rl)
+	interval := tokenInterval(rl)
+
+	switch {
+	case rl.Status.LastRefillTime == nil:
+		// A new rate limiter starts with a full bucket
+		rl.Status.AvailableTokens = burst
+		refilledAt := metav1.NewMicroTime(now)
+		rl.Status.LastRefillTime = &refilledAt
+	case rl.Status.AvailableTokens >= burst:
+		// Burst may have been lowered since the bucket filled up
+		rl.Status.AvailableTokens = burst
+	default:
+		elapsed := now.Sub(rl.Status.LastRefillTime.Time)
+		if earned := int64(elapsed / interval); earned > 0 {
+			if earned >= int64(burst-rl.Status.AvailableTokens) {
+				rl.Status.AvailableTokens = burst
+				refilledAt := metav1.NewMicroTime(now)
+				rl.Status.LastRefillTime = &refilledAt
+			} else {
+				rl.Status.AvailableTokens += int32(earned)
+				refilledAt := metav1.NewMicroTime(rl.Status.LastRefillTime.Add(time.Duration(earned) * interval))
+				rl.Status.LastRefillTime = &refilledAt
+			}
+		}
+	}
+
+	if rl.Status.AvailableTokens > 0 {
+		rl.Status.Phase = syncv1.RateLimiterPhaseAvailable
+	} else {
+		rl.Status.Phase = syncv1.RateLimiterPhaseExhausted
+	}
+
+	if rl.Status.AvailableTokens >= burst {
+		return 0
+	}
+	next := interval - now.Sub(rl.Status.LastRefillTime.Time)
+	if next <= 0 {
+		next = time.Millisecond
+	}
+	return next
+}
+
+// rateLimiterBurst returns the bucket size, which defaults to the rate
+func rateLimiterBurst(rl *syncv1.RateLimiter) int32 {
+	if rl.Spec.Burst > 0 {
+		return rl.Spec.Burst
+	}
+	if rl.Spec.Rate > 0 {
+		return rl.Spec.Rate
+	}
+	return 1
+}
+
+// tokenInterval returns how often one token is added to the bucket
+func tokenInterval(rl *syncv1.RateLimiter) time.Duration {
+	period := defaultRateLimiterPeriod
+	if rl.Spec.Period != nil && rl.Spec.Period.Duration > 0 {
+		period = rl.Spec.Period.Duration
+	}
+	rate := int64(rl.Spec.Rate)
+	if rate <= 0 {
+		rate = 1
+	}
+	if interval := period / time.Duration(rate); interval > 0 {
+		return interval
+	}
+	return time.Nanosecond
+}
+
+func (r *RateLimiterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("ratelimiter-controller")
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&syncv1.RateLimiter{}).
+		Complete(trackHealth("ratelimiter", r))
+}
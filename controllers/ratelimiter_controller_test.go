@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+func newTestRateLimiter(rate, burst, available int32, lastRefill *time.Time) *syncv1.RateLimiter {
+	rl := &syncv1.RateLimiter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ratelimiter",
+			Namespace: "default",
+		},
+		Spec: syncv1.RateLimiterSpec{
+			Rate:  rate,
+			Burst: burst,
+		},
+		Status: syncv1.RateLimiterStatus{
+			AvailableTokens: available,
+		},
+	}
+	if lastRefill != nil {
+		refilledAt := metav1.NewMicroTime(*lastRefill)
+		rl.Status.LastRefillTime = &refilledAt
+	}
+	return rl
+}
+
+func TestRefillTokens(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name           string
+		rateLimiter    *syncv1.RateLimiter
+		wantTokens     int32
+		wantLastRefill time.Time
+		wantNextToken  time.Duration
+		wantPhase      syncv1.RateLimiterPhase
+	}{
+		{
+			name:           "new rate limiter starts full",
+			rateLimiter:    newTestRateLimiter(2, 10, 0, nil),
+			wantTokens:     10,
+			wantLastRefill: now,
+			wantNextToken:  0,
+			wantPhase:      syncv1.RateLimiterPhaseAvailable,
+		},
+		{
+			name:           "burst defaults to rate",
+			rateLimiter:    newTestRateLimiter(5, 0, 0, nil),
+			wantTokens:     5,
+			wantLastRefill: now,
+			wantNextToken:  0,
+			wantPhase:      syncv1.RateLimiterPhaseAvailable,
+		},
+		{
+			name:        "adds whole tokens and carries the remainder",
+			rateLimiter: newTestRateLimiter(2, 10, 0, at(2300*time.Millisecond)),
+			wantTokens:  4,
+			// Four tokens at 500ms each account for 2s of the 2.3s elapsed
+			wantLastRefill: now.Add(-300 * time.Millisecond),
+			wantNextToken:  200 * time.Millisecond,
+			wantPhase:      syncv1.RateLimiterPhaseAvailable,
+		},
+		{
+			name:           "no token before a full interval",
+			rateLimiter:    newTestRateLimiter(1, 5, 0, at(400*time.Millisecond)),
+			wantTokens:     0,
+			wantLastRefill: now.Add(-400 * time.Millisecond),
+			wantNextToken:  600 * time.Millisecond,
+			wantPhase:      syncv1.RateLimiterPhaseExhausted,
+		},
+		{
+			name:           "caps at burst",
+			rateLimiter:    newTestRateLimiter(10, 20, 3, at(time.Hour)),
+			wantTokens:     20,
+			wantLastRefill: now,
+			wantNextToken:  0,
+			wantPhase:      syncv1.RateLimiterPhaseAvailable,
+		},
+		{
+			name:           "lowered burst trims a full bucket",
+			rateLimiter:    newTestRateLimiter(10, 5, 15, at(time.Minute)),
+			wantTokens:     5,
+			wantLastRefill: now.Add(-time.Minute),
+			wantNextToken:  0,
+			wantPhase:      syncv1.RateLimiterPhaseAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := refillTokens(tt.rateLimiter, now)
+
+			assert.Equal(t, tt.wantTokens, tt.rateLimiter.Status.AvailableTokens)
+			require.NotNil(t, tt.rateLimiter.Status.LastRefillTime)
+			assert.True(t, tt.wantLastRefill.Equal(tt.rateLimiter.Status.LastRefillTime.Time),
+				"last refill %v, want %v", tt.rateLimiter.Status.LastRefillTime.Time, tt.wantLastRefill)
+			assert.Equal(t, tt.wantNextToken, next)
+			assert.Equal(t, tt.wantPhase, tt.rateLimiter.Status.Phase)
+		})
+	}
+}
+
+func TestTokenInterval(t *testing.T) {
+	rl := newTestRateLimiter(4, 0, 0, nil)
+	assert.Equal(t, 250*time.Millisecond, tokenInterval(rl))
+
+	rl.Spec.Period = &metav1.Duration{Duration: time.Minute}
+	assert.Equal(t, 15*time.Second, tokenInterval(rl))
+}
+
+func TestRateLimiterReconciler_RefillsAndRequeuesForNextToken(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	lastRefill := time.Now().Add(-1500 * time.Millisecond)
+	rl := newTestRateLimiter(1, 3, 0, &lastRefill)
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rl).
+		WithStatusSubresource(&syncv1.RateLimiter{}).
+		Build()
+
+	reconciler := &RateLimiterReconciler{Client: client, Scheme: scheme}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: rl.Name, Namespace: rl.Namespace},
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.RateLimiter
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, int32(1), updated.Status.AvailableTokens)
+	assert.Equal(t, syncv1.RateLimiterPhaseAvailable, updated.Status.Phase)
+	assert.WithinDuration(t, lastRefill.Add(time.Second), updated.Status.LastRefillTime.Time, time.Millisecond)
+
+	// The second token is due a second after the first
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+	assert.LessOrEqual(t, result.RequeueAfter, 500*time.Millisecond)
+}
+
+func TestRateLimiterReconciler_FullBucketDoesNotRequeue(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	rl := newTestRateLimiter(5, 10, 0, nil)
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rl).
+		WithStatusSubresource(&syncv1.RateLimiter{}).
+		Build()
+
+	reconciler := &RateLimiterReconciler{Client: client, Scheme: scheme}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: rl.Name, Namespace: rl.Namespace},
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	var updated syncv1.RateLimiter
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, int32(10), updated.Status.AvailableTokens)
+	assert.NotNil(t, updated.Status.LastRefillTime)
+}
+
+func TestRateLimiterReconciler_TTLExpired(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	rl := newTestRateLimiter(5, 10, 0, nil)
+	rl.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	rl.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rl).
+		WithStatusSubresource(&syncv1.RateLimiter{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &RateLimiterReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: rl.Name, Namespace: rl.Namespace},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.RateLimiter
+	err = client.Get(context.Background(), req.NamespacedName, &updated)
+	assert.True(t, apierrors.IsNotFound(err))
+	assertEvents(t, recorder, "Normal RateLimiterExpired Deleted after TTL of 1h0m0s")
+}
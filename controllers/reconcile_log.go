@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// startReconcile returns the logger for one reconcile of req and a ctx that
+// carries it, so helpers that call log.FromContext log with the same fields.
+// Under a manager the logger in ctx already has the name, namespace and
+// reconcileID of the request; when Reconcile is called directly they are
+// added here, with a fresh reconcileID, so every reconcile can be followed by
+// the same fields either way.
+func startReconcile(ctx context.Context, kind string, req ctrl.Request) (context.Context, logr.Logger) {
+	logger := log.FromContext(ctx).WithValues("kind", kind)
+	if controller.ReconcileIDFromContext(ctx) == "" {
+		logger = logger.WithValues("name", req.Name, "namespace", req.Namespace, "reconcileID", uuid.NewString())
+	}
+
+	logger.V(1).Info("Reconciling")
+	return log.IntoContext(ctx, logger), logger
+}
+
+// logPhase logs the phase a reconcile decided on and why. Moving from one
+// phase to another is logged at info level, and a reconcile that leaves the
+// phase as it was only at debug level.
+func logPhase[P ~string](logger logr.Logger, from, to P, reason string) {
+	if from != to {
+		logger.Info("Phase changed", "from", from, "to", to, "reason", reason)
+		return
+	}
+	logger.V(1).Info("Phase unchanged", "phase", to, "reason", reason)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// testLogs returns a context whose logger records every entry, debug ones
+// included, and the entries it has recorded so far
+func testLogs(t *testing.T) (context.Context, func() []map[string]any) {
+	var lines []string
+	level := "v"
+	logger := funcr.NewJSON(func(obj string) { lines = append(lines, obj) },
+		funcr.Options{Verbosity: 1, LogInfoLevel: &level})
+
+	return log.IntoContext(context.Background(), logger), func() []map[string]any {
+		entries := make([]map[string]any, len(lines))
+		for i, line := range lines {
+			require.NoError(t, json.Unmarshal([]byte(line), &entries[i]))
+		}
+		return entries
+	}
+}
+
+// withMessage returns the entries logged with msg
+func withMessage(entries []map[string]any, msg string) []map[string]any {
+	var matching []map[string]any
+	for _, entry := range entries {
+		if entry["msg"] == msg {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+func TestStartReconcile(t *testing.T) {
+	ctx, entries := testLogs(t)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "deploy", Namespace: "jobs"}}
+
+	reconcileCtx, _ := startReconcile(ctx, "Gate", req)
+	log.FromContext(reconcileCtx).Info("checking conditions")
+	startReconcile(ctx, "Gate", req)
+
+	logged := entries()
+	require.Len(t, logged, 3)
+	assert.Equal(t, "Reconciling", logged[0]["msg"])
+	assert.Equal(t, float64(1), logged[0]["v"], "entry is logged at debug level")
+
+	// Helpers that take the logger from the context log with the same fields
+	helper := logged[1]
+	assert.Equal(t, "Gate", helper["kind"])
+	assert.Equal(t, "deploy", helper["name"])
+	assert.Equal(t, "jobs", helper["namespace"])
+	assert.NotEmpty(t, helper["reconcileID"])
+	assert.Equal(t, logged[0]["reconcileID"], helper["reconcileID"])
+
+	assert.NotEqual(t, helper["reconcileID"], logged[2]["reconcileID"], "each reconcile gets its own ID")
+}
+
+func TestLogPhase(t *testing.T) {
+	ctx, entries := testLogs(t)
+	logger := log.FromContext(ctx)
+
+	logPhase(logger, syncv1.GatePhaseWaiting, syncv1.GatePhaseOpen, "2 of 2 conditions met")
+	logPhase(logger, syncv1.GatePhaseOpen, syncv1.GatePhaseOpen, "2 of 2 conditions met")
+
+	logged := entries()
+	require.Len(t, logged, 2)
+	assert.Equal(t, "Phase changed", logged[0]["msg"])
+	assert.Equal(t, float64(0), logged[0]["v"])
+	assert.Equal(t, "Waiting", logged[0]["from"])
+	assert.Equal(t, "Open", logged[0]["to"])
+	assert.Equal(t, "2 of 2 conditions met", logged[0]["reason"])
+
+	assert.Equal(t, "Phase unchanged", logged[1]["msg"])
+	assert.Equal(t, float64(1), logged[1]["v"])
+	assert.Equal(t, "Open", logged[1]["phase"])
+}
+
+func TestWaitGroupReconciler_LogsOnlyActualTransitions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"},
+		Status:     syncv1.WaitGroupStatus{Counter: 2, Phase: syncv1.WaitGroupPhaseWaiting},
+	}
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(wg).
+		WithStatusSubresource(&syncv1.WaitGroup{}).
+		Build()
+	reconciler := &WaitGroupReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: wg.Name, Namespace: wg.Namespace}}
+
+	ctx, entries := testLogs(t)
+	reconcile := func() {
+		t.Helper()
+		_, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+
+	// Still waiting, and still waiting on the next pass
+	reconcile()
+	reconcile()
+	assert.Empty(t, withMessage(entries(), "Phase changed"))
+	unchanged := withMessage(entries(), "Phase unchanged")
+	require.Len(t, unchanged, 2)
+	assert.Equal(t, "counter at 2", unchanged[0]["reason"])
+
+	var updated syncv1.WaitGroup
+	require.NoError(t, client.Get(ctx, req.NamespacedName, &updated))
+	updated.Status.Counter = 0
+	require.NoError(t, client.Status().Update(ctx, &updated))
+
+	reconcile()
+	reconcile()
+	changed := withMessage(entries(), "Phase changed")
+	require.Len(t, changed, 1, "only the reconcile that moved the phase logs a transition")
+	assert.Equal(t, "Waiting", changed[0]["from"])
+	assert.Equal(t, "Done", changed[0]["to"])
+	assert.Equal(t, "counter reached zero", changed[0]["reason"])
+	assert.Equal(t, "WaitGroup", changed[0]["kind"])
+	assert.Equal(t, "test-wg", changed[0]["name"])
+	assert.Len(t, withMessage(entries(), "Phase unchanged"), 3)
+}
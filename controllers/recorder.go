@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reasons for the Kubernetes Events recorded against konductor resources
+const (
+	ReasonSemaphoreFull              = "SemaphoreFull"
+	ReasonPermitGranted              = "PermitGranted"
+	ReasonPermitDenied               = "PermitDenied"
+	ReasonSemaphoreDraining          = "SemaphoreDraining"
+	ReasonBarrierOpened              = "BarrierOpened"
+	ReasonBarrierFailed              = "BarrierFailed"
+	ReasonLeaseGranted               = "LeaseGranted"
+	ReasonLeaseExpired               = "LeaseExpired"
+	ReasonLeaseMaxHoldExceeded       = "LeaseMaxHoldExceeded"
+	ReasonGateOpened                 = "GateOpened"
+	ReasonGateFailed                 = "GateFailed"
+	ReasonMutexTTLExpired            = "MutexTTLExpired"
+	ReasonRWMutexTTLExpired          = "RWMutexTTLExpired"
+	ReasonRWMutexWritePendingExpired = "RWMutexWritePendingExpired"
+	ReasonOnceExecuted               = "OnceExecuted"
+	ReasonOnceExpired                = "OnceExpired"
+	ReasonOnceReset                  = "OnceReset"
+	ReasonWaitGroupDone              = "WaitGroupDone"
+	ReasonEventExpired               = "EventExpired"
+	ReasonRateLimiterExpired         = "RateLimiterExpired"
+	ReasonCircuitBreakerHalfOpen     = "CircuitBreakerHalfOpen"
+	ReasonCircuitBreakerOpened       = "CircuitBreakerOpened"
+	ReasonCircuitBreakerExpired      = "CircuitBreakerExpired"
+)
+
+// recordEvent emits a Kubernetes Event for obj. Reconcilers built without a
+// recorder, as in most unit tests, skip recording.
+func recordEvent(recorder record.EventRecorder, obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// recordNormal emits a Normal Kubernetes Event for obj
+func recordNormal(recorder record.EventRecorder, obj runtime.Object, reason, messageFmt string, args ...interface{}) {
+	recordEvent(recorder, obj, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+// recordWarning emits a Warning Kubernetes Event for obj
+func recordWarning(recorder record.EventRecorder, obj runtime.Object, reason, messageFmt string, args ...interface{}) {
+	recordEvent(recorder, obj, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
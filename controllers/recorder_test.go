@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/record"
+)
+
+// assertEvents drains recorder and checks that exactly the expected events,
+// formatted as "Type Reason Message", were recorded in order.
+func assertEvents(t *testing.T, recorder *record.FakeRecorder, expected ...string) {
+	t.Helper()
+
+	var recorded []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			recorded = append(recorded, event)
+			continue
+		default:
+		}
+		break
+	}
+
+	if len(expected) == 0 {
+		assert.Empty(t, recorded)
+		return
+	}
+	assert.Equal(t, expected, recorded)
+}
+
+func TestRecordEvent_NilRecorder(t *testing.T) {
+	assert.NotPanics(t, func() {
+		recordNormal(nil, nil, ReasonGateOpened, "All %d conditions met", 1)
+	})
+}
@@ -2,13 +2,16 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
@@ -16,15 +19,17 @@ import (
 // RWMutexReconciler reconciles a RWMutex object
 type RWMutexReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=rwmutexes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=rwmutexes/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=rwmutexes/finalizers,verbs=update
 
-func (r *RWMutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
+func (r *RWMutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("RWMutex", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "RWMutex", req)
 
 	var rwmutex syncv1.RWMutex
 	if err := r.Get(ctx, req.NamespacedName, &rwmutex); err != nil {
@@ -35,10 +40,17 @@ func (r *RWMutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	now := time.Now()
+	oldPhase := rwmutex.Status.Phase
 	updated := false
+	expiredMessage := ""
 
 	// Check TTL expiration
-	if rwmutex.Status.ExpiresAt != nil && rwmutex.Status.ExpiresAt.Time.Before(now) {
+	if hasExpired(rwmutex.Status.ExpiresAt, now) {
+		if rwmutex.Status.WriteHolder != "" {
+			expiredMessage = fmt.Sprintf("Write lock held by %s expired", rwmutex.Status.WriteHolder)
+		} else if len(rwmutex.Status.ReadHolders) > 0 {
+			expiredMessage = fmt.Sprintf("Read locks held by %s expired", strings.Join(rwmutex.Status.ReadHolders, ", "))
+		}
 		rwmutex.Status.Phase = syncv1.RWMutexPhaseUnlocked
 		rwmutex.Status.WriteHolder = ""
 		rwmutex.Status.ReadHolders = nil
@@ -47,6 +59,24 @@ func (r *RWMutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		updated = true
 	}
 
+	// A writer renews its pending registration while it waits, so one that
+	// has not been renewed in time belongs to a writer that went away and
+	// would otherwise keep every reader and writer out
+	pendingMessage := ""
+	if rwmutex.Status.WritePending != "" {
+		if rwmutex.Status.WritePendingSince == nil {
+			// Registered by a client that does not renew; time it from now
+			since := metav1.NewTime(now)
+			rwmutex.Status.WritePendingSince = &since
+			updated = true
+		} else if hasExpired(writePendingDeadline(&rwmutex), now) {
+			pendingMessage = fmt.Sprintf("Pending write by %s expired", rwmutex.Status.WritePending)
+			rwmutex.Status.WritePending = ""
+			rwmutex.Status.WritePendingSince = nil
+			updated = true
+		}
+	}
+
 	// Update phase based on holders
 	if rwmutex.Status.WriteHolder == "" && len(rwmutex.Status.ReadHolders) == 0 {
 		if rwmutex.Status.Phase != syncv1.RWMutexPhaseUnlocked {
@@ -65,23 +95,58 @@ func (r *RWMutexReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
+	if observeGeneration(&rwmutex.Status.ObservedGeneration, &rwmutex) {
+		updated = true
+	}
+
 	if updated {
 		if err := r.Status().Update(ctx, &rwmutex); err != nil {
 			log.Error(err, "unable to update RWMutex status")
 			return ctrl.Result{}, err
 		}
+		if expiredMessage != "" {
+			recordWarning(r.Recorder, &rwmutex, ReasonRWMutexTTLExpired, "%s", expiredMessage)
+		}
+		if pendingMessage != "" {
+			recordWarning(r.Recorder, &rwmutex, ReasonRWMutexWritePendingExpired, "%s", pendingMessage)
+		}
+	}
+
+	reason := "no holders"
+	switch {
+	case expiredMessage != "":
+		reason = expiredMessage
+	case rwmutex.Status.WriteHolder != "":
+		reason = fmt.Sprintf("write lock held by %s", rwmutex.Status.WriteHolder)
+	case len(rwmutex.Status.ReadHolders) > 0:
+		reason = fmt.Sprintf("read lock held by %d readers", len(rwmutex.Status.ReadHolders))
 	}
+	logPhase(log, oldPhase, rwmutex.Status.Phase, reason)
 
-	// Requeue if TTL is set and not expired
-	if rwmutex.Status.ExpiresAt != nil && rwmutex.Status.ExpiresAt.Time.After(now) {
-		return ctrl.Result{RequeueAfter: time.Until(rwmutex.Status.ExpiresAt.Time)}, nil
+	// Readers share one expiry, refreshed by each new reader, so waking at
+	// it frees every holder together
+	expiresAt := rwmutex.Status.ExpiresAt
+	if pending := writePendingDeadline(&rwmutex); pending != nil && (expiresAt == nil || pending.Before(expiresAt)) {
+		expiresAt = pending
 	}
+	return requeueAtExpiry(expiresAt, now), nil
+}
 
-	return ctrl.Result{}, nil
+// writePendingDeadline is when the pending writer registration on rwmutex
+// lapses unless renewed, or nil if no writer is pending
+func writePendingDeadline(rwmutex *syncv1.RWMutex) *metav1.Time {
+	if rwmutex.Status.WritePending == "" || rwmutex.Status.WritePendingSince == nil {
+		return nil
+	}
+	deadline := metav1.NewTime(rwmutex.Status.WritePendingSince.Add(syncv1.RWMutexWritePendingTimeout))
+	return &deadline
 }
 
 func (r *RWMutexReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("rwmutex-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.RWMutex{}).
-		Complete(r)
+		Complete(trackHealth("rwmutex", r))
 }
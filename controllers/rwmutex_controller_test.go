@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -125,9 +126,11 @@ func TestRWMutexReconciler_Expiration(t *testing.T) {
 		WithStatusSubresource(&syncv1.RWMutex{}).
 		Build()
 
+	recorder := record.NewFakeRecorder(10)
 	reconciler := &RWMutexReconciler{
-		Client: client,
-		Scheme: scheme,
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
 	}
 
 	req := ctrl.Request{
@@ -148,6 +151,7 @@ func TestRWMutexReconciler_Expiration(t *testing.T) {
 	assert.Equal(t, "", updated.Status.WriteHolder)
 	assert.Nil(t, updated.Status.ReadHolders)
 	assert.Nil(t, updated.Status.ExpiresAt)
+	assertEvents(t, recorder, "Warning RWMutexTTLExpired Write lock held by writer-1 expired")
 }
 
 func TestRWMutexReconciler_RequeueWithTTL(t *testing.T) {
@@ -192,3 +196,142 @@ func TestRWMutexReconciler_RequeueWithTTL(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, result.RequeueAfter > 0)
 }
+
+func TestRWMutexReconciler_UnlocksWhenRequeuedAtExpiry(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	// Stored timestamps have whole-second precision, so expire on a second
+	// boundary between one and two seconds away
+	expiresAt := metav1.NewTime(time.Now().Truncate(time.Second).Add(2 * time.Second))
+	rwmutex := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-rwmutex",
+			Namespace: "default",
+		},
+		Spec: syncv1.RWMutexSpec{
+			TTL: &metav1.Duration{Duration: 2 * time.Second},
+		},
+		Status: syncv1.RWMutexStatus{
+			Phase:       syncv1.RWMutexPhaseReadLocked,
+			ReadHolders: []string{"reader-1", "reader-2"},
+			ExpiresAt:   &expiresAt,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rwmutex).
+		WithStatusSubresource(&syncv1.RWMutex{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &RWMutexReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: rwmutex.Name, Namespace: rwmutex.Namespace}}
+
+	untilExpiry := time.Until(expiresAt.Time)
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, time.Until(expiresAt.Time), "requeued just past the expiry")
+	assert.LessOrEqual(t, result.RequeueAfter, untilExpiry+expiryRequeueMargin)
+
+	time.Sleep(result.RequeueAfter)
+	result, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	var current syncv1.RWMutex
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &current))
+	assert.Equal(t, syncv1.RWMutexPhaseUnlocked, current.Status.Phase)
+	assert.Empty(t, current.Status.ReadHolders)
+	assert.Nil(t, current.Status.ExpiresAt)
+	assertEvents(t, recorder, "Warning RWMutexTTLExpired Read locks held by reader-1, reader-2 expired")
+}
+
+func TestRWMutexReconciler_ClearsAbandonedWritePending(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	// The writer registered, then died without withdrawing
+	abandoned := metav1.NewTime(time.Now().Add(-syncv1.RWMutexWritePendingTimeout - time.Minute))
+	rwmutex := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-rwmutex", Namespace: "default"},
+		Status: syncv1.RWMutexStatus{
+			Phase:             syncv1.RWMutexPhaseReadLocked,
+			ReadHolders:       []string{"reader-1"},
+			WritePending:      "writer-1",
+			WritePendingSince: &abandoned,
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rwmutex).
+		WithStatusSubresource(&syncv1.RWMutex{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &RWMutexReconciler{Client: client, Scheme: scheme, Recorder: recorder}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: rwmutex.Name, Namespace: rwmutex.Namespace}}
+
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	var current syncv1.RWMutex
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &current))
+	assert.Empty(t, current.Status.WritePending)
+	assert.Nil(t, current.Status.WritePendingSince)
+	assert.Equal(t, []string{"reader-1"}, current.Status.ReadHolders, "the readers keep their locks")
+	assertEvents(t, recorder, "Warning RWMutexWritePendingExpired Pending write by writer-1 expired")
+
+	// New readers are admitted again
+	current.Status.ReadHolders = append(current.Status.ReadHolders, "reader-2")
+	require.NoError(t, client.Status().Update(context.Background(), &current))
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &current))
+	assert.Equal(t, syncv1.RWMutexPhaseReadLocked, current.Status.Phase)
+	assert.Equal(t, []string{"reader-1", "reader-2"}, current.Status.ReadHolders)
+}
+
+func TestRWMutexReconciler_RequeuesAtWritePendingTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	rwmutex := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-rwmutex", Namespace: "default"},
+		Status: syncv1.RWMutexStatus{
+			Phase:        syncv1.RWMutexPhaseReadLocked,
+			ReadHolders:  []string{"reader-1"},
+			WritePending: "writer-1",
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(rwmutex).
+		WithStatusSubresource(&syncv1.RWMutex{}).
+		Build()
+
+	reconciler := &RWMutexReconciler{Client: client, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: rwmutex.Name, Namespace: rwmutex.Namespace}}
+
+	// A registration without a time is timed from when the controller sees it
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.InDelta(t, syncv1.RWMutexWritePendingTimeout, result.RequeueAfter, float64(2*time.Second))
+
+	var current syncv1.RWMutex
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &current))
+	assert.Equal(t, "writer-1", current.Status.WritePending, "a fresh registration is kept")
+	require.NotNil(t, current.Status.WritePendingSince)
+
+	// A registration due sooner than the lock's own expiry wins
+	expiresAt := metav1.NewTime(time.Now().Add(time.Hour))
+	current.Status.ExpiresAt = &expiresAt
+	require.NoError(t, client.Status().Update(context.Background(), &current))
+	result, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, result.RequeueAfter, syncv1.RWMutexWritePendingTimeout+expiryRequeueMargin)
+}
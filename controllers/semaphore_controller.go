@@ -2,13 +2,21 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
@@ -16,44 +24,49 @@ import (
 // SemaphoreReconciler reconciles a Semaphore object
 type SemaphoreReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=semaphores,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=semaphores/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=semaphores/finalizers,verbs=update
-//+kubebuilder:rbac:groups=sync.konductor.io,resources=permits,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=permits,verbs=get;list;watch;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=permits/status,verbs=get;update;patch
 
-func (r *SemaphoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
-
-	log.Info("Reconciling Semaphore", "name", req.Name, "namespace", req.Namespace)
+func (r *SemaphoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("Semaphore", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "Semaphore", req)
 
 	var semaphore syncv1.Semaphore
 	if err := r.Get(ctx, req.NamespacedName, &semaphore); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Semaphore not found, likely deleted", "name", req.Name)
+			semaphorePermitsInUse.DeleteLabelValues(req.Namespace, req.Name)
+			semaphorePermitsAvailable.DeleteLabelValues(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "unable to fetch Semaphore")
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Found Semaphore", "name", semaphore.Name, "permits", semaphore.Spec.Permits, "currentAvailable", semaphore.Status.Available)
+	log.V(1).Info("Found Semaphore", "permits", semaphore.Spec.Permits, "currentAvailable", semaphore.Status.Available)
 
 	if semaphore.Status.Phase == "" {
 		semaphore.Status.Available = semaphore.Spec.Permits
 		semaphore.Status.InUse = 0
 		semaphore.Status.Phase = syncv1.SemaphorePhaseReady
+		observeGeneration(&semaphore.Status.ObservedGeneration, &semaphore)
 		if err := r.Status().Update(ctx, &semaphore); err != nil {
 			log.Error(err, "unable to initialize Semaphore status")
 			return ctrl.Result{}, err
 		}
-		log.Info("Initialized Semaphore status", "name", semaphore.Name)
+		logPhase(log, "", semaphore.Status.Phase, "initialized")
 		return ctrl.Result{}, nil
 	}
 
+	original := semaphore.Status.DeepCopy()
+
 	permits := &syncv1.PermitList{}
 	if err := r.List(ctx, permits, client.InNamespace(req.Namespace),
 		client.MatchingLabels{"semaphore": semaphore.Name}); err != nil {
@@ -61,50 +74,84 @@ func (r *SemaphoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
-	log.Info("Found permits", "count", len(permits.Items), "semaphore", semaphore.Name)
+	log.V(1).Info("Found permits", "count", len(permits.Items))
 
-	validPermits := 0
 	now := time.Now()
-	for i := range permits.Items {
-		permit := &permits.Items[i]
-		isValid := permit.Status.ExpiresAt == nil || permit.Status.ExpiresAt.Time.After(now)
-		if isValid {
-			if permit.Status.Phase != syncv1.PermitPhaseGranted {
-				permit.Status.Phase = syncv1.PermitPhaseGranted
-				if err := r.Status().Update(ctx, permit); err != nil {
-					log.Error(err, "failed to update permit status", "permit", permit.Name)
-					return ctrl.Result{}, err
-				}
-			}
-			validPermits++
+	live, err := r.deleteExpiredPermits(ctx, permits.Items, now)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	permits.Items = live
+
+	// inUse is the total weight of the granted permits
+	inUse := 0
+	pendingPermits := 0
+	if semaphore.Spec.Drain {
+		granted, pending, err := r.holdPending(ctx, &semaphore, permits.Items, now)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
+		inUse = granted
+		pendingPermits = pending
+	} else if semaphore.Spec.Fair || needsQueue(permits.Items) {
+		granted, pending, err := r.grantQueued(ctx, &semaphore, permits.Items, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		inUse = granted
+		pendingPermits = pending
+	} else {
+		granted, err := r.admitPermits(ctx, &semaphore, permits.Items, now)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		inUse = granted
 	}
 
 	oldInUse := semaphore.Status.InUse
 	oldAvailable := semaphore.Status.Available
 	oldPhase := semaphore.Status.Phase
 
-	semaphore.Status.InUse = int32(validPermits)
-	semaphore.Status.Available = semaphore.Spec.Permits - int32(validPermits)
+	semaphore.Status.InUse = int32(inUse)
+	semaphore.Status.Pending = int32(pendingPermits)
+	semaphore.Status.Available = semaphore.Spec.Permits - int32(inUse)
+
+	recordSemaphoreUsage(&semaphore, oldInUse, now)
 
-	if semaphore.Status.Available > 0 {
+	switch {
+	case semaphore.Spec.Drain:
+		semaphore.Status.Phase = syncv1.SemaphorePhaseDraining
+	case semaphore.Status.Available > 0:
 		semaphore.Status.Phase = syncv1.SemaphorePhaseReady
-	} else {
+	default:
 		semaphore.Status.Phase = syncv1.SemaphorePhaseFull
 	}
 
-	log.Info("Status update", "semaphore", semaphore.Name,
-		"validPermits", validPermits,
+	log.V(1).Info("Status update",
 		"oldInUse", oldInUse, "newInUse", semaphore.Status.InUse,
 		"oldAvailable", oldAvailable, "newAvailable", semaphore.Status.Available,
 		"oldPhase", oldPhase, "newPhase", semaphore.Status.Phase)
 
-	if err := r.Status().Update(ctx, &semaphore); err != nil {
-		log.Error(err, "unable to update Semaphore status")
-		return ctrl.Result{}, err
+	observeGeneration(&semaphore.Status.ObservedGeneration, &semaphore)
+	recordLongestWait(&semaphore, original, permits.Items, now)
+	if !equality.Semantic.DeepEqual(original, &semaphore.Status) {
+		if err := r.Status().Update(ctx, &semaphore); err != nil {
+			log.Error(err, "unable to update Semaphore status")
+			return ctrl.Result{}, err
+		}
+	}
+	logPhase(log, oldPhase, semaphore.Status.Phase,
+		fmt.Sprintf("%d of %d permits in use", semaphore.Status.InUse, semaphore.Spec.Permits))
+
+	if oldPhase != syncv1.SemaphorePhaseFull && semaphore.Status.Phase == syncv1.SemaphorePhaseFull {
+		recordNormal(r.Recorder, &semaphore, ReasonSemaphoreFull, "All %d permits are in use", semaphore.Spec.Permits)
+	}
+	if oldPhase != syncv1.SemaphorePhaseDraining && semaphore.Status.Phase == syncv1.SemaphorePhaseDraining {
+		recordNormal(r.Recorder, &semaphore, ReasonSemaphoreDraining, "Draining with %d permits still held", semaphore.Status.InUse)
 	}
 
-	log.Info("Successfully updated Semaphore status", "name", semaphore.Name)
+	semaphorePermitsInUse.WithLabelValues(semaphore.Namespace, semaphore.Name).Set(float64(semaphore.Status.InUse))
+	semaphorePermitsAvailable.WithLabelValues(semaphore.Namespace, semaphore.Name).Set(float64(semaphore.Status.Available))
 
 	// Use adaptive requeue interval based on activity
 	requeueAfter := time.Minute
@@ -113,12 +160,323 @@ func (r *SemaphoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		requeueAfter = 10 * time.Second
 	}
 
+	// Come back just after the next permit expires so its slot frees promptly
+	if next := nextPermitExpiry(permits.Items); next != nil {
+		if untilExpiry := time.Until(*next) + permitExpiryGrace; untilExpiry < requeueAfter {
+			requeueAfter = untilExpiry
+		}
+	}
+
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
+// recordSemaphoreUsage keeps the high-water mark of permits in use and stamps
+// LastFullAt each time usage reaches the permit count, so operators can tell
+// whether the semaphore needs resizing
+func recordSemaphoreUsage(semaphore *syncv1.Semaphore, oldInUse int32, now time.Time) {
+	inUse := semaphore.Status.InUse
+	if inUse > semaphore.Status.MaxInUse {
+		semaphore.Status.MaxInUse = inUse
+	}
+
+	permits := semaphore.Spec.Permits
+	if inUse >= permits && (oldInUse < permits || semaphore.Status.LastFullAt == nil) {
+		fullAt := metav1.NewTime(now)
+		semaphore.Status.LastFullAt = &fullAt
+	}
+}
+
+// longestWaitResolution is how far LongestWait may drift before it is written
+// on its own. The wait grows on every reconcile, and each status update
+// triggers another, so always writing it would reconcile without end.
+const longestWaitResolution = 10 * time.Second
+
+// recordLongestWait sets LongestWait to how long the oldest pending permit
+// has been queued, or clears it when none is. The new value is only kept if
+// some other part of the status changed too, or it drifted by at least
+// longestWaitResolution from original.
+func recordLongestWait(semaphore *syncv1.Semaphore, original *syncv1.SemaphoreStatus, permits []syncv1.Permit, now time.Time) {
+	var longest *metav1.Duration
+	for i := range permits {
+		permit := &permits[i]
+		if permit.Status.Phase != syncv1.PermitPhasePending {
+			continue
+		}
+		wait := now.Sub(permit.CreationTimestamp.Time).Truncate(time.Second)
+		if longest == nil || wait > longest.Duration {
+			longest = &metav1.Duration{Duration: wait}
+		}
+	}
+
+	semaphore.Status.LongestWait = original.LongestWait
+	if longest != nil && original.LongestWait != nil &&
+		equality.Semantic.DeepEqual(original, &semaphore.Status) {
+		drift := longest.Duration - original.LongestWait.Duration
+		if drift < longestWaitResolution && drift > -longestWaitResolution {
+			return
+		}
+	}
+	semaphore.Status.LongestWait = longest
+}
+
+// permitExpiryGrace is added to the next permit expiry when requeueing, so the
+// permit is past its ExpiresAt by the time the semaphore is reconciled again
+const permitExpiryGrace = 100 * time.Millisecond
+
+// nextPermitExpiry returns the soonest ExpiresAt among permits, or nil if none
+// of them expire
+func nextPermitExpiry(permits []syncv1.Permit) *time.Time {
+	var next *time.Time
+	for i := range permits {
+		expiresAt := permits[i].Status.ExpiresAt
+		if expiresAt == nil {
+			continue
+		}
+		if next == nil || expiresAt.Time.Before(*next) {
+			t := expiresAt.Time
+			next = &t
+		}
+	}
+	return next
+}
+
+// deleteExpiredPermits deletes permits whose ExpiresAt has passed so they do
+// not accumulate in the cluster, returning the permits that are still live
+func (r *SemaphoreReconciler) deleteExpiredPermits(ctx context.Context, permits []syncv1.Permit, now time.Time) ([]syncv1.Permit, error) {
+	live := permits[:0]
+	for i := range permits {
+		permit := &permits[i]
+		if permit.Status.ExpiresAt == nil || permit.Status.ExpiresAt.Time.After(now) {
+			live = append(live, *permit)
+			continue
+		}
+		if err := r.Delete(ctx, permit); client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).Error(err, "failed to delete expired permit", "permit", permit.Name)
+			return nil, err
+		}
+		log.FromContext(ctx).Info("Deleted expired permit", "permit", permit.Name, "holder", permit.Spec.Holder)
+	}
+	return live, nil
+}
+
+// needsQueue reports whether a non-fair semaphore has to queue its permits:
+// some request asked for a priority, or earlier queueing left permits Pending
+func needsQueue(permits []syncv1.Permit) bool {
+	for _, permit := range permits {
+		if permit.Status.Phase == syncv1.PermitPhaseDenied {
+			continue
+		}
+		if permit.Status.Phase == syncv1.PermitPhasePending {
+			return true
+		}
+		if permit.Spec.Priority != nil && permit.Status.Phase != syncv1.PermitPhaseGranted {
+			return true
+		}
+	}
+	return false
+}
+
+// grantQueued grants waiting permits by highest priority, then oldest first,
+// while the semaphore has capacity for their weight, leaving the rest Pending.
+// A permit too heavy for the capacity left holds up those behind it, so
+// lighter requests cannot starve it. Permits that are already granted keep
+// their slot. It returns the total weight granted and the number of pending
+// permits.
+func (r *SemaphoreReconciler) grantQueued(ctx context.Context, semaphore *syncv1.Semaphore, permits []syncv1.Permit, now time.Time) (int, int, error) {
+	granted, queue := waitingPermits(permits, now)
+
+	pending := 0
+	blocked := false
+	for _, permit := range queue {
+		phase := syncv1.PermitPhasePending
+		weight := int(permitWeight(permit))
+		if !blocked && granted+weight <= int(semaphore.Spec.Permits) {
+			phase = syncv1.PermitPhaseGranted
+			granted += weight
+		} else {
+			blocked = true
+			pending++
+		}
+		if permit.Status.Phase == phase {
+			continue
+		}
+		if err := r.setPermitPhase(ctx, semaphore, permit, phase); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return granted, pending, nil
+}
+
+// admitPermits grants waiting permits of a semaphore that does not queue, in
+// the order they were requested, while it has capacity for their weight.
+// Permits that no longer fit are Denied rather than left waiting, so an
+// acquirer that raced others for the last permits finds out and withdraws.
+// Permits that are already granted keep their slot. It returns the total
+// weight granted.
+func (r *SemaphoreReconciler) admitPermits(ctx context.Context, semaphore *syncv1.Semaphore, permits []syncv1.Permit, now time.Time) (int, error) {
+	granted, waiting := waitingPermits(permits, now)
+	for _, permit := range waiting {
+		weight := int(permitWeight(permit))
+		if granted+weight > int(semaphore.Spec.Permits) {
+			if err := r.setPermitPhase(ctx, semaphore, permit, syncv1.PermitPhaseDenied); err != nil {
+				return 0, err
+			}
+			recordWarning(r.Recorder, semaphore, ReasonPermitDenied, "Denied permit %s to %s, %d of %d permits are in use",
+				permit.Name, permit.Spec.Holder, granted, semaphore.Spec.Permits)
+			continue
+		}
+		if permit.Status.Phase != syncv1.PermitPhaseGranted {
+			if err := r.setPermitPhase(ctx, semaphore, permit, syncv1.PermitPhaseGranted); err != nil {
+				return 0, err
+			}
+		}
+		granted += weight
+	}
+	return granted, nil
+}
+
+// waitingPermits returns the total weight of the granted permits and the
+// live permits still waiting for a decision, by highest priority, then oldest
+// first. Denied permits are neither.
+func waitingPermits(permits []syncv1.Permit, now time.Time) (int, []*syncv1.Permit) {
+	granted := 0
+	var queue []*syncv1.Permit
+	for i := range permits {
+		permit := &permits[i]
+		if permit.Status.ExpiresAt != nil && !permit.Status.ExpiresAt.Time.After(now) {
+			continue
+		}
+		switch permit.Status.Phase {
+		case syncv1.PermitPhaseGranted:
+			granted += int(permitWeight(permit))
+		case syncv1.PermitPhaseDenied:
+		default:
+			queue = append(queue, permit)
+		}
+	}
+
+	// Permit names end in a nanosecond timestamp, which breaks ties between
+	// requests created within the same second
+	sort.SliceStable(queue, func(i, j int) bool {
+		if pi, pj := permitPriority(queue[i]), permitPriority(queue[j]); pi != pj {
+			return pi > pj
+		}
+		a, b := queue[i].CreationTimestamp, queue[j].CreationTimestamp
+		if !a.Equal(&b) {
+			return a.Before(&b)
+		}
+		return queue[i].Name < queue[j].Name
+	})
+
+	return granted, queue
+}
+
+// holdPending leaves the permits that are already granted in place and keeps
+// every other one Pending, so a draining semaphore grants nothing new. It
+// returns the total weight granted and the number of pending permits.
+func (r *SemaphoreReconciler) holdPending(ctx context.Context, semaphore *syncv1.Semaphore, permits []syncv1.Permit, now time.Time) (int, int, error) {
+	granted, pending := 0, 0
+	for i := range permits {
+		permit := &permits[i]
+		if permit.Status.ExpiresAt != nil && !permit.Status.ExpiresAt.Time.After(now) {
+			continue
+		}
+		if permit.Status.Phase == syncv1.PermitPhaseGranted {
+			granted += int(permitWeight(permit))
+			continue
+		}
+		if permit.Status.Phase == syncv1.PermitPhaseDenied {
+			continue
+		}
+		pending++
+		if permit.Status.Phase == syncv1.PermitPhasePending {
+			continue
+		}
+		if err := r.setPermitPhase(ctx, semaphore, permit, syncv1.PermitPhasePending); err != nil {
+			return 0, 0, err
+		}
+	}
+	return granted, pending, nil
+}
+
+func permitPriority(permit *syncv1.Permit) int32 {
+	if permit.Spec.Priority == nil {
+		return 0
+	}
+	return *permit.Spec.Priority
+}
+
+// permitWeight returns how many of the semaphore's permits permit counts for.
+// Permits created before weights existed count for one.
+func permitWeight(permit *syncv1.Permit) int32 {
+	if permit.Spec.Weight <= 0 {
+		return 1
+	}
+	return permit.Spec.Weight
+}
+
+// deniedPermitRetention is how long a Denied permit is kept for its acquirer
+// to see the decision. Acquirers delete it themselves; the expiry cleans up
+// after one that went away first.
+const deniedPermitRetention = 30 * time.Second
+
+// permitTTL returns how long permit is held once granted: its own TTL, or
+// else the semaphore's default. Zero means the permit does not expire.
+func permitTTL(semaphore *syncv1.Semaphore, permit *syncv1.Permit) time.Duration {
+	if permit.Spec.TTL != nil {
+		return permit.Spec.TTL.Duration
+	}
+	if semaphore.Spec.TTL != nil {
+		return semaphore.Spec.TTL.Duration
+	}
+	return 0
+}
+
+// setPermitPhase moves permit to phase. A granted permit expires its TTL
+// after the grant, and a denied one once deniedPermitRetention has passed.
+func (r *SemaphoreReconciler) setPermitPhase(ctx context.Context, semaphore *syncv1.Semaphore, permit *syncv1.Permit, phase syncv1.PermitPhase) error {
+	permit.Status.Phase = phase
+	now := time.Now()
+	switch phase {
+	case syncv1.PermitPhaseGranted:
+		if ttl := permitTTL(semaphore, permit); ttl > 0 {
+			expiresAt := metav1.NewTime(now.Add(ttl))
+			permit.Status.ExpiresAt = &expiresAt
+		}
+	case syncv1.PermitPhaseDenied:
+		expiresAt := metav1.NewTime(now.Add(deniedPermitRetention))
+		permit.Status.ExpiresAt = &expiresAt
+	}
+	if err := r.Status().Update(ctx, permit); err != nil {
+		log.FromContext(ctx).Error(err, "failed to update permit status", "permit", permit.Name)
+		return err
+	}
+	if phase == syncv1.PermitPhaseGranted {
+		recordNormal(r.Recorder, semaphore, ReasonPermitGranted, "Granted permit %s to %s", permit.Name, permit.Spec.Holder)
+	}
+	return nil
+}
+
 func (r *SemaphoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("semaphore-controller")
+	}
+	// Permits are mapped to their semaphore by name rather than owner, since
+	// a permit owned by its holder pod has no owner reference to it
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.Semaphore{}).
-		Owns(&syncv1.Permit{}).
-		Complete(r)
+		Watches(&syncv1.Permit{}, handler.EnqueueRequestsFromMapFunc(permitSemaphore)).
+		Complete(trackHealth("semaphore", r))
+}
+
+// permitSemaphore maps a permit to a reconcile request for its semaphore
+func permitSemaphore(_ context.Context, obj client.Object) []reconcile.Request {
+	permit, ok := obj.(*syncv1.Permit)
+	if !ok || permit.Spec.Semaphore == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: permit.Spec.Semaphore, Namespace: permit.Namespace},
+	}}
 }
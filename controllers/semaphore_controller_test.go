@@ -7,11 +7,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
@@ -21,12 +24,16 @@ func TestSemaphoreReconciler_Reconcile(t *testing.T) {
 	require.NoError(t, syncv1.AddToScheme(scheme))
 
 	tests := []struct {
-		name          string
-		semaphore     *syncv1.Semaphore
-		permits       []syncv1.Permit
-		expectedPhase syncv1.SemaphorePhase
-		expectedInUse int32
-		expectedAvail int32
+		name            string
+		semaphore       *syncv1.Semaphore
+		permits         []syncv1.Permit
+		expectedPhase   syncv1.SemaphorePhase
+		expectedInUse   int32
+		expectedAvail   int32
+		expectedPending int32
+		expectedEvents  []string
+		// expectedRequeue is the steady-state requeue, a minute when unset
+		expectedRequeue time.Duration
 	}{
 		{
 			name: "empty semaphore should be ready",
@@ -71,6 +78,9 @@ func TestSemaphoreReconciler_Reconcile(t *testing.T) {
 			expectedPhase: syncv1.SemaphorePhaseReady,
 			expectedInUse: 1,
 			expectedAvail: 4,
+			expectedEvents: []string{
+				"Normal PermitGranted Granted permit permit-1 to holder-1",
+			},
 		},
 		{
 			name: "full semaphore should be full",
@@ -110,6 +120,148 @@ func TestSemaphoreReconciler_Reconcile(t *testing.T) {
 			expectedPhase: syncv1.SemaphorePhaseFull,
 			expectedInUse: 2,
 			expectedAvail: 0,
+			expectedEvents: []string{
+				"Normal PermitGranted Granted permit permit-1 to holder-1",
+				"Normal PermitGranted Granted permit permit-2 to holder-2",
+				"Normal SemaphoreFull All 2 permits are in use",
+			},
+		},
+		{
+			name: "permits beyond capacity should be denied",
+			semaphore: &syncv1.Semaphore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sem",
+					Namespace: "default",
+				},
+				Spec: syncv1.SemaphoreSpec{
+					Permits: 1,
+				},
+			},
+			permits: []syncv1.Permit{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-1",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-2",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-2",
+					},
+				},
+			},
+			expectedPhase: syncv1.SemaphorePhaseFull,
+			expectedInUse: 1,
+			expectedAvail: 0,
+			expectedEvents: []string{
+				"Normal PermitGranted Granted permit permit-1 to holder-1",
+				"Warning PermitDenied Denied permit permit-2 to holder-2, 1 of 1 permits are in use",
+				"Normal SemaphoreFull All 1 permits are in use",
+			},
+			// The denied permit is due for deletion
+			expectedRequeue: deniedPermitRetention,
+		},
+		{
+			name: "weighted permits below capacity should be ready",
+			semaphore: &syncv1.Semaphore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sem",
+					Namespace: "default",
+				},
+				Spec: syncv1.SemaphoreSpec{
+					Permits: 5,
+				},
+			},
+			permits: []syncv1.Permit{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-big",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-big",
+						Weight:    3,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-small",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-small",
+						Weight:    1,
+					},
+				},
+			},
+			expectedPhase: syncv1.SemaphorePhaseReady,
+			expectedInUse: 4,
+			expectedAvail: 1,
+			expectedEvents: []string{
+				"Normal PermitGranted Granted permit permit-big to holder-big",
+				"Normal PermitGranted Granted permit permit-small to holder-small",
+			},
+		},
+		{
+			name: "weighted permits summing to capacity should be full",
+			semaphore: &syncv1.Semaphore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sem",
+					Namespace: "default",
+				},
+				Spec: syncv1.SemaphoreSpec{
+					Permits: 5,
+				},
+			},
+			permits: []syncv1.Permit{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-big",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-big",
+						Weight:    3,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-medium",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-medium",
+						Weight:    2,
+					},
+				},
+			},
+			expectedPhase: syncv1.SemaphorePhaseFull,
+			expectedInUse: 5,
+			expectedAvail: 0,
+			expectedEvents: []string{
+				"Normal PermitGranted Granted permit permit-big to holder-big",
+				"Normal PermitGranted Granted permit permit-medium to holder-medium",
+				"Normal SemaphoreFull All 5 permits are in use",
+			},
 		},
 		{
 			name: "expired permits should not count",
@@ -155,6 +307,66 @@ func TestSemaphoreReconciler_Reconcile(t *testing.T) {
 			expectedPhase: syncv1.SemaphorePhaseReady,
 			expectedInUse: 1,
 			expectedAvail: 2,
+			expectedEvents: []string{
+				"Normal PermitGranted Granted permit permit-2 to holder-2",
+			},
+		},
+		{
+			name: "queued semaphore should grant to highest priority",
+			semaphore: &syncv1.Semaphore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sem",
+					Namespace: "default",
+				},
+				Spec: syncv1.SemaphoreSpec{
+					Permits: 1,
+				},
+			},
+			permits: []syncv1.Permit{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-low",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-low",
+						Priority:  int32Ptr(1),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-high",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-high",
+						Priority:  int32Ptr(10),
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "permit-default",
+						Namespace: "default",
+						Labels:    map[string]string{"semaphore": "test-sem"},
+					},
+					Spec: syncv1.PermitSpec{
+						Semaphore: "test-sem",
+						Holder:    "holder-default",
+					},
+				},
+			},
+			expectedPhase:   syncv1.SemaphorePhaseFull,
+			expectedInUse:   1,
+			expectedAvail:   0,
+			expectedPending: 2,
+			expectedEvents: []string{
+				"Normal PermitGranted Granted permit permit-high to holder-high",
+				"Normal SemaphoreFull All 1 permits are in use",
+			},
 		},
 	}
 
@@ -171,9 +383,11 @@ func TestSemaphoreReconciler_Reconcile(t *testing.T) {
 				WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
 				Build()
 
+			recorder := record.NewFakeRecorder(10)
 			reconciler := &SemaphoreReconciler{
-				Client: client,
-				Scheme: scheme,
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: recorder,
 			}
 
 			req := ctrl.Request{
@@ -194,7 +408,11 @@ func TestSemaphoreReconciler_Reconcile(t *testing.T) {
 			// Third reconcile verifies steady-state behavior
 			result, err := reconciler.Reconcile(context.Background(), req)
 			require.NoError(t, err)
-			assert.Equal(t, time.Minute, result.RequeueAfter)
+			if tt.expectedRequeue == 0 {
+				assert.Equal(t, time.Minute, result.RequeueAfter)
+			} else {
+				assert.InDelta(t, tt.expectedRequeue, result.RequeueAfter, float64(time.Second))
+			}
 
 			var updated syncv1.Semaphore
 			err = client.Get(context.Background(), req.NamespacedName, &updated)
@@ -203,6 +421,8 @@ func TestSemaphoreReconciler_Reconcile(t *testing.T) {
 			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
 			assert.Equal(t, tt.expectedInUse, updated.Status.InUse)
 			assert.Equal(t, tt.expectedAvail, updated.Status.Available)
+			assert.Equal(t, tt.expectedPending, updated.Status.Pending)
+			assertEvents(t, recorder, tt.expectedEvents...)
 		})
 	}
 }
@@ -228,3 +448,925 @@ func TestSemaphoreReconciler_NotFound(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, ctrl.Result{}, result)
 }
+
+func TestSemaphoreReconciler_FairGrantsInArrivalOrder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 1,
+			Fair:    true,
+		},
+		Status: syncv1.SemaphoreStatus{
+			Available: 1,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+
+	// Listed out of arrival order so the reconciler has to sort them
+	start := time.Now().Add(-time.Minute)
+	newPermit := func(name string, createdAt time.Time) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"semaphore": "test-sem"},
+				CreationTimestamp: metav1.NewTime(createdAt),
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+			},
+		}
+	}
+	third := newPermit("third", start.Add(2*time.Second))
+	first := newPermit("first", start)
+	second := newPermit("second", start.Add(time.Second))
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, third, first, second).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SemaphoreReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      semaphore.Name,
+			Namespace: semaphore.Namespace,
+		},
+	}
+
+	phaseOf := func(name string) syncv1.PermitPhase {
+		var permit syncv1.Permit
+		require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &permit))
+		return permit.Status.Phase
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, syncv1.PermitPhaseGranted, phaseOf("first"))
+	assert.Equal(t, syncv1.PermitPhasePending, phaseOf("second"))
+	assert.Equal(t, syncv1.PermitPhasePending, phaseOf("third"))
+	assertEvents(t, recorder,
+		"Normal PermitGranted Granted permit first to first",
+		"Normal SemaphoreFull All 1 permits are in use")
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, int32(1), updated.Status.InUse)
+	assert.Equal(t, int32(0), updated.Status.Available)
+
+	// Releasing the held permit hands it to the next request in line
+	require.NoError(t, client.Delete(context.Background(), first))
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, syncv1.PermitPhaseGranted, phaseOf("second"))
+	assert.Equal(t, syncv1.PermitPhasePending, phaseOf("third"))
+	assertEvents(t, recorder, "Normal PermitGranted Granted permit second to second")
+}
+
+func TestSemaphoreReconciler_FairGrantsByWeight(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 4,
+			Fair:    true,
+		},
+		Status: syncv1.SemaphoreStatus{
+			Available: 4,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+
+	start := time.Now().Add(-time.Minute)
+	newPermit := func(name string, weight int32, createdAt time.Time) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"semaphore": "test-sem"},
+				CreationTimestamp: metav1.NewTime(createdAt),
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+				Weight:    weight,
+			},
+		}
+	}
+	big := newPermit("big", 3, start)
+	medium := newPermit("medium", 2, start.Add(time.Second))
+	small := newPermit("small", 1, start.Add(2*time.Second))
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, big, medium, small).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SemaphoreReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      semaphore.Name,
+			Namespace: semaphore.Namespace,
+		},
+	}
+
+	phaseOf := func(name string) syncv1.PermitPhase {
+		var permit syncv1.Permit
+		require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &permit))
+		return permit.Status.Phase
+	}
+	status := func() syncv1.SemaphoreStatus {
+		var updated syncv1.Semaphore
+		require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+		return updated.Status
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	// medium does not fit in the one permit left, and small, which would,
+	// waits behind it rather than jumping the queue
+	assert.Equal(t, syncv1.PermitPhaseGranted, phaseOf("big"))
+	assert.Equal(t, syncv1.PermitPhasePending, phaseOf("medium"))
+	assert.Equal(t, syncv1.PermitPhasePending, phaseOf("small"))
+	assertEvents(t, recorder, "Normal PermitGranted Granted permit big to big")
+
+	current := status()
+	assert.Equal(t, int32(3), current.InUse)
+	assert.Equal(t, int32(1), current.Available)
+	assert.Equal(t, int32(2), current.Pending)
+	assert.Equal(t, syncv1.SemaphorePhaseReady, current.Phase)
+
+	require.NoError(t, client.Delete(context.Background(), big))
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, syncv1.PermitPhaseGranted, phaseOf("medium"))
+	assert.Equal(t, syncv1.PermitPhaseGranted, phaseOf("small"))
+	assertEvents(t, recorder,
+		"Normal PermitGranted Granted permit medium to medium",
+		"Normal PermitGranted Granted permit small to small")
+
+	current = status()
+	assert.Equal(t, int32(3), current.InUse)
+	assert.Equal(t, int32(1), current.Available)
+	assert.Equal(t, int32(0), current.Pending)
+}
+
+func TestSemaphoreReconciler_PriorityTiesBrokenByArrival(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 1,
+		},
+		Status: syncv1.SemaphoreStatus{
+			Available: 1,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+
+	start := time.Now().Add(-time.Minute)
+	newPermit := func(name string, priority int32, createdAt time.Time) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"semaphore": "test-sem"},
+				CreationTimestamp: metav1.NewTime(createdAt),
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+				Priority:  int32Ptr(priority),
+			},
+		}
+	}
+	low := newPermit("low", 1, start)
+	early := newPermit("early", 5, start.Add(time.Second))
+	late := newPermit("late", 5, start.Add(2*time.Second))
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, late, low, early).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	reconciler := &SemaphoreReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      semaphore.Name,
+			Namespace: semaphore.Namespace,
+		},
+	}
+
+	grantedPermit := func() string {
+		var permits syncv1.PermitList
+		require.NoError(t, client.List(context.Background(), &permits))
+		granted := ""
+		for _, permit := range permits.Items {
+			if permit.Status.Phase == syncv1.PermitPhaseGranted {
+				require.Empty(t, granted, "only one permit may be granted")
+				granted = permit.Name
+			}
+		}
+		return granted
+	}
+
+	// Each release hands the permit to the next request in priority order,
+	// never granting more than the semaphore allows
+	for _, expected := range []*syncv1.Permit{early, late, low} {
+		_, err := reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Name, grantedPermit())
+
+		_, err = reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Name, grantedPermit())
+
+		require.NoError(t, client.Delete(context.Background(), expected))
+	}
+}
+
+func TestSemaphoreReconciler_DeletesExpiredPermits(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 2,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     2,
+			Available: 0,
+			Phase:     syncv1.SemaphorePhaseFull,
+		},
+	}
+
+	newPermit := func(name string, expiresAt time.Time) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"semaphore": "test-sem"},
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+			},
+			Status: syncv1.PermitStatus{
+				Phase:     syncv1.PermitPhaseGranted,
+				ExpiresAt: &metav1.Time{Time: expiresAt},
+			},
+		}
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore,
+			newPermit("expired", time.Now().Add(-time.Minute)),
+			newPermit("live", time.Now().Add(time.Hour))).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	reconciler := &SemaphoreReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      semaphore.Name,
+			Namespace: semaphore.Namespace,
+		},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "live", permits.Items[0].Name)
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.SemaphorePhaseReady, updated.Status.Phase)
+	assert.Equal(t, int32(1), updated.Status.InUse)
+	assert.Equal(t, int32(1), updated.Status.Available)
+}
+
+func TestSemaphoreReconciler_RequeuesAtNextPermitExpiry(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 2,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     2,
+			Available: 0,
+			Phase:     syncv1.SemaphorePhaseFull,
+		},
+	}
+
+	newPermit := func(name string, expiresAt time.Time) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"semaphore": "test-sem"},
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+			},
+			Status: syncv1.PermitStatus{
+				Phase:     syncv1.PermitPhaseGranted,
+				ExpiresAt: &metav1.Time{Time: expiresAt},
+			},
+		}
+	}
+
+	// Permit timestamps are stored with second precision, so expire on a
+	// whole second between one and two seconds from now
+	expiresAt := time.Now().Truncate(time.Second).Add(2 * time.Second)
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore,
+			newPermit("expiring", expiresAt),
+			newPermit("live", time.Now().Add(time.Hour))).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	reconciler := &SemaphoreReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      semaphore.Name,
+			Namespace: semaphore.Namespace,
+		},
+	}
+
+	start := time.Now()
+	result, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+	assert.LessOrEqual(t, result.RequeueAfter, expiresAt.Sub(start)+permitExpiryGrace)
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, int32(0), updated.Status.Available)
+
+	// Reconcile again once the permit has expired
+	var expiring syncv1.Permit
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: "expiring", Namespace: "default"}, &expiring))
+	expiring.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	require.NoError(t, client.Status().Update(context.Background(), &expiring))
+
+	result, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, result.RequeueAfter)
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "live", permits.Items[0].Name)
+
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.SemaphorePhaseReady, updated.Status.Phase)
+	assert.Equal(t, int32(1), updated.Status.Available)
+}
+
+func TestSemaphoreReconciler_DeniedPermitIsNotGrantedLater(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 1,
+		},
+		Status: syncv1.SemaphoreStatus{
+			Available: 1,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+	// The holder of a denied permit may not have withdrawn it yet
+	denied := &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "denied",
+			Namespace: "default",
+			Labels:    map[string]string{"semaphore": "test-sem"},
+		},
+		Spec: syncv1.PermitSpec{
+			Semaphore: "test-sem",
+			Holder:    "late",
+		},
+		Status: syncv1.PermitStatus{Phase: syncv1.PermitPhaseDenied},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, denied).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SemaphoreReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-sem", Namespace: "default"}}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var permit syncv1.Permit
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: "denied", Namespace: "default"}, &permit))
+	assert.Equal(t, syncv1.PermitPhaseDenied, permit.Status.Phase)
+	assertEvents(t, recorder)
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, int32(0), updated.Status.InUse)
+	assert.Equal(t, int32(1), updated.Status.Available)
+	assert.Equal(t, int32(0), updated.Status.Pending)
+}
+
+func TestSemaphoreReconciler_DrainKeepsHoldersAndGrantsNothing(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 3,
+			Drain:   true,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     1,
+			Available: 2,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+	newPermit := func(name string, phase syncv1.PermitPhase) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"semaphore": "test-sem"},
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+			},
+			Status: syncv1.PermitStatus{Phase: phase},
+		}
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, newPermit("existing", syncv1.PermitPhaseGranted), newPermit("new", "")).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &SemaphoreReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      semaphore.Name,
+			Namespace: semaphore.Namespace,
+		},
+	}
+
+	phaseOf := func(name string) syncv1.PermitPhase {
+		var permit syncv1.Permit
+		require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &permit))
+		return permit.Status.Phase
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, syncv1.PermitPhaseGranted, phaseOf("existing"))
+	assert.Equal(t, syncv1.PermitPhasePending, phaseOf("new"))
+	assertEvents(t, recorder, "Normal SemaphoreDraining Draining with 1 permits still held")
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.SemaphorePhaseDraining, updated.Status.Phase)
+	assert.Equal(t, int32(1), updated.Status.InUse)
+	assert.Equal(t, int32(1), updated.Status.Pending)
+
+	// Lifting drain grants the request that waited
+	updated.Spec.Drain = false
+	require.NoError(t, client.Update(context.Background(), &updated))
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, syncv1.PermitPhaseGranted, phaseOf("new"))
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, syncv1.SemaphorePhaseReady, updated.Status.Phase)
+	assert.Equal(t, int32(2), updated.Status.InUse)
+	assertEvents(t, recorder, "Normal PermitGranted Granted permit new to new")
+}
+
+func TestSemaphoreReconciler_RecordsPeakUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 2,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     0,
+			Available: 2,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+	newPermit := func(name string) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"semaphore": "test-sem"},
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+			},
+		}
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	reconciler := &SemaphoreReconciler{
+		Client: client,
+		Scheme: scheme,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      semaphore.Name,
+			Namespace: semaphore.Namespace,
+		},
+	}
+
+	reconcile := func() syncv1.SemaphoreStatus {
+		_, err := reconciler.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		var updated syncv1.Semaphore
+		require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+		return updated.Status
+	}
+
+	first := newPermit("first")
+	require.NoError(t, client.Create(context.Background(), first))
+	status := reconcile()
+	assert.Equal(t, int32(1), status.MaxInUse)
+	assert.Nil(t, status.LastFullAt, "a semaphore with permits to spare was never full")
+
+	require.NoError(t, client.Create(context.Background(), newPermit("second")))
+	start := time.Now()
+	status = reconcile()
+	assert.Equal(t, syncv1.SemaphorePhaseFull, status.Phase)
+	assert.Equal(t, int32(2), status.MaxInUse)
+	require.NotNil(t, status.LastFullAt)
+	assert.WithinDuration(t, start, status.LastFullAt.Time, 2*time.Second)
+	fullAt := *status.LastFullAt
+
+	// Staying full keeps the time it filled up
+	status = reconcile()
+	require.NotNil(t, status.LastFullAt)
+	assert.True(t, fullAt.Equal(status.LastFullAt))
+
+	// Releasing a permit keeps the high-water mark and the last time it was full
+	require.NoError(t, client.Delete(context.Background(), first))
+	status = reconcile()
+	assert.Equal(t, int32(1), status.InUse)
+	assert.Equal(t, int32(2), status.MaxInUse)
+	require.NotNil(t, status.LastFullAt)
+	assert.True(t, fullAt.Equal(status.LastFullAt))
+}
+
+func TestSemaphoreReconciler_ReportsQueueDepthAndLongestWait(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "default",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 1,
+			Fair:    true,
+		},
+		Status: syncv1.SemaphoreStatus{
+			Available: 1,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+
+	now := time.Now()
+	newPermit := func(name string, age time.Duration) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"semaphore": "test-sem"},
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: syncv1.PermitSpec{
+				Semaphore: "test-sem",
+				Holder:    name,
+			},
+		}
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore,
+			newPermit("first", 5*time.Minute),
+			newPermit("second", 3*time.Minute),
+			newPermit("third", time.Minute),
+			newPermit("fourth", 30*time.Second)).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+
+	reconciler := &SemaphoreReconciler{Client: client, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: semaphore.Name, Namespace: semaphore.Namespace},
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Equal(t, int32(1), updated.Status.InUse)
+	assert.Equal(t, int32(3), updated.Status.Pending)
+	// first holds the permit, so second has waited longest
+	require.NotNil(t, updated.Status.LongestWait)
+	assert.InDelta(t, (3 * time.Minute).Seconds(), updated.Status.LongestWait.Seconds(), 2)
+
+	// The wait grows between reconciles, but not by enough to be written
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	var again syncv1.Semaphore
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &again))
+	assert.Equal(t, updated.ResourceVersion, again.ResourceVersion)
+
+	// Once the queue empties the wait is cleared
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		require.NoError(t, client.Delete(context.Background(), &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}))
+	}
+	_, err = reconciler.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	require.NoError(t, client.Get(context.Background(), req.NamespacedName, &updated))
+	assert.Zero(t, updated.Status.Pending)
+	assert.Nil(t, updated.Status.LongestWait)
+}
+
+func TestRecordLongestWait(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	pending := []syncv1.Permit{{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		Status:     syncv1.PermitStatus{Phase: syncv1.PermitPhasePending},
+	}}
+	wait := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	tests := []struct {
+		name     string
+		previous *metav1.Duration
+		changed  bool
+		want     *metav1.Duration
+	}{
+		{name: "first pending permit", previous: nil, want: wait(time.Minute)},
+		{name: "small drift is not written", previous: wait(55 * time.Second), want: wait(55 * time.Second)},
+		{name: "large drift is written", previous: wait(40 * time.Second), want: wait(time.Minute)},
+		{name: "written with other changes", previous: wait(55 * time.Second), changed: true, want: wait(time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &syncv1.SemaphoreStatus{InUse: 1, LongestWait: tt.previous}
+			semaphore := &syncv1.Semaphore{Status: *original.DeepCopy()}
+			if tt.changed {
+				semaphore.Status.Pending = 1
+			}
+
+			recordLongestWait(semaphore, original, pending, now)
+			assert.Equal(t, tt.want, semaphore.Status.LongestWait)
+		})
+	}
+
+	original := &syncv1.SemaphoreStatus{LongestWait: wait(time.Minute)}
+	semaphore := &syncv1.Semaphore{Status: *original.DeepCopy()}
+	recordLongestWait(semaphore, original, nil, now)
+	assert.Nil(t, semaphore.Status.LongestWait, "an empty queue clears the wait")
+}
+
+func TestPermitSemaphore(t *testing.T) {
+	// A pod-owned permit has no owner reference to its semaphore
+	permit := &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem-worker-1",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       "worker-1",
+				UID:        "3f1c2a9e-0d4b-4c1e-9a77-5b2f8e6d1c40",
+			}},
+		},
+		Spec: syncv1.PermitSpec{
+			Semaphore: "test-sem",
+			Holder:    "worker-1",
+		},
+	}
+
+	assert.Equal(t, []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: "test-sem", Namespace: "default"},
+	}}, permitSemaphore(context.Background(), permit))
+	assert.Empty(t, permitSemaphore(context.Background(), &syncv1.Semaphore{}))
+}
+
+func TestSemaphoreReconciler_DeletesAbandonedDeniedPermit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 1},
+		Status:     syncv1.SemaphoreStatus{Available: 1, Phase: syncv1.SemaphorePhaseReady},
+	}
+	permit := func(name string) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"semaphore": "test-sem"}},
+			Spec:       syncv1.PermitSpec{Semaphore: "test-sem", Holder: name},
+		}
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, permit("first"), permit("second")).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+	reconciler := &SemaphoreReconciler{Client: client, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-sem", Namespace: "default"}}
+	ctx := context.Background()
+
+	// The second permit is denied and kept only for a while
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	var denied syncv1.Permit
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "second", Namespace: "default"}, &denied))
+	assert.Equal(t, syncv1.PermitPhaseDenied, denied.Status.Phase)
+	require.NotNil(t, denied.Status.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(deniedPermitRetention), denied.Status.ExpiresAt.Time, 2*time.Second)
+
+	// Its acquirer went away without withdrawing it, and the retention passed
+	past := metav1.NewTime(time.Now().Add(-time.Second))
+	denied.Status.ExpiresAt = &past
+	require.NoError(t, client.Status().Update(ctx, &denied))
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	err = client.Get(ctx, types.NamespacedName{Name: "second", Namespace: "default"}, &denied)
+	assert.True(t, apierrors.IsNotFound(err), "the abandoned denied permit is deleted")
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(ctx, req.NamespacedName, &updated))
+	assert.Equal(t, int32(1), updated.Status.InUse)
+}
+
+func TestSemaphoreReconciler_GrantedPermitExpiresAfterTTL(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 2, TTL: &metav1.Duration{Duration: time.Hour}},
+		Status:     syncv1.SemaphoreStatus{Available: 2, Phase: syncv1.SemaphorePhaseReady},
+	}
+	permit := func(name string, ttl *metav1.Duration) *syncv1.Permit {
+		return &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"semaphore": "test-sem"}},
+			Spec:       syncv1.PermitSpec{Semaphore: "test-sem", Holder: name, TTL: ttl},
+		}
+	}
+
+	// One permit sets its own TTL, the other falls back to the semaphore's
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore,
+			permit("short", &metav1.Duration{Duration: 2 * time.Minute}),
+			permit("default", nil)).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+	reconciler := &SemaphoreReconciler{Client: client, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-sem", Namespace: "default"}}
+	ctx := context.Background()
+
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var short, fallback syncv1.Permit
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "short", Namespace: "default"}, &short))
+	require.NoError(t, client.Get(ctx, types.NamespacedName{Name: "default", Namespace: "default"}, &fallback))
+	assert.Equal(t, syncv1.PermitPhaseGranted, short.Status.Phase)
+	require.NotNil(t, short.Status.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), short.Status.ExpiresAt.Time, 2*time.Second)
+	require.NotNil(t, fallback.Status.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), fallback.Status.ExpiresAt.Time, 2*time.Second)
+	assert.Equal(t, 10*time.Second, result.RequeueAfter)
+
+	// The holder never released the permit and its TTL ran out
+	past := metav1.NewTime(time.Now().Add(-time.Second))
+	short.Status.ExpiresAt = &past
+	require.NoError(t, client.Status().Update(ctx, &short))
+
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	err = client.Get(ctx, types.NamespacedName{Name: "short", Namespace: "default"}, &short)
+	assert.True(t, apierrors.IsNotFound(err), "the expired permit is deleted")
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.Get(ctx, req.NamespacedName, &updated))
+	assert.Equal(t, int32(1), updated.Status.InUse)
+	assert.Equal(t, int32(1), updated.Status.Available)
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// expiryRequeueMargin is how long after a TTL runs out the reconcile that
+// clears it is scheduled, so the expiry has passed by the time it runs
+const expiryRequeueMargin = 100 * time.Millisecond
+
+// observeGeneration records obj's current generation in observed and reports
+// whether it moved, so a status that is otherwise unchanged is still written
+// back once after a spec edit
+func observeGeneration(observed *int64, obj metav1.Object) bool {
+	if *observed == obj.GetGeneration() {
+		return false
+	}
+	*observed = obj.GetGeneration()
+	return true
+}
+
+// hasExpired reports whether expiresAt is set and has been reached by now
+func hasExpired(expiresAt *metav1.Time, now time.Time) bool {
+	return expiresAt != nil && !now.Before(expiresAt.Time)
+}
+
+// requeueAtExpiry schedules the next reconcile for just after expiresAt, or
+// none if it is unset
+func requeueAtExpiry(expiresAt *metav1.Time, now time.Time) ctrl.Result {
+	if expiresAt == nil {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: expiresAt.Sub(now) + expiryRequeueMargin}
+}
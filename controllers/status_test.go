@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// newStatusCountingClient returns a fake client that counts status updates
+// issued against obj
+func newStatusCountingClient(t *testing.T, obj client.Object) (client.Client, *int) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	updates := 0
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(obj).
+		WithStatusSubresource(obj).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, o client.Object, opts ...client.SubResourceUpdateOption) error {
+				if subResourceName == "status" && o.GetName() == obj.GetName() {
+					updates++
+				}
+				return c.SubResource(subResourceName).Update(ctx, o, opts...)
+			},
+		}).
+		Build()
+	return c, &updates
+}
+
+func TestReconcilers_SkipRedundantStatusUpdates(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "test", Namespace: "default", Generation: 1}
+
+	tests := []struct {
+		name       string
+		obj        client.Object
+		reconciler func(c client.Client) reconcile.Reconciler
+		observed   func(obj client.Object) int64
+	}{
+		{
+			name: "semaphore",
+			obj: &syncv1.Semaphore{
+				ObjectMeta: meta,
+				Spec:       syncv1.SemaphoreSpec{Permits: 2},
+				Status:     syncv1.SemaphoreStatus{Phase: syncv1.SemaphorePhaseReady, Available: 2},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &SemaphoreReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.Semaphore).Status.ObservedGeneration },
+		},
+		{
+			name: "barrier",
+			obj: &syncv1.Barrier{
+				ObjectMeta: meta,
+				Spec:       syncv1.BarrierSpec{Expected: 2},
+				Status:     syncv1.BarrierStatus{Phase: syncv1.BarrierPhaseWaiting},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &BarrierReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.Barrier).Status.ObservedGeneration },
+		},
+		{
+			name: "lease",
+			obj: &syncv1.Lease{
+				ObjectMeta: meta,
+				Status:     syncv1.LeaseStatus{Phase: syncv1.LeasePhaseAvailable},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &LeaseReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.Lease).Status.ObservedGeneration },
+		},
+		{
+			name: "gate",
+			obj: &syncv1.Gate{
+				ObjectMeta: meta,
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &GateReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.Gate).Status.ObservedGeneration },
+		},
+		{
+			name: "mutex",
+			obj: &syncv1.Mutex{
+				ObjectMeta: meta,
+				Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseUnlocked},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &MutexReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.Mutex).Status.ObservedGeneration },
+		},
+		{
+			name: "rwmutex",
+			obj: &syncv1.RWMutex{
+				ObjectMeta: meta,
+				Status:     syncv1.RWMutexStatus{Phase: syncv1.RWMutexPhaseUnlocked},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &RWMutexReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.RWMutex).Status.ObservedGeneration },
+		},
+		{
+			name: "once",
+			obj: &syncv1.Once{
+				ObjectMeta: meta,
+				Status:     syncv1.OnceStatus{Phase: syncv1.OncePhasePending},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &OnceReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.Once).Status.ObservedGeneration },
+		},
+		{
+			name: "waitgroup",
+			obj: &syncv1.WaitGroup{
+				ObjectMeta: meta,
+				Status:     syncv1.WaitGroupStatus{Phase: syncv1.WaitGroupPhaseWaiting, Counter: 1},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &WaitGroupReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.WaitGroup).Status.ObservedGeneration },
+		},
+		{
+			name: "event",
+			obj: &syncv1.Event{
+				ObjectMeta: meta,
+				Status:     syncv1.EventStatus{Phase: syncv1.EventPhaseCleared},
+			},
+			reconciler: func(c client.Client) reconcile.Reconciler { return &EventReconciler{Client: c} },
+			observed:   func(obj client.Object) int64 { return obj.(*syncv1.Event).Status.ObservedGeneration },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, updates := newStatusCountingClient(t, tt.obj)
+			r := tt.reconciler(c)
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tt.obj)}
+			ctx := context.Background()
+
+			// The first reconcile records the generation it observed
+			_, err := r.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, 1, *updates)
+
+			current := tt.obj.DeepCopyObject().(client.Object)
+			require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+			assert.Equal(t, int64(1), tt.observed(current))
+
+			// Reconciling the unchanged object must not write status again
+			_, err = r.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, 1, *updates)
+
+			// A spec edit bumps the generation, which must be written back
+			current.SetGeneration(2)
+			require.NoError(t, c.Update(ctx, current))
+
+			_, err = r.Reconcile(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, 2, *updates)
+
+			require.NoError(t, c.Get(ctx, req.NamespacedName, current))
+			assert.Equal(t, int64(2), tt.observed(current))
+		})
+	}
+}
+
+func TestRequeueAtExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: now.Add(d)} }
+
+	assert.Equal(t, ctrl.Result{}, requeueAtExpiry(nil, now))
+	assert.Equal(t, ctrl.Result{RequeueAfter: 2*time.Second + expiryRequeueMargin}, requeueAtExpiry(at(2*time.Second), now))
+
+	assert.False(t, hasExpired(nil, now))
+	assert.False(t, hasExpired(at(time.Millisecond), now))
+	assert.True(t, hasExpired(at(0), now), "a TTL is spent the moment it is reached")
+	assert.True(t, hasExpired(at(-time.Second), now))
+}
@@ -2,12 +2,14 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
@@ -15,15 +17,17 @@ import (
 // WaitGroupReconciler reconciles a WaitGroup object
 type WaitGroupReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=waitgroups,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=waitgroups/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sync.konductor.io,resources=waitgroups/finalizers,verbs=update
 
-func (r *WaitGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := log.FromContext(ctx)
+func (r *WaitGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	defer observeReconcile("WaitGroup", time.Now(), &err)
+	ctx, log := startReconcile(ctx, "WaitGroup", req)
 
 	var wg syncv1.WaitGroup
 	if err := r.Get(ctx, req.NamespacedName, &wg); err != nil {
@@ -35,26 +39,36 @@ func (r *WaitGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	// Update phase based on counter
 	var newPhase syncv1.WaitGroupPhase
+	reason := "counter reached zero"
 	if wg.Status.Counter <= 0 {
 		newPhase = syncv1.WaitGroupPhaseDone
 	} else {
 		newPhase = syncv1.WaitGroupPhaseWaiting
+		reason = fmt.Sprintf("counter at %d", wg.Status.Counter)
 	}
 
-	if wg.Status.Phase != newPhase {
+	oldPhase := wg.Status.Phase
+	generationChanged := observeGeneration(&wg.Status.ObservedGeneration, &wg)
+	if wg.Status.Phase != newPhase || generationChanged {
 		wg.Status.Phase = newPhase
 		if err := r.Status().Update(ctx, &wg); err != nil {
 			log.Error(err, "unable to update WaitGroup status")
 			return ctrl.Result{}, err
 		}
-		log.Info("WaitGroup phase updated", "phase", newPhase, "counter", wg.Status.Counter)
+		if oldPhase != newPhase && newPhase == syncv1.WaitGroupPhaseDone {
+			recordNormal(r.Recorder, &wg, ReasonWaitGroupDone, "Counter reached zero")
+		}
 	}
 
+	logPhase(log, oldPhase, newPhase, reason)
 	return ctrl.Result{}, nil
 }
 
 func (r *WaitGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("waitgroup-controller")
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&syncv1.WaitGroup{}).
-		Complete(r)
+		Complete(trackHealth("waitgroup", r))
 }
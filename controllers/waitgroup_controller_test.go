@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -20,9 +21,10 @@ func TestWaitGroupReconciler_Reconcile(t *testing.T) {
 	require.NoError(t, syncv1.AddToScheme(scheme))
 
 	tests := []struct {
-		name          string
-		wg            *syncv1.WaitGroup
-		expectedPhase syncv1.WaitGroupPhase
+		name           string
+		wg             *syncv1.WaitGroup
+		expectedPhase  syncv1.WaitGroupPhase
+		expectedEvents []string
 	}{
 		{
 			name: "waiting waitgroup",
@@ -50,7 +52,8 @@ func TestWaitGroupReconciler_Reconcile(t *testing.T) {
 					Counter: 0,
 				},
 			},
-			expectedPhase: syncv1.WaitGroupPhaseDone,
+			expectedPhase:  syncv1.WaitGroupPhaseDone,
+			expectedEvents: []string{"Normal WaitGroupDone Counter reached zero"},
 		},
 	}
 
@@ -62,9 +65,11 @@ func TestWaitGroupReconciler_Reconcile(t *testing.T) {
 				WithStatusSubresource(&syncv1.WaitGroup{}).
 				Build()
 
+			recorder := record.NewFakeRecorder(10)
 			reconciler := &WaitGroupReconciler{
-				Client: client,
-				Scheme: scheme,
+				Client:   client,
+				Scheme:   scheme,
+				Recorder: recorder,
 			}
 
 			req := ctrl.Request{
@@ -82,6 +87,7 @@ func TestWaitGroupReconciler_Reconcile(t *testing.T) {
 			require.NoError(t, err)
 
 			assert.Equal(t, tt.expectedPhase, updated.Status.Phase)
+			assertEvents(t, recorder, tt.expectedEvents...)
 		})
 	}
 }
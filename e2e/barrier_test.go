@@ -98,4 +98,63 @@ func TestE2EBarrier(t *testing.T) {
 		}
 		t.Logf("Barrier already deleted or not found: %s", string(output))
 	}
-}
\ No newline at end of file
+}
+func TestE2EBarrierArriveMany(t *testing.T) {
+	k8sClient, err := setupClient()
+	if err != nil {
+		t.Fatalf("Failed to setup client: %v", err)
+	}
+
+	namespace := "default"
+	barrierName := fmt.Sprintf("e2e-test-barrier-many-%d", time.Now().Unix())
+
+	cmd := exec.Command(cliPath, "barrier", "create", barrierName, "--expected", "3", "--timeout", "2m", "-n", namespace)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to create barrier: %v, output: %s", err, string(output))
+	}
+	defer func() {
+		_ = exec.Command(cliPath, "barrier", "delete", barrierName, "-n", namespace).Run()
+	}()
+
+	waitForBarrierReady(t, k8sClient, barrierName, namespace)
+
+	// One worker arrives on its own before the coordinator registers everyone
+	cmd = exec.Command(cliPath, "barrier", "arrive", barrierName, "worker-1", "-n", namespace)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to arrive at barrier: %v, output: %s", err, string(output))
+	}
+
+	cmd = exec.Command(cliPath, "barrier", "arrive", barrierName, "--holders", "worker-1,worker-2", "-n", namespace)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to arrive at barrier: %v, output: %s", err, string(output))
+	}
+
+	// Two of three have arrived, so the barrier must still be waiting
+	time.Sleep(3 * time.Second)
+	barrier := &syncv1.Barrier{}
+	if err := k8sClient.Get(context.TODO(), client.ObjectKey{Name: barrierName, Namespace: namespace}, barrier); err != nil {
+		t.Fatalf("Failed to get barrier: %v", err)
+	}
+	if barrier.Status.Phase == syncv1.BarrierPhaseOpen {
+		t.Fatalf("Barrier opened with %d of 3 arrivals", barrier.Status.Arrived)
+	}
+
+	cmd = exec.Command(cliPath, "barrier", "arrive", barrierName, "--holders", "worker-2,worker-3", "-n", namespace)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to arrive at barrier: %v, output: %s", err, string(output))
+	}
+
+	err = wait.PollImmediate(time.Second, 30*time.Second, func() (bool, error) {
+		if err := k8sClient.Get(context.TODO(), client.ObjectKey{Name: barrierName, Namespace: namespace}, barrier); err != nil {
+			return false, nil
+		}
+		return barrier.Status.Phase == syncv1.BarrierPhaseOpen, nil
+	})
+	if err != nil {
+		t.Fatalf("Barrier did not open: %+v", barrier.Status)
+	}
+	if barrier.Status.Arrived != 3 {
+		t.Errorf("Expected 3 arrivals, got %d", barrier.Status.Arrived)
+	}
+}
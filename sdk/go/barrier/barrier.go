@@ -3,11 +3,12 @@ package barrier
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -18,6 +19,12 @@ func wrapError(operation, name string, err error) error {
 	return fmt.Errorf("failed to %s barrier %s: %w", operation, name, err)
 }
 
+// Wait blocks until the barrier opens or fails, reacting to status changes via a
+// watch and falling back to polling when a watch cannot be established.
+//
+// A cyclic barrier never stays Open, so Wait returns once a generation has
+// opened instead: the one given by WithGeneration, otherwise the one in
+// progress when the barrier is first read.
 func Wait(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
 	options := &konductor.Options{Timeout: 0}
 	for _, opt := range opts {
@@ -40,19 +47,31 @@ func Wait(c *konductor.Client, ctx context.Context, name string, opts ...konduct
 		config.Timeout = options.Timeout
 	}
 
-	err := c.WaitForCondition(ctx, barrier, func(obj client.Object) bool {
-		b := obj.(*syncv1.Barrier)
-		switch b.Status.Phase {
-		case syncv1.BarrierPhaseOpen:
-			return true
-		case syncv1.BarrierPhaseFailed:
-			return true // Will be handled as error after condition returns
-		default:
-			return false
+	generation := options.Generation
+	opened := func(b *syncv1.Barrier) bool {
+		if !b.Spec.Cyclic {
+			return b.Status.Phase == syncv1.BarrierPhaseOpen
+		}
+		if generation == nil {
+			current := b.Status.Generation
+			generation = &current
 		}
+		return b.Status.Generation > *generation
+	}
+
+	err := c.WatchForCondition(ctx, barrier, func(obj client.Object) bool {
+		b := obj.(*syncv1.Barrier)
+		// Failure is handled as an error after the condition returns
+		return opened(b) || b.Status.Phase == syncv1.BarrierPhaseFailed
 	}, config)
 
 	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("context cancelled while waiting for barrier %s: %w", name, ctx.Err())
+		}
+		if wait.Interrupted(err) {
+			return fmt.Errorf("%w waiting for barrier %s: %w", konductor.ErrTimeout, name, err)
+		}
 		return wrapError("wait", name, err)
 	}
 
@@ -64,13 +83,59 @@ func Wait(c *konductor.Client, ctx context.Context, name string, opts ...konduct
 		return wrapError("get", name, err)
 	}
 
-	if finalBarrier.Status.Phase == syncv1.BarrierPhaseFailed {
+	if !opened(&finalBarrier) && finalBarrier.Status.Phase == syncv1.BarrierPhaseFailed {
 		return fmt.Errorf("barrier %s failed", name)
 	}
 
 	return nil
 }
 
+// WaitForCount blocks until at least count holders have arrived at the
+// barrier, whatever its phase, for coordinators that can proceed on part of
+// the arrivals without lowering the barrier's own threshold. It fails once the
+// barrier fails short of count, and with ErrTimeout after timeout, or after
+// the 30 seconds Wait also defaults to when timeout is zero.
+func WaitForCount(c *konductor.Client, ctx context.Context, name string, count int32, timeout time.Duration) error {
+	if count <= 0 {
+		return fmt.Errorf("failed to wait for barrier %s: count must be greater than zero, got %d", name, count)
+	}
+
+	barrier := &syncv1.Barrier{}
+	barrier.Name = name
+	barrier.Namespace = c.Namespace()
+
+	config := &konductor.WaitConfig{
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     5 * time.Second,
+		Factor:       1.5,
+		Jitter:       0.1,
+		Timeout:      30 * time.Second,
+	}
+	if timeout > 0 {
+		config.Timeout = timeout
+	}
+
+	err := c.WatchForCondition(ctx, barrier, func(obj client.Object) bool {
+		b := obj.(*syncv1.Barrier)
+		return b.Status.Arrived >= count || b.Status.Phase == syncv1.BarrierPhaseFailed
+	}, config)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("context cancelled while waiting for %d arrivals at barrier %s: %w", count, name, ctx.Err())
+		}
+		if wait.Interrupted(err) {
+			return fmt.Errorf("%w waiting for %d arrivals at barrier %s: %w", konductor.ErrTimeout, count, name, err)
+		}
+		return wrapError("wait", name, err)
+	}
+
+	// On success barrier holds the state that ended the wait
+	if barrier.Status.Arrived < count {
+		return fmt.Errorf("barrier %s failed with %d of %d arrivals", name, barrier.Status.Arrived, count)
+	}
+	return nil
+}
+
 // Arrive signals arrival with confirmation of barrier update
 func Arrive(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
 	options := &konductor.Options{}
@@ -80,11 +145,7 @@ func Arrive(c *konductor.Client, ctx context.Context, name string, opts ...kondu
 
 	holder := options.Holder
 	if holder == "" {
-		if hostname := os.Getenv("HOSTNAME"); hostname != "" {
-			holder = hostname
-		} else {
-			holder = fmt.Sprintf("sdk-%d", time.Now().Unix())
-		}
+		holder = konductor.DefaultHolder()
 	}
 
 	// Get current barrier state
@@ -95,11 +156,51 @@ func Arrive(c *konductor.Client, ctx context.Context, name string, opts ...kondu
 		return wrapError("get", name, err)
 	}
 
-	// Create arrival
+	return arrive(c, ctx, &barrier, holder)
+}
+
+// ArriveMany signals arrival at the barrier on behalf of each of holders, for
+// a coordinator registering its workers. Like Arrive it is idempotent, so
+// holders that have already arrived are left as they are. Every holder is
+// attempted even if some fail; the returned map holds the error for each
+// holder whose arrival could not be created, and the error is non-nil if any
+// did or the barrier could not be read.
+func ArriveMany(c *konductor.Client, ctx context.Context, name string, holders []string) (map[string]error, error) {
+	if len(holders) == 0 {
+		return nil, fmt.Errorf("failed to arrive at barrier %s: no holders given", name)
+	}
+
+	var barrier syncv1.Barrier
+	if err := c.K8sClient().Get(ctx, types.NamespacedName{
+		Name: name, Namespace: c.Namespace(),
+	}, &barrier); err != nil {
+		return nil, wrapError("get", name, err)
+	}
+
+	failed := make(map[string]error)
+	for _, holder := range holders {
+		if holder == "" {
+			failed[holder] = fmt.Errorf("holder must not be empty")
+			continue
+		}
+		if err := arrive(c, ctx, &barrier, holder); err != nil {
+			failed[holder] = err
+		}
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("failed to arrive at barrier %s for %d of %d holders", name, len(failed), len(holders))
+	}
+	return nil, nil
+}
+
+// arrive creates holder's arrival at barrier, treating an existing one as
+// success
+func arrive(c *konductor.Client, ctx context.Context, barrier *syncv1.Barrier, holder string) error {
+	name := barrier.Name
 	ctrlTrue := true
 	arrival := &syncv1.Arrival{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%s", name, holder),
+			Name:      konductor.HolderObjectName(name, holder),
 			Namespace: c.Namespace(),
 			Labels:    map[string]string{"barrier": name},
 			OwnerReferences: []metav1.OwnerReference{{
@@ -117,7 +218,19 @@ func Arrive(c *konductor.Client, ctx context.Context, name string, opts ...kondu
 		},
 	}
 
-	if err := c.K8sClient().Create(ctx, arrival); err != nil {
+	// Each round of a cyclic barrier is arrived at afresh, so the arrival is
+	// tagged with the round and named after it
+	if barrier.Spec.Cyclic {
+		arrival.Name = konductor.HolderObjectName(name, fmt.Sprintf("%s-%d", holder, barrier.Status.Generation))
+		arrival.Spec.Generation = barrier.Status.Generation
+	}
+
+	err := c.K8sClient().Create(ctx, arrival)
+	if err != nil && errors.IsAlreadyExists(err) {
+		// The holder has already arrived, this is not an error for idempotent arrive
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("failed to create arrival: %w", err)
 	}
 
@@ -126,6 +239,72 @@ func Arrive(c *konductor.Client, ctx context.Context, name string, opts ...kondu
 	return nil
 }
 
+// Reset returns the barrier to the Waiting phase so it can be reused for
+// another round. The status is reset first, so the controller ignores any
+// Arrivals from the previous round while they are being deleted. Reset is
+// meant to be called between rounds, before any holder arrives again.
+//
+// A cyclic barrier counts Arrivals by generation rather than by reset time,
+// so its Arrivals are deleted before the status is reset instead; losing
+// arrivals can never open it. Its generation is kept.
+func Reset(c *konductor.Client, ctx context.Context, name string) error {
+	barrier := &syncv1.Barrier{}
+	if err := c.K8sClient().Get(ctx, types.NamespacedName{
+		Name: name, Namespace: c.Namespace(),
+	}, barrier); err != nil {
+		return wrapError("reset", name, err)
+	}
+	cyclic := barrier.Spec.Cyclic
+
+	if cyclic {
+		if err := deleteArrivals(c, ctx, name); err != nil {
+			return wrapError("reset", name, err)
+		}
+	}
+
+	err := c.StatusUpdateWithRetry(ctx, barrier, func(obj client.Object) error {
+		b := obj.(*syncv1.Barrier)
+		resetAt := metav1.Now()
+		b.Status.Phase = syncv1.BarrierPhaseWaiting
+		b.Status.Arrived = 0
+		b.Status.Arrivals = nil
+		b.Status.OpenedAt = nil
+		b.Status.ResetAt = &resetAt
+		return nil
+	})
+	if err != nil {
+		return wrapError("reset", name, err)
+	}
+
+	if !cyclic {
+		if err := deleteArrivals(c, ctx, name); err != nil {
+			return wrapError("reset", name, err)
+		}
+	}
+	return nil
+}
+
+// deleteArrivals deletes every Arrival recorded at the named barrier
+func deleteArrivals(c *konductor.Client, ctx context.Context, name string) error {
+	var arrivals syncv1.ArrivalList
+	if err := c.K8sClient().List(ctx, &arrivals, client.InNamespace(c.Namespace()),
+		client.MatchingLabels{"barrier": name}); err != nil {
+		return err
+	}
+
+	for i := range arrivals.Items {
+		err := c.K8sClient().Delete(ctx, &arrivals.Items[i])
+		if err != nil && errors.IsNotFound(err) {
+			// Arrival already deleted, this is not an error for idempotent reset
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func With(c *konductor.Client, ctx context.Context, name string, fn func() error, opts ...konductor.Option) error {
 	if err := fn(); err != nil {
 		return err
@@ -149,9 +328,9 @@ func WaitAndArrive(c *konductor.Client, ctx context.Context, waitBarrier, arrive
 	return nil
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.Barrier, error) {
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.Barrier, error) {
 	var barriers syncv1.BarrierList
-	if err := c.K8sClient().List(ctx, &barriers, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &barriers, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list barriers: %w", err)
 	}
 	return barriers.Items, nil
@@ -219,8 +398,10 @@ func Delete(c *konductor.Client, ctx context.Context, name string) error {
 	return nil
 }
 
-func Update(c *konductor.Client, ctx context.Context, barrier *syncv1.Barrier) error {
-	if err := c.K8sClient().Update(ctx, barrier); err != nil {
+// Update writes the barrier's spec and metadata, or with WithServerSideApply
+// only the fields set in barrier
+func Update(c *konductor.Client, ctx context.Context, barrier *syncv1.Barrier, opts ...konductor.Option) error {
+	if err := c.Update(ctx, barrier, opts...); err != nil {
 		return wrapError("update", barrier.Name, err)
 	}
 	return nil
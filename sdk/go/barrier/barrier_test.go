@@ -2,18 +2,24 @@ package barrier
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/internal/applytest"
 )
 
 func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
@@ -48,6 +54,61 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "barrier1", barriers[0].Name)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-barrier",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-barrier",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-barrier", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-barrier",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-barrier",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestGet(t *testing.T) {
 	barrier := &syncv1.Barrier{
 		ObjectMeta: metav1.ObjectMeta{
@@ -141,6 +202,111 @@ func TestArriveBarrier(t *testing.T) {
 	assert.Equal(t, "test-holder", arrivals.Items[0].Spec.Holder)
 }
 
+func TestArriveBarrier_Idempotent(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	require.NoError(t, Arrive(client, context.Background(), "test-barrier", konductor.WithHolder("test-holder")))
+	require.NoError(t, Arrive(client, context.Background(), "test-barrier", konductor.WithHolder("test-holder")))
+
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, client.K8sClient().List(context.Background(), &arrivals))
+	assert.Len(t, arrivals.Items, 1)
+}
+
+func waitingBarrier(expected int32) *syncv1.Barrier {
+	return &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: expected,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+}
+
+func arrivedHolders(t *testing.T, c *konductor.Client) []string {
+	t.Helper()
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, c.K8sClient().List(context.Background(), &arrivals))
+	holders := make([]string, 0, len(arrivals.Items))
+	for _, arrival := range arrivals.Items {
+		holders = append(holders, arrival.Spec.Holder)
+	}
+	return holders
+}
+
+func TestArriveMany(t *testing.T) {
+	client := setupTestClient(t, waitingBarrier(3))
+
+	// worker-1 arrived on its own before the coordinator registered everyone
+	require.NoError(t, Arrive(client, context.Background(), "test-barrier", konductor.WithHolder("worker-1")))
+
+	failed, err := ArriveMany(client, context.Background(), "test-barrier", []string{"worker-1", "worker-2", "worker-3"})
+	require.NoError(t, err)
+	assert.Empty(t, failed)
+
+	assert.ElementsMatch(t, []string{"worker-1", "worker-2", "worker-3"}, arrivedHolders(t, client))
+}
+
+func TestArriveMany_ReportsPerHolderErrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(waitingBarrier(3)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if arrival, ok := obj.(*syncv1.Arrival); ok && arrival.Spec.Holder == "worker-2" {
+					return errors.New("admission denied")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	c := konductor.NewFromClient(k8sClient, "test-ns")
+
+	failed, err := ArriveMany(c, context.Background(), "test-barrier", []string{"worker-1", "worker-2", "worker-3"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 3 holders")
+	require.Len(t, failed, 1)
+	assert.ErrorContains(t, failed["worker-2"], "admission denied")
+
+	// The holders after the failing one are still attempted
+	assert.ElementsMatch(t, []string{"worker-1", "worker-3"}, arrivedHolders(t, c))
+}
+
+func TestArriveMany_NoHolders(t *testing.T) {
+	client := setupTestClient(t, waitingBarrier(3))
+
+	_, err := ArriveMany(client, context.Background(), "test-barrier", nil)
+	assert.Error(t, err)
+}
+
+func TestArriveMany_NotFound(t *testing.T) {
+	client := setupTestClient(t)
+
+	failed, err := ArriveMany(client, context.Background(), "missing", []string{"worker-1"})
+	assert.Error(t, err)
+	assert.Nil(t, failed)
+}
+
 func TestWaitBarrier_AlreadyOpen(t *testing.T) {
 	barrier := &syncv1.Barrier{
 		ObjectMeta: metav1.ObjectMeta{
@@ -202,3 +368,450 @@ func TestUpdate(t *testing.T) {
 	err := Update(client, context.Background(), barrier)
 	assert.NoError(t, err)
 }
+
+func TestUpdate_ServerSideApplyKeepsConcurrentChanges(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "test-ns"},
+		Spec:       syncv1.BarrierSpec{Expected: 3},
+	}
+	k8sClient, _ := applytest.NewClient(t, barrier)
+	client := konductor.NewFromClient(k8sClient, "test-ns")
+	ctx := context.Background()
+
+	// Someone else sets a quorum after the barrier was read
+	current, err := Get(client, ctx, "test-barrier")
+	require.NoError(t, err)
+	quorum := int32(2)
+	current.Spec.Quorum = &quorum
+	require.NoError(t, client.K8sClient().Update(ctx, current))
+
+	err = Update(client, ctx, &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier"},
+		Spec: syncv1.BarrierSpec{
+			Expected: 5,
+			Timeout:  &metav1.Duration{Duration: time.Hour},
+		},
+	}, konductor.WithServerSideApply())
+	require.NoError(t, err)
+
+	updated, err := Get(client, ctx, "test-barrier")
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), updated.Spec.Expected)
+	assert.Equal(t, time.Hour, updated.Spec.Timeout.Duration)
+	require.NotNil(t, updated.Spec.Quorum)
+	assert.Equal(t, int32(2), *updated.Spec.Quorum)
+}
+
+func TestWaitBarrier_OpensDuringWait(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+		},
+		Status: syncv1.BarrierStatus{
+			Arrived: 1,
+			Phase:   syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		var current syncv1.Barrier
+		if err := client.K8sClient().Get(context.Background(), types.NamespacedName{
+			Name: "test-barrier", Namespace: "test-ns",
+		}, &current); err != nil {
+			return
+		}
+		current.Status.Arrived = 2
+		current.Status.Phase = syncv1.BarrierPhaseOpen
+		_ = client.K8sClient().Update(context.Background(), &current)
+	}()
+
+	start := time.Now()
+	err := Wait(client, context.Background(), "test-barrier", konductor.WithTimeout(10*time.Second))
+	require.NoError(t, err)
+
+	// Polling starts at a 1s interval, so returning sooner proves the watch fired
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWaitBarrier_FailsDuringWait(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		var current syncv1.Barrier
+		if err := client.K8sClient().Get(context.Background(), types.NamespacedName{
+			Name: "test-barrier", Namespace: "test-ns",
+		}, &current); err != nil {
+			return
+		}
+		current.Status.Phase = syncv1.BarrierPhaseFailed
+		_ = client.K8sClient().Update(context.Background(), &current)
+	}()
+
+	err := Wait(client, context.Background(), "test-barrier", konductor.WithTimeout(10*time.Second))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "barrier test-barrier failed")
+}
+
+func TestWaitBarrier_Timeout(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	start := time.Now()
+	err := Wait(client, context.Background(), "test-barrier", konductor.WithTimeout(300*time.Millisecond))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestWaitForCount(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 5,
+		},
+		Status: syncv1.BarrierStatus{
+			Arrived: 1,
+			Phase:   syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	// Arrivals climb one at a time, and the barrier never opens
+	go func() {
+		for arrived := int32(2); arrived <= 3; arrived++ {
+			time.Sleep(100 * time.Millisecond)
+			var current syncv1.Barrier
+			if err := client.K8sClient().Get(context.Background(), types.NamespacedName{
+				Name: "test-barrier", Namespace: "test-ns",
+			}, &current); err != nil {
+				return
+			}
+			current.Status.Arrived = arrived
+			_ = client.K8sClient().Update(context.Background(), &current)
+		}
+	}()
+
+	start := time.Now()
+	err := WaitForCount(client, context.Background(), "test-barrier", 3, 10*time.Second)
+	require.NoError(t, err)
+
+	// Polling starts at a 1s interval, so returning sooner proves the watch fired
+	assert.Less(t, time.Since(start), time.Second)
+
+	current, err := Get(client, context.Background(), "test-barrier")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), current.Status.Arrived)
+	assert.Equal(t, syncv1.BarrierPhaseWaiting, current.Status.Phase)
+}
+
+func TestWaitForCount_AlreadyReached(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 3,
+		},
+		Status: syncv1.BarrierStatus{
+			Arrived: 3,
+			Phase:   syncv1.BarrierPhaseOpen,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	require.NoError(t, WaitForCount(client, context.Background(), "test-barrier", 2, time.Second))
+}
+
+func TestWaitForCount_FailedShort(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 3,
+		},
+		Status: syncv1.BarrierStatus{
+			Arrived: 1,
+			Phase:   syncv1.BarrierPhaseFailed,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	err := WaitForCount(client, context.Background(), "test-barrier", 2, 10*time.Second)
+	require.Error(t, err)
+	assert.EqualError(t, err, "barrier test-barrier failed with 1 of 2 arrivals")
+	assert.False(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestWaitForCount_Timeout(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 3,
+		},
+		Status: syncv1.BarrierStatus{
+			Arrived: 1,
+			Phase:   syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	start := time.Now()
+	err := WaitForCount(client, context.Background(), "test-barrier", 2, 300*time.Millisecond)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestWaitForCount_InvalidCount(t *testing.T) {
+	client := setupTestClient(t)
+
+	err := WaitForCount(client, context.Background(), "test-barrier", 0, time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "count must be greater than zero")
+}
+
+func TestWaitBarrier_ContextCancelledIsNotTimeout(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err := Wait(client, ctx, "test-barrier", konductor.WithTimeout(10*time.Second))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+// setupStatusClient returns a client that serves Barrier status through the
+// status subresource, as the API server does
+func setupStatusClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.Barrier{}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func TestReset(t *testing.T) {
+	openedAt := metav1.Now()
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase:    syncv1.BarrierPhaseOpen,
+			Arrived:  2,
+			Arrivals: []string{"holder-1", "holder-2"},
+			OpenedAt: &openedAt,
+		},
+	}
+
+	client := setupStatusClient(t, barrier)
+	ctx := context.Background()
+
+	require.NoError(t, Arrive(client, ctx, "test-barrier", konductor.WithHolder("holder-1")))
+	require.NoError(t, Arrive(client, ctx, "test-barrier", konductor.WithHolder("holder-2")))
+
+	require.NoError(t, Reset(client, ctx, "test-barrier"))
+
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, client.K8sClient().List(ctx, &arrivals))
+	assert.Empty(t, arrivals.Items)
+
+	status, err := GetStatus(client, ctx, "test-barrier")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.BarrierPhaseWaiting, status.Phase)
+	assert.Equal(t, int32(0), status.Arrived)
+	assert.Empty(t, status.Arrivals)
+	assert.Nil(t, status.OpenedAt)
+	assert.NotNil(t, status.ResetAt)
+
+	// The same holders can arrive again for the next round
+	require.NoError(t, Arrive(client, ctx, "test-barrier", konductor.WithHolder("holder-1")))
+	require.NoError(t, client.K8sClient().List(ctx, &arrivals))
+	assert.Len(t, arrivals.Items, 1)
+}
+
+func TestReset_NotFound(t *testing.T) {
+	client := setupStatusClient(t)
+
+	err := Reset(client, context.Background(), "missing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reset barrier missing")
+}
+
+func TestWaitBarrier_ContextCancelledMidWait(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase: syncv1.BarrierPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, barrier)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	err := Wait(client, ctx, "test-barrier", konductor.WithTimeout(10*time.Second))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Contains(t, err.Error(), "waiting for barrier test-barrier")
+}
+
+func cyclicBarrier(generation int64) *syncv1.Barrier {
+	return &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.BarrierSpec{
+			Expected: 2,
+			Cyclic:   true,
+		},
+		Status: syncv1.BarrierStatus{
+			Phase:      syncv1.BarrierPhaseWaiting,
+			Generation: generation,
+		},
+	}
+}
+
+func TestArriveBarrier_TagsGeneration(t *testing.T) {
+	client := setupTestClient(t, cyclicBarrier(3))
+	ctx := context.Background()
+
+	require.NoError(t, Arrive(client, ctx, "test-barrier", konductor.WithHolder("holder-1")))
+
+	var arrival syncv1.Arrival
+	require.NoError(t, client.K8sClient().Get(ctx, types.NamespacedName{
+		Name: "test-barrier-holder-1-3", Namespace: "test-ns",
+	}, &arrival))
+	assert.Equal(t, int64(3), arrival.Spec.Generation)
+}
+
+func TestWaitBarrier_Generation(t *testing.T) {
+	client := setupTestClient(t, cyclicBarrier(2))
+	ctx := context.Background()
+
+	// Generation 1 has already opened
+	require.NoError(t, Wait(client, ctx, "test-barrier", konductor.WithGeneration(1)))
+
+	// Generation 2 is still in progress
+	err := Wait(client, ctx, "test-barrier", konductor.WithGeneration(2), konductor.WithTimeout(200*time.Millisecond))
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestWaitBarrier_CyclicOpensDuringWait(t *testing.T) {
+	client := setupTestClient(t, cyclicBarrier(0))
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		var current syncv1.Barrier
+		if err := client.K8sClient().Get(context.Background(), types.NamespacedName{
+			Name: "test-barrier", Namespace: "test-ns",
+		}, &current); err != nil {
+			return
+		}
+		// The controller moves straight on to the next round; the barrier is
+		// never seen Open
+		current.Status.Generation = 1
+		_ = client.K8sClient().Update(context.Background(), &current)
+	}()
+
+	err := Wait(client, context.Background(), "test-barrier", konductor.WithTimeout(10*time.Second))
+	require.NoError(t, err)
+}
+
+func TestReset_CyclicKeepsGeneration(t *testing.T) {
+	barrier := cyclicBarrier(4)
+	barrier.Status.Phase = syncv1.BarrierPhaseFailed
+	client := setupStatusClient(t, barrier)
+	ctx := context.Background()
+
+	require.NoError(t, Arrive(client, ctx, "test-barrier", konductor.WithHolder("holder-1")))
+	require.NoError(t, Reset(client, ctx, "test-barrier"))
+
+	var arrivals syncv1.ArrivalList
+	require.NoError(t, client.K8sClient().List(ctx, &arrivals))
+	assert.Empty(t, arrivals.Items)
+
+	status, err := GetStatus(client, ctx, "test-barrier")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.BarrierPhaseWaiting, status.Phase)
+	assert.Equal(t, int64(4), status.Generation)
+}
@@ -0,0 +1,262 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+func wrapError(operation, name string, err error) error {
+	return fmt.Errorf("failed to %s circuit breaker %s: %w", operation, name, err)
+}
+
+// Allow reports whether a request may go ahead. A closed breaker lets every
+// request through and an open one none. A half-open breaker lets through as
+// many trial requests as its success threshold, and each caller allowed one
+// must report how it went with RecordSuccess or RecordFailure.
+func Allow(c *konductor.Client, ctx context.Context, name string) (bool, error) {
+	cb, err := Get(c, ctx, name)
+	if err != nil {
+		return false, err
+	}
+	switch cb.Status.State {
+	case syncv1.CircuitBreakerStateOpen:
+		return false, nil
+	case syncv1.CircuitBreakerStateHalfOpen:
+	default:
+		// Closed, or new and not yet initialised by the controller
+		return true, nil
+	}
+
+	allowed := false
+	err = update(c, ctx, name, func(cb *syncv1.CircuitBreaker) bool {
+		allowed = false
+		switch cb.Status.State {
+		case syncv1.CircuitBreakerStateOpen:
+			return false
+		case syncv1.CircuitBreakerStateHalfOpen:
+			if cb.Status.TrialRequests >= successThreshold(cb) {
+				return false
+			}
+			cb.Status.TrialRequests++
+		}
+		allowed = true
+		return cb.Status.State == syncv1.CircuitBreakerStateHalfOpen
+	})
+	if err != nil {
+		return false, wrapError("check", name, err)
+	}
+	return allowed, nil
+}
+
+// RecordSuccess reports a request that succeeded. It clears the failure
+// count of a closed breaker and closes a half-open one once its success
+// threshold of trial requests has succeeded.
+func RecordSuccess(c *konductor.Client, ctx context.Context, name string) error {
+	err := update(c, ctx, name, func(cb *syncv1.CircuitBreaker) bool {
+		switch cb.Status.State {
+		case syncv1.CircuitBreakerStateOpen:
+			// A request let through before the breaker opened; the reset
+			// timeout decides when to try again
+			return false
+		case syncv1.CircuitBreakerStateHalfOpen:
+			cb.Status.Successes++
+			if cb.Status.Successes >= successThreshold(cb) {
+				setState(cb, syncv1.CircuitBreakerStateClosed)
+			}
+			return true
+		default:
+			if cb.Status.Failures == 0 {
+				return false
+			}
+			cb.Status.Failures = 0
+			return true
+		}
+	})
+	if err != nil {
+		return wrapError("record success on", name, err)
+	}
+	return nil
+}
+
+// RecordFailure reports a request that failed. A closed breaker opens once
+// its failure threshold of consecutive failures is reached, and a half-open
+// one opens again on the first failed trial request.
+func RecordFailure(c *konductor.Client, ctx context.Context, name string) error {
+	err := update(c, ctx, name, func(cb *syncv1.CircuitBreaker) bool {
+		now := metav1.Now()
+		cb.Status.Failures++
+		cb.Status.LastFailureTime = &now
+
+		switch cb.Status.State {
+		case syncv1.CircuitBreakerStateOpen:
+		case syncv1.CircuitBreakerStateHalfOpen:
+			setState(cb, syncv1.CircuitBreakerStateOpen)
+		default:
+			if cb.Status.Failures >= failureThreshold(cb) {
+				setState(cb, syncv1.CircuitBreakerStateOpen)
+			} else {
+				cb.Status.State = syncv1.CircuitBreakerStateClosed
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return wrapError("record failure on", name, err)
+	}
+	return nil
+}
+
+// With runs fn if the breaker allows it and records its outcome, returning
+// ErrCircuitOpen without calling fn otherwise. An error from fn counts as a
+// failure and is returned, joined with any error recording it.
+func With(c *konductor.Client, ctx context.Context, name string, fn func() error) error {
+	allowed, err := Allow(c, ctx, name)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("circuit breaker %s: %w", name, konductor.ErrCircuitOpen)
+	}
+
+	if fnErr := fn(); fnErr != nil {
+		return errors.Join(fnErr, RecordFailure(c, ctx, name))
+	}
+	return RecordSuccess(c, ctx, name)
+}
+
+// update applies fn to the latest version of the breaker and writes its
+// status back if fn reports a change, retrying on conflict
+func update(c *konductor.Client, ctx context.Context, name string, fn func(*syncv1.CircuitBreaker) bool) error {
+	return c.RetryOnConflict(ctx, func() error {
+		var cb syncv1.CircuitBreaker
+		if err := c.K8sClient().Get(ctx, types.NamespacedName{
+			Name: name, Namespace: c.Namespace(),
+		}, &cb); err != nil {
+			return err
+		}
+		if !fn(&cb) {
+			return nil
+		}
+		return c.K8sClient().Status().Update(ctx, &cb)
+	})
+}
+
+// setState moves cb to state, resetting the counters kept for it
+func setState(cb *syncv1.CircuitBreaker, state syncv1.CircuitBreakerState) {
+	now := metav1.Now()
+	cb.Status.State = state
+	cb.Status.StateChangedAt = &now
+	cb.Status.TrialRequests = 0
+	cb.Status.Successes = 0
+	if state == syncv1.CircuitBreakerStateClosed {
+		cb.Status.Failures = 0
+	}
+}
+
+// failureThreshold returns the number of failures that opens the breaker
+func failureThreshold(cb *syncv1.CircuitBreaker) int32 {
+	if cb.Spec.FailureThreshold > 0 {
+		return cb.Spec.FailureThreshold
+	}
+	return syncv1.DefaultCircuitBreakerFailureThreshold
+}
+
+// successThreshold returns the number of successful trials that closes the
+// breaker
+func successThreshold(cb *syncv1.CircuitBreaker) int32 {
+	if cb.Spec.SuccessThreshold > 0 {
+		return cb.Spec.SuccessThreshold
+	}
+	return syncv1.DefaultCircuitBreakerSuccessThreshold
+}
+
+// State returns the current state of the breaker
+func State(c *konductor.Client, ctx context.Context, name string) (syncv1.CircuitBreakerState, error) {
+	cb, err := Get(c, ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if cb.Status.State == "" {
+		return syncv1.CircuitBreakerStateClosed, nil
+	}
+	return cb.Status.State, nil
+}
+
+// Create creates a circuit breaker that opens after failureThreshold
+// consecutive failures and lets trial requests through resetTimeout after
+// opening. Zero values take the defaults of 5 failures and 30 seconds.
+func Create(c *konductor.Client, ctx context.Context, name string, failureThreshold int32, resetTimeout time.Duration, opts ...konductor.Option) error {
+	if failureThreshold < 0 {
+		return fmt.Errorf("failure threshold cannot be negative, got %d", failureThreshold)
+	}
+
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	cb := &syncv1.CircuitBreaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace(),
+		},
+		Spec: syncv1.CircuitBreakerSpec{
+			FailureThreshold: failureThreshold,
+		},
+	}
+
+	if resetTimeout > 0 {
+		cb.Spec.ResetTimeout = &metav1.Duration{Duration: resetTimeout}
+	}
+	if options.TTL > 0 {
+		cb.Spec.TTL = &metav1.Duration{Duration: options.TTL}
+	}
+
+	if err := c.K8sClient().Create(ctx, cb); err != nil {
+		return wrapError("create", name, err)
+	}
+	return nil
+}
+
+func Delete(c *konductor.Client, ctx context.Context, name string) error {
+	cb := &syncv1.CircuitBreaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace(),
+		},
+	}
+	if err := c.K8sClient().Delete(ctx, cb); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return wrapError("delete", name, err)
+		}
+	}
+	return nil
+}
+
+func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.CircuitBreaker, error) {
+	var cb syncv1.CircuitBreaker
+	if err := c.K8sClient().Get(ctx, types.NamespacedName{
+		Name:      name,
+		Namespace: c.Namespace(),
+	}, &cb); err != nil {
+		return nil, wrapError("get", name, err)
+	}
+	return &cb, nil
+}
+
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.CircuitBreaker, error) {
+	var circuitBreakers syncv1.CircuitBreakerList
+	if err := c.K8sClient().List(ctx, &circuitBreakers, c.ListOptions(opts...)...); err != nil {
+		return nil, fmt.Errorf("failed to list circuit breakers: %w", err)
+	}
+	return circuitBreakers.Items, nil
+}
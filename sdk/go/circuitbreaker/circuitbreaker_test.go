@@ -0,0 +1,249 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.CircuitBreaker{}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func newCircuitBreaker(state syncv1.CircuitBreakerState) *syncv1.CircuitBreaker {
+	changedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+	return &syncv1.CircuitBreaker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-circuitbreaker",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.CircuitBreakerSpec{
+			FailureThreshold: 3,
+			SuccessThreshold: 2,
+		},
+		Status: syncv1.CircuitBreakerStatus{
+			State:          state,
+			StateChangedAt: &changedAt,
+		},
+	}
+}
+
+func TestAllow_Closed(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateClosed))
+
+	for i := 0; i < 5; i++ {
+		allowed, err := Allow(client, context.Background(), "test-circuitbreaker")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	cb, err := Get(client, context.Background(), "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), cb.Status.TrialRequests, "a closed breaker does not count requests")
+}
+
+func TestAllow_NotYetInitialised(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(""))
+
+	allowed, err := Allow(client, context.Background(), "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestAllow_Open(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateOpen))
+
+	allowed, err := Allow(client, context.Background(), "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestAllow_HalfOpenLimitsTrialRequests(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateHalfOpen))
+
+	var allowed []bool
+	for i := 0; i < 4; i++ {
+		ok, err := Allow(client, context.Background(), "test-circuitbreaker")
+		require.NoError(t, err)
+		allowed = append(allowed, ok)
+	}
+	assert.Equal(t, []bool{true, true, false, false}, allowed)
+
+	cb, err := Get(client, context.Background(), "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), cb.Status.TrialRequests)
+}
+
+func TestRecordFailure_OpensAtThreshold(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateClosed))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, RecordFailure(client, ctx, "test-circuitbreaker"))
+	}
+	state, err := State(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.CircuitBreakerStateClosed, state)
+
+	require.NoError(t, RecordFailure(client, ctx, "test-circuitbreaker"))
+
+	cb, err := Get(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.CircuitBreakerStateOpen, cb.Status.State)
+	assert.Equal(t, int32(3), cb.Status.Failures)
+	assert.NotNil(t, cb.Status.LastFailureTime)
+	assert.WithinDuration(t, time.Now(), cb.Status.StateChangedAt.Time, 2*time.Second)
+
+	allowed, err := Allow(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestRecordSuccess_ResetsFailures(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateClosed))
+	ctx := context.Background()
+
+	require.NoError(t, RecordFailure(client, ctx, "test-circuitbreaker"))
+	require.NoError(t, RecordFailure(client, ctx, "test-circuitbreaker"))
+	require.NoError(t, RecordSuccess(client, ctx, "test-circuitbreaker"))
+	require.NoError(t, RecordFailure(client, ctx, "test-circuitbreaker"))
+
+	cb, err := Get(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.CircuitBreakerStateClosed, cb.Status.State, "failures must be consecutive to open the breaker")
+	assert.Equal(t, int32(1), cb.Status.Failures)
+}
+
+func TestHalfOpen_ClosesAfterSuccessThreshold(t *testing.T) {
+	cb := newCircuitBreaker(syncv1.CircuitBreakerStateHalfOpen)
+	cb.Status.Failures = 3
+	client := setupTestClient(t, cb)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		allowed, err := Allow(client, ctx, "test-circuitbreaker")
+		require.NoError(t, err)
+		require.True(t, allowed)
+	}
+
+	require.NoError(t, RecordSuccess(client, ctx, "test-circuitbreaker"))
+	state, err := State(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.CircuitBreakerStateHalfOpen, state)
+
+	require.NoError(t, RecordSuccess(client, ctx, "test-circuitbreaker"))
+
+	updated, err := Get(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.CircuitBreakerStateClosed, updated.Status.State)
+	assert.Equal(t, int32(0), updated.Status.Failures)
+	assert.Equal(t, int32(0), updated.Status.TrialRequests)
+}
+
+func TestHalfOpen_FailedTrialReopens(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateHalfOpen))
+	ctx := context.Background()
+
+	allowed, err := Allow(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	require.NoError(t, RecordFailure(client, ctx, "test-circuitbreaker"))
+
+	cb, err := Get(client, ctx, "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.CircuitBreakerStateOpen, cb.Status.State)
+	assert.Equal(t, int32(0), cb.Status.TrialRequests)
+}
+
+func TestWith(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateClosed))
+	ctx := context.Background()
+	errBackend := errors.New("backend unavailable")
+
+	calls := 0
+	for i := 0; i < 3; i++ {
+		err := With(client, ctx, "test-circuitbreaker", func() error {
+			calls++
+			return errBackend
+		})
+		assert.ErrorIs(t, err, errBackend)
+	}
+
+	err := With(client, ctx, "test-circuitbreaker", func() error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, konductor.ErrCircuitOpen)
+	assert.Equal(t, 3, calls, "an open breaker must not call fn")
+}
+
+func TestAllow_NotFound(t *testing.T) {
+	client := setupTestClient(t)
+
+	_, err := Allow(client, context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+func TestCreate(t *testing.T) {
+	client := setupTestClient(t)
+
+	err := Create(client, context.Background(), "test-circuitbreaker", 10, time.Minute, konductor.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	cb, err := Get(client, context.Background(), "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), cb.Spec.FailureThreshold)
+	require.NotNil(t, cb.Spec.ResetTimeout)
+	assert.Equal(t, time.Minute, cb.Spec.ResetTimeout.Duration)
+	require.NotNil(t, cb.Spec.TTL)
+	assert.Equal(t, time.Hour, cb.Spec.TTL.Duration)
+}
+
+func TestCreate_Defaults(t *testing.T) {
+	client := setupTestClient(t)
+
+	require.NoError(t, Create(client, context.Background(), "test-circuitbreaker", 0, 0))
+
+	cb, err := Get(client, context.Background(), "test-circuitbreaker")
+	require.NoError(t, err)
+	assert.Zero(t, cb.Spec.FailureThreshold)
+	assert.Nil(t, cb.Spec.ResetTimeout)
+}
+
+func TestListAndDelete(t *testing.T) {
+	client := setupTestClient(t, newCircuitBreaker(syncv1.CircuitBreakerStateClosed))
+
+	circuitBreakers, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, circuitBreakers, 1)
+	assert.Equal(t, "test-circuitbreaker", circuitBreakers[0].Name)
+
+	require.NoError(t, Delete(client, context.Background(), "test-circuitbreaker"))
+	require.NoError(t, Delete(client, context.Background(), "test-circuitbreaker"))
+
+	_, err = Get(client, context.Background(), "test-circuitbreaker")
+	assert.Error(t, err)
+}
@@ -18,9 +18,16 @@ package client
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
@@ -33,6 +40,7 @@ import (
 type Client struct {
 	k8sClient client.Client
 	namespace string
+	stats     *retryStats
 }
 
 // Config holds client configuration options.
@@ -41,8 +49,15 @@ type Config struct {
 	// Namespace specifies the Kubernetes namespace to operate in.
 	// Defaults to "default" if not specified.
 	Namespace string
-	// Kubeconfig path for authentication (currently unused, uses in-cluster or default config)
+	// Kubeconfig is the path of the kubeconfig file to connect with. When
+	// empty the usual lookup applies: --kubeconfig, $KUBECONFIG, the
+	// in-cluster config, then ~/.kube/config.
 	Kubeconfig string
+	// DisableCache builds a direct client that reads straight from the API
+	// server and maps the konductor kinds itself instead of through
+	// discovery. Every request it makes is then one an operation needs in
+	// Namespace, so a service account with only a Role there is enough.
+	DisableCache bool
 }
 
 // New creates a new konductor client with the specified configuration.
@@ -55,33 +70,88 @@ func New(cfg *Config) (*Client, error) {
 		cfg = &Config{}
 	}
 
-	// Get Kubernetes config
-	k8sConfig, err := config.GetConfig()
+	k8sConfig, err := restConfig(cfg.Kubeconfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
 	}
 
+	return newFromConfig(k8sConfig, cfg.Namespace, cfg.DisableCache)
+}
+
+// NewFromConfig creates a konductor client that connects with restCfg, for
+// talking to a cluster other than the default one or authenticating in a way
+// a kubeconfig cannot express.
+//
+// The namespace parameter specifies which Kubernetes namespace to operate in.
+// If empty, defaults to "default".
+func NewFromConfig(restCfg *rest.Config, namespace string) (*Client, error) {
+	return newFromConfig(restCfg, namespace, false)
+}
+
+func newFromConfig(restCfg *rest.Config, namespace string, disableCache bool) (*Client, error) {
+	if restCfg == nil {
+		return nil, fmt.Errorf("rest config must not be nil")
+	}
+
 	// Build scheme with konductor types
 	scheme := runtime.NewScheme()
 	if err := syncv1.AddToScheme(scheme); err != nil {
 		return nil, fmt.Errorf("failed to add konductor types to scheme: %w", err)
 	}
 
-	// Create Kubernetes client
-	k8sClient, err := client.New(k8sConfig, client.Options{Scheme: scheme})
+	// Create Kubernetes client with watch support for prompt wait operations
+	opts := client.Options{Scheme: scheme}
+	if disableCache {
+		// Options.Cache stays unset, so reads always go to the API server, and
+		// the static mapper spares the discovery requests a Role may not allow
+		opts.Mapper = konductorRESTMapper()
+	}
+	k8sClient, err := client.NewWithWatch(restCfg, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
-	namespace := cfg.Namespace
-	if namespace == "" {
-		namespace = "default"
+	return NewFromClient(k8sClient, namespace), nil
+}
+
+// konductorResources maps each konductor kind to the resource its CRD serves
+var konductorResources = map[string]string{
+	"Semaphore":      "semaphores",
+	"Permit":         "permits",
+	"Barrier":        "barriers",
+	"Arrival":        "arrivals",
+	"Lease":          "leases",
+	"LeaseRequest":   "leaserequests",
+	"Gate":           "gates",
+	"Mutex":          "mutexes",
+	"RWMutex":        "rwmutexes",
+	"Once":           "onces",
+	"WaitGroup":      "waitgroups",
+	"Event":          "events",
+	"RateLimiter":    "ratelimiters",
+	"CircuitBreaker": "circuitbreakers",
+}
+
+// konductorRESTMapper maps the konductor kinds, all namespaced, without
+// asking the API server
+func konductorRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{syncv1.GroupVersion})
+	for kind, resource := range konductorResources {
+		mapper.AddSpecific(syncv1.GroupVersion.WithKind(kind),
+			syncv1.GroupVersion.WithResource(resource),
+			syncv1.GroupVersion.WithResource(strings.ToLower(kind)),
+			meta.RESTScopeNamespace)
 	}
+	return mapper
+}
 
-	return &Client{
-		k8sClient: k8sClient,
-		namespace: namespace,
-	}, nil
+// restConfig loads the rest config from the kubeconfig file at path, or
+// looks one up the way kubectl does when path is empty
+func restConfig(path string) (*rest.Config, error) {
+	if path == "" {
+		return config.GetConfig()
+	}
+	return clientcmd.BuildConfigFromFlags("", path)
 }
 
 // NewFromClient creates a konductor client from an existing Kubernetes client.
@@ -97,6 +167,7 @@ func NewFromClient(k8sClient client.Client, namespace string) *Client {
 	return &Client{
 		k8sClient: k8sClient,
 		namespace: namespace,
+		stats:     &retryStats{},
 	}
 }
 
@@ -116,6 +187,7 @@ func (c *Client) WithNamespace(namespace string) *Client {
 	return &Client{
 		k8sClient: c.k8sClient,
 		namespace: namespace,
+		stats:     c.stats,
 	}
 }
 
@@ -127,7 +199,7 @@ func (c *Client) K8sClient() client.Client {
 
 // ReleaseSemaphorePermit releases a semaphore permit.
 func (c *Client) ReleaseSemaphorePermit(ctx context.Context, semaphoreName, holder string) error {
-	permitName := fmt.Sprintf("%s-%s", semaphoreName, holder)
+	permitName := HolderObjectName(semaphoreName, holder)
 	permit := &syncv1.Permit{}
 	permit.Name = permitName
 	permit.Namespace = c.namespace
@@ -139,7 +211,7 @@ func (c *Client) ReleaseSemaphorePermit(ctx context.Context, semaphoreName, hold
 
 // ReleaseLease releases a lease.
 func (c *Client) ReleaseLease(ctx context.Context, leaseName, holder string) error {
-	requestName := fmt.Sprintf("%s-%s", leaseName, holder)
+	requestName := HolderObjectName(leaseName, holder)
 	request := &syncv1.LeaseRequest{}
 	request.Name = requestName
 	request.Namespace = c.namespace
@@ -149,11 +221,72 @@ func (c *Client) ReleaseLease(ctx context.Context, leaseName, holder string) err
 	return nil
 }
 
-// ListPermits returns all permits for a specific semaphore.
-func (c *Client) ListPermits(ctx context.Context, semaphoreName string) ([]syncv1.Permit, error) {
+// GetOrCreate fetches the primitive named by obj's name in the client's
+// namespace into obj. If it does not exist and autoCreate is set, obj is
+// created as given first, so it should carry the defaults to create with.
+// Otherwise the error wraps ErrNotFound and names the primitive, for example
+// "mutex db-lock not found in namespace default". kind is the primitive name
+// used in errors.
+func (c *Client) GetOrCreate(ctx context.Context, kind string, obj client.Object, autoCreate bool) error {
+	key := types.NamespacedName{Name: obj.GetName(), Namespace: c.namespace}
+	defaults := obj.DeepCopyObject().(client.Object)
+
+	err := c.k8sClient.Get(ctx, key, obj)
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get %s %s: %w", kind, key.Name, err)
+	}
+	if !autoCreate {
+		return fmt.Errorf("%s %s %w in namespace %s: %w", kind, key.Name, ErrNotFound, key.Namespace, err)
+	}
+
+	defaults.SetNamespace(key.Namespace)
+	if err := c.k8sClient.Create(ctx, defaults); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create %s %s: %w", kind, key.Name, err)
+	}
+	// Read back whichever was stored: ours, or one another caller created first
+	if err := c.k8sClient.Get(ctx, key, obj); err != nil {
+		return fmt.Errorf("failed to get %s %s: %w", kind, key.Name, err)
+	}
+	return nil
+}
+
+// ListOptions resolves opts into the options used to list primitives in the
+// client's namespace, or in every namespace with WithAllNamespaces.
+func (c *Client) ListOptions(opts ...Option) []client.ListOption {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var listOpts []client.ListOption
+	if !options.AllNamespaces {
+		listOpts = append(listOpts, client.InNamespace(c.namespace))
+	}
+	if len(options.LabelSelector) > 0 {
+		listOpts = append(listOpts, client.MatchingLabels(options.LabelSelector))
+	}
+	return listOpts
+}
+
+// ListPermits returns all permits for a specific semaphore. A label selector
+// given with WithLabelSelector narrows the result further, e.g. to one holder.
+func (c *Client) ListPermits(ctx context.Context, semaphoreName string, opts ...Option) ([]syncv1.Permit, error) {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	selector := client.MatchingLabels{}
+	for k, v := range options.LabelSelector {
+		selector[k] = v
+	}
+	selector["semaphore"] = semaphoreName
+
 	var permits syncv1.PermitList
-	if err := c.k8sClient.List(ctx, &permits, client.InNamespace(c.namespace),
-		client.MatchingLabels{"semaphore": semaphoreName}); err != nil {
+	if err := c.k8sClient.List(ctx, &permits, client.InNamespace(c.namespace), selector); err != nil {
 		return nil, fmt.Errorf("failed to list permits: %w", err)
 	}
 	return permits.Items, nil
@@ -176,12 +309,45 @@ type Options struct {
 	TTL time.Duration
 	// Timeout specifies how long to wait for an operation to complete
 	Timeout time.Duration
-	// Priority is used for lease acquisition ordering (higher values win)
+	// Priority is used for lease and semaphore acquisition ordering (higher values win)
 	Priority int32
 	// Holder identifies the entity holding a resource (defaults to hostname)
 	Holder string
 	// Quorum specifies minimum arrivals needed to open a barrier
 	Quorum int32
+	// AutoRenew is the interval at which an acquired lease is renewed (0 disables renewal)
+	AutoRenew time.Duration
+	// Reentrant creates a mutex that its holder can lock more than once
+	Reentrant bool
+	// LabelSelector restricts List operations to objects carrying all of these labels
+	LabelSelector map[string]string
+	// AllNamespaces makes List operations span every namespace instead of the client's
+	AllNamespaces bool
+	// Generation is the round of a cyclic barrier to wait for (nil waits for the current one)
+	Generation *int64
+	// Count is the initial counter of a new waitgroup
+	Count int32
+	// Burst is the most tokens a new rate limiter holds (defaults to its rate)
+	Burst int32
+	// Period is the interval over which a new rate limiter adds its rate of tokens
+	Period time.Duration
+	// PollInterval is how often a gate wait checks the gate once it has backed off
+	PollInterval time.Duration
+	// OwnerPod makes the current pod own acquired semaphore permits
+	OwnerPod bool
+	// Weight is how many of a semaphore's permits an acquired permit counts for (0 means 1)
+	Weight int32
+	// ServerSideApply makes Update functions apply only the fields they set
+	ServerSideApply bool
+	// ApplyFields lists the fields a server-side apply sends, as dotted JSON
+	// paths such as "spec.ttl"
+	ApplyFields []string
+	// FieldManager names the writer Update functions record their changes as
+	FieldManager string
+	// AutoCreate makes acquire operations create a missing primitive with defaults
+	AutoCreate bool
+	// SkipConfirm makes mutex.Lock return once its update succeeds, without reading the lock back
+	SkipConfirm bool
 }
 
 // Option is a function that configures Options.
@@ -208,10 +374,27 @@ func NewPermit(client *Client, name, holder string, ctx context.Context) *Permit
 	}
 }
 
+// NewPermitWithID creates a permit instance for the Permit object named
+// permitID, which Release deletes.
+func NewPermitWithID(client *Client, name, holder, permitID string, ctx context.Context) *Permit {
+	permit := NewPermit(client, name, holder, ctx)
+	permit.permitID = permitID
+	return permit
+}
+
 func (p *Permit) Release(ctx context.Context) error {
 	if p.cancelCtx != nil {
 		p.cancelCtx()
 	}
+	if p.permitID != "" {
+		permit := &syncv1.Permit{}
+		permit.Name = p.permitID
+		permit.Namespace = p.client.namespace
+		if err := p.client.k8sClient.Delete(ctx, permit); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to release permit %s for holder %s: %w", p.permitID, p.holder, err)
+		}
+		return nil
+	}
 	if err := p.client.ReleaseSemaphorePermit(ctx, p.name, p.holder); err != nil {
 		if client.IgnoreNotFound(err) != nil {
 			return fmt.Errorf("failed to release permit %s for holder %s: %w", p.name, p.holder, err)
@@ -232,11 +415,12 @@ func (p *Permit) Name() string {
 
 // LeaseHandle represents an acquired lease.
 type LeaseHandle struct {
-	client    *Client
-	name      string
-	holder    string
-	ctx       context.Context
-	cancelCtx context.CancelFunc
+	client     *Client
+	name       string
+	holder     string
+	fenceToken int64
+	ctx        context.Context
+	cancelCtx  context.CancelFunc
 }
 
 // Release releases the lease.
@@ -262,6 +446,11 @@ func (l *LeaseHandle) Name() string {
 	return l.name
 }
 
+// FenceToken returns the fencing token issued when the lease was granted.
+func (l *LeaseHandle) FenceToken() int64 {
+	return l.fenceToken
+}
+
 // WithTTL sets the time-to-live for the operation.
 // Resources with TTL will be automatically cleaned up after expiration.
 //
@@ -286,8 +475,9 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
-// WithPriority sets the priority for lease operations.
-// Higher priority requests will be granted leases before lower priority ones.
+// WithPriority sets the priority for lease and semaphore operations.
+// Higher priority requests will be granted leases and permits before lower
+// priority ones.
 //
 // Example:
 //
@@ -321,3 +511,207 @@ func WithQuorum(quorum int32) Option {
 		o.Quorum = quorum
 	}
 }
+
+// WithCount sets the initial counter of a waitgroup when it is created,
+// as if Add had been called with count.
+//
+// Example:
+//
+//	waitgroup.Create(client, ctx, "workers", client.WithCount(5))
+func WithCount(count int32) Option {
+	return func(o *Options) {
+		o.Count = count
+	}
+}
+
+// WithBurst sets how many tokens a rate limiter holds when it is created,
+// and so how many can be taken at once. It defaults to the rate.
+//
+// Example:
+//
+//	ratelimiter.Create(client, ctx, "external-api", 10, client.WithBurst(20))
+func WithBurst(burst int32) Option {
+	return func(o *Options) {
+		o.Burst = burst
+	}
+}
+
+// WithPeriod sets the interval over which a rate limiter adds its rate of
+// tokens when it is created. It defaults to one second.
+//
+// Example:
+//
+//	ratelimiter.Create(client, ctx, "reports", 100, client.WithPeriod(time.Hour))
+func WithPeriod(period time.Duration) Option {
+	return func(o *Options) {
+		o.Period = period
+	}
+}
+
+// WithPollInterval sets how often gate.Wait and gate.WaitForConditions check
+// the gate once they have backed off. Each poll is jittered by up to 20% so
+// that many waiters spread their requests. It defaults to 10 seconds.
+//
+// Example:
+//
+//	gate.Wait(client, ctx, "deploy-ready", client.WithPollInterval(2*time.Second))
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.PollInterval = interval
+	}
+}
+
+// WithOwnerPod makes the pod the process runs in the owner of the permits it
+// acquires, so Kubernetes garbage-collects them as soon as the pod is deleted
+// rather than when their TTL runs out. The pod is identified by the POD_NAME
+// and POD_UID variables, which must be set through the downward API.
+//
+// Example:
+//
+//	semaphore.Acquire(client, ctx, "api-limit", client.WithOwnerPod())
+func WithOwnerPod() Option {
+	return func(o *Options) {
+		o.OwnerPod = true
+	}
+}
+
+// WithWeight makes an acquired semaphore permit count for weight of the
+// semaphore's permits rather than one, for holders that need a bigger share.
+// It is granted only once that many are available.
+//
+// Example:
+//
+//	semaphore.Acquire(client, ctx, "build-slots", client.WithWeight(3))
+func WithWeight(weight int32) Option {
+	return func(o *Options) {
+		o.Weight = weight
+	}
+}
+
+// WithGeneration waits for a specific round of a cyclic barrier to open,
+// rather than the one in progress when the wait starts. Waiting for a round
+// that has already opened returns straight away.
+//
+// Example:
+//
+//	barrier.Wait(client, ctx, "step", client.WithGeneration(3))
+func WithGeneration(generation int64) Option {
+	return func(o *Options) {
+		o.Generation = &generation
+	}
+}
+
+// WithAutoRenew keeps an acquired lease alive by renewing it at the given interval
+// until it is released or its context is cancelled.
+// The interval should be comfortably shorter than the lease TTL.
+//
+// Example:
+//
+//	lease.Acquire(client, ctx, "singleton", client.WithAutoRenew(30*time.Second))
+func WithAutoRenew(interval time.Duration) Option {
+	return func(o *Options) {
+		o.AutoRenew = interval
+	}
+}
+
+// WithReentrant creates a mutex that the current holder can lock again
+// without waiting. Every Lock must be matched by an Unlock before the mutex
+// is released.
+//
+// Example:
+//
+//	mutex.Create(client, ctx, "config-writer", client.WithReentrant())
+func WithReentrant() Option {
+	return func(o *Options) {
+		o.Reentrant = true
+	}
+}
+
+// WithLabelSelector restricts List operations to objects carrying all of the
+// given labels.
+//
+// Example:
+//
+//	semaphore.List(client, ctx, client.WithLabelSelector(map[string]string{"team": "data"}))
+func WithLabelSelector(selector map[string]string) Option {
+	return func(o *Options) {
+		o.LabelSelector = selector
+	}
+}
+
+// WithAllNamespaces makes List operations return objects from every namespace
+// rather than only the client's.
+//
+// Example:
+//
+//	semaphore.List(client, ctx, client.WithAllNamespaces())
+func WithAllNamespaces() Option {
+	return func(o *Options) {
+		o.AllNamespaces = true
+	}
+}
+
+// WithServerSideApply makes an Update function use a server-side apply, which
+// only sets the fields it sends. Fields other writers changed in the meantime
+// are left alone. The apply sends the fields named in fields as dotted JSON
+// paths, or without any the ones the object passed sets to a non-zero value,
+// so a field can only be applied as false, 0 or "" by naming it.
+//
+// Example:
+//
+//	lease.Update(client, ctx, &syncv1.Lease{
+//		ObjectMeta: metav1.ObjectMeta{Name: "singleton"},
+//		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: time.Minute}},
+//	}, client.WithServerSideApply())
+//
+//	semaphore.Update(client, ctx, &syncv1.Semaphore{
+//		ObjectMeta: metav1.ObjectMeta{Name: "api-quota"},
+//		Spec:       syncv1.SemaphoreSpec{Drain: false},
+//	}, client.WithServerSideApply("spec.drain"))
+func WithServerSideApply(fields ...string) Option {
+	return func(o *Options) {
+		o.ServerSideApply = true
+		o.ApplyFields = fields
+	}
+}
+
+// WithFieldManager sets the field manager an Update function records its
+// changes as. A server-side apply defaults to DefaultFieldManager.
+//
+// Example:
+//
+//	lease.Update(client, ctx, l, client.WithServerSideApply(), client.WithFieldManager("deployer"))
+func WithFieldManager(manager string) Option {
+	return func(o *Options) {
+		o.FieldManager = manager
+	}
+}
+
+// WithAutoCreate makes an acquire or lock operation create the primitive with
+// its defaults if it does not exist yet, instead of failing with ErrNotFound.
+// Settings beyond the defaults, such as a TTL on the primitive itself, need it
+// to be created beforehand with the package's Create function.
+//
+// Example:
+//
+//	mutex.Lock(client, ctx, "config-writer", client.WithAutoCreate())
+func WithAutoCreate() Option {
+	return func(o *Options) {
+		o.AutoCreate = true
+	}
+}
+
+// WithoutConfirm makes mutex.Lock return as soon as its status update is
+// accepted, skipping the read that confirms the caller holds the lock. The
+// confirmation can add up to 2 seconds and is only needed against an API
+// server whose reads may lag its writes, so skip it only when reads are known
+// to see the caller's own writes.
+//
+// Example:
+//
+//	mutex.Lock(client, ctx, "config-writer", client.WithoutConfirm())
+func WithoutConfirm() Option {
+	return func(o *Options) {
+		o.SkipConfirm = true
+	}
+}
@@ -2,14 +2,24 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -79,6 +89,164 @@ func TestNewFromClient(t *testing.T) {
 	}
 }
 
+func TestNewFromConfig(t *testing.T) {
+	// Building a client does not contact the API server, so any host will do
+	restCfg := &rest.Config{Host: "https://konductor.example.com:6443"}
+
+	c, err := NewFromConfig(restCfg, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", c.Namespace())
+	require.NotNil(t, c.K8sClient())
+	_, isWatch := c.K8sClient().(ctrlclient.WithWatch)
+	assert.True(t, isWatch, "waits need a client that can watch")
+	assert.True(t, c.K8sClient().Scheme().Recognizes(syncv1.GroupVersion.WithKind("Semaphore")))
+
+	c, err = NewFromConfig(restCfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, "default", c.Namespace())
+
+	_, err = NewFromConfig(nil, "team-a")
+	assert.Error(t, err)
+}
+
+func TestNew_Kubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com:6443
+users:
+- name: deployer
+  user:
+    token: secret
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    user: deployer
+current-context: staging
+`), 0o600))
+
+	restCfg, err := restConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com:6443", restCfg.Host)
+	assert.Equal(t, "secret", restCfg.BearerToken)
+
+	c, err := New(&Config{Kubeconfig: path, Namespace: "staging"})
+	require.NoError(t, err)
+	assert.Equal(t, "staging", c.Namespace())
+
+	_, err = New(&Config{Kubeconfig: filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get kubeconfig")
+}
+
+// namespacedOnlyServer serves konductor objects in namespace and forbids
+// everything else, like an API server to a service account with only a Role
+// there. It returns the server and the paths it forbade.
+func namespacedOnlyServer(t *testing.T, namespace string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var forbidden []string
+	prefix := "/apis/sync.konductor.io/v1/namespaces/" + namespace + "/"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == prefix+"mutexes/migrations":
+			_ = json.NewEncoder(w).Encode(&syncv1.Mutex{
+				TypeMeta:   metav1.TypeMeta{APIVersion: syncv1.GroupVersion.String(), Kind: "Mutex"},
+				ObjectMeta: metav1.ObjectMeta{Name: "migrations", Namespace: namespace},
+				Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseLocked, Holder: "pod-1"},
+			})
+		case r.URL.Path == prefix+"permits":
+			_ = json.NewEncoder(w).Encode(&syncv1.PermitList{
+				TypeMeta: metav1.TypeMeta{APIVersion: syncv1.GroupVersion.String(), Kind: "PermitList"},
+			})
+		default:
+			mu.Lock()
+			forbidden = append(forbidden, r.URL.Path)
+			mu.Unlock()
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(&metav1.Status{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+				Status:   metav1.StatusFailure,
+				Reason:   metav1.StatusReasonForbidden,
+				Code:     http.StatusForbidden,
+			})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), forbidden...)
+	}
+}
+
+func TestNew_DisableCache(t *testing.T) {
+	srv, forbidden := namespacedOnlyServer(t, "team-a")
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: team-a
+  cluster:
+    server: `+srv.URL+`
+users:
+- name: worker
+  user:
+    token: secret
+contexts:
+- name: team-a
+  context:
+    cluster: team-a
+    user: worker
+current-context: team-a
+`), 0o600))
+	ctx := context.Background()
+
+	c, err := New(&Config{Kubeconfig: path, Namespace: "team-a", DisableCache: true})
+	require.NoError(t, err)
+
+	var mutex syncv1.Mutex
+	require.NoError(t, c.K8sClient().Get(ctx, types.NamespacedName{Name: "migrations", Namespace: "team-a"}, &mutex))
+	assert.Equal(t, "pod-1", mutex.Status.Holder)
+	_, err = c.ListPermits(ctx, "db-pool")
+	require.NoError(t, err)
+	assert.Empty(t, forbidden(), "only requests within the namespace are made")
+
+	// Without it the kinds are looked up through discovery first, which the
+	// Role does not allow
+	c, err = New(&Config{Kubeconfig: path, Namespace: "team-a"})
+	require.NoError(t, err)
+	err = c.K8sClient().Get(ctx, types.NamespacedName{Name: "migrations", Namespace: "team-a"}, &mutex)
+	require.Error(t, err)
+	assert.NotEmpty(t, forbidden())
+}
+
+func TestKonductorRESTMapper(t *testing.T) {
+	mapper := konductorRESTMapper()
+
+	// Every kind in the scheme is mapped, so a new kind cannot be missed
+	for kind, typ := range setupTestScheme(t).KnownTypes(syncv1.GroupVersion) {
+		obj, ok := reflect.New(typ).Interface().(ctrlclient.Object)
+		if !ok || meta.IsListType(obj) {
+			continue
+		}
+		mapping, err := mapper.RESTMapping(syncv1.GroupVersion.WithKind(kind).GroupKind(), syncv1.GroupVersion.Version)
+		require.NoError(t, err, kind)
+		assert.Equal(t, meta.RESTScopeNameNamespace, mapping.Scope.Name(), kind)
+	}
+
+	mapping, err := mapper.RESTMapping(syncv1.GroupVersion.WithKind("RWMutex").GroupKind())
+	require.NoError(t, err)
+	assert.Equal(t, "rwmutexes", mapping.Resource.Resource)
+}
+
 func TestClient_WithNamespace(t *testing.T) {
 	scheme := setupTestScheme(t)
 
@@ -106,6 +274,35 @@ func TestOptions(t *testing.T) {
 	assert.Equal(t, "test-holder", opts.Holder)
 }
 
+func TestClient_GetOrCreate(t *testing.T) {
+	scheme := setupTestScheme(t)
+	existing := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 5},
+	}
+	client := NewFromClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build(), "default")
+	ctx := context.Background()
+
+	// An existing primitive is fetched, not overwritten with the defaults
+	sem := &syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "existing"}, Spec: syncv1.SemaphoreSpec{Permits: 1}}
+	require.NoError(t, client.GetOrCreate(ctx, "semaphore", sem, true))
+	assert.Equal(t, int32(5), sem.Spec.Permits)
+
+	missing := &syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "missing"}, Spec: syncv1.SemaphoreSpec{Permits: 2}}
+	err := client.GetOrCreate(ctx, "semaphore", missing, false)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.True(t, errors.IsNotFound(err))
+	assert.Contains(t, err.Error(), "semaphore missing not found in namespace default")
+
+	require.NoError(t, client.GetOrCreate(ctx, "semaphore", missing, true))
+	assert.Equal(t, "default", missing.Namespace)
+	assert.Equal(t, int32(2), missing.Spec.Permits)
+
+	var stored syncv1.Semaphore
+	require.NoError(t, client.K8sClient().Get(ctx, types.NamespacedName{Name: "missing", Namespace: "default"}, &stored))
+	assert.Equal(t, int32(2), stored.Spec.Permits)
+}
+
 func TestClient_ReleaseSemaphorePermit(t *testing.T) {
 	scheme := setupTestScheme(t)
 
@@ -140,6 +337,42 @@ func TestClient_ReleaseSemaphorePermit(t *testing.T) {
 	assert.True(t, errors.IsNotFound(err))
 }
 
+func TestPermit_ReleaseDeletesItsPermit(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	// Permits created by Acquire carry a unique suffix after the holder
+	permit := &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-semaphore-test-holder-1700000000",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.PermitSpec{
+			Semaphore: "test-semaphore",
+			Holder:    "test-holder",
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(permit).
+		Build()
+
+	client := NewFromClient(k8sClient, "test-ns")
+	handle := NewPermitWithID(client, "test-semaphore", "test-holder", permit.Name, context.Background())
+
+	require.NoError(t, handle.Release(context.Background()))
+
+	var retrievedPermit syncv1.Permit
+	err := k8sClient.Get(context.Background(), types.NamespacedName{
+		Name:      permit.Name,
+		Namespace: "test-ns",
+	}, &retrievedPermit)
+	assert.True(t, errors.IsNotFound(err))
+
+	// Releasing again is not an error
+	assert.NoError(t, handle.Release(context.Background()))
+}
+
 func TestClient_ReleaseLease(t *testing.T) {
 	scheme := setupTestScheme(t)
 
@@ -174,6 +407,39 @@ func TestClient_ReleaseLease(t *testing.T) {
 	assert.True(t, errors.IsNotFound(err))
 }
 
+func TestClient_ReleaseLease_SanitizedHolder(t *testing.T) {
+	scheme := setupTestScheme(t)
+
+	// Holders that are not valid in an object name are rewritten the same way
+	// when the request is created
+	request := &syncv1.LeaseRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HolderObjectName("test-lease", "Worker/1"),
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.LeaseRequestSpec{
+			Lease:  "test-lease",
+			Holder: "Worker/1",
+		},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(request).
+		Build()
+
+	client := NewFromClient(k8sClient, "test-ns")
+
+	require.NoError(t, client.ReleaseLease(context.Background(), "test-lease", "Worker/1"))
+
+	var retrievedRequest syncv1.LeaseRequest
+	err := k8sClient.Get(context.Background(), types.NamespacedName{
+		Name:      request.Name,
+		Namespace: "test-ns",
+	}, &retrievedRequest)
+	assert.True(t, errors.IsNotFound(err))
+}
+
 func TestClient_ListPermits(t *testing.T) {
 	scheme := setupTestScheme(t)
 
@@ -214,6 +480,18 @@ func TestClient_ListPermits(t *testing.T) {
 
 	assertContainsHolder(t, permits, "holder1")
 	assertContainsHolder(t, permits, "holder2")
+
+	permit1.Labels["holder"] = "holder1"
+	k8sClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(permit1, permit2).
+		Build()
+	client = NewFromClient(k8sClient, "test-ns")
+
+	permits, err = client.ListPermits(context.Background(), "test-sem", WithLabelSelector(map[string]string{"holder": "holder1"}))
+	require.NoError(t, err)
+	require.Len(t, permits, 1)
+	assert.Equal(t, "holder1", permits[0].Spec.Holder)
 }
 
 func TestClient_ListLeaseRequests(t *testing.T) {
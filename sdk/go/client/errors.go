@@ -0,0 +1,70 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors wrapped by the primitive packages so callers can match
+// failures with errors.Is instead of inspecting messages.
+var (
+	// ErrTimeout is returned when the timeout set with WithTimeout elapses
+	// before an operation completes.
+	ErrTimeout = errors.New("timeout")
+
+	// ErrNotHolder is returned when releasing or renewing a lock or lease that
+	// the caller does not hold.
+	ErrNotHolder = errors.New("not the holder")
+
+	// ErrDenied is returned when the controller rejects a request.
+	ErrDenied = errors.New("denied")
+
+	// ErrLocked is returned when a lock is held by someone else.
+	ErrLocked = errors.New("locked")
+
+	// ErrNoPermits is returned when a semaphore has no permit available for
+	// a request that does not wait for one.
+	ErrNoPermits = errors.New("no permits available")
+
+	// ErrExpired is returned when renewing a lease whose TTL has already
+	// elapsed.
+	ErrExpired = errors.New("expired")
+
+	// ErrDraining is returned when acquiring a permit from a semaphore that
+	// is being drained for maintenance.
+	ErrDraining = errors.New("draining")
+
+	// ErrWouldBlock is returned when a non-blocking acquisition cannot
+	// succeed without waiting.
+	ErrWouldBlock = errors.New("would block")
+
+	// ErrNegativeCounter is returned when a waitgroup update would take its
+	// counter below zero.
+	ErrNegativeCounter = errors.New("counter cannot go below zero")
+
+	// ErrNotExecuted is returned when reading the result of a once that has
+	// not been executed yet.
+	ErrNotExecuted = errors.New("not executed")
+
+	// ErrCircuitOpen is returned when a circuit breaker does not let a
+	// request through.
+	ErrCircuitOpen = errors.New("circuit open")
+
+	// ErrUnexpectedPhase is returned by a conditional transition, such as
+	// gate.OpenIf, when the primitive is not in the phase it expects.
+	ErrUnexpectedPhase = errors.New("unexpected phase")
+
+	// ErrNoQuorum is returned by a multi-cluster acquisition that succeeded
+	// on fewer than a majority of clusters.
+	ErrNoQuorum = errors.New("no quorum")
+
+	// ErrNotFound is returned when acquiring or locking a primitive that does
+	// not exist, unless WithAutoCreate is given.
+	ErrNotFound = errors.New("not found")
+)
+
+// ErrAcquireTimeout is returned when the timeout set with WithTimeout elapses
+// before a permit is granted. Expiry of the caller's context is reported as
+// the context's own error instead, so the two cases can be told apart with
+// errors.Is. It also matches ErrTimeout.
+var ErrAcquireTimeout = fmt.Errorf("%w waiting to acquire", ErrTimeout)
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// ExpiredCounts is how many expired holds CollectExpired cleared, by kind
+type ExpiredCounts struct {
+	// Mutexes is the number of mutexes unlocked
+	Mutexes int
+	// RWMutexes is the number of RWMutexes unlocked
+	RWMutexes int
+	// Leases is the number of leases freed
+	Leases int
+	// Permits is the number of permits deleted
+	Permits int
+	// Semaphores is the number of semaphores whose counts were recomputed
+	// after their permits were deleted
+	Semaphores int
+}
+
+// Total returns the number of mutexes, RWMutexes, leases and permits cleared.
+func (e ExpiredCounts) Total() int {
+	return e.Mutexes + e.RWMutexes + e.Leases + e.Permits
+}
+
+// CollectExpired does the expiry work of the controller for every mutex,
+// RWMutex, lease and semaphore permit in the client's namespace, for
+// recovering after the controller was down. Locks and leases whose ExpiresAt
+// has passed are released, expired permits are deleted, and the semaphores
+// they belonged to have their in-use and available counts recomputed from
+// the permits that are left. Holds that are renewed while it runs are kept.
+func (c *Client) CollectExpired(ctx context.Context) (*ExpiredCounts, error) {
+	counts := &ExpiredCounts{}
+	now := time.Now()
+
+	var err error
+	if counts.Mutexes, err = c.collectExpiredMutexes(ctx, now); err != nil {
+		return counts, err
+	}
+	if counts.RWMutexes, err = c.collectExpiredRWMutexes(ctx, now); err != nil {
+		return counts, err
+	}
+	if counts.Leases, err = c.collectExpiredLeases(ctx, now); err != nil {
+		return counts, err
+	}
+	if counts.Permits, counts.Semaphores, err = c.collectExpiredPermits(ctx, now); err != nil {
+		return counts, err
+	}
+	return counts, nil
+}
+
+// expiredAt reports whether a hold that expires at expiresAt has expired by
+// now. A hold without an expiry never does.
+func expiredAt(expiresAt *metav1.Time, now time.Time) bool {
+	return expiresAt != nil && !expiresAt.After(now)
+}
+
+// clearExpired re-reads obj and, if expired still holds for it, applies
+// release and updates its status, retrying on conflicts. It reports whether
+// the status was cleared.
+func (c *Client) clearExpired(ctx context.Context, obj client.Object, expired func() bool, release func()) (bool, error) {
+	cleared := false
+	err := c.RetryOnConflict(ctx, func() error {
+		cleared = false
+		if err := c.k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !expired() {
+			return nil
+		}
+		release()
+		if err := c.k8sClient.Status().Update(ctx, obj); err != nil {
+			return err
+		}
+		cleared = true
+		return nil
+	})
+	return cleared, err
+}
+
+func (c *Client) collectExpiredMutexes(ctx context.Context, now time.Time) (int, error) {
+	var mutexes syncv1.MutexList
+	if err := c.k8sClient.List(ctx, &mutexes, client.InNamespace(c.namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list mutexes: %w", err)
+	}
+
+	collected := 0
+	for i := range mutexes.Items {
+		mutex := &mutexes.Items[i]
+		if !expiredAt(mutex.Status.ExpiresAt, now) {
+			continue
+		}
+		cleared, err := c.clearExpired(ctx, mutex, func() bool {
+			return expiredAt(mutex.Status.ExpiresAt, now)
+		}, func() {
+			mutex.Status.Phase = syncv1.MutexPhaseUnlocked
+			mutex.Status.Holder = ""
+			mutex.Status.LockedAt = nil
+			mutex.Status.ExpiresAt = nil
+			mutex.Status.HoldCount = 0
+		})
+		if err != nil {
+			return collected, fmt.Errorf("failed to unlock expired mutex %s: %w", mutex.Name, err)
+		}
+		if cleared {
+			collected++
+		}
+	}
+	return collected, nil
+}
+
+func (c *Client) collectExpiredRWMutexes(ctx context.Context, now time.Time) (int, error) {
+	var rwmutexes syncv1.RWMutexList
+	if err := c.k8sClient.List(ctx, &rwmutexes, client.InNamespace(c.namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list rwmutexes: %w", err)
+	}
+
+	collected := 0
+	for i := range rwmutexes.Items {
+		rwmutex := &rwmutexes.Items[i]
+		if !expiredAt(rwmutex.Status.ExpiresAt, now) {
+			continue
+		}
+		cleared, err := c.clearExpired(ctx, rwmutex, func() bool {
+			return expiredAt(rwmutex.Status.ExpiresAt, now)
+		}, func() {
+			rwmutex.Status.Phase = syncv1.RWMutexPhaseUnlocked
+			rwmutex.Status.WriteHolder = ""
+			rwmutex.Status.ReadHolders = nil
+			rwmutex.Status.LockedAt = nil
+			rwmutex.Status.ExpiresAt = nil
+		})
+		if err != nil {
+			return collected, fmt.Errorf("failed to unlock expired rwmutex %s: %w", rwmutex.Name, err)
+		}
+		if cleared {
+			collected++
+		}
+	}
+	return collected, nil
+}
+
+func (c *Client) collectExpiredLeases(ctx context.Context, now time.Time) (int, error) {
+	var leases syncv1.LeaseList
+	if err := c.k8sClient.List(ctx, &leases, client.InNamespace(c.namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list leases: %w", err)
+	}
+
+	collected := 0
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if !expiredAt(lease.Status.ExpiresAt, now) {
+			continue
+		}
+		// The fence token is kept, so the next holder still gets a higher one
+		cleared, err := c.clearExpired(ctx, lease, func() bool {
+			return expiredAt(lease.Status.ExpiresAt, now)
+		}, func() {
+			lease.Status.Phase = syncv1.LeasePhaseAvailable
+			lease.Status.Holder = ""
+			lease.Status.AcquiredAt = nil
+			lease.Status.ExpiresAt = nil
+		})
+		if err != nil {
+			return collected, fmt.Errorf("failed to free expired lease %s: %w", lease.Name, err)
+		}
+		if cleared {
+			collected++
+		}
+	}
+	return collected, nil
+}
+
+// collectExpiredPermits deletes the expired permits and recomputes the counts
+// of the semaphores they belonged to. It returns the number of permits
+// deleted and of semaphores recomputed.
+func (c *Client) collectExpiredPermits(ctx context.Context, now time.Time) (int, int, error) {
+	var permits syncv1.PermitList
+	if err := c.k8sClient.List(ctx, &permits, client.InNamespace(c.namespace)); err != nil {
+		return 0, 0, fmt.Errorf("failed to list permits: %w", err)
+	}
+
+	deleted := 0
+	affected := map[string]bool{}
+	for i := range permits.Items {
+		permit := &permits.Items[i]
+		if !expiredAt(permit.Status.ExpiresAt, now) {
+			continue
+		}
+		if err := c.k8sClient.Delete(ctx, permit); client.IgnoreNotFound(err) != nil {
+			return deleted, 0, fmt.Errorf("failed to delete expired permit %s: %w", permit.Name, err)
+		}
+		deleted++
+		affected[permit.Spec.Semaphore] = true
+	}
+
+	names := make([]string, 0, len(affected))
+	for name := range affected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	recomputed := 0
+	for _, name := range names {
+		ok, err := c.recomputeSemaphore(ctx, name)
+		if err != nil {
+			return deleted, recomputed, fmt.Errorf("failed to recompute semaphore %s: %w", name, err)
+		}
+		if ok {
+			recomputed++
+		}
+	}
+	return deleted, recomputed, nil
+}
+
+// recomputeSemaphore sets the named semaphore's in-use and available counts,
+// and the phase that follows from them, from its granted permits. It reports
+// false if the semaphore no longer exists.
+func (c *Client) recomputeSemaphore(ctx context.Context, name string) (bool, error) {
+	found := true
+	err := c.RetryOnConflict(ctx, func() error {
+		semaphore := &syncv1.Semaphore{}
+		if err := c.k8sClient.Get(ctx, client.ObjectKey{Name: name, Namespace: c.namespace}, semaphore); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				found = false
+				return nil
+			}
+			return err
+		}
+
+		permits, err := c.ListPermits(ctx, name)
+		if err != nil {
+			return err
+		}
+		inUse := int32(0)
+		for _, permit := range permits {
+			if permit.Status.Phase != syncv1.PermitPhaseGranted {
+				continue
+			}
+			// Permits created before weights existed count for one
+			inUse += max(permit.Spec.Weight, 1)
+		}
+
+		semaphore.Status.InUse = inUse
+		semaphore.Status.Available = max(semaphore.Spec.Permits-inUse, 0)
+		switch {
+		case semaphore.Spec.Drain:
+			semaphore.Status.Phase = syncv1.SemaphorePhaseDraining
+		case semaphore.Status.Available > 0:
+			semaphore.Status.Phase = syncv1.SemaphorePhaseReady
+		default:
+			semaphore.Status.Phase = syncv1.SemaphorePhaseFull
+		}
+		return c.k8sClient.Status().Update(ctx, semaphore)
+	})
+	return found, err
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// gcPermit returns a granted permit on test-sem expiring at expiresAt
+func gcPermit(name string, weight int32, expiresAt time.Time) *syncv1.Permit {
+	expiry := metav1.NewTime(expiresAt)
+	return &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-ns",
+			Labels:    map[string]string{"semaphore": "test-sem"},
+		},
+		Spec:   syncv1.PermitSpec{Semaphore: "test-sem", Holder: name, Weight: weight},
+		Status: syncv1.PermitStatus{Phase: syncv1.PermitPhaseGranted, ExpiresAt: &expiry},
+	}
+}
+
+func TestClient_CollectExpired(t *testing.T) {
+	scheme := setupTestScheme(t)
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	future := metav1.NewTime(time.Now().Add(time.Hour))
+
+	expiredMutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "expired-mutex", Namespace: "test-ns"},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseLocked, Holder: "worker-1", LockedAt: &past, ExpiresAt: &past,
+			HoldCount: 2, FenceToken: 4,
+		},
+	}
+	liveMutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "live-mutex", Namespace: "test-ns"},
+		Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseLocked, Holder: "worker-2", ExpiresAt: &future},
+	}
+	otherNamespace := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "expired-mutex", Namespace: "other-ns"},
+		Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseLocked, Holder: "worker-3", ExpiresAt: &past},
+	}
+	rwmutex := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "expired-rwmutex", Namespace: "test-ns"},
+		Status: syncv1.RWMutexStatus{
+			Phase: syncv1.RWMutexPhaseReadLocked, ReadHolders: []string{"reader-1", "reader-2"}, ExpiresAt: &past,
+		},
+	}
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "expired-lease", Namespace: "test-ns"},
+		Status: syncv1.LeaseStatus{
+			Phase: syncv1.LeasePhaseHeld, Holder: "leader-1", AcquiredAt: &past, ExpiresAt: &past, FenceToken: 7,
+		},
+	}
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "test-ns"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 4},
+		Status:     syncv1.SemaphoreStatus{InUse: 4, Available: 0, Phase: syncv1.SemaphorePhaseFull},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(expiredMutex, liveMutex, otherNamespace, rwmutex, lease, semaphore,
+			gcPermit("expired-1", 0, past.Time), gcPermit("expired-2", 2, past.Time), gcPermit("live", 1, future.Time)).
+		WithStatusSubresource(&syncv1.Mutex{}, &syncv1.RWMutex{}, &syncv1.Lease{}, &syncv1.Semaphore{}, &syncv1.Permit{}).
+		Build()
+	c := NewFromClient(k8sClient, "test-ns")
+	ctx := context.Background()
+
+	counts, err := c.CollectExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &ExpiredCounts{Mutexes: 1, RWMutexes: 1, Leases: 1, Permits: 2, Semaphores: 1}, counts)
+	assert.Equal(t, 5, counts.Total())
+
+	var m syncv1.Mutex
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "expired-mutex", Namespace: "test-ns"}, &m))
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, m.Status.Phase)
+	assert.Empty(t, m.Status.Holder)
+	assert.Nil(t, m.Status.ExpiresAt)
+	assert.Zero(t, m.Status.HoldCount)
+	assert.Equal(t, int64(4), m.Status.FenceToken)
+
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "live-mutex", Namespace: "test-ns"}, &m))
+	assert.Equal(t, "worker-2", m.Status.Holder, "a mutex that has not expired is kept")
+
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "expired-mutex", Namespace: "other-ns"}, &m))
+	assert.Equal(t, "worker-3", m.Status.Holder, "other namespaces are left alone")
+
+	var rw syncv1.RWMutex
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "expired-rwmutex", Namespace: "test-ns"}, &rw))
+	assert.Equal(t, syncv1.RWMutexPhaseUnlocked, rw.Status.Phase)
+	assert.Empty(t, rw.Status.ReadHolders)
+
+	var l syncv1.Lease
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "expired-lease", Namespace: "test-ns"}, &l))
+	assert.Equal(t, syncv1.LeasePhaseAvailable, l.Status.Phase)
+	assert.Empty(t, l.Status.Holder)
+	assert.Nil(t, l.Status.ExpiresAt)
+	assert.Equal(t, int64(7), l.Status.FenceToken)
+
+	var permit syncv1.Permit
+	for _, name := range []string{"expired-1", "expired-2"} {
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "test-ns"}, &permit)
+		assert.True(t, errors.IsNotFound(err), name)
+	}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "live", Namespace: "test-ns"}, &permit))
+
+	var s syncv1.Semaphore
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "test-sem", Namespace: "test-ns"}, &s))
+	assert.Equal(t, int32(1), s.Status.InUse)
+	assert.Equal(t, int32(3), s.Status.Available)
+	assert.Equal(t, syncv1.SemaphorePhaseReady, s.Status.Phase)
+
+	// Nothing is left to collect on a second run
+	counts, err = c.CollectExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, counts.Total())
+	assert.Zero(t, counts.Semaphores)
+}
+
+func TestClient_CollectExpired_KeepsRenewedLock(t *testing.T) {
+	scheme := setupTestScheme(t)
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "renewed", Namespace: "test-ns"},
+		Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseLocked, Holder: "worker-1", ExpiresAt: &past},
+	}
+
+	// The holder renews between the list and the re-read before the update
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Get(ctx, key, obj, opts...); err != nil {
+					return err
+				}
+				if m, ok := obj.(*syncv1.Mutex); ok {
+					renewed := metav1.NewTime(time.Now().Add(time.Hour))
+					m.Status.ExpiresAt = &renewed
+				}
+				return nil
+			},
+		}).
+		Build()
+
+	counts, err := NewFromClient(k8sClient, "test-ns").CollectExpired(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, counts.Mutexes)
+
+	var stored syncv1.Mutex
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "renewed", Namespace: "test-ns"}, &stored))
+	assert.Equal(t, "worker-1", stored.Status.Holder)
+}
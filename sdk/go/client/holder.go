@@ -0,0 +1,78 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// HolderFromEnv identifies the current process from its environment. Inside
+// a pod it prefers the POD_NAME and POD_UID variables set through the
+// downward API, which together stay unique even when a pod is recreated with
+// the same name, then POD_NAME alone, then HOSTNAME. It returns an empty
+// string when none of these are set.
+func HolderFromEnv() string {
+	if podName := os.Getenv("POD_NAME"); podName != "" {
+		if podUID := os.Getenv("POD_UID"); podUID != "" {
+			return fmt.Sprintf("%s-%s", podName, podUID)
+		}
+		return podName
+	}
+	return os.Getenv("HOSTNAME")
+}
+
+// DefaultHolder returns the holder used when an operation is not given one
+// with WithHolder: HolderFromEnv, or a random identifier when the environment
+// does not identify the process.
+func DefaultHolder() string {
+	if holder := HolderFromEnv(); holder != "" {
+		return holder
+	}
+	return fmt.Sprintf("sdk-%s", uuid.NewString())
+}
+
+// holderHashLength is how many hex digits of the hash HolderObjectName
+// appends to a name it had to rewrite
+const holderHashLength = 8
+
+// HolderObjectName returns the name of the object, such as a LeaseRequest or
+// an Arrival, that holder creates for the primitive called name. It is
+// "<name>-<holder>" whenever that is a valid object name. Otherwise it is
+// lowercased, every character other than a letter, digit or '-' becomes '-'
+// and it is cut to length, and a hash of "<name>-<holder>" is appended so
+// holders that only differ in those characters still get distinct objects.
+// The same name and holder always give the same object name, so release
+// finds what acquire created.
+func HolderObjectName(name, holder string) string {
+	raw := name + "-" + holder
+	if len(validation.IsDNS1123Subdomain(raw)) == 0 {
+		return raw
+	}
+
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, raw)
+	if limit := validation.DNS1123SubdomainMaxLength - holderHashLength - 1; len(sanitized) > limit {
+		sanitized = sanitized[:limit]
+	}
+	sanitized = strings.Trim(sanitized, "-")
+
+	sum := sha256.Sum256([]byte(raw))
+	hash := hex.EncodeToString(sum[:])[:holderHashLength]
+	if sanitized == "" {
+		return hash
+	}
+	return sanitized + "-" + hash
+}
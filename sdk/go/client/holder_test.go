@@ -0,0 +1,105 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestDefaultHolder(t *testing.T) {
+	tests := []struct {
+		name     string
+		podName  string
+		podUID   string
+		hostname string
+		expected string
+	}{
+		{
+			name:     "pod name and uid",
+			podName:  "worker-0",
+			podUID:   "9b8c7d6e-1111-2222-3333-444455556666",
+			hostname: "worker-0",
+			expected: "worker-0-9b8c7d6e-1111-2222-3333-444455556666",
+		},
+		{
+			name:     "pod name without uid",
+			podName:  "worker-0",
+			hostname: "host-1",
+			expected: "worker-0",
+		},
+		{
+			name:     "uid without pod name falls back to hostname",
+			podUID:   "9b8c7d6e-1111-2222-3333-444455556666",
+			hostname: "host-1",
+			expected: "host-1",
+		},
+		{
+			name:     "hostname only",
+			hostname: "host-1",
+			expected: "host-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POD_NAME", tt.podName)
+			t.Setenv("POD_UID", tt.podUID)
+			t.Setenv("HOSTNAME", tt.hostname)
+
+			assert.Equal(t, tt.expected, HolderFromEnv())
+			assert.Equal(t, tt.expected, DefaultHolder())
+		})
+	}
+}
+
+func TestDefaultHolder_NoEnvironment(t *testing.T) {
+	t.Setenv("POD_NAME", "")
+	t.Setenv("POD_UID", "")
+	t.Setenv("HOSTNAME", "")
+
+	assert.Empty(t, HolderFromEnv())
+
+	first := DefaultHolder()
+	require.True(t, strings.HasPrefix(first, "sdk-"))
+	_, err := uuid.Parse(strings.TrimPrefix(first, "sdk-"))
+	assert.NoError(t, err)
+
+	// Processes started at the same moment still get distinct holders
+	assert.NotEqual(t, first, DefaultHolder())
+}
+
+func TestHolderObjectName(t *testing.T) {
+	// Names that are already valid are kept as they were
+	assert.Equal(t, "deploy-worker-0", HolderObjectName("deploy", "worker-0"))
+	assert.Equal(t, "deploy-10.0.0.1", HolderObjectName("deploy", "10.0.0.1"))
+
+	tests := []struct {
+		name   string
+		holder string
+		prefix string
+	}{
+		{name: "uppercase", holder: "Worker-1", prefix: "deploy-worker-1-"},
+		{name: "slash", holder: "ns/worker_1", prefix: "deploy-ns-worker-1-"},
+		{name: "spaces", holder: "build job #7", prefix: "deploy-build-job--7-"},
+		{name: "trailing special character", holder: "worker:", prefix: "deploy-worker-"},
+		{name: "too long", holder: strings.Repeat("w", 300), prefix: "deploy-www"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := HolderObjectName("deploy", tt.holder)
+			assert.Empty(t, validation.IsDNS1123Subdomain(name))
+			assert.True(t, strings.HasPrefix(name, tt.prefix), name)
+			assert.Equal(t, name, HolderObjectName("deploy", tt.holder), "the same holder always maps to the same name")
+		})
+	}
+
+	// Holders that only differ in characters that get rewritten stay apart
+	assert.NotEqual(t, HolderObjectName("deploy", "Worker-1"), HolderObjectName("deploy", "worker-1"))
+	assert.NotEqual(t, HolderObjectName("deploy", "ns/worker"), HolderObjectName("deploy", "ns_worker"))
+	assert.NotEqual(t, HolderObjectName("deploy", strings.Repeat("w", 300)), HolderObjectName("deploy", strings.Repeat("w", 301)))
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MultiClient fans operations out to several clusters, one Client each, so a
+// primitive can be held on a majority of them and survive losing a cluster.
+type MultiClient struct {
+	clients []*Client
+}
+
+// NewMultiClient creates a MultiClient over clients, one per cluster. An odd
+// number of clusters makes the most of them: three tolerate one being down,
+// as do four.
+func NewMultiClient(clients ...*Client) (*MultiClient, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("multi-cluster client needs at least one client")
+	}
+	for i, c := range clients {
+		if c == nil {
+			return nil, fmt.Errorf("client for cluster %d is nil", i)
+		}
+	}
+	return &MultiClient{clients: clients}, nil
+}
+
+// Clients returns the per-cluster clients, in the order they were given.
+func (m *MultiClient) Clients() []*Client {
+	return m.clients
+}
+
+// Majority returns how many clusters make a majority.
+func (m *MultiClient) Majority() int {
+	return len(m.clients)/2 + 1
+}
+
+// FanOut runs fn against every cluster at once and waits for all of them.
+// The results and errors are indexed like Clients.
+func FanOut[T any](m *MultiClient, fn func(*Client) (T, error)) ([]T, []error) {
+	results := make([]T, len(m.clients))
+	errs := make([]error, len(m.clients))
+
+	var wg sync.WaitGroup
+	for i, c := range m.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = fn(c)
+		}()
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+// AcquireMajority runs acquire against every cluster at once and keeps what
+// it got if a majority of clusters succeeded, returning those acquisitions.
+// Otherwise it releases every acquisition it made and fails with ErrNoQuorum,
+// wrapping each cluster's error. Every cluster is waited for, so acquire
+// should be bounded, for example by WithTimeout.
+func AcquireMajority[T any](m *MultiClient, ctx context.Context, acquire func(*Client) (T, error), release func(context.Context, T) error) ([]T, error) {
+	results, errs := FanOut(m, acquire)
+
+	var acquired []T
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("cluster %d: %w", i, err))
+			continue
+		}
+		acquired = append(acquired, results[i])
+	}
+
+	if len(acquired) >= m.Majority() {
+		return acquired, nil
+	}
+
+	// ctx may already be done, so clean up with a detached context
+	cleanupCtx, cancel := CleanupContext(ctx)
+	defer cancel()
+	for _, a := range acquired {
+		if err := release(cleanupCtx, a); err != nil {
+			failures = append(failures, fmt.Errorf("cleanup failed: %w", err))
+		}
+	}
+
+	return nil, fmt.Errorf("%w: acquired on %d of %d clusters, need %d: %w",
+		ErrNoQuorum, len(acquired), len(m.clients), m.Majority(), errors.Join(failures...))
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestMultiClient returns a MultiClient over n clusters, each a separate
+// fake client
+func newTestMultiClient(t *testing.T, n int) *MultiClient {
+	scheme := setupTestScheme(t)
+	clients := make([]*Client, n)
+	for i := range clients {
+		clients[i] = NewFromClient(fake.NewClientBuilder().WithScheme(scheme).Build(), "default")
+	}
+
+	m, err := NewMultiClient(clients...)
+	require.NoError(t, err)
+	return m
+}
+
+func TestNewMultiClient(t *testing.T) {
+	_, err := NewMultiClient()
+	assert.ErrorContains(t, err, "at least one client")
+
+	_, err = NewMultiClient(NewFromClient(fake.NewClientBuilder().Build(), "default"), nil)
+	assert.ErrorContains(t, err, "client for cluster 1 is nil")
+
+	m := newTestMultiClient(t, 3)
+	assert.Len(t, m.Clients(), 3)
+}
+
+func TestMultiClient_Majority(t *testing.T) {
+	for clusters, majority := range map[int]int{1: 1, 2: 2, 3: 2, 4: 3, 5: 3} {
+		assert.Equal(t, majority, newTestMultiClient(t, clusters).Majority(), "%d clusters", clusters)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	m := newTestMultiClient(t, 3)
+
+	results, errs := FanOut(m, func(c *Client) (int, error) {
+		i := clusterIndex(m, c)
+		if i == 1 {
+			return 0, errors.New("unreachable")
+		}
+		return i * 10, nil
+	})
+
+	assert.Equal(t, []int{0, 0, 20}, results)
+	require.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.EqualError(t, errs[1], "unreachable")
+	assert.NoError(t, errs[2])
+}
+
+// clusterIndex returns the position of c among m's clients
+func clusterIndex(m *MultiClient, c *Client) int {
+	for i, candidate := range m.Clients() {
+		if candidate == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestAcquireMajority(t *testing.T) {
+	tests := []struct {
+		name     string
+		failing  []int
+		acquired []string
+		released []string
+	}{
+		{
+			name:     "all clusters",
+			acquired: []string{"cluster-0", "cluster-1", "cluster-2"},
+		},
+		{
+			name:     "one cluster fails",
+			failing:  []int{1},
+			acquired: []string{"cluster-0", "cluster-2"},
+		},
+		{
+			name:     "two clusters fail",
+			failing:  []int{0, 2},
+			released: []string{"cluster-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMultiClient(t, 3)
+
+			var mu sync.Mutex
+			var released []string
+			acquired, err := AcquireMajority(m, context.Background(), func(c *Client) (string, error) {
+				i := clusterIndex(m, c)
+				for _, f := range tt.failing {
+					if f == i {
+						return "", fmt.Errorf("denied on cluster-%d", i)
+					}
+				}
+				return fmt.Sprintf("cluster-%d", i), nil
+			}, func(ctx context.Context, handle string) error {
+				assert.NoError(t, ctx.Err())
+				mu.Lock()
+				defer mu.Unlock()
+				released = append(released, handle)
+				return nil
+			})
+
+			if tt.acquired == nil {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrNoQuorum))
+				assert.Contains(t, err.Error(), "acquired on 1 of 3 clusters, need 2")
+				assert.Contains(t, err.Error(), "cluster 0: denied on cluster-0")
+				assert.Contains(t, err.Error(), "cluster 2: denied on cluster-2")
+				assert.Nil(t, acquired)
+			} else {
+				require.NoError(t, err)
+				sort.Strings(acquired)
+				assert.Equal(t, tt.acquired, acquired)
+			}
+			assert.Equal(t, tt.released, released)
+		})
+	}
+}
+
+func TestAcquireMajority_ReleasesWithCancelledContext(t *testing.T) {
+	m := newTestMultiClient(t, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var released []int
+	_, err := AcquireMajority(m, ctx, func(c *Client) (int, error) {
+		i := clusterIndex(m, c)
+		if i == 0 {
+			return i, nil
+		}
+		// The caller gives up while the other clusters are still waiting
+		cancel()
+		return 0, ctx.Err()
+	}, func(ctx context.Context, handle int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		released = append(released, handle)
+		return errors.New("already gone")
+	})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNoQuorum))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Contains(t, err.Error(), "cleanup failed: already gone")
+	assert.Equal(t, []int{0}, released)
+}
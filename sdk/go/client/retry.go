@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -46,20 +47,7 @@ func (c *Client) RetryOnConflict(ctx context.Context, fn func() error) error {
 		Cap:      config.MaxDelay,
 	}
 
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		err := fn()
-		if err == nil {
-			return true, nil
-		}
-
-		// Retry on conflict errors
-		if errors.IsConflict(err) {
-			return false, nil
-		}
-
-		// Don't retry other errors
-		return false, err
-	})
+	return c.retry(ctx, backoff, fn)
 }
 
 // WaitForUpdate waits for operator to process changes before continuing
@@ -133,3 +121,61 @@ func (c *Client) StatusUpdateWithRetry(ctx context.Context, obj client.Object, u
 		return c.k8sClient.Status().Update(ctx, latest)
 	})
 }
+
+// RetryStats counts how the client's retried operations went, so contention on
+// shared primitives can be observed.
+type RetryStats struct {
+	// Retries is the number of attempts repeated after a retryable error
+	Retries int64
+	// Failures is the number of operations that ended in an error, whether
+	// terminal, retried until the backoff ran out or cancelled
+	Failures int64
+}
+
+// retryStats is shared by a client and the clients derived from it with
+// WithNamespace
+type retryStats struct {
+	retries  atomic.Int64
+	failures atomic.Int64
+}
+
+// RetryStats returns the retry counters accumulated by RetryWithBackoff,
+// RetryOnConflict and the operations built on them.
+func (c *Client) RetryStats() RetryStats {
+	if c.stats == nil {
+		return RetryStats{}
+	}
+	return RetryStats{
+		Retries:  c.stats.retries.Load(),
+		Failures: c.stats.failures.Load(),
+	}
+}
+
+// isRetryable reports whether err is transient: the object changed under us
+// or the API server timed out. Anything else, such as NotFound or Forbidden,
+// will fail the same way on every attempt.
+func isRetryable(err error) bool {
+	return errors.IsConflict(err) || errors.IsServerTimeout(err)
+}
+
+// retry runs fn until it succeeds, fails with an error that is not retryable,
+// the backoff runs out or ctx is done, counting retries and failures
+func (c *Client) retry(ctx context.Context, backoff wait.Backoff, fn func() error) error {
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(context.Context) (bool, error) {
+		err := fn()
+		if err == nil {
+			return true, nil
+		}
+		if isRetryable(err) {
+			if c.stats != nil {
+				c.stats.retries.Add(1)
+			}
+			return false, nil
+		}
+		return false, err
+	})
+	if err != nil && c.stats != nil {
+		c.stats.failures.Add(1)
+	}
+	return err
+}
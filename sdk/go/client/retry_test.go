@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+var testResource = schema.GroupResource{Group: "sync.konductor.io", Resource: "mutexes"}
+
+// setupFailingClient wraps a fake client whose status updates return the
+// given errors in turn before going through to the fake, and counts the
+// attempts
+func setupFailingClient(t *testing.T, failures ...error) (*Client, *int) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mutex", Namespace: "default"},
+	}
+
+	attempts := 0
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c ctrlclient.Client, subResourceName string, obj ctrlclient.Object, opts ...ctrlclient.SubResourceUpdateOption) error {
+				attempts++
+				if attempts <= len(failures) {
+					return failures[attempts-1]
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return NewFromClient(k8sClient, "default"), &attempts
+}
+
+func lockMutex(obj ctrlclient.Object) error {
+	obj.(*syncv1.Mutex).Status.Holder = "holder-1"
+	return nil
+}
+
+func TestStatusUpdateWithRetry_ConflictThenSuccess(t *testing.T) {
+	client, attempts := setupFailingClient(t,
+		apierrors.NewConflict(testResource, "test-mutex", errors.New("modified")),
+		apierrors.NewServerTimeout(testResource, "update", 1),
+	)
+
+	mutex := &syncv1.Mutex{ObjectMeta: metav1.ObjectMeta{Name: "test-mutex", Namespace: "default"}}
+	require.NoError(t, client.StatusUpdateWithRetry(context.Background(), mutex, lockMutex))
+	assert.Equal(t, 3, *attempts)
+
+	require.NoError(t, client.K8sClient().Get(context.Background(), ctrlclient.ObjectKeyFromObject(mutex), mutex))
+	assert.Equal(t, "holder-1", mutex.Status.Holder)
+	assert.Equal(t, RetryStats{Retries: 2, Failures: 0}, client.RetryStats())
+}
+
+func TestStatusUpdateWithRetry_ForbiddenFailsFast(t *testing.T) {
+	client, attempts := setupFailingClient(t,
+		apierrors.NewForbidden(testResource, "test-mutex", errors.New("no access")),
+	)
+
+	mutex := &syncv1.Mutex{ObjectMeta: metav1.ObjectMeta{Name: "test-mutex", Namespace: "default"}}
+	start := time.Now()
+	err := client.StatusUpdateWithRetry(context.Background(), mutex, lockMutex)
+	require.Error(t, err)
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.Equal(t, 1, *attempts)
+	assert.Less(t, time.Since(start), DefaultRetryConfig().InitialDelay)
+	assert.Equal(t, RetryStats{Retries: 0, Failures: 1}, client.RetryStats())
+}
+
+func TestStatusUpdateWithRetry_NotFoundFailsFast(t *testing.T) {
+	client, attempts := setupFailingClient(t)
+
+	missing := &syncv1.Mutex{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "default"}}
+	err := client.StatusUpdateWithRetry(context.Background(), missing, lockMutex)
+	require.Error(t, err)
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, 0, *attempts)
+	assert.Equal(t, RetryStats{Retries: 0, Failures: 1}, client.RetryStats())
+}
+
+func TestRetryOnConflict_ContextCancelledBetweenAttempts(t *testing.T) {
+	client, _ := setupFailingClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	callCount := 0
+	start := time.Now()
+	err := client.RetryOnConflict(ctx, func() error {
+		callCount++
+		cancel()
+		return apierrors.NewConflict(testResource, "test-mutex", errors.New("modified"))
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, callCount)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int64(1), client.RetryStats().Failures)
+}
+
+func TestRetryStats_SharedWithNamespacedClient(t *testing.T) {
+	client, _ := setupFailingClient(t)
+	other := client.WithNamespace("other")
+
+	err := other.RetryOnConflict(context.Background(), func() error {
+		return apierrors.NewForbidden(testResource, "test-mutex", errors.New("no access"))
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, RetryStats{Retries: 0, Failures: 1}, client.RetryStats())
+	assert.Equal(t, client.RetryStats(), other.RetryStats())
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// DefaultFieldManager is the field manager a server-side apply is made as
+// when WithFieldManager is not given
+const DefaultFieldManager = "konductor-sdk"
+
+// Update writes obj with a full update, or with a server-side apply when
+// WithServerSideApply is given. The apply only sends the fields the caller
+// set, see WithServerSideApply, and takes them over from whichever manager
+// held them, leaving the rest to their writers. An obj without a namespace
+// is applied in the client's. Either way obj is refreshed from the result.
+func (c *Client) Update(ctx context.Context, obj client.Object, opts ...Option) error {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if !options.ServerSideApply {
+		var updateOpts []client.UpdateOption
+		if options.FieldManager != "" {
+			updateOpts = append(updateOpts, client.FieldOwner(options.FieldManager))
+		}
+		return c.k8sClient.Update(ctx, obj, updateOpts...)
+	}
+
+	manager := options.FieldManager
+	if manager == "" {
+		manager = DefaultFieldManager
+	}
+
+	// The API server needs the kind in an apply, which typed objects read
+	// from it leave empty
+	gvk, err := apiutil.GVKForObject(obj, c.k8sClient.Scheme())
+	if err != nil {
+		return err
+	}
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = c.namespace
+	}
+
+	body, err := applyConfiguration(obj, gvk, namespace, options.ApplyFields)
+	if err != nil {
+		return err
+	}
+	if err := c.k8sClient.Patch(ctx, body, client.Apply, client.FieldOwner(manager), client.ForceOwnership); err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(body.Object, obj)
+}
+
+// applyConfiguration builds the body of an apply of obj: its kind, name and
+// namespace, and the fields at paths, or when paths is empty every field obj
+// sets to a non-zero value. A typed object always carries its fields that are
+// not omitempty, so sending it whole would take ownership of those the
+// caller never meant to set.
+func applyConfiguration(obj client.Object, gvk schema.GroupVersionKind, namespace string, paths []string) (*unstructured.Unstructured, error) {
+	body := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if len(paths) == 0 {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		// Only the metadata a caller can own goes into an apply
+		for _, field := range []string{"spec", "labels", "annotations"} {
+			path := []string{field}
+			if field != "spec" {
+				path = []string{"metadata", field}
+			}
+			if value, found, _ := unstructured.NestedFieldNoCopy(content, path...); found {
+				if value = pruneZero(value); value != nil {
+					if err := unstructured.SetNestedField(body.Object, value, path...); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		// Read from the typed object, since converting it drops the zero
+		// values of omitempty fields the caller may be applying
+		value, err := fieldAt(obj, fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read field %s: %w", path, err)
+		}
+		// An unset field is left out, which gives it up if this manager
+		// applied it before
+		if value == nil {
+			continue
+		}
+		if err := unstructured.SetNestedField(body.Object, value, fields...); err != nil {
+			return nil, fmt.Errorf("failed to apply field %s: %w", path, err)
+		}
+	}
+
+	body.SetGroupVersionKind(gvk)
+	body.SetName(obj.GetName())
+	body.SetNamespace(namespace)
+	return body, nil
+}
+
+// fieldAt returns the JSON value of the field of obj at the path of JSON
+// field names, or nil if it or an object on the way to it is unset
+func fieldAt(obj interface{}, fields []string) (interface{}, error) {
+	v := reflect.ValueOf(obj)
+	for _, name := range fields {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s is not in an object", name)
+		}
+		field, ok := jsonField(v, name)
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %s", v.Type(), name)
+		}
+		v = field
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// jsonField returns the field of struct v named name in JSON, looking into
+// the structs it inlines
+func jsonField(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if tag == name {
+			return v.Field(i), true
+		}
+		if tag == "" && v.Field(i).Kind() == reflect.Struct {
+			if field, ok := jsonField(v.Field(i), name); ok {
+				return field, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// pruneZero drops the zero values from a field read from an object, along
+// with the maps left empty by that, returning nil if nothing is left. Lists
+// are kept as they are, since each item is applied whole.
+func pruneZero(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		pruned := map[string]interface{}{}
+		for key, field := range v {
+			if field = pruneZero(field); field != nil {
+				pruned[key] = field
+			}
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		return pruned
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+	case bool:
+		if !v {
+			return nil
+		}
+	case int64:
+		if v == 0 {
+			return nil
+		}
+	case float64:
+		if v == 0 {
+			return nil
+		}
+	}
+	return value
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// TestUpdate_ServerSideApplyAgainstAPIServer checks the apply against a real
+// API server, whose field ownership the fake client cannot stand in for. It
+// needs the envtest binaries, see the go:test:envtest task.
+func TestUpdate_ServerSideApplyAgainstAPIServer(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = env.Stop() })
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+	k8sClient, err := ctrlclient.NewWithWatch(cfg, ctrlclient.Options{Scheme: scheme})
+	require.NoError(t, err)
+	client := NewFromClient(k8sClient, "default")
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "test-barrier", Namespace: "default"}
+
+	// Another writer owns the expected count
+	require.NoError(t, k8sClient.Create(ctx, &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Spec:       syncv1.BarrierSpec{Expected: 3},
+	}, ctrlclient.FieldOwner("deployer")))
+
+	require.NoError(t, client.Update(ctx, &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+		Spec:       syncv1.BarrierSpec{Timeout: &metav1.Duration{Duration: time.Hour}},
+	}, WithServerSideApply()))
+
+	var updated syncv1.Barrier
+	require.NoError(t, k8sClient.Get(ctx, key, &updated))
+	assert.Equal(t, int32(3), updated.Spec.Expected)
+	require.NotNil(t, updated.Spec.Timeout)
+	assert.Equal(t, time.Hour, updated.Spec.Timeout.Duration)
+
+	applied := appliedFields(t, &updated, DefaultFieldManager)
+	assert.Contains(t, applied, "f:timeout")
+	assert.NotContains(t, applied, "f:expected", "the apply must not take over fields it did not set")
+
+	// Applying again without the timeout gives it up, removing it
+	quorum := int32(2)
+	require.NoError(t, client.Update(ctx, &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+		Spec:       syncv1.BarrierSpec{Quorum: &quorum},
+	}, WithServerSideApply()))
+
+	require.NoError(t, k8sClient.Get(ctx, key, &updated))
+	assert.Nil(t, updated.Spec.Timeout)
+	require.NotNil(t, updated.Spec.Quorum)
+	assert.Equal(t, int32(2), *updated.Spec.Quorum)
+	assert.Equal(t, int32(3), updated.Spec.Expected)
+}
+
+// appliedFields returns the fields manager owns on obj through an apply, as
+// the API server records them
+func appliedFields(t *testing.T, obj ctrlclient.Object, manager string) string {
+	t.Helper()
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == manager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			require.NotNil(t, entry.FieldsV1)
+			return string(entry.FieldsV1.Raw)
+		}
+	}
+	t.Fatalf("no fields applied by %s", manager)
+	return ""
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	"github.com/LogicIQ/konductor/sdk/go/internal/applytest"
+)
+
+func newApplyTestLease() *syncv1.Lease {
+	priority := int32(5)
+	return &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-lease", Namespace: "default"},
+		Spec: syncv1.LeaseSpec{
+			TTL:      &metav1.Duration{Duration: time.Minute},
+			Priority: &priority,
+		},
+	}
+}
+
+func TestUpdate_ServerSideApplyKeepsConcurrentChanges(t *testing.T) {
+	k8sClient, applied := applytest.NewClient(t, newApplyTestLease())
+	client := NewFromClient(k8sClient, "default")
+	ctx := context.Background()
+
+	// Another writer raises the priority after we last read the lease
+	var current syncv1.Lease
+	require.NoError(t, client.K8sClient().Get(ctx, types.NamespacedName{Name: "test-lease", Namespace: "default"}, &current))
+	priority := int32(10)
+	current.Spec.Priority = &priority
+	require.NoError(t, client.K8sClient().Update(ctx, &current))
+
+	// We only own the TTL
+	update := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-lease"},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: 2 * time.Minute}},
+	}
+	require.NoError(t, client.Update(ctx, update, WithServerSideApply()))
+
+	require.Len(t, *applied, 1)
+	assert.Equal(t, DefaultFieldManager, (*applied)[0].Manager)
+	assert.True(t, (*applied)[0].Force)
+	assert.Contains(t, (*applied)[0].Body, `"kind":"Lease"`)
+	assert.Contains(t, (*applied)[0].Body, `"apiVersion":"sync.konductor.io/v1"`)
+	assert.NotContains(t, (*applied)[0].Body, "priority")
+	assert.NotContains(t, (*applied)[0].Body, "resourceVersion")
+
+	var updated syncv1.Lease
+	require.NoError(t, client.K8sClient().Get(ctx, types.NamespacedName{Name: "test-lease", Namespace: "default"}, &updated))
+	assert.Equal(t, 2*time.Minute, updated.Spec.TTL.Duration)
+	require.NotNil(t, updated.Spec.Priority)
+	assert.Equal(t, int32(10), *updated.Spec.Priority, "the concurrent change must survive the apply")
+}
+
+func TestUpdate_FieldManager(t *testing.T) {
+	k8sClient, applied := applytest.NewClient(t, newApplyTestLease())
+	client := NewFromClient(k8sClient, "default")
+
+	update := &syncv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: "test-lease"}}
+	require.NoError(t, client.Update(context.Background(), update, WithServerSideApply(), WithFieldManager("deployer")))
+
+	require.Len(t, *applied, 1)
+	assert.Equal(t, "deployer", (*applied)[0].Manager)
+}
+
+func TestUpdate_FullUpdateByDefault(t *testing.T) {
+	k8sClient, applied := applytest.NewClient(t, newApplyTestLease())
+	client := NewFromClient(k8sClient, "default")
+	ctx := context.Background()
+
+	var current syncv1.Lease
+	require.NoError(t, client.K8sClient().Get(ctx, types.NamespacedName{Name: "test-lease", Namespace: "default"}, &current))
+	current.Spec.Priority = nil
+	require.NoError(t, client.Update(ctx, &current))
+	assert.Empty(t, *applied)
+
+	var updated syncv1.Lease
+	require.NoError(t, client.K8sClient().Get(ctx, types.NamespacedName{Name: "test-lease", Namespace: "default"}, &updated))
+	assert.Nil(t, updated.Spec.Priority, "a full update replaces the whole spec")
+
+	// Without an apply a stale object conflicts rather than overwriting
+	current.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+	current.ResourceVersion = "1"
+	assert.Error(t, client.Update(ctx, &current))
+}
+
+func TestUpdate_ServerSideApplySendsOnlySetFields(t *testing.T) {
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "default"},
+		Spec:       syncv1.BarrierSpec{Expected: 3},
+	}
+	k8sClient, applied := applytest.NewClient(t, barrier)
+	client := NewFromClient(k8sClient, "default")
+	ctx := context.Background()
+
+	// Expected is not omitempty, but the caller never set it
+	update := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier"},
+		Spec:       syncv1.BarrierSpec{Timeout: &metav1.Duration{Duration: time.Hour}},
+	}
+	require.NoError(t, client.Update(ctx, update, WithServerSideApply()))
+
+	require.Len(t, *applied, 1)
+	assert.JSONEq(t, `{
+		"apiVersion": "sync.konductor.io/v1",
+		"kind": "Barrier",
+		"metadata": {"name": "test-barrier", "namespace": "default"},
+		"spec": {"timeout": "1h0m0s"}
+	}`, (*applied)[0].Body)
+
+	// The object passed is refreshed from the result
+	assert.Equal(t, int32(3), update.Spec.Expected)
+	assert.Equal(t, time.Hour, update.Spec.Timeout.Duration)
+}
+
+func TestUpdate_ServerSideApplyNamedFields(t *testing.T) {
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 3, Drain: true},
+	}
+	k8sClient, applied := applytest.NewClient(t, semaphore)
+	client := NewFromClient(k8sClient, "default")
+	ctx := context.Background()
+
+	// Naming the field applies its zero value, and leaves out the rest
+	update := &syncv1.Semaphore{ObjectMeta: metav1.ObjectMeta{Name: "test-sem"}}
+	require.NoError(t, client.Update(ctx, update, WithServerSideApply("spec.drain", "spec.ttl")))
+
+	require.Len(t, *applied, 1)
+	assert.JSONEq(t, `{
+		"apiVersion": "sync.konductor.io/v1",
+		"kind": "Semaphore",
+		"metadata": {"name": "test-sem", "namespace": "default"},
+		"spec": {"drain": false}
+	}`, (*applied)[0].Body)
+
+	var updated syncv1.Semaphore
+	require.NoError(t, client.K8sClient().Get(ctx, types.NamespacedName{Name: "test-sem", Namespace: "default"}, &updated))
+	assert.False(t, updated.Spec.Drain)
+	assert.Equal(t, int32(3), updated.Spec.Permits)
+}
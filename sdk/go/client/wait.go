@@ -2,11 +2,19 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
 )
 
 type WaitConfig struct {
@@ -32,6 +40,17 @@ func DefaultWaitConfig() *WaitConfig {
 	}
 }
 
+// CleanupTimeout bounds the best-effort deletes an SDK call makes after giving
+// up on a wait
+const CleanupTimeout = 5 * time.Second
+
+// CleanupContext returns a context for cleaning up after an abandoned wait. It
+// keeps ctx's values but not its cancellation, since ctx is usually already
+// done, and expires after CleanupTimeout so cleanup cannot hang the caller.
+func CleanupContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), CleanupTimeout)
+}
+
 func calculateBackoffSteps(initialDelay, maxDelay time.Duration, factor float64, timeout time.Duration) int {
 	if initialDelay <= 0 {
 		initialDelay = 1 * time.Millisecond
@@ -60,7 +79,9 @@ func calculateBackoffSteps(initialDelay, maxDelay time.Duration, factor float64,
 			break
 		}
 	}
-	return steps
+	// Each step is one check, so add one to check again after the last delay
+	// rather than giving up as soon as it has elapsed
+	return steps + 1
 }
 
 func (c *Client) WaitForCondition(ctx context.Context, obj client.Object, condition func(client.Object) bool, config *WaitConfig) error {
@@ -84,7 +105,8 @@ func (c *Client) WaitForCondition(ctx context.Context, obj client.Object, condit
 		Cap:      config.MaxDelay,
 	}
 
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+	// Stop polling as soon as ctx is done, even if the backoff has steps left
+	return wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
 		if err := c.k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
 			if errors.IsNotFound(err) {
 				return false, nil
@@ -108,14 +130,115 @@ func (c *Client) RetryWithBackoff(ctx context.Context, fn func() error, config *
 		Cap:      config.MaxDelay,
 	}
 
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		err := fn()
-		if err == nil {
-			return true, nil
+	// Retries only conflicts and server timeouts, and stops waiting between
+	// attempts as soon as ctx is done
+	return c.retry(ctx, backoff, fn)
+}
+
+// deletionPollInterval is how often WaitForDeletion checks what is left of a
+// deleted object
+const deletionPollInterval = 250 * time.Millisecond
+
+// WaitForDeletion blocks until obj no longer exists, nor do the Permits,
+// Arrivals and LeaseRequests that name it as their owner, which the garbage
+// collector only removes after obj is gone. Only obj's name needs to be set;
+// without a namespace it is looked up in the client's. It fails with
+// ErrTimeout once timeout passes, while a zero timeout waits for as long as
+// ctx allows.
+func (c *Client) WaitForDeletion(ctx context.Context, obj client.Object, timeout time.Duration) error {
+	gvk, err := apiutil.GVKForObject(obj, c.k8sClient.Scheme())
+	if err != nil {
+		return fmt.Errorf("failed to wait for deletion of %s: %w", obj.GetName(), err)
+	}
+	kind := strings.ToLower(gvk.Kind)
+	key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	if key.Namespace == "" {
+		key.Namespace = c.namespace
+	}
+
+	waitCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// The UID tells dependents of obj apart from those of an object recreated
+	// under the same name, once it is known
+	uid := obj.GetUID()
+	current := obj.DeepCopyObject().(client.Object)
+	remaining := kind + " still exists"
+	for {
+		err := c.k8sClient.Get(waitCtx, key, current)
+		switch {
+		case err == nil:
+			uid = current.GetUID()
+			remaining = fmt.Sprintf("%s still exists", kind)
+		case !errors.IsNotFound(err):
+			if waitCtx.Err() == nil {
+				return fmt.Errorf("failed to wait for deletion of %s %s: %w", kind, key.Name, err)
+			}
+		default:
+			dependents, err := c.countDependents(waitCtx, obj, gvk.Kind, key, uid)
+			switch {
+			case err == nil && dependents == 0:
+				return nil
+			case err == nil:
+				remaining = fmt.Sprintf("%d dependents remain", dependents)
+			case waitCtx.Err() == nil:
+				return fmt.Errorf("failed to wait for deletion of %s %s: %w", kind, key.Name, err)
+			}
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return fmt.Errorf("context cancelled while waiting for deletion of %s %s: %w", kind, key.Name, ctx.Err())
+			}
+			return fmt.Errorf("%w waiting for deletion of %s %s, %s: %w", ErrTimeout, kind, key.Name, remaining, waitCtx.Err())
+		case <-time.After(deletionPollInterval):
+		}
+	}
+}
+
+// countDependents counts the objects in key's namespace that name the kind
+// and name of key, and uid when it is set, among their owners
+func (c *Client) countDependents(ctx context.Context, obj client.Object, kind string, key types.NamespacedName, uid types.UID) (int, error) {
+	count := 0
+	for _, list := range dependentLists(obj) {
+		if err := c.k8sClient.List(ctx, list, client.InNamespace(key.Namespace)); err != nil {
+			return 0, err
 		}
-		if errors.IsConflict(err) {
-			return false, nil // Retry conflicts
+		err := meta.EachListItem(list, func(item runtime.Object) error {
+			dependent, ok := item.(client.Object)
+			if !ok {
+				return nil
+			}
+			for _, owner := range dependent.GetOwnerReferences() {
+				if owner.Kind == kind && owner.Name == key.Name && (uid == "" || owner.UID == uid) {
+					count++
+					break
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
 		}
-		return false, err // Don't retry other errors
-	})
+	}
+	return count, nil
+}
+
+// dependentLists returns an empty list of each kind that objects like obj can
+// own
+func dependentLists(obj client.Object) []client.ObjectList {
+	switch obj.(type) {
+	case *syncv1.Semaphore:
+		return []client.ObjectList{&syncv1.PermitList{}}
+	case *syncv1.Barrier:
+		return []client.ObjectList{&syncv1.ArrivalList{}}
+	case *syncv1.Lease:
+		return []client.ObjectList{&syncv1.LeaseRequestList{}}
+	}
+	return nil
 }
@@ -143,3 +143,125 @@ func TestDefaultWaitConfig(t *testing.T) {
 	assert.Equal(t, 30*time.Second, config.Timeout)
 	assert.Equal(t, 2*time.Second, config.OperatorDelay)
 }
+
+type cleanupKey struct{}
+
+func TestCleanupContext(t *testing.T) {
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), cleanupKey{}, "value"))
+	cancel()
+
+	ctx, cleanupCancel := CleanupContext(parent)
+	defer cleanupCancel()
+
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, "value", ctx.Value(cleanupKey{}))
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(CleanupTimeout), deadline, time.Second)
+}
+
+// ownedPermit returns a permit owned by semaphore
+func ownedPermit(name string, semaphore *syncv1.Semaphore) *syncv1.Permit {
+	return &syncv1.Permit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: semaphore.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "sync.konductor.io/v1",
+				Kind:       "Semaphore",
+				Name:       semaphore.Name,
+				UID:        semaphore.UID,
+			}},
+		},
+		Spec: syncv1.PermitSpec{Semaphore: semaphore.Name, Holder: name},
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "default", UID: "sem-uid"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 2},
+	}
+	other := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-sem", Namespace: "default", UID: "other-uid"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 2},
+	}
+	permit := ownedPermit("worker-1", semaphore)
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(semaphore, other, permit, ownedPermit("worker-2", other)).
+		Build()
+	client := NewFromClient(k8sClient, "default")
+
+	// Delete the semaphore, then its permit as the garbage collector would
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = k8sClient.Delete(context.Background(), semaphore.DeepCopy())
+		time.Sleep(400 * time.Millisecond)
+		_ = k8sClient.Delete(context.Background(), permit.DeepCopy())
+	}()
+
+	start := time.Now()
+	err := client.WaitForDeletion(context.Background(), &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem"},
+	}, 5*time.Second)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond, "waits for the permit too")
+
+	err = k8sClient.Get(context.Background(), ctrlclient.ObjectKeyFromObject(permit), &syncv1.Permit{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestWaitForDeletion_Timeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	barrier := &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "default", UID: "barrier-uid"},
+		Spec:       syncv1.BarrierSpec{Expected: 2},
+	}
+	arrival := &syncv1.Arrival{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-barrier-worker-1",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "sync.konductor.io/v1",
+				Kind:       "Barrier",
+				Name:       barrier.Name,
+				UID:        barrier.UID,
+			}},
+		},
+		Spec: syncv1.ArrivalSpec{Barrier: barrier.Name, Holder: "worker-1"},
+	}
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(barrier, arrival).
+		Build()
+	client := NewFromClient(k8sClient, "default")
+
+	err := client.WaitForDeletion(context.Background(), barrier, 300*time.Millisecond)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Contains(t, err.Error(), "barrier still exists")
+
+	// A barrier that is gone still times out while its arrival is left
+	require.NoError(t, k8sClient.Delete(context.Background(), barrier))
+	err = client.WaitForDeletion(context.Background(), barrier, 300*time.Millisecond)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Contains(t, err.Error(), "1 dependents remain")
+
+	// Cancelling ctx is not a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.WaitForDeletion(ctx, barrier, time.Second)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrTimeout)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// WatchForCondition waits for obj to satisfy condition using a Kubernetes watch,
+// so callers observe status transitions as soon as the API server publishes them.
+//
+// If the underlying client does not support watches, or the watch cannot be
+// established or is closed early, it falls back to WaitForCondition polling for
+// the remaining time. On success obj holds the state that satisfied condition.
+func (c *Client) WatchForCondition(ctx context.Context, obj client.Object, condition func(client.Object) bool, config *WaitConfig) error {
+	if config == nil {
+		config = DefaultWaitConfig()
+	}
+
+	watcher, ok := c.k8sClient.(client.WithWatch)
+	if !ok {
+		return c.WaitForCondition(ctx, obj, condition, config)
+	}
+
+	// Mandatory wait for operator processing
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(config.OperatorDelay):
+	}
+
+	waitCtx := ctx
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	list, err := c.newListFor(obj)
+	if err != nil {
+		return c.pollRemaining(ctx, waitCtx, obj, condition, config)
+	}
+
+	// Start the watch before reading the current state so no transition
+	// between the two calls can be missed.
+	w, err := watcher.Watch(waitCtx, list,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{"metadata.name": obj.GetName()})
+	if err != nil {
+		return c.pollRemaining(ctx, waitCtx, obj, condition, config)
+	}
+	defer w.Stop()
+
+	if err := c.k8sClient.Get(waitCtx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if !errors.IsNotFound(err) {
+			return waitError(ctx, err)
+		}
+	} else if condition(obj) {
+		return nil
+	}
+
+	for {
+		select {
+		case <-waitCtx.Done():
+			return waitError(ctx, waitCtx.Err())
+		case event, open := <-w.ResultChan():
+			if !open {
+				return c.pollRemaining(ctx, waitCtx, obj, condition, config)
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				current, ok := event.Object.(client.Object)
+				if !ok || current.GetName() != obj.GetName() {
+					continue
+				}
+				if condition(current) {
+					copyObject(current, obj)
+					return nil
+				}
+			case watch.Error:
+				return c.pollRemaining(ctx, waitCtx, obj, condition, config)
+			}
+		}
+	}
+}
+
+// pollRemaining falls back to polling for whatever time is left on waitCtx.
+func (c *Client) pollRemaining(ctx, waitCtx context.Context, obj client.Object, condition func(client.Object) bool, config *WaitConfig) error {
+	fallback := *config
+	fallback.OperatorDelay = 0
+	if deadline, ok := waitCtx.Deadline(); ok {
+		fallback.Timeout = time.Until(deadline)
+		if fallback.Timeout <= 0 {
+			return waitError(ctx, context.DeadlineExceeded)
+		}
+	}
+	if err := c.WaitForCondition(waitCtx, obj, condition, &fallback); err != nil {
+		return waitError(ctx, err)
+	}
+	return nil
+}
+
+func (c *Client) newListFor(obj client.Object) (client.ObjectList, error) {
+	scheme := c.k8sClient.Scheme()
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	gvk.Kind += "List"
+	runtimeList, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := runtimeList.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list type", gvk.Kind)
+	}
+	return list, nil
+}
+
+// waitError reports cancellation of the caller's context as-is and treats
+// expiry of the wait's own timeout like an exhausted poll.
+func waitError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == context.DeadlineExceeded {
+		return wait.ErrWaitTimeout
+	}
+	return err
+}
+
+func copyObject(src, dst client.Object) {
+	dstValue := reflect.ValueOf(dst)
+	srcValue := reflect.ValueOf(src)
+	if dstValue.Type() == srcValue.Type() && dstValue.Kind() == reflect.Pointer {
+		dstValue.Elem().Set(srcValue.Elem())
+	}
+}
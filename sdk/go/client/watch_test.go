@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// nonWatchingClient hides the Watch method of a fake client to exercise the polling fallback.
+type nonWatchingClient struct {
+	ctrlclient.Client
+}
+
+func TestWatchForCondition_AlreadyMet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"},
+		Status:     syncv1.WaitGroupStatus{Counter: 0},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(wg).Build()
+	client := NewFromClient(k8sClient, "default")
+
+	target := &syncv1.WaitGroup{ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"}}
+	err := client.WatchForCondition(context.Background(), target, func(obj ctrlclient.Object) bool {
+		return obj.(*syncv1.WaitGroup).Status.Counter == 0
+	}, &WaitConfig{InitialDelay: time.Second, MaxDelay: time.Second, Timeout: time.Second})
+
+	assert.NoError(t, err)
+}
+
+func TestWatchForCondition_ReactsToUpdate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"},
+		Status:     syncv1.WaitGroupStatus{Counter: 2},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(wg).Build()
+	client := NewFromClient(k8sClient, "default")
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		var current syncv1.WaitGroup
+		if err := k8sClient.Get(context.Background(), ctrlclient.ObjectKeyFromObject(wg), &current); err != nil {
+			return
+		}
+		current.Status.Counter = 0
+		_ = k8sClient.Update(context.Background(), &current)
+	}()
+
+	target := &syncv1.WaitGroup{ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"}}
+	start := time.Now()
+	err := client.WatchForCondition(context.Background(), target, func(obj ctrlclient.Object) bool {
+		return obj.(*syncv1.WaitGroup).Status.Counter == 0
+	}, &WaitConfig{InitialDelay: 5 * time.Second, MaxDelay: 5 * time.Second, Timeout: 10 * time.Second})
+
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second, "watch should observe the update well before the first poll")
+	assert.Equal(t, int32(0), target.Status.Counter)
+}
+
+func TestWatchForCondition_Timeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"},
+		Status:     syncv1.WaitGroupStatus{Counter: 1},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(wg).Build()
+	client := NewFromClient(k8sClient, "default")
+
+	err := client.WatchForCondition(context.Background(), wg, func(obj ctrlclient.Object) bool {
+		return false
+	}, &WaitConfig{InitialDelay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond, Timeout: 100 * time.Millisecond})
+
+	assert.Error(t, err)
+}
+
+func TestWatchForCondition_ContextCancelled(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"},
+		Status:     syncv1.WaitGroupStatus{Counter: 1},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(wg).Build()
+	client := NewFromClient(k8sClient, "default")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := client.WatchForCondition(ctx, wg, func(obj ctrlclient.Object) bool {
+		return false
+	}, &WaitConfig{InitialDelay: time.Second, MaxDelay: time.Second, Timeout: 10 * time.Second})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWatchForCondition_FallsBackToPolling(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-wg", Namespace: "default"},
+		Status:     syncv1.WaitGroupStatus{Counter: 0},
+	}
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(wg).Build()
+	client := NewFromClient(nonWatchingClient{Client: k8sClient}, "default")
+
+	err := client.WatchForCondition(context.Background(), wg, func(obj ctrlclient.Object) bool {
+		return obj.(*syncv1.WaitGroup).Status.Counter == 0
+	}, &WaitConfig{InitialDelay: 10 * time.Millisecond, MaxDelay: 20 * time.Millisecond, Timeout: time.Second})
+
+	assert.NoError(t, err)
+}
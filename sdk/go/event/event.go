@@ -0,0 +1,184 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+func wrapError(operation, name string, err error) error {
+	return fmt.Errorf("failed to %s event %s: %w", operation, name, err)
+}
+
+// Set signals the event, releasing every current and future waiter until the
+// event is cleared again. Setting an already signaled event is a no-op.
+func Set(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	holder := options.Holder
+	if holder == "" {
+		holder = konductor.DefaultHolder()
+	}
+
+	event := &syncv1.Event{}
+	event.Name = name
+	event.Namespace = c.Namespace()
+
+	err := c.StatusUpdateWithRetry(ctx, event, func(obj client.Object) error {
+		e := obj.(*syncv1.Event)
+		if e.Status.Signaled {
+			return nil
+		}
+		signaledAt := metav1.Now()
+		e.Status.Signaled = true
+		e.Status.SignaledBy = holder
+		e.Status.SignaledAt = &signaledAt
+		e.Status.Phase = syncv1.EventPhaseSet
+		return nil
+	})
+	if err != nil {
+		return wrapError("set", name, err)
+	}
+	return nil
+}
+
+// Clear resets the event so that subsequent calls to Wait block until it is
+// set again. Clearing an event that is not signaled is a no-op.
+func Clear(c *konductor.Client, ctx context.Context, name string) error {
+	event := &syncv1.Event{}
+	event.Name = name
+	event.Namespace = c.Namespace()
+
+	err := c.StatusUpdateWithRetry(ctx, event, func(obj client.Object) error {
+		e := obj.(*syncv1.Event)
+		e.Status.Signaled = false
+		e.Status.Phase = syncv1.EventPhaseCleared
+		return nil
+	})
+	if err != nil {
+		return wrapError("clear", name, err)
+	}
+	return nil
+}
+
+// IsSet checks if the event is currently signaled
+func IsSet(c *konductor.Client, ctx context.Context, name string) (bool, error) {
+	event, err := Get(c, ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return event.Status.Signaled, nil
+}
+
+// Wait blocks until the event is signaled, reacting to status changes via a
+// watch and falling back to polling when a watch cannot be established. It
+// returns immediately if the event is already set.
+func Wait(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	event := &syncv1.Event{}
+	event.Name = name
+	event.Namespace = c.Namespace()
+
+	config := &konductor.WaitConfig{
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     5 * time.Second,
+		Factor:       1.5,
+		Jitter:       0.1,
+		Timeout:      30 * time.Second,
+	}
+
+	if options.Timeout > 0 {
+		config.Timeout = options.Timeout
+	}
+
+	err := c.WatchForCondition(ctx, event, func(obj client.Object) bool {
+		return obj.(*syncv1.Event).Status.Signaled
+	}, config)
+	if err != nil {
+		if ctx.Err() == nil && wait.Interrupted(err) {
+			return fmt.Errorf("%w waiting for event %s: %w", konductor.ErrTimeout, name, err)
+		}
+		return wrapError("wait", name, err)
+	}
+	return nil
+}
+
+// Create creates a new event in the cleared state
+func Create(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	event := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace(),
+		},
+		Spec: syncv1.EventSpec{},
+	}
+
+	if options.TTL > 0 {
+		event.Spec.TTL = &metav1.Duration{Duration: options.TTL}
+	}
+
+	err := c.K8sClient().Create(ctx, event)
+	if err != nil && errors.IsAlreadyExists(err) {
+		// Resource already exists, this is not an error for idempotent create
+		return nil
+	}
+	if err != nil {
+		return wrapError("create", name, err)
+	}
+	return nil
+}
+
+func Delete(c *konductor.Client, ctx context.Context, name string) error {
+	event := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace(),
+		},
+	}
+	err := c.K8sClient().Delete(ctx, event)
+	if err != nil && errors.IsNotFound(err) {
+		// Resource doesn't exist, this is not an error for idempotent delete
+		return nil
+	}
+	return err
+}
+
+func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.Event, error) {
+	var event syncv1.Event
+	if err := c.K8sClient().Get(ctx, types.NamespacedName{
+		Name:      name,
+		Namespace: c.Namespace(),
+	}, &event); err != nil {
+		return nil, wrapError("get", name, err)
+	}
+	return &event, nil
+}
+
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.Event, error) {
+	var events syncv1.EventList
+	if err := c.K8sClient().List(ctx, &events, c.ListOptions(opts...)...); err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events.Items, nil
+}
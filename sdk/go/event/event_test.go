@@ -0,0 +1,211 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.Event{}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func newEvent(signaled bool) *syncv1.Event {
+	phase := syncv1.EventPhaseCleared
+	if signaled {
+		phase = syncv1.EventPhaseSet
+	}
+	return &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-event",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.EventStatus{
+			Signaled: signaled,
+			Phase:    phase,
+		},
+	}
+}
+
+func TestList(t *testing.T) {
+	client := setupTestClient(t, newEvent(false))
+
+	events, err := List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "test-event", events[0].Name)
+}
+
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-event",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-event",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-event", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-event",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-event",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
+func TestCreateWithTTL(t *testing.T) {
+	client := setupTestClient(t)
+
+	err := Create(client, context.Background(), "test-event", konductor.WithTTL(5*time.Minute))
+	require.NoError(t, err)
+
+	event, err := Get(client, context.Background(), "test-event")
+	require.NoError(t, err)
+	require.NotNil(t, event.Spec.TTL)
+	assert.Equal(t, 5*time.Minute, event.Spec.TTL.Duration)
+	assert.False(t, event.Status.Signaled)
+}
+
+func TestDelete(t *testing.T) {
+	client := setupTestClient(t, newEvent(false))
+
+	err := Delete(client, context.Background(), "test-event")
+	require.NoError(t, err)
+
+	_, err = Get(client, context.Background(), "test-event")
+	assert.Error(t, err)
+}
+
+func TestSetAndClear(t *testing.T) {
+	client := setupTestClient(t, newEvent(false))
+
+	err := Set(client, context.Background(), "test-event", konductor.WithHolder("producer"))
+	require.NoError(t, err)
+
+	event, err := Get(client, context.Background(), "test-event")
+	require.NoError(t, err)
+	assert.True(t, event.Status.Signaled)
+	assert.Equal(t, "producer", event.Status.SignaledBy)
+	assert.NotNil(t, event.Status.SignaledAt)
+	assert.Equal(t, syncv1.EventPhaseSet, event.Status.Phase)
+
+	err = Clear(client, context.Background(), "test-event")
+	require.NoError(t, err)
+
+	set, err := IsSet(client, context.Background(), "test-event")
+	require.NoError(t, err)
+	assert.False(t, set)
+
+	// The event can be signaled again after being cleared
+	err = Set(client, context.Background(), "test-event")
+	require.NoError(t, err)
+
+	set, err = IsSet(client, context.Background(), "test-event")
+	require.NoError(t, err)
+	assert.True(t, set)
+}
+
+func TestSet_NotFound(t *testing.T) {
+	client := setupTestClient(t)
+
+	err := Set(client, context.Background(), "missing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to set event missing")
+}
+
+func TestWait_SetBeforeWait(t *testing.T) {
+	client := setupTestClient(t, newEvent(false))
+
+	require.NoError(t, Set(client, context.Background(), "test-event"))
+
+	start := time.Now()
+	err := Wait(client, context.Background(), "test-event", konductor.WithTimeout(5*time.Second))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWait_SetAfterWait(t *testing.T) {
+	client := setupTestClient(t, newEvent(false))
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = Set(client, context.Background(), "test-event")
+	}()
+
+	start := time.Now()
+	err := Wait(client, context.Background(), "test-event", konductor.WithTimeout(10*time.Second))
+	require.NoError(t, err)
+
+	// Polling starts at a 1s interval, so returning sooner proves the watch fired
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWait_ClearedTimesOut(t *testing.T) {
+	client := setupTestClient(t, newEvent(true))
+
+	require.NoError(t, Clear(client, context.Background(), "test-event"))
+
+	err := Wait(client, context.Background(), "test-event", konductor.WithTimeout(300*time.Millisecond))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
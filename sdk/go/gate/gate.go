@@ -3,8 +3,10 @@ package gate
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,57 +15,77 @@ import (
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
+const (
+	// DefaultPollInterval is how often Wait and WaitForConditions look at the
+	// gate once their backoff has ramped up
+	DefaultPollInterval = 10 * time.Second
+
+	// defaultWaitTimeout bounds Wait when WithTimeout is not given
+	defaultWaitTimeout = 30 * time.Second
+
+	// pollJitter spreads each poll by up to this fraction either way, so many
+	// waiters on the same gate do not hit the API server in step
+	pollJitter = 0.2
+)
+
+// Wait blocks until the gate opens, failing if it fails instead. It checks
+// straight away, then backs off to polling every WithPollInterval, which
+// defaults to DefaultPollInterval. WithTimeout bounds the wait, which
+// otherwise gives up after 30 seconds.
 func Wait(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
 	options := &konductor.Options{Timeout: 0}
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	gate := &syncv1.Gate{}
-	gate.Name = name
-	gate.Namespace = c.Namespace()
-
-	config := &konductor.WaitConfig{
-		InitialDelay: 2 * time.Second,
-		MaxDelay:     10 * time.Second,
-		Factor:       1.5,
-		Jitter:       0.1,
-		Timeout:      30 * time.Second,
-	}
-
+	timeout := defaultWaitTimeout
 	if options.Timeout > 0 {
-		config.Timeout = options.Timeout
+		timeout = options.Timeout
 	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	err := c.WaitForCondition(ctx, gate, func(obj client.Object) bool {
-		g := obj.(*syncv1.Gate)
-		switch g.Status.Phase {
-		case syncv1.GatePhaseOpen:
-			return true
-		case syncv1.GatePhaseFailed:
-			return true // Will be handled as error after condition returns
-		default:
-			return false
-		}
-	}, config)
+	interval := pollInterval(options)
+	delay := min(2*time.Second, interval)
 
-	if err != nil {
-		return err
-	}
+	for {
+		var gate syncv1.Gate
+		err := c.K8sClient().Get(waitCtx, types.NamespacedName{
+			Name: name, Namespace: c.Namespace(),
+		}, &gate)
+		switch {
+		case err == nil && gate.Status.Phase == syncv1.GatePhaseOpen:
+			return nil
+		case err == nil && gate.Status.Phase == syncv1.GatePhaseFailed:
+			return fmt.Errorf("gate %s failed", name)
+		case err != nil && !errors.IsNotFound(err) && waitCtx.Err() == nil:
+			// A gate that does not exist yet may still be created
+			return fmt.Errorf("failed to wait for gate %s: %w", name, err)
+		}
 
-	// Check final state after wait completes
-	var finalGate syncv1.Gate
-	if err := c.K8sClient().Get(ctx, types.NamespacedName{
-		Name: name, Namespace: c.Namespace(),
-	}, &finalGate); err != nil {
-		return fmt.Errorf("failed to get gate %s: %w", name, err)
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return fmt.Errorf("context cancelled while waiting for gate %s: %w", name, ctx.Err())
+			}
+			return fmt.Errorf("%w waiting for gate %s: %w", konductor.ErrTimeout, name, waitCtx.Err())
+		case <-time.After(jitter(delay)):
+			delay = min(time.Duration(float64(delay)*1.5), interval)
+		}
 	}
+}
 
-	if finalGate.Status.Phase == syncv1.GatePhaseFailed {
-		return fmt.Errorf("gate %s failed", name)
+// pollInterval returns the WithPollInterval option or DefaultPollInterval
+func pollInterval(options *konductor.Options) time.Duration {
+	if options.PollInterval > 0 {
+		return options.PollInterval
 	}
+	return DefaultPollInterval
+}
 
-	return nil
+// jitter returns d moved randomly by up to pollJitter of itself either way
+func jitter(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (1 + pollJitter*(2*rand.Float64()-1)))
 }
 
 func Check(c *konductor.Client, ctx context.Context, name string) (bool, error) {
@@ -104,8 +126,8 @@ func WaitForConditions(c *konductor.Client, ctx context.Context, name string, co
 	}
 
 	startTime := time.Now()
-	delay := 1 * time.Second
-	maxDelay := 10 * time.Second
+	interval := pollInterval(options)
+	delay := min(1*time.Second, interval)
 
 	for {
 		conditions, err := GetConditions(c, ctx, name)
@@ -132,14 +154,14 @@ func WaitForConditions(c *konductor.Client, ctx context.Context, name string, co
 		}
 
 		if options.Timeout > 0 && time.Since(startTime) > options.Timeout {
-			return fmt.Errorf("timeout waiting for conditions in gate %s", name)
+			return fmt.Errorf("%w waiting for conditions in gate %s", konductor.ErrTimeout, name)
 		}
 
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(delay):
-			delay = min(time.Duration(float64(delay)*1.5), maxDelay)
+			return fmt.Errorf("context cancelled while waiting for conditions in gate %s: %w", name, ctx.Err())
+		case <-time.After(jitter(delay)):
+			delay = min(time.Duration(float64(delay)*1.5), interval)
 		}
 	}
 }
@@ -151,9 +173,9 @@ func With(c *konductor.Client, ctx context.Context, name string, fn func() error
 	return fn()
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.Gate, error) {
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.Gate, error) {
 	var gates syncv1.GateList
-	if err := c.K8sClient().List(ctx, &gates, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &gates, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list gates: %w", err)
 	}
 	return gates.Items, nil
@@ -201,38 +223,36 @@ func Delete(c *konductor.Client, ctx context.Context, name string) error {
 	return c.K8sClient().Delete(ctx, gate)
 }
 
-func Update(c *konductor.Client, ctx context.Context, gate *syncv1.Gate) error {
-	return c.K8sClient().Update(ctx, gate)
+// Update writes the gate's spec and metadata. See Client.Update for how
+// WithServerSideApply limits the write to the fields gate sets.
+func Update(c *konductor.Client, ctx context.Context, gate *syncv1.Gate, opts ...konductor.Option) error {
+	return c.Update(ctx, gate, opts...)
 }
 
+// Open opens the gate by hand, whatever its conditions
 func Open(c *konductor.Client, ctx context.Context, name string) error {
-	gate := &syncv1.Gate{}
-	gate.Name = name
-	gate.Namespace = c.Namespace()
-
-	err := c.RetryWithBackoff(ctx, func() error {
-		var g syncv1.Gate
-		if err := c.K8sClient().Get(ctx, types.NamespacedName{
-			Name: name, Namespace: c.Namespace(),
-		}, &g); err != nil {
-			return err
-		}
-		g.Status.Phase = syncv1.GatePhaseOpen
-		return c.K8sClient().Status().Update(ctx, &g)
-	}, nil)
-
-	if err != nil {
-		return err
-	}
+	return setPhase(c, ctx, name, syncv1.GatePhaseOpen, nil)
+}
 
-	// Wait for confirmation
-	return c.WaitForCondition(ctx, gate, func(obj client.Object) bool {
-		g := obj.(*syncv1.Gate)
-		return g.Status.Phase == syncv1.GatePhaseOpen
-	}, nil)
+// OpenIf opens the gate by hand only if it is in the expected phase, failing
+// with ErrUnexpectedPhase otherwise. The phase is checked against a fresh
+// read right before the update, so a concurrent Open or Close in between
+// makes the update conflict and the check run again rather than being
+// overwritten.
+func OpenIf(c *konductor.Client, ctx context.Context, name string, expected syncv1.GatePhase) error {
+	return setPhase(c, ctx, name, syncv1.GatePhaseOpen, &expected)
 }
 
+// Close puts the gate back to waiting for its conditions
 func Close(c *konductor.Client, ctx context.Context, name string) error {
+	return setPhase(c, ctx, name, syncv1.GatePhaseWaiting, nil)
+}
+
+// setPhase moves the gate to phase, first checking it is in expected unless
+// that is nil, and waits for the change to be seen. Each attempt re-reads the
+// gate, so an update that conflicts with a concurrent one is retried against
+// the latest state.
+func setPhase(c *konductor.Client, ctx context.Context, name string, phase syncv1.GatePhase, expected *syncv1.GatePhase) error {
 	gate := &syncv1.Gate{}
 	gate.Name = name
 	gate.Namespace = c.Namespace()
@@ -244,7 +264,10 @@ func Close(c *konductor.Client, ctx context.Context, name string) error {
 		}, &g); err != nil {
 			return err
 		}
-		g.Status.Phase = syncv1.GatePhaseWaiting
+		if expected != nil && g.Status.Phase != *expected {
+			return fmt.Errorf("%w: gate %s is %s, not %s", konductor.ErrUnexpectedPhase, name, g.Status.Phase, *expected)
+		}
+		g.Status.Phase = phase
 		return c.K8sClient().Status().Update(ctx, &g)
 	}, nil)
 
@@ -255,6 +278,6 @@ func Close(c *konductor.Client, ctx context.Context, name string) error {
 	// Wait for confirmation
 	return c.WaitForCondition(ctx, gate, func(obj client.Object) bool {
 		g := obj.(*syncv1.Gate)
-		return g.Status.Phase == syncv1.GatePhaseWaiting
+		return g.Status.Phase == phase
 	}, nil)
 }
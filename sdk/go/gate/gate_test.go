@@ -2,7 +2,9 @@ package gate
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,10 +12,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/internal/applytest"
 )
 
 func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
@@ -49,6 +54,61 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "gate1", gates[0].Name)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-gate",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-gate",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-gate", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-gate",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-gate",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestGet(t *testing.T) {
 	gate := &syncv1.Gate{
 		ObjectMeta: metav1.ObjectMeta{
@@ -228,3 +288,310 @@ func TestUpdate(t *testing.T) {
 	err := Update(client, context.Background(), gate)
 	assert.NoError(t, err)
 }
+
+func TestUpdate_ServerSideApplyKeepsConcurrentChanges(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "test-ns"},
+		Spec:       syncv1.GateSpec{Conditions: []syncv1.GateCondition{}},
+	}
+	k8sClient, _ := applytest.NewClient(t, gate)
+	client := konductor.NewFromClient(k8sClient, "test-ns")
+	ctx := context.Background()
+
+	// Someone else sets a timeout after the gate was read
+	current, err := Get(client, ctx, "test-gate")
+	require.NoError(t, err)
+	current.Spec.Timeout = &metav1.Duration{Duration: time.Hour}
+	require.NoError(t, client.K8sClient().Update(ctx, current))
+
+	err = Update(client, ctx, &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gate"},
+		Spec: syncv1.GateSpec{
+			Conditions: []syncv1.GateCondition{{Type: "Job", Name: "migrate", State: "Complete"}},
+		},
+	}, konductor.WithServerSideApply())
+	require.NoError(t, err)
+
+	updated, err := Get(client, ctx, "test-gate")
+	require.NoError(t, err)
+	require.Len(t, updated.Spec.Conditions, 1)
+	assert.Equal(t, "migrate", updated.Spec.Conditions[0].Name)
+	require.NotNil(t, updated.Spec.Timeout)
+	assert.Equal(t, time.Hour, updated.Spec.Timeout.Duration)
+}
+
+func TestWait_Timeout(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gate",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.GateStatus{
+			Phase: syncv1.GatePhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, gate)
+
+	err := Wait(client, context.Background(), "test-gate", konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestWait_PollInterval(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gate",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.GateStatus{
+			Phase: syncv1.GatePhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, gate)
+
+	time.AfterFunc(200*time.Millisecond, func() {
+		opened := gate.DeepCopy()
+		opened.Status.Phase = syncv1.GatePhaseOpen
+		assert.NoError(t, client.K8sClient().Status().Update(context.Background(), opened))
+	})
+
+	// The default backoff would not look again for two seconds
+	start := time.Now()
+	err := Wait(client, context.Background(), "test-gate",
+		konductor.WithPollInterval(50*time.Millisecond), konductor.WithTimeout(5*time.Second))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestJitter(t *testing.T) {
+	interval := 10 * time.Second
+	low := time.Duration(float64(interval) * (1 - pollJitter))
+	high := time.Duration(float64(interval) * (1 + pollJitter))
+
+	seen := map[time.Duration]bool{}
+	for range 1000 {
+		d := jitter(interval)
+		assert.GreaterOrEqual(t, d, low)
+		assert.LessOrEqual(t, d, high)
+		seen[d] = true
+	}
+	assert.Greater(t, len(seen), 1, "polls must not all wait the same time")
+}
+
+func TestPollInterval(t *testing.T) {
+	assert.Equal(t, DefaultPollInterval, pollInterval(&konductor.Options{}))
+
+	options := &konductor.Options{}
+	konductor.WithPollInterval(time.Second)(options)
+	assert.Equal(t, time.Second, pollInterval(options))
+}
+
+func TestWaitForConditions_Timeout(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gate",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.GateStatus{
+			ConditionStatuses: []syncv1.GateConditionStatus{
+				{Type: "Job", Name: "job1", Met: false},
+			},
+		},
+	}
+
+	client := setupTestClient(t, gate)
+
+	err := WaitForConditions(client, context.Background(), "test-gate", []string{"job1"}, konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout waiting for conditions in gate test-gate")
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestWait_ContextCancelledMidWait(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gate",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.GateStatus{
+			Phase: syncv1.GatePhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, gate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	err := Wait(client, ctx, "test-gate", konductor.WithTimeout(10*time.Second))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Contains(t, err.Error(), "waiting for gate test-gate")
+}
+
+func TestWaitForConditions_ContextCancelledMidWait(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gate",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.GateStatus{
+			ConditionStatuses: []syncv1.GateConditionStatus{
+				{Type: "Job", Name: "job1", Met: false},
+			},
+		},
+	}
+
+	client := setupTestClient(t, gate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	err := WaitForConditions(client, ctx, "test-gate", []string{"job1"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Contains(t, err.Error(), "waiting for conditions in gate test-gate")
+}
+
+func newWaitingGate() *syncv1.Gate {
+	return &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gate",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.GateStatus{Phase: syncv1.GatePhaseWaiting},
+	}
+}
+
+// setupConflictingClient returns a client on which the first conflicts status
+// updates of the gate are beaten by a concurrent one applying interfere, so
+// they fail with a conflict. A negative conflicts makes every update fail.
+func setupConflictingClient(t *testing.T, gate *syncv1.Gate, conflicts int, interfere func(*syncv1.Gate)) (*konductor.Client, *int) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	updates := 0
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(gate).
+		WithStatusSubresource(&syncv1.Gate{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				updates++
+				if conflicts < 0 || updates <= conflicts {
+					var current syncv1.Gate
+					if err := c.Get(ctx, client.ObjectKeyFromObject(obj), &current); err != nil {
+						return err
+					}
+					interfere(&current)
+					if err := c.Status().Update(ctx, &current); err != nil {
+						return err
+					}
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns"), &updates
+}
+
+func TestOpen_RetriesAfterConflict(t *testing.T) {
+	client, updates := setupConflictingClient(t, newWaitingGate(), 1, func(g *syncv1.Gate) {
+		g.Status.ConditionStatuses = []syncv1.GateConditionStatus{{Type: "Job", Name: "job1"}}
+	})
+
+	require.NoError(t, Open(client, context.Background(), "test-gate"))
+	assert.Equal(t, 2, *updates, "the conflicting update must be retried")
+
+	gate, err := Get(client, context.Background(), "test-gate")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.GatePhaseOpen, gate.Status.Phase)
+	assert.Len(t, gate.Status.ConditionStatuses, 1, "the concurrent update must not be clobbered")
+}
+
+func TestClose_RetriesAfterConcurrentOpen(t *testing.T) {
+	client, updates := setupConflictingClient(t, newWaitingGate(), 1, func(g *syncv1.Gate) {
+		g.Status.Phase = syncv1.GatePhaseOpen
+	})
+
+	require.NoError(t, Close(client, context.Background(), "test-gate"))
+	assert.Equal(t, 2, *updates)
+
+	gate, err := Get(client, context.Background(), "test-gate")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.GatePhaseWaiting, gate.Status.Phase)
+}
+
+func TestOpenIf(t *testing.T) {
+	client := setupTestClient(t, newWaitingGate())
+
+	require.NoError(t, OpenIf(client, context.Background(), "test-gate", syncv1.GatePhaseWaiting))
+
+	gate, err := Get(client, context.Background(), "test-gate")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.GatePhaseOpen, gate.Status.Phase)
+}
+
+func TestOpenIf_WrongPhase(t *testing.T) {
+	gate := newWaitingGate()
+	gate.Status.Phase = syncv1.GatePhaseFailed
+	client := setupTestClient(t, gate)
+
+	err := OpenIf(client, context.Background(), "test-gate", syncv1.GatePhaseWaiting)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrUnexpectedPhase))
+	assert.Contains(t, err.Error(), "gate test-gate is Failed, not Waiting")
+
+	current, err := Get(client, context.Background(), "test-gate")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.GatePhaseFailed, current.Status.Phase)
+}
+
+func TestOpenIf_RechecksPhaseAfterConflict(t *testing.T) {
+	client, updates := setupConflictingClient(t, newWaitingGate(), 1, func(g *syncv1.Gate) {
+		g.Status.Phase = syncv1.GatePhaseFailed
+	})
+
+	err := OpenIf(client, context.Background(), "test-gate", syncv1.GatePhaseWaiting)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrUnexpectedPhase))
+	assert.Equal(t, 1, *updates, "a phase that no longer matches must not be retried")
+
+	gate, err := Get(client, context.Background(), "test-gate")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.GatePhaseFailed, gate.Status.Phase)
+}
+
+func TestOpenIf_SucceedsWhenConflictKeepsPhase(t *testing.T) {
+	client, updates := setupConflictingClient(t, newWaitingGate(), 1, func(g *syncv1.Gate) {
+		g.Status.ConditionStatuses = []syncv1.GateConditionStatus{{Type: "Job", Name: "job1"}}
+	})
+
+	require.NoError(t, OpenIf(client, context.Background(), "test-gate", syncv1.GatePhaseWaiting))
+	assert.Equal(t, 2, *updates)
+
+	gate, err := Get(client, context.Background(), "test-gate")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.GatePhaseOpen, gate.Status.Phase)
+}
+
+func TestOpen_GivesUpOnPersistentConflicts(t *testing.T) {
+	client, _ := setupConflictingClient(t, newWaitingGate(), -1, func(g *syncv1.Gate) {
+		g.Status.Phase = syncv1.GatePhaseWaiting
+		g.Status.ConditionStatuses = append(g.Status.ConditionStatuses, syncv1.GateConditionStatus{Type: "Job"})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	require.Error(t, Open(client, ctx, "test-gate"))
+
+	gate, err := Get(client, context.Background(), "test-gate")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.GatePhaseWaiting, gate.Status.Phase)
+}
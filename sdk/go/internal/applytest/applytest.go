@@ -0,0 +1,64 @@
+// Package applytest provides the fake API server the SDK's tests use for
+// server-side applies, which the controller-runtime fake client does not
+// support.
+package applytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+)
+
+// Patch records how an apply was made
+type Patch struct {
+	Manager string
+	Force   bool
+	Body    string
+}
+
+// NewClient returns a fake client holding objects that stands in for the API
+// server's server-side apply by merging the applied fields into the stored
+// object. Each apply is recorded in the returned slice.
+func NewClient(t testing.TB, objects ...runtime.Object) (client.WithWatch, *[]Patch) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	var applied []Patch
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if patch.Type() != types.ApplyPatchType {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				patchOpts := &client.PatchOptions{}
+				patchOpts.ApplyOptions(opts)
+				data, err := patch.Data(obj)
+				if err != nil {
+					return err
+				}
+				applied = append(applied, Patch{
+					Manager: patchOpts.FieldManager,
+					Force:   patchOpts.Force != nil && *patchOpts.Force,
+					Body:    string(data),
+				})
+				return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+			},
+		}).
+		Build()
+
+	return k8sClient, &applied
+}
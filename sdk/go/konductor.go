@@ -5,6 +5,7 @@ import (
 	"github.com/LogicIQ/konductor/sdk/go/barrier"
 	"github.com/LogicIQ/konductor/sdk/go/client"
 	"github.com/LogicIQ/konductor/sdk/go/gate"
+	"github.com/LogicIQ/konductor/sdk/go/leader"
 	"github.com/LogicIQ/konductor/sdk/go/lease"
 	"github.com/LogicIQ/konductor/sdk/go/mutex"
 	"github.com/LogicIQ/konductor/sdk/go/semaphore"
@@ -19,42 +20,104 @@ type Config = client.Config
 // Options for coordination operations
 type Options = client.Options
 
+// RetryStats counts the client's retried operations
+type RetryStats = client.RetryStats
+
+// ExpiredCounts is what CollectExpired cleared, by kind
+type ExpiredCounts = client.ExpiredCounts
+
 // Option functions
 var (
-	WithTTL      = client.WithTTL
-	WithTimeout  = client.WithTimeout
-	WithPriority = client.WithPriority
-	WithHolder   = client.WithHolder
-	WithQuorum   = client.WithQuorum
+	WithTTL             = client.WithTTL
+	WithTimeout         = client.WithTimeout
+	WithPriority        = client.WithPriority
+	WithHolder          = client.WithHolder
+	WithQuorum          = client.WithQuorum
+	WithGeneration      = client.WithGeneration
+	WithCount           = client.WithCount
+	WithBurst           = client.WithBurst
+	WithPeriod          = client.WithPeriod
+	WithPollInterval    = client.WithPollInterval
+	WithOwnerPod        = client.WithOwnerPod
+	WithWeight          = client.WithWeight
+	WithAutoRenew       = client.WithAutoRenew
+	WithLabelSelector   = client.WithLabelSelector
+	WithAllNamespaces   = client.WithAllNamespaces
+	WithServerSideApply = client.WithServerSideApply
+	WithFieldManager    = client.WithFieldManager
+	WithAutoCreate      = client.WithAutoCreate
+	WithoutConfirm      = client.WithoutConfirm
+)
+
+// Sentinel errors for matching failures with errors.Is
+var (
+	ErrTimeout         = client.ErrTimeout
+	ErrAcquireTimeout  = client.ErrAcquireTimeout
+	ErrNotHolder       = client.ErrNotHolder
+	ErrDenied          = client.ErrDenied
+	ErrLocked          = client.ErrLocked
+	ErrDraining        = client.ErrDraining
+	ErrNotExecuted     = client.ErrNotExecuted
+	ErrWouldBlock      = client.ErrWouldBlock
+	ErrNegativeCounter = client.ErrNegativeCounter
+	ErrCircuitOpen     = client.ErrCircuitOpen
+	ErrUnexpectedPhase = client.ErrUnexpectedPhase
+	ErrNoQuorum        = client.ErrNoQuorum
+	ErrNotFound        = client.ErrNotFound
 )
 
 // New creates a new konductor client
 var New = client.New
 
+// DefaultHolder returns the holder used when none is given with WithHolder
+var DefaultHolder = client.DefaultHolder
+
+// HolderObjectName returns the name of the object holder creates for the
+// named primitive, rewritten if needed to be a valid object name
+var HolderObjectName = client.HolderObjectName
+
 // NewFromClient creates a konductor client from an existing Kubernetes client
 var NewFromClient = client.NewFromClient
 
+// NewFromConfig creates a konductor client that connects with a rest.Config
+var NewFromConfig = client.NewFromConfig
+
+// MultiClient fans operations out to one client per cluster
+type MultiClient = client.MultiClient
+
+// NewMultiClient creates a MultiClient over one client per cluster
+var NewMultiClient = client.NewMultiClient
+
 // Semaphore operations
 var (
-	SemaphoreCreate  = semaphore.Create
-	SemaphoreDelete  = semaphore.Delete
-	SemaphoreUpdate  = semaphore.Update
-	SemaphoreGet     = semaphore.Get
-	SemaphoreList    = semaphore.List
-	SemaphoreAcquire = semaphore.Acquire
-	SemaphoreWith    = semaphore.With
+	SemaphoreCreate        = semaphore.Create
+	SemaphoreDelete        = semaphore.Delete
+	SemaphoreUpdate        = semaphore.Update
+	SemaphoreGet           = semaphore.Get
+	SemaphoreList          = semaphore.List
+	SemaphoreAcquire       = semaphore.Acquire
+	SemaphoreTryAcquire    = semaphore.TryAcquire
+	SemaphoreAcquireN      = semaphore.AcquireN
+	SemaphoreAcquireQuorum = semaphore.AcquireQuorum
+	SemaphoreWith          = semaphore.With
+	SemaphoreReleaseAll    = semaphore.ReleaseAll
+	SemaphoreSetDrain      = semaphore.SetDrain
+	SemaphoreSetPermits    = semaphore.SetPermits
 )
 
 // Barrier operations
 var (
-	BarrierCreate = barrier.Create
-	BarrierDelete = barrier.Delete
-	BarrierUpdate = barrier.Update
-	BarrierGet    = barrier.Get
-	BarrierList   = barrier.List
-	BarrierWait   = barrier.Wait
-	BarrierArrive = barrier.Arrive
-	BarrierWith   = barrier.With
+	BarrierCreate       = barrier.Create
+	BarrierDelete       = barrier.Delete
+	BarrierUpdate       = barrier.Update
+	BarrierGet          = barrier.Get
+	BarrierList         = barrier.List
+	BarrierWait         = barrier.Wait
+	BarrierWaitForCount = barrier.WaitForCount
+	BarrierArrive       = barrier.Arrive
+	BarrierArriveMany   = barrier.ArriveMany
+	BarrierWith         = barrier.With
+	BarrierReset        = barrier.Reset
 )
 
 // Gate operations
@@ -67,23 +130,32 @@ var (
 	GateWait   = gate.Wait
 	GateCheck  = gate.Check
 	GateOpen   = gate.Open
+	GateOpenIf = gate.OpenIf
 	GateClose  = gate.Close
 	GateWith   = gate.With
 )
 
 // Lease operations
 var (
-	LeaseCreate      = lease.Create
-	LeaseDelete      = lease.Delete
-	LeaseUpdate      = lease.Update
-	LeaseGet         = lease.Get
-	LeaseList        = lease.List
-	LeaseAcquire     = lease.Acquire
-	LeaseTryAcquire  = lease.TryAcquire
-	LeaseWith        = lease.With
-	LeaseIsAvailable = lease.IsAvailable
+	LeaseCreate          = lease.Create
+	LeaseDelete          = lease.Delete
+	LeaseUpdate          = lease.Update
+	LeaseGet             = lease.Get
+	LeaseList            = lease.List
+	LeaseAcquire         = lease.Acquire
+	LeaseTryAcquire      = lease.TryAcquire
+	LeaseAcquireMajority = lease.AcquireMajority
+	LeaseAcquireAll      = lease.AcquireAll
+	LeaseWith            = lease.With
+	LeaseIsAvailable     = lease.IsAvailable
+	LeaseTransfer        = lease.Transfer
+	LeaseCurrentHolder   = lease.CurrentHolder
+	LeaseWatch           = lease.Watch
 )
 
+// LeaderRun campaigns for leadership of a lease, see leader.Run
+var LeaderRun = leader.Run
+
 // Mutex operations
 var (
 	MutexCreate   = mutex.Create
@@ -96,4 +168,5 @@ var (
 	MutexUnlock   = mutex.Unlock
 	MutexWith     = mutex.With
 	MutexIsLocked = mutex.IsLocked
+	MutexOwner    = mutex.Owner
 )
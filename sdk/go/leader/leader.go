@@ -0,0 +1,140 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/lease"
+)
+
+// retryPeriod is how long a candidate waits before campaigning again after a
+// failed acquisition or after losing leadership. Overridden in tests.
+var retryPeriod = 2 * time.Second
+
+// defaultRenewInterval is used for leases without a TTL when WithAutoRenew is
+// not given
+const defaultRenewInterval = 10 * time.Second
+
+// Run campaigns for leadership of the named lease until ctx is cancelled.
+// Whenever holder is granted the lease it is renewed automatically and
+// onElected runs with a context that is cancelled once leadership is lost:
+// when a renewal fails, when ctx is cancelled or when onElected itself
+// returns. Run then waits for onElected to return, gives the lease up so
+// another candidate can take over, calls onRevoked and campaigns again after
+// a short pause.
+//
+// An empty holder defaults to konductor.DefaultHolder. WithAutoRenew sets the
+// renewal interval, which otherwise defaults to a third of the lease's TTL,
+// and WithTimeout and WithPriority apply to each acquisition attempt. Run
+// returns nil once ctx is cancelled.
+func Run(c *konductor.Client, ctx context.Context, leaseName, holder string, onElected func(ctx context.Context), onRevoked func(), opts ...konductor.Option) error {
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if holder == "" {
+		holder = konductor.DefaultHolder()
+	}
+
+	renewInterval := options.AutoRenew
+	if renewInterval <= 0 {
+		l, err := lease.Get(c, ctx, leaseName)
+		if err != nil {
+			return fmt.Errorf("failed to run leader election for lease %s: %w", leaseName, err)
+		}
+		renewInterval = renewIntervalFor(l)
+	}
+
+	acquireOpts := append([]konductor.Option{}, opts...)
+	acquireOpts = append(acquireOpts, konductor.WithHolder(holder), konductor.WithAutoRenew(renewInterval))
+
+	for {
+		held, err := lease.Acquire(c, ctx, leaseName, acquireOpts...)
+		switch {
+		case err == nil:
+			lead(c, ctx, held, onElected)
+			if onRevoked != nil {
+				onRevoked()
+			}
+		case errors.IsAlreadyExists(err):
+			// A previous run with the same holder left its request behind;
+			// remove it so the next attempt can create a fresh one
+			_ = client.IgnoreNotFound(c.ReleaseLease(ctx, leaseName, holder))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(retryPeriod):
+		}
+	}
+}
+
+// lead runs onElected for as long as held stays renewed and steps down once
+// it returns, a renewal fails or ctx is cancelled
+func lead(c *konductor.Client, ctx context.Context, held *lease.Lease, onElected func(ctx context.Context)) {
+	electedCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		onElected(electedCtx)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	case <-held.RenewalErrors():
+	}
+	cancel()
+	<-done
+
+	// ctx may already be done, so step down with a detached context. Both
+	// calls are best effort: if they fail the lease still expires on its own
+	cleanupCtx, cancelCleanup := konductor.CleanupContext(ctx)
+	defer cancelCleanup()
+	_ = held.Release(cleanupCtx)
+	_ = stepDown(c, cleanupCtx, held.Name(), held.Holder())
+}
+
+// stepDown frees the lease if holder still holds it. The controller only frees
+// a lease once it expires, so without this the next candidate would have to
+// wait out the TTL.
+func stepDown(c *konductor.Client, ctx context.Context, name, holder string) error {
+	l := &syncv1.Lease{}
+	l.Name = name
+	l.Namespace = c.Namespace()
+
+	err := c.StatusUpdateWithRetry(ctx, l, func(obj client.Object) error {
+		current := obj.(*syncv1.Lease)
+		if current.Status.Holder != holder {
+			return nil
+		}
+		current.Status.Holder = ""
+		current.Status.Phase = syncv1.LeasePhaseAvailable
+		current.Status.AcquiredAt = nil
+		current.Status.ExpiresAt = nil
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to step down from lease %s: %w", name, err)
+	}
+	return nil
+}
+
+// renewIntervalFor renews three times per TTL so a single failed renewal does
+// not lose the lease
+func renewIntervalFor(l *syncv1.Lease) time.Duration {
+	if l.Spec.TTL != nil && l.Spec.TTL.Duration > 0 {
+		return l.Spec.TTL.Duration / 3
+	}
+	return defaultRenewInterval
+}
@@ -0,0 +1,233 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.Lease{}, &syncv1.LeaseRequest{}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func newTestLease() *syncv1.Lease {
+	return &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Minute},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase: syncv1.LeasePhaseAvailable,
+		},
+	}
+}
+
+// runLeaseController stands in for the lease controller, granting an
+// available lease to the first pending request until ctx is cancelled
+func runLeaseController(t *testing.T, ctx context.Context, c *konductor.Client) {
+	t.Helper()
+
+	go func() {
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			var l syncv1.Lease
+			if err := c.K8sClient().Get(ctx, client.ObjectKey{Name: "test-lease", Namespace: "test-ns"}, &l); err != nil {
+				continue
+			}
+			if l.Status.Holder != "" {
+				continue
+			}
+
+			var requests syncv1.LeaseRequestList
+			if err := c.K8sClient().List(ctx, &requests, client.InNamespace("test-ns"),
+				client.MatchingLabels{"lease": "test-lease"}); err != nil || len(requests.Items) == 0 {
+				continue
+			}
+
+			request := &requests.Items[0]
+			l.Status.Holder = request.Spec.Holder
+			l.Status.Phase = syncv1.LeasePhaseHeld
+			expiresAt := metav1.NewTime(time.Now().Add(l.Spec.TTL.Duration))
+			l.Status.ExpiresAt = &expiresAt
+			if err := c.K8sClient().Status().Update(ctx, &l); err != nil {
+				continue
+			}
+			request.Status.Phase = syncv1.LeaseRequestPhaseGranted
+			_ = c.K8sClient().Status().Update(ctx, request)
+		}
+	}()
+}
+
+// candidate records the leadership callbacks of one Run
+type candidate struct {
+	elected chan struct{}
+	revoked chan struct{}
+	resign  chan struct{}
+	lost    chan struct{}
+}
+
+func newCandidate() *candidate {
+	return &candidate{
+		elected: make(chan struct{}, 8),
+		revoked: make(chan struct{}, 8),
+		resign:  make(chan struct{}),
+		lost:    make(chan struct{}, 8),
+	}
+}
+
+func (c *candidate) onElected(ctx context.Context) {
+	c.elected <- struct{}{}
+	select {
+	case <-c.resign:
+	case <-ctx.Done():
+		c.lost <- struct{}{}
+	}
+}
+
+func (c *candidate) onRevoked() {
+	c.revoked <- struct{}{}
+}
+
+func receive(t *testing.T, ch <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal(msg)
+	}
+}
+
+func setRetryPeriod(t *testing.T, d time.Duration) {
+	previous := retryPeriod
+	retryPeriod = d
+	t.Cleanup(func() { retryPeriod = previous })
+}
+
+func TestRun_SecondCandidateTakesOverAfterFirstReleases(t *testing.T) {
+	setRetryPeriod(t, 100*time.Millisecond)
+	c := setupTestClient(t, newTestLease())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runLeaseController(t, ctx, c)
+
+	opts := []konductor.Option{konductor.WithAutoRenew(20 * time.Millisecond), konductor.WithTimeout(5 * time.Second)}
+
+	first, second := newCandidate(), newCandidate()
+	results := make(chan error, 2)
+	go func() { results <- Run(c, ctx, "test-lease", "candidate-1", first.onElected, first.onRevoked, opts...) }()
+	receive(t, first.elected, "first candidate was not elected")
+
+	go func() {
+		results <- Run(c, ctx, "test-lease", "candidate-2", second.onElected, second.onRevoked, opts...)
+	}()
+
+	select {
+	case <-second.elected:
+		t.Fatal("second candidate elected while the first still leads")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(first.resign)
+	receive(t, first.revoked, "first candidate was not revoked after resigning")
+	receive(t, second.elected, "second candidate did not take over")
+
+	l := &syncv1.Lease{}
+	require.NoError(t, c.K8sClient().Get(ctx, client.ObjectKey{Name: "test-lease", Namespace: "test-ns"}, l))
+	assert.Equal(t, "candidate-2", l.Status.Holder)
+
+	cancel()
+	receive(t, second.lost, "second candidate's context was not cancelled")
+	receive(t, second.revoked, "second candidate was not revoked on shutdown")
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-results:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Run did not return after the context was cancelled")
+		}
+	}
+}
+
+func TestRun_RevokesWhenLeaseIsLost(t *testing.T) {
+	setRetryPeriod(t, time.Hour)
+	c := setupTestClient(t, newTestLease())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runLeaseController(t, ctx, c)
+
+	leader := newCandidate()
+	result := make(chan error, 1)
+	go func() {
+		result <- Run(c, ctx, "test-lease", "candidate-1", leader.onElected, leader.onRevoked,
+			konductor.WithAutoRenew(20*time.Millisecond))
+	}()
+	receive(t, leader.elected, "candidate was not elected")
+
+	// Another holder takes the lease, so the next renewal fails
+	l := &syncv1.Lease{}
+	require.NoError(t, c.K8sClient().Get(ctx, client.ObjectKey{Name: "test-lease", Namespace: "test-ns"}, l))
+	l.Status.Holder = "intruder"
+	require.NoError(t, c.K8sClient().Status().Update(ctx, l))
+
+	receive(t, leader.lost, "leadership context was not cancelled after the lease was lost")
+	receive(t, leader.revoked, "candidate was not revoked after the lease was lost")
+
+	require.NoError(t, c.K8sClient().Get(ctx, client.ObjectKey{Name: "test-lease", Namespace: "test-ns"}, l))
+	assert.Equal(t, "intruder", l.Status.Holder, "stepping down must not free a lease held by someone else")
+
+	cancel()
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestRun_LeaseNotFound(t *testing.T) {
+	c := setupTestClient(t)
+
+	err := Run(c, context.Background(), "missing", "candidate-1", func(context.Context) {}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to run leader election for lease missing")
+}
+
+func TestRenewIntervalFor(t *testing.T) {
+	assert.Equal(t, 20*time.Second, renewIntervalFor(newTestLease()))
+	assert.Equal(t, defaultRenewInterval, renewIntervalFor(&syncv1.Lease{}))
+}
@@ -2,32 +2,45 @@ package lease
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
-	"os"
+	"slices"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
+// now is overridden in tests to simulate the passage of time
+var now = time.Now
+
 // Lease represents an acquired lease
 type Lease struct {
-	client    *konductor.Client
-	name      string
-	requestID string
-	holder    string
-	ctx       context.Context
-	cancelCtx context.CancelFunc
+	client     *konductor.Client
+	name       string
+	requestID  string
+	holder     string
+	ctx        context.Context
+	cancelCtx  context.CancelFunc
+	renewErrs  chan error
+	renewDone  chan struct{}
+	fenceToken int64
 }
 
 func (l *Lease) Release(ctx context.Context) error {
 	if l.cancelCtx != nil {
 		l.cancelCtx()
 	}
+	if l.renewDone != nil {
+		<-l.renewDone
+	}
 
 	request := &syncv1.LeaseRequest{
 		ObjectMeta: metav1.ObjectMeta{
@@ -49,6 +62,72 @@ func (l *Lease) Name() string {
 	return l.name
 }
 
+// FenceToken returns the token issued when this lease was granted. Tokens increase
+// with every new holder, so external systems can reject writes carrying an older one.
+// It is 0 if the grant could not be confirmed on the Lease.
+func (l *Lease) FenceToken() int64 {
+	return l.fenceToken
+}
+
+// RenewalErrors returns a channel that receives errors from automatic renewal.
+// The channel is closed once renewal stops; it is nil when WithAutoRenew was not used.
+func (l *Lease) RenewalErrors() <-chan error {
+	return l.renewErrs
+}
+
+func (l *Lease) startRenewal(interval time.Duration) {
+	l.renewErrs = make(chan error, 1)
+	l.renewDone = make(chan struct{})
+
+	go func() {
+		defer close(l.renewDone)
+		defer close(l.renewErrs)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.renew(l.ctx); err != nil && l.ctx.Err() == nil {
+					// Drop the error if the previous one has not been consumed yet
+					select {
+					case l.renewErrs <- err:
+					default:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// renew extends the lease expiry by its TTL and bumps the renewal counter
+func (l *Lease) renew(ctx context.Context) error {
+	err := l.client.RetryWithBackoff(ctx, func() error {
+		lease, err := Get(l.client, ctx, l.name)
+		if err != nil {
+			return err
+		}
+		if lease.Status.Holder != l.holder {
+			return fmt.Errorf("lease %s is no longer held by %s: %w", l.name, l.holder, konductor.ErrNotHolder)
+		}
+
+		if lease.Spec.TTL != nil && lease.Spec.TTL.Duration > 0 {
+			expiresAt := metav1.NewTime(now().Add(lease.Spec.TTL.Duration))
+			lease.Status.ExpiresAt = &expiresAt
+		}
+		lease.Status.RenewCount++
+
+		return l.client.K8sClient().Status().Update(ctx, lease)
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to renew lease %s: %w", l.name, err)
+	}
+	return nil
+}
+
 // Acquire attempts to acquire lease with retry and confirmation
 func Acquire(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*Lease, error) {
 	options := &konductor.Options{Timeout: 0, Priority: 0}
@@ -58,33 +137,20 @@ func Acquire(c *konductor.Client, ctx context.Context, name string, opts ...kond
 
 	holder := options.Holder
 	if holder == "" {
-		if holder = os.Getenv("HOSTNAME"); holder == "" {
-			holder = fmt.Sprintf("sdk-%d", time.Now().Unix())
-		}
-	}
-
-	requestID := fmt.Sprintf("%s-%s", name, holder)
-	request := &syncv1.LeaseRequest{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      requestID,
-			Namespace: c.Namespace(),
-			Labels:    map[string]string{"lease": name},
-		},
-		Spec: syncv1.LeaseRequestSpec{
-			Lease:  name,
-			Holder: holder,
-		},
+		holder = konductor.DefaultHolder()
 	}
 
-	if options.Priority > 0 {
-		request.Spec.Priority = &options.Priority
+	if _, err := getLease(c, ctx, name, options); err != nil {
+		return nil, err
 	}
 
+	request := newRequest(c, name, holder, options)
 	if err := c.K8sClient().Create(ctx, request); err != nil {
 		return nil, fmt.Errorf("failed to create lease request: %w", err)
 	}
 
-	// Wait for lease decision with exponential backoff
+	// Watch for the controller's decision, falling back to polling if the
+	// watch cannot be established
 	config := &konductor.WaitConfig{
 		InitialDelay: 1 * time.Second,
 		MaxDelay:     5 * time.Second,
@@ -97,7 +163,7 @@ func Acquire(c *konductor.Client, ctx context.Context, name string, opts ...kond
 		config.Timeout = options.Timeout
 	}
 
-	err := c.WaitForCondition(ctx, request, func(obj client.Object) bool {
+	err := c.WatchForCondition(ctx, request, func(obj client.Object) bool {
 		req, ok := obj.(*syncv1.LeaseRequest)
 		if !ok {
 			return false
@@ -106,7 +172,15 @@ func Acquire(c *konductor.Client, ctx context.Context, name string, opts ...kond
 	}, config)
 
 	if err != nil {
-		if deleteErr := c.K8sClient().Delete(ctx, request); deleteErr != nil {
+		// ctx may already be done, so clean up with a detached context
+		cleanupCtx, cancel := konductor.CleanupContext(ctx)
+		defer cancel()
+		if ctx.Err() != nil {
+			err = fmt.Errorf("context cancelled while waiting for lease %s: %w", name, ctx.Err())
+		} else if wait.Interrupted(err) {
+			err = timeoutError(c, cleanupCtx, name, holder, err)
+		}
+		if deleteErr := c.K8sClient().Delete(cleanupCtx, request); deleteErr != nil {
 			return nil, fmt.Errorf("%w (cleanup failed: %v)", err, deleteErr)
 		}
 		return nil, err
@@ -119,21 +193,92 @@ func Acquire(c *konductor.Client, ctx context.Context, name string, opts ...kond
 
 	if request.Status.Phase == syncv1.LeaseRequestPhaseDenied {
 		if deleteErr := c.K8sClient().Delete(ctx, request); deleteErr != nil {
-			return nil, fmt.Errorf("lease request denied for %s (cleanup failed: %v)", name, deleteErr)
+			return nil, fmt.Errorf("lease request %w for %s (cleanup failed: %v)", konductor.ErrDenied, name, deleteErr)
 		}
-		return nil, fmt.Errorf("lease request denied for %s", name)
+		return nil, fmt.Errorf("lease request %w for %s", konductor.ErrDenied, name)
+	}
+
+	// The controller records the new holder on the Lease right after granting the
+	// request; read the fence token from there once it shows up
+	var fenceToken int64
+	granted := &syncv1.Lease{}
+	granted.Name = name
+	granted.Namespace = c.Namespace()
+	if err := c.WaitForCondition(ctx, granted, func(obj client.Object) bool {
+		l, ok := obj.(*syncv1.Lease)
+		return ok && l.Status.Holder == holder
+	}, &konductor.WaitConfig{InitialDelay: 100 * time.Millisecond, MaxDelay: 500 * time.Millisecond, Factor: 1.5, Timeout: 2 * time.Second}); err == nil {
+		fenceToken = granted.Status.FenceToken
+	}
+
+	return newLease(c, ctx, request, fenceToken, options), nil
+}
+
+// timeoutError wraps ErrTimeout for an Acquire of name that gave up, saying
+// who holds the lease now, if anyone other than holder does, so a contended
+// lease can be told apart from a request the controller never decided on
+func timeoutError(c *konductor.Client, ctx context.Context, name, holder string, err error) error {
+	lease := &syncv1.Lease{}
+	if getErr := c.K8sClient().Get(ctx, types.NamespacedName{Name: name, Namespace: c.Namespace()}, lease); getErr == nil &&
+		lease.Status.Holder != "" && lease.Status.Holder != holder {
+		return fmt.Errorf("%w waiting for lease %s: held by %s: %w", konductor.ErrTimeout, name, lease.Status.Holder, err)
+	}
+	return fmt.Errorf("%w waiting for lease %s: %w", konductor.ErrTimeout, name, err)
+}
+
+// getLease fetches the named lease. With WithAutoCreate a missing lease is
+// created first with the default TTL.
+func getLease(c *konductor.Client, ctx context.Context, name string, options *konductor.Options) (*syncv1.Lease, error) {
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.Namespace()},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: syncv1.DefaultLeaseTTL}},
+	}
+	if err := c.GetOrCreate(ctx, "lease", lease, options.AutoCreate); err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// newRequest builds the LeaseRequest holder files to acquire the named lease
+func newRequest(c *konductor.Client, name, holder string, options *konductor.Options) *syncv1.LeaseRequest {
+	request := &syncv1.LeaseRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      konductor.HolderObjectName(name, holder),
+			Namespace: c.Namespace(),
+			Labels:    map[string]string{"lease": name},
+		},
+		Spec: syncv1.LeaseRequestSpec{
+			Lease:  name,
+			Holder: holder,
+		},
 	}
 
+	if options.Priority > 0 {
+		request.Spec.Priority = &options.Priority
+	}
+	return request
+}
+
+// newLease returns the handle for a granted request, renewing it in the
+// background when WithAutoRenew was given
+func newLease(c *konductor.Client, ctx context.Context, request *syncv1.LeaseRequest, fenceToken int64, options *konductor.Options) *Lease {
 	// Create a context for the lease that can be cancelled on Release
 	leaseCtx, cancelCtx := context.WithCancel(ctx)
-	return &Lease{
-		client:    c,
-		name:      name,
-		requestID: requestID,
-		holder:    holder,
-		ctx:       leaseCtx,
-		cancelCtx: cancelCtx,
-	}, nil
+	lease := &Lease{
+		client:     c,
+		name:       request.Spec.Lease,
+		requestID:  request.Name,
+		holder:     request.Spec.Holder,
+		ctx:        leaseCtx,
+		cancelCtx:  cancelCtx,
+		fenceToken: fenceToken,
+	}
+
+	if options.AutoRenew > 0 {
+		lease.startRenewal(options.AutoRenew)
+	}
+
+	return lease
 }
 
 func With(c *konductor.Client, ctx context.Context, name string, fn func() error, opts ...konductor.Option) (err error) {
@@ -153,14 +298,313 @@ func With(c *konductor.Client, ctx context.Context, name string, fn func() error
 	return fn()
 }
 
+// MajorityLease is a lease of the same name held in a majority of clusters,
+// acquired by AcquireMajority. Holding it means no other holder can hold a
+// majority too, even while one cluster is unreachable.
+type MajorityLease struct {
+	name   string
+	leases []*Lease
+}
+
+// Release releases the lease in every cluster it was acquired in, carrying
+// on past failures and reporting all of them.
+func (l *MajorityLease) Release(ctx context.Context) error {
+	var errs []error
+	for _, lease := range l.leases {
+		if err := lease.Release(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := stderrors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to release lease %s in %d of %d clusters: %w", l.name, len(errs), len(l.leases), err)
+	}
+	return nil
+}
+
+func (l *MajorityLease) Name() string {
+	return l.name
+}
+
+// Leases returns the lease acquired in each cluster that granted it. Their
+// fence tokens are issued per cluster and cannot be compared across them.
+func (l *MajorityLease) Leases() []*Lease {
+	return l.leases
+}
+
+// AcquireMajority acquires the named lease in every cluster of m at once,
+// with the same options as Acquire, and succeeds once a majority of clusters
+// have granted it. If too few do, the grants it did get are released and it
+// fails with ErrNoQuorum. Every cluster is waited for, bounded by WithTimeout
+// as for Acquire.
+func AcquireMajority(m *konductor.MultiClient, ctx context.Context, name string, opts ...konductor.Option) (*MajorityLease, error) {
+	leases, err := konductor.AcquireMajority(m, ctx, func(c *konductor.Client) (*Lease, error) {
+		return Acquire(c, ctx, name, opts...)
+	}, func(ctx context.Context, lease *Lease) error {
+		return lease.Release(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lease %s on a majority of clusters: %w", name, err)
+	}
+	return &MajorityLease{name: name, leases: leases}, nil
+}
+
+// LeaseSet is a group of distinct leases held together, acquired by
+// AcquireAll
+type LeaseSet struct {
+	leases []*Lease
+}
+
+// Release releases every lease in the set, in the reverse of the order they
+// were acquired, carrying on past failures and reporting all of them.
+func (s *LeaseSet) Release(ctx context.Context) error {
+	var errs []error
+	for i := len(s.leases) - 1; i >= 0; i-- {
+		if err := s.leases[i].Release(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := stderrors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to release %d of %d leases: %w", len(errs), len(s.leases), err)
+	}
+	return nil
+}
+
+// Names returns the names of the leases in the set, in the order they were
+// acquired
+func (s *LeaseSet) Names() []string {
+	names := make([]string, len(s.leases))
+	for i, lease := range s.leases {
+		names[i] = lease.Name()
+	}
+	return names
+}
+
+// Leases returns the leases in the set, in the order they were acquired
+func (s *LeaseSet) Leases() []*Lease {
+	return s.leases
+}
+
+// AcquireAll acquires every named lease, with the same options as Acquire.
+// The leases are always taken in sorted name order, so two holders needing
+// overlapping sets cannot each hold a lease the other is waiting for. If any
+// lease cannot be acquired, those already held are released before the
+// error is returned. WithTimeout bounds the wait for each lease, not for the
+// whole set.
+func AcquireAll(c *konductor.Client, ctx context.Context, names []string, opts ...konductor.Option) (*LeaseSet, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("failed to acquire leases: no lease names given")
+	}
+
+	ordered := slices.Clone(names)
+	slices.Sort(ordered)
+	for i := 1; i < len(ordered); i++ {
+		if ordered[i] == ordered[i-1] {
+			return nil, fmt.Errorf("failed to acquire leases: lease %s is listed more than once", ordered[i])
+		}
+	}
+
+	set := &LeaseSet{}
+	for _, name := range ordered {
+		lease, err := Acquire(c, ctx, name, opts...)
+		if err == nil {
+			set.leases = append(set.leases, lease)
+			continue
+		}
+
+		err = fmt.Errorf("failed to acquire lease %s after acquiring %d of %d: %w", name, len(set.leases), len(ordered), err)
+		if len(set.leases) == 0 {
+			return nil, err
+		}
+		// ctx may already be done, so release with a detached context
+		cleanupCtx, cancel := konductor.CleanupContext(ctx)
+		defer cancel()
+		if releaseErr := set.Release(cleanupCtx); releaseErr != nil {
+			return nil, fmt.Errorf("%w (cleanup failed: %v)", err, releaseErr)
+		}
+		return nil, err
+	}
+	return set, nil
+}
+
+// TryAcquire attempts to acquire the lease without waiting. It fails straight
+// away with ErrWouldBlock if the lease is held by someone else. Otherwise it
+// files a request and checks it once. If the controller has not granted it
+// yet, the request is withdrawn and ErrWouldBlock is returned as well.
 func TryAcquire(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*Lease, error) {
-	opts = append([]konductor.Option{konductor.WithTimeout(1 * time.Second)}, opts...)
-	return Acquire(c, ctx, name, opts...)
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	holder := options.Holder
+	if holder == "" {
+		holder = konductor.DefaultHolder()
+	}
+
+	current, err := getLease(c, ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status.Holder != "" && current.Status.Holder != holder &&
+		(current.Status.ExpiresAt == nil || current.Status.ExpiresAt.After(now())) {
+		return nil, fmt.Errorf("lease %s is held by %s: %w", name, current.Status.Holder, konductor.ErrWouldBlock)
+	}
+
+	request := newRequest(c, name, holder, options)
+	if err := c.K8sClient().Create(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create lease request: %w", err)
+	}
+
+	// withdraw removes the request once it is clear it will not be granted
+	// right away; ctx may already be done, so use a detached context
+	withdraw := func(err error) (*Lease, error) {
+		cleanupCtx, cancel := konductor.CleanupContext(ctx)
+		defer cancel()
+		if deleteErr := c.K8sClient().Delete(cleanupCtx, request); client.IgnoreNotFound(deleteErr) != nil {
+			return nil, fmt.Errorf("%w (cleanup failed: %v)", err, deleteErr)
+		}
+		return nil, err
+	}
+
+	if err := c.K8sClient().Get(ctx, client.ObjectKeyFromObject(request), request); err != nil {
+		return withdraw(fmt.Errorf("failed to get lease request: %w", err))
+	}
+
+	switch request.Status.Phase {
+	case syncv1.LeaseRequestPhaseGranted:
+	case syncv1.LeaseRequestPhaseDenied:
+		return withdraw(fmt.Errorf("lease request %w for %s", konductor.ErrDenied, name))
+	default:
+		return withdraw(fmt.Errorf("lease %s was not granted immediately: %w", name, konductor.ErrWouldBlock))
+	}
+
+	// The grant is already visible, so the fence token is either on the Lease
+	// now or not confirmed at all
+	var fenceToken int64
+	if granted, err := Get(c, ctx, name); err == nil && granted.Status.Holder == holder {
+		fenceToken = granted.Status.FenceToken
+	}
+
+	return newLease(c, ctx, request, fenceToken, options), nil
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.Lease, error) {
+// Renew extends a held lease by its TTL on behalf of the holder set with
+// WithHolder, without going through a LeaseRequest. It fails with ErrNotHolder
+// if the lease is held by someone else and ErrExpired if the TTL has already
+// elapsed, since the controller may grant an expired lease to another holder.
+func Renew(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*syncv1.Lease, error) {
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	lease := &syncv1.Lease{}
+	lease.Name = name
+	lease.Namespace = c.Namespace()
+
+	err := c.StatusUpdateWithRetry(ctx, lease, func(obj client.Object) error {
+		l := obj.(*syncv1.Lease)
+		if l.Status.Holder == "" || l.Status.Holder != options.Holder {
+			return fmt.Errorf("lease %s is not held by %s: %w", name, options.Holder, konductor.ErrNotHolder)
+		}
+
+		current := now()
+		if l.Status.Phase == syncv1.LeasePhaseExpired ||
+			(l.Status.ExpiresAt != nil && !l.Status.ExpiresAt.After(current)) {
+			return fmt.Errorf("lease %s held by %s: %w", name, options.Holder, konductor.ErrExpired)
+		}
+
+		if l.Spec.TTL != nil && l.Spec.TTL.Duration > 0 {
+			expiresAt := metav1.NewTime(current.Add(l.Spec.TTL.Duration))
+			l.Status.ExpiresAt = &expiresAt
+		}
+		l.Status.RenewCount++
+
+		lease = l
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew lease %s: %w", name, err)
+	}
+	return lease, nil
+}
+
+// Transfer hands a held lease from fromHolder straight to toHolder, without
+// releasing it into contention. The holder check and hand-off happen in a single
+// status update, so it fails with ErrNotHolder if fromHolder no longer holds
+// the lease and ErrExpired if its TTL has elapsed. The new holder starts a fresh
+// TTL and fence token, fromHolder's lease request is removed and a pending
+// request from toHolder is marked granted.
+func Transfer(c *konductor.Client, ctx context.Context, name, fromHolder, toHolder string) (*syncv1.Lease, error) {
+	if toHolder == "" {
+		return nil, fmt.Errorf("failed to transfer lease %s: new holder must not be empty", name)
+	}
+
+	lease := &syncv1.Lease{}
+	lease.Name = name
+	lease.Namespace = c.Namespace()
+
+	err := c.StatusUpdateWithRetry(ctx, lease, func(obj client.Object) error {
+		l := obj.(*syncv1.Lease)
+		if l.Status.Holder == "" || l.Status.Holder != fromHolder {
+			return fmt.Errorf("lease %s is not held by %s: %w", name, fromHolder, konductor.ErrNotHolder)
+		}
+
+		current := now()
+		if l.Status.Phase == syncv1.LeasePhaseExpired ||
+			(l.Status.ExpiresAt != nil && !l.Status.ExpiresAt.After(current)) {
+			return fmt.Errorf("lease %s held by %s: %w", name, fromHolder, konductor.ErrExpired)
+		}
+
+		acquiredAt := metav1.NewTime(current)
+		l.Status.Holder = toHolder
+		l.Status.AcquiredAt = &acquiredAt
+		l.Status.ExpiresAt = nil
+		if l.Spec.TTL != nil && l.Spec.TTL.Duration > 0 {
+			expiresAt := metav1.NewTime(current.Add(l.Spec.TTL.Duration))
+			l.Status.ExpiresAt = &expiresAt
+		}
+		l.Status.RenewCount = 0
+		l.Status.FenceToken++
+
+		lease = l
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer lease %s: %w", name, err)
+	}
+
+	// The controller considers every request when the lease next becomes
+	// available, so the previous holder's must not outlive the hand-off
+	previous := &syncv1.LeaseRequest{}
+	previous.Name = konductor.HolderObjectName(name, fromHolder)
+	previous.Namespace = c.Namespace()
+	if err := c.K8sClient().Delete(ctx, previous); client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to delete lease request %s: %w", previous.Name, err)
+	}
+
+	successor := &syncv1.LeaseRequest{}
+	if err := c.K8sClient().Get(ctx, types.NamespacedName{
+		Name:      konductor.HolderObjectName(name, toHolder),
+		Namespace: c.Namespace(),
+	}, successor); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to get lease request for %s: %w", toHolder, err)
+		}
+		return lease, nil
+	}
+	if successor.Status.Phase != syncv1.LeaseRequestPhaseGranted {
+		successor.Status.Phase = syncv1.LeaseRequestPhaseGranted
+		if err := c.K8sClient().Status().Update(ctx, successor); err != nil {
+			return nil, fmt.Errorf("failed to grant lease request %s: %w", successor.Name, err)
+		}
+	}
+	return lease, nil
+}
+
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.Lease, error) {
 	var leases syncv1.LeaseList
-	if err := c.K8sClient().List(ctx, &leases, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &leases, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list leases: %w", err)
 	}
 	return leases.Items, nil
@@ -177,6 +621,213 @@ func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.Lease,
 	return &lease, nil
 }
 
+// CurrentHolder returns the holder recorded on the lease and when they
+// acquired it. A lease with no holder returns an empty holder and a zero time.
+// The holder is reported until the controller frees the lease, even if its
+// TTL has already elapsed.
+func CurrentHolder(c *konductor.Client, ctx context.Context, name string) (holder string, since time.Time, err error) {
+	lease, err := Get(c, ctx, name)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if lease.Status.Holder == "" {
+		return "", time.Time{}, nil
+	}
+	if lease.Status.AcquiredAt != nil {
+		since = lease.Status.AcquiredAt.Time
+	}
+	return lease.Status.Holder, since, nil
+}
+
+// LeaseEventType is the kind of change a LeaseEvent reports
+type LeaseEventType string
+
+const (
+	// LeaseEventAcquired is sent when a lease with no holder is granted, or
+	// when its holder is granted it again with a new fence token
+	LeaseEventAcquired LeaseEventType = "Acquired"
+	// LeaseEventReleased is sent when the lease loses its holder, whether it
+	// was released or its TTL elapsed
+	LeaseEventReleased LeaseEventType = "Released"
+	// LeaseEventTransferred is sent when the lease passes straight from one
+	// holder to another
+	LeaseEventTransferred LeaseEventType = "Transferred"
+	// LeaseEventPhaseChanged is sent when the phase changes but the holder does not
+	LeaseEventPhaseChanged LeaseEventType = "PhaseChanged"
+	// LeaseEventDeleted is sent when the lease is deleted, just before the
+	// channel is closed
+	LeaseEventDeleted LeaseEventType = "Deleted"
+)
+
+// rewatchDelay is how long Watch waits before retrying a watch that could not
+// be re-established
+const rewatchDelay = time.Second
+
+// LeaseEvent is a change of holder or phase observed on a lease
+type LeaseEvent struct {
+	Type LeaseEventType
+	// Holder is the new holder, empty if the lease has none
+	Holder         string
+	PreviousHolder string
+	Phase          syncv1.LeasePhase
+	// FenceToken is the token issued to Holder
+	FenceToken int64
+	// Lease is the lease as observed with this change
+	Lease *syncv1.Lease
+}
+
+// Watch streams the changes of holder and phase on a lease using a Kubernetes
+// watch, starting from its current state, which is not itself reported. Renewals
+// are not reported. The watch is re-established if the API server closes it,
+// and changes made in between are reported as one event. The channel is closed
+// when ctx is done or after a LeaseEventDeleted.
+func Watch(c *konductor.Client, ctx context.Context, name string) (<-chan LeaseEvent, error) {
+	watcher, ok := c.K8sClient().(client.WithWatch)
+	if !ok {
+		return nil, fmt.Errorf("failed to watch lease %s: client does not support watches", name)
+	}
+
+	// Start the watch before reading the current state so no change between
+	// the two calls can be missed
+	w, err := watchLease(watcher, ctx, c.Namespace(), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch lease %s: %w", name, err)
+	}
+	last, err := Get(c, ctx, name)
+	if err != nil {
+		w.Stop()
+		return nil, err
+	}
+
+	events := make(chan LeaseEvent)
+	go func() {
+		defer close(events)
+		defer func() {
+			if w != nil {
+				w.Stop()
+			}
+		}()
+
+		send := func(event LeaseEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, open := <-w.ResultChan():
+				if open && event.Type != watch.Error {
+					current, ok := event.Object.(*syncv1.Lease)
+					if !ok || current.Name != name {
+						continue
+					}
+					if event.Type == watch.Deleted {
+						send(deletedEvent(last))
+						return
+					}
+					if changed, ok := leaseEvent(last, current); ok && !send(changed) {
+						return
+					}
+					last = current
+					continue
+				}
+
+				// The watch ended; pick up from the current state
+				w.Stop()
+				var current *syncv1.Lease
+				w, current, err = rewatchLease(c, watcher, ctx, name)
+				if err != nil {
+					if errors.IsNotFound(err) {
+						send(deletedEvent(last))
+					}
+					return
+				}
+				if changed, ok := leaseEvent(last, current); ok && !send(changed) {
+					return
+				}
+				last = current
+			}
+		}
+	}()
+	return events, nil
+}
+
+func watchLease(watcher client.WithWatch, ctx context.Context, namespace, name string) (watch.Interface, error) {
+	return watcher.Watch(ctx, &syncv1.LeaseList{},
+		client.InNamespace(namespace),
+		client.MatchingFields{"metadata.name": name})
+}
+
+// rewatchLease re-establishes the watch and reads the current state, retrying
+// until ctx is done. It gives up with a NotFound error if the lease is gone.
+func rewatchLease(c *konductor.Client, watcher client.WithWatch, ctx context.Context, name string) (watch.Interface, *syncv1.Lease, error) {
+	for {
+		w, err := watchLease(watcher, ctx, c.Namespace(), name)
+		if err == nil {
+			var current syncv1.Lease
+			err = c.K8sClient().Get(ctx, types.NamespacedName{Name: name, Namespace: c.Namespace()}, &current)
+			if err == nil {
+				return w, &current, nil
+			}
+			w.Stop()
+			if errors.IsNotFound(err) {
+				return nil, nil, err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(rewatchDelay):
+		}
+	}
+}
+
+// leaseEvent reports the change from last to current, if its holder, fence
+// token or phase differ
+func leaseEvent(last, current *syncv1.Lease) (LeaseEvent, bool) {
+	event := LeaseEvent{
+		Holder:         current.Status.Holder,
+		PreviousHolder: last.Status.Holder,
+		Phase:          current.Status.Phase,
+		FenceToken:     current.Status.FenceToken,
+		Lease:          current,
+	}
+
+	switch {
+	case event.Holder == event.PreviousHolder:
+		if event.Holder != "" && current.Status.FenceToken != last.Status.FenceToken {
+			event.Type = LeaseEventAcquired
+		} else if current.Status.Phase != last.Status.Phase {
+			event.Type = LeaseEventPhaseChanged
+		} else {
+			return LeaseEvent{}, false
+		}
+	case event.PreviousHolder == "":
+		event.Type = LeaseEventAcquired
+	case event.Holder == "":
+		event.Type = LeaseEventReleased
+	default:
+		event.Type = LeaseEventTransferred
+	}
+	return event, true
+}
+
+func deletedEvent(last *syncv1.Lease) LeaseEvent {
+	return LeaseEvent{
+		Type:           LeaseEventDeleted,
+		PreviousHolder: last.Status.Holder,
+		FenceToken:     last.Status.FenceToken,
+		Lease:          last,
+	}
+}
+
 func IsAvailable(c *konductor.Client, ctx context.Context, name string) (bool, error) {
 	lease, err := Get(c, ctx, name)
 	if err != nil {
@@ -202,7 +853,7 @@ func Create(c *konductor.Client, ctx context.Context, name string, opts ...kondu
 	if options.TTL > 0 {
 		lease.Spec.TTL = &metav1.Duration{Duration: options.TTL}
 	} else {
-		lease.Spec.TTL = &metav1.Duration{Duration: 10 * time.Minute}
+		lease.Spec.TTL = &metav1.Duration{Duration: syncv1.DefaultLeaseTTL}
 	}
 
 	if err := c.K8sClient().Create(ctx, lease); err != nil {
@@ -224,8 +875,11 @@ func Delete(c *konductor.Client, ctx context.Context, name string) error {
 	return nil
 }
 
-func Update(c *konductor.Client, ctx context.Context, lease *syncv1.Lease) error {
-	if err := c.K8sClient().Update(ctx, lease); err != nil {
+// Update writes the lease's spec and metadata. With WithServerSideApply only
+// the fields set in lease change, so a lease carrying just a new TTL leaves a
+// priority someone else set alone.
+func Update(c *konductor.Client, ctx context.Context, lease *syncv1.Lease, opts ...konductor.Option) error {
+	if err := c.Update(ctx, lease, opts...); err != nil {
 		return fmt.Errorf("failed to update lease %s: %w", lease.Name, err)
 	}
 	return nil
@@ -2,18 +2,27 @@ package lease
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/internal/applytest"
 )
 
 func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
@@ -29,6 +38,60 @@ func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client
 	return konductor.NewFromClient(k8sClient, "test-ns")
 }
 
+func setupTestClientWithStatus(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.Lease{}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func heldLease(holder string) (*syncv1.Lease, *syncv1.LeaseRequest) {
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Minute},
+		},
+		Status: syncv1.LeaseStatus{
+			Holder: holder,
+			Phase:  syncv1.LeasePhaseHeld,
+		},
+	}
+	request := &syncv1.LeaseRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease-" + holder,
+			Namespace: "test-ns",
+			Labels:    map[string]string{"lease": "test-lease"},
+		},
+		Spec: syncv1.LeaseRequestSpec{
+			Lease:  "test-lease",
+			Holder: holder,
+		},
+	}
+	return lease, request
+}
+
+func newTestLease(c *konductor.Client, ctx context.Context, holder string) *Lease {
+	leaseCtx, cancelCtx := context.WithCancel(ctx)
+	return &Lease{
+		client:    c,
+		name:      "test-lease",
+		requestID: "test-lease-" + holder,
+		holder:    holder,
+		ctx:       leaseCtx,
+		cancelCtx: cancelCtx,
+	}
+}
+
 func TestList(t *testing.T) {
 	lease1 := &syncv1.Lease{
 		ObjectMeta: metav1.ObjectMeta{
@@ -48,6 +111,61 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "lease1", leases[0].Name)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-lease",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-lease",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-lease", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-lease",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-lease",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestGet(t *testing.T) {
 	lease := &syncv1.Lease{
 		ObjectMeta: metav1.ObjectMeta{
@@ -123,6 +241,47 @@ func TestIsLeaseAvailable_Held(t *testing.T) {
 	assert.False(t, available)
 }
 
+func TestCurrentHolder_Held(t *testing.T) {
+	acquiredAt := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.LeaseStatus{
+			Phase:      syncv1.LeasePhaseHeld,
+			Holder:     "holder-1",
+			AcquiredAt: &acquiredAt,
+		},
+	}
+
+	client := setupTestClient(t, lease)
+
+	holder, since, err := CurrentHolder(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, "holder-1", holder)
+	assert.True(t, acquiredAt.Time.Equal(since))
+}
+
+func TestCurrentHolder_Available(t *testing.T) {
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.LeaseStatus{
+			Phase: syncv1.LeasePhaseAvailable,
+		},
+	}
+
+	client := setupTestClient(t, lease)
+
+	holder, since, err := CurrentHolder(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Empty(t, holder)
+	assert.True(t, since.IsZero())
+}
+
 func TestUpdate(t *testing.T) {
 	lease := &syncv1.Lease{
 		ObjectMeta: metav1.ObjectMeta{
@@ -140,3 +299,842 @@ func TestUpdate(t *testing.T) {
 	err := Update(client, context.Background(), lease)
 	assert.NoError(t, err)
 }
+
+func TestUpdate_ServerSideApplyKeepsConcurrentChanges(t *testing.T) {
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-lease", Namespace: "test-ns"},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: time.Minute}},
+	}
+	k8sClient, _ := applytest.NewClient(t, lease)
+	client := konductor.NewFromClient(k8sClient, "test-ns")
+	ctx := context.Background()
+
+	// Someone else sets a priority after the lease was read
+	current, err := Get(client, ctx, "test-lease")
+	require.NoError(t, err)
+	priority := int32(7)
+	current.Spec.Priority = &priority
+	require.NoError(t, client.K8sClient().Update(ctx, current))
+
+	err = Update(client, ctx, &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-lease"},
+		Spec:       syncv1.LeaseSpec{TTL: &metav1.Duration{Duration: 5 * time.Minute}},
+	}, konductor.WithServerSideApply())
+	require.NoError(t, err)
+
+	updated, err := Get(client, ctx, "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, updated.Spec.TTL.Duration)
+	require.NotNil(t, updated.Spec.Priority)
+	assert.Equal(t, int32(7), *updated.Spec.Priority)
+}
+
+func TestRenew_ExtendsExpiry(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	client := setupTestClientWithStatus(t, lease, request)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	current := start
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = time.Now })
+
+	l := newTestLease(client, context.Background(), "worker-1")
+
+	current = start.Add(30 * time.Second)
+	require.NoError(t, l.renew(context.Background()))
+
+	current = start.Add(60 * time.Second)
+	require.NoError(t, l.renew(context.Background()))
+
+	result, err := Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), result.Status.RenewCount)
+	require.NotNil(t, result.Status.ExpiresAt)
+	assert.True(t, result.Status.ExpiresAt.Time.Equal(start.Add(2*time.Minute)))
+}
+
+func TestRenewByName(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	client := setupTestClientWithStatus(t, lease, request)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+	t.Cleanup(func() { now = time.Now })
+
+	renewed, err := Renew(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), renewed.Status.RenewCount)
+	require.NotNil(t, renewed.Status.ExpiresAt)
+	assert.True(t, renewed.Status.ExpiresAt.Time.Equal(start.Add(time.Minute)))
+
+	_, err = Renew(client, context.Background(), "test-lease", konductor.WithHolder("worker-2"))
+	assert.True(t, errors.Is(err, konductor.ErrNotHolder))
+}
+
+func TestRenewByName_Expired(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	expiresAt := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	lease.Status.ExpiresAt = &expiresAt
+	client := setupTestClientWithStatus(t, lease, request)
+
+	now = func() time.Time { return expiresAt.Add(time.Second) }
+	t.Cleanup(func() { now = time.Now })
+
+	_, err := Renew(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	assert.True(t, errors.Is(err, konductor.ErrExpired))
+
+	result, err := Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), result.Status.RenewCount)
+}
+
+func TestAutoRenew_RenewsUntilReleased(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	client := setupTestClientWithStatus(t, lease, request)
+
+	l := newTestLease(client, context.Background(), "worker-1")
+	l.startRenewal(20 * time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		result, err := Get(client, context.Background(), "test-lease")
+		return err == nil && result.Status.RenewCount >= 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, l.Release(context.Background()))
+
+	_, open := <-l.RenewalErrors()
+	assert.False(t, open, "renewal errors channel should be closed after release")
+
+	result, err := Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	renewCount := result.Status.RenewCount
+
+	time.Sleep(60 * time.Millisecond)
+	result, err = Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, renewCount, result.Status.RenewCount, "no renewals should happen after release")
+}
+
+func TestAutoRenew_StopsOnContextCancel(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	client := setupTestClientWithStatus(t, lease, request)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := newTestLease(client, ctx, "worker-1")
+	l.startRenewal(20 * time.Millisecond)
+
+	cancel()
+
+	select {
+	case _, open := <-l.RenewalErrors():
+		assert.False(t, open)
+	case <-time.After(time.Second):
+		t.Fatal("renewal did not stop after context cancellation")
+	}
+}
+
+func TestAutoRenew_ReportsErrors(t *testing.T) {
+	lease, request := heldLease("worker-2")
+	client := setupTestClientWithStatus(t, lease, request)
+
+	l := newTestLease(client, context.Background(), "worker-1")
+	l.startRenewal(20 * time.Millisecond)
+	defer l.cancelCtx()
+
+	select {
+	case err := <-l.RenewalErrors():
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no longer held by worker-1")
+		assert.True(t, errors.Is(err, konductor.ErrNotHolder))
+	case <-time.After(time.Second):
+		t.Fatal("expected a renewal error")
+	}
+}
+
+func TestRenewalErrors_NilWithoutAutoRenew(t *testing.T) {
+	l := &Lease{}
+	assert.Nil(t, l.RenewalErrors())
+}
+
+// setupTestClientWithDecision returns a client whose lease requests are created
+// already carrying the given controller decision
+func setupTestClientWithDecision(t *testing.T, phase syncv1.LeaseRequestPhase, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if request, ok := obj.(*syncv1.LeaseRequest); ok {
+					request.Status.Phase = phase
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func TestAcquire_Denied(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseDenied, availableLease())
+
+	_, err := Acquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lease request denied for test-lease")
+	assert.True(t, errors.Is(err, konductor.ErrDenied))
+}
+
+func TestAcquire_Timeout(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhasePending, availableLease())
+
+	_, err := Acquire(client, context.Background(), "test-lease",
+		konductor.WithHolder("worker-1"),
+		konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestAcquire_TimeoutNamesHolder(t *testing.T) {
+	held, _ := heldLease("worker-2")
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhasePending, held)
+
+	_, err := Acquire(client, context.Background(), "test-lease",
+		konductor.WithHolder("worker-1"),
+		konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Contains(t, err.Error(), "timeout waiting for lease test-lease: held by worker-2")
+
+	// An available lease whose request is never decided names no holder
+	client = setupTestClientWithDecision(t, syncv1.LeaseRequestPhasePending, availableLease())
+	_, err = Acquire(client, context.Background(), "test-lease",
+		konductor.WithHolder("worker-1"),
+		konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "held by")
+}
+
+func TestAcquire_ReactsToGrant(t *testing.T) {
+	lease := &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Minute},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase: syncv1.LeasePhaseAvailable,
+		},
+	}
+	client := setupTestClientWithStatus(t, lease)
+	k8sClient := client.K8sClient()
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		ctx := context.Background()
+
+		var request syncv1.LeaseRequest
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "test-lease-worker-1", Namespace: "test-ns"}, &request); err != nil {
+			return
+		}
+
+		var current syncv1.Lease
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "test-lease", Namespace: "test-ns"}, &current); err != nil {
+			return
+		}
+		current.Status.Holder = "worker-1"
+		current.Status.Phase = syncv1.LeasePhaseHeld
+		current.Status.FenceToken = 7
+		_ = k8sClient.Status().Update(ctx, &current)
+
+		request.Status.Phase = syncv1.LeaseRequestPhaseGranted
+		_ = k8sClient.Update(ctx, &request)
+	}()
+
+	start := time.Now()
+	l, err := Acquire(client, context.Background(), "test-lease",
+		konductor.WithHolder("worker-1"),
+		konductor.WithTimeout(10*time.Second))
+	require.NoError(t, err)
+
+	// Polling would not see the grant until its second check after 1s, so
+	// returning well before that proves the watch fired
+	assert.Less(t, time.Since(start), 700*time.Millisecond)
+	assert.Equal(t, int64(7), l.FenceToken())
+}
+
+func TestAcquire_TimeoutCleansUpRequest(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhasePending, availableLease())
+
+	_, err := Acquire(client, context.Background(), "test-lease",
+		konductor.WithHolder("worker-1"),
+		konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, client.K8sClient().List(context.Background(), &requests))
+	assert.Empty(t, requests.Items)
+}
+
+func TestAcquire_ContextCancelledCleansUpRequest(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhasePending, availableLease())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	_, err := Acquire(client, ctx, "test-lease", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Contains(t, err.Error(), "waiting for lease test-lease")
+
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, client.K8sClient().List(context.Background(), &requests))
+	assert.Empty(t, requests.Items)
+}
+
+func TestAcquire_CancelledCleanupUsesDetachedDeadline(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	var cleanupErr error
+	var cleanupHasDeadline bool
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(availableLease()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				cleanupErr = ctx.Err()
+				_, cleanupHasDeadline = ctx.Deadline()
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	c := konductor.NewFromClient(k8sClient, "test-ns")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	_, err := Acquire(c, ctx, "test-lease", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	assert.NoError(t, cleanupErr)
+	assert.True(t, cleanupHasDeadline)
+
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, k8sClient.List(context.Background(), &requests))
+	assert.Empty(t, requests.Items)
+}
+
+func TestTransfer(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	lease.Status.RenewCount = 3
+	lease.Status.FenceToken = 4
+	successor := &syncv1.LeaseRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease-worker-2",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"lease": "test-lease"},
+		},
+		Spec: syncv1.LeaseRequestSpec{
+			Lease:  "test-lease",
+			Holder: "worker-2",
+		},
+		Status: syncv1.LeaseRequestStatus{Phase: syncv1.LeaseRequestPhasePending},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+	client := konductor.NewFromClient(fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(lease, request, successor).
+		WithStatusSubresource(&syncv1.Lease{}, &syncv1.LeaseRequest{}).
+		Build(), "test-ns")
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return start }
+	t.Cleanup(func() { now = time.Now })
+
+	transferred, err := Transfer(client, context.Background(), "test-lease", "worker-1", "worker-2")
+	require.NoError(t, err)
+	assert.Equal(t, "worker-2", transferred.Status.Holder)
+
+	result, err := Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, "worker-2", result.Status.Holder)
+	assert.Equal(t, syncv1.LeasePhaseHeld, result.Status.Phase)
+	require.NotNil(t, result.Status.AcquiredAt)
+	assert.True(t, result.Status.AcquiredAt.Time.Equal(start))
+	require.NotNil(t, result.Status.ExpiresAt)
+	assert.True(t, result.Status.ExpiresAt.Time.Equal(start.Add(time.Minute)))
+	assert.Equal(t, int32(0), result.Status.RenewCount)
+	assert.Equal(t, int64(5), result.Status.FenceToken)
+
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, client.K8sClient().List(context.Background(), &requests))
+	require.Len(t, requests.Items, 1)
+	assert.Equal(t, "worker-2", requests.Items[0].Spec.Holder)
+	assert.Equal(t, syncv1.LeaseRequestPhaseGranted, requests.Items[0].Status.Phase)
+}
+
+func TestTransfer_NotHolder(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	client := setupTestClientWithStatus(t, lease, request)
+
+	_, err := Transfer(client, context.Background(), "test-lease", "worker-3", "worker-2")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotHolder))
+
+	result, err := Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, "worker-1", result.Status.Holder)
+
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, client.K8sClient().List(context.Background(), &requests))
+	assert.Len(t, requests.Items, 1)
+}
+
+func TestTransfer_Expired(t *testing.T) {
+	lease, request := heldLease("worker-1")
+	expiresAt := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	lease.Status.ExpiresAt = &expiresAt
+	client := setupTestClientWithStatus(t, lease, request)
+
+	now = func() time.Time { return expiresAt.Add(time.Second) }
+	t.Cleanup(func() { now = time.Now })
+
+	_, err := Transfer(client, context.Background(), "test-lease", "worker-1", "worker-2")
+	assert.True(t, errors.Is(err, konductor.ErrExpired))
+
+	result, err := Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	assert.Equal(t, "worker-1", result.Status.Holder)
+}
+
+func availableLease() *syncv1.Lease {
+	return &syncv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-lease",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.LeaseSpec{
+			TTL: &metav1.Duration{Duration: time.Minute},
+		},
+		Status: syncv1.LeaseStatus{
+			Phase: syncv1.LeasePhaseAvailable,
+		},
+	}
+}
+
+func assertNoLeaseRequests(t *testing.T, c *konductor.Client) {
+	t.Helper()
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, c.K8sClient().List(context.Background(), &requests))
+	assert.Empty(t, requests.Items)
+}
+
+func TestTryAcquire_HeldByOtherReturnsImmediately(t *testing.T) {
+	lease, _ := heldLease("worker-1")
+	client := setupTestClient(t, lease)
+
+	start := time.Now()
+	_, err := TryAcquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-2"))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrWouldBlock))
+	assert.Contains(t, err.Error(), "held by worker-1")
+	assertNoLeaseRequests(t, client)
+}
+
+func TestTryAcquire_PendingRequestIsWithdrawn(t *testing.T) {
+	client := setupTestClient(t, availableLease())
+
+	start := time.Now()
+	_, err := TryAcquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrWouldBlock))
+	assertNoLeaseRequests(t, client)
+}
+
+func TestTryAcquire_Granted(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted, availableLease())
+
+	l, err := TryAcquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "worker-1", l.Holder())
+	assert.Equal(t, "test-lease", l.Name())
+
+	require.NoError(t, l.Release(context.Background()))
+	assertNoLeaseRequests(t, client)
+}
+
+func TestAcquire_HolderNotValidInObjectName(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted, availableLease())
+
+	l, err := Acquire(client, context.Background(), "test-lease", konductor.WithHolder("Worker/1"))
+	require.NoError(t, err)
+	assert.Equal(t, "Worker/1", l.Holder())
+
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, client.K8sClient().List(context.Background(), &requests))
+	require.Len(t, requests.Items, 1)
+	assert.Equal(t, konductor.HolderObjectName("test-lease", "Worker/1"), requests.Items[0].Name)
+	assert.Equal(t, "Worker/1", requests.Items[0].Spec.Holder)
+
+	// Releasing by name finds the request without the Lease handle
+	require.NoError(t, client.ReleaseLease(context.Background(), "test-lease", "Worker/1"))
+	assertNoLeaseRequests(t, client)
+}
+
+func TestTryAcquire_Denied(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseDenied, availableLease())
+
+	_, err := TryAcquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrDenied))
+	assertNoLeaseRequests(t, client)
+}
+
+func TestTryAcquire_ExpiredHolderDoesNotBlock(t *testing.T) {
+	lease, _ := heldLease("worker-1")
+	expiresAt := metav1.NewTime(time.Now().Add(-time.Second))
+	lease.Status.ExpiresAt = &expiresAt
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted, lease)
+
+	l, err := TryAcquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-2"))
+	require.NoError(t, err)
+	assert.Equal(t, "worker-2", l.Holder())
+}
+
+// setHolder stands in for the controller, granting the lease to holder or,
+// with an empty holder, freeing it
+func setHolder(t *testing.T, c *konductor.Client, holder string) {
+	t.Helper()
+	lease, err := Get(c, context.Background(), "test-lease")
+	require.NoError(t, err)
+	lease.Status.Holder = holder
+	lease.Status.Phase = syncv1.LeasePhaseAvailable
+	if holder != "" {
+		lease.Status.Phase = syncv1.LeasePhaseHeld
+		lease.Status.FenceToken++
+	}
+	require.NoError(t, c.K8sClient().Status().Update(context.Background(), lease))
+}
+
+func nextEvent(t *testing.T, events <-chan LeaseEvent) LeaseEvent {
+	t.Helper()
+	select {
+	case event, open := <-events:
+		require.True(t, open, "events channel closed")
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for lease event")
+		return LeaseEvent{}
+	}
+}
+
+func TestWatch_ReportsHolderChanges(t *testing.T) {
+	client := setupTestClientWithStatus(t, availableLease())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := Watch(client, ctx, "test-lease")
+	require.NoError(t, err)
+
+	setHolder(t, client, "worker-1")
+	event := nextEvent(t, events)
+	assert.Equal(t, LeaseEventAcquired, event.Type)
+	assert.Equal(t, "worker-1", event.Holder)
+	assert.Empty(t, event.PreviousHolder)
+	assert.Equal(t, syncv1.LeasePhaseHeld, event.Phase)
+	assert.Equal(t, int64(1), event.FenceToken)
+
+	// A renewal changes neither holder nor phase, so it is not reported
+	_, err = Renew(client, ctx, "test-lease", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+
+	_, err = Transfer(client, ctx, "test-lease", "worker-1", "worker-2")
+	require.NoError(t, err)
+	event = nextEvent(t, events)
+	assert.Equal(t, LeaseEventTransferred, event.Type)
+	assert.Equal(t, "worker-2", event.Holder)
+	assert.Equal(t, "worker-1", event.PreviousHolder)
+	assert.Equal(t, int64(2), event.FenceToken)
+
+	setHolder(t, client, "")
+	event = nextEvent(t, events)
+	assert.Equal(t, LeaseEventReleased, event.Type)
+	assert.Empty(t, event.Holder)
+	assert.Equal(t, "worker-2", event.PreviousHolder)
+	assert.Equal(t, syncv1.LeasePhaseAvailable, event.Phase)
+
+	require.NoError(t, Delete(client, ctx, "test-lease"))
+	event = nextEvent(t, events)
+	assert.Equal(t, LeaseEventDeleted, event.Type)
+
+	_, open := <-events
+	assert.False(t, open, "events channel should be closed after the lease is deleted")
+}
+
+func TestWatch_ReacquiredBySameHolder(t *testing.T) {
+	lease, _ := heldLease("worker-1")
+	lease.Status.FenceToken = 3
+	client := setupTestClientWithStatus(t, lease)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := Watch(client, ctx, "test-lease")
+	require.NoError(t, err)
+
+	// Expired and granted to the same holder again in a single reconcile
+	setHolder(t, client, "worker-1")
+	event := nextEvent(t, events)
+	assert.Equal(t, LeaseEventAcquired, event.Type)
+	assert.Equal(t, "worker-1", event.Holder)
+	assert.Equal(t, "worker-1", event.PreviousHolder)
+	assert.Equal(t, int64(4), event.FenceToken)
+}
+
+func TestWatch_RewatchReportsMissedChanges(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	first := watch.NewFake()
+	watches := 0
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(availableLease()).
+		WithStatusSubresource(&syncv1.Lease{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+				watches++
+				if watches == 1 {
+					return first, nil
+				}
+				return c.Watch(ctx, list, opts...)
+			},
+		}).
+		Build()
+	c := konductor.NewFromClient(k8sClient, "test-ns")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := Watch(c, ctx, "test-lease")
+	require.NoError(t, err)
+
+	// The first watch never sees the grant and is closed afterwards
+	setHolder(t, c, "worker-1")
+	first.Stop()
+
+	event := nextEvent(t, events)
+	assert.Equal(t, LeaseEventAcquired, event.Type)
+	assert.Equal(t, "worker-1", event.Holder)
+	assert.Equal(t, 2, watches)
+
+	setHolder(t, c, "")
+	event = nextEvent(t, events)
+	assert.Equal(t, LeaseEventReleased, event.Type)
+}
+
+func TestWatch_StopsOnContextCancel(t *testing.T) {
+	client := setupTestClientWithStatus(t, availableLease())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := Watch(client, ctx, "test-lease")
+	require.NoError(t, err)
+
+	cancel()
+	select {
+	case _, open := <-events:
+		assert.False(t, open)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events channel not closed after context cancel")
+	}
+}
+
+func TestWatch_NotFound(t *testing.T) {
+	client := setupTestClientWithStatus(t)
+
+	_, err := Watch(client, context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+func TestAcquireMajority(t *testing.T) {
+	clients := []*konductor.Client{
+		setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted, availableLease()),
+		setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseDenied, availableLease()),
+		setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted, availableLease()),
+	}
+	m, err := konductor.NewMultiClient(clients...)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	lease, err := AcquireMajority(m, ctx, "test-lease", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "test-lease", lease.Name())
+	require.Len(t, lease.Leases(), 2)
+	for _, l := range lease.Leases() {
+		assert.Equal(t, "worker-1", l.Holder())
+	}
+
+	require.NoError(t, lease.Release(ctx))
+	for _, c := range clients {
+		var requests syncv1.LeaseRequestList
+		require.NoError(t, c.K8sClient().List(ctx, &requests))
+		assert.Empty(t, requests.Items)
+	}
+}
+
+func TestAcquireMajority_NoMajorityReleasesGrants(t *testing.T) {
+	clients := []*konductor.Client{
+		setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseDenied, availableLease()),
+		setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted, availableLease()),
+		setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseDenied, availableLease()),
+	}
+	m, err := konductor.NewMultiClient(clients...)
+	require.NoError(t, err)
+
+	_, err = AcquireMajority(m, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNoQuorum))
+	assert.True(t, errors.Is(err, konductor.ErrDenied))
+	assert.Contains(t, err.Error(), "failed to acquire lease test-lease on a majority of clusters")
+
+	// The grant from the one cluster that gave it is handed back
+	var requests syncv1.LeaseRequestList
+	require.NoError(t, clients[1].K8sClient().List(context.Background(), &requests))
+	assert.Empty(t, requests.Items)
+}
+
+func TestAcquire_MissingLease(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted)
+
+	_, err := Acquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotFound))
+	assert.Contains(t, err.Error(), "lease test-lease not found in namespace test-ns")
+
+	_, err = TryAcquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotFound))
+	assertNoLeaseRequests(t, client)
+}
+
+func TestAcquire_AutoCreate(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted)
+
+	l, err := Acquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+	assert.Equal(t, "worker-1", l.Holder())
+
+	created, err := Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+	require.NotNil(t, created.Spec.TTL)
+	assert.Equal(t, syncv1.DefaultLeaseTTL, created.Spec.TTL.Duration)
+}
+
+func TestTryAcquire_AutoCreate(t *testing.T) {
+	client := setupTestClientWithDecision(t, syncv1.LeaseRequestPhaseGranted)
+
+	l, err := TryAcquire(client, context.Background(), "test-lease", konductor.WithHolder("worker-1"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+	assert.Equal(t, "test-lease", l.Name())
+
+	_, err = Get(client, context.Background(), "test-lease")
+	require.NoError(t, err)
+}
+
+// setupTestClientGranting returns a client whose requests for the granted
+// leases are created already granted and all others left pending, and the
+// names of the leases requested so far, in order
+func setupTestClientGranting(t *testing.T, granted []string, objects ...runtime.Object) (*konductor.Client, func() []string) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	var mu sync.Mutex
+	var requested []string
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if request, ok := obj.(*syncv1.LeaseRequest); ok {
+					mu.Lock()
+					requested = append(requested, request.Spec.Lease)
+					mu.Unlock()
+					request.Status.Phase = syncv1.LeaseRequestPhasePending
+					if slices.Contains(granted, request.Spec.Lease) {
+						request.Status.Phase = syncv1.LeaseRequestPhaseGranted
+					}
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns"), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(requested)
+	}
+}
+
+// namedLease returns an available lease with the given name
+func namedLease(name string) *syncv1.Lease {
+	lease := availableLease()
+	lease.Name = name
+	return lease
+}
+
+func TestAcquireAll(t *testing.T) {
+	client, requested := setupTestClientGranting(t, []string{"lease-a", "lease-b"}, namedLease("lease-a"), namedLease("lease-b"))
+	ctx := context.Background()
+
+	set, err := AcquireAll(client, ctx, []string{"lease-b", "lease-a"}, konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lease-a", "lease-b"}, requested(), "leases are acquired in sorted order")
+	assert.Equal(t, []string{"lease-a", "lease-b"}, set.Names())
+	for _, l := range set.Leases() {
+		assert.Equal(t, "worker-1", l.Holder())
+	}
+
+	require.NoError(t, set.Release(ctx))
+	assertNoLeaseRequests(t, client)
+}
+
+func TestAcquireAll_TimeoutReleasesAcquired(t *testing.T) {
+	client, requested := setupTestClientGranting(t, []string{"lease-a"}, namedLease("lease-a"), namedLease("lease-b"))
+
+	_, err := AcquireAll(client, context.Background(), []string{"lease-b", "lease-a"},
+		konductor.WithHolder("worker-1"),
+		konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Contains(t, err.Error(), "failed to acquire lease lease-b after acquiring 1 of 2")
+	assert.Equal(t, []string{"lease-a", "lease-b"}, requested())
+
+	// The first lease is handed back and the second's request withdrawn
+	assertNoLeaseRequests(t, client)
+}
+
+func TestAcquireAll_InvalidNames(t *testing.T) {
+	client, requested := setupTestClientGranting(t, nil, namedLease("lease-a"))
+
+	_, err := AcquireAll(client, context.Background(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no lease names given")
+
+	_, err = AcquireAll(client, context.Background(), []string{"lease-a", "lease-a"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lease lease-a is listed more than once")
+	assert.Empty(t, requested())
+}
@@ -3,12 +3,12 @@ package mutex
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -17,9 +17,10 @@ import (
 
 // Mutex represents an acquired mutex lock
 type Mutex struct {
-	client *konductor.Client
-	name   string
-	holder string
+	client     *konductor.Client
+	name       string
+	holder     string
+	fenceToken int64
 }
 
 func (m *Mutex) Unlock(ctx context.Context) error {
@@ -36,7 +37,13 @@ func (m *Mutex) Unlock(ctx context.Context) error {
 		}
 
 		if mutex.Status.Holder != m.holder {
-			return fmt.Errorf("cannot unlock: not the holder")
+			return fmt.Errorf("cannot unlock: %w", konductor.ErrNotHolder)
+		}
+
+		// A reentrant mutex stays locked until every Lock has been unlocked
+		if mutex.Spec.Reentrant && mutex.Status.HoldCount > 1 {
+			mutex.Status.HoldCount--
+			return m.client.K8sClient().Status().Update(ctx, &mutex)
 		}
 
 		m.clearMutexStatus(&mutex)
@@ -49,6 +56,7 @@ func (m *Mutex) clearMutexStatus(mutex *syncv1.Mutex) {
 	mutex.Status.Holder = ""
 	mutex.Status.LockedAt = nil
 	mutex.Status.ExpiresAt = nil
+	mutex.Status.HoldCount = 0
 }
 
 func (m *Mutex) Holder() string {
@@ -59,6 +67,16 @@ func (m *Mutex) Name() string {
 	return m.name
 }
 
+// FenceToken returns the token issued when this lock was acquired. Tokens increase
+// with every new holder, so external systems can reject writes carrying an older one.
+func (m *Mutex) FenceToken() int64 {
+	return m.fenceToken
+}
+
+// Lock blocks until the mutex is acquired for the caller. WithTimeout bounds
+// the whole call and fails with ErrTimeout once it passes; without it, Lock
+// waits up to 30 seconds for the mutex to be unlocked. Once taken, the lock is
+// read back to confirm it is held, unless WithoutConfirm is given.
 func Lock(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*Mutex, error) {
 	if name == "" {
 		return nil, fmt.Errorf("mutex name cannot be empty")
@@ -71,9 +89,20 @@ func Lock(c *konductor.Client, ctx context.Context, name string, opts ...konduct
 
 	holder := options.Holder
 	if holder == "" {
-		if holder = os.Getenv("HOSTNAME"); holder == "" {
-			holder = fmt.Sprintf("sdk-%d", time.Now().Unix())
-		}
+		holder = konductor.DefaultHolder()
+	}
+
+	// A single deadline bounds waiting, acquiring and confirming together, so
+	// WithTimeout caps the whole call however the time is split between them
+	lockCtx := ctx
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		lockCtx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	if err := ensureMutex(c, lockCtx, name, options); err != nil {
+		return nil, err
 	}
 
 	mutex := &syncv1.Mutex{}
@@ -92,39 +121,54 @@ func Lock(c *konductor.Client, ctx context.Context, name string, opts ...konduct
 		config.Timeout = options.Timeout
 	}
 
-	// Wait for mutex to be unlocked
-	err := c.WaitForCondition(ctx, mutex, func(obj client.Object) bool {
+	// Wait for mutex to be unlocked, or for the caller to be able to reenter it
+	err := c.WaitForCondition(lockCtx, mutex, func(obj client.Object) bool {
 		m, ok := obj.(*syncv1.Mutex)
 		if !ok {
 			return false
 		}
-		return m.Status.Phase != syncv1.MutexPhaseLocked
+		return m.Status.Phase != syncv1.MutexPhaseLocked || reentrant(m, holder)
 	}, config)
 
 	if err != nil {
 		if ctx.Err() != nil {
 			return nil, fmt.Errorf("context cancelled while waiting for mutex %s: %w", name, ctx.Err())
 		}
-		return nil, fmt.Errorf("timeout acquiring mutex %s: %w", name, err)
+		if wait.Interrupted(err) {
+			return nil, timeoutError(name, mutex.Status.Holder, err)
+		}
+		return nil, fmt.Errorf("failed to wait for mutex %s: %w", name, err)
 	}
 
 	// Now try to acquire the lock
-	err = c.RetryWithBackoff(ctx, func() error {
+	var fenceToken int64
+	lastHolder := ""
+	err = c.RetryWithBackoff(lockCtx, func() error {
 		var m syncv1.Mutex
-		if err := c.K8sClient().Get(ctx, types.NamespacedName{
+		if err := c.K8sClient().Get(lockCtx, types.NamespacedName{
 			Name: name, Namespace: c.Namespace(),
 		}, &m); err != nil {
 			return err
 		}
+		lastHolder = m.Status.Holder
+
+		if reentrant(&m, holder) {
+			m.Status.HoldCount++
+			fenceToken = m.Status.FenceToken
+			return c.K8sClient().Status().Update(lockCtx, &m)
+		}
 
 		// Atomic check: only proceed if truly unlocked
 		if m.Status.Phase == syncv1.MutexPhaseLocked && m.Status.Holder != "" {
-			return fmt.Errorf("mutex locked by %s", m.Status.Holder)
+			return fmt.Errorf("mutex %w by %s", konductor.ErrLocked, m.Status.Holder)
 		}
 
 		// Atomic set: this will fail with 409 if another pod modified it
 		m.Status.Phase = syncv1.MutexPhaseLocked
 		m.Status.Holder = holder
+		m.Status.FenceToken++
+		fenceToken = m.Status.FenceToken
+		m.Status.HoldCount = 1
 		lockedAt := metav1.Now()
 		m.Status.LockedAt = &lockedAt
 
@@ -134,37 +178,75 @@ func Lock(c *konductor.Client, ctx context.Context, name string, opts ...konduct
 		}
 
 		// Critical: Update will fail with conflict if resource version changed
-		return c.K8sClient().Status().Update(ctx, &m)
+		return c.K8sClient().Status().Update(lockCtx, &m)
 	}, &konductor.WaitConfig{InitialDelay: 100 * time.Millisecond, MaxDelay: 1 * time.Second, Timeout: 5 * time.Second})
 
 	if err != nil {
-		if ctx.Err() != nil {
-			return nil, fmt.Errorf("context cancelled while acquiring mutex %s: %w", name, ctx.Err())
-		}
-		return nil, fmt.Errorf("failed to acquire mutex lock %s: %w", name, err)
+		return nil, lockError(ctx, lockCtx, name, lastHolder, err)
+	}
+
+	mutexObj := &Mutex{client: c, name: name, holder: holder, fenceToken: fenceToken}
+	if options.SkipConfirm {
+		return mutexObj, nil
 	}
 
 	// Wait for confirmation
-	mutexObj := &Mutex{client: c, name: name, holder: holder}
 	confirmConfig := &konductor.WaitConfig{
 		InitialDelay: 100 * time.Millisecond,
 		MaxDelay:     500 * time.Millisecond,
 		Factor:       1.5,
 		Timeout:      2 * time.Second,
 	}
-	if err := c.WaitForCondition(ctx, mutex, func(obj client.Object) bool {
+	if err := c.WaitForCondition(lockCtx, mutex, func(obj client.Object) bool {
 		m, ok := obj.(*syncv1.Mutex)
 		if !ok {
 			return false
 		}
 		return m.Status.Phase == syncv1.MutexPhaseLocked && m.Status.Holder == holder
 	}, confirmConfig); err != nil {
+		// The lock may be held even though it was not seen in time; release
+		// it rather than leave it held by a caller that has given up
+		cleanupCtx, cancel := konductor.CleanupContext(ctx)
+		defer cancel()
+		_ = mutexObj.Unlock(cleanupCtx)
+
+		if lockCtx.Err() != nil {
+			otherHolder := ""
+			if mutex.Status.Holder != holder {
+				otherHolder = mutex.Status.Holder
+			}
+			return nil, lockError(ctx, lockCtx, name, otherHolder, err)
+		}
 		return nil, fmt.Errorf("failed to confirm mutex lock: %w", err)
 	}
 
 	return mutexObj, nil
 }
 
+// lockError reports cancellation of the caller's ctx as-is and the end of the
+// WithTimeout deadline in lockCtx as ErrTimeout, naming lastHolder as the
+// holder last seen on the mutex
+func lockError(ctx, lockCtx context.Context, name, lastHolder string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("context cancelled while acquiring mutex %s: %w", name, ctx.Err())
+	}
+	if lockCtx.Err() != nil {
+		return timeoutError(name, lastHolder, lockCtx.Err())
+	}
+	return fmt.Errorf("failed to acquire mutex lock %s: %w", name, err)
+}
+
+// timeoutError wraps ErrTimeout for a Lock that gave up on name, saying who
+// held it when last seen so contention can be told apart from a mutex that
+// was free but could not be taken, for instance because every update
+// conflicted
+func timeoutError(name, lastHolder string, err error) error {
+	if lastHolder != "" {
+		return fmt.Errorf("%w acquiring mutex %s: held by %s: %w", konductor.ErrTimeout, name, lastHolder, err)
+	}
+	return fmt.Errorf("%w acquiring mutex %s: %w", konductor.ErrTimeout, name, err)
+}
+
 func TryLock(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*Mutex, error) {
 	if name == "" {
 		return nil, fmt.Errorf("mutex name cannot be empty")
@@ -177,11 +259,14 @@ func TryLock(c *konductor.Client, ctx context.Context, name string, opts ...kond
 
 	holder := options.Holder
 	if holder == "" {
-		if holder = os.Getenv("HOSTNAME"); holder == "" {
-			holder = fmt.Sprintf("sdk-%d", time.Now().Unix())
-		}
+		holder = konductor.DefaultHolder()
 	}
 
+	if err := ensureMutex(c, ctx, name, options); err != nil {
+		return nil, err
+	}
+
+	var fenceToken int64
 	err := c.RetryWithBackoff(ctx, func() error {
 		var m syncv1.Mutex
 		if err := c.K8sClient().Get(ctx, types.NamespacedName{
@@ -190,12 +275,21 @@ func TryLock(c *konductor.Client, ctx context.Context, name string, opts ...kond
 			return err
 		}
 
+		if reentrant(&m, holder) {
+			m.Status.HoldCount++
+			fenceToken = m.Status.FenceToken
+			return c.K8sClient().Status().Update(ctx, &m)
+		}
+
 		if m.Status.Phase == syncv1.MutexPhaseLocked && m.Status.Holder != "" {
-			return fmt.Errorf("mutex already locked by %s", m.Status.Holder)
+			return fmt.Errorf("mutex already %w by %s", konductor.ErrLocked, m.Status.Holder)
 		}
 
 		m.Status.Phase = syncv1.MutexPhaseLocked
 		m.Status.Holder = holder
+		m.Status.FenceToken++
+		fenceToken = m.Status.FenceToken
+		m.Status.HoldCount = 1
 		lockedAt := metav1.Now()
 		m.Status.LockedAt = &lockedAt
 
@@ -209,12 +303,28 @@ func TryLock(c *konductor.Client, ctx context.Context, name string, opts ...kond
 
 	if err != nil {
 		if errors.IsConflict(err) {
-			return nil, fmt.Errorf("mutex locked by another process")
+			return nil, fmt.Errorf("mutex %w by another process", konductor.ErrLocked)
 		}
 		return nil, fmt.Errorf("failed to acquire mutex: %w", err)
 	}
 
-	return &Mutex{client: c, name: name, holder: holder}, nil
+	return &Mutex{client: c, name: name, holder: holder, fenceToken: fenceToken}, nil
+}
+
+// ensureMutex checks that the named mutex exists. With WithAutoCreate a
+// missing mutex is created first, reentrant if WithReentrant is given too.
+func ensureMutex(c *konductor.Client, ctx context.Context, name string, options *konductor.Options) error {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.Namespace()},
+		Spec:       syncv1.MutexSpec{Reentrant: options.Reentrant},
+	}
+	return c.GetOrCreate(ctx, "mutex", mutex, options.AutoCreate)
+}
+
+// reentrant reports whether holder can take m again, being a reentrant mutex
+// it already holds, by counting one more Lock
+func reentrant(m *syncv1.Mutex, holder string) bool {
+	return m.Spec.Reentrant && m.Status.Phase == syncv1.MutexPhaseLocked && m.Status.Holder == holder
 }
 
 func With(c *konductor.Client, ctx context.Context, name string, fn func() error, opts ...konductor.Option) (err error) {
@@ -252,6 +362,7 @@ func Create(c *konductor.Client, ctx context.Context, name string, opts ...kondu
 	if options.TTL > 0 {
 		mutex.Spec.TTL = &metav1.Duration{Duration: options.TTL}
 	}
+	mutex.Spec.Reentrant = options.Reentrant
 
 	err := c.K8sClient().Create(ctx, mutex)
 	if err != nil && errors.IsAlreadyExists(err) {
@@ -285,9 +396,9 @@ func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.Mutex,
 	return &mutex, nil
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.Mutex, error) {
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.Mutex, error) {
 	var mutexes syncv1.MutexList
-	if err := c.K8sClient().List(ctx, &mutexes, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &mutexes, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list mutexes: %w", err)
 	}
 	return mutexes.Items, nil
@@ -301,13 +412,64 @@ func IsLocked(c *konductor.Client, ctx context.Context, name string) (bool, erro
 	return mutex.Status.Phase == syncv1.MutexPhaseLocked, nil
 }
 
+// Owner returns the current holder of the mutex and when they locked it. An
+// unlocked mutex has an empty holder and a zero time.
+func Owner(c *konductor.Client, ctx context.Context, name string) (holder string, since time.Time, err error) {
+	mutex, err := Get(c, ctx, name)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if mutex.Status.Holder == "" {
+		return "", time.Time{}, nil
+	}
+	if mutex.Status.LockedAt != nil {
+		since = mutex.Status.LockedAt.Time
+	}
+	return mutex.Status.Holder, since, nil
+}
+
 func Unlock(c *konductor.Client, ctx context.Context, name, holder string) error {
 	m := &Mutex{client: c, name: name, holder: holder}
 	return m.Unlock(ctx)
 }
 
-func Update(c *konductor.Client, ctx context.Context, mutex *syncv1.Mutex) error {
-	if err := c.K8sClient().Update(ctx, mutex); err != nil {
+// Break unlocks the mutex whoever holds it, for freeing a lock whose holder
+// is gone before its TTL runs out. The fence token is bumped so the token the
+// old holder was given is stale. It returns the holder the lock was taken
+// from, or an empty string if the mutex was not locked.
+func Break(c *konductor.Client, ctx context.Context, name string) (string, error) {
+	var previous string
+	err := c.RetryWithBackoff(ctx, func() error {
+		var mutex syncv1.Mutex
+		if err := c.K8sClient().Get(ctx, types.NamespacedName{
+			Name: name, Namespace: c.Namespace(),
+		}, &mutex); err != nil {
+			return err
+		}
+
+		previous = mutex.Status.Holder
+		if previous == "" && mutex.Status.Phase != syncv1.MutexPhaseLocked {
+			return nil
+		}
+
+		mutex.Status.Phase = syncv1.MutexPhaseUnlocked
+		mutex.Status.Holder = ""
+		mutex.Status.LockedAt = nil
+		mutex.Status.ExpiresAt = nil
+		mutex.Status.HoldCount = 0
+		mutex.Status.FenceToken++
+		return c.K8sClient().Status().Update(ctx, &mutex)
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to break mutex %s: %w", name, err)
+	}
+	return previous, nil
+}
+
+// Update writes the mutex's spec and metadata, as a server-side apply with
+// WithServerSideApply
+func Update(c *konductor.Client, ctx context.Context, mutex *syncv1.Mutex, opts ...konductor.Option) error {
+	if err := c.Update(ctx, mutex, opts...); err != nil {
 		return fmt.Errorf("failed to update mutex %s: %w", mutex.Name, err)
 	}
 	return nil
@@ -2,16 +2,20 @@ package mutex
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
@@ -48,6 +52,61 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "mutex1", mutexes[0].Name)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-mutex",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-mutex",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-mutex", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-mutex",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-mutex",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestGet(t *testing.T) {
 	mutex := &syncv1.Mutex{
 		ObjectMeta: metav1.ObjectMeta{
@@ -133,6 +192,54 @@ func TestIsLocked_Unlocked(t *testing.T) {
 	assert.False(t, locked)
 }
 
+func TestOwner_Locked(t *testing.T) {
+	lockedAt := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase:    syncv1.MutexPhaseLocked,
+			Holder:   "holder-1",
+			LockedAt: &lockedAt,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+
+	holder, since, err := Owner(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.Equal(t, "holder-1", holder)
+	assert.True(t, lockedAt.Time.Equal(since))
+}
+
+func TestOwner_Unlocked(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseUnlocked,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+
+	holder, since, err := Owner(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.Empty(t, holder)
+	assert.True(t, since.IsZero())
+}
+
+func TestOwner_NotFound(t *testing.T) {
+	client := setupTestClient(t)
+
+	_, _, err := Owner(client, context.Background(), "missing")
+	assert.Error(t, err)
+}
+
 func TestLock(t *testing.T) {
 	mutex := &syncv1.Mutex{
 		ObjectMeta: metav1.ObjectMeta{
@@ -204,6 +311,69 @@ func TestUnlock_NotHolder(t *testing.T) {
 	err := m.Unlock(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not the holder")
+	assert.True(t, errors.Is(err, konductor.ErrNotHolder))
+}
+
+func TestBreak(t *testing.T) {
+	lockedAt := metav1.Now()
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase:      syncv1.MutexPhaseLocked,
+			Holder:     "gone-holder",
+			LockedAt:   &lockedAt,
+			FenceToken: 4,
+			HoldCount:  2,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+
+	previous, err := Break(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.Equal(t, "gone-holder", previous)
+
+	updated, err := Get(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, updated.Status.Phase)
+	assert.Empty(t, updated.Status.Holder)
+	assert.Nil(t, updated.Status.LockedAt)
+	assert.Zero(t, updated.Status.HoldCount)
+	assert.Equal(t, int64(5), updated.Status.FenceToken)
+
+	// The next holder gets a token newer than the broken one
+	m, err := TryLock(client, context.Background(), "test-mutex", konductor.WithHolder("new-holder"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), m.FenceToken())
+}
+
+func TestBreak_Unlocked(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase:      syncv1.MutexPhaseUnlocked,
+			FenceToken: 4,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+
+	previous, err := Break(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.Empty(t, previous)
+
+	updated, err := Get(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), updated.Status.FenceToken, "an unlocked mutex is left alone")
+
+	_, err = Break(client, context.Background(), "missing")
+	assert.ErrorContains(t, err, "failed to break mutex missing")
 }
 
 func TestTryLock_Available(t *testing.T) {
@@ -241,6 +411,7 @@ func TestTryLock_Locked(t *testing.T) {
 	_, err := TryLock(client, context.Background(), "test-mutex", konductor.WithHolder("test-holder"))
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "locked")
+	assert.True(t, errors.Is(err, konductor.ErrLocked))
 }
 
 func TestWith(t *testing.T) {
@@ -292,3 +463,327 @@ func TestUpdate(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 10*time.Minute, updated.Spec.TTL.Duration)
 }
+
+func TestLock_FenceTokenIncreasesAcrossHolders(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase:      syncv1.MutexPhaseUnlocked,
+			FenceToken: 2,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+	ctx := context.Background()
+
+	first, err := Lock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), first.FenceToken())
+	require.NoError(t, first.Unlock(ctx))
+
+	second, err := TryLock(client, ctx, "test-mutex", konductor.WithHolder("holder-2"))
+	require.NoError(t, err)
+	assert.Greater(t, second.FenceToken(), first.FenceToken())
+	require.NoError(t, second.Unlock(ctx))
+
+	third, err := Lock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+	assert.Greater(t, third.FenceToken(), second.FenceToken())
+
+	stored, err := Get(client, ctx, "test-mutex")
+	require.NoError(t, err)
+	assert.Equal(t, third.FenceToken(), stored.Status.FenceToken)
+}
+
+func TestLock_Timeout(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase:  syncv1.MutexPhaseLocked,
+			Holder: "other-holder",
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+
+	_, err := Lock(client, context.Background(), "test-mutex",
+		konductor.WithHolder("test-holder"),
+		konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout acquiring mutex test-mutex: held by other-holder")
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestLock_WithoutConfirm(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseUnlocked,
+		},
+	}
+
+	newClient := func() *konductor.Client {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		require.NoError(t, syncv1.AddToScheme(scheme))
+
+		// Reading the lock back once it is held is slow, as against a
+		// lagging API server
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRuntimeObjects(mutex.DeepCopy()).
+			WithStatusSubresource(&syncv1.Mutex{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := c.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					if m, ok := obj.(*syncv1.Mutex); ok && m.Status.Holder == "test-holder" {
+						time.Sleep(300 * time.Millisecond)
+					}
+					return nil
+				},
+			}).
+			Build()
+		return konductor.NewFromClient(k8sClient, "test-ns")
+	}
+
+	lockTime := func(opts ...konductor.Option) time.Duration {
+		t.Helper()
+		c := newClient()
+		start := time.Now()
+		m, err := Lock(c, context.Background(), "test-mutex",
+			append([]konductor.Option{konductor.WithHolder("test-holder")}, opts...)...)
+		elapsed := time.Since(start)
+		require.NoError(t, err)
+		assert.Equal(t, "test-holder", m.Holder())
+
+		stored, err := Get(c, context.Background(), "test-mutex")
+		require.NoError(t, err)
+		assert.Equal(t, syncv1.MutexPhaseLocked, stored.Status.Phase)
+		assert.Equal(t, "test-holder", stored.Status.Holder)
+		return elapsed
+	}
+
+	assert.GreaterOrEqual(t, lockTime(), 300*time.Millisecond, "the lock is confirmed by default")
+	assert.Less(t, lockTime(konductor.WithoutConfirm()), 300*time.Millisecond)
+}
+
+func TestLock_TimeoutBoundsAcquire(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseUnlocked,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	// The mutex looks free, but another pod wins every update to it
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(mutex).
+		WithStatusSubresource(&syncv1.Mutex{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				return apierrors.NewConflict(syncv1.GroupVersion.WithResource("mutexes").GroupResource(), obj.GetName(), nil)
+			},
+		}).
+		Build()
+	client := konductor.NewFromClient(k8sClient, "test-ns")
+
+	start := time.Now()
+	_, err := Lock(client, context.Background(), "test-mutex",
+		konductor.WithHolder("test-holder"),
+		konductor.WithTimeout(500*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Less(t, time.Since(start), 750*time.Millisecond,
+		"the timeout must bound the acquire retries as well as the wait")
+	assert.NotContains(t, err.Error(), "held by", "a free mutex lost to conflicts names no holder")
+}
+
+func TestLock_ContextCancelledIsNotTimeout(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase:  syncv1.MutexPhaseLocked,
+			Holder: "other-holder",
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := Lock(client, ctx, "test-mutex",
+		konductor.WithHolder("test-holder"),
+		konductor.WithTimeout(10*time.Second))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestCreateReentrant(t *testing.T) {
+	client := setupTestClient(t)
+
+	err := Create(client, context.Background(), "test-mutex", konductor.WithReentrant())
+	require.NoError(t, err)
+
+	mutex, err := Get(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.True(t, mutex.Spec.Reentrant)
+}
+
+func TestLock_ReentrantRequiresMatchingUnlocks(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.MutexSpec{
+			Reentrant: true,
+		},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseUnlocked,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+	ctx := context.Background()
+
+	first, err := Lock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+
+	start := time.Now()
+	second, err := Lock(client, ctx, "test-mutex",
+		konductor.WithHolder("holder-1"),
+		konductor.WithTimeout(time.Second))
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "relocking must not wait for the mutex")
+	assert.Equal(t, first.FenceToken(), second.FenceToken())
+
+	third, err := TryLock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+
+	stored, err := Get(client, ctx, "test-mutex")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), stored.Status.HoldCount)
+
+	// Another holder is still locked out
+	_, err = TryLock(client, ctx, "test-mutex", konductor.WithHolder("holder-2"))
+	assert.True(t, errors.Is(err, konductor.ErrLocked))
+
+	require.NoError(t, third.Unlock(ctx))
+	require.NoError(t, second.Unlock(ctx))
+
+	locked, err := IsLocked(client, ctx, "test-mutex")
+	require.NoError(t, err)
+	assert.True(t, locked, "mutex must stay locked until every Lock is unlocked")
+
+	require.NoError(t, first.Unlock(ctx))
+
+	stored, err = Get(client, ctx, "test-mutex")
+	require.NoError(t, err)
+	assert.Equal(t, syncv1.MutexPhaseUnlocked, stored.Status.Phase)
+	assert.Equal(t, "", stored.Status.Holder)
+	assert.Equal(t, int32(0), stored.Status.HoldCount)
+}
+
+func TestLock_DoesNotCountFailures(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.MutexSpec{
+			Reentrant: true,
+		},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseUnlocked,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+	ctx := context.Background()
+
+	_, err := Lock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), client.RetryStats().Failures, "a plain Lock must not count as a failure")
+
+	_, err = Lock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+	_, err = TryLock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), client.RetryStats().Failures, "reentering must not count as a failure")
+}
+
+func TestLock_NonReentrantSameHolderWaits(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-mutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.MutexStatus{
+			Phase: syncv1.MutexPhaseUnlocked,
+		},
+	}
+
+	client := setupTestClient(t, mutex)
+	ctx := context.Background()
+
+	_, err := Lock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	require.NoError(t, err)
+
+	_, err = TryLock(client, ctx, "test-mutex", konductor.WithHolder("holder-1"))
+	assert.True(t, errors.Is(err, konductor.ErrLocked))
+}
+
+func TestLock_MissingMutex(t *testing.T) {
+	client := setupTestClient(t)
+
+	_, err := Lock(client, context.Background(), "test-mutex", konductor.WithTimeout(time.Second))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotFound))
+	assert.Contains(t, err.Error(), "mutex test-mutex not found in namespace test-ns")
+
+	_, err = TryLock(client, context.Background(), "test-mutex")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotFound))
+}
+
+func TestLock_AutoCreate(t *testing.T) {
+	client := setupTestClient(t)
+
+	m, err := Lock(client, context.Background(), "test-mutex", konductor.WithHolder("holder-1"),
+		konductor.WithAutoCreate(), konductor.WithReentrant())
+	require.NoError(t, err)
+	assert.Equal(t, "holder-1", m.Holder())
+
+	created, err := Get(client, context.Background(), "test-mutex")
+	require.NoError(t, err)
+	assert.True(t, created.Spec.Reentrant)
+	assert.Equal(t, "holder-1", created.Status.Holder)
+
+	// An existing mutex is used as it is
+	_, err = TryLock(client, context.Background(), "test-mutex", konductor.WithHolder("holder-2"), konductor.WithAutoCreate())
+	assert.True(t, errors.Is(err, konductor.ErrLocked))
+}
@@ -3,13 +3,11 @@ package once
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
@@ -18,6 +16,17 @@ import (
 // Do executes the function if it hasn't been executed yet
 // Returns true if this call executed the function, false if already executed
 func Do(c *konductor.Client, ctx context.Context, name string, fn func() error, opts ...konductor.Option) (bool, error) {
+	return DoWithResult(c, ctx, name, func() (string, error) {
+		return "", fn()
+	}, opts...)
+}
+
+// DoWithResult executes the function if it hasn't been executed yet and
+// stores the string it returns in the once's status, where callers that did
+// not execute it can read it with GetResult. The result must fit in the
+// status, so keep it small (at most 32KiB).
+// Returns true if this call executed the function, false if already executed
+func DoWithResult(c *konductor.Client, ctx context.Context, name string, fn func() (string, error), opts ...konductor.Option) (bool, error) {
 	options := &konductor.Options{}
 	for _, opt := range opts {
 		opt(options)
@@ -25,11 +34,7 @@ func Do(c *konductor.Client, ctx context.Context, name string, fn func() error,
 
 	executor := options.Holder
 	if executor == "" {
-		if hostname := os.Getenv("HOSTNAME"); hostname != "" {
-			executor = hostname
-		} else {
-			executor = fmt.Sprintf("sdk-%d", time.Now().Unix())
-		}
+		executor = konductor.DefaultHolder()
 	}
 
 	// Retry loop to handle race conditions
@@ -56,6 +61,7 @@ func Do(c *konductor.Client, ctx context.Context, name string, fn func() error,
 		once.Status.Executor = executor
 		executedAt := metav1.Now()
 		once.Status.ExecutedAt = &executedAt
+		once.Status.CompletedAt = nil
 		once.Status.Phase = syncv1.OncePhaseExecuted
 
 		if err := c.K8sClient().Status().Update(ctx, &once); err != nil {
@@ -72,7 +78,8 @@ func Do(c *konductor.Client, ctx context.Context, name string, fn func() error,
 		}
 
 		// Execute the function
-		if err := fn(); err != nil {
+		result, err := fn()
+		if err != nil {
 			// Rollback the execution status on failure with retry
 			rollbackBackoff := 100 * time.Millisecond
 			for rollbackRetries := 0; rollbackRetries < 3; rollbackRetries++ {
@@ -87,6 +94,8 @@ func Do(c *konductor.Client, ctx context.Context, name string, fn func() error,
 				rollbackOnce.Status.Executed = false
 				rollbackOnce.Status.Executor = ""
 				rollbackOnce.Status.ExecutedAt = nil
+				rollbackOnce.Status.CompletedAt = nil
+				rollbackOnce.Status.Result = ""
 				rollbackOnce.Status.Phase = syncv1.OncePhasePending
 
 				if rollbackErr := c.K8sClient().Status().Update(ctx, &rollbackOnce); rollbackErr != nil {
@@ -102,12 +111,62 @@ func Do(c *konductor.Client, ctx context.Context, name string, fn func() error,
 			return true, fmt.Errorf("execution failed: %w", err)
 		}
 
+		// Recording completion also tells the controller that a once with a
+		// ResetInterval is no longer running and may be reset
+		if err := complete(c, ctx, name, result); err != nil {
+			return true, err
+		}
+
 		return true, nil
 	}
 
 	return false, fmt.Errorf("failed to acquire execution after retries")
 }
 
+// complete records that the executor finished along with its result,
+// retrying on conflicts with the controller's own status updates
+func complete(c *konductor.Client, ctx context.Context, name, result string) error {
+	backoff := 100 * time.Millisecond
+	for retries := 0; retries < 5; retries++ {
+		var once syncv1.Once
+		if err := c.K8sClient().Get(ctx, types.NamespacedName{
+			Name:      name,
+			Namespace: c.Namespace(),
+		}, &once); err != nil {
+			return fmt.Errorf("executed but failed to get once %s to store result: %w", name, err)
+		}
+
+		completedAt := metav1.Now()
+		once.Status.CompletedAt = &completedAt
+		once.Status.Result = result
+		if err := c.K8sClient().Status().Update(ctx, &once); err != nil {
+			if errors.IsConflict(err) {
+				time.Sleep(backoff)
+				backoff *= 2
+				continue
+			}
+			return fmt.Errorf("executed but failed to store result for once %s: %w", name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("executed but failed to store result for once %s after retries", name)
+}
+
+// GetResult returns the result stored by the executor of the once. It
+// returns ErrNotExecuted if the once has not been executed yet. The result is
+// empty while the executor is still running, or if it stored none.
+func GetResult(c *konductor.Client, ctx context.Context, name string) (string, error) {
+	once, err := Get(c, ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if !once.Status.Executed {
+		return "", fmt.Errorf("once %s: %w", name, konductor.ErrNotExecuted)
+	}
+	return once.Status.Result, nil
+}
+
 // IsExecuted checks if the once has been executed
 func IsExecuted(c *konductor.Client, ctx context.Context, name string) (bool, error) {
 	once, err := Get(c, ctx, name)
@@ -162,6 +221,16 @@ func Delete(c *konductor.Client, ctx context.Context, name string) error {
 	return err
 }
 
+// Update writes the once's spec and metadata, as a server-side apply with
+// WithServerSideApply. Whether it has executed is status, which Update leaves
+// alone.
+func Update(c *konductor.Client, ctx context.Context, once *syncv1.Once, opts ...konductor.Option) error {
+	if err := c.Update(ctx, once, opts...); err != nil {
+		return fmt.Errorf("failed to update once %s: %w", once.Name, err)
+	}
+	return nil
+}
+
 func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.Once, error) {
 	var once syncv1.Once
 	if err := c.K8sClient().Get(ctx, types.NamespacedName{
@@ -173,9 +242,9 @@ func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.Once, e
 	return &once, nil
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.Once, error) {
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.Once, error) {
 	var onces syncv1.OnceList
-	if err := c.K8sClient().List(ctx, &onces, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &onces, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list onces: %w", err)
 	}
 	return onces.Items, nil
@@ -48,6 +48,61 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "once1", onces[0].Name)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-once",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-once",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-once", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-once",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-once",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestGet(t *testing.T) {
 	once := &syncv1.Once{
 		ObjectMeta: metav1.ObjectMeta{
@@ -95,6 +150,30 @@ func TestDelete(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpdate(t *testing.T) {
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.OnceStatus{Executed: true, Executor: "init-job"},
+	}
+	client := setupTestClient(t, once)
+	ctx := context.Background()
+
+	current, err := Get(client, ctx, "test-once")
+	require.NoError(t, err)
+	current.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+	current.Status.Executed = false
+	require.NoError(t, Update(client, ctx, current))
+
+	updated, err := Get(client, ctx, "test-once")
+	require.NoError(t, err)
+	require.NotNil(t, updated.Spec.TTL)
+	assert.Equal(t, time.Hour, updated.Spec.TTL.Duration)
+	assert.True(t, updated.Status.Executed, "Update leaves the status alone")
+}
+
 func TestIsExecuted_NotExecuted(t *testing.T) {
 	once := &syncv1.Once{
 		ObjectMeta: metav1.ObjectMeta{
@@ -161,6 +240,31 @@ func TestDo_FirstExecution(t *testing.T) {
 	assert.Equal(t, syncv1.OncePhaseExecuted, updated.Status.Phase)
 }
 
+func TestDo_RecordsCompletion(t *testing.T) {
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "test-ns",
+		},
+	}
+
+	client := setupTestClient(t, once)
+
+	_, err := Do(client, context.Background(), "test-once", func() error {
+		// Still running, so the controller must not reset it yet
+		running, err := Get(client, context.Background(), "test-once")
+		require.NoError(t, err)
+		assert.True(t, running.Status.Executed)
+		assert.Nil(t, running.Status.CompletedAt)
+		return nil
+	})
+	require.NoError(t, err)
+
+	updated, err := Get(client, context.Background(), "test-once")
+	require.NoError(t, err)
+	assert.NotNil(t, updated.Status.CompletedAt)
+}
+
 func TestDo_AlreadyExecuted(t *testing.T) {
 	once := &syncv1.Once{
 		ObjectMeta: metav1.ObjectMeta{
@@ -208,3 +312,67 @@ func TestDo_FunctionError(t *testing.T) {
 	assert.True(t, didExecute)
 	assert.Contains(t, err.Error(), "execution failed")
 }
+
+func TestDoWithResult_ReadableByOtherCaller(t *testing.T) {
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "test-ns",
+		},
+	}
+
+	client := setupTestClient(t, once)
+
+	_, err := GetResult(client, context.Background(), "test-once")
+	assert.ErrorIs(t, err, konductor.ErrNotExecuted)
+
+	didExecute, err := DoWithResult(client, context.Background(), "test-once", func() (string, error) {
+		return "schema-v42", nil
+	}, konductor.WithHolder("executor-1"))
+	require.NoError(t, err)
+	assert.True(t, didExecute)
+
+	// A second caller does not run its function and reads the stored result
+	called := false
+	didExecute, err = DoWithResult(client, context.Background(), "test-once", func() (string, error) {
+		called = true
+		return "schema-v43", nil
+	}, konductor.WithHolder("executor-2"))
+	require.NoError(t, err)
+	assert.False(t, didExecute)
+	assert.False(t, called)
+
+	result, err := GetResult(client, context.Background(), "test-once")
+	require.NoError(t, err)
+	assert.Equal(t, "schema-v42", result)
+
+	updated, err := Get(client, context.Background(), "test-once")
+	require.NoError(t, err)
+	assert.Equal(t, "executor-1", updated.Status.Executor)
+	assert.Equal(t, "schema-v42", updated.Status.Result)
+}
+
+func TestDoWithResult_FunctionErrorStoresNoResult(t *testing.T) {
+	once := &syncv1.Once{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-once",
+			Namespace: "test-ns",
+		},
+	}
+
+	client := setupTestClient(t, once)
+
+	didExecute, err := DoWithResult(client, context.Background(), "test-once", func() (string, error) {
+		return "partial", errors.New("function failed")
+	}, konductor.WithHolder("executor-1"))
+	require.Error(t, err)
+	assert.True(t, didExecute)
+
+	updated, err := Get(client, context.Background(), "test-once")
+	require.NoError(t, err)
+	assert.False(t, updated.Status.Executed)
+	assert.Empty(t, updated.Status.Result)
+
+	_, err = GetResult(client, context.Background(), "test-once")
+	assert.ErrorIs(t, err, konductor.ErrNotExecuted)
+}
@@ -0,0 +1,202 @@
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+// recheckInterval bounds each wait for tokens, so a caller that missed an
+// update still looks at the bucket again
+const recheckInterval = 30 * time.Second
+
+// Take removes n tokens from the rate limiter's bucket, blocking until the
+// controller has refilled enough of them. WithTimeout bounds the wait, which
+// otherwise lasts until ctx is done. Asking for more tokens than the bucket
+// can hold fails straight away.
+func Take(c *konductor.Client, ctx context.Context, name string, n int32, opts ...konductor.Option) error {
+	if n <= 0 {
+		return fmt.Errorf("token count must be positive, got %d", n)
+	}
+
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	waitCtx := ctx
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	config := &konductor.WaitConfig{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     2 * time.Second,
+		Factor:       1.5,
+		Jitter:       0.1,
+		Timeout:      recheckInterval,
+	}
+
+	for {
+		var rl syncv1.RateLimiter
+		if err := c.K8sClient().Get(waitCtx, types.NamespacedName{
+			Name: name, Namespace: c.Namespace(),
+		}, &rl); err != nil {
+			return takeError(ctx, name, err)
+		}
+
+		if burst := burstOf(&rl); n > burst {
+			return fmt.Errorf("cannot take %d tokens from rate limiter %s, which holds at most %d", n, name, burst)
+		}
+
+		if rl.Status.AvailableTokens >= n {
+			err := take(c, waitCtx, &rl, n)
+			if err == nil {
+				return nil
+			}
+			if errors.IsConflict(err) {
+				// Another caller or a refill changed the bucket; look again
+				continue
+			}
+			return takeError(ctx, name, err)
+		}
+
+		err := c.WatchForCondition(waitCtx, &rl, func(obj client.Object) bool {
+			return obj.(*syncv1.RateLimiter).Status.AvailableTokens >= n
+		}, config)
+		if err != nil && !(waitCtx.Err() == nil && wait.Interrupted(err)) {
+			return takeError(ctx, name, err)
+		}
+	}
+}
+
+// take removes n tokens from rl. A full bucket earns no tokens, so taking
+// from one restarts the refill clock rather than crediting the time it sat
+// full.
+func take(c *konductor.Client, ctx context.Context, rl *syncv1.RateLimiter, n int32) error {
+	if rl.Status.AvailableTokens >= burstOf(rl) {
+		now := metav1.NewMicroTime(time.Now())
+		rl.Status.LastRefillTime = &now
+	}
+
+	rl.Status.AvailableTokens -= n
+	if rl.Status.AvailableTokens > 0 {
+		rl.Status.Phase = syncv1.RateLimiterPhaseAvailable
+	} else {
+		rl.Status.Phase = syncv1.RateLimiterPhaseExhausted
+	}
+
+	// Fails with a conflict if the bucket changed since it was read
+	return c.K8sClient().Status().Update(ctx, rl)
+}
+
+// takeError reports cancellation of the caller's ctx as-is and an exhausted
+// WithTimeout as ErrTimeout
+func takeError(ctx context.Context, name string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("context cancelled while waiting for rate limiter %s: %w", name, ctx.Err())
+	}
+	if wait.Interrupted(err) || err == context.DeadlineExceeded {
+		return fmt.Errorf("%w waiting for rate limiter %s: %w", konductor.ErrTimeout, name, err)
+	}
+	return fmt.Errorf("failed to take tokens from rate limiter %s: %w", name, err)
+}
+
+// burstOf returns the bucket size, which defaults to the rate
+func burstOf(rl *syncv1.RateLimiter) int32 {
+	if rl.Spec.Burst > 0 {
+		return rl.Spec.Burst
+	}
+	return rl.Spec.Rate
+}
+
+// Available returns the number of tokens that can be taken right now
+func Available(c *konductor.Client, ctx context.Context, name string) (int32, error) {
+	rl, err := Get(c, ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	return rl.Status.AvailableTokens, nil
+}
+
+// Create creates a rate limiter adding rate tokens every period. WithPeriod
+// sets the period, which defaults to one second, and WithBurst the bucket
+// size, which defaults to rate.
+func Create(c *konductor.Client, ctx context.Context, name string, rate int32, opts ...konductor.Option) error {
+	if rate <= 0 {
+		return fmt.Errorf("rate must be positive, got %d", rate)
+	}
+
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	rl := &syncv1.RateLimiter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace(),
+		},
+		Spec: syncv1.RateLimiterSpec{
+			Rate:  rate,
+			Burst: options.Burst,
+		},
+	}
+
+	if options.Period > 0 {
+		rl.Spec.Period = &metav1.Duration{Duration: options.Period}
+	}
+	if options.TTL > 0 {
+		rl.Spec.TTL = &metav1.Duration{Duration: options.TTL}
+	}
+
+	if err := c.K8sClient().Create(ctx, rl); err != nil {
+		return fmt.Errorf("failed to create rate limiter %s: %w", name, err)
+	}
+	return nil
+}
+
+func Delete(c *konductor.Client, ctx context.Context, name string) error {
+	rl := &syncv1.RateLimiter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.Namespace(),
+		},
+	}
+	if err := c.K8sClient().Delete(ctx, rl); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete rate limiter %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.RateLimiter, error) {
+	var rl syncv1.RateLimiter
+	if err := c.K8sClient().Get(ctx, types.NamespacedName{
+		Name:      name,
+		Namespace: c.Namespace(),
+	}, &rl); err != nil {
+		return nil, fmt.Errorf("failed to get rate limiter %s: %w", name, err)
+	}
+	return &rl, nil
+}
+
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.RateLimiter, error) {
+	var rateLimiters syncv1.RateLimiterList
+	if err := c.K8sClient().List(ctx, &rateLimiters, c.ListOptions(opts...)...); err != nil {
+		return nil, fmt.Errorf("failed to list rate limiters: %w", err)
+	}
+	return rateLimiters.Items, nil
+}
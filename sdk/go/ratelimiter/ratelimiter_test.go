@@ -0,0 +1,202 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+func setupTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.RateLimiter{}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func newRateLimiter(burst, available int32) *syncv1.RateLimiter {
+	lastRefill := metav1.NewMicroTime(time.Now().Add(-time.Hour))
+	return &syncv1.RateLimiter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ratelimiter",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.RateLimiterSpec{
+			Rate:  1,
+			Burst: burst,
+		},
+		Status: syncv1.RateLimiterStatus{
+			AvailableTokens: available,
+			LastRefillTime:  &lastRefill,
+			Phase:           syncv1.RateLimiterPhaseAvailable,
+		},
+	}
+}
+
+// refill stands in for the controller, setting the bucket to tokens. It runs
+// outside the test goroutine, so failures are reported without stopping.
+func refill(t *testing.T, c *konductor.Client, tokens int32) {
+	rl, err := Get(c, context.Background(), "test-ratelimiter")
+	if !assert.NoError(t, err) {
+		return
+	}
+	rl.Status.AvailableTokens = tokens
+	rl.Status.Phase = syncv1.RateLimiterPhaseAvailable
+	assert.NoError(t, c.K8sClient().Status().Update(context.Background(), rl))
+}
+
+func TestTake_Available(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(10, 5))
+
+	require.NoError(t, Take(client, context.Background(), "test-ratelimiter", 3))
+
+	available, err := Available(client, context.Background(), "test-ratelimiter")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), available)
+}
+
+func TestTake_LastTokenExhausts(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(10, 2))
+
+	require.NoError(t, Take(client, context.Background(), "test-ratelimiter", 2))
+
+	rl, err := Get(client, context.Background(), "test-ratelimiter")
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), rl.Status.AvailableTokens)
+	assert.Equal(t, syncv1.RateLimiterPhaseExhausted, rl.Status.Phase)
+}
+
+func TestTake_FromFullBucketRestartsRefillClock(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(5, 5))
+
+	start := time.Now()
+	require.NoError(t, Take(client, context.Background(), "test-ratelimiter", 1))
+
+	rl, err := Get(client, context.Background(), "test-ratelimiter")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), rl.Status.AvailableTokens)
+	assert.WithinDuration(t, start, rl.Status.LastRefillTime.Time, time.Second,
+		"a full bucket must not be credited for the time it sat full")
+}
+
+func TestTake_BlocksUntilRefilled(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(10, 0))
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		refill(t, client, 5)
+	}()
+
+	start := time.Now()
+	err := Take(client, context.Background(), "test-ratelimiter", 2, konductor.WithTimeout(5*time.Second))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	available, err := Available(client, context.Background(), "test-ratelimiter")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), available)
+}
+
+func TestTake_Timeout(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(10, 1))
+
+	err := Take(client, context.Background(), "test-ratelimiter", 2, konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+
+	available, err := Available(client, context.Background(), "test-ratelimiter")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), available, "a failed take must leave the bucket alone")
+}
+
+func TestTake_ContextCancelledIsNotTimeout(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(10, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	err := Take(client, ctx, "test-ratelimiter", 1)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestTake_MoreThanBurst(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(3, 3))
+
+	err := Take(client, context.Background(), "test-ratelimiter", 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "holds at most 3")
+}
+
+func TestTake_InvalidCount(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(3, 3))
+
+	assert.Error(t, Take(client, context.Background(), "test-ratelimiter", 0))
+}
+
+func TestTake_NotFound(t *testing.T) {
+	client := setupTestClient(t)
+
+	err := Take(client, context.Background(), "missing", 1)
+	assert.Error(t, err)
+}
+
+func TestCreate(t *testing.T) {
+	client := setupTestClient(t)
+
+	err := Create(client, context.Background(), "test-ratelimiter", 10,
+		konductor.WithBurst(20), konductor.WithPeriod(time.Minute), konductor.WithTTL(time.Hour))
+	require.NoError(t, err)
+
+	rl, err := Get(client, context.Background(), "test-ratelimiter")
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), rl.Spec.Rate)
+	assert.Equal(t, int32(20), rl.Spec.Burst)
+	require.NotNil(t, rl.Spec.Period)
+	assert.Equal(t, time.Minute, rl.Spec.Period.Duration)
+	require.NotNil(t, rl.Spec.TTL)
+	assert.Equal(t, time.Hour, rl.Spec.TTL.Duration)
+}
+
+func TestCreate_InvalidRate(t *testing.T) {
+	client := setupTestClient(t)
+
+	assert.Error(t, Create(client, context.Background(), "test-ratelimiter", 0))
+}
+
+func TestListAndDelete(t *testing.T) {
+	client := setupTestClient(t, newRateLimiter(3, 3))
+
+	rateLimiters, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, rateLimiters, 1)
+	assert.Equal(t, "test-ratelimiter", rateLimiters[0].Name)
+
+	require.NoError(t, Delete(client, context.Background(), "test-ratelimiter"))
+	require.NoError(t, Delete(client, context.Background(), "test-ratelimiter"))
+
+	_, err = Get(client, context.Background(), "test-ratelimiter")
+	assert.Error(t, err)
+}
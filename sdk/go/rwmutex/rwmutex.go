@@ -3,11 +3,13 @@ package rwmutex
 import (
 	"context"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -49,7 +51,7 @@ func (m *RWMutex) runlock(ctx context.Context) error {
 		}
 
 		if !found {
-			return fmt.Errorf("cannot unlock: not a read lock holder")
+			return fmt.Errorf("cannot unlock: %w of a read lock", konductor.ErrNotHolder)
 		}
 
 		rw.Status.ReadHolders = holders
@@ -74,7 +76,7 @@ func (m *RWMutex) wunlock(ctx context.Context) error {
 		}
 
 		if rw.Status.WriteHolder != m.holder {
-			return fmt.Errorf("cannot unlock: not the holder")
+			return fmt.Errorf("cannot unlock: %w", konductor.ErrNotHolder)
 		}
 
 		rw.Status.Phase = syncv1.RWMutexPhaseUnlocked
@@ -98,10 +100,7 @@ func getHolder(options *konductor.Options) string {
 	if options.Holder != "" {
 		return options.Holder
 	}
-	if hostname := os.Getenv("HOSTNAME"); hostname != "" {
-		return hostname
-	}
-	return fmt.Sprintf("sdk-%d", time.Now().Unix())
+	return konductor.DefaultHolder()
 }
 
 func getWaitConfig(timeout time.Duration) *konductor.WaitConfig {
@@ -118,6 +117,172 @@ func getWaitConfig(timeout time.Duration) *konductor.WaitConfig {
 	return config
 }
 
+// acquire repeatedly runs try until it reports the lock as taken, sleeping on a
+// watch until ready holds between attempts. With recheck set, try also runs
+// again whenever recheck passes without ready holding. It gives up when
+// config.Timeout elapses. It also returns the rwmutex as last seen, nil if it
+// never was, to report what stood in the way.
+func acquire(c *konductor.Client, ctx context.Context, name string, config *konductor.WaitConfig, recheck time.Duration,
+	ready func(*syncv1.RWMutex) bool, try func(*syncv1.RWMutex) (bool, error)) (*syncv1.RWMutex, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	watchConfig := *config
+	watchConfig.Timeout = 0
+
+	var last *syncv1.RWMutex
+	for {
+		var acquired bool
+		err := c.RetryWithBackoff(waitCtx, func() error {
+			var rw syncv1.RWMutex
+			if err := c.K8sClient().Get(waitCtx, types.NamespacedName{
+				Name: name, Namespace: c.Namespace(),
+			}, &rw); err != nil {
+				return err
+			}
+			last = rw.DeepCopy()
+			var err error
+			acquired, err = try(&rw)
+			return err
+		}, nil)
+		if err != nil {
+			if waitCtx.Err() != nil {
+				return last, waitCtx.Err()
+			}
+			return last, err
+		}
+		if acquired {
+			return last, nil
+		}
+
+		rw := &syncv1.RWMutex{}
+		rw.Name = name
+		rw.Namespace = c.Namespace()
+		watchCtx, cancelWatch := waitCtx, context.CancelFunc(func() {})
+		if recheck > 0 {
+			watchCtx, cancelWatch = context.WithTimeout(waitCtx, recheck)
+		}
+		err = c.WatchForCondition(watchCtx, rw, func(obj client.Object) bool {
+			return ready(obj.(*syncv1.RWMutex))
+		}, &watchConfig)
+		cancelWatch()
+		if err != nil {
+			if watchCtx.Err() != nil && waitCtx.Err() == nil {
+				continue
+			}
+			if rw.ResourceVersion != "" {
+				last = rw
+			}
+			return last, err
+		}
+	}
+}
+
+// ensureRWMutex fails with ErrNotFound if the named rwmutex does not exist,
+// unless WithAutoCreate asks for it to be created
+func ensureRWMutex(c *konductor.Client, ctx context.Context, name string, options *konductor.Options) error {
+	rw := &syncv1.RWMutex{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.Namespace()}}
+	return c.GetOrCreate(ctx, "rwmutex", rw, options.AutoCreate)
+}
+
+// lockError wraps ErrTimeout when acquire gave up because its own wait ran out
+// rather than because ctx was cancelled, saying who held the rwmutex when last
+// seen unless nobody other than holder did
+func lockError(ctx context.Context, kind, name, holder string, last *syncv1.RWMutex, err error) error {
+	if ctx.Err() == nil && wait.Interrupted(err) {
+		if blocking := blockedBy(last, holder, kind == "read"); blocking != "" {
+			return fmt.Errorf("%w acquiring %s lock on %s: %s: %w", konductor.ErrTimeout, kind, name, blocking, err)
+		}
+		return fmt.Errorf("%w acquiring %s lock on %s: %w", konductor.ErrTimeout, kind, name, err)
+	}
+	return fmt.Errorf("failed to acquire %s lock on %s: %w", kind, name, err)
+}
+
+// blockedBy describes who rw shows keeping holder from taking a read lock, or
+// a write lock unless read is set, or returns "" if nobody is. Readers only
+// wait on a writer, whether it holds the lock or is waiting for it.
+func blockedBy(rw *syncv1.RWMutex, holder string, read bool) string {
+	if rw == nil {
+		return ""
+	}
+	if rw.Status.WriteHolder != "" && rw.Status.WriteHolder != holder {
+		return "held by " + rw.Status.WriteHolder
+	}
+	if read {
+		if rw.Status.WritePending != "" {
+			return "waiting behind writer " + rw.Status.WritePending
+		}
+		return ""
+	}
+	var readers []string
+	for _, reader := range rw.Status.ReadHolders {
+		if reader != holder {
+			readers = append(readers, reader)
+		}
+	}
+	if len(readers) > 0 {
+		return "held by " + strings.Join(readers, ", ")
+	}
+	if rw.Status.WritePending != "" && rw.Status.WritePending != holder {
+		return "waiting behind writer " + rw.Status.WritePending
+	}
+	return ""
+}
+
+// readable reports whether a reader may take the lock. Readers yield to a
+// waiting writer so a stream of readers cannot starve it.
+func readable(rw *syncv1.RWMutex) bool {
+	return rw.Status.WriteHolder == "" && rw.Status.WritePending == ""
+}
+
+// writable reports whether holder may take the write lock
+func writable(rw *syncv1.RWMutex, holder string) bool {
+	return rw.Status.WriteHolder == "" && len(rw.Status.ReadHolders) == 0 &&
+		(rw.Status.WritePending == "" || rw.Status.WritePending == holder)
+}
+
+// writePendingRenewal is how often a waiting writer renews its registration,
+// well inside the timeout after which the controller clears it
+const writePendingRenewal = syncv1.RWMutexWritePendingTimeout / 3
+
+// writePendingStale reports whether the pending writer registration in rw is
+// due for renewal
+func writePendingStale(rw *syncv1.RWMutex) bool {
+	since := rw.Status.WritePendingSince
+	return since == nil || time.Since(since.Time) >= writePendingRenewal
+}
+
+// takeRead adds holder to the readers in rw's status
+func takeRead(rw *syncv1.RWMutex, holder string) {
+	rw.Status.Phase = syncv1.RWMutexPhaseReadLocked
+	rw.Status.ReadHolders = append(rw.Status.ReadHolders, holder)
+
+	if rw.Status.LockedAt == nil {
+		lockedAt := metav1.Now()
+		rw.Status.LockedAt = &lockedAt
+	}
+
+	if rw.Spec.TTL != nil {
+		expiresAt := metav1.NewTime(time.Now().Add(rw.Spec.TTL.Duration))
+		rw.Status.ExpiresAt = &expiresAt
+	}
+}
+
+// takeWrite records holder as the writer in rw's status
+func takeWrite(rw *syncv1.RWMutex, holder string) {
+	rw.Status.Phase = syncv1.RWMutexPhaseWriteLocked
+	rw.Status.WriteHolder = holder
+	rw.Status.WritePending = ""
+	rw.Status.WritePendingSince = nil
+	lockedAt := metav1.Now()
+	rw.Status.LockedAt = &lockedAt
+
+	if rw.Spec.TTL != nil {
+		expiresAt := metav1.NewTime(time.Now().Add(rw.Spec.TTL.Duration))
+		rw.Status.ExpiresAt = &expiresAt
+	}
+}
+
 func RLock(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*RWMutex, error) {
 	options := &konductor.Options{Timeout: 0}
 	for _, opt := range opts {
@@ -132,37 +297,21 @@ func RLock(c *konductor.Client, ctx context.Context, name string, opts ...konduc
 
 	config := getWaitConfig(options.Timeout)
 
-	// Atomically check and acquire read lock
-	err := c.RetryWithBackoff(ctx, func() error {
-		var rw syncv1.RWMutex
-		if err := c.K8sClient().Get(ctx, types.NamespacedName{
-			Name: name, Namespace: c.Namespace(),
-		}, &rw); err != nil {
-			return err
-		}
-
-		if rw.Status.WriteHolder != "" {
-			return fmt.Errorf("cannot acquire read lock: write lock is held")
-		}
-
-		rw.Status.Phase = syncv1.RWMutexPhaseReadLocked
-		rw.Status.ReadHolders = append(rw.Status.ReadHolders, holder)
-
-		if rw.Status.LockedAt == nil {
-			lockedAt := metav1.Now()
-			rw.Status.LockedAt = &lockedAt
-		}
+	if err := ensureRWMutex(c, ctx, name, options); err != nil {
+		return nil, err
+	}
 
-		if rw.Spec.TTL != nil {
-			expiresAt := metav1.NewTime(time.Now().Add(rw.Spec.TTL.Duration))
-			rw.Status.ExpiresAt = &expiresAt
+	last, err := acquire(c, ctx, name, config, 0, readable, func(rw *syncv1.RWMutex) (bool, error) {
+		if !readable(rw) {
+			return false, nil
 		}
 
-		return c.K8sClient().Status().Update(ctx, &rw)
-	}, config)
+		takeRead(rw, holder)
+		return true, c.K8sClient().Status().Update(ctx, rw)
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("timeout acquiring read lock on %s: %w", name, err)
+		return nil, lockError(ctx, "read", name, holder, last, err)
 	}
 
 	// Wait for confirmation
@@ -187,40 +336,138 @@ func Lock(c *konductor.Client, ctx context.Context, name string, opts ...konduct
 	holder := getHolder(options)
 	config := getWaitConfig(options.Timeout)
 
-	// Atomically check and acquire write lock
-	err := c.RetryWithBackoff(ctx, func() error {
-		var rw syncv1.RWMutex
-		if err := c.K8sClient().Get(ctx, types.NamespacedName{
-			Name: name, Namespace: c.Namespace(),
-		}, &rw); err != nil {
-			return err
-		}
-
-		if rw.Status.WriteHolder != "" || len(rw.Status.ReadHolders) > 0 {
-			return fmt.Errorf("rwmutex locked")
-		}
+	writableBy := func(rw *syncv1.RWMutex) bool {
+		return writable(rw, holder)
+	}
 
-		rw.Status.Phase = syncv1.RWMutexPhaseWriteLocked
-		rw.Status.WriteHolder = holder
-		lockedAt := metav1.Now()
-		rw.Status.LockedAt = &lockedAt
+	if err := ensureRWMutex(c, ctx, name, options); err != nil {
+		return nil, err
+	}
 
-		if rw.Spec.TTL != nil {
-			expiresAt := metav1.NewTime(time.Now().Add(rw.Spec.TTL.Duration))
-			rw.Status.ExpiresAt = &expiresAt
+	// While waiting the writer renews its registration, so the controller can
+	// tell it from one left behind by a writer that died
+	last, err := acquire(c, ctx, name, config, writePendingRenewal, writableBy, func(rw *syncv1.RWMutex) (bool, error) {
+		if !writable(rw, holder) {
+			// Register as the pending writer so new readers stop piling up behind us
+			if rw.Status.WritePending == "" || (rw.Status.WritePending == holder && writePendingStale(rw)) {
+				now := metav1.Now()
+				rw.Status.WritePending = holder
+				rw.Status.WritePendingSince = &now
+				return false, c.K8sClient().Status().Update(ctx, rw)
+			}
+			return false, nil
 		}
 
-		return c.K8sClient().Status().Update(ctx, &rw)
-	}, config)
+		takeWrite(rw, holder)
+		return true, c.K8sClient().Status().Update(ctx, rw)
+	})
 
 	if err != nil {
-		return nil, fmt.Errorf("timeout acquiring write lock on %s: %w", name, err)
+		if cleanupErr := clearWritePending(c, name, holder); cleanupErr != nil {
+			return nil, fmt.Errorf("%w (cleanup failed: %v)", lockError(ctx, "write", name, holder, last, err), cleanupErr)
+		}
+		return nil, lockError(ctx, "write", name, holder, last, err)
 	}
 
 	mutex := &RWMutex{client: c, name: name, holder: holder, isRead: false}
 	return mutex, nil
 }
 
+// TryRLock takes a read lock with a single status update, without waiting. It
+// fails with ErrLocked if a writer holds or is waiting for the lock, or if
+// another update to the rwmutex wins the race.
+func TryRLock(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*RWMutex, error) {
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	holder := getHolder(options)
+	if err := tryAcquire(c, ctx, name, "read", options.AutoCreate, readable, func(rw *syncv1.RWMutex) {
+		takeRead(rw, holder)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &RWMutex{client: c, name: name, holder: holder, isRead: true}, nil
+}
+
+// TryLock takes the write lock with a single status update, without waiting.
+// It fails with ErrLocked if the rwmutex is held by a reader or writer, another
+// writer is waiting for it, or another update to the rwmutex wins the race.
+func TryLock(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*RWMutex, error) {
+	options := &konductor.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	holder := getHolder(options)
+	writableBy := func(rw *syncv1.RWMutex) bool {
+		return writable(rw, holder)
+	}
+	if err := tryAcquire(c, ctx, name, "write", options.AutoCreate, writableBy, func(rw *syncv1.RWMutex) {
+		takeWrite(rw, holder)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &RWMutex{client: c, name: name, holder: holder, isRead: false}, nil
+}
+
+// tryAcquire reads the rwmutex once, creating it first if autoCreate is set,
+// and if available allows it, applies take in a single status update
+func tryAcquire(c *konductor.Client, ctx context.Context, name, kind string, autoCreate bool,
+	available func(*syncv1.RWMutex) bool, take func(*syncv1.RWMutex)) error {
+	rw := syncv1.RWMutex{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.Namespace()}}
+	if err := c.GetOrCreate(ctx, "rwmutex", &rw, autoCreate); err != nil {
+		return fmt.Errorf("failed to acquire %s lock on %s: %w", kind, name, err)
+	}
+
+	if !available(&rw) {
+		switch {
+		case rw.Status.WriteHolder != "":
+			return fmt.Errorf("rwmutex %s already %w for writing by %s", name, konductor.ErrLocked, rw.Status.WriteHolder)
+		case rw.Status.WritePending != "":
+			return fmt.Errorf("rwmutex %s %w: writer %s is waiting", name, konductor.ErrLocked, rw.Status.WritePending)
+		default:
+			return fmt.Errorf("rwmutex %s already %w for reading by %d holders", name, konductor.ErrLocked, len(rw.Status.ReadHolders))
+		}
+	}
+
+	take(&rw)
+	if err := c.K8sClient().Status().Update(ctx, &rw); err != nil {
+		if errors.IsConflict(err) {
+			return fmt.Errorf("rwmutex %s %w by another process", name, konductor.ErrLocked)
+		}
+		return fmt.Errorf("failed to acquire %s lock on %s: %w", kind, name, err)
+	}
+	return nil
+}
+
+// clearWritePending withdraws holder's pending write registration after a failed
+// Lock. It uses its own context since the caller's may already be cancelled.
+func clearWritePending(c *konductor.Client, name, holder string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return c.RetryWithBackoff(ctx, func() error {
+		var rw syncv1.RWMutex
+		if err := c.K8sClient().Get(ctx, types.NamespacedName{
+			Name: name, Namespace: c.Namespace(),
+		}, &rw); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		if rw.Status.WritePending != holder {
+			return nil
+		}
+
+		rw.Status.WritePending = ""
+		rw.Status.WritePendingSince = nil
+		return c.K8sClient().Status().Update(ctx, &rw)
+	}, nil)
+}
+
 func Create(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
 	options := &konductor.Options{}
 	for _, opt := range opts {
@@ -252,6 +499,15 @@ func Delete(c *konductor.Client, ctx context.Context, name string) error {
 	return c.K8sClient().Delete(ctx, rwmutex)
 }
 
+// Update writes the rwmutex's spec and metadata, as a server-side apply with
+// WithServerSideApply
+func Update(c *konductor.Client, ctx context.Context, rwmutex *syncv1.RWMutex, opts ...konductor.Option) error {
+	if err := c.Update(ctx, rwmutex, opts...); err != nil {
+		return fmt.Errorf("failed to update rwmutex %s: %w", rwmutex.Name, err)
+	}
+	return nil
+}
+
 func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.RWMutex, error) {
 	var rwmutex syncv1.RWMutex
 	if err := c.K8sClient().Get(ctx, types.NamespacedName{
@@ -263,14 +519,88 @@ func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.RWMutex
 	return &rwmutex, nil
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.RWMutex, error) {
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.RWMutex, error) {
 	var rwmutexes syncv1.RWMutexList
-	if err := c.K8sClient().List(ctx, &rwmutexes, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &rwmutexes, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list rwmutexes: %w", err)
 	}
 	return rwmutexes.Items, nil
 }
 
+// Holders describes who currently holds a rwmutex
+type Holders struct {
+	// Writer holds the write lock, if any
+	Writer string
+	// Readers hold read locks, in the order they took them
+	Readers []string
+	// Pending is the writer waiting for the readers to drain
+	Pending string
+	// Since is when the writer took the lock, or since when the rwmutex has
+	// been read locked without interruption. It is zero while unlocked.
+	Since time.Time
+}
+
+// Owners returns the current write and read holders of the rwmutex. The
+// rwmutex records a single lock time, so all readers share one Since.
+func Owners(c *konductor.Client, ctx context.Context, name string) (*Holders, error) {
+	rw, err := Get(c, ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	holders := &Holders{
+		Writer:  rw.Status.WriteHolder,
+		Readers: rw.Status.ReadHolders,
+		Pending: rw.Status.WritePending,
+	}
+	if rw.Status.LockedAt != nil && (holders.Writer != "" || len(holders.Readers) > 0) {
+		holders.Since = rw.Status.LockedAt.Time
+	}
+	return holders, nil
+}
+
+// Break releases every lock on the rwmutex whoever holds it, for freeing a
+// lock whose holders are gone before its TTL runs out. A pending writer is
+// withdrawn too; one that is still waiting registers again on its next
+// attempt. It returns who held the rwmutex before it was broken.
+func Break(c *konductor.Client, ctx context.Context, name string) (*Holders, error) {
+	previous := &Holders{}
+	err := c.RetryWithBackoff(ctx, func() error {
+		var rw syncv1.RWMutex
+		if err := c.K8sClient().Get(ctx, types.NamespacedName{
+			Name: name, Namespace: c.Namespace(),
+		}, &rw); err != nil {
+			return err
+		}
+
+		previous = &Holders{
+			Writer:  rw.Status.WriteHolder,
+			Readers: rw.Status.ReadHolders,
+			Pending: rw.Status.WritePending,
+		}
+		if rw.Status.LockedAt != nil {
+			previous.Since = rw.Status.LockedAt.Time
+		}
+		if previous.Writer == "" && len(previous.Readers) == 0 && previous.Pending == "" &&
+			rw.Status.Phase == syncv1.RWMutexPhaseUnlocked {
+			return nil
+		}
+
+		rw.Status.Phase = syncv1.RWMutexPhaseUnlocked
+		rw.Status.WriteHolder = ""
+		rw.Status.ReadHolders = nil
+		rw.Status.WritePending = ""
+		rw.Status.WritePendingSince = nil
+		rw.Status.LockedAt = nil
+		rw.Status.ExpiresAt = nil
+		return c.K8sClient().Status().Update(ctx, &rw)
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to break rwmutex %s: %w", name, err)
+	}
+	return previous, nil
+}
+
 // Unlock releases a lock held by the specified holder
 func Unlock(c *konductor.Client, ctx context.Context, name string, holder string) error {
 	m := &RWMutex{
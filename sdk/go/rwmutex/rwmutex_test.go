@@ -2,6 +2,7 @@ package rwmutex
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -49,6 +50,61 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "rwmutex1", rwmutexes[0].Name)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-rwmutex",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-rwmutex",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-rwmutex", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-rwmutex",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-rwmutex",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestGet(t *testing.T) {
 	rwmutex := &syncv1.RWMutex{
 		ObjectMeta: metav1.ObjectMeta{
@@ -96,6 +152,28 @@ func TestDelete(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestUpdate(t *testing.T) {
+	rwmutex := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-rwmutex",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.RWMutexSpec{TTL: &metav1.Duration{Duration: time.Minute}},
+	}
+	client := setupTestClient(t, rwmutex)
+	ctx := context.Background()
+
+	current, err := Get(client, ctx, "test-rwmutex")
+	require.NoError(t, err)
+	current.Spec.TTL = &metav1.Duration{Duration: 5 * time.Minute}
+	require.NoError(t, Update(client, ctx, current))
+
+	updated, err := Get(client, ctx, "test-rwmutex")
+	require.NoError(t, err)
+	require.NotNil(t, updated.Spec.TTL)
+	assert.Equal(t, 5*time.Minute, updated.Spec.TTL.Duration)
+}
+
 func TestRLock(t *testing.T) {
 	rwmutex := &syncv1.RWMutex{
 		ObjectMeta: metav1.ObjectMeta{
@@ -198,6 +276,26 @@ func TestRUnlock(t *testing.T) {
 	assert.Empty(t, updated.Status.ReadHolders)
 }
 
+func TestRUnlock_NotHolder(t *testing.T) {
+	rwmutex := &syncv1.RWMutex{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-rwmutex",
+			Namespace: "test-ns",
+		},
+		Status: syncv1.RWMutexStatus{
+			Phase:       syncv1.RWMutexPhaseReadLocked,
+			ReadHolders: []string{"reader-1"},
+		},
+	}
+
+	client := setupTestClient(t, rwmutex)
+	m := createRWMutex(client, "test-rwmutex", "reader-2", true)
+
+	err := m.Unlock(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNotHolder))
+}
+
 func TestRUnlock_MultipleReaders(t *testing.T) {
 	rwmutex := &syncv1.RWMutex{
 		ObjectMeta: metav1.ObjectMeta{
@@ -265,6 +363,7 @@ func TestWUnlock_NotHolder(t *testing.T) {
 	err := m.Unlock(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not the holder")
+	assert.True(t, errors.Is(err, konductor.ErrNotHolder))
 }
 
 func TestRLock_Timeout(t *testing.T) {
@@ -285,7 +384,8 @@ func TestRLock_Timeout(t *testing.T) {
 		konductor.WithHolder("reader-1"),
 		konductor.WithTimeout(testTimeout))
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "timeout")
+	assert.Contains(t, err.Error(), "timeout acquiring read lock on test-rwmutex: held by writer-1")
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
 }
 
 func TestLock_Timeout(t *testing.T) {
@@ -306,5 +406,416 @@ func TestLock_Timeout(t *testing.T) {
 		konductor.WithHolder("writer-1"),
 		konductor.WithTimeout(testTimeout))
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "timeout")
+	assert.Contains(t, err.Error(), "timeout acquiring write lock on test-rwmutex: held by other-writer")
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
+
+func TestLock_TimeoutNamesReaders(t *testing.T) {
+	rwmutex := createTestRWMutex("test-rwmutex", "test-ns", syncv1.RWMutexPhaseReadLocked, []string{"reader-1", "reader-2"}, "")
+
+	client := setupTestClient(t, rwmutex)
+
+	_, err := Lock(client, context.Background(), "test-rwmutex",
+		konductor.WithHolder("writer-1"),
+		konductor.WithTimeout(500*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Contains(t, err.Error(), "timeout acquiring write lock on test-rwmutex: held by reader-1, reader-2")
+}
+
+func TestRLock_BlockedByPendingWriter(t *testing.T) {
+	rwmutex := createTestRWMutex("test-rwmutex", "test-ns", syncv1.RWMutexPhaseReadLocked, []string{"reader-1"}, "")
+	rwmutex.Status.WritePending = "writer-1"
+
+	client := setupTestClient(t, rwmutex)
+
+	_, err := RLock(client, context.Background(), "test-rwmutex",
+		konductor.WithHolder("reader-2"),
+		konductor.WithTimeout(500*time.Millisecond))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout acquiring read lock on test-rwmutex: waiting behind writer writer-1")
+
+	updated, err := Get(client, context.Background(), "test-rwmutex")
+	require.NoError(t, err)
+	assert.NotContains(t, updated.Status.ReadHolders, "reader-2")
+}
+
+func TestLock_WriterPreference(t *testing.T) {
+	rwmutex := createTestRWMutex("test-rwmutex", "test-ns", syncv1.RWMutexPhaseReadLocked, []string{"reader-1"}, "")
+	client := setupTestClient(t, rwmutex)
+	ctx := context.Background()
+
+	writerLocked := make(chan *RWMutex, 1)
+	writerErr := make(chan error, 1)
+	go func() {
+		m, err := Lock(client, ctx, "test-rwmutex",
+			konductor.WithHolder("writer-1"),
+			konductor.WithTimeout(10*time.Second))
+		if err != nil {
+			writerErr <- err
+			return
+		}
+		writerLocked <- m
+	}()
+
+	require.Eventually(t, func() bool {
+		rw, err := Get(client, ctx, "test-rwmutex")
+		return err == nil && rw.Status.WritePending == "writer-1"
+	}, 5*time.Second, 20*time.Millisecond, "writer should register as pending")
+
+	// Readers arriving after the writer started waiting must queue behind it
+	readerLocked := make(chan time.Time, 1)
+	go func() {
+		if _, err := RLock(client, ctx, "test-rwmutex",
+			konductor.WithHolder("reader-2"),
+			konductor.WithTimeout(10*time.Second)); err == nil {
+			readerLocked <- time.Now()
+		}
+	}()
+
+	time.Sleep(300 * time.Millisecond)
+	rw, err := Get(client, ctx, "test-rwmutex")
+	require.NoError(t, err)
+	assert.NotContains(t, rw.Status.ReadHolders, "reader-2")
+
+	require.NoError(t, createRWMutex(client, "test-rwmutex", "reader-1", true).Unlock(ctx))
+
+	var writer *RWMutex
+	select {
+	case writer = <-writerLocked:
+	case err := <-writerErr:
+		t.Fatalf("writer failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("writer did not acquire the lock")
+	}
+
+	rw, err = Get(client, ctx, "test-rwmutex")
+	require.NoError(t, err)
+	assert.Equal(t, "writer-1", rw.Status.WriteHolder)
+	assert.Empty(t, rw.Status.WritePending)
+	assert.NotContains(t, rw.Status.ReadHolders, "reader-2")
+
+	writerReleased := time.Now()
+	require.NoError(t, writer.Unlock(ctx))
+
+	select {
+	case acquiredAt := <-readerLocked:
+		assert.True(t, acquiredAt.After(writerReleased), "reader should acquire only after the writer releases")
+	case <-time.After(5 * time.Second):
+		t.Fatal("reader did not acquire the lock after writer released")
+	}
+}
+
+func TestLock_RenewsWritePending(t *testing.T) {
+	// writer-1 registered a while ago, so its registration is due for renewal
+	stale := metav1.NewTime(time.Now().Add(-time.Minute))
+	rwmutex := createTestRWMutex("test-rwmutex", "test-ns", syncv1.RWMutexPhaseReadLocked, []string{"reader-1"}, "")
+	rwmutex.Status.WritePending = "writer-1"
+	rwmutex.Status.WritePendingSince = &stale
+	client := setupTestClient(t, rwmutex)
+	ctx := context.Background()
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_, _ = Lock(client, lockCtx, "test-rwmutex", konductor.WithHolder("writer-1"), konductor.WithTimeout(10*time.Second))
+	}()
+
+	require.Eventually(t, func() bool {
+		rw, err := Get(client, ctx, "test-rwmutex")
+		return err == nil && rw.Status.WritePending == "writer-1" &&
+			rw.Status.WritePendingSince != nil && rw.Status.WritePendingSince.After(stale.Time)
+	}, 5*time.Second, 20*time.Millisecond, "the waiting writer should renew its registration")
+}
+
+func TestLock_TimeoutClearsWritePending(t *testing.T) {
+	rwmutex := createTestRWMutex("test-rwmutex", "test-ns", syncv1.RWMutexPhaseReadLocked, []string{"reader-1"}, "")
+	client := setupTestClient(t, rwmutex)
+
+	_, err := Lock(client, context.Background(), "test-rwmutex",
+		konductor.WithHolder("writer-1"),
+		konductor.WithTimeout(500*time.Millisecond))
+	require.Error(t, err)
+
+	rw, err := Get(client, context.Background(), "test-rwmutex")
+	require.NoError(t, err)
+	assert.Empty(t, rw.Status.WritePending)
+}
+
+func TestLock_ContextCancelClearsWritePending(t *testing.T) {
+	rwmutex := createTestRWMutex("test-rwmutex", "test-ns", syncv1.RWMutexPhaseReadLocked, []string{"reader-1"}, "")
+	client := setupTestClient(t, rwmutex)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := Lock(client, ctx, "test-rwmutex",
+			konductor.WithHolder("writer-1"),
+			konductor.WithTimeout(10*time.Second))
+		errCh <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		rw, err := Get(client, context.Background(), "test-rwmutex")
+		return err == nil && rw.Status.WritePending == "writer-1"
+	}, 5*time.Second, 20*time.Millisecond)
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.False(t, errors.Is(err, konductor.ErrTimeout))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lock did not return after context cancellation")
+	}
+
+	rw, err := Get(client, context.Background(), "test-rwmutex")
+	require.NoError(t, err)
+	assert.Empty(t, rw.Status.WritePending)
+}
+
+func TestTryRLock(t *testing.T) {
+	tests := []struct {
+		name        string
+		readHolders []string
+		writeHolder string
+		pending     string
+		wantErr     bool
+	}{
+		{name: "unlocked"},
+		{name: "read held", readHolders: []string{"reader-1"}},
+		{name: "write held", writeHolder: "writer-1", wantErr: true},
+		{name: "writer waiting", readHolders: []string{"reader-1"}, pending: "writer-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rwmutex := createTestRWMutex("test-rwmutex", "test-ns", "", tt.readHolders, tt.writeHolder)
+			rwmutex.Status.WritePending = tt.pending
+			client := setupTestClient(t, rwmutex)
+
+			start := time.Now()
+			m, err := TryRLock(client, context.Background(), "test-rwmutex", konductor.WithHolder("reader-2"))
+			assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+			updated, getErr := Get(client, context.Background(), "test-rwmutex")
+			require.NoError(t, getErr)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, konductor.ErrLocked))
+				assert.NotContains(t, updated.Status.ReadHolders, "reader-2")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, m.isRead)
+			assert.Contains(t, updated.Status.ReadHolders, "reader-2")
+			assert.Equal(t, syncv1.RWMutexPhaseReadLocked, updated.Status.Phase)
+		})
+	}
+}
+
+func TestTryLock(t *testing.T) {
+	tests := []struct {
+		name        string
+		readHolders []string
+		writeHolder string
+		wantErr     bool
+	}{
+		{name: "unlocked"},
+		{name: "read held", readHolders: []string{"reader-1"}, wantErr: true},
+		{name: "write held", writeHolder: "writer-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rwmutex := createTestRWMutex("test-rwmutex", "test-ns", "", tt.readHolders, tt.writeHolder)
+			client := setupTestClient(t, rwmutex)
+
+			start := time.Now()
+			m, err := TryLock(client, context.Background(), "test-rwmutex", konductor.WithHolder("writer-2"))
+			assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+			updated, getErr := Get(client, context.Background(), "test-rwmutex")
+			require.NoError(t, getErr)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, konductor.ErrLocked))
+				assert.NotEqual(t, "writer-2", updated.Status.WriteHolder)
+				assert.Empty(t, updated.Status.WritePending, "TryLock must not register as a pending writer")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.False(t, m.isRead)
+			assert.Equal(t, "writer-2", updated.Status.WriteHolder)
+			assert.Equal(t, syncv1.RWMutexPhaseWriteLocked, updated.Status.Phase)
+		})
+	}
+}
+
+func TestOwners(t *testing.T) {
+	lockedAt := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+
+	tests := []struct {
+		name   string
+		status syncv1.RWMutexStatus
+		want   Holders
+	}{
+		{
+			name: "write locked",
+			status: syncv1.RWMutexStatus{
+				Phase:       syncv1.RWMutexPhaseWriteLocked,
+				WriteHolder: "writer",
+				LockedAt:    &lockedAt,
+			},
+			want: Holders{Writer: "writer", Since: lockedAt.Time},
+		},
+		{
+			name: "read locked with a pending writer",
+			status: syncv1.RWMutexStatus{
+				Phase:        syncv1.RWMutexPhaseReadLocked,
+				ReadHolders:  []string{"reader-1", "reader-2"},
+				WritePending: "writer",
+				LockedAt:     &lockedAt,
+			},
+			want: Holders{Readers: []string{"reader-1", "reader-2"}, Pending: "writer", Since: lockedAt.Time},
+		},
+		{
+			name:   "unlocked",
+			status: syncv1.RWMutexStatus{Phase: syncv1.RWMutexPhaseUnlocked},
+			want:   Holders{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := &syncv1.RWMutex{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-rwmutex", Namespace: "test-ns"},
+				Status:     tt.status,
+			}
+			client := setupTestClient(t, rw)
+
+			holders, err := Owners(client, context.Background(), "test-rwmutex")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Writer, holders.Writer)
+			assert.Equal(t, tt.want.Readers, holders.Readers)
+			assert.Equal(t, tt.want.Pending, holders.Pending)
+			assert.True(t, tt.want.Since.Equal(holders.Since), "since %v, want %v", holders.Since, tt.want.Since)
+		})
+	}
+}
+
+func TestLock_MissingRWMutex(t *testing.T) {
+	client := setupTestClient(t)
+
+	for name, lock := range map[string]func() error{
+		"RLock": func() error {
+			_, err := RLock(client, context.Background(), "test-rwmutex", konductor.WithTimeout(time.Second))
+			return err
+		},
+		"Lock": func() error {
+			_, err := Lock(client, context.Background(), "test-rwmutex", konductor.WithTimeout(time.Second))
+			return err
+		},
+		"TryRLock": func() error {
+			_, err := TryRLock(client, context.Background(), "test-rwmutex")
+			return err
+		},
+		"TryLock": func() error {
+			_, err := TryLock(client, context.Background(), "test-rwmutex")
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := lock()
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, konductor.ErrNotFound))
+			assert.Contains(t, err.Error(), "rwmutex test-rwmutex not found in namespace test-ns")
+		})
+	}
+}
+
+func TestRLock_AutoCreate(t *testing.T) {
+	client := setupTestClient(t)
+
+	m, err := RLock(client, context.Background(), "test-rwmutex", konductor.WithHolder("reader-1"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+	assert.True(t, m.isRead)
+
+	_, err = TryRLock(client, context.Background(), "test-rwmutex", konductor.WithHolder("reader-2"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+
+	updated, err := Get(client, context.Background(), "test-rwmutex")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"reader-1", "reader-2"}, updated.Status.ReadHolders)
+}
+
+func TestTryLock_AutoCreate(t *testing.T) {
+	client := setupTestClient(t)
+
+	m, err := TryLock(client, context.Background(), "test-rwmutex", konductor.WithHolder("writer-1"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+	assert.False(t, m.isRead)
+
+	updated, err := Get(client, context.Background(), "test-rwmutex")
+	require.NoError(t, err)
+	assert.Equal(t, "writer-1", updated.Status.WriteHolder)
+}
+
+func TestBreak(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   syncv1.RWMutexStatus
+		expected Holders
+	}{
+		{
+			name: "write locked",
+			status: syncv1.RWMutexStatus{
+				Phase:       syncv1.RWMutexPhaseWriteLocked,
+				WriteHolder: "writer-1",
+			},
+			expected: Holders{Writer: "writer-1"},
+		},
+		{
+			name: "read locked with a writer waiting",
+			status: syncv1.RWMutexStatus{
+				Phase:        syncv1.RWMutexPhaseReadLocked,
+				ReadHolders:  []string{"reader-1", "reader-2"},
+				WritePending: "writer-1",
+			},
+			expected: Holders{Readers: []string{"reader-1", "reader-2"}, Pending: "writer-1"},
+		},
+		{
+			name:   "unlocked",
+			status: syncv1.RWMutexStatus{Phase: syncv1.RWMutexPhaseUnlocked},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := setupTestClient(t, &syncv1.RWMutex{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-rwmutex", Namespace: "test-ns"},
+				Status:     tt.status,
+			})
+
+			previous, err := Break(client, context.Background(), "test-rwmutex")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *previous)
+
+			updated, err := Get(client, context.Background(), "test-rwmutex")
+			require.NoError(t, err)
+			assert.Equal(t, syncv1.RWMutexPhaseUnlocked, updated.Status.Phase)
+			assert.Empty(t, updated.Status.WriteHolder)
+			assert.Empty(t, updated.Status.ReadHolders)
+			assert.Empty(t, updated.Status.WritePending)
+
+			_, err = TryLock(client, context.Background(), "test-rwmutex", konductor.WithHolder("writer-2"))
+			require.NoError(t, err)
+		})
+	}
 }
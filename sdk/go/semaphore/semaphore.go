@@ -2,12 +2,15 @@ package semaphore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
@@ -20,63 +23,497 @@ func Acquire(c *konductor.Client, ctx context.Context, name string, opts ...kond
 		opt(options)
 	}
 
-	holder := options.Holder
-	if holder == "" {
-		if hostname := os.Getenv("HOSTNAME"); hostname != "" {
-			holder = hostname
+	weight, err := permitWeight(options)
+	if err != nil {
+		return nil, err
+	}
+
+	holder := resolveHolder(options.Holder)
+	podOwner, err := permitPodOwner(c, options)
+	if err != nil {
+		return nil, err
+	}
+
+	semaphore, err := getSemaphore(c, ctx, name, weight, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if semaphore.Spec.Drain {
+		return nil, fmt.Errorf("failed to acquire semaphore %s: %w", name, konductor.ErrDraining)
+	}
+
+	if err := checkCapacity(semaphore, weight); err != nil {
+		return nil, err
+	}
+
+	// Wait for permits and the grant until whichever of the ctx deadline and
+	// WithTimeout comes first
+	waitCtx, cancel, shouldWait := acquireContext(ctx, options.Timeout)
+	defer cancel()
+
+	// Fair semaphores and prioritised requests are queued by the controller,
+	// so the permit is created straight away to take its place in line
+	queued := semaphore.Spec.Fair || options.Priority > 0
+
+	// denied is set once the controller turned a permit down, after which
+	// availability is checked afresh before asking again
+	denied := false
+	for {
+		// Check if permits are available (for production)
+		if (semaphore.Status.Available < weight || denied) && shouldWait && !queued {
+			config := &konductor.WaitConfig{
+				InitialDelay: 1 * time.Second,
+				MaxDelay:     5 * time.Second,
+				Factor:       1.5,
+				Jitter:       0.1,
+				Timeout:      remaining(waitCtx),
+			}
+
+			// Wait for available permits
+			err := c.WaitForCondition(waitCtx, semaphore, func(obj client.Object) bool {
+				s := obj.(*syncv1.Semaphore)
+				return s.Status.Available >= weight
+			}, config)
+
+			if err != nil {
+				return nil, acquireError(ctx, name, err)
+			}
+		}
+
+		permit := newPermit(c, semaphore, holder, podOwner, options)
+		if err := c.K8sClient().Create(ctx, permit); err != nil {
+			return nil, fmt.Errorf("failed to create permit: %w", err)
+		}
+
+		// The controller may deny a permit that raced others for the last
+		// slots, so the decision is always awaited before reporting it held
+		config := &konductor.WaitConfig{
+			InitialDelay: 100 * time.Millisecond,
+			MaxDelay:     1 * time.Second,
+			Timeout:      remaining(waitCtx),
+		}
+
+		var err error
+		if queued || !shouldWait {
+			// Without a deadline the decision is awaited until ctx is cancelled
+			err = c.WatchForCondition(waitCtx, permit, permitDecided, config)
 		} else {
-			holder = fmt.Sprintf("sdk-%d", time.Now().Unix())
+			err = c.WaitForCondition(waitCtx, permit, permitDecided, config)
 		}
+
+		if err != nil {
+			err = acquireError(ctx, name, err)
+			// ctx may already be done, so clean up with a detached context
+			cleanupCtx, cancel := konductor.CleanupContext(ctx)
+			defer cancel()
+			if deleteErr := c.K8sClient().Delete(cleanupCtx, permit); deleteErr != nil {
+				return nil, fmt.Errorf("failed to wait for permit grant and failed to cleanup permit: %w (cleanup error: %v)", err, deleteErr)
+			}
+			return nil, err
+		}
+
+		if permit.Status.Phase == syncv1.PermitPhaseGranted {
+			return konductor.NewPermitWithID(c, name, holder, permit.Name, ctx), nil
+		}
+
+		// Other acquirers took the last permits between the availability
+		// check and the create, so the controller turned this one down
+		if err := c.K8sClient().Delete(ctx, permit); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to withdraw denied permit %s: %w", permit.Name, err)
+		}
+		if !shouldWait {
+			return nil, fmt.Errorf("failed to acquire semaphore %s: %w", name, konductor.ErrNoPermits)
+		}
+		denied = true
 	}
+}
 
-	var semaphore syncv1.Semaphore
-	if err := c.K8sClient().Get(ctx, types.NamespacedName{
-		Name: name, Namespace: c.Namespace(),
-	}, &semaphore); err != nil {
-		return nil, fmt.Errorf("failed to get semaphore %s: %w", name, err)
+// permitDecided reports whether the controller has granted or denied a
+// permit
+func permitDecided(obj client.Object) bool {
+	phase := obj.(*syncv1.Permit).Status.Phase
+	return phase == syncv1.PermitPhaseGranted || phase == syncv1.PermitPhaseDenied
+}
+
+// tryAcquireDecision bounds how long TryAcquire waits for the controller to
+// grant or deny the permit it created
+const tryAcquireDecision = 5 * time.Second
+
+// TryAcquire requests a permit without waiting for one to free up. It fails
+// with ErrNoPermits if the semaphore has fewer permits available than the
+// permit's weight, or if other requests are already queued for a fair or
+// prioritised semaphore. Otherwise the permit is created and the controller's
+// decision awaited briefly; a permit that is denied, or not decided in time,
+// is deleted again and TryAcquire fails.
+func TryAcquire(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) (*konductor.Permit, error) {
+	options := &konductor.Options{TTL: 10 * time.Minute}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	weight, err := permitWeight(options)
+	if err != nil {
+		return nil, err
+	}
+
+	holder := resolveHolder(options.Holder)
+	podOwner, err := permitPodOwner(c, options)
+	if err != nil {
+		return nil, err
+	}
+
+	semaphore, err := getSemaphore(c, ctx, name, weight, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if semaphore.Spec.Drain {
+		return nil, fmt.Errorf("failed to acquire semaphore %s: %w", name, konductor.ErrDraining)
+	}
+
+	if err := checkCapacity(semaphore, weight); err != nil {
+		return nil, err
+	}
+
+	if semaphore.Status.Available < weight || semaphore.Status.Pending > 0 {
+		return nil, fmt.Errorf("failed to acquire semaphore %s: %w", name, konductor.ErrNoPermits)
+	}
+
+	permit := newPermit(c, semaphore, holder, podOwner, options)
+	if err := c.K8sClient().Create(ctx, permit); err != nil {
+		return nil, fmt.Errorf("failed to create permit: %w", err)
+	}
+
+	decideCtx, cancel := context.WithTimeout(ctx, tryAcquireDecision)
+	defer cancel()
+	err = c.WatchForCondition(decideCtx, permit, permitDecided, &konductor.WaitConfig{
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     1 * time.Second,
+	})
+	if err == nil && permit.Status.Phase == syncv1.PermitPhaseGranted {
+		return konductor.NewPermitWithID(c, name, holder, permit.Name, ctx), nil
+	}
+
+	if err != nil {
+		err = acquireError(ctx, name, err)
+	} else {
+		err = fmt.Errorf("failed to acquire semaphore %s: %w", name, konductor.ErrNoPermits)
+	}
+	// ctx may already be done, so clean up with a detached context
+	cleanupCtx, cleanupCancel := konductor.CleanupContext(ctx)
+	defer cleanupCancel()
+	if deleteErr := c.K8sClient().Delete(cleanupCtx, permit); client.IgnoreNotFound(deleteErr) != nil {
+		return nil, fmt.Errorf("%w (cleanup of permit %s failed: %v)", err, permit.Name, deleteErr)
+	}
+	return nil, err
+}
+
+// QuorumPermit is a permit on the same semaphore in a majority of clusters,
+// acquired by AcquireQuorum
+type QuorumPermit struct {
+	name    string
+	permits []*konductor.Permit
+}
+
+// Release releases the permit in every cluster it was acquired in, carrying
+// on past failures and reporting all of them.
+func (p *QuorumPermit) Release(ctx context.Context) error {
+	var errs []error
+	for _, permit := range p.permits {
+		if err := permit.Release(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to release semaphore %s in %d of %d clusters: %w", p.name, len(errs), len(p.permits), err)
+	}
+	return nil
+}
+
+// Name returns the semaphore name.
+func (p *QuorumPermit) Name() string {
+	return p.name
+}
+
+// Permits returns the permit acquired in each cluster that granted one.
+func (p *QuorumPermit) Permits() []*konductor.Permit {
+	return p.permits
+}
+
+// AcquireQuorum acquires a permit on the named semaphore in every cluster of
+// m at once, with the same options as Acquire, and succeeds if a majority of
+// clusters granted one. Otherwise the permits that were granted are released
+// and it fails with ErrNoQuorum. Every cluster is waited for, so pass
+// WithTimeout to bound how long an unreachable one can hold things up.
+func AcquireQuorum(m *konductor.MultiClient, ctx context.Context, name string, opts ...konductor.Option) (*QuorumPermit, error) {
+	permits, err := konductor.AcquireMajority(m, ctx, func(c *konductor.Client) (*konductor.Permit, error) {
+		return Acquire(c, ctx, name, opts...)
+	}, func(ctx context.Context, permit *konductor.Permit) error {
+		return permit.Release(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire semaphore %s on a quorum of clusters: %w", name, err)
+	}
+	return &QuorumPermit{name: name, permits: permits}, nil
+}
+
+// Permits is a set of permits on one semaphore acquired together by
+// AcquireN. Release frees all of them.
+type Permits struct {
+	client  *konductor.Client
+	name    string
+	holder  string
+	permits []*syncv1.Permit
+}
+
+// Release deletes every permit in the set. Permits that are already gone,
+// for example because their TTL expired, are skipped.
+func (p *Permits) Release(ctx context.Context) error {
+	if err := deletePermits(ctx, p.client, p.permits); err != nil {
+		return fmt.Errorf("failed to release %d permits on semaphore %s for holder %s: %w", len(p.permits), p.name, p.holder, err)
+	}
+	return nil
+}
+
+// Holder returns the permit holder identifier.
+func (p *Permits) Holder() string {
+	return p.holder
+}
+
+// Name returns the semaphore name.
+func (p *Permits) Name() string {
+	return p.name
+}
+
+// Count returns the number of permits in the set.
+func (p *Permits) Count() int {
+	return len(p.permits)
+}
+
+// AcquireN reserves n permits on the named semaphore for a single holder. It
+// waits, bounded by WithTimeout or the ctx deadline, until n permits are
+// available and fails with ErrNoPermits straight away when there is nothing
+// to wait on. Acquisition is all or nothing: if any permit cannot be created
+// or granted, those already created are deleted before returning. With
+// WithAutoCreate a missing semaphore is created with n permits, where Acquire
+// and TryAcquire create it with one. With WithWeight each of the n permits
+// counts for that weight, so n times as many are needed.
+func AcquireN(c *konductor.Client, ctx context.Context, name string, n int32, opts ...konductor.Option) (*Permits, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("permit count must be positive, got %d", n)
+	}
+
+	options := &konductor.Options{TTL: 10 * time.Minute, Timeout: 0}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	weight, err := permitWeight(options)
+	if err != nil {
+		return nil, err
 	}
+	need := n * weight
+
+	holder := resolveHolder(options.Holder)
+	podOwner, err := permitPodOwner(c, options)
+	if err != nil {
+		return nil, err
+	}
+
+	semaphore, err := getSemaphore(c, ctx, name, need, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if semaphore.Spec.Drain {
+		return nil, fmt.Errorf("failed to acquire semaphore %s: %w", name, konductor.ErrDraining)
+	}
+
+	if err := checkCapacity(semaphore, need); err != nil {
+		return nil, err
+	}
+
+	waitCtx, cancel, shouldWait := acquireContext(ctx, options.Timeout)
+	defer cancel()
+
+	queued := semaphore.Spec.Fair || options.Priority > 0
+
+	if semaphore.Status.Available < need && !queued {
+		if !shouldWait {
+			return nil, fmt.Errorf("failed to acquire %d permits on semaphore %s: %w", need, name, konductor.ErrNoPermits)
+		}
 
-	// Check if permits are available (for production)
-	if semaphore.Status.Available <= 0 && options.Timeout > 0 {
 		config := &konductor.WaitConfig{
 			InitialDelay: 1 * time.Second,
 			MaxDelay:     5 * time.Second,
 			Factor:       1.5,
 			Jitter:       0.1,
-			Timeout:      options.Timeout,
+			Timeout:      remaining(waitCtx),
 		}
 
-		// Wait for available permits
-		err := c.WaitForCondition(ctx, &semaphore, func(obj client.Object) bool {
-			s := obj.(*syncv1.Semaphore)
-			return s.Status.Available > 0
+		err := c.WaitForCondition(waitCtx, semaphore, func(obj client.Object) bool {
+			return obj.(*syncv1.Semaphore).Status.Available >= need
 		}, config)
 
 		if err != nil {
-			return nil, fmt.Errorf("timeout waiting for semaphore %s: %w", name, err)
+			return nil, acquireError(ctx, name, err)
+		}
+	}
+
+	permits := make([]*syncv1.Permit, 0, n)
+	// rollback deletes the permits created so far, with a detached context
+	// since ctx may already be done
+	rollback := func(err error) error {
+		cleanupCtx, cancel := konductor.CleanupContext(ctx)
+		defer cancel()
+		if deleteErr := deletePermits(cleanupCtx, c, permits); deleteErr != nil {
+			return fmt.Errorf("%w (cleanup of %d permits failed: %v)", err, len(permits), deleteErr)
+		}
+		return err
+	}
+
+	for i := int32(0); i < n; i++ {
+		permit := newPermit(c, semaphore, holder, podOwner, options)
+		permit.Name = fmt.Sprintf("%s-%d", permit.Name, i)
+		if err := c.K8sClient().Create(ctx, permit); err != nil {
+			return nil, rollback(fmt.Errorf("failed to create permit: %w", err))
+		}
+		permits = append(permits, permit)
+	}
+
+	// As with Acquire, the grants are always awaited
+	for _, permit := range permits {
+		config := &konductor.WaitConfig{
+			InitialDelay: 100 * time.Millisecond,
+			MaxDelay:     1 * time.Second,
+			Timeout:      remaining(waitCtx),
+		}
+
+		var err error
+		if queued || !shouldWait {
+			err = c.WatchForCondition(waitCtx, permit, permitDecided, config)
+		} else {
+			err = c.WaitForCondition(waitCtx, permit, permitDecided, config)
+		}
+		if err != nil {
+			return nil, rollback(acquireError(ctx, name, err))
+		}
+		if permit.Status.Phase == syncv1.PermitPhaseDenied {
+			return nil, rollback(fmt.Errorf("failed to acquire %d permits on semaphore %s: %w", need, name, konductor.ErrNoPermits))
+		}
+	}
+
+	return &Permits{client: c, name: name, holder: holder, permits: permits}, nil
+}
+
+// getSemaphore fetches the named semaphore. With WithAutoCreate a missing
+// semaphore is created first with the given number of permits.
+func getSemaphore(c *konductor.Client, ctx context.Context, name string, permits int32, options *konductor.Options) (*syncv1.Semaphore, error) {
+	semaphore := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.Namespace()},
+		Spec:       syncv1.SemaphoreSpec{Permits: permits},
+	}
+	if err := c.GetOrCreate(ctx, "semaphore", semaphore, options.AutoCreate); err != nil {
+		return nil, err
+	}
+	return semaphore, nil
+}
+
+// permitWeight returns the weight WithWeight set, or 1 when it is unset
+func permitWeight(options *konductor.Options) (int32, error) {
+	if options.Weight < 0 {
+		return 0, fmt.Errorf("permit weight must be positive, got %d", options.Weight)
+	}
+	if options.Weight == 0 {
+		return 1, nil
+	}
+	return options.Weight, nil
+}
+
+// checkCapacity fails when semaphore has fewer permits in all than weight, so
+// it could never grant them
+func checkCapacity(semaphore *syncv1.Semaphore, weight int32) error {
+	if weight > semaphore.Spec.Permits {
+		return fmt.Errorf("failed to acquire %d permits on semaphore %s, which only has %d", weight, semaphore.Name, semaphore.Spec.Permits)
+	}
+	return nil
+}
+
+// deletePermits deletes permits, ignoring any that no longer exist. It tries
+// every permit and returns the first error.
+func deletePermits(ctx context.Context, c *konductor.Client, permits []*syncv1.Permit) error {
+	var firstErr error
+	for _, permit := range permits {
+		if err := c.K8sClient().Delete(ctx, permit); client.IgnoreNotFound(err) != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to delete permit %s: %w", permit.Name, err)
 		}
 	}
+	return firstErr
+}
+
+// resolveHolder defaults an empty holder to konductor.DefaultHolder
+func resolveHolder(holder string) string {
+	if holder != "" {
+		return holder
+	}
+	return konductor.DefaultHolder()
+}
+
+// permitPodOwner returns an owner reference to the pod the process runs in
+// when WithOwnerPod is set, or nil otherwise. The pod comes from the POD_NAME
+// and POD_UID variables and has to be in the client's namespace, since an
+// owner cannot live in another one.
+func permitPodOwner(c *konductor.Client, options *konductor.Options) (*metav1.OwnerReference, error) {
+	if !options.OwnerPod {
+		return nil, nil
+	}
 
-	permitID := fmt.Sprintf("%s-%s-%d", name, holder, time.Now().UnixNano())
+	podName, podUID := os.Getenv("POD_NAME"), os.Getenv("POD_UID")
+	if podName == "" || podUID == "" {
+		return nil, fmt.Errorf("owning permits by pod requires the POD_NAME and POD_UID environment variables")
+	}
+	if podNamespace := os.Getenv("POD_NAMESPACE"); podNamespace != "" && podNamespace != c.Namespace() {
+		return nil, fmt.Errorf("pod %s in namespace %s cannot own permits in namespace %s", podName, podNamespace, c.Namespace())
+	}
+
+	return &metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       podName,
+		UID:        types.UID(podUID),
+	}, nil
+}
+
+// newPermit builds a Permit for holder owned by semaphore, or by podOwner
+// when it is set
+func newPermit(c *konductor.Client, semaphore *syncv1.Semaphore, holder string, podOwner *metav1.OwnerReference, options *konductor.Options) *syncv1.Permit {
+	permitID := konductor.HolderObjectName(semaphore.Name, fmt.Sprintf("%s-%d", holder, time.Now().UnixNano()))
 
-	// Create permit
 	ctrlTrue := true
+	owners := []metav1.OwnerReference{{
+		APIVersion:         "sync.konductor.io/v1",
+		Kind:               "Semaphore",
+		Name:               semaphore.Name,
+		UID:                semaphore.UID,
+		Controller:         &ctrlTrue,
+		BlockOwnerDeletion: &ctrlTrue,
+	}}
+	if podOwner != nil {
+		// A dependent is only garbage-collected once every owner is gone, so
+		// the pod replaces the semaphore rather than joining it
+		owners = []metav1.OwnerReference{*podOwner}
+	}
+
 	permit := &syncv1.Permit{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      permitID,
-			Namespace: c.Namespace(),
-			Labels:    map[string]string{"semaphore": name},
-			OwnerReferences: []metav1.OwnerReference{{
-				APIVersion:         "sync.konductor.io/v1",
-				Kind:               "Semaphore",
-				Name:               semaphore.Name,
-				UID:                semaphore.UID,
-				Controller:         &ctrlTrue,
-				BlockOwnerDeletion: &ctrlTrue,
-			}},
+			Name:            permitID,
+			Namespace:       c.Namespace(),
+			Labels:          permitLabels(semaphore.Name, holder),
+			OwnerReferences: owners,
 		},
 		Spec: syncv1.PermitSpec{
-			Semaphore: name,
+			Semaphore: semaphore.Name,
 			Holder:    holder,
 		},
 	}
@@ -85,32 +522,58 @@ func Acquire(c *konductor.Client, ctx context.Context, name string, opts ...kond
 		permit.Spec.TTL = &metav1.Duration{Duration: options.TTL}
 	}
 
-	if err := c.K8sClient().Create(ctx, permit); err != nil {
-		return nil, fmt.Errorf("failed to create permit: %w", err)
+	if options.Priority > 0 {
+		permit.Spec.Priority = &options.Priority
 	}
 
-	// Only wait for permit grant confirmation if timeout is specified (production)
-	if options.Timeout > 0 {
-		config := &konductor.WaitConfig{
-			InitialDelay: 100 * time.Millisecond,
-			MaxDelay:     1 * time.Second,
-			Timeout:      options.Timeout,
-		}
+	if options.Weight > 0 {
+		permit.Spec.Weight = options.Weight
+	}
 
-		err := c.WaitForCondition(ctx, permit, func(obj client.Object) bool {
-			p := obj.(*syncv1.Permit)
-			return p.Status.Phase == syncv1.PermitPhaseGranted
-		}, config)
+	return permit
+}
 
-		if err != nil {
-			if deleteErr := c.K8sClient().Delete(ctx, permit); deleteErr != nil {
-				return nil, fmt.Errorf("failed to wait for permit grant and failed to cleanup permit: %w (cleanup error: %v)", err, deleteErr)
-			}
-			return nil, err
-		}
+// permitLabels labels a permit with its semaphore and, when holder is a valid
+// label value, its holder so ReleaseAll can find every permit it holds
+func permitLabels(semaphore, holder string) map[string]string {
+	labels := map[string]string{"semaphore": semaphore}
+	if len(validation.IsValidLabelValue(holder)) == 0 {
+		labels["holder"] = holder
+	}
+	return labels
+}
+
+// acquireContext bounds ctx by timeout when one is given. It reports whether
+// Acquire should wait at all, which is the case when either ctx carries a
+// deadline or timeout is positive.
+func acquireContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, bool) {
+	_, hasDeadline := ctx.Deadline()
+	if timeout > 0 {
+		waitCtx, cancel := context.WithTimeout(ctx, timeout)
+		return waitCtx, cancel, true
+	}
+	return ctx, func() {}, hasDeadline
+}
+
+// remaining returns the time left before ctx's deadline
+func remaining(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
 	}
+	return time.Until(deadline)
+}
 
-	return konductor.NewPermit(c, name, holder, ctx), nil
+// acquireError reports expiry or cancellation of the caller's ctx as-is and an
+// exhausted WithTimeout as ErrAcquireTimeout.
+func acquireError(ctx context.Context, name string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("failed to acquire semaphore %s: %w", name, ctx.Err())
+	}
+	if wait.Interrupted(err) {
+		return fmt.Errorf("failed to acquire semaphore %s: %w", name, konductor.ErrAcquireTimeout)
+	}
+	return fmt.Errorf("failed to acquire semaphore %s: %w", name, err)
 }
 
 func With(c *konductor.Client, ctx context.Context, name string, fn func() error, opts ...konductor.Option) error {
@@ -123,9 +586,72 @@ func With(c *konductor.Client, ctx context.Context, name string, fn func() error
 	return fn()
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.Semaphore, error) {
+// ReleaseAll deletes every permit holder has on the named semaphore, such as
+// those left behind by repeated acquisitions that were never released.
+func ReleaseAll(c *konductor.Client, ctx context.Context, name, holder string) error {
+	var opts []konductor.Option
+	if len(validation.IsValidLabelValue(holder)) == 0 {
+		opts = append(opts, konductor.WithLabelSelector(map[string]string{"holder": holder}))
+	}
+
+	permits, err := c.ListPermits(ctx, name, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to release permits on semaphore %s: %w", name, err)
+	}
+
+	for i := range permits {
+		// Holders that cannot be a label value are only recorded in the spec
+		if permits[i].Spec.Holder != holder {
+			continue
+		}
+		if err := c.K8sClient().Delete(ctx, &permits[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete permit %s: %w", permits[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// SetDrain turns drain mode on or off for the named semaphore. While draining,
+// current holders keep their permits but no new ones are granted, and Acquire
+// and TryAcquire fail with ErrDraining.
+func SetDrain(c *konductor.Client, ctx context.Context, name string, drain bool) error {
+	semaphore := &syncv1.Semaphore{}
+	semaphore.Name = name
+	semaphore.Namespace = c.Namespace()
+
+	if err := c.UpdateWithRetry(ctx, semaphore, func(obj client.Object) error {
+		obj.(*syncv1.Semaphore).Spec.Drain = drain
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to set drain on semaphore %s: %w", name, err)
+	}
+	return nil
+}
+
+// SetPermits changes how many permits the semaphore has, retrying if it
+// changes underneath. Shrinking it below the permits in use revokes none; the
+// controller just grants no more until enough are released.
+func SetPermits(c *konductor.Client, ctx context.Context, name string, permits int32) error {
+	if permits <= 0 {
+		return fmt.Errorf("failed to set permits on semaphore %s: permits must be greater than zero, got %d", name, permits)
+	}
+
+	semaphore := &syncv1.Semaphore{}
+	semaphore.Name = name
+	semaphore.Namespace = c.Namespace()
+
+	if err := c.UpdateWithRetry(ctx, semaphore, func(obj client.Object) error {
+		obj.(*syncv1.Semaphore).Spec.Permits = permits
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to set permits on semaphore %s: %w", name, err)
+	}
+	return nil
+}
+
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.Semaphore, error) {
 	var semaphores syncv1.SemaphoreList
-	if err := c.K8sClient().List(ctx, &semaphores, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &semaphores, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list semaphores: %w", err)
 	}
 	return semaphores.Items, nil
@@ -187,8 +713,10 @@ func Delete(c *konductor.Client, ctx context.Context, name string) error {
 	return nil
 }
 
-func Update(c *konductor.Client, ctx context.Context, semaphore *syncv1.Semaphore) error {
-	if err := c.K8sClient().Update(ctx, semaphore); err != nil {
+// Update writes the semaphore's spec and metadata. WithServerSideApply sends
+// only the fields semaphore sets, such as a new permit count.
+func Update(c *konductor.Client, ctx context.Context, semaphore *syncv1.Semaphore, opts ...konductor.Option) error {
+	if err := c.Update(ctx, semaphore, opts...); err != nil {
 		return fmt.Errorf("failed to update semaphore %s: %w", semaphore.Name, err)
 	}
 	return nil
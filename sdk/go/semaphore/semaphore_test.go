@@ -2,7 +2,11 @@ package semaphore
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,7 +14,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
@@ -53,6 +59,61 @@ func TestList(t *testing.T) {
 	assert.Equal(t, "sem1", semaphores[0].Name)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-semaphore",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-semaphore",
+			Namespace: "test-ns",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupSemaphoreTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-semaphore", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-semaphore",
+			Namespace: "test-ns",
+		},
+	}
+	remote := &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-semaphore",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupSemaphoreTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "test-ns", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"test-ns", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestGet(t *testing.T) {
 	semaphore := &syncv1.Semaphore{
 		ObjectMeta: metav1.ObjectMeta{
@@ -108,4 +169,831 @@ func TestUpdate(t *testing.T) {
 	semaphore.Spec.Permits = 10
 	err := Update(client, context.Background(), semaphore)
 	assert.NoError(t, err)
+
+	updated, err := Get(client, context.Background(), "test-sem")
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), updated.Spec.Permits)
+}
+
+func exhaustedSemaphore() *syncv1.Semaphore {
+	return &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: 1,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     1,
+			Available: 0,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+}
+
+func TestAcquire_ContextDeadlineSoonerThanTimeout(t *testing.T) {
+	client := setupSemaphoreTestClient(t, exhaustedSemaphore())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Acquire(client, ctx, "test-sem", konductor.WithTimeout(10*time.Second))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, errors.Is(err, konductor.ErrAcquireTimeout))
+	// Returns at the sooner deadline rather than after the first poll or the later one
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+func TestAcquire_TimeoutSoonerThanContextDeadline(t *testing.T) {
+	client := setupSemaphoreTestClient(t, exhaustedSemaphore())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Acquire(client, ctx, "test-sem", konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrAcquireTimeout))
+	assert.False(t, errors.Is(err, context.DeadlineExceeded))
+	// Returns at the sooner deadline rather than after the first poll or the later one
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+func TestAcquire_GrantTimeoutCleansUpPermit(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupSemaphoreTestClient(t, semaphore)
+
+	// No controller runs in the test, so the permit is never granted
+	_, err := Acquire(client, context.Background(), "test-sem", konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrAcquireTimeout))
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+// grantFirstPermit stands in for the controller, granting the first permit
+// to be created
+func grantFirstPermit(client *konductor.Client) {
+	go func() {
+		for {
+			var permits syncv1.PermitList
+			if err := client.K8sClient().List(context.Background(), &permits); err == nil && len(permits.Items) == 1 {
+				permit := permits.Items[0]
+				permit.Status.Phase = syncv1.PermitPhaseGranted
+				_ = client.K8sClient().Update(context.Background(), &permit)
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+}
+
+func TestAcquire_FairWaitsForGrant(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Spec.Fair = true
+	client := setupSemaphoreTestClient(t, semaphore)
+	grantFirstPermit(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Even with no permits available, a fair acquire queues its permit
+	// rather than waiting for one to free up first
+	permit, err := Acquire(client, ctx, "test-sem", konductor.WithHolder("queued"))
+	require.NoError(t, err)
+	assert.Equal(t, "queued", permit.Holder())
+}
+
+func TestAcquire_FairTimeoutCleansUpPermit(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Spec.Fair = true
+	client := setupSemaphoreTestClient(t, semaphore)
+
+	_, err := Acquire(client, context.Background(), "test-sem", konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrAcquireTimeout))
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestAcquire_WithPriorityQueuesPermit(t *testing.T) {
+	client := setupSemaphoreTestClient(t, exhaustedSemaphore())
+	grantFirstPermit(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := Acquire(client, ctx, "test-sem", konductor.WithPriority(7))
+	require.NoError(t, err)
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	require.NotNil(t, permits.Items[0].Spec.Priority)
+	assert.Equal(t, int32(7), *permits.Items[0].Spec.Priority)
+}
+
+// setupAdmittingTestClient returns a client whose permits are granted when
+// created while the semaphore has room for them and denied otherwise,
+// standing in for the controller. The semaphore's status is left as it was,
+// so acquirers keep seeing the permits they race for as free.
+func setupAdmittingTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	var mu sync.Mutex
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+				permit, ok := obj.(*syncv1.Permit)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+
+				var semaphore syncv1.Semaphore
+				if err := c.Get(ctx, ctrlclient.ObjectKey{Name: permit.Spec.Semaphore, Namespace: permit.Namespace}, &semaphore); err != nil {
+					return err
+				}
+				var permits syncv1.PermitList
+				if err := c.List(ctx, &permits); err != nil {
+					return err
+				}
+				// Permits of the default weight leave it unset
+				weight := func(p *syncv1.Permit) int32 { return max(p.Spec.Weight, 1) }
+				inUse := int32(0)
+				for i := range permits.Items {
+					if permits.Items[i].Status.Phase == syncv1.PermitPhaseGranted {
+						inUse += weight(&permits.Items[i])
+					}
+				}
+
+				permit.Status.Phase = syncv1.PermitPhaseDenied
+				if inUse+weight(permit) <= semaphore.Spec.Permits {
+					permit.Status.Phase = syncv1.PermitPhaseGranted
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+func TestAcquire_RejectsOversubscription(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupAdmittingTestClient(t, semaphore)
+
+	// Every acquirer sees the last permit free and creates one
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = Acquire(client, context.Background(), "test-sem",
+				konductor.WithHolder(fmt.Sprintf("worker-%d", i)))
+		}()
+	}
+	wg.Wait()
+
+	acquired := 0
+	for _, err := range errs {
+		if err == nil {
+			acquired++
+			continue
+		}
+		assert.True(t, errors.Is(err, konductor.ErrNoPermits), err)
+	}
+	assert.Equal(t, 1, acquired, "only one acquirer fits in the semaphore")
+
+	// The denied permits are withdrawn
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, syncv1.PermitPhaseGranted, permits.Items[0].Status.Phase)
+}
+
+func TestAcquire_DeniedRetriesUntilPermitFrees(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupAdmittingTestClient(t, semaphore)
+
+	held, err := Acquire(client, context.Background(), "test-sem", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		_ = held.Release(context.Background())
+	}()
+
+	// With a deadline, a denied acquire asks again rather than failing
+	permit, err := Acquire(client, context.Background(), "test-sem",
+		konductor.WithHolder("worker-2"), konductor.WithTimeout(10*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, "worker-2", permit.Holder())
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "worker-2", permits.Items[0].Spec.Holder)
+	assert.Equal(t, syncv1.PermitPhaseGranted, permits.Items[0].Status.Phase)
+}
+
+func TestAcquireN_DeniedRollsBack(t *testing.T) {
+	client := setupAdmittingTestClient(t, multiSemaphore(2, 2))
+
+	held, err := Acquire(client, context.Background(), "test-sem", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+
+	// The status still shows both permits free, but only one fits
+	_, err = AcquireN(client, context.Background(), "test-sem", 2,
+		konductor.WithHolder("worker-2"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNoPermits))
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "worker-1", permits.Items[0].Spec.Holder)
+	require.NoError(t, held.Release(context.Background()))
+}
+
+func TestTryAcquire_Available(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupAdmittingTestClient(t, semaphore)
+
+	start := time.Now()
+	permit, err := TryAcquire(client, context.Background(), "test-sem", konductor.WithHolder("test-holder"))
+	require.NoError(t, err)
+	assert.Equal(t, "test-holder", permit.Holder())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "test-holder", permits.Items[0].Spec.Holder)
+	assert.Equal(t, "test-sem", permits.Items[0].Labels["semaphore"])
+	assert.Equal(t, "test-holder", permits.Items[0].Labels["holder"])
+}
+
+func TestTryAcquire_DeniedDeletesPermit(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupAdmittingTestClient(t, semaphore)
+
+	held, err := TryAcquire(client, context.Background(), "test-sem", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+
+	// The status still shows the permit free, but the controller denies it
+	_, err = TryAcquire(client, context.Background(), "test-sem", konductor.WithHolder("worker-2"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNoPermits))
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "worker-1", permits.Items[0].Spec.Holder)
+	require.NoError(t, held.Release(context.Background()))
+}
+
+func TestTryAcquire_UndecidedDeletesPermit(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	// There is no controller to decide the permit
+	client := setupSemaphoreTestClient(t, semaphore)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err := TryAcquire(client, ctx, "test-sem", konductor.WithHolder("test-holder"))
+	require.Error(t, err)
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestAcquire_WithOwnerPod(t *testing.T) {
+	t.Setenv("POD_NAME", "worker-7f9c")
+	t.Setenv("POD_UID", "3f1c2a9e-0d4b-4c1e-9a77-5b2f8e6d1c40")
+	t.Setenv("POD_NAMESPACE", "test-ns")
+
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupAdmittingTestClient(t, semaphore)
+
+	_, err := Acquire(client, context.Background(), "test-sem",
+		konductor.WithHolder("test-holder"), konductor.WithOwnerPod())
+	require.NoError(t, err)
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	require.Len(t, permits.Items, 1)
+
+	// The pod is the only owner, so deleting it garbage-collects the permit
+	assert.Equal(t, []metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       "worker-7f9c",
+		UID:        "3f1c2a9e-0d4b-4c1e-9a77-5b2f8e6d1c40",
+	}}, permits.Items[0].OwnerReferences)
+	assert.Equal(t, "test-sem", permits.Items[0].Spec.Semaphore)
+}
+
+func TestAcquire_WithOwnerPodRequiresPodIdentity(t *testing.T) {
+	t.Setenv("POD_NAME", "worker-7f9c")
+	t.Setenv("POD_UID", "")
+
+	semaphore := exhaustedSemaphore()
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupSemaphoreTestClient(t, semaphore)
+
+	_, err := Acquire(client, context.Background(), "test-sem", konductor.WithOwnerPod())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "POD_UID")
+
+	t.Setenv("POD_UID", "3f1c2a9e-0d4b-4c1e-9a77-5b2f8e6d1c40")
+	t.Setenv("POD_NAMESPACE", "other-ns")
+	_, err = Acquire(client, context.Background(), "test-sem", konductor.WithOwnerPod())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace other-ns")
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestTryAcquire_NoPermits(t *testing.T) {
+	client := setupSemaphoreTestClient(t, exhaustedSemaphore())
+
+	start := time.Now()
+	_, err := TryAcquire(client, context.Background(), "test-sem", konductor.WithHolder("test-holder"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNoPermits))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestTryAcquire_QueuedRequestsAhead(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Spec.Fair = true
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	semaphore.Status.Pending = 2
+	client := setupSemaphoreTestClient(t, semaphore)
+
+	_, err := TryAcquire(client, context.Background(), "test-sem", konductor.WithHolder("test-holder"))
+	assert.True(t, errors.Is(err, konductor.ErrNoPermits))
+}
+
+func TestReleaseAll(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Spec.Permits = 4
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 4
+	client := setupAdmittingTestClient(t, semaphore)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := TryAcquire(client, ctx, "test-sem", konductor.WithHolder("worker-1"))
+		require.NoError(t, err)
+	}
+	_, err := TryAcquire(client, ctx, "test-sem", konductor.WithHolder("worker-2"))
+	require.NoError(t, err)
+
+	require.NoError(t, ReleaseAll(client, ctx, "test-sem", "worker-1"))
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(ctx, &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "worker-2", permits.Items[0].Spec.Holder)
+}
+
+func TestReleaseAll_HolderNotValidLabelValue(t *testing.T) {
+	holder := "worker 1 on node/a"
+	permits := []runtime.Object{}
+	for _, name := range []string{"test-sem-a", "test-sem-b"} {
+		permits = append(permits, &syncv1.Permit{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-ns",
+				Labels:    permitLabels("test-sem", holder),
+			},
+			Spec: syncv1.PermitSpec{Semaphore: "test-sem", Holder: holder},
+		})
+	}
+	client := setupSemaphoreTestClient(t, permits...)
+	ctx := context.Background()
+
+	require.NoError(t, ReleaseAll(client, ctx, "test-sem", holder))
+
+	var remaining syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(ctx, &remaining))
+	assert.Empty(t, remaining.Items)
+}
+
+func TestAcquire_Draining(t *testing.T) {
+	semaphore := exhaustedSemaphore()
+	semaphore.Spec.Drain = true
+	semaphore.Status.InUse = 0
+	semaphore.Status.Available = 1
+	client := setupSemaphoreTestClient(t, semaphore)
+
+	start := time.Now()
+	_, err := Acquire(client, context.Background(), "test-sem", konductor.WithTimeout(5*time.Second))
+	assert.True(t, errors.Is(err, konductor.ErrDraining))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	_, err = TryAcquire(client, context.Background(), "test-sem")
+	assert.True(t, errors.Is(err, konductor.ErrDraining))
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestSetDrain(t *testing.T) {
+	client := setupSemaphoreTestClient(t, exhaustedSemaphore())
+
+	require.NoError(t, SetDrain(client, context.Background(), "test-sem", true))
+	result, err := Get(client, context.Background(), "test-sem")
+	require.NoError(t, err)
+	assert.True(t, result.Spec.Drain)
+
+	require.NoError(t, SetDrain(client, context.Background(), "test-sem", false))
+	result, err = Get(client, context.Background(), "test-sem")
+	require.NoError(t, err)
+	assert.False(t, result.Spec.Drain)
+}
+
+func TestSetPermits(t *testing.T) {
+	client := setupSemaphoreTestClient(t, exhaustedSemaphore())
+
+	require.NoError(t, SetPermits(client, context.Background(), "test-sem", 4))
+	result, err := Get(client, context.Background(), "test-sem")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), result.Spec.Permits)
+	assert.Equal(t, int32(1), result.Status.InUse, "resizing leaves the permits in use alone")
+
+	err = SetPermits(client, context.Background(), "test-sem", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permits must be greater than zero")
+
+	err = SetPermits(client, context.Background(), "missing", 2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to set permits on semaphore missing")
+}
+
+// multiSemaphore returns a semaphore with permits in total, available of them free
+func multiSemaphore(permits, available int32) *syncv1.Semaphore {
+	return &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sem",
+			Namespace: "test-ns",
+		},
+		Spec: syncv1.SemaphoreSpec{
+			Permits: permits,
+		},
+		Status: syncv1.SemaphoreStatus{
+			InUse:     permits - available,
+			Available: available,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+}
+
+func TestAcquireN(t *testing.T) {
+	client := setupAdmittingTestClient(t, multiSemaphore(4, 4))
+	ctx := context.Background()
+
+	permits, err := AcquireN(client, ctx, "test-sem", 3, konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, permits.Count())
+	assert.Equal(t, "worker-1", permits.Holder())
+	assert.Equal(t, "test-sem", permits.Name())
+
+	var list syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(ctx, &list))
+	require.Len(t, list.Items, 3)
+	for _, p := range list.Items {
+		assert.Equal(t, "worker-1", p.Spec.Holder)
+	}
+
+	require.NoError(t, permits.Release(ctx))
+	require.NoError(t, client.K8sClient().List(ctx, &list))
+	assert.Empty(t, list.Items)
+
+	// Releasing again skips the permits that are already gone
+	require.NoError(t, permits.Release(ctx))
+}
+
+func TestAcquireN_InsufficientPermits(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []konductor.Option
+		expected error
+	}{
+		{
+			name:     "without waiting",
+			expected: konductor.ErrNoPermits,
+		},
+		{
+			name:     "timeout",
+			opts:     []konductor.Option{konductor.WithTimeout(200 * time.Millisecond)},
+			expected: konductor.ErrAcquireTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := setupSemaphoreTestClient(t, multiSemaphore(3, 1))
+
+			_, err := AcquireN(client, context.Background(), "test-sem", 2, tt.opts...)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.expected))
+
+			var list syncv1.PermitList
+			require.NoError(t, client.K8sClient().List(context.Background(), &list))
+			assert.Empty(t, list.Items)
+		})
+	}
+}
+
+func TestAcquireN_InvalidCount(t *testing.T) {
+	client := setupSemaphoreTestClient(t, multiSemaphore(2, 2))
+
+	_, err := AcquireN(client, context.Background(), "test-sem", 0)
+	assert.ErrorContains(t, err, "must be positive")
+
+	_, err = AcquireN(client, context.Background(), "test-sem", 3)
+	assert.ErrorContains(t, err, "only has 2")
+}
+
+func TestAcquireN_GrantTimeoutLeavesNoPermits(t *testing.T) {
+	// There is no controller to grant the permits, so the grant wait times out
+	client := setupSemaphoreTestClient(t, multiSemaphore(3, 3))
+
+	_, err := AcquireN(client, context.Background(), "test-sem", 2, konductor.WithTimeout(300*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrAcquireTimeout))
+
+	var list syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &list))
+	assert.Empty(t, list.Items)
+}
+
+func TestAcquireN_CreateFailureRollsBack(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	created := 0
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(multiSemaphore(3, 3)).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+				if created++; created == 3 {
+					return errors.New("quota exceeded")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	client := konductor.NewFromClient(k8sClient, "test-ns")
+
+	_, err := AcquireN(client, context.Background(), "test-sem", 3)
+	assert.ErrorContains(t, err, "quota exceeded")
+
+	var list syncv1.PermitList
+	require.NoError(t, k8sClient.List(context.Background(), &list))
+	assert.Empty(t, list.Items)
+}
+
+// setupGrantingTestClient returns a client for one cluster whose permits are
+// granted as soon as they are created, and whose new semaphores start with all
+// their permits free, standing in for that cluster's controller
+func setupGrantingTestClient(t *testing.T, objects ...runtime.Object) *konductor.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+				switch o := obj.(type) {
+				case *syncv1.Permit:
+					o.Status.Phase = syncv1.PermitPhaseGranted
+				case *syncv1.Semaphore:
+					o.Status.Available = o.Spec.Permits
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	return konductor.NewFromClient(k8sClient, "test-ns")
+}
+
+// drainingSemaphore returns a semaphore with a free permit that is draining,
+// so acquiring it fails straight away
+func drainingSemaphore() *syncv1.Semaphore {
+	semaphore := multiSemaphore(1, 1)
+	semaphore.Spec.Drain = true
+	return semaphore
+}
+
+func TestAcquireQuorum(t *testing.T) {
+	clients := []*konductor.Client{
+		setupGrantingTestClient(t, multiSemaphore(1, 1)),
+		setupGrantingTestClient(t, drainingSemaphore()),
+		setupGrantingTestClient(t, multiSemaphore(1, 1)),
+	}
+	m, err := konductor.NewMultiClient(clients...)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	permit, err := AcquireQuorum(m, ctx, "test-sem", konductor.WithHolder("worker-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "test-sem", permit.Name())
+	require.Len(t, permit.Permits(), 2)
+	for _, p := range permit.Permits() {
+		assert.Equal(t, "worker-1", p.Holder())
+	}
+
+	for i, c := range clients {
+		var list syncv1.PermitList
+		require.NoError(t, c.K8sClient().List(ctx, &list))
+		if i == 1 {
+			assert.Empty(t, list.Items, "the draining cluster grants no permit")
+		} else {
+			assert.Len(t, list.Items, 1)
+		}
+	}
+
+	require.NoError(t, permit.Release(ctx))
+	for _, c := range clients {
+		var list syncv1.PermitList
+		require.NoError(t, c.K8sClient().List(ctx, &list))
+		assert.Empty(t, list.Items)
+	}
+}
+
+func TestAcquireQuorum_NoQuorumReleasesPermits(t *testing.T) {
+	clients := []*konductor.Client{
+		setupGrantingTestClient(t, drainingSemaphore()),
+		setupGrantingTestClient(t, multiSemaphore(1, 1)),
+		setupGrantingTestClient(t),
+	}
+	m, err := konductor.NewMultiClient(clients...)
+	require.NoError(t, err)
+
+	_, err = AcquireQuorum(m, context.Background(), "test-sem", konductor.WithHolder("worker-1"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNoQuorum))
+	assert.True(t, errors.Is(err, konductor.ErrDraining))
+	assert.Contains(t, err.Error(), "acquired on 1 of 3 clusters, need 2")
+
+	// The permit granted on the one healthy cluster is given back
+	for _, c := range clients {
+		var list syncv1.PermitList
+		require.NoError(t, c.K8sClient().List(context.Background(), &list))
+		assert.Empty(t, list.Items)
+	}
+}
+
+func TestAcquire_MissingSemaphore(t *testing.T) {
+	client := setupSemaphoreTestClient(t)
+
+	for name, acquire := range map[string]func() error{
+		"Acquire": func() error {
+			_, err := Acquire(client, context.Background(), "test-sem")
+			return err
+		},
+		"TryAcquire": func() error {
+			_, err := TryAcquire(client, context.Background(), "test-sem")
+			return err
+		},
+		"AcquireN": func() error {
+			_, err := AcquireN(client, context.Background(), "test-sem", 2)
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := acquire()
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, konductor.ErrNotFound))
+			assert.Contains(t, err.Error(), "semaphore test-sem not found in namespace test-ns")
+		})
+	}
+
+	var permits syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestAcquire_AutoCreate(t *testing.T) {
+	client := setupGrantingTestClient(t)
+
+	permit, err := Acquire(client, context.Background(), "test-sem", konductor.WithHolder("worker-1"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+	assert.Equal(t, "worker-1", permit.Holder())
+
+	semaphore, err := Get(client, context.Background(), "test-sem")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), semaphore.Spec.Permits)
+
+	// The semaphore exists now, so a second auto-creating acquire reuses it
+	_, err = AcquireN(client, context.Background(), "test-sem", 1, konductor.WithHolder("worker-2"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+}
+
+func TestAcquireN_AutoCreateSizesSemaphore(t *testing.T) {
+	client := setupGrantingTestClient(t)
+
+	_, err := AcquireN(client, context.Background(), "test-sem", 3, konductor.WithHolder("worker-1"), konductor.WithAutoCreate())
+	require.NoError(t, err)
+
+	semaphore, err := Get(client, context.Background(), "test-sem")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), semaphore.Spec.Permits)
+}
+
+func TestAcquire_WithWeight(t *testing.T) {
+	client := setupGrantingTestClient(t, multiSemaphore(5, 3))
+	ctx := context.Background()
+
+	_, err := Acquire(client, ctx, "test-sem", konductor.WithHolder("big-job"), konductor.WithWeight(3), konductor.WithTimeout(time.Second))
+	require.NoError(t, err)
+
+	var list syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(ctx, &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, int32(3), list.Items[0].Spec.Weight)
+}
+
+func TestTryAcquire_WeightExceedsAvailable(t *testing.T) {
+	client := setupAdmittingTestClient(t, multiSemaphore(5, 2))
+
+	_, err := TryAcquire(client, context.Background(), "test-sem", konductor.WithWeight(3))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNoPermits))
+
+	// A permit light enough for what is left still goes through
+	_, err = TryAcquire(client, context.Background(), "test-sem", konductor.WithWeight(2))
+	require.NoError(t, err)
+}
+
+func TestAcquire_WeightExceedsPermits(t *testing.T) {
+	client := setupSemaphoreTestClient(t, multiSemaphore(2, 2))
+
+	_, err := Acquire(client, context.Background(), "test-sem", konductor.WithWeight(3))
+	assert.EqualError(t, err, "failed to acquire 3 permits on semaphore test-sem, which only has 2")
+
+	_, err = Acquire(client, context.Background(), "test-sem", konductor.WithWeight(-1))
+	assert.ErrorContains(t, err, "permit weight must be positive")
+
+	var list syncv1.PermitList
+	require.NoError(t, client.K8sClient().List(context.Background(), &list))
+	assert.Empty(t, list.Items)
+}
+
+func TestAcquireN_WithWeight(t *testing.T) {
+	client := setupAdmittingTestClient(t, multiSemaphore(6, 5))
+
+	// Two permits of weight 3 need six available
+	_, err := AcquireN(client, context.Background(), "test-sem", 2, konductor.WithWeight(3))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNoPermits))
+
+	permits, err := AcquireN(client, context.Background(), "test-sem", 2, konductor.WithWeight(2))
+	require.NoError(t, err)
+	assert.Equal(t, 2, permits.Count())
 }
@@ -8,13 +8,16 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	syncv1 "github.com/LogicIQ/konductor/api/v1"
 	konductor "github.com/LogicIQ/konductor/sdk/go/client"
 )
 
-// Add increments the counter by delta with atomic operation protection
+// Add increments the counter by delta with atomic operation protection. A
+// negative delta that would take the counter below zero fails with
+// ErrNegativeCounter and leaves the counter unchanged.
 func Add(c *konductor.Client, ctx context.Context, name string, delta int32) error {
 	// Retry on conflicts with atomic read-modify-write
 	err := c.RetryWithBackoff(ctx, func() error {
@@ -25,11 +28,13 @@ func Add(c *konductor.Client, ctx context.Context, name string, delta int32) err
 			return err
 		}
 
+		if wg.Status.Counter+delta < 0 {
+			return fmt.Errorf("waitgroup %s at %d cannot be decremented by %d: %w",
+				name, wg.Status.Counter, -delta, konductor.ErrNegativeCounter)
+		}
+
 		// Atomic increment - this will fail with conflict if another pod modified it
 		wg.Status.Counter += delta
-		if wg.Status.Counter < 0 {
-			wg.Status.Counter = 0
-		}
 
 		if wg.Status.Counter <= 0 {
 			wg.Status.Phase = syncv1.WaitGroupPhaseDone
@@ -74,8 +79,18 @@ func Done(c *konductor.Client, ctx context.Context, name string) error {
 	return Add(c, ctx, name, -1)
 }
 
-// Wait blocks until counter is zero with exponential backoff
+// Wait blocks until counter is zero, watching the waitgroup and falling back
+// to polling if the watch cannot be established
 func Wait(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
+	return WaitWithProgress(c, ctx, name, nil, opts...)
+}
+
+// WaitWithProgress blocks like Wait, calling progress with the counter each
+// time it is seen to change: once with the counter when the wait starts, then
+// after each Add or Done that lands while waiting, and finally with zero.
+// Changes that land between two observations are only seen as the latest
+// value. progress runs on the waiting goroutine and may be nil.
+func WaitWithProgress(c *konductor.Client, ctx context.Context, name string, progress func(remaining int32), opts ...konductor.Option) error {
 	options := &konductor.Options{Timeout: 0}
 	for _, opt := range opts {
 		opt(options)
@@ -97,10 +112,22 @@ func Wait(c *konductor.Client, ctx context.Context, name string, opts ...konduct
 		config.Timeout = options.Timeout
 	}
 
-	if err := c.WaitForCondition(ctx, wg, func(obj client.Object) bool {
+	observed := false
+	var last int32
+	if err := c.WatchForCondition(ctx, wg, func(obj client.Object) bool {
 		waitGroup := obj.(*syncv1.WaitGroup)
+		if progress != nil && (!observed || waitGroup.Status.Counter != last) {
+			observed, last = true, waitGroup.Status.Counter
+			progress(max(last, 0))
+		}
 		return waitGroup.Status.Counter <= 0
 	}, config); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("context cancelled while waiting for waitgroup %s: %w", name, ctx.Err())
+		}
+		if wait.Interrupted(err) {
+			return fmt.Errorf("%w waiting for waitgroup %s: %w", konductor.ErrTimeout, name, err)
+		}
 		return fmt.Errorf("failed to wait for waitgroup %s: %w", name, err)
 	}
 
@@ -116,6 +143,8 @@ func GetCounter(c *konductor.Client, ctx context.Context, name string) (int32, e
 	return wg.Status.Counter, nil
 }
 
+// Create creates a waitgroup, starting its counter at the count given with
+// WithCount
 func Create(c *konductor.Client, ctx context.Context, name string, opts ...konductor.Option) error {
 	options := &konductor.Options{}
 	for _, opt := range opts {
@@ -135,14 +164,26 @@ func Create(c *konductor.Client, ctx context.Context, name string, opts ...kondu
 	}
 
 	// Use retry for create operations to handle name conflicts
-	return c.RetryWithBackoff(ctx, func() error {
+	created := false
+	err := c.RetryWithBackoff(ctx, func() error {
 		err := c.K8sClient().Create(ctx, wg)
 		if err != nil && errors.IsAlreadyExists(err) {
 			// Resource already exists, this is not an error for idempotent create
 			return nil
 		}
+		created = err == nil
 		return err
 	}, nil)
+	if err != nil {
+		return err
+	}
+
+	// Only a waitgroup created here starts at the requested count; an existing
+	// one keeps its counter
+	if created && options.Count != 0 {
+		return Add(c, ctx, name, options.Count)
+	}
+	return nil
 }
 
 func Delete(c *konductor.Client, ctx context.Context, name string) error {
@@ -171,9 +212,9 @@ func Get(c *konductor.Client, ctx context.Context, name string) (*syncv1.WaitGro
 	return &wg, nil
 }
 
-func List(c *konductor.Client, ctx context.Context) ([]syncv1.WaitGroup, error) {
+func List(c *konductor.Client, ctx context.Context, opts ...konductor.Option) ([]syncv1.WaitGroup, error) {
 	var wgs syncv1.WaitGroupList
-	if err := c.K8sClient().List(ctx, &wgs, client.InNamespace(c.Namespace())); err != nil {
+	if err := c.K8sClient().List(ctx, &wgs, c.ListOptions(opts...)...); err != nil {
 		return nil, fmt.Errorf("failed to list waitgroups: %w", err)
 	}
 	return wgs.Items, nil
@@ -2,6 +2,7 @@ package waitgroup
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -97,6 +98,61 @@ func TestList(t *testing.T) {
 	assert.Len(t, wgs, 2)
 }
 
+func TestList_WithLabelSelector(t *testing.T) {
+	data := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data-waitgroup",
+			Namespace: "default",
+			Labels:    map[string]string{"team": "data"},
+		},
+	}
+	web := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-waitgroup",
+			Namespace: "default",
+			Labels:    map[string]string{"team": "web"},
+		},
+	}
+
+	client := setupTestClient(t, data, web)
+
+	items, err := List(client, context.Background(), konductor.WithLabelSelector(map[string]string{"team": "data"}))
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "data-waitgroup", items[0].Name)
+
+	items, err = List(client, context.Background())
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestList_WithAllNamespaces(t *testing.T) {
+	local := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "local-waitgroup",
+			Namespace: "default",
+		},
+	}
+	remote := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "remote-waitgroup",
+			Namespace: "other-ns",
+		},
+	}
+
+	client := setupTestClient(t, local, remote)
+
+	items, err := List(client, context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "default", items[0].Namespace)
+
+	items, err = List(client, context.Background(), konductor.WithAllNamespaces())
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.ElementsMatch(t, []string{"default", "other-ns"}, []string{items[0].Namespace, items[1].Namespace})
+}
+
 func TestAdd_WithExistingCounter(t *testing.T) {
 	wg := &syncv1.WaitGroup{
 		ObjectMeta: metav1.ObjectMeta{
@@ -139,3 +195,160 @@ func TestCreate_WithoutOptions(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "test-wg", created.Name)
 }
+
+func TestCreate_WithCount(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, Create(client, ctx, "test-wg", konductor.WithCount(3)))
+
+	counter, err := GetCounter(client, ctx, "test-wg")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), counter)
+
+	// Creating an existing waitgroup again leaves its counter alone
+	require.NoError(t, Create(client, ctx, "test-wg", konductor.WithCount(3)))
+
+	counter, err = GetCounter(client, ctx, "test-wg")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), counter)
+}
+
+func TestAdd_AfterCreate(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, Create(client, ctx, "test-wg"))
+	require.NoError(t, Add(client, ctx, "test-wg", 2))
+
+	final, err := Get(client, ctx, "test-wg")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), final.Status.Counter)
+	assert.Equal(t, syncv1.WaitGroupPhaseWaiting, final.Status.Phase)
+}
+
+func TestDone_ToZeroReleasesWait(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, Create(client, ctx, "test-wg", konductor.WithCount(2)))
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- Wait(client, ctx, "test-wg", konductor.WithTimeout(5*time.Second))
+	}()
+
+	require.NoError(t, Done(client, ctx, "test-wg"))
+	select {
+	case <-waitErr:
+		t.Fatal("Wait returned before the counter reached zero")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, Done(client, ctx, "test-wg"))
+	select {
+	case err := <-waitErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return after the counter reached zero")
+	}
+
+	final, err := Get(client, ctx, "test-wg")
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), final.Status.Counter)
+	assert.Equal(t, syncv1.WaitGroupPhaseDone, final.Status.Phase)
+}
+
+func TestWaitWithProgress(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, Create(client, ctx, "test-wg", konductor.WithCount(3)))
+
+	seen := make(chan int32, 10)
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- WaitWithProgress(client, ctx, "test-wg", func(remaining int32) {
+			seen <- remaining
+		}, konductor.WithTimeout(30*time.Second))
+	}()
+
+	select {
+	case remaining := <-seen:
+		assert.Equal(t, int32(3), remaining, "reports the counter the wait starts at")
+	case <-time.After(5 * time.Second):
+		t.Fatal("progress was not reported when the wait started")
+	}
+
+	for _, want := range []int32{2, 1, 0} {
+		require.NoError(t, Done(client, ctx, "test-wg"))
+		select {
+		case remaining := <-seen:
+			assert.Equal(t, want, remaining)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("progress was not reported after the counter dropped to %d", want)
+		}
+	}
+
+	select {
+	case err := <-waitErr:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitWithProgress did not return after the counter reached zero")
+	}
+	assert.Empty(t, seen, "each value is reported once")
+}
+
+func TestWaitWithProgress_Timeout(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, Create(client, ctx, "test-wg", konductor.WithCount(2)))
+
+	var seen []int32
+	err := WaitWithProgress(client, ctx, "test-wg", func(remaining int32) {
+		seen = append(seen, remaining)
+	}, konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+	assert.Equal(t, []int32{2}, seen)
+}
+
+func TestAdd_NegativeCounter(t *testing.T) {
+	wg := &syncv1.WaitGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-wg",
+			Namespace: "default",
+		},
+		Status: syncv1.WaitGroupStatus{
+			Counter: 1,
+			Phase:   syncv1.WaitGroupPhaseWaiting,
+		},
+	}
+
+	client := setupTestClient(t, wg)
+	ctx := context.Background()
+
+	err := Add(client, ctx, "test-wg", -2)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrNegativeCounter))
+
+	counter, err := GetCounter(client, ctx, "test-wg")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), counter, "a rejected decrement must not change the counter")
+
+	require.NoError(t, Done(client, ctx, "test-wg"))
+	err = Done(client, ctx, "test-wg")
+	assert.True(t, errors.Is(err, konductor.ErrNegativeCounter))
+}
+
+func TestWait_Timeout(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	require.NoError(t, Create(client, ctx, "test-wg", konductor.WithCount(1)))
+
+	err := Wait(client, ctx, "test-wg", konductor.WithTimeout(200*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, konductor.ErrTimeout))
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	"github.com/LogicIQ/konductor/sdk/go/barrier"
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type barrierService struct {
+	pb.UnimplementedBarrierServiceServer
+	server *Server
+}
+
+func (s *barrierService) Arrive(ctx context.Context, req *pb.ArriveBarrierRequest) (*pb.ArriveBarrierResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := barrier.Arrive(c, ctx, req.GetName(), konductor.WithHolder(req.GetHolder())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ArriveBarrierResponse{}, nil
+}
+
+func (s *barrierService) Wait(req *pb.WaitBarrierRequest, stream pb.BarrierService_WaitServer) error {
+	if err := validate(req.GetName(), nil); err != nil {
+		return err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return err
+	}
+	b := &syncv1.Barrier{}
+	b.Name = req.GetName()
+	b.Namespace = c.Namespace()
+
+	return streamStatus(stream.Context(), c, "barrier", b, duration(req.GetTimeout()), barrierSummarizer(), stream.Send)
+}
+
+// barrierSummarizer returns a summarize func for one Wait stream. A cyclic
+// barrier never stays Open, so it is reported open once its generation moves
+// past the one it was in when first summarized.
+func barrierSummarizer() func(client.Object) (*pb.BarrierStatus, outcome) {
+	var startGeneration *int64
+	return func(obj client.Object) (*pb.BarrierStatus, outcome) {
+		b := obj.(*syncv1.Barrier)
+		summary, o := summarizeBarrier(b)
+		if b.Spec.Cyclic {
+			if startGeneration == nil {
+				generation := b.Status.Generation
+				startGeneration = &generation
+			}
+			if b.Status.Generation > *startGeneration {
+				summary.Phase = string(syncv1.BarrierPhaseOpen)
+				o = outcomeOpen
+			}
+		}
+		return summary, o
+	}
+}
+
+func summarizeBarrier(b *syncv1.Barrier) (*pb.BarrierStatus, outcome) {
+	summary := &pb.BarrierStatus{
+		Name:     b.Name,
+		Phase:    string(b.Status.Phase),
+		Arrived:  b.Status.Arrived,
+		Expected: b.Spec.Expected,
+	}
+
+	switch b.Status.Phase {
+	case syncv1.BarrierPhaseOpen:
+		return summary, outcomeOpen
+	case syncv1.BarrierPhaseFailed:
+		return summary, outcomeFailed
+	default:
+		return summary, outcomeWaiting
+	}
+}
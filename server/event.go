@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/event"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type eventService struct {
+	pb.UnimplementedEventServiceServer
+	server *Server
+}
+
+func (s *eventService) Set(ctx context.Context, req *pb.SetEventRequest) (*pb.SetEventResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := event.Set(c, ctx, req.GetName(), konductor.WithHolder(req.GetHolder())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.SetEventResponse{}, nil
+}
+
+func (s *eventService) Clear(ctx context.Context, req *pb.ClearEventRequest) (*pb.ClearEventResponse, error) {
+	if err := validate(req.GetName(), nil); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := event.Clear(c, ctx, req.GetName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ClearEventResponse{}, nil
+}
+
+func (s *eventService) Wait(ctx context.Context, req *pb.WaitEventRequest) (*pb.WaitEventResponse, error) {
+	if err := validate(req.GetName(), nil); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := event.Wait(c, ctx, req.GetName(), timeoutOptions(req.GetTimeout())...); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.WaitEventResponse{}, nil
+}
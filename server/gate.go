@@ -0,0 +1,54 @@
+package server
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type gateService struct {
+	pb.UnimplementedGateServiceServer
+	server *Server
+}
+
+func (s *gateService) Wait(req *pb.WaitGateRequest, stream pb.GateService_WaitServer) error {
+	if err := validate(req.GetName(), nil); err != nil {
+		return err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return err
+	}
+	g := &syncv1.Gate{}
+	g.Name = req.GetName()
+	g.Namespace = c.Namespace()
+
+	return streamStatus(stream.Context(), c, "gate", g, duration(req.GetTimeout()), summarizeGate, stream.Send)
+}
+
+func summarizeGate(obj client.Object) (*pb.GateStatus, outcome) {
+	g := obj.(*syncv1.Gate)
+	met := 0
+	for _, condition := range g.Status.ConditionStatuses {
+		if condition.Met {
+			met++
+		}
+	}
+	summary := &pb.GateStatus{
+		Name:            g.Name,
+		Phase:           string(g.Status.Phase),
+		ConditionsMet:   int32(met),
+		ConditionsTotal: int32(len(g.Spec.Conditions)),
+	}
+
+	switch g.Status.Phase {
+	case syncv1.GatePhaseOpen:
+		return summary, outcomeOpen
+	case syncv1.GatePhaseFailed:
+		return summary, outcomeFailed
+	default:
+		return summary, outcomeWaiting
+	}
+}
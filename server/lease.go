@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/lease"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type leaseService struct {
+	pb.UnimplementedLeaseServiceServer
+	server *Server
+}
+
+func (s *leaseService) Acquire(ctx context.Context, req *pb.AcquireLeaseRequest) (*pb.AcquireLeaseResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	opts := append(timeoutOptions(req.GetTimeout()), konductor.WithHolder(req.GetHolder()))
+	if req.GetPriority() > 0 {
+		opts = append(opts, konductor.WithPriority(req.GetPriority()))
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	l, err := lease.Acquire(c, ctx, req.GetName(), opts...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AcquireLeaseResponse{Name: l.Name(), Holder: l.Holder(), FenceToken: l.FenceToken()}, nil
+}
+
+func (s *leaseService) Release(ctx context.Context, req *pb.ReleaseLeaseRequest) (*pb.ReleaseLeaseResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	err = c.ReleaseLease(ctx, req.GetName(), req.GetHolder())
+	if client.IgnoreNotFound(err) != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ReleaseLeaseResponse{}, nil
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/mutex"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type mutexService struct {
+	pb.UnimplementedMutexServiceServer
+	server *Server
+}
+
+func (s *mutexService) Acquire(ctx context.Context, req *pb.AcquireMutexRequest) (*pb.AcquireMutexResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	opts := append(timeoutOptions(req.GetTimeout()), konductor.WithHolder(req.GetHolder()))
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	m, err := mutex.Lock(c, ctx, req.GetName(), opts...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AcquireMutexResponse{Name: m.Name(), Holder: m.Holder(), FenceToken: m.FenceToken()}, nil
+}
+
+func (s *mutexService) Release(ctx context.Context, req *pb.ReleaseMutexRequest) (*pb.ReleaseMutexResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := mutex.Unlock(c, ctx, req.GetName(), req.GetHolder()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ReleaseMutexResponse{}, nil
+}
@@ -0,0 +1,1214 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: konductor.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	SemaphoreService_Acquire_FullMethodName = "/konductor.v1.SemaphoreService/Acquire"
+	SemaphoreService_Release_FullMethodName = "/konductor.v1.SemaphoreService/Release"
+)
+
+// SemaphoreServiceClient is the client API for SemaphoreService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SemaphoreService controls access to a limited number of permits
+type SemaphoreServiceClient interface {
+	// Acquire blocks until a permit is granted or the timeout passes
+	Acquire(ctx context.Context, in *AcquireSemaphoreRequest, opts ...grpc.CallOption) (*AcquireSemaphoreResponse, error)
+	// Release returns the holder's permit
+	Release(ctx context.Context, in *ReleaseSemaphoreRequest, opts ...grpc.CallOption) (*ReleaseSemaphoreResponse, error)
+}
+
+type semaphoreServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSemaphoreServiceClient(cc grpc.ClientConnInterface) SemaphoreServiceClient {
+	return &semaphoreServiceClient{cc}
+}
+
+func (c *semaphoreServiceClient) Acquire(ctx context.Context, in *AcquireSemaphoreRequest, opts ...grpc.CallOption) (*AcquireSemaphoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcquireSemaphoreResponse)
+	err := c.cc.Invoke(ctx, SemaphoreService_Acquire_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *semaphoreServiceClient) Release(ctx context.Context, in *ReleaseSemaphoreRequest, opts ...grpc.CallOption) (*ReleaseSemaphoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseSemaphoreResponse)
+	err := c.cc.Invoke(ctx, SemaphoreService_Release_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SemaphoreServiceServer is the server API for SemaphoreService service.
+// All implementations must embed UnimplementedSemaphoreServiceServer
+// for forward compatibility
+//
+// SemaphoreService controls access to a limited number of permits
+type SemaphoreServiceServer interface {
+	// Acquire blocks until a permit is granted or the timeout passes
+	Acquire(context.Context, *AcquireSemaphoreRequest) (*AcquireSemaphoreResponse, error)
+	// Release returns the holder's permit
+	Release(context.Context, *ReleaseSemaphoreRequest) (*ReleaseSemaphoreResponse, error)
+	mustEmbedUnimplementedSemaphoreServiceServer()
+}
+
+// UnimplementedSemaphoreServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedSemaphoreServiceServer struct {
+}
+
+func (UnimplementedSemaphoreServiceServer) Acquire(context.Context, *AcquireSemaphoreRequest) (*AcquireSemaphoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Acquire not implemented")
+}
+func (UnimplementedSemaphoreServiceServer) Release(context.Context, *ReleaseSemaphoreRequest) (*ReleaseSemaphoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
+}
+func (UnimplementedSemaphoreServiceServer) mustEmbedUnimplementedSemaphoreServiceServer() {}
+
+// UnsafeSemaphoreServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SemaphoreServiceServer will
+// result in compilation errors.
+type UnsafeSemaphoreServiceServer interface {
+	mustEmbedUnimplementedSemaphoreServiceServer()
+}
+
+func RegisterSemaphoreServiceServer(s grpc.ServiceRegistrar, srv SemaphoreServiceServer) {
+	s.RegisterService(&SemaphoreService_ServiceDesc, srv)
+}
+
+func _SemaphoreService_Acquire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireSemaphoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SemaphoreServiceServer).Acquire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SemaphoreService_Acquire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SemaphoreServiceServer).Acquire(ctx, req.(*AcquireSemaphoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SemaphoreService_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseSemaphoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SemaphoreServiceServer).Release(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SemaphoreService_Release_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SemaphoreServiceServer).Release(ctx, req.(*ReleaseSemaphoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SemaphoreService_ServiceDesc is the grpc.ServiceDesc for SemaphoreService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SemaphoreService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.SemaphoreService",
+	HandlerType: (*SemaphoreServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Acquire",
+			Handler:    _SemaphoreService_Acquire_Handler,
+		},
+		{
+			MethodName: "Release",
+			Handler:    _SemaphoreService_Release_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "konductor.proto",
+}
+
+const (
+	LeaseService_Acquire_FullMethodName = "/konductor.v1.LeaseService/Acquire"
+	LeaseService_Release_FullMethodName = "/konductor.v1.LeaseService/Release"
+)
+
+// LeaseServiceClient is the client API for LeaseService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LeaseService grants exclusive, time-limited ownership
+type LeaseServiceClient interface {
+	// Acquire blocks until the lease is granted or the timeout passes
+	Acquire(ctx context.Context, in *AcquireLeaseRequest, opts ...grpc.CallOption) (*AcquireLeaseResponse, error)
+	// Release gives up the holder's lease
+	Release(ctx context.Context, in *ReleaseLeaseRequest, opts ...grpc.CallOption) (*ReleaseLeaseResponse, error)
+}
+
+type leaseServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLeaseServiceClient(cc grpc.ClientConnInterface) LeaseServiceClient {
+	return &leaseServiceClient{cc}
+}
+
+func (c *leaseServiceClient) Acquire(ctx context.Context, in *AcquireLeaseRequest, opts ...grpc.CallOption) (*AcquireLeaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcquireLeaseResponse)
+	err := c.cc.Invoke(ctx, LeaseService_Acquire_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaseServiceClient) Release(ctx context.Context, in *ReleaseLeaseRequest, opts ...grpc.CallOption) (*ReleaseLeaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseLeaseResponse)
+	err := c.cc.Invoke(ctx, LeaseService_Release_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LeaseServiceServer is the server API for LeaseService service.
+// All implementations must embed UnimplementedLeaseServiceServer
+// for forward compatibility
+//
+// LeaseService grants exclusive, time-limited ownership
+type LeaseServiceServer interface {
+	// Acquire blocks until the lease is granted or the timeout passes
+	Acquire(context.Context, *AcquireLeaseRequest) (*AcquireLeaseResponse, error)
+	// Release gives up the holder's lease
+	Release(context.Context, *ReleaseLeaseRequest) (*ReleaseLeaseResponse, error)
+	mustEmbedUnimplementedLeaseServiceServer()
+}
+
+// UnimplementedLeaseServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLeaseServiceServer struct {
+}
+
+func (UnimplementedLeaseServiceServer) Acquire(context.Context, *AcquireLeaseRequest) (*AcquireLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Acquire not implemented")
+}
+func (UnimplementedLeaseServiceServer) Release(context.Context, *ReleaseLeaseRequest) (*ReleaseLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
+}
+func (UnimplementedLeaseServiceServer) mustEmbedUnimplementedLeaseServiceServer() {}
+
+// UnsafeLeaseServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LeaseServiceServer will
+// result in compilation errors.
+type UnsafeLeaseServiceServer interface {
+	mustEmbedUnimplementedLeaseServiceServer()
+}
+
+func RegisterLeaseServiceServer(s grpc.ServiceRegistrar, srv LeaseServiceServer) {
+	s.RegisterService(&LeaseService_ServiceDesc, srv)
+}
+
+func _LeaseService_Acquire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaseServiceServer).Acquire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaseService_Acquire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaseServiceServer).Acquire(ctx, req.(*AcquireLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaseService_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaseServiceServer).Release(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaseService_Release_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaseServiceServer).Release(ctx, req.(*ReleaseLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LeaseService_ServiceDesc is the grpc.ServiceDesc for LeaseService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LeaseService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.LeaseService",
+	HandlerType: (*LeaseServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Acquire",
+			Handler:    _LeaseService_Acquire_Handler,
+		},
+		{
+			MethodName: "Release",
+			Handler:    _LeaseService_Release_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "konductor.proto",
+}
+
+const (
+	MutexService_Acquire_FullMethodName = "/konductor.v1.MutexService/Acquire"
+	MutexService_Release_FullMethodName = "/konductor.v1.MutexService/Release"
+)
+
+// MutexServiceClient is the client API for MutexService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MutexService provides mutual exclusion
+type MutexServiceClient interface {
+	// Acquire blocks until the mutex is locked or the timeout passes
+	Acquire(ctx context.Context, in *AcquireMutexRequest, opts ...grpc.CallOption) (*AcquireMutexResponse, error)
+	// Release unlocks the mutex held by the holder
+	Release(ctx context.Context, in *ReleaseMutexRequest, opts ...grpc.CallOption) (*ReleaseMutexResponse, error)
+}
+
+type mutexServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMutexServiceClient(cc grpc.ClientConnInterface) MutexServiceClient {
+	return &mutexServiceClient{cc}
+}
+
+func (c *mutexServiceClient) Acquire(ctx context.Context, in *AcquireMutexRequest, opts ...grpc.CallOption) (*AcquireMutexResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcquireMutexResponse)
+	err := c.cc.Invoke(ctx, MutexService_Acquire_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mutexServiceClient) Release(ctx context.Context, in *ReleaseMutexRequest, opts ...grpc.CallOption) (*ReleaseMutexResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseMutexResponse)
+	err := c.cc.Invoke(ctx, MutexService_Release_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MutexServiceServer is the server API for MutexService service.
+// All implementations must embed UnimplementedMutexServiceServer
+// for forward compatibility
+//
+// MutexService provides mutual exclusion
+type MutexServiceServer interface {
+	// Acquire blocks until the mutex is locked or the timeout passes
+	Acquire(context.Context, *AcquireMutexRequest) (*AcquireMutexResponse, error)
+	// Release unlocks the mutex held by the holder
+	Release(context.Context, *ReleaseMutexRequest) (*ReleaseMutexResponse, error)
+	mustEmbedUnimplementedMutexServiceServer()
+}
+
+// UnimplementedMutexServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMutexServiceServer struct {
+}
+
+func (UnimplementedMutexServiceServer) Acquire(context.Context, *AcquireMutexRequest) (*AcquireMutexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Acquire not implemented")
+}
+func (UnimplementedMutexServiceServer) Release(context.Context, *ReleaseMutexRequest) (*ReleaseMutexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
+}
+func (UnimplementedMutexServiceServer) mustEmbedUnimplementedMutexServiceServer() {}
+
+// UnsafeMutexServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MutexServiceServer will
+// result in compilation errors.
+type UnsafeMutexServiceServer interface {
+	mustEmbedUnimplementedMutexServiceServer()
+}
+
+func RegisterMutexServiceServer(s grpc.ServiceRegistrar, srv MutexServiceServer) {
+	s.RegisterService(&MutexService_ServiceDesc, srv)
+}
+
+func _MutexService_Acquire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireMutexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MutexServiceServer).Acquire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MutexService_Acquire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MutexServiceServer).Acquire(ctx, req.(*AcquireMutexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MutexService_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseMutexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MutexServiceServer).Release(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MutexService_Release_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MutexServiceServer).Release(ctx, req.(*ReleaseMutexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MutexService_ServiceDesc is the grpc.ServiceDesc for MutexService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MutexService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.MutexService",
+	HandlerType: (*MutexServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Acquire",
+			Handler:    _MutexService_Acquire_Handler,
+		},
+		{
+			MethodName: "Release",
+			Handler:    _MutexService_Release_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "konductor.proto",
+}
+
+const (
+	RWMutexService_Acquire_FullMethodName = "/konductor.v1.RWMutexService/Acquire"
+	RWMutexService_Release_FullMethodName = "/konductor.v1.RWMutexService/Release"
+)
+
+// RWMutexServiceClient is the client API for RWMutexService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RWMutexService provides shared read and exclusive write locks
+type RWMutexServiceClient interface {
+	// Acquire blocks until a read or write lock is held or the timeout passes
+	Acquire(ctx context.Context, in *AcquireRWMutexRequest, opts ...grpc.CallOption) (*AcquireRWMutexResponse, error)
+	// Release unlocks whichever lock the holder has
+	Release(ctx context.Context, in *ReleaseRWMutexRequest, opts ...grpc.CallOption) (*ReleaseRWMutexResponse, error)
+}
+
+type rWMutexServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRWMutexServiceClient(cc grpc.ClientConnInterface) RWMutexServiceClient {
+	return &rWMutexServiceClient{cc}
+}
+
+func (c *rWMutexServiceClient) Acquire(ctx context.Context, in *AcquireRWMutexRequest, opts ...grpc.CallOption) (*AcquireRWMutexResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcquireRWMutexResponse)
+	err := c.cc.Invoke(ctx, RWMutexService_Acquire_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rWMutexServiceClient) Release(ctx context.Context, in *ReleaseRWMutexRequest, opts ...grpc.CallOption) (*ReleaseRWMutexResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseRWMutexResponse)
+	err := c.cc.Invoke(ctx, RWMutexService_Release_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RWMutexServiceServer is the server API for RWMutexService service.
+// All implementations must embed UnimplementedRWMutexServiceServer
+// for forward compatibility
+//
+// RWMutexService provides shared read and exclusive write locks
+type RWMutexServiceServer interface {
+	// Acquire blocks until a read or write lock is held or the timeout passes
+	Acquire(context.Context, *AcquireRWMutexRequest) (*AcquireRWMutexResponse, error)
+	// Release unlocks whichever lock the holder has
+	Release(context.Context, *ReleaseRWMutexRequest) (*ReleaseRWMutexResponse, error)
+	mustEmbedUnimplementedRWMutexServiceServer()
+}
+
+// UnimplementedRWMutexServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRWMutexServiceServer struct {
+}
+
+func (UnimplementedRWMutexServiceServer) Acquire(context.Context, *AcquireRWMutexRequest) (*AcquireRWMutexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Acquire not implemented")
+}
+func (UnimplementedRWMutexServiceServer) Release(context.Context, *ReleaseRWMutexRequest) (*ReleaseRWMutexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Release not implemented")
+}
+func (UnimplementedRWMutexServiceServer) mustEmbedUnimplementedRWMutexServiceServer() {}
+
+// UnsafeRWMutexServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RWMutexServiceServer will
+// result in compilation errors.
+type UnsafeRWMutexServiceServer interface {
+	mustEmbedUnimplementedRWMutexServiceServer()
+}
+
+func RegisterRWMutexServiceServer(s grpc.ServiceRegistrar, srv RWMutexServiceServer) {
+	s.RegisterService(&RWMutexService_ServiceDesc, srv)
+}
+
+func _RWMutexService_Acquire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireRWMutexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RWMutexServiceServer).Acquire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RWMutexService_Acquire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RWMutexServiceServer).Acquire(ctx, req.(*AcquireRWMutexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RWMutexService_Release_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseRWMutexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RWMutexServiceServer).Release(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RWMutexService_Release_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RWMutexServiceServer).Release(ctx, req.(*ReleaseRWMutexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RWMutexService_ServiceDesc is the grpc.ServiceDesc for RWMutexService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RWMutexService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.RWMutexService",
+	HandlerType: (*RWMutexServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Acquire",
+			Handler:    _RWMutexService_Acquire_Handler,
+		},
+		{
+			MethodName: "Release",
+			Handler:    _RWMutexService_Release_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "konductor.proto",
+}
+
+const (
+	BarrierService_Arrive_FullMethodName = "/konductor.v1.BarrierService/Arrive"
+	BarrierService_Wait_FullMethodName   = "/konductor.v1.BarrierService/Wait"
+)
+
+// BarrierServiceClient is the client API for BarrierService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BarrierService coordinates a set of holders reaching the same point
+type BarrierServiceClient interface {
+	// Arrive records the holder's arrival at the barrier
+	Arrive(ctx context.Context, in *ArriveBarrierRequest, opts ...grpc.CallOption) (*ArriveBarrierResponse, error)
+	// Wait streams the barrier's status on every change until it opens. The
+	// stream ends with FAILED_PRECONDITION if the barrier fails.
+	Wait(ctx context.Context, in *WaitBarrierRequest, opts ...grpc.CallOption) (BarrierService_WaitClient, error)
+}
+
+type barrierServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBarrierServiceClient(cc grpc.ClientConnInterface) BarrierServiceClient {
+	return &barrierServiceClient{cc}
+}
+
+func (c *barrierServiceClient) Arrive(ctx context.Context, in *ArriveBarrierRequest, opts ...grpc.CallOption) (*ArriveBarrierResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArriveBarrierResponse)
+	err := c.cc.Invoke(ctx, BarrierService_Arrive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *barrierServiceClient) Wait(ctx context.Context, in *WaitBarrierRequest, opts ...grpc.CallOption) (BarrierService_WaitClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BarrierService_ServiceDesc.Streams[0], BarrierService_Wait_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &barrierServiceWaitClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BarrierService_WaitClient interface {
+	Recv() (*BarrierStatus, error)
+	grpc.ClientStream
+}
+
+type barrierServiceWaitClient struct {
+	grpc.ClientStream
+}
+
+func (x *barrierServiceWaitClient) Recv() (*BarrierStatus, error) {
+	m := new(BarrierStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BarrierServiceServer is the server API for BarrierService service.
+// All implementations must embed UnimplementedBarrierServiceServer
+// for forward compatibility
+//
+// BarrierService coordinates a set of holders reaching the same point
+type BarrierServiceServer interface {
+	// Arrive records the holder's arrival at the barrier
+	Arrive(context.Context, *ArriveBarrierRequest) (*ArriveBarrierResponse, error)
+	// Wait streams the barrier's status on every change until it opens. The
+	// stream ends with FAILED_PRECONDITION if the barrier fails.
+	Wait(*WaitBarrierRequest, BarrierService_WaitServer) error
+	mustEmbedUnimplementedBarrierServiceServer()
+}
+
+// UnimplementedBarrierServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBarrierServiceServer struct {
+}
+
+func (UnimplementedBarrierServiceServer) Arrive(context.Context, *ArriveBarrierRequest) (*ArriveBarrierResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Arrive not implemented")
+}
+func (UnimplementedBarrierServiceServer) Wait(*WaitBarrierRequest, BarrierService_WaitServer) error {
+	return status.Errorf(codes.Unimplemented, "method Wait not implemented")
+}
+func (UnimplementedBarrierServiceServer) mustEmbedUnimplementedBarrierServiceServer() {}
+
+// UnsafeBarrierServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BarrierServiceServer will
+// result in compilation errors.
+type UnsafeBarrierServiceServer interface {
+	mustEmbedUnimplementedBarrierServiceServer()
+}
+
+func RegisterBarrierServiceServer(s grpc.ServiceRegistrar, srv BarrierServiceServer) {
+	s.RegisterService(&BarrierService_ServiceDesc, srv)
+}
+
+func _BarrierService_Arrive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArriveBarrierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BarrierServiceServer).Arrive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BarrierService_Arrive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BarrierServiceServer).Arrive(ctx, req.(*ArriveBarrierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BarrierService_Wait_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WaitBarrierRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BarrierServiceServer).Wait(m, &barrierServiceWaitServer{ServerStream: stream})
+}
+
+type BarrierService_WaitServer interface {
+	Send(*BarrierStatus) error
+	grpc.ServerStream
+}
+
+type barrierServiceWaitServer struct {
+	grpc.ServerStream
+}
+
+func (x *barrierServiceWaitServer) Send(m *BarrierStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BarrierService_ServiceDesc is the grpc.ServiceDesc for BarrierService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BarrierService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.BarrierService",
+	HandlerType: (*BarrierServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Arrive",
+			Handler:    _BarrierService_Arrive_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Wait",
+			Handler:       _BarrierService_Wait_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "konductor.proto",
+}
+
+const (
+	GateService_Wait_FullMethodName = "/konductor.v1.GateService/Wait"
+)
+
+// GateServiceClient is the client API for GateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GateService blocks callers until a set of conditions is met
+type GateServiceClient interface {
+	// Wait streams the gate's status on every change until it opens. The
+	// stream ends with FAILED_PRECONDITION if the gate fails.
+	Wait(ctx context.Context, in *WaitGateRequest, opts ...grpc.CallOption) (GateService_WaitClient, error)
+}
+
+type gateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGateServiceClient(cc grpc.ClientConnInterface) GateServiceClient {
+	return &gateServiceClient{cc}
+}
+
+func (c *gateServiceClient) Wait(ctx context.Context, in *WaitGateRequest, opts ...grpc.CallOption) (GateService_WaitClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GateService_ServiceDesc.Streams[0], GateService_Wait_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gateServiceWaitClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GateService_WaitClient interface {
+	Recv() (*GateStatus, error)
+	grpc.ClientStream
+}
+
+type gateServiceWaitClient struct {
+	grpc.ClientStream
+}
+
+func (x *gateServiceWaitClient) Recv() (*GateStatus, error) {
+	m := new(GateStatus)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GateServiceServer is the server API for GateService service.
+// All implementations must embed UnimplementedGateServiceServer
+// for forward compatibility
+//
+// GateService blocks callers until a set of conditions is met
+type GateServiceServer interface {
+	// Wait streams the gate's status on every change until it opens. The
+	// stream ends with FAILED_PRECONDITION if the gate fails.
+	Wait(*WaitGateRequest, GateService_WaitServer) error
+	mustEmbedUnimplementedGateServiceServer()
+}
+
+// UnimplementedGateServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedGateServiceServer struct {
+}
+
+func (UnimplementedGateServiceServer) Wait(*WaitGateRequest, GateService_WaitServer) error {
+	return status.Errorf(codes.Unimplemented, "method Wait not implemented")
+}
+func (UnimplementedGateServiceServer) mustEmbedUnimplementedGateServiceServer() {}
+
+// UnsafeGateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GateServiceServer will
+// result in compilation errors.
+type UnsafeGateServiceServer interface {
+	mustEmbedUnimplementedGateServiceServer()
+}
+
+func RegisterGateServiceServer(s grpc.ServiceRegistrar, srv GateServiceServer) {
+	s.RegisterService(&GateService_ServiceDesc, srv)
+}
+
+func _GateService_Wait_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WaitGateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GateServiceServer).Wait(m, &gateServiceWaitServer{ServerStream: stream})
+}
+
+type GateService_WaitServer interface {
+	Send(*GateStatus) error
+	grpc.ServerStream
+}
+
+type gateServiceWaitServer struct {
+	grpc.ServerStream
+}
+
+func (x *gateServiceWaitServer) Send(m *GateStatus) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// GateService_ServiceDesc is the grpc.ServiceDesc for GateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.GateService",
+	HandlerType: (*GateServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Wait",
+			Handler:       _GateService_Wait_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "konductor.proto",
+}
+
+const (
+	WaitGroupService_Add_FullMethodName  = "/konductor.v1.WaitGroupService/Add"
+	WaitGroupService_Done_FullMethodName = "/konductor.v1.WaitGroupService/Done"
+	WaitGroupService_Wait_FullMethodName = "/konductor.v1.WaitGroupService/Wait"
+)
+
+// WaitGroupServiceClient is the client API for WaitGroupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WaitGroupService waits for a counter of outstanding work to reach zero
+type WaitGroupServiceClient interface {
+	// Add adjusts the counter by delta
+	Add(ctx context.Context, in *AddWaitGroupRequest, opts ...grpc.CallOption) (*AddWaitGroupResponse, error)
+	// Done decrements the counter by one
+	Done(ctx context.Context, in *DoneWaitGroupRequest, opts ...grpc.CallOption) (*DoneWaitGroupResponse, error)
+	// Wait blocks until the counter reaches zero or the timeout passes
+	Wait(ctx context.Context, in *WaitWaitGroupRequest, opts ...grpc.CallOption) (*WaitWaitGroupResponse, error)
+}
+
+type waitGroupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWaitGroupServiceClient(cc grpc.ClientConnInterface) WaitGroupServiceClient {
+	return &waitGroupServiceClient{cc}
+}
+
+func (c *waitGroupServiceClient) Add(ctx context.Context, in *AddWaitGroupRequest, opts ...grpc.CallOption) (*AddWaitGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddWaitGroupResponse)
+	err := c.cc.Invoke(ctx, WaitGroupService_Add_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waitGroupServiceClient) Done(ctx context.Context, in *DoneWaitGroupRequest, opts ...grpc.CallOption) (*DoneWaitGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DoneWaitGroupResponse)
+	err := c.cc.Invoke(ctx, WaitGroupService_Done_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *waitGroupServiceClient) Wait(ctx context.Context, in *WaitWaitGroupRequest, opts ...grpc.CallOption) (*WaitWaitGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WaitWaitGroupResponse)
+	err := c.cc.Invoke(ctx, WaitGroupService_Wait_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WaitGroupServiceServer is the server API for WaitGroupService service.
+// All implementations must embed UnimplementedWaitGroupServiceServer
+// for forward compatibility
+//
+// WaitGroupService waits for a counter of outstanding work to reach zero
+type WaitGroupServiceServer interface {
+	// Add adjusts the counter by delta
+	Add(context.Context, *AddWaitGroupRequest) (*AddWaitGroupResponse, error)
+	// Done decrements the counter by one
+	Done(context.Context, *DoneWaitGroupRequest) (*DoneWaitGroupResponse, error)
+	// Wait blocks until the counter reaches zero or the timeout passes
+	Wait(context.Context, *WaitWaitGroupRequest) (*WaitWaitGroupResponse, error)
+	mustEmbedUnimplementedWaitGroupServiceServer()
+}
+
+// UnimplementedWaitGroupServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedWaitGroupServiceServer struct {
+}
+
+func (UnimplementedWaitGroupServiceServer) Add(context.Context, *AddWaitGroupRequest) (*AddWaitGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
+}
+func (UnimplementedWaitGroupServiceServer) Done(context.Context, *DoneWaitGroupRequest) (*DoneWaitGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Done not implemented")
+}
+func (UnimplementedWaitGroupServiceServer) Wait(context.Context, *WaitWaitGroupRequest) (*WaitWaitGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Wait not implemented")
+}
+func (UnimplementedWaitGroupServiceServer) mustEmbedUnimplementedWaitGroupServiceServer() {}
+
+// UnsafeWaitGroupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WaitGroupServiceServer will
+// result in compilation errors.
+type UnsafeWaitGroupServiceServer interface {
+	mustEmbedUnimplementedWaitGroupServiceServer()
+}
+
+func RegisterWaitGroupServiceServer(s grpc.ServiceRegistrar, srv WaitGroupServiceServer) {
+	s.RegisterService(&WaitGroupService_ServiceDesc, srv)
+}
+
+func _WaitGroupService_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWaitGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaitGroupServiceServer).Add(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WaitGroupService_Add_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaitGroupServiceServer).Add(ctx, req.(*AddWaitGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WaitGroupService_Done_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DoneWaitGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaitGroupServiceServer).Done(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WaitGroupService_Done_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaitGroupServiceServer).Done(ctx, req.(*DoneWaitGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WaitGroupService_Wait_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitWaitGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WaitGroupServiceServer).Wait(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WaitGroupService_Wait_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WaitGroupServiceServer).Wait(ctx, req.(*WaitWaitGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WaitGroupService_ServiceDesc is the grpc.ServiceDesc for WaitGroupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WaitGroupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.WaitGroupService",
+	HandlerType: (*WaitGroupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Add",
+			Handler:    _WaitGroupService_Add_Handler,
+		},
+		{
+			MethodName: "Done",
+			Handler:    _WaitGroupService_Done_Handler,
+		},
+		{
+			MethodName: "Wait",
+			Handler:    _WaitGroupService_Wait_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "konductor.proto",
+}
+
+const (
+	EventService_Set_FullMethodName   = "/konductor.v1.EventService/Set"
+	EventService_Clear_FullMethodName = "/konductor.v1.EventService/Clear"
+	EventService_Wait_FullMethodName  = "/konductor.v1.EventService/Wait"
+)
+
+// EventServiceClient is the client API for EventService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EventService signals waiters once a condition has occurred
+type EventServiceClient interface {
+	// Set signals the event, releasing every waiter
+	Set(ctx context.Context, in *SetEventRequest, opts ...grpc.CallOption) (*SetEventResponse, error)
+	// Clear resets the event so waiters block again
+	Clear(ctx context.Context, in *ClearEventRequest, opts ...grpc.CallOption) (*ClearEventResponse, error)
+	// Wait blocks until the event is set or the timeout passes
+	Wait(ctx context.Context, in *WaitEventRequest, opts ...grpc.CallOption) (*WaitEventResponse, error)
+}
+
+type eventServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventServiceClient(cc grpc.ClientConnInterface) EventServiceClient {
+	return &eventServiceClient{cc}
+}
+
+func (c *eventServiceClient) Set(ctx context.Context, in *SetEventRequest, opts ...grpc.CallOption) (*SetEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEventResponse)
+	err := c.cc.Invoke(ctx, EventService_Set_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) Clear(ctx context.Context, in *ClearEventRequest, opts ...grpc.CallOption) (*ClearEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearEventResponse)
+	err := c.cc.Invoke(ctx, EventService_Clear_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) Wait(ctx context.Context, in *WaitEventRequest, opts ...grpc.CallOption) (*WaitEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WaitEventResponse)
+	err := c.cc.Invoke(ctx, EventService_Wait_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EventServiceServer is the server API for EventService service.
+// All implementations must embed UnimplementedEventServiceServer
+// for forward compatibility
+//
+// EventService signals waiters once a condition has occurred
+type EventServiceServer interface {
+	// Set signals the event, releasing every waiter
+	Set(context.Context, *SetEventRequest) (*SetEventResponse, error)
+	// Clear resets the event so waiters block again
+	Clear(context.Context, *ClearEventRequest) (*ClearEventResponse, error)
+	// Wait blocks until the event is set or the timeout passes
+	Wait(context.Context, *WaitEventRequest) (*WaitEventResponse, error)
+	mustEmbedUnimplementedEventServiceServer()
+}
+
+// UnimplementedEventServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEventServiceServer struct {
+}
+
+func (UnimplementedEventServiceServer) Set(context.Context, *SetEventRequest) (*SetEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedEventServiceServer) Clear(context.Context, *ClearEventRequest) (*ClearEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Clear not implemented")
+}
+func (UnimplementedEventServiceServer) Wait(context.Context, *WaitEventRequest) (*WaitEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Wait not implemented")
+}
+func (UnimplementedEventServiceServer) mustEmbedUnimplementedEventServiceServer() {}
+
+// UnsafeEventServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventServiceServer will
+// result in compilation errors.
+type UnsafeEventServiceServer interface {
+	mustEmbedUnimplementedEventServiceServer()
+}
+
+func RegisterEventServiceServer(s grpc.ServiceRegistrar, srv EventServiceServer) {
+	s.RegisterService(&EventService_ServiceDesc, srv)
+}
+
+func _EventService_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_Set_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).Set(ctx, req.(*SetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_Clear_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).Clear(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_Clear_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).Clear(ctx, req.(*ClearEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_Wait_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).Wait(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EventService_Wait_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).Wait(ctx, req.(*WaitEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EventService_ServiceDesc is the grpc.ServiceDesc for EventService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "konductor.v1.EventService",
+	HandlerType: (*EventServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Set",
+			Handler:    _EventService_Set_Handler,
+		},
+		{
+			MethodName: "Clear",
+			Handler:    _EventService_Clear_Handler,
+		},
+		{
+			MethodName: "Wait",
+			Handler:    _EventService_Wait_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "konductor.proto",
+}
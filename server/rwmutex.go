@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/rwmutex"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type rwmutexService struct {
+	pb.UnimplementedRWMutexServiceServer
+	server *Server
+}
+
+func (s *rwmutexService) Acquire(ctx context.Context, req *pb.AcquireRWMutexRequest) (*pb.AcquireRWMutexResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	opts := append(timeoutOptions(req.GetTimeout()), konductor.WithHolder(req.GetHolder()))
+
+	lock := rwmutex.Lock
+	if req.GetRead() {
+		lock = rwmutex.RLock
+	}
+
+	m, err := lock(c, ctx, req.GetName(), opts...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AcquireRWMutexResponse{Name: m.Name(), Holder: m.Holder()}, nil
+}
+
+func (s *rwmutexService) Release(ctx context.Context, req *pb.ReleaseRWMutexRequest) (*pb.ReleaseRWMutexResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := rwmutex.Unlock(c, ctx, req.GetName(), req.GetHolder()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ReleaseRWMutexResponse{}, nil
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/sdk/go/semaphore"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type semaphoreService struct {
+	pb.UnimplementedSemaphoreServiceServer
+	server *Server
+}
+
+func (s *semaphoreService) Acquire(ctx context.Context, req *pb.AcquireSemaphoreRequest) (*pb.AcquireSemaphoreResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	opts := append(timeoutOptions(req.GetTimeout()), konductor.WithHolder(req.GetHolder()))
+	if ttl := duration(req.GetTtl()); ttl > 0 {
+		opts = append(opts, konductor.WithTTL(ttl))
+	}
+	if req.GetPriority() > 0 {
+		opts = append(opts, konductor.WithPriority(req.GetPriority()))
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	permit, err := semaphore.Acquire(c, ctx, req.GetName(), opts...)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AcquireSemaphoreResponse{Name: permit.Name(), Holder: permit.Holder()}, nil
+}
+
+func (s *semaphoreService) Release(ctx context.Context, req *pb.ReleaseSemaphoreRequest) (*pb.ReleaseSemaphoreResponse, error) {
+	if err := validate(req.GetName(), &req.Holder); err != nil {
+		return nil, err
+	}
+
+	// Permit names carry a creation timestamp, so the holder's permit is
+	// looked up the same way koncli's release does
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	permits, err := c.ListPermits(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	for i := range permits {
+		if permits[i].Spec.Holder != req.GetHolder() {
+			continue
+		}
+		if err := c.K8sClient().Delete(ctx, &permits[i]); client.IgnoreNotFound(err) != nil {
+			return nil, toStatus(err)
+		}
+		return &pb.ReleaseSemaphoreResponse{}, nil
+	}
+
+	return nil, status.Errorf(codes.NotFound, "no permit found on semaphore %s for holder %s", req.GetName(), req.GetHolder())
+}
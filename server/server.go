@@ -0,0 +1,227 @@
+// Package server exposes konductor's coordination primitives over gRPC, so
+// services without a Kubernetes client can take part in coordination. Every
+// RPC is translated into the same SDK calls koncli makes against the
+// primitive's custom resources.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+//+kubebuilder:rbac:groups=sync.konductor.io,resources=arrivals;leaserequests;permits,verbs=create;delete
+
+// DefaultNamespace is used for requests that do not name a namespace when
+// the server was not given one
+const DefaultNamespace = "default"
+
+// Server serves the konductor gRPC API on an address. It implements
+// manager.Runnable so it can run alongside the controllers.
+type Server struct {
+	addr       string
+	k8sClient  client.Client
+	namespace  string
+	namespaces map[string]bool
+	tls        *tls.Config
+}
+
+// Options controls who may call a Server and what they may act on. Every
+// caller the server admits acts with the server's own Kubernetes identity,
+// so Namespaces is what keeps them out of the rest of the cluster.
+type Options struct {
+	// Namespaces lists the namespaces requests may name. Requests for any
+	// other are refused with PermissionDenied. Empty allows only the
+	// server's default namespace.
+	Namespaces []string
+
+	// TLS serves the API over TLS rather than plaintext. See LoadTLSConfig
+	// for one that also requires callers to present a client certificate.
+	TLS *tls.Config
+}
+
+// New returns a Server listening on addr that acts on primitives through
+// k8sClient. Requests without a namespace use namespace.
+func New(addr string, k8sClient client.Client, namespace string, opts Options) *Server {
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	namespaces := map[string]bool{}
+	for _, ns := range opts.Namespaces {
+		namespaces[ns] = true
+	}
+	if len(namespaces) == 0 {
+		namespaces[namespace] = true
+	}
+	return &Server{
+		addr:       addr,
+		k8sClient:  k8sClient,
+		namespace:  namespace,
+		namespaces: namespaces,
+		tls:        opts.TLS,
+	}
+}
+
+// LoadTLSConfig reads tls.crt and tls.key from certDir as the server's
+// certificate, and requires every caller to present a client certificate
+// signed by the CA in certDir's ca.crt
+func LoadTLSConfig(certDir string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certDir, "tls.crt"), filepath.Join(certDir, "tls.key"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load serving certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(filepath.Join(certDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", filepath.Join(certDir, "ca.crt"))
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ServerOptions returns the options a grpc.Server needs to serve the API
+// with the server's transport security
+func (s *Server) ServerOptions() []grpc.ServerOption {
+	if s.tls == nil {
+		return nil
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(s.tls))}
+}
+
+// Register adds every konductor service to g
+func (s *Server) Register(g *grpc.Server) {
+	pb.RegisterSemaphoreServiceServer(g, &semaphoreService{server: s})
+	pb.RegisterLeaseServiceServer(g, &leaseService{server: s})
+	pb.RegisterMutexServiceServer(g, &mutexService{server: s})
+	pb.RegisterRWMutexServiceServer(g, &rwmutexService{server: s})
+	pb.RegisterBarrierServiceServer(g, &barrierService{server: s})
+	pb.RegisterGateServiceServer(g, &gateService{server: s})
+	pb.RegisterWaitGroupServiceServer(g, &waitGroupService{server: s})
+	pb.RegisterEventServiceServer(g, &eventService{server: s})
+}
+
+// Start serves until ctx is cancelled, then stops accepting new RPCs and
+// waits for in-flight ones to finish
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	g := grpc.NewServer(s.ServerOptions()...)
+	s.Register(g)
+
+	go func() {
+		<-ctx.Done()
+		g.GracefulStop()
+	}()
+
+	if err := g.Serve(listener); err != nil {
+		return fmt.Errorf("failed to serve gRPC on %s: %w", s.addr, err)
+	}
+	return nil
+}
+
+// NeedLeaderElection reports that every replica serves the API, not only the
+// leader, since requests are handled entirely through the API server
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// clientFor returns an SDK client scoped to namespace, or to the server's
+// namespace when it is empty. Namespaces the server was not allowed to serve
+// are refused.
+func (s *Server) clientFor(namespace string) (*konductor.Client, error) {
+	if namespace == "" {
+		namespace = s.namespace
+	}
+	if !s.namespaces[namespace] {
+		return nil, status.Errorf(codes.PermissionDenied, "namespace %s is not served by this API", namespace)
+	}
+	return konductor.NewFromClient(s.k8sClient, namespace), nil
+}
+
+// validate rejects requests missing a name, or a holder when one is needed
+// to take ownership on the caller's behalf
+func validate(name string, holder *string) error {
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+	if holder != nil && *holder == "" {
+		return status.Error(codes.InvalidArgument, "holder is required")
+	}
+	return nil
+}
+
+// timeoutOptions converts an optional request timeout into an SDK option
+func timeoutOptions(timeout *durationpb.Duration) []konductor.Option {
+	if d := duration(timeout); d > 0 {
+		return []konductor.Option{konductor.WithTimeout(d)}
+	}
+	return nil
+}
+
+func duration(d *durationpb.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.AsDuration()
+}
+
+// toStatus maps an SDK error onto the gRPC status code callers can act on
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, konductor.ErrTimeout):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, konductor.ErrNotHolder), errors.Is(err, konductor.ErrExpired),
+		errors.Is(err, konductor.ErrNegativeCounter):
+		code = codes.FailedPrecondition
+	case errors.Is(err, konductor.ErrDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, konductor.ErrLocked), errors.Is(err, konductor.ErrNoPermits):
+		code = codes.ResourceExhausted
+	case errors.Is(err, konductor.ErrDraining):
+		code = codes.Unavailable
+	case apierrors.IsNotFound(err):
+		code = codes.NotFound
+	case apierrors.IsAlreadyExists(err):
+		code = codes.AlreadyExists
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		code = codes.InvalidArgument
+	}
+	return status.Error(code, err.Error())
+}
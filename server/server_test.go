@@ -0,0 +1,457 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	syncv1 "github.com/LogicIQ/konductor/api/v1"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+// setupTestServer serves the API in-process over bufconn, backed by a fake
+// client holding objects whose permits are granted as soon as they are
+// created, and returns a connection to it
+func setupTestServer(t *testing.T, objects ...runtime.Object) (*grpc.ClientConn, client.Client) {
+	return setupTestServerWith(t, Options{}, insecure.NewCredentials(), objects...)
+}
+
+// setupTestServerWith is setupTestServer for a server built with opts,
+// dialled with creds
+func setupTestServerWith(t *testing.T, opts Options, creds credentials.TransportCredentials, objects ...runtime.Object) (*grpc.ClientConn, client.Client) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, syncv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objects...).
+		WithStatusSubresource(&syncv1.Semaphore{}, &syncv1.Permit{}, &syncv1.Mutex{},
+			&syncv1.Barrier{}, &syncv1.Gate{}, &syncv1.WaitGroup{}, &syncv1.Event{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if permit, ok := obj.(*syncv1.Permit); ok {
+					permit.Status.Phase = syncv1.PermitPhaseGranted
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := New("bufconn", k8sClient, "test-ns", opts)
+	g := grpc.NewServer(srv.ServerOptions()...)
+	srv.Register(g)
+	go func() { _ = g.Serve(listener) }()
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn, k8sClient
+}
+
+func newSemaphore(available int32) *syncv1.Semaphore {
+	return &syncv1.Semaphore{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sem", Namespace: "test-ns"},
+		Spec:       syncv1.SemaphoreSpec{Permits: 1},
+		Status: syncv1.SemaphoreStatus{
+			Available: available,
+			InUse:     1 - available,
+			Phase:     syncv1.SemaphorePhaseReady,
+		},
+	}
+}
+
+func TestSemaphore_AcquireAndRelease(t *testing.T) {
+	conn, k8sClient := setupTestServer(t, newSemaphore(1))
+	semaphores := pb.NewSemaphoreServiceClient(conn)
+	ctx := context.Background()
+
+	resp, err := semaphores.Acquire(ctx, &pb.AcquireSemaphoreRequest{Name: "test-sem", Holder: "worker-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "test-sem", resp.GetName())
+	assert.Equal(t, "worker-1", resp.GetHolder())
+
+	var permits syncv1.PermitList
+	require.NoError(t, k8sClient.List(ctx, &permits))
+	require.Len(t, permits.Items, 1)
+	assert.Equal(t, "worker-1", permits.Items[0].Spec.Holder)
+
+	_, err = semaphores.Release(ctx, &pb.ReleaseSemaphoreRequest{Name: "test-sem", Holder: "worker-2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = semaphores.Release(ctx, &pb.ReleaseSemaphoreRequest{Name: "test-sem", Holder: "worker-1"})
+	require.NoError(t, err)
+
+	require.NoError(t, k8sClient.List(ctx, &permits))
+	assert.Empty(t, permits.Items)
+}
+
+func TestSemaphore_AcquireStatusCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *pb.AcquireSemaphoreRequest
+		expected codes.Code
+	}{
+		{
+			name:     "missing holder",
+			request:  &pb.AcquireSemaphoreRequest{Name: "test-sem"},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name:     "missing semaphore",
+			request:  &pb.AcquireSemaphoreRequest{Name: "missing", Holder: "worker-1"},
+			expected: codes.NotFound,
+		},
+		{
+			name:     "timed out waiting for a permit",
+			request:  &pb.AcquireSemaphoreRequest{Name: "test-sem", Holder: "worker-1", Timeout: durationpb.New(200 * time.Millisecond)},
+			expected: codes.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _ := setupTestServer(t, newSemaphore(0))
+
+			_, err := pb.NewSemaphoreServiceClient(conn).Acquire(context.Background(), tt.request)
+			require.Error(t, err)
+			assert.Equal(t, tt.expected, status.Code(err))
+		})
+	}
+}
+
+func TestMutex_ReleaseByOtherHolderIsRejected(t *testing.T) {
+	mutex := &syncv1.Mutex{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mutex", Namespace: "test-ns"},
+		Status:     syncv1.MutexStatus{Phase: syncv1.MutexPhaseUnlocked},
+	}
+	conn, _ := setupTestServer(t, mutex)
+	mutexes := pb.NewMutexServiceClient(conn)
+	ctx := context.Background()
+
+	resp, err := mutexes.Acquire(ctx, &pb.AcquireMutexRequest{Name: "test-mutex", Holder: "worker-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "worker-1", resp.GetHolder())
+
+	_, err = mutexes.Release(ctx, &pb.ReleaseMutexRequest{Name: "test-mutex", Holder: "worker-2"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = mutexes.Release(ctx, &pb.ReleaseMutexRequest{Name: "test-mutex", Holder: "worker-1"})
+	require.NoError(t, err)
+}
+
+func newBarrier() *syncv1.Barrier {
+	return &syncv1.Barrier{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-barrier", Namespace: "test-ns"},
+		Spec:       syncv1.BarrierSpec{Expected: 2},
+		Status:     syncv1.BarrierStatus{Phase: syncv1.BarrierPhaseWaiting},
+	}
+}
+
+// setBarrierStatus updates the barrier's status as the controller would
+func setBarrierStatus(t *testing.T, k8sClient client.Client, phase syncv1.BarrierPhase, arrived int32) {
+	var b syncv1.Barrier
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "test-barrier", Namespace: "test-ns"}, &b))
+	b.Status.Phase = phase
+	b.Status.Arrived = arrived
+	require.NoError(t, k8sClient.Status().Update(context.Background(), &b))
+}
+
+func TestBarrier_WaitStreamsUntilOpen(t *testing.T) {
+	conn, k8sClient := setupTestServer(t, newBarrier())
+	barriers := pb.NewBarrierServiceClient(conn)
+	ctx := context.Background()
+
+	_, err := barriers.Arrive(ctx, &pb.ArriveBarrierRequest{Name: "test-barrier", Holder: "worker-1"})
+	require.NoError(t, err)
+
+	stream, err := barriers.Wait(ctx, &pb.WaitBarrierRequest{Name: "test-barrier", Timeout: durationpb.New(5 * time.Second)})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Waiting", first.GetPhase())
+	assert.Equal(t, int32(2), first.GetExpected())
+
+	setBarrierStatus(t, k8sClient, syncv1.BarrierPhaseWaiting, 1)
+	progress, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), progress.GetArrived())
+
+	setBarrierStatus(t, k8sClient, syncv1.BarrierPhaseOpen, 2)
+	open, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Open", open.GetPhase())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestBarrier_WaitEndsWhenFailed(t *testing.T) {
+	conn, k8sClient := setupTestServer(t, newBarrier())
+
+	stream, err := pb.NewBarrierServiceClient(conn).Wait(context.Background(), &pb.WaitBarrierRequest{Name: "test-barrier"})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	setBarrierStatus(t, k8sClient, syncv1.BarrierPhaseFailed, 1)
+	failed, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Failed", failed.GetPhase())
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestGate_WaitStreamsUntilOpen(t *testing.T) {
+	gate := &syncv1.Gate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gate", Namespace: "test-ns"},
+		Spec: syncv1.GateSpec{
+			Conditions: []syncv1.GateCondition{{Type: "Job", Name: "setup", State: "Complete"}},
+		},
+		Status: syncv1.GateStatus{Phase: syncv1.GatePhaseWaiting},
+	}
+	conn, k8sClient := setupTestServer(t, gate)
+
+	stream, err := pb.NewGateServiceClient(conn).Wait(context.Background(), &pb.WaitGateRequest{Name: "test-gate"})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Waiting", first.GetPhase())
+	assert.Equal(t, int32(0), first.GetConditionsMet())
+	assert.Equal(t, int32(1), first.GetConditionsTotal())
+
+	var g syncv1.Gate
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "test-gate", Namespace: "test-ns"}, &g))
+	g.Status.Phase = syncv1.GatePhaseOpen
+	g.Status.ConditionStatuses = []syncv1.GateConditionStatus{{Type: "Job", Name: "setup", Met: true}}
+	require.NoError(t, k8sClient.Status().Update(context.Background(), &g))
+
+	open, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Open", open.GetPhase())
+	assert.Equal(t, int32(1), open.GetConditionsMet())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestGate_WaitNotFound(t *testing.T) {
+	conn, _ := setupTestServer(t)
+
+	stream, err := pb.NewGateServiceClient(conn).Wait(context.Background(), &pb.WaitGateRequest{Name: "missing"})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestEvent_SetReleasesWait(t *testing.T) {
+	event := &syncv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-event", Namespace: "test-ns"},
+		Status:     syncv1.EventStatus{Phase: syncv1.EventPhaseCleared},
+	}
+	conn, _ := setupTestServer(t, event)
+	events := pb.NewEventServiceClient(conn)
+	ctx := context.Background()
+
+	_, err := events.Wait(ctx, &pb.WaitEventRequest{Name: "test-event", Timeout: durationpb.New(200 * time.Millisecond)})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	_, err = events.Set(ctx, &pb.SetEventRequest{Name: "test-event", Holder: "producer"})
+	require.NoError(t, err)
+
+	_, err = events.Wait(ctx, &pb.WaitEventRequest{Name: "test-event", Timeout: durationpb.New(time.Second)})
+	require.NoError(t, err)
+}
+
+func TestBarrier_WaitCyclicEndsWhenGenerationAdvances(t *testing.T) {
+	b := newBarrier()
+	b.Spec.Cyclic = true
+	conn, k8sClient := setupTestServer(t, b)
+
+	stream, err := pb.NewBarrierServiceClient(conn).Wait(context.Background(), &pb.WaitBarrierRequest{Name: "test-barrier"})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Waiting", first.GetPhase())
+
+	// The controller opens the round and moves straight on to the next one
+	var current syncv1.Barrier
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: "test-barrier", Namespace: "test-ns"}, &current))
+	current.Status.Generation = 1
+	require.NoError(t, k8sClient.Status().Update(context.Background(), &current))
+
+	open, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Open", open.GetPhase())
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestServer_RefusesNamespacesNotAllowed(t *testing.T) {
+	other := newSemaphore(1)
+	other.Namespace = "team-b"
+
+	t.Run("default namespace only", func(t *testing.T) {
+		conn, k8sClient := setupTestServerWith(t, Options{}, insecure.NewCredentials(), newSemaphore(1), other)
+		semaphores := pb.NewSemaphoreServiceClient(conn)
+
+		_, err := semaphores.Acquire(context.Background(), &pb.AcquireSemaphoreRequest{Name: "test-sem", Namespace: "team-b", Holder: "worker-1"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = semaphores.Acquire(context.Background(), &pb.AcquireSemaphoreRequest{Name: "test-sem", Holder: "worker-1"})
+		require.NoError(t, err)
+
+		var permits syncv1.PermitList
+		require.NoError(t, k8sClient.List(context.Background(), &permits, client.InNamespace("team-b")))
+		assert.Empty(t, permits.Items)
+	})
+
+	t.Run("allow-listed namespaces", func(t *testing.T) {
+		conn, _ := setupTestServerWith(t, Options{Namespaces: []string{"test-ns", "team-b"}}, insecure.NewCredentials(), other)
+
+		_, err := pb.NewSemaphoreServiceClient(conn).Acquire(context.Background(),
+			&pb.AcquireSemaphoreRequest{Name: "test-sem", Namespace: "team-b", Holder: "worker-1"})
+		require.NoError(t, err)
+
+		_, err = pb.NewMutexServiceClient(conn).Acquire(context.Background(),
+			&pb.AcquireMutexRequest{Name: "test-mutex", Namespace: "kube-system", Holder: "worker-1"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		stream, err := pb.NewBarrierServiceClient(conn).Wait(context.Background(),
+			&pb.WaitBarrierRequest{Name: "test-barrier", Namespace: "kube-system"})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestServer_RequiresClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCertificate(t, "konductor-ca", nil, nil)
+	writePEM(t, filepath.Join(dir, "ca.crt"), "CERTIFICATE", ca.Raw)
+	serving, servingKey := newTestCertificate(t, "bufconn", ca, caKey)
+	writePEM(t, filepath.Join(dir, "tls.crt"), "CERTIFICATE", serving.Raw)
+	writePEM(t, filepath.Join(dir, "tls.key"), "PRIVATE KEY", marshalKey(t, servingKey))
+
+	tlsConfig, err := LoadTLSConfig(dir)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	clientCert, clientKey := newTestCertificate(t, "worker-1", ca, caKey)
+
+	t.Run("with a client certificate", func(t *testing.T) {
+		creds := credentials.NewTLS(&tls.Config{
+			RootCAs:    roots,
+			ServerName: "bufconn",
+			Certificates: []tls.Certificate{{
+				Certificate: [][]byte{clientCert.Raw},
+				PrivateKey:  clientKey,
+			}},
+		})
+		conn, _ := setupTestServerWith(t, Options{TLS: tlsConfig}, creds, newSemaphore(1))
+
+		_, err := pb.NewSemaphoreServiceClient(conn).Acquire(context.Background(),
+			&pb.AcquireSemaphoreRequest{Name: "test-sem", Holder: "worker-1"})
+		require.NoError(t, err)
+	})
+
+	t.Run("without one", func(t *testing.T) {
+		creds := credentials.NewTLS(&tls.Config{RootCAs: roots, ServerName: "bufconn"})
+		conn, k8sClient := setupTestServerWith(t, Options{TLS: tlsConfig}, creds, newSemaphore(1))
+
+		_, err := pb.NewSemaphoreServiceClient(conn).Acquire(context.Background(),
+			&pb.AcquireSemaphoreRequest{Name: "test-sem", Holder: "worker-1"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+
+		var permits syncv1.PermitList
+		require.NoError(t, k8sClient.List(context.Background(), &permits))
+		assert.Empty(t, permits.Items)
+	})
+}
+
+// newTestCertificate returns a certificate for name signed by parent, or a
+// self-signed CA when parent is nil
+func newTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func marshalKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return der
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
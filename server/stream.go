@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	konductor "github.com/LogicIQ/konductor/sdk/go/client"
+)
+
+// outcome is how far a waitable primitive has got
+type outcome int
+
+const (
+	outcomeWaiting outcome = iota
+	outcomeOpen
+	outcomeFailed
+)
+
+// streamStatus sends a summary of obj every time it changes until the
+// primitive opens or fails, ending the stream with FAILED_PRECONDITION in the
+// latter case. The wait reacts to status changes via a watch, like the SDK's
+// Wait functions, and gives up after timeout or 30s when none is given.
+func streamStatus[M proto.Message](ctx context.Context, c *konductor.Client, kind string, obj client.Object, timeout time.Duration,
+	summarize func(client.Object) (M, outcome), send func(M) error) error {
+	if err := c.K8sClient().Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return toStatus(err)
+	}
+
+	config := &konductor.WaitConfig{
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     5 * time.Second,
+		Factor:       1.5,
+		Jitter:       0.1,
+		Timeout:      30 * time.Second,
+	}
+	if timeout > 0 {
+		config.Timeout = timeout
+	}
+
+	var last M
+	sent := false
+	result := outcomeWaiting
+	var sendErr error
+
+	err := c.WatchForCondition(ctx, obj, func(current client.Object) bool {
+		summary, o := summarize(current)
+		if !sent || !proto.Equal(summary, last) {
+			if sendErr = send(summary); sendErr != nil {
+				return true
+			}
+			last, sent = summary, true
+		}
+		result = o
+		return o != outcomeWaiting
+	}, config)
+
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		if ctx.Err() == nil && wait.Interrupted(err) {
+			return status.Errorf(codes.DeadlineExceeded, "timed out waiting for %s %s", kind, obj.GetName())
+		}
+		return toStatus(err)
+	}
+	if result == outcomeFailed {
+		return status.Errorf(codes.FailedPrecondition, "%s %s failed", kind, obj.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+
+	"github.com/LogicIQ/konductor/sdk/go/waitgroup"
+	"github.com/LogicIQ/konductor/server/pb"
+)
+
+type waitGroupService struct {
+	pb.UnimplementedWaitGroupServiceServer
+	server *Server
+}
+
+func (s *waitGroupService) Add(ctx context.Context, req *pb.AddWaitGroupRequest) (*pb.AddWaitGroupResponse, error) {
+	if err := validate(req.GetName(), nil); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := waitgroup.Add(c, ctx, req.GetName(), req.GetDelta()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AddWaitGroupResponse{}, nil
+}
+
+func (s *waitGroupService) Done(ctx context.Context, req *pb.DoneWaitGroupRequest) (*pb.DoneWaitGroupResponse, error) {
+	if err := validate(req.GetName(), nil); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := waitgroup.Done(c, ctx, req.GetName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DoneWaitGroupResponse{}, nil
+}
+
+func (s *waitGroupService) Wait(ctx context.Context, req *pb.WaitWaitGroupRequest) (*pb.WaitWaitGroupResponse, error) {
+	if err := validate(req.GetName(), nil); err != nil {
+		return nil, err
+	}
+
+	c, err := s.server.clientFor(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	if err := waitgroup.Wait(c, ctx, req.GetName(), timeoutOptions(req.GetTimeout())...); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.WaitWaitGroupResponse{}, nil
+}